@@ -0,0 +1,183 @@
+import fetch from 'isomorphic-fetch'
+
+const CHANGE_PASSWORD_SUCCESS_MESSAGE = 'Password was changed successfully. Please log in again.'
+const TWO_FACTOR_ENABLED_MESSAGE = 'Two-factor authentication is now on.'
+const TWO_FACTOR_DISABLED_MESSAGE = 'Two-factor authentication is now off.'
+
+import {
+  INVALID_SESSION_ERROR,
+  GENERIC_SERVER_ERROR,
+  flashOperationResult
+} from './general'
+
+import {
+	logoutUser
+} from './logout'
+
+/* Password */
+
+function submitPasswordChange(passwordChange) {
+	let request = {
+		method: 'PUT',
+		headers: { 
+			'Content-Type':'application/json',
+			'X-Auth-Header': localStorage.getItem('authToken') 
+		},
+		body: JSON.stringify(passwordChange)
+	}
+
+	return dispatch => {
+		return fetch('/api/account/password', request)
+		.then(rawResponse => {
+			if (!rawResponse.ok) {
+        switch(rawResponse.status) {
+          case 400:
+            return rawResponse.json().then(response => {
+              dispatch(flashOperationResult(response.error, false))
+              return Promise.reject()
+            })
+          case 401:
+            dispatch(flashOperationResult(INVALID_SESSION_ERROR, false))
+						dispatch(logoutUser())
+            break
+          default:
+            dispatch(flashOperationResult(GENERIC_SERVER_ERROR, false))
+        }
+
+				return Promise.reject()
+			}
+
+			// Dispatch the success action. Changing the password ends every session, including this one
+			dispatch(flashOperationResult(CHANGE_PASSWORD_SUCCESS_MESSAGE, true))
+			dispatch(logoutUser())
+
+			return Promise.resolve(true)
+		}).catch(err => {
+			if (err) {
+				console.warn("change password error", err)
+			}
+
+			return false
+		})
+	}
+}
+
+/* Two-factor authentication */
+
+function beginTwoFactorEnrollment() {
+	let request = {
+		method: 'POST',
+		headers: {
+			'X-Auth-Header': localStorage.getItem('authToken')
+		}
+	}
+
+	return dispatch => {
+		return fetch('/api/account/totp', request)
+		.then(rawResponse => {
+			if (!rawResponse.ok) {
+				return rejectAccountResponse(dispatch, rawResponse)
+			}
+
+			return rawResponse.json()
+		}).catch(err => {
+			if (err) {
+				console.warn("begin two-factor enrollment error", err)
+			}
+
+			return null
+		})
+	}
+}
+
+// confirmTwoFactorEnrollment resolves with the recovery codes, which are never shown again
+function confirmTwoFactorEnrollment(code) {
+	let request = {
+		method: 'POST',
+		headers: {
+			'Content-Type':'application/json',
+			'X-Auth-Header': localStorage.getItem('authToken')
+		},
+		body: JSON.stringify({ code })
+	}
+
+	return dispatch => {
+		return fetch('/api/account/totp/confirm', request)
+		.then(rawResponse => {
+			if (!rawResponse.ok) {
+				return rejectAccountResponse(dispatch, rawResponse)
+			}
+
+			return rawResponse.json()
+		}).then(response => {
+			localStorage.setItem('twoFactorEnabled', 'true')
+
+			dispatch(flashOperationResult(TWO_FACTOR_ENABLED_MESSAGE, true))
+
+			return Promise.resolve(response.recoveryCodes)
+		}).catch(err => {
+			if (err) {
+				console.warn("confirm two-factor enrollment error", err)
+			}
+
+			return null
+		})
+	}
+}
+
+function disableTwoFactor(password) {
+	let request = {
+		method: 'DELETE',
+		headers: {
+			'Content-Type':'application/json',
+			'X-Auth-Header': localStorage.getItem('authToken')
+		},
+		body: JSON.stringify({ password })
+	}
+
+	return dispatch => {
+		return fetch('/api/account/totp', request)
+		.then(rawResponse => {
+			if (!rawResponse.ok) {
+				return rejectAccountResponse(dispatch, rawResponse)
+			}
+
+			localStorage.setItem('twoFactorEnabled', 'false')
+
+			dispatch(flashOperationResult(TWO_FACTOR_DISABLED_MESSAGE, true))
+
+			return Promise.resolve(true)
+		}).catch(err => {
+			if (err) {
+				console.warn("disable two-factor error", err)
+			}
+
+			return false
+		})
+	}
+}
+
+function rejectAccountResponse(dispatch, rawResponse) {
+	switch(rawResponse.status) {
+		case 400:
+			return rawResponse.json().then(response => {
+				dispatch(flashOperationResult(response.error, false))
+				return Promise.reject()
+			})
+		case 401:
+			dispatch(flashOperationResult(INVALID_SESSION_ERROR, false))
+			dispatch(logoutUser())
+			break
+		default:
+			dispatch(flashOperationResult(GENERIC_SERVER_ERROR, false))
+	}
+
+	return Promise.reject()
+}
+
+module.exports = {
+	submitPasswordChange,
+	beginTwoFactorEnrollment,
+	confirmTwoFactorEnrollment,
+	disableTwoFactor
+}
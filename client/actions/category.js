@@ -14,6 +14,10 @@ const TOGGLE_CATEGORY_DELETE_CONFIRMATION = 'TOGGLE_CATEGORY_DELETE_CONFIRMATION
 const SET_CATEGORY_DELETED = 'SET_CATEGORY_DELETED'
 const DELETE_CATEGORY_SUCCESS_MESSAGE = 'Category was deleted successfully.'
 
+import {
+  LIST_PAGE_SIZE
+} from '../constants'
+
 import {
   INVALID_SESSION_ERROR,
   GENERIC_SERVER_ERROR,
@@ -51,7 +55,7 @@ function fetchCategories() {
 	}
 
 	return dispatch => {
-		return fetch('/api/categories', request)
+		return fetch(`/api/categories?pageSize=${LIST_PAGE_SIZE}`, request)
 		.then(rawResponse => {
 			if (!rawResponse.ok) {
 				switch(rawResponse.status) {
@@ -68,7 +72,7 @@ function fetchCategories() {
 			return rawResponse.json()
 		}).then(response =>  {
       // Update categories
-      dispatch(setCategories(response))
+      dispatch(setCategories(response.categories))
 
 			return Promise.resolve()
 		}).catch(err => {
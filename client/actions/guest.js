@@ -2,12 +2,69 @@ import fetch from 'isomorphic-fetch'
 
 const SET_GUEST_RSVP = 'SET_GUEST_RSVP'
 const SET_GUEST_RSVP_CREATED = 'SET_GUEST_RSVP_CREATED'
+const SET_GUEST_PHONE_CONFIRMATION_REQUIRED = 'SET_GUEST_PHONE_CONFIRMATION_REQUIRED'
+
+const LINK_GONE_ERROR = 'This invitation link is no longer valid. Please ask the Bride or Groom for a new one.'
+const PHONE_NUMBER_INCORRECT_ERROR = 'That mobile phone number does not match the invitation.'
+const TOO_MANY_ATTEMPTS_ERROR = 'Too many incorrect attempts, please try again later.'
 
 import {
   GENERIC_SERVER_ERROR,
   flashGuestOperationFailure
 } from './general'
 
+/* Phone confirmation */
+
+function setGuestPhoneConfirmationRequired(required) {
+  return {
+    type: SET_GUEST_PHONE_CONFIRMATION_REQUIRED,
+    required
+  }
+}
+
+// guestHeaders adds the mobile phone number the guest confirmed for this invitation, if any
+function guestHeaders(id) {
+  let headers = {
+    'Content-Type':'application/json'
+  }
+
+  let phoneNumber = sessionStorage.getItem(`guestPhoneNumber:${id}`)
+  if (phoneNumber) {
+    headers['X-Guest-Phone-Number'] = phoneNumber
+  }
+
+  return headers
+}
+
+// rejectGuestResponse explains why the invitation link could not be used
+function rejectGuestResponse(dispatch, id, rawResponse) {
+  switch (rawResponse.status) {
+    case 410:
+      dispatch(flashGuestOperationFailure(LINK_GONE_ERROR))
+      break
+    case 403:
+      if (sessionStorage.getItem(`guestPhoneNumber:${id}`)) {
+        sessionStorage.removeItem(`guestPhoneNumber:${id}`)
+        dispatch(flashGuestOperationFailure(PHONE_NUMBER_INCORRECT_ERROR))
+      }
+      dispatch(setGuestPhoneConfirmationRequired(true))
+      break
+    case 429:
+      dispatch(flashGuestOperationFailure(TOO_MANY_ATTEMPTS_ERROR))
+      break
+    default:
+      dispatch(flashGuestOperationFailure(GENERIC_SERVER_ERROR))
+  }
+
+  return Promise.reject()
+}
+
+function confirmGuestPhoneNumber(id, phoneNumber) {
+  sessionStorage.setItem(`guestPhoneNumber:${id}`, phoneNumber)
+
+  return fetchRSVP(id)
+}
+
 /* Fetch */
 
 function setGuestRSVP(rsvp) {
@@ -20,23 +77,20 @@ function setGuestRSVP(rsvp) {
 function fetchRSVP(id) {
 	let request = {
 		method: 'GET',
-		headers: { 
-			'Content-Type':'application/json' 
-		}
+		headers: guestHeaders(id)
 	}
 
 	return dispatch => {
 		return fetch(`/api/rsvps/${id}`, request)
 		.then(rawResponse => {
 			if (!rawResponse.ok) {
-        dispatch(flashGuestOperationFailure(GENERIC_SERVER_ERROR))
-
-				return Promise.reject()
+				return rejectGuestResponse(dispatch, id, rawResponse)
 			}
 
 			return rawResponse.json()
 		}).then(response =>  {
       // Update guest rsvp
+      dispatch(setGuestPhoneConfirmationRequired(false))
       dispatch(setGuestRSVP(response))
 
 			return Promise.resolve()
@@ -53,9 +107,7 @@ function fetchRSVP(id) {
 function submitGuestRSVPCreate(rsvp) {
 	let request = {
 		method: 'POST',
-		headers: { 
-			'Content-Type':'application/json' 
-		},
+		headers: guestHeaders(rsvp.invitationPrivateID),
 		body: JSON.stringify(rsvp)
 	}
 
@@ -63,9 +115,7 @@ function submitGuestRSVPCreate(rsvp) {
 		return fetch('/api/rsvps', request)
 		.then(rawResponse => {
 			if (!rawResponse.ok) {
-				dispatch(flashGuestOperationFailure(GENERIC_SERVER_ERROR))
-
-				return Promise.reject()
+				return rejectGuestResponse(dispatch, rsvp.invitationPrivateID, rawResponse)
 			}
 
 			return rawResponse.json()
@@ -84,6 +134,8 @@ function submitGuestRSVPCreate(rsvp) {
 
 module.exports = {
   SET_GUEST_RSVP,
+  SET_GUEST_PHONE_CONFIRMATION_REQUIRED,
+  confirmGuestPhoneNumber,
   fetchRSVP,
   submitGuestRSVPCreate
 }
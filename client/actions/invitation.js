@@ -6,6 +6,7 @@ const SET_INVITATIONS = 'SET_INVITATIONS'
 
 const SET_INVITATION_UPDATED = 'SET_INVITATION_UPDATED'
 const EDIT_INVITATION_SUCCESS_MESSAGE = 'Invitation was updated successfully.'
+const EDIT_INVITATION_CONFLICT_MESSAGE = 'Invitation was changed by someone else. The latest version has been loaded, please review and try again.'
 
 const SET_INVITATION_CREATED = 'SET_INVITATION_CREATED'
 const CREATE_INVITATION_SUCCESS_MESSAGE = 'Invitation was created successfully.'
@@ -14,6 +15,10 @@ const TOGGLE_INVITATION_DELETE_CONFIRMATION = 'TOGGLE_INVITATION_DELETE_CONFIRMA
 const SET_INVITATION_DELETED = 'SET_INVITATION_DELETED'
 const DELETE_INVITATION_SUCCESS_MESSAGE = 'Invitation was deleted successfully.'
 
+import {
+  LIST_PAGE_SIZE
+} from '../constants'
+
 import {
   INVALID_SESSION_ERROR,
   GENERIC_SERVER_ERROR,
@@ -51,7 +56,7 @@ function fetchInvitations() {
 	}
 
 	return dispatch => {
-		return fetch('/api/invitations', request)
+		return fetch(`/api/invitations?pageSize=${LIST_PAGE_SIZE}`, request)
 		.then(rawResponse => {
 			if (!rawResponse.ok) {
 				switch(rawResponse.status) {
@@ -69,7 +74,7 @@ function fetchInvitations() {
 			return rawResponse.json()
 		}).then(response =>  {
       // Update invitations
-      dispatch(setInvitations(response))
+      dispatch(setInvitations(response.invitations))
 
 			return Promise.resolve()
 		}).catch(err => {
@@ -110,6 +115,15 @@ function submitInvitationEdit(invitation) {
           case 401:
             dispatch(flashOperationResult(INVALID_SESSION_ERROR, false))
             break
+          case 409:
+            // The response carries the latest invitation so the list reflects what was changed
+            return rawResponse.json().then(current => {
+              dispatch(toggleInvitationFormVisibility(null, {}))
+              dispatch(flashOperationResult(EDIT_INVITATION_CONFLICT_MESSAGE, false))
+              dispatch(setInvitationUpdated(current))
+
+              return Promise.reject()
+            })
           default:
             dispatch(flashOperationResult(GENERIC_SERVER_ERROR, false))
         }
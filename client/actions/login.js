@@ -2,15 +2,28 @@ import fetch from 'isomorphic-fetch'
 import { browserHistory } from 'react-router'
 
 import {
+  INVALID_SESSION_ERROR,
   GENERIC_SERVER_ERROR,
   flashOperationResult
 } from './general'
 
+import {
+	logoutUser
+} from './logout'
+
 const LOGIN_REQUEST = 'LOGIN_REQUEST'
 const LOGIN_SUCCESS = 'LOGIN_SUCCESS'
 const LOGIN_FAILURE = 'LOGIN_FAILURE'
+const LOGIN_TWO_FACTOR_REQUIRED = 'LOGIN_TWO_FACTOR_REQUIRED'
+const LOGIN_TWO_FACTOR_CANCEL = 'LOGIN_TWO_FACTOR_CANCEL'
 
 const INVALID_CREDENTIALS_ERROR = 'Oops, username and/or password were not correct.'
+const INVALID_TWO_FACTOR_CODE_ERROR = 'Oops, that code was not correct or the login has expired.'
+
+// Refresh the session this long before the auth token expires
+const SESSION_REFRESH_MARGIN = 60 * 1000
+
+let sessionRefreshTimer = null
 
 function requestLogin(credentials) {
   return {
@@ -39,6 +52,27 @@ function loginError(message) {
   }
 }
 
+function requireTwoFactor(preAuthToken) {
+  return {
+    type: LOGIN_TWO_FACTOR_REQUIRED,
+    isFetching: false,
+    isAuthenticated: false,
+    preAuthToken
+  }
+}
+
+function cancelTwoFactor() {
+  return {
+    type: LOGIN_TWO_FACTOR_CANCEL
+  }
+}
+
+function tooManyAttemptsError(rawResponse) {
+  let retryAfter = rawResponse.headers.get('Retry-After')
+
+  return `Too many failed logins, please try again in ${retryAfter} seconds.`
+}
+
 function loginUser(credentials) {
 	let request = {
 		method: 'POST',
@@ -57,6 +91,9 @@ function loginUser(credentials) {
           case 401:
             dispatch(loginError(INVALID_CREDENTIALS_ERROR))
             break
+          case 429:
+            dispatch(loginError(tooManyAttemptsError(rawResponse)))
+            break
           default:
             dispatch(flashOperationResult(GENERIC_SERVER_ERROR, false))
         }
@@ -66,25 +103,145 @@ function loginUser(credentials) {
 
 			return rawResponse.json()
 		}).then(response =>  {
-			if (!response.authToken) {
-        dispatch(flashOperationResult(GENERIC_SERVER_ERROR, false))
+			// The password was right but a code from an authenticator app is needed as well
+			if (response.twoFactorRequired) {
+				dispatch(requireTwoFactor(response.preAuthToken))
+
+				return Promise.resolve()
+			}
+
+			localStorage.setItem('twoFactorEnabled', 'false')
+
+			return completeLogin(dispatch, response)
+		}).catch(err => {
+			if (err) {
+				console.warn("login error", err)
+			}
+		})
+	}
+}
+
+function submitTwoFactorCode(code) {
+	return (dispatch, getState) => {
+		let request = {
+			method: 'POST',
+			headers: { 'Content-Type':'application/json' },
+			body: JSON.stringify({ preAuthToken: getState().auth.preAuthToken, code })
+		}
+
+		dispatch(requestLogin())
+
+		return fetch('/api/sessions/two-factor', request)
+		.then(rawResponse => {
+			if (!rawResponse.ok) {
+        switch(rawResponse.status) {
+          case 401:
+            dispatch(loginError(INVALID_TWO_FACTOR_CODE_ERROR))
+            break
+          case 429:
+            dispatch(loginError(tooManyAttemptsError(rawResponse)))
+            break
+          default:
+            dispatch(flashOperationResult(GENERIC_SERVER_ERROR, false))
+        }
 
 				return Promise.reject()
 			}
 
-			// If login was successful, set the token in local storage
-			localStorage.setItem('authToken', response.authToken)
-			localStorage.setItem('username', response.username)
+			return rawResponse.json()
+		}).then(response => {
+			localStorage.setItem('twoFactorEnabled', 'true')
 
-			// Dispatch the success action
-			dispatch(receiveLogin(response))
+			return completeLogin(dispatch, response)
+		}).catch(err => {
+			if (err) {
+				console.warn("two-factor login error", err)
+			}
+		})
+	}
+}
+
+function completeLogin(dispatch, response) {
+	if (!response.authToken) {
+		dispatch(flashOperationResult(GENERIC_SERVER_ERROR, false))
+
+		return Promise.reject()
+	}
+
+	// If login was successful, set the tokens in local storage
+	storeSessionTokens(response)
+	localStorage.setItem('username', response.username)
+
+	// Dispatch the success action
+	dispatch(receiveLogin(response))
 
-			browserHistory.push('/control_panel')
+	browserHistory.push('/control_panel')
+
+	return Promise.resolve()
+}
+
+function storeSessionTokens(tokens) {
+	localStorage.setItem('authToken', tokens.authToken)
+	localStorage.setItem('authTokenExpiresAt', tokens.authTokenExpiresAt)
+	localStorage.setItem('refreshToken', tokens.refreshToken)
+}
+
+/* Refresh */
+
+function scheduleSessionRefresh() {
+	return dispatch => {
+		clearTimeout(sessionRefreshTimer)
+
+		let expiresAt = Date.parse(localStorage.getItem('authTokenExpiresAt'))
+		if (isNaN(expiresAt)) {
+			return
+		}
+
+		let delay = Math.max(expiresAt - Date.now() - SESSION_REFRESH_MARGIN, 0)
+		sessionRefreshTimer = setTimeout(() => dispatch(refreshSession()), delay)
+	}
+}
+
+function refreshSession() {
+	let refreshToken = localStorage.getItem('refreshToken')
+
+	let request = {
+		method: 'POST',
+		headers: { 'Content-Type':'application/json' },
+		body: JSON.stringify({ refreshToken })
+	}
+
+	return dispatch => {
+		// Logged out since the refresh was scheduled
+		if (!refreshToken) {
+			return Promise.resolve()
+		}
+
+		return fetch('/api/sessions/refresh', request)
+		.then(rawResponse => {
+			if (!rawResponse.ok) {
+				switch(rawResponse.status) {
+					case 401:
+						dispatch(flashOperationResult(INVALID_SESSION_ERROR, false))
+						dispatch(logoutUser())
+						break
+					default:
+						dispatch(flashOperationResult(GENERIC_SERVER_ERROR, false))
+				}
+
+				return Promise.reject()
+			}
+
+			return rawResponse.json()
+		}).then(response => {
+			storeSessionTokens(response)
+
+			dispatch(scheduleSessionRefresh())
 
 			return Promise.resolve()
 		}).catch(err => {
 			if (err) {
-				console.warn("login error", err)
+				console.warn("refresh session error", err)
 			}
 		})
 	}
@@ -94,6 +251,11 @@ module.exports = {
     LOGIN_REQUEST,
     LOGIN_SUCCESS,
     LOGIN_FAILURE,
+    LOGIN_TWO_FACTOR_REQUIRED,
+    LOGIN_TWO_FACTOR_CANCEL,
     INVALID_CREDENTIALS_ERROR,
-    loginUser
+    loginUser,
+    submitTwoFactorCode,
+    cancelTwoFactor,
+    scheduleSessionRefresh
 }
@@ -40,7 +40,10 @@ function logoutUser() {
         }
 
         localStorage.removeItem('authToken')
+        localStorage.removeItem('authTokenExpiresAt')
+        localStorage.removeItem('refreshToken')
         localStorage.removeItem('username')
+        localStorage.removeItem('twoFactorEnabled')
 
         dispatch(receiveLogout())
 
@@ -6,6 +6,7 @@ const SET_RSVPS = 'SET_RSVPS'
 
 const SET_RSVP_UPDATED = 'SET_RSVP_UPDATED'
 const EDIT_RSVP_SUCCESS_MESSAGE = 'RSVP was updated successfully.'
+const EDIT_RSVP_CONFLICT_MESSAGE = 'RSVP was changed by someone else. The latest version has been loaded, please review and try again.'
 
 const SET_RSVP_CREATED = 'SET_RSVP_CREATED'
 const CREATE_RSVP_SUCCESS_MESSAGE = 'RSVP was created successfully.'
@@ -14,6 +15,10 @@ const TOGGLE_RSVP_DELETE_CONFIRMATION = 'TOGGLE_RSVP_DELETE_CONFIRMATION'
 const SET_RSVP_DELETED = 'SET_RSVP_DELETED'
 const DELETE_RSVP_SUCCESS_MESSAGE = 'RSVP was deleted successfully.'
 
+import {
+  LIST_PAGE_SIZE
+} from '../constants'
+
 import {
   INVALID_SESSION_ERROR,
   GENERIC_SERVER_ERROR,
@@ -51,7 +56,7 @@ function fetchRSVPs() {
 	}
 
 	return dispatch => {
-		return fetch('/api/rsvps', request)
+		return fetch(`/api/rsvps?pageSize=${LIST_PAGE_SIZE}`, request)
 		.then(rawResponse => {
 			if (!rawResponse.ok) {
 				switch(rawResponse.status) {
@@ -69,7 +74,7 @@ function fetchRSVPs() {
 			return rawResponse.json()
 		}).then(response =>  {
       // Update rsvps
-      dispatch(setRSVPs(response))
+      dispatch(setRSVPs(response.rsvps))
 
 			return Promise.resolve()
 		}).catch(err => {
@@ -110,6 +115,15 @@ function submitRSVPEdit(rsvp) {
           case 401:
             dispatch(flashOperationResult(INVALID_SESSION_ERROR, false))
             break
+          case 409:
+            // The response carries the latest rsvp so the list reflects what was changed
+            return rawResponse.json().then(current => {
+              dispatch(toggleRSVPFormVisibility(null, {}))
+              dispatch(flashOperationResult(EDIT_RSVP_CONFLICT_MESSAGE, false))
+              dispatch(setRSVPUpdated(current))
+
+              return Promise.reject()
+            })
           default:
             dispatch(flashOperationResult(GENERIC_SERVER_ERROR, false))
         }
@@ -0,0 +1,54 @@
+import React, { Component } from 'react';
+import fetch from 'isomorphic-fetch';
+import ReCAPTCHA from "react-google-recaptcha";
+
+// Stub providers accept or reject any token so there is no widget to show
+const stubProviders = ['always_pass', 'always_fail']
+
+// Captcha renders the widget of the CAPTCHA provider the server is configured with. Only the reCAPTCHA
+// v2 checkbox is bundled, other providers need their widget added here.
+class Captcha extends Component {
+  constructor(props) {
+    super(props)
+    this.state = {
+      provider: null,
+      siteKey: null
+    }
+  }
+
+  componentDidMount() {
+    fetch('/api/captcha')
+      .then(rawResponse => {
+        if (!rawResponse.ok) {
+          return Promise.reject(rawResponse)
+        }
+
+        return rawResponse.json()
+      }).then(settings => {
+        if (stubProviders.indexOf(settings.provider) !== -1) {
+          this.props.onChange('stub-token')
+        }
+
+        this.setState(settings)
+      }).catch(err => {
+        console.warn("captcha settings error", err)
+      })
+  }
+
+  render() {
+    const { provider, siteKey } = this.state
+
+    if (provider === 'recaptcha') {
+      return (
+        <ReCAPTCHA
+          sitekey={siteKey}
+          onChange={this.props.onChange}
+        />
+      )
+    }
+
+    return null
+  }
+}
+
+export default Captcha
@@ -6,6 +6,10 @@ import {
   logoutUser
 } from '../../actions/logout';
 
+import {
+  scheduleSessionRefresh
+} from '../../actions/login';
+
 import {
   fetchRSVPs
 } from '../../actions/rsvp';
@@ -21,9 +25,27 @@ import {
 import RSVPs from '../RSVPs';
 import Invitations from '../Invitations';
 import Categories from '../Categories';
+import PasswordForm from '../PasswordForm';
+import TwoFactorForm from '../TwoFactorForm';
 
 class ControlPanel extends Component {
+  constructor(props) {
+    super(props)
+    this.state = { passwordFormVisible: false, twoFactorFormVisible: false }
+    this.togglePasswordForm = this.togglePasswordForm.bind(this)
+    this.toggleTwoFactorForm = this.toggleTwoFactorForm.bind(this)
+  }
+
+  togglePasswordForm() {
+    this.setState({ passwordFormVisible: !this.state.passwordFormVisible, twoFactorFormVisible: false })
+  }
+
+  toggleTwoFactorForm() {
+    this.setState({ twoFactorFormVisible: !this.state.twoFactorFormVisible, passwordFormVisible: false })
+  }
+
   componentDidMount() {
+    this.props.onScheduleSessionRefresh()
     this.props.onFetchRSVPs()
     this.props.onFetchCategories()
     this.props.onFetchInvitations()
@@ -45,6 +67,8 @@ class ControlPanel extends Component {
 
             <Col lg={6} className="text-right">
               <span className="margin-right-md">Hello {this.props.username}!</span>
+              <Button bsStyle="default" bsSize="small" className="margin-right-sm" onClick={this.togglePasswordForm}>Change Password</Button>
+              <Button bsStyle="default" bsSize="small" className="margin-right-sm" onClick={this.toggleTwoFactorForm}>Two-Factor</Button>
               <Button bsStyle="default" bsSize="small" onClick={this.props.onLogoutClick}>Logout</Button>
             </Col>
           </Row>
@@ -59,6 +83,10 @@ class ControlPanel extends Component {
             </Row>}
           </div>
 
+          {this.state.passwordFormVisible && <PasswordForm onCancel={this.togglePasswordForm} />}
+
+          {this.state.twoFactorFormVisible && <TwoFactorForm onCancel={this.toggleTwoFactorForm} />}
+
           <Row>
             <Col lg={12}>
               <div className="tabs-container">
@@ -125,6 +153,9 @@ const mapDispatchToProps = (dispatch) => {
     onFetchInvitations: () => {
       dispatch(fetchInvitations())
     },
+    onScheduleSessionRefresh: () => {
+      dispatch(scheduleSessionRefresh())
+    },
     onLogoutClick: () => {
       dispatch(logoutUser())
     }
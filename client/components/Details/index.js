@@ -5,8 +5,10 @@ import Scroll, { Link,Element } from 'react-scroll';
 
 import RSVPForm from '../RSVPForm';
 import RSVPAck from '../RSVPAck';
+import PhoneConfirmation from '../PhoneConfirmation';
 
 import {
+  confirmGuestPhoneNumber,
   fetchRSVP
 } from '../../actions/guest';
 
@@ -132,6 +134,14 @@ class Details extends Component {
       </section>
 
 
+      {this.props.guestPhoneConfirmationRequired && <section className="padding-top-lg padding-bottom-lg">
+        <Row>
+          <Col lg={6} lgOffset={3}>
+            <PhoneConfirmation onConfirm={(phoneNumber) => this.props.confirmPhoneNumber(this.props.params.id, phoneNumber)} />
+          </Col>
+        </Row>
+      </section>}
+
       {this.props.guestRSVP && <Element name="form">
         <section>
           <div className="rsvp-form">
@@ -161,7 +171,8 @@ class Details extends Component {
 
 const mapStateToProps = (state) => {
   return {
-    guestRSVP: state.guestRSVP
+    guestRSVP: state.guestRSVP,
+    guestPhoneConfirmationRequired: state.guestPhoneConfirmationRequired
   };
 };
 
@@ -171,6 +182,9 @@ const mapDispatchToProps = (dispatch) => {
       if (id) {
         dispatch(fetchRSVP(id)); 
       }
+    },
+    confirmPhoneNumber: (id, phoneNumber) => {
+      dispatch(confirmGuestPhoneNumber(id, phoneNumber));
     }
   };
 };
@@ -18,7 +18,7 @@ import {
   INVITATION_STATUS_SENT
 } from '../../constants';
 
-import { isEmpty } from '../../validation';
+import { isEmpty,email } from '../../validation';
 
 const validationValues = Object.freeze({
   GREETING_MIN_LENGTH: 2,
@@ -50,6 +50,14 @@ const validate = values => {
       errors.mobilePhoneNumber = `Please enter a mobile phone number less than ${validationValues.MAXIMUM_PHONE_NUMBER_LENGTH} numbers`;
   }
 
+  if (email(values.email)) {
+    errors.email = `Please enter a valid email address`;
+  }
+
+  if (!isEmpty(values.language) && !/^[a-zA-Z]{2,3}(-[a-zA-Z0-9]{2,8})*$/.test(values.language)) {
+    errors.language = `Please enter a language tag such as en or zh-Hans`;
+  }
+
   if (!isEmpty(values.notes) && values.notes.length > validationValues.NOTES_MAXIMUM_LENGTH) {
     errors.notes = `Please enter some notes no longer than ${validationValues.NOTES_MAXIMUM_LENGTH} characters in length`;
   }
@@ -142,6 +150,37 @@ const mobilePhoneNumberInput = field =>
     </Col>
   </FormGroup>;
 
+const emailInput = field =>
+  <FormGroup>
+    <Col componentClass={ControlLabel} lg={4}>
+      Email:
+    </Col>
+
+    <Col lg={8}>
+      <FormControl
+        type="email"
+        {...field.input}>
+      </FormControl>
+      {field.meta.touched && field.meta.error && <div className="form-error">{field.meta.error}</div>}
+    </Col>
+  </FormGroup>;
+
+const languageInput = field =>
+  <FormGroup>
+    <Col componentClass={ControlLabel} lg={4}>
+      Language:
+    </Col>
+
+    <Col lg={8}>
+      <FormControl
+        type="text"
+        placeholder="en"
+        {...field.input}>
+      </FormControl>
+      {field.meta.touched && field.meta.error && <div className="form-error">{field.meta.error}</div>}
+    </Col>
+  </FormGroup>;
+
 const statusSelect = field =>
   <FormGroup>
     <Col componentClass={ControlLabel} lg={4}>
@@ -164,7 +203,10 @@ const submit = (values, dispatch, props) => {
     maximumGuestCount: parseInt(values.maximumGuestCount),
     notes: values.notes,
     mobilePhoneNumber: values.mobilePhoneNumber,
-    status: values.status
+    email: values.email,
+    language: values.language,
+    status: values.status,
+    version: values.version
   }
 
   if (props.mode === INVITATION_FORM_NEW_MODE) {
@@ -222,6 +264,16 @@ class InvitationForm extends Component {
               component={mobilePhoneNumberInput}
             />
 
+            <Field
+              name="email"
+              component={emailInput}
+            />
+
+            <Field
+              name="language"
+              component={languageInput}
+            />
+
             <Field
               name="status"
               component={statusSelect}
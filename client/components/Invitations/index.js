@@ -114,7 +114,12 @@ class Invitations extends Component {
                   <td>{invitation.maximumGuestCount}</td>
                   <td>{invitation.notes || '-'}</td>
                   <td>{invitation.mobilePhoneNumber}</td>
-                  <td>{translateStatusCode(invitation.status)}<p><small>{formatDateForDisplay(invitation.updatedAt)}</small></p></td>
+                  <td>
+                    {translateStatusCode(invitation.status)}<p><small>{formatDateForDisplay(invitation.updatedAt)}</small></p>
+                    {invitation.delivery && invitation.delivery.map((delivery) => {
+                      return <p key={delivery.channel} title={delivery.error}><small>{delivery.channel}: {delivery.status}</small></p>
+                    })}
+                  </td>
                   <td>
                     <Button bsStyle="danger" bsSize="xs" className="margin-right-sm" onClick={() => {this.props.onToggleDeleteInvitation(invitation.id)}}>Delete</Button>
                     <Button bsStyle="success" bsSize="xs" className="margin-right-sm">Send RSVP</Button>
@@ -3,10 +3,12 @@ import { connect } from 'react-redux';
 import { reduxForm,reset,change,Field,Fields } from 'redux-form';
 import { browserHistory } from 'react-router';
 import { Row,Col,FormGroup,FormControl,ControlLabel,Button } from 'react-bootstrap';
-import ReCAPTCHA from "react-google-recaptcha";
+import Captcha from '../Captcha';
 
 import {
-  loginUser
+  loginUser,
+  submitTwoFactorCode,
+  cancelTwoFactor
 } from '../../actions/login';
 
 import { isEmpty } from '../../validation';
@@ -29,6 +31,16 @@ const validate = values => {
   return errors;
 }
 
+const validateTwoFactor = values => {
+  var errors = {}
+
+  if (isEmpty(values.code)) {
+    errors.code = `Please enter the code from your authenticator app or a recovery code`;
+  }
+
+  return errors;
+}
+
 const usernameInput = field =>
   <FormGroup>
     <Col componentClass={ControlLabel} lg={4}>
@@ -59,10 +71,26 @@ const passwordInput = field =>
     </Col>
   </FormGroup>;
 
+const codeInput = field =>
+  <FormGroup>
+    <Col componentClass={ControlLabel} lg={4}>
+      Code:
+    </Col>
+
+    <Col lg={6}>
+      <FormControl
+        type="text"
+        autoComplete="off"
+        autoFocus
+        {...field.input}>
+      </FormControl>
+      {field.meta.touched && field.meta.error && <div className="form-error">{field.meta.error}</div>}
+    </Col>
+  </FormGroup>;
+
 const recaptchaInput = field =>
   <Col className="margin-top-md margin-bottom-lg" lg={6} lgOffset={4}>
-    <ReCAPTCHA
-      sitekey="6Ld9PQkUAAAAADM9IjQjKnXKNOOswNJe0NknxJAF"
+    <Captcha
       onChange={field.onReCAPTCHAChange}
     />
     {field.meta.touched && field.meta.error && <div className="form-error">{field.meta.error}</div>}
@@ -80,6 +108,36 @@ const submit = (values, dispatch) => {
   return dispatch(loginUser(credentials));
 }
 
+const submitTwoFactor = (values, dispatch) => {
+  return dispatch(submitTwoFactorCode(values.code));
+}
+
+// TwoFactorForm is the second step of logging in for users with two-factor authentication
+let TwoFactorForm = props => {
+  const { handleSubmit, auth, onCancel } = props;
+
+  return <form className="form-horizontal margin-left-xs margin-right-sm" onSubmit={handleSubmit(submitTwoFactor)}>
+    <p className="text-center margin-bottom-md">Enter the code from your authenticator app, or one of your recovery codes.</p>
+
+    <Field
+      name="code"
+      component={codeInput}
+    />
+
+    <Row className="margin-top-md">
+      <Col className="text-right margin-top-sm" xs={12}>
+        <Button bsSize="small" onClick={onCancel}>Back</Button>
+        <Button type="submit" className="margin-left-sm" bsStyle="primary" bsSize="small" disabled={auth.isFetching}>Verify</Button>
+      </Col>
+    </Row>
+  </form>;
+}
+
+TwoFactorForm = reduxForm({
+  form: 'twoFactorForm',
+  validate: validateTwoFactor
+})(TwoFactorForm);
+
 class LoginForm extends Component {
 
   componentWillMount() {
@@ -99,7 +157,9 @@ class LoginForm extends Component {
 
           <div className="panel">
             <div className="panel-body">
-              <form className="form-horizontal margin-left-xs margin-right-sm" onSubmit={handleSubmit(submit)}>
+              {this.props.auth.preAuthToken && <TwoFactorForm auth={this.props.auth} onCancel={this.props.onCancelTwoFactor} />}
+
+              {!this.props.auth.preAuthToken && <form className="form-horizontal margin-left-xs margin-right-sm" onSubmit={handleSubmit(submit)}>
                 <Field
                   name="username"
                   component={usernameInput}
@@ -121,7 +181,7 @@ class LoginForm extends Component {
                     <Button type="submit" className="margin-left-sm" bsStyle="primary" bsSize="small" disabled={this.props.auth.isFetching}>Login</Button>
                   </Col>
                 </Row>
-              </form>
+              </form>}
             </div>
           </div>
         </Col>
@@ -140,6 +200,9 @@ const mapDispatchToProps = (dispatch) => {
   return {
     onReCAPTCHAChange: (value) => {
       dispatch(change('loginForm', 'recaptcha', value));
+    },
+    onCancelTwoFactor: () => {
+      dispatch(cancelTwoFactor());
     }
   };
 };
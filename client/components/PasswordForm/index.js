@@ -0,0 +1,111 @@
+import React, { Component } from 'react';
+import { reduxForm,Field } from 'redux-form';
+import { Row,Col,FormGroup,FormControl,ControlLabel,Button } from 'react-bootstrap';
+
+import {
+  submitPasswordChange
+} from '../../actions/account';
+
+import { isEmpty } from '../../validation';
+
+const validationValues = Object.freeze({
+  PASSWORD_MIN_LENGTH: 8,
+  PASSWORD_MAX_LENGTH: 72
+})
+
+const validate = values => {
+  var errors = {}
+
+  if (isEmpty(values.currentPassword)) {
+    errors.currentPassword = 'Please enter your current password';
+  }
+
+  if (isEmpty(values.newPassword) || 
+      values.newPassword.length < validationValues.PASSWORD_MIN_LENGTH || 
+      values.newPassword.length > validationValues.PASSWORD_MAX_LENGTH) {
+    errors.newPassword = `Please enter a password between ${validationValues.PASSWORD_MIN_LENGTH} to ${validationValues.PASSWORD_MAX_LENGTH} characters long`;
+  }
+
+  if (values.confirmPassword !== values.newPassword) {
+    errors.confirmPassword = 'Passwords do not match';
+  }
+
+  return errors;
+}
+
+const passwordInput = field =>
+  <FormGroup>
+    <Col componentClass={ControlLabel} lg={4}>
+      {field.label}:
+    </Col>
+
+    <Col lg={6}>
+      <FormControl
+        type="password" 
+        {...field.input}>
+      </FormControl>
+      {field.meta.touched && field.meta.error && <div className="form-error">{field.meta.error}</div>}
+    </Col>
+  </FormGroup>;
+
+const submit = (values, dispatch, props) => {
+  let passwordChange = {
+    currentPassword: values.currentPassword,
+    newPassword: values.newPassword
+  }
+
+  return dispatch(submitPasswordChange(passwordChange)).then(changed => {
+    if (changed) {
+      props.onCancel()
+    }
+  })
+}
+
+class PasswordForm extends Component {
+
+  render() {
+    const { handleSubmit, submitting } = this.props;
+
+    return <Row>
+      <Col lg={6} lgOffset={3}>
+        <div className="well">
+          <form className="form-horizontal margin-left-xs margin-right-sm" onSubmit={handleSubmit(submit)}>
+            <h4>Change Password</h4>
+            
+            <Field
+              name="currentPassword"
+              label="Current Password"
+              component={passwordInput}
+            />
+
+            <Field
+              name="newPassword"
+              label="New Password"
+              component={passwordInput}
+            />
+
+            <Field
+              name="confirmPassword"
+              label="Confirm Password"
+              component={passwordInput}
+            />
+
+            <Row className="margin-top-md">
+              <Col className="text-right margin-top-sm" xs={12}>
+                <Button bsStyle="default" bsSize="sm" onClick={this.props.onCancel}>Cancel</Button>
+                <Button type="submit" className="margin-left-sm" bsStyle="success" bsSize="sm" disabled={submitting}>Change</Button>
+              </Col>
+            </Row>
+          </form>
+        </div>
+      </Col>
+    </Row>;
+  }     
+}
+
+PasswordForm = reduxForm({ 
+  form: 'passwordForm',
+  validate
+})(PasswordForm);
+
+export default PasswordForm;
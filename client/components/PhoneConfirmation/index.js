@@ -0,0 +1,47 @@
+import React, { Component } from 'react';
+import { Row,Col,FormGroup,FormControl,ControlLabel,Button } from 'react-bootstrap';
+
+// PhoneConfirmation asks guests for the mobile phone number of their invitation before showing it, for
+// invitation links the hosts have asked to be confirmed
+class PhoneConfirmation extends Component {
+  constructor(props) {
+    super(props)
+    this.state = {
+      phoneNumber: ''
+    }
+    this.handleSubmit = this.handleSubmit.bind(this)
+  }
+
+  handleSubmit(event) {
+    event.preventDefault()
+
+    this.props.onConfirm(this.state.phoneNumber)
+  }
+
+  render() {
+    return <div className="panel padding-bottom-md">
+      <div className="panel-body">
+        <form className="form-horizontal margin-left-xs margin-right-sm" onSubmit={this.handleSubmit}>
+          <p>Please enter the mobile phone number this invitation was sent to.</p>
+
+          <FormGroup>
+            <Col componentClass={ControlLabel} sm={4}>
+              Mobile Number
+            </Col>
+            <Col sm={8}>
+              <FormControl type="tel" value={this.state.phoneNumber} onChange={(event) => this.setState({ phoneNumber: event.target.value })} />
+            </Col>
+          </FormGroup>
+
+          <Row className="margin-top-md">
+            <Col className="text-right" xs={12}>
+              <Button type="submit" bsStyle="success" bsSize="sm" disabled={!this.state.phoneNumber}>Continue</Button>
+            </Col>
+          </Row>
+        </form>
+      </div>
+    </div>;
+  }
+}
+
+export default PhoneConfirmation;
@@ -68,6 +68,16 @@ class RSVPAck extends Component {
                 <strong>{this.props.rsvp.mobilePhoneNumber}</strong>
               </Col>
             </Row>
+
+            {this.props.rsvp.email && <Row className="margin-top-md">
+              <Col xs={2} xsOffset={3}>
+                Email:
+              </Col>
+
+              <Col xs={4}>
+                <strong>{this.props.rsvp.email}</strong>
+              </Col>
+            </Row>}
           </div>;
         })()}
       </div>
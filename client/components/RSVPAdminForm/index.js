@@ -18,7 +18,7 @@ import {
   INVITATION_STATUS_SENT
 } from '../../constants';
 
-import { isEmpty } from '../../validation';
+import { isEmpty,email } from '../../validation';
 
 const validationValues = Object.freeze({
   GREETING_MIN_LENGTH: 2,
@@ -57,6 +57,10 @@ const validate = values => {
       errors.mobilePhoneNumber = `Please enter a mobile phone number between ${validationValues.MINIMUM_PHONE_NUMBER_LENGTH} to ${validationValues.MAXIMUM_PHONE_NUMBER_LENGTH} long`;
   }
 
+  if (email(values.email)) {
+    errors.email = `Please enter a valid email address`;
+  }
+
   return errors;
 }
 
@@ -163,6 +167,21 @@ const mobilePhoneNumberInput = field =>
     </Col>
   </FormGroup>;
 
+const emailInput = field =>
+  <FormGroup>
+    <Col componentClass={ControlLabel} lg={4}>
+      Email:
+    </Col>
+
+    <Col lg={6}>
+      <FormControl
+        type="email"
+        {...field.input}>
+      </FormControl>
+      {field.meta.touched && field.meta.error && <div className="form-error">{field.meta.error}</div>}
+    </Col>
+  </FormGroup>;
+
 const submit = (values, dispatch, props) => {
   let rsvp = {
     id: values.id,
@@ -172,7 +191,9 @@ const submit = (values, dispatch, props) => {
     specialDiet: values.specialDiet,
     guestCount: parseInt(values.guestCount),
     remarks: values.remarks,
-    mobilePhoneNumber: values.mobilePhoneNumber
+    mobilePhoneNumber: values.mobilePhoneNumber,
+    email: values.email,
+    version: values.version
   }
 
   if (props.mode === RSVP_FORM_NEW_MODE) {
@@ -240,6 +261,11 @@ class RSVPAdminForm extends Component {
               component={mobilePhoneNumberInput}
             />
 
+            <Field
+              name="email"
+              component={emailInput}
+            />
+
             <Row className="margin-top-md">
               <Col className="text-right margin-top-sm" xs={12}>
                 <Button bsStyle="default" bsSize="sm" onClick={this.props.onToggleRSVPFormVisibilityClick}>Cancel</Button>
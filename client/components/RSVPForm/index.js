@@ -2,7 +2,7 @@ import React, { Component } from 'react';
 import { connect } from 'react-redux';
 import { reduxForm,reset,change,Field,Fields } from 'redux-form';
 import { Row,Col,FormGroup,FormControl,ControlLabel,Radio,Button,Alert } from 'react-bootstrap';
-import ReCAPTCHA from "react-google-recaptcha";
+import Captcha from '../Captcha';
 
 const { textarea } = require('./styles.css');
 
@@ -10,7 +10,7 @@ import {
   submitGuestRSVPCreate
 } from '../../actions/guest';
 
-import { isEmpty,isIncluded } from '../../validation';
+import { isEmpty,isIncluded,email } from '../../validation';
 
 const validationValues = Object.freeze({
   FULL_NAME_MIN_LENGTH: 2,
@@ -41,6 +41,10 @@ const validate = values => {
       errors.mobilePhoneNumber = `Please enter a mobile phone number between ${validationValues.MINIMUM_PHONE_NUMBER_LENGTH} to ${validationValues.MAXIMUM_PHONE_NUMBER_LENGTH} in length`;
   }
 
+  if (email(values.email)) {
+    errors.email = `Please enter a valid email address`;
+  }
+
   if (isEmpty(values.reCAPTCHA)) {
     errors.reCAPTCHA = `Please click on the checkbox`;
   }
@@ -139,10 +143,25 @@ const mobilePhoneNumberInput = field =>
     </Col>
   </FormGroup>;
 
+const emailInput = field =>
+  <FormGroup>
+    <Col componentClass={ControlLabel} lg={4}>
+      Email:
+    </Col>
+
+    <Col lg={6}>
+      <FormControl
+        type="email"
+        {...field.input}>
+      </FormControl>
+      <small className="text-muted">* Optional, for a copy of your reply.</small>
+      {field.meta.touched && field.meta.error && <div className="form-error">{field.meta.error}</div>}
+    </Col>
+  </FormGroup>;
+
 const recaptchaInput = field =>
   <Col className="margin-top-md margin-bottom-lg" lg={8} lgOffset={4}>
-    <ReCAPTCHA
-      sitekey="6Ld9PQkUAAAAADM9IjQjKnXKNOOswNJe0NknxJAF"
+    <Captcha
       onChange={field.onReCAPTCHAChange}
     />
     {field.meta.touched && field.meta.error && <div className="form-error">{field.meta.error}</div>}
@@ -157,7 +176,8 @@ const submit = (values, dispatch) => {
     reCAPTCHA: values.reCAPTCHA,
     remarks: values.remarks,
     specialDiet: values.specialDiet,
-    mobilePhoneNumber: values.mobilePhoneNumber
+    mobilePhoneNumber: values.mobilePhoneNumber,
+    email: values.email
   }
 
   return dispatch(submitGuestRSVPCreate(rsvp))
@@ -213,6 +233,11 @@ class RSVPForm extends Component {
               component={mobilePhoneNumberInput}
             />
 
+            <Field
+              name="email"
+              component={emailInput}
+            />
+
             <Field
               name="recaptcha"
               component={recaptchaInput}
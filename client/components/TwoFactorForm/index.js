@@ -0,0 +1,186 @@
+import React, { Component } from 'react';
+import { connect } from 'react-redux';
+import { Row,Col,FormGroup,FormControl,ControlLabel,Button } from 'react-bootstrap';
+import QRCode from 'qrcode.react';
+
+import {
+  beginTwoFactorEnrollment,
+  confirmTwoFactorEnrollment,
+  disableTwoFactor
+} from '../../actions/account';
+
+// TwoFactorForm sets up two-factor authentication by scanning a QR code into an authenticator app and
+// confirming a code from it, or turns it off again given the password
+class TwoFactorForm extends Component {
+  constructor(props) {
+    super(props)
+    this.state = {
+      enabled: localStorage.getItem('twoFactorEnabled') === 'true',
+      enrollment: null,
+      recoveryCodes: null,
+      code: '',
+      password: ''
+    }
+    this.handleBegin = this.handleBegin.bind(this)
+    this.handleConfirm = this.handleConfirm.bind(this)
+    this.handleDisable = this.handleDisable.bind(this)
+  }
+
+  handleBegin() {
+    this.props.onBegin().then(enrollment => {
+      if (enrollment) {
+        this.setState({ enrollment })
+      }
+    })
+  }
+
+  handleConfirm(event) {
+    event.preventDefault()
+
+    this.props.onConfirm(this.state.code).then(recoveryCodes => {
+      if (recoveryCodes) {
+        this.setState({ enabled: true, enrollment: null, recoveryCodes, code: '' })
+      }
+    })
+  }
+
+  handleDisable(event) {
+    event.preventDefault()
+
+    this.props.onDisable(this.state.password).then(disabled => {
+      if (disabled) {
+        this.props.onCancel()
+      }
+    })
+  }
+
+  renderRecoveryCodes() {
+    return <div>
+      <p>Keep these recovery codes somewhere safe. Each one logs you in once if you lose your authenticator app, and they will not be shown again.</p>
+      <ul className="list-unstyled text-center">
+        {this.state.recoveryCodes.map(code => <li key={code}><code>{code}</code></li>)}
+      </ul>
+
+      <Row className="margin-top-md">
+        <Col className="text-right margin-top-sm" xs={12}>
+          <Button bsStyle="success" bsSize="sm" onClick={this.props.onCancel}>Done</Button>
+        </Col>
+      </Row>
+    </div>;
+  }
+
+  renderEnrollment() {
+    return <form className="form-horizontal margin-left-xs margin-right-sm" onSubmit={this.handleConfirm}>
+      <p>Scan this QR code with your authenticator app, or enter the key <code>{this.state.enrollment.secret}</code> by hand, then enter the code it shows.</p>
+
+      <div className="text-center margin-bottom-md">
+        <QRCode value={this.state.enrollment.provisioningURI} size={192} />
+      </div>
+
+      <FormGroup>
+        <Col componentClass={ControlLabel} lg={4}>
+          Code:
+        </Col>
+
+        <Col lg={6}>
+          <FormControl
+            type="text"
+            autoComplete="off"
+            value={this.state.code}
+            onChange={event => this.setState({ code: event.target.value })}>
+          </FormControl>
+        </Col>
+      </FormGroup>
+
+      <Row className="margin-top-md">
+        <Col className="text-right margin-top-sm" xs={12}>
+          <Button bsStyle="default" bsSize="sm" onClick={this.props.onCancel}>Cancel</Button>
+          <Button type="submit" className="margin-left-sm" bsStyle="success" bsSize="sm" disabled={this.state.code === ''}>Turn On</Button>
+        </Col>
+      </Row>
+    </form>;
+  }
+
+  renderDisable() {
+    return <form className="form-horizontal margin-left-xs margin-right-sm" onSubmit={this.handleDisable}>
+      <p>Two-factor authentication is on. Enter your password to turn it off.</p>
+
+      <FormGroup>
+        <Col componentClass={ControlLabel} lg={4}>
+          Password:
+        </Col>
+
+        <Col lg={6}>
+          <FormControl
+            type="password"
+            value={this.state.password}
+            onChange={event => this.setState({ password: event.target.value })}>
+          </FormControl>
+        </Col>
+      </FormGroup>
+
+      <Row className="margin-top-md">
+        <Col className="text-right margin-top-sm" xs={12}>
+          <Button bsStyle="default" bsSize="sm" onClick={this.props.onCancel}>Cancel</Button>
+          <Button type="submit" className="margin-left-sm" bsStyle="danger" bsSize="sm" disabled={this.state.password === ''}>Turn Off</Button>
+        </Col>
+      </Row>
+    </form>;
+  }
+
+  renderStart() {
+    return <div>
+      <p>Two-factor authentication asks for a code from an authenticator app on your phone after your password.</p>
+
+      <Row className="margin-top-md">
+        <Col className="text-right margin-top-sm" xs={12}>
+          <Button bsStyle="default" bsSize="sm" onClick={this.props.onCancel}>Cancel</Button>
+          <Button className="margin-left-sm" bsStyle="success" bsSize="sm" onClick={this.handleBegin}>Set Up</Button>
+        </Col>
+      </Row>
+    </div>;
+  }
+
+  render() {
+    let content
+    switch (true) {
+      case this.state.recoveryCodes !== null:
+        content = this.renderRecoveryCodes()
+        break
+      case this.state.enrollment !== null:
+        content = this.renderEnrollment()
+        break
+      case this.state.enabled:
+        content = this.renderDisable()
+        break
+      default:
+        content = this.renderStart()
+    }
+
+    return <Row>
+      <Col lg={6} lgOffset={3}>
+        <div className="well">
+          <h4>Two-Factor Authentication</h4>
+
+          {content}
+        </div>
+      </Col>
+    </Row>;
+  }
+}
+
+const mapDispatchToProps = (dispatch) => {
+  return {
+    onBegin: () => {
+      return dispatch(beginTwoFactorEnrollment())
+    },
+    onConfirm: (code) => {
+      return dispatch(confirmTwoFactorEnrollment(code))
+    },
+    onDisable: (password) => {
+      return dispatch(disableTwoFactor(password))
+    }
+  };
+};
+
+export default connect(null, mapDispatchToProps)(TwoFactorForm);
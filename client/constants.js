@@ -6,6 +6,7 @@ module.exports = Object.freeze({
     INVITATION_FORM_NEW_MODE: 'NEW_INVITATION',
     INVITATION_FORM_EDIT_MODE: 'EDIT_INVITATION',
     INVITATION_MAX_GUESTS: 10,
+    LIST_PAGE_SIZE: 200,
     INVITATION_STATUS_NOT_SENT: 'NS',
     INVITATION_STATUS_SENT: 'ST',
     INVITATION_STATUS_REPLIED_ATTENDING: 'RA',
@@ -7,7 +7,8 @@ import {
 } from './actions/general';
 
 import { 
-  SET_GUEST_RSVP
+  SET_GUEST_RSVP,
+  SET_GUEST_PHONE_CONFIRMATION_REQUIRED
 } from './actions/guest';
 
 import { 
@@ -40,7 +41,9 @@ import {
 import { 
 	LOGIN_REQUEST,
 	LOGIN_SUCCESS,
-	LOGIN_FAILURE
+	LOGIN_FAILURE,
+	LOGIN_TWO_FACTOR_REQUIRED,
+	LOGIN_TWO_FACTOR_CANCEL
 } from './actions/login';
 
 import { 
@@ -75,6 +78,15 @@ export function guestRSVP(state = null, action) {
   }
 }
 
+export function guestPhoneConfirmationRequired(state = false, action) {
+  switch (action.type) {
+    case SET_GUEST_PHONE_CONFIRMATION_REQUIRED:
+      return action.required;
+    default:
+      return state;
+  }
+}
+
 export function rsvps(state = [], action) {
 	switch (action.type) {
 		case SET_RSVPS:
@@ -328,6 +340,19 @@ export function auth(state = {
 			return Object.assign({}, state, {
 				isFetching: false,
 				isAuthenticated: true,
+				preAuthToken: null,
+				errorMessage: ''
+			});
+		case LOGIN_TWO_FACTOR_REQUIRED:
+			return Object.assign({}, state, {
+				isFetching: false,
+				isAuthenticated: false,
+				preAuthToken: action.preAuthToken,
+				errorMessage: ''
+			});
+		case LOGIN_TWO_FACTOR_CANCEL:
+			return Object.assign({}, state, {
+				preAuthToken: null,
 				errorMessage: ''
 			});
 		case LOGIN_FAILURE:
@@ -355,6 +380,6 @@ export function removeByKey(obj, deleteKey) {
     }, {});
 }
 
-const gatheredReducers = {operation, guestRSVP, rsvps, categories, invitations, rsvpForm, categoryForm, invitationForm, deleteRSVPConfirmation, deleteCategoryConfirmation, deleteInvitationConfirmation, auth, form: formReducer};
+const gatheredReducers = {operation, guestRSVP, guestPhoneConfirmationRequired, rsvps, categories, invitations, rsvpForm, categoryForm, invitationForm, deleteRSVPConfirmation, deleteCategoryConfirmation, deleteInvitationConfirmation, auth, form: formReducer};
 
 export default gatheredReducers;
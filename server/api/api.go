@@ -1,16 +1,30 @@
 package api
 
 import (
+	"net"
+
 	"github.com/rawfish-dev/rsvp-starter/server/config"
+	"github.com/rawfish-dev/rsvp-starter/server/domain"
 	"github.com/rawfish-dev/rsvp-starter/server/interfaces"
+	"github.com/rawfish-dev/rsvp-starter/server/services/apikey"
+	"github.com/rawfish-dev/rsvp-starter/server/services/audit"
 	"github.com/rawfish-dev/rsvp-starter/server/services/cache"
+	"github.com/rawfish-dev/rsvp-starter/server/services/captcha"
 	"github.com/rawfish-dev/rsvp-starter/server/services/category"
 	"github.com/rawfish-dev/rsvp-starter/server/services/invitation"
 	"github.com/rawfish-dev/rsvp-starter/server/services/jwt"
+	"github.com/rawfish-dev/rsvp-starter/server/services/memory"
+	"github.com/rawfish-dev/rsvp-starter/server/services/messagetemplate"
+	"github.com/rawfish-dev/rsvp-starter/server/services/messaging"
 	"github.com/rawfish-dev/rsvp-starter/server/services/postgres"
 	"github.com/rawfish-dev/rsvp-starter/server/services/rsvp"
+	"github.com/rawfish-dev/rsvp-starter/server/services/search"
 	"github.com/rawfish-dev/rsvp-starter/server/services/security"
 	"github.com/rawfish-dev/rsvp-starter/server/services/session"
+	"github.com/rawfish-dev/rsvp-starter/server/services/sqlite"
+	"github.com/rawfish-dev/rsvp-starter/server/services/throttle"
+	"github.com/rawfish-dev/rsvp-starter/server/services/trash"
+	"github.com/rawfish-dev/rsvp-starter/server/services/user"
 
 	"github.com/gin-gonic/gin"
 	"golang.org/x/net/context"
@@ -20,29 +34,44 @@ type API struct {
 	Router   *gin.Engine
 	HTTPPort int
 
+	// SlidingSessions renews auth tokens past half of their lifetime on any authenticated request
+	SlidingSessions bool
+
+	// Captcha tells the client which CAPTCHA widget to render
+	Captcha domain.CaptchaSettings
+
+	// StatusWebhookSecret is what providers sign their delivery status reports with
+	StatusWebhookSecret string
+
+	// TrustedProxies are the only addresses whose forwarding headers are believed for the client IP
+	TrustedProxies []*net.IPNet
+
 	// Service Factories
-	JWTServiceFactory        func(context.Context) interfaces.JWTServiceProvider
-	CacheServiceFactory      func(context.Context) interfaces.CacheServiceProvider
-	SessionServiceFactory    func(context.Context) interfaces.SessionServiceProvider
-	SecurityServiceFactory   func(context.Context) interfaces.SecurityServiceProvider
-	CategoryServiceFactory   func(context.Context) interfaces.CategoryServiceProvider
-	InvitationServiceFactory func(context.Context) interfaces.InvitationServiceProvider
-	RSVPServiceFactory       func(context.Context) interfaces.RSVPServiceProvider
-	CategoryStorageFactory   func(context.Context) interfaces.CategoryStorage
-	InvitationStorageFactory func(context.Context) interfaces.InvitationStorage
-	RSVPStorageFactory       func(context.Context) interfaces.RSVPStorage
+	JWTServiceFactory             func(context.Context) interfaces.JWTServiceProvider
+	CacheServiceFactory           func(context.Context) interfaces.CacheServiceProvider
+	SessionServiceFactory         func(context.Context) interfaces.SessionServiceProvider
+	SecurityServiceFactory        func(context.Context) interfaces.SecurityServiceProvider
+	CaptchaVerifierFactory        func(context.Context) interfaces.CaptchaVerifier
+	LoginThrottleFactory          func(context.Context) interfaces.LoginThrottleServiceProvider
+	GuestThrottleFactory          func(context.Context) interfaces.LoginThrottleServiceProvider
+	MessagingProviderFactory      func(context.Context) interfaces.MessagingProvider
+	EmailSenderFactory            func(context.Context) interfaces.EmailSender
+	CategoryServiceFactory        func(context.Context) interfaces.CategoryServiceProvider
+	InvitationServiceFactory      func(context.Context) interfaces.InvitationServiceProvider
+	RSVPServiceFactory            func(context.Context) interfaces.RSVPServiceProvider
+	MessageTemplateServiceFactory func(context.Context) interfaces.MessageTemplateServiceProvider
+	SearchServiceFactory          func(context.Context) interfaces.SearchServiceProvider
+	TrashServiceFactory           func(context.Context) interfaces.TrashServiceProvider
+	AuditServiceFactory           func(context.Context) interfaces.AuditServiceProvider
+	UserServiceFactory            func(context.Context) interfaces.UserServiceProvider
+	APIKeyServiceFactory          func(context.Context) interfaces.APIKeyServiceProvider
+	StorageFactory                func(context.Context) interfaces.Storage
 }
 
 func NewAPI(config config.Config) *API {
-	// Setup storage factories
-	categoryStorageFactory := func(ctx context.Context) interfaces.CategoryStorage {
-		return postgres.NewService(ctx, config.Postgres)
-	}
-	invitationStorageFactory := func(ctx context.Context) interfaces.InvitationStorage {
-		return postgres.NewService(ctx, config.Postgres)
-	}
-	rsvpStorageFactory := func(ctx context.Context) interfaces.RSVPStorage {
-		return postgres.NewService(ctx, config.Postgres)
+	// Setup storage factory
+	storageFactory := func(ctx context.Context) interfaces.Storage {
+		return NewStorage(ctx, config)
 	}
 
 	// Setup service factories
@@ -53,33 +82,96 @@ func NewAPI(config config.Config) *API {
 		return cache.NewService(ctx)
 	}
 	sessionServiceFactory := func(ctx context.Context) interfaces.SessionServiceProvider {
-		return session.NewService(ctx, config.Session, jwtServiceFactory(ctx), cacheServiceFactory(ctx))
+		return session.NewService(ctx, config.Session, jwtServiceFactory(ctx), cacheServiceFactory(ctx), storageFactory(ctx))
 	}
 	securityServiceFactory := func(ctx context.Context) interfaces.SecurityServiceProvider {
-		return security.NewService(ctx)
+		return security.NewService(ctx, config.Security, jwtServiceFactory(ctx), storageFactory(ctx))
+	}
+	captchaVerifierFactory := func(ctx context.Context) interfaces.CaptchaVerifier {
+		return captcha.NewVerifier(ctx, config.Captcha)
+	}
+	loginThrottleFactory := func(ctx context.Context) interfaces.LoginThrottleServiceProvider {
+		return throttle.NewService(ctx, throttle.LoginNamespace, config.Login, cacheServiceFactory(ctx), storageFactory(ctx))
+	}
+	guestThrottleFactory := func(ctx context.Context) interfaces.LoginThrottleServiceProvider {
+		return throttle.NewService(ctx, throttle.GuestNamespace, config.Login, cacheServiceFactory(ctx), storageFactory(ctx))
+	}
+	messagingProviderFactory := func(ctx context.Context) interfaces.MessagingProvider {
+		return messaging.NewProvider(ctx, config.Messaging)
+	}
+	emailSenderFactory := func(ctx context.Context) interfaces.EmailSender {
+		return messaging.NewEmailSender(ctx, config.Email)
 	}
 	categoryServiceFactory := func(ctx context.Context) interfaces.CategoryServiceProvider {
-		return category.NewService(ctx, categoryStorageFactory(ctx))
+		return category.NewService(ctx, storageFactory(ctx))
 	}
 	invitationServiceFactory := func(ctx context.Context) interfaces.InvitationServiceProvider {
-		return invitation.NewService(ctx, invitationStorageFactory(ctx))
+		return invitation.NewService(ctx, config.Messaging, config.Reminder, messagingProviderFactory(ctx), emailSenderFactory(ctx), storageFactory(ctx))
 	}
 	rsvpServiceFactory := func(ctx context.Context) interfaces.RSVPServiceProvider {
-		return rsvp.NewService(ctx, rsvpStorageFactory(ctx))
+		return rsvp.NewService(ctx, emailSenderFactory(ctx), storageFactory(ctx))
+	}
+	messageTemplateServiceFactory := func(ctx context.Context) interfaces.MessageTemplateServiceProvider {
+		return messagetemplate.NewService(ctx, config.Messaging, storageFactory(ctx))
+	}
+	searchServiceFactory := func(ctx context.Context) interfaces.SearchServiceProvider {
+		return search.NewService(ctx, storageFactory(ctx))
+	}
+	trashServiceFactory := func(ctx context.Context) interfaces.TrashServiceProvider {
+		return trash.NewService(ctx, storageFactory(ctx))
+	}
+	auditServiceFactory := func(ctx context.Context) interfaces.AuditServiceProvider {
+		return audit.NewService(ctx, storageFactory(ctx))
+	}
+	userServiceFactory := func(ctx context.Context) interfaces.UserServiceProvider {
+		return user.NewService(ctx, config.Security, storageFactory(ctx), sessionServiceFactory(ctx))
+	}
+	apiKeyServiceFactory := func(ctx context.Context) interfaces.APIKeyServiceProvider {
+		return apikey.NewService(ctx, storageFactory(ctx))
+	}
+
+	captchaSettings := domain.CaptchaSettings{
+		Provider: config.Captcha.Provider,
+		SiteKey:  config.Captcha.SiteKey,
 	}
 
 	return &API{
-		Router:                   gin.New(),
-		HTTPPort:                 config.HTTPPort,
-		JWTServiceFactory:        jwtServiceFactory,
-		CacheServiceFactory:      cacheServiceFactory,
-		SessionServiceFactory:    sessionServiceFactory,
-		SecurityServiceFactory:   securityServiceFactory,
-		CategoryServiceFactory:   categoryServiceFactory,
-		InvitationServiceFactory: invitationServiceFactory,
-		RSVPServiceFactory:       rsvpServiceFactory,
-		CategoryStorageFactory:   categoryStorageFactory,
-		InvitationStorageFactory: invitationStorageFactory,
-		RSVPStorageFactory:       rsvpStorageFactory,
+		Router:                        gin.New(),
+		HTTPPort:                      config.HTTPPort,
+		SlidingSessions:               config.Session.Sliding,
+		Captcha:                       captchaSettings,
+		StatusWebhookSecret:           config.Messaging.StatusWebhookSecret,
+		TrustedProxies:                config.Login.TrustedProxies,
+		JWTServiceFactory:             jwtServiceFactory,
+		CacheServiceFactory:           cacheServiceFactory,
+		SessionServiceFactory:         sessionServiceFactory,
+		SecurityServiceFactory:        securityServiceFactory,
+		CaptchaVerifierFactory:        captchaVerifierFactory,
+		LoginThrottleFactory:          loginThrottleFactory,
+		GuestThrottleFactory:          guestThrottleFactory,
+		MessagingProviderFactory:      messagingProviderFactory,
+		EmailSenderFactory:            emailSenderFactory,
+		CategoryServiceFactory:        categoryServiceFactory,
+		InvitationServiceFactory:      invitationServiceFactory,
+		RSVPServiceFactory:            rsvpServiceFactory,
+		MessageTemplateServiceFactory: messageTemplateServiceFactory,
+		SearchServiceFactory:          searchServiceFactory,
+		TrashServiceFactory:           trashServiceFactory,
+		AuditServiceFactory:           auditServiceFactory,
+		UserServiceFactory:            userServiceFactory,
+		APIKeyServiceFactory:          apiKeyServiceFactory,
+		StorageFactory:                storageFactory,
+	}
+}
+
+// NewStorage returns the storage backend selected by the storage driver config.
+func NewStorage(ctx context.Context, loadedConfig config.Config) interfaces.Storage {
+	switch loadedConfig.Storage.Driver {
+	case config.SQLiteDriver:
+		return sqlite.NewService(ctx, loadedConfig.SQLite)
+	case config.MemoryDriver:
+		return memory.NewService(ctx)
 	}
+
+	return postgres.NewService(ctx, loadedConfig.Postgres)
 }
@@ -0,0 +1,104 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/rawfish-dev/rsvp-starter/server/domain"
+	"github.com/rawfish-dev/rsvp-starter/server/services/apikey"
+	serviceErrors "github.com/rawfish-dev/rsvp-starter/server/services/errors"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/gin-gonic/gin"
+	"golang.org/x/net/context"
+)
+
+func createAPIKey(api *API) func(c *gin.Context) {
+	return func(c *gin.Context) {
+		ctxlogger := logrus.New()
+		ctx := context.Background()
+		ctx = context.WithValue(ctx, "logger", ctxlogger)
+		ctx = contextWithActor(ctx, c)
+
+		apiKeyService := api.APIKeyServiceFactory(ctx)
+
+		var apiKeyCreateRequest domain.APIKeyCreateRequest
+		err := c.BindJSON(&apiKeyCreateRequest)
+		if err != nil {
+			ctxlogger.Errorf("api key api - unable to create new api key while unwrapping request due to %v", err)
+			c.AbortWithStatus(http.StatusBadRequest)
+			return
+		}
+
+		newAPIKey, err := apiKeyService.CreateAPIKey(&apiKeyCreateRequest)
+		if err != nil {
+			switch err.(type) {
+			case serviceErrors.ValidationError:
+				ctxlogger.Warnf("api key api - unable to create new api key due to validation error %v", err)
+				c.JSON(domain.NewCustomBadRequestError(err.Error()))
+				return
+			}
+
+			ctxlogger.Errorf("api key api - unable to create new api key due to %v", err)
+			c.AbortWithStatus(http.StatusInternalServerError)
+			return
+		}
+
+		c.JSON(http.StatusOK, newAPIKey)
+		return
+	}
+}
+
+func listAPIKeys(api *API) func(c *gin.Context) {
+	return func(c *gin.Context) {
+		ctxlogger := logrus.New()
+		ctx := context.Background()
+		ctx = context.WithValue(ctx, "logger", ctxlogger)
+
+		apiKeyService := api.APIKeyServiceFactory(ctx)
+
+		apiKeys, err := apiKeyService.ListAPIKeys()
+		if err != nil {
+			ctxlogger.Errorf("api key api - unable to list api keys due to %v", err)
+			c.AbortWithStatus(http.StatusInternalServerError)
+			return
+		}
+
+		c.JSON(http.StatusOK, apiKeys)
+		return
+	}
+}
+
+func revokeAPIKey(api *API) func(c *gin.Context) {
+	return func(c *gin.Context) {
+		ctxlogger := logrus.New()
+		ctx := context.Background()
+		ctx = context.WithValue(ctx, "logger", ctxlogger)
+		ctx = contextWithActor(ctx, c)
+
+		apiKeyService := api.APIKeyServiceFactory(ctx)
+
+		apiKeyID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			ctxlogger.Warnf("api key api - unable to revoke api key as params id %v could not be converted due to %v", c.Param("id"), err)
+			c.AbortWithStatus(http.StatusBadRequest)
+			return
+		}
+
+		revokedAPIKey, err := apiKeyService.RevokeAPIKeyByID(apiKeyID)
+		if err != nil {
+			switch err.(type) {
+			case apikey.APIKeyNotFoundError:
+				c.AbortWithStatus(http.StatusNotFound)
+				return
+			}
+
+			ctxlogger.Errorf("api key api - unable to revoke api key due to %v", err)
+			c.AbortWithStatus(http.StatusInternalServerError)
+			return
+		}
+
+		c.JSON(http.StatusOK, revokedAPIKey)
+		return
+	}
+}
@@ -0,0 +1,122 @@
+package api_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+
+	"github.com/rawfish-dev/rsvp-starter/server/api"
+	"github.com/rawfish-dev/rsvp-starter/server/config"
+	"github.com/rawfish-dev/rsvp-starter/server/domain"
+	"github.com/rawfish-dev/rsvp-starter/server/interfaces"
+	"github.com/rawfish-dev/rsvp-starter/server/mock"
+	. "github.com/rawfish-dev/rsvp-starter/server/services/apikey"
+	serviceErrors "github.com/rawfish-dev/rsvp-starter/server/services/errors"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"golang.org/x/net/context"
+)
+
+var _ = Describe("APIKey", func() {
+
+	var ctrl *gomock.Controller
+	var testAPI *api.API
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+
+		testConfig := config.LoadConfig()
+		testAPI = api.NewAPI(testConfig)
+
+		testAPI.SessionServiceFactory = func(ctx context.Context) interfaces.SessionServiceProvider {
+			mockSessionService := mock_interfaces.NewMockSessionServiceProvider(ctrl)
+			mockSessionService.EXPECT().IsSessionValid("").Return(true, nil)
+			mockSessionService.EXPECT().Username("").Return("admin", nil)
+			mockSessionService.EXPECT().Role("").Return(domain.RoleOwner, nil)
+
+			return mockSessionService
+		}
+
+		testAPI.InitRoutes()
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	Context("creation", func() {
+
+		var createAPIKeyReq domain.APIKeyCreateRequest
+
+		BeforeEach(func() {
+			createAPIKeyReq = domain.APIKeyCreateRequest{Name: "spreadsheet", Scopes: []domain.Scope{domain.ScopeInvitationsRead}}
+		})
+
+		It("should return 200 OK and the new api key with the key itself but not its hash", func() {
+			testAPI.APIKeyServiceFactory = func(ctx context.Context) interfaces.APIKeyServiceProvider {
+				mockAPIKeyService := mock_interfaces.NewMockAPIKeyServiceProvider(ctrl)
+				mockAPIKeyService.EXPECT().CreateAPIKey(&createAPIKeyReq).
+					Return(&domain.NewAPIKey{
+						APIKey: domain.APIKey{ID: 1, Name: "spreadsheet", KeyHash: "some-hash"},
+						Key:    "rsvp_some-key",
+					}, nil)
+
+				return mockAPIKeyService
+			}
+
+			reqBytes, err := json.Marshal(createAPIKeyReq)
+			Expect(err).ToNot(HaveOccurred())
+
+			respBody := HitEndpoint(testAPI, "POST", "/api/api-keys", bytes.NewBuffer(reqBytes), http.StatusOK)
+			Expect(string(respBody)).To(ContainSubstring(`"key":"rsvp_some-key"`))
+			Expect(string(respBody)).ToNot(ContainSubstring("some-hash"))
+		})
+
+		It("should return 400 Bad Request for validation errors", func() {
+			testAPI.APIKeyServiceFactory = func(ctx context.Context) interfaces.APIKeyServiceProvider {
+				mockAPIKeyService := mock_interfaces.NewMockAPIKeyServiceProvider(ctrl)
+				mockAPIKeyService.EXPECT().CreateAPIKey(&createAPIKeyReq).
+					Return(nil, serviceErrors.NewValidationError([]string{"at least one scope is required"}))
+
+				return mockAPIKeyService
+			}
+
+			reqBytes, err := json.Marshal(createAPIKeyReq)
+			Expect(err).ToNot(HaveOccurred())
+
+			HitEndpoint(testAPI, "POST", "/api/api-keys", bytes.NewBuffer(reqBytes), http.StatusBadRequest)
+		})
+	})
+
+	Context("revocation", func() {
+
+		It("should return 200 OK and the revoked api key", func() {
+			testAPI.APIKeyServiceFactory = func(ctx context.Context) interfaces.APIKeyServiceProvider {
+				mockAPIKeyService := mock_interfaces.NewMockAPIKeyServiceProvider(ctrl)
+				mockAPIKeyService.EXPECT().RevokeAPIKeyByID(int64(1)).
+					Return(&domain.APIKey{ID: 1, RevokedAt: "2026-10-17T12:00:00Z"}, nil)
+
+				return mockAPIKeyService
+			}
+
+			respBody := HitEndpoint(testAPI, "POST", "/api/api-keys/1/revoke", nil, http.StatusOK)
+
+			var revokedAPIKey domain.APIKey
+			Expect(json.Unmarshal(respBody, &revokedAPIKey)).To(Succeed())
+			Expect(revokedAPIKey.RevokedAt).To(Equal("2026-10-17T12:00:00Z"))
+		})
+
+		It("should return 404 Not Found for unknown api keys", func() {
+			testAPI.APIKeyServiceFactory = func(ctx context.Context) interfaces.APIKeyServiceProvider {
+				mockAPIKeyService := mock_interfaces.NewMockAPIKeyServiceProvider(ctrl)
+				mockAPIKeyService.EXPECT().RevokeAPIKeyByID(int64(1)).Return(nil, NewAPIKeyNotFoundError())
+
+				return mockAPIKeyService
+			}
+
+			HitEndpoint(testAPI, "POST", "/api/api-keys/1/revoke", nil, http.StatusNotFound)
+		})
+	})
+})
@@ -0,0 +1,51 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/rawfish-dev/rsvp-starter/server/domain"
+	serviceErrors "github.com/rawfish-dev/rsvp-starter/server/services/errors"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/gin-gonic/gin"
+	"golang.org/x/net/context"
+)
+
+func listAuditEntries(api *API) func(c *gin.Context) {
+	return func(c *gin.Context) {
+		ctxlogger := logrus.New()
+		ctx := context.Background()
+		ctx = context.WithValue(ctx, "logger", ctxlogger)
+
+		auditService := api.AuditServiceFactory(ctx)
+
+		query := &listQuery{c: c}
+		auditListRequest := domain.AuditListRequest{
+			ListRequest: query.listRequest(),
+			EntityType:  domain.AuditEntityType(c.Query("entityType")),
+			EntityID:    query.int64("entityID"),
+		}
+		if query.err != nil {
+			ctxlogger.Warnf("audit api - unable to list audit entries due to invalid query %v", query.err)
+			c.JSON(domain.NewCustomBadRequestError(query.err.Error()))
+			return
+		}
+
+		auditList, err := auditService.ListAuditEntries(&auditListRequest)
+		if err != nil {
+			switch err.(type) {
+			case serviceErrors.ValidationError:
+				ctxlogger.Warnf("audit api - unable to list audit entries due to validation error %v", err)
+				c.JSON(domain.NewCustomBadRequestError(err.Error()))
+				return
+			}
+
+			ctxlogger.Errorf("audit api - unable to list audit entries due to %v", err)
+			c.AbortWithStatus(http.StatusInternalServerError)
+			return
+		}
+
+		c.JSON(http.StatusOK, auditList)
+		return
+	}
+}
@@ -0,0 +1,117 @@
+package api_test
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/rawfish-dev/rsvp-starter/server/api"
+	"github.com/rawfish-dev/rsvp-starter/server/config"
+	"github.com/rawfish-dev/rsvp-starter/server/domain"
+	"github.com/rawfish-dev/rsvp-starter/server/interfaces"
+	"github.com/rawfish-dev/rsvp-starter/server/mock"
+	serviceErrors "github.com/rawfish-dev/rsvp-starter/server/services/errors"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"golang.org/x/net/context"
+)
+
+var _ = Describe("Audit", func() {
+
+	var ctrl *gomock.Controller
+	var testAPI *api.API
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+
+		testConfig := config.LoadConfig()
+		testAPI = api.NewAPI(testConfig)
+
+		testAPI.SessionServiceFactory = func(ctx context.Context) interfaces.SessionServiceProvider {
+			mockSessionService := mock_interfaces.NewMockSessionServiceProvider(ctrl)
+			mockSessionService.EXPECT().IsSessionValid("").Return(true, nil)
+			mockSessionService.EXPECT().Username("").Return("admin", nil)
+			mockSessionService.EXPECT().Role("").Return(domain.RoleOwner, nil)
+
+			return mockSessionService
+		}
+
+		testAPI.InitRoutes()
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	Context("listing", func() {
+
+		It("should return 200 OK and the entries of an entity", func() {
+			auditList := &domain.AuditList{
+				ListResponse: domain.ListResponse{Page: 1, PageSize: 20, Total: 1},
+				Entries: []domain.AuditEntry{
+					{
+						ID:         1,
+						Actor:      "admin",
+						Action:     domain.AuditUpdated,
+						EntityType: domain.InvitationAuditEntity,
+						EntityID:   2,
+						Before:     json.RawMessage(`{"greeting":"ah ma"}`),
+						After:      json.RawMessage(`{"greeting":"ah gong"}`),
+						CreatedAt:  "2026-10-17T10:00:00Z",
+					},
+				},
+			}
+
+			testAPI.AuditServiceFactory = func(ctx context.Context) interfaces.AuditServiceProvider {
+				mockAuditService := mock_interfaces.NewMockAuditServiceProvider(ctrl)
+				mockAuditService.EXPECT().ListAuditEntries(&domain.AuditListRequest{
+					ListRequest: domain.ListRequest{Page: 1, PageSize: 20},
+					EntityType:  domain.InvitationAuditEntity,
+					EntityID:    2,
+				}).Return(auditList, nil)
+
+				return mockAuditService
+			}
+
+			responseBytes := HitEndpoint(testAPI, "GET", "/api/audit?page=1&pageSize=20&entityType=invitation&entityID=2", nil, http.StatusOK)
+
+			var retrievedAuditList domain.AuditList
+			err := json.Unmarshal(responseBytes, &retrievedAuditList)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(retrievedAuditList).To(Equal(*auditList))
+		})
+
+		It("should return 400 Bad Request when the entity id is not a number", func() {
+			HitEndpoint(testAPI, "GET", "/api/audit?entityType=invitation&entityID=abc", nil, http.StatusBadRequest)
+		})
+
+		It("should return 400 Bad Request when a validation error occurs", func() {
+			testAPI.AuditServiceFactory = func(ctx context.Context) interfaces.AuditServiceProvider {
+				mockAuditService := mock_interfaces.NewMockAuditServiceProvider(ctrl)
+				mockAuditService.EXPECT().ListAuditEntries(gomock.Any()).
+					Return(nil, serviceErrors.NewValidationError([]string{"entity type guest is invalid"}))
+
+				return mockAuditService
+			}
+
+			responseBytes := HitEndpoint(testAPI, "GET", "/api/audit?entityType=guest", nil, http.StatusBadRequest)
+
+			var badRequestError domain.CustomBadRequestError
+			err := json.Unmarshal(responseBytes, &badRequestError)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(badRequestError.Error).To(Equal("entity type guest is invalid"))
+		})
+
+		It("should return 500 Internal Server Error when an unknown service error occurs", func() {
+			testAPI.AuditServiceFactory = func(ctx context.Context) interfaces.AuditServiceProvider {
+				mockAuditService := mock_interfaces.NewMockAuditServiceProvider(ctrl)
+				mockAuditService.EXPECT().ListAuditEntries(gomock.Any()).Return(nil, serviceErrors.NewGeneralServiceError())
+
+				return mockAuditService
+			}
+
+			HitEndpoint(testAPI, "GET", "/api/audit", nil, http.StatusInternalServerError)
+		})
+	})
+})
@@ -0,0 +1,13 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+func getCaptchaSettings(api *API) func(c *gin.Context) {
+	return func(c *gin.Context) {
+		c.JSON(http.StatusOK, api.Captcha)
+	}
+}
@@ -0,0 +1,37 @@
+package api_test
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/rawfish-dev/rsvp-starter/server/api"
+	"github.com/rawfish-dev/rsvp-starter/server/config"
+	"github.com/rawfish-dev/rsvp-starter/server/domain"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Captcha", func() {
+
+	It("should return the provider and site key for the client without logging in", func() {
+		testConfig := config.LoadConfig()
+		testConfig.Captcha.Provider = config.HCaptchaProvider
+		testConfig.Captcha.SiteKey = "some-site-key"
+		testConfig.Captcha.Secret = "some-secret"
+
+		testAPI := api.NewAPI(testConfig)
+		testAPI.InitRoutes()
+
+		respBody := HitEndpoint(testAPI, "GET", "/api/captcha", nil, http.StatusOK)
+
+		var captchaSettings domain.CaptchaSettings
+		Expect(json.Unmarshal(respBody, &captchaSettings)).To(Succeed())
+		Expect(captchaSettings).To(Equal(domain.CaptchaSettings{
+			Provider: config.HCaptchaProvider,
+			SiteKey:  "some-site-key",
+		}))
+
+		Expect(string(respBody)).ToNot(ContainSubstring("some-secret"))
+	})
+})
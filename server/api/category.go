@@ -19,6 +19,7 @@ func createCategory(api *API) func(c *gin.Context) {
 		ctxlogger := logrus.New()
 		ctx := context.Background()
 		ctx = context.WithValue(ctx, "logger", ctxlogger)
+		ctx = contextWithActor(ctx, c)
 
 		categoryService := api.CategoryServiceFactory(ctx)
 
@@ -57,14 +58,31 @@ func listCategories(api *API) func(c *gin.Context) {
 
 		categoryService := api.CategoryServiceFactory(ctx)
 
-		allCategories, err := categoryService.ListCategories()
+		query := &listQuery{c: c}
+		categoryListRequest := domain.CategoryListRequest{
+			ListRequest: query.listRequest(),
+		}
+		if query.err != nil {
+			ctxlogger.Warnf("category api - unable to list categories due to invalid query %v", query.err)
+			c.JSON(domain.NewCustomBadRequestError(query.err.Error()))
+			return
+		}
+
+		categoryList, err := categoryService.ListCategories(&categoryListRequest)
 		if err != nil {
-			ctxlogger.Errorf("category api - unable to list all categories due to %v", err)
+			switch err.(type) {
+			case serviceErrors.ValidationError:
+				ctxlogger.Warnf("category api - unable to list categories due to validation error %v", err)
+				c.JSON(domain.NewCustomBadRequestError(err.Error()))
+				return
+			}
+
+			ctxlogger.Errorf("category api - unable to list categories due to %v", err)
 			c.AbortWithStatus(http.StatusInternalServerError)
 			return
 		}
 
-		c.JSON(http.StatusOK, allCategories)
+		c.JSON(http.StatusOK, categoryList)
 		return
 	}
 }
@@ -74,6 +92,7 @@ func updateCategory(api *API) func(c *gin.Context) {
 		ctxlogger := logrus.New()
 		ctx := context.Background()
 		ctx = context.WithValue(ctx, "logger", ctxlogger)
+		ctx = contextWithActor(ctx, c)
 
 		categoryService := api.CategoryServiceFactory(ctx)
 
@@ -115,6 +134,7 @@ func deleteCategory(api *API) func(c *gin.Context) {
 		ctxlogger := logrus.New()
 		ctx := context.Background()
 		ctx = context.WithValue(ctx, "logger", ctxlogger)
+		ctx = contextWithActor(ctx, c)
 
 		categoryService := api.CategoryServiceFactory(ctx)
 
@@ -142,3 +162,42 @@ func deleteCategory(api *API) func(c *gin.Context) {
 		return
 	}
 }
+
+func restoreCategory(api *API) func(c *gin.Context) {
+	return func(c *gin.Context) {
+		ctxlogger := logrus.New()
+		ctx := context.Background()
+		ctx = context.WithValue(ctx, "logger", ctxlogger)
+		ctx = contextWithActor(ctx, c)
+
+		categoryService := api.CategoryServiceFactory(ctx)
+
+		categoryIDStr := c.Param("id")
+		categoryID, err := strconv.ParseInt(categoryIDStr, 10, 64)
+		if err != nil {
+			ctxlogger.Warnf("category api - unable to restore category as params id %v could not be converted due to %v", c.Param("id"), err)
+			c.AbortWithStatus(http.StatusBadRequest)
+			return
+		}
+
+		restoredCategory, err := categoryService.RestoreCategoryByID(categoryID)
+		if err != nil {
+			switch err.(type) {
+			case category.CategoryNotFoundError:
+				c.AbortWithStatus(http.StatusNotFound)
+				return
+			case serviceErrors.ValidationError:
+				ctxlogger.Warnf("category api - unable to restore category due to validation error %v", err)
+				c.JSON(domain.NewCustomBadRequestError(err.Error()))
+				return
+			}
+
+			ctxlogger.Errorf("category api - unable to restore category due to %v", err)
+			c.AbortWithStatus(http.StatusInternalServerError)
+			return
+		}
+
+		c.JSON(http.StatusOK, restoredCategory)
+		return
+	}
+}
@@ -33,6 +33,8 @@ var _ = Describe("Category", func() {
 		testAPI.SessionServiceFactory = func(ctx context.Context) interfaces.SessionServiceProvider {
 			mockSessionService := mock_interfaces.NewMockSessionServiceProvider(ctrl)
 			mockSessionService.EXPECT().IsSessionValid("").Return(true, nil)
+			mockSessionService.EXPECT().Username("").Return("admin", nil)
+			mockSessionService.EXPECT().Role("").Return(domain.RoleOwner, nil)
 
 			return mockSessionService
 		}
@@ -153,27 +155,33 @@ var _ = Describe("Category", func() {
 				},
 			}
 
+			categoryList := &domain.CategoryList{
+				ListResponse: domain.ListResponse{Page: 1, PageSize: 200, Total: len(categories)},
+				Categories:   categories,
+			}
+
 			testAPI.CategoryServiceFactory = func(ctx context.Context) interfaces.CategoryServiceProvider {
 				mockCategoryService := mock_interfaces.NewMockCategoryServiceProvider(ctrl)
-				mockCategoryService.EXPECT().ListCategories().
-					Return(categories, nil)
+				mockCategoryService.EXPECT().ListCategories(&domain.CategoryListRequest{
+					ListRequest: domain.ListRequest{PageSize: 200, Sort: "tag"},
+				}).Return(categoryList, nil)
 
 				return mockCategoryService
 			}
 
-			responseBytes := HitEndpoint(testAPI, "GET", "/api/categories", nil, http.StatusOK)
+			responseBytes := HitEndpoint(testAPI, "GET", "/api/categories?pageSize=200&sort=tag", nil, http.StatusOK)
 
-			var categoryList []domain.Category
-			err := json.Unmarshal(responseBytes, &categoryList)
+			var returnedCategoryList domain.CategoryList
+			err := json.Unmarshal(responseBytes, &returnedCategoryList)
 			Expect(err).ToNot(HaveOccurred())
 
-			Expect(categoryList).To(Equal(categories))
+			Expect(returnedCategoryList).To(Equal(*categoryList))
 		})
 
 		It("should return 500 Internal Server Error when an unknown service error occurs", func() {
 			testAPI.CategoryServiceFactory = func(ctx context.Context) interfaces.CategoryServiceProvider {
 				mockCategoryService := mock_interfaces.NewMockCategoryServiceProvider(ctrl)
-				mockCategoryService.EXPECT().ListCategories().
+				mockCategoryService.EXPECT().ListCategories(gomock.Any()).
 					Return(nil, serviceErrors.NewGeneralServiceError())
 
 				return mockCategoryService
@@ -335,4 +343,68 @@ var _ = Describe("Category", func() {
 			HitEndpoint(testAPI, "DELETE", "/api/categories/1", nil, http.StatusInternalServerError)
 		})
 	})
+
+	Context("restoring", func() {
+
+		It("should return 200 OK and the restored category given a valid id", func() {
+			restoredCategory := &domain.Category{ID: 1, Tag: "family"}
+
+			testAPI.CategoryServiceFactory = func(ctx context.Context) interfaces.CategoryServiceProvider {
+				mockCategoryService := mock_interfaces.NewMockCategoryServiceProvider(ctrl)
+				mockCategoryService.EXPECT().RestoreCategoryByID(int64(1)).Return(restoredCategory, nil)
+
+				return mockCategoryService
+			}
+
+			responseBytes := HitEndpoint(testAPI, "POST", "/api/categories/1/restore", nil, http.StatusOK)
+
+			var returnedCategory domain.Category
+			err := json.Unmarshal(responseBytes, &returnedCategory)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(returnedCategory).To(Equal(*restoredCategory))
+		})
+
+		It("should return 400 Bad Request if the id is not valid", func() {
+			testAPI.CategoryServiceFactory = func(ctx context.Context) interfaces.CategoryServiceProvider {
+				return mock_interfaces.NewMockCategoryServiceProvider(ctrl)
+			}
+
+			HitEndpoint(testAPI, "POST", "/api/categories/abc/restore", nil, http.StatusBadRequest)
+		})
+
+		It("should return 400 Bad Request when a validation error occurs", func() {
+			testAPI.CategoryServiceFactory = func(ctx context.Context) interfaces.CategoryServiceProvider {
+				mockCategoryService := mock_interfaces.NewMockCategoryServiceProvider(ctrl)
+				mockCategoryService.EXPECT().RestoreCategoryByID(int64(1)).Return(nil, serviceErrors.NewValidationError([]string{"category tag already exists"}))
+
+				return mockCategoryService
+			}
+
+			responseBytes := HitEndpoint(testAPI, "POST", "/api/categories/1/restore", nil, http.StatusBadRequest)
+			Expect(string(responseBytes)).To(ContainSubstring("category tag already exists"))
+		})
+
+		It("should return 404 Not Found if the id is not in the trash", func() {
+			testAPI.CategoryServiceFactory = func(ctx context.Context) interfaces.CategoryServiceProvider {
+				mockCategoryService := mock_interfaces.NewMockCategoryServiceProvider(ctrl)
+				mockCategoryService.EXPECT().RestoreCategoryByID(int64(1)).Return(nil, NewCategoryNotFoundError())
+
+				return mockCategoryService
+			}
+
+			HitEndpoint(testAPI, "POST", "/api/categories/1/restore", nil, http.StatusNotFound)
+		})
+
+		It("should return 500 Internal Server Error when an unknown service error occurs", func() {
+			testAPI.CategoryServiceFactory = func(ctx context.Context) interfaces.CategoryServiceProvider {
+				mockCategoryService := mock_interfaces.NewMockCategoryServiceProvider(ctrl)
+				mockCategoryService.EXPECT().RestoreCategoryByID(int64(1)).Return(nil, serviceErrors.NewGeneralServiceError())
+
+				return mockCategoryService
+			}
+
+			HitEndpoint(testAPI, "POST", "/api/categories/1/restore", nil, http.StatusInternalServerError)
+		})
+	})
 })
@@ -19,6 +19,7 @@ func createInvitation(api *API) func(c *gin.Context) {
 		ctxlogger := logrus.New()
 		ctx := context.Background()
 		ctx = context.WithValue(ctx, "logger", ctxlogger)
+		ctx = contextWithActor(ctx, c)
 
 		invitationService := api.InvitationServiceFactory(ctx)
 
@@ -55,24 +56,36 @@ func listInvitations(api *API) func(c *gin.Context) {
 		ctx := context.Background()
 		ctx = context.WithValue(ctx, "logger", ctxlogger)
 
-		rsvpService := api.RSVPServiceFactory(ctx)
 		invitationService := api.InvitationServiceFactory(ctx)
 
-		allRSVPs, err := rsvpService.ListRSVPs()
-		if err != nil {
-			ctxlogger.Errorf("invitation api - unable to retrieve all rsvps due to %v", err)
-			c.AbortWithStatus(http.StatusInternalServerError)
+		query := &listQuery{c: c}
+		invitationListRequest := domain.InvitationListRequest{
+			ListRequest:  query.listRequest(),
+			CategoryID:   query.int64("categoryID"),
+			Status:       domain.RSVPStatus(c.Query("status")),
+			UpdatedSince: query.time("updatedSince"),
+		}
+		if query.err != nil {
+			ctxlogger.Warnf("invitation api - unable to list invitations due to invalid query %v", query.err)
+			c.JSON(domain.NewCustomBadRequestError(query.err.Error()))
 			return
 		}
 
-		allInvitations, err := invitationService.ListInvitations(allRSVPs)
+		invitationList, err := invitationService.ListInvitations(&invitationListRequest)
 		if err != nil {
-			ctxlogger.Errorf("invitation api - unable to list all invitations due to %v", err)
+			switch err.(type) {
+			case serviceErrors.ValidationError:
+				ctxlogger.Warnf("invitation api - unable to list invitations due to validation error %v", err)
+				c.JSON(domain.NewCustomBadRequestError(err.Error()))
+				return
+			}
+
+			ctxlogger.Errorf("invitation api - unable to list invitations due to %v", err)
 			c.AbortWithStatus(http.StatusInternalServerError)
 			return
 		}
 
-		c.JSON(http.StatusOK, allInvitations)
+		c.JSON(http.StatusOK, invitationList)
 		return
 	}
 }
@@ -82,6 +95,7 @@ func updateInvitation(api *API) func(c *gin.Context) {
 		ctxlogger := logrus.New()
 		ctx := context.Background()
 		ctx = context.WithValue(ctx, "logger", ctxlogger)
+		ctx = contextWithActor(ctx, c)
 
 		invitationService := api.InvitationServiceFactory(ctx)
 
@@ -101,11 +115,19 @@ func updateInvitation(api *API) func(c *gin.Context) {
 
 		updatedInvitation, err := invitationService.UpdateInvitation(&invitationUpdateRequest)
 		if err != nil {
-			switch err.(type) {
+			switch err := err.(type) {
 			case serviceErrors.ValidationError:
 				ctxlogger.Errorf("invitation api - unable to update invitation due to validation error %v", err)
 				c.JSON(domain.NewCustomBadRequestError(err.Error()))
 				return
+			case invitation.InvitationNotFoundError:
+				c.AbortWithStatus(http.StatusNotFound)
+				return
+			case invitation.InvitationVersionConflictError:
+				// Send back the latest invitation so the control panel can refresh the stale copy
+				ctxlogger.Warnf("invitation api - unable to update invitation %v due to %v", invitationUpdateRequest.ID, err)
+				c.JSON(http.StatusConflict, err.Current)
+				return
 			}
 
 			ctxlogger.Errorf("invitation api - unable to update invitation due to %v", err)
@@ -123,6 +145,7 @@ func deleteInvitation(api *API) func(c *gin.Context) {
 		ctxlogger := logrus.New()
 		ctx := context.Background()
 		ctx = context.WithValue(ctx, "logger", ctxlogger)
+		ctx = contextWithActor(ctx, c)
 
 		invitationService := api.InvitationServiceFactory(ctx)
 
@@ -150,3 +173,42 @@ func deleteInvitation(api *API) func(c *gin.Context) {
 		return
 	}
 }
+
+func restoreInvitation(api *API) func(c *gin.Context) {
+	return func(c *gin.Context) {
+		ctxlogger := logrus.New()
+		ctx := context.Background()
+		ctx = context.WithValue(ctx, "logger", ctxlogger)
+		ctx = contextWithActor(ctx, c)
+
+		invitationService := api.InvitationServiceFactory(ctx)
+
+		invitationIDStr := c.Param("id")
+		invitationID, err := strconv.ParseInt(invitationIDStr, 10, 64)
+		if err != nil {
+			ctxlogger.Warnf("invitation api - unable to restore invitation as params id %v could not be converted due to %v", c.Param("id"), err)
+			c.AbortWithStatus(http.StatusBadRequest)
+			return
+		}
+
+		restoredInvitation, err := invitationService.RestoreInvitationByID(invitationID)
+		if err != nil {
+			switch err.(type) {
+			case invitation.InvitationNotFoundError:
+				c.AbortWithStatus(http.StatusNotFound)
+				return
+			case serviceErrors.ValidationError:
+				ctxlogger.Warnf("invitation api - unable to restore invitation due to validation error %v", err)
+				c.JSON(domain.NewCustomBadRequestError(err.Error()))
+				return
+			}
+
+			ctxlogger.Errorf("invitation api - unable to restore invitation due to %v", err)
+			c.AbortWithStatus(http.StatusInternalServerError)
+			return
+		}
+
+		c.JSON(http.StatusOK, restoredInvitation)
+		return
+	}
+}
@@ -33,6 +33,8 @@ var _ = Describe("Invitation", func() {
 		testAPI.SessionServiceFactory = func(ctx context.Context) interfaces.SessionServiceProvider {
 			mockSessionService := mock_interfaces.NewMockSessionServiceProvider(ctrl)
 			mockSessionService.EXPECT().IsSessionValid("").Return(true, nil)
+			mockSessionService.EXPECT().Username("").Return("admin", nil)
+			mockSessionService.EXPECT().Role("").Return(domain.RoleOwner, nil)
 
 			return mockSessionService
 		}
@@ -190,62 +192,68 @@ var _ = Describe("Invitation", func() {
 				},
 			}
 
-			testAPI.RSVPServiceFactory = func(ctx context.Context) interfaces.RSVPServiceProvider {
-				mockRSVPService := mock_interfaces.NewMockRSVPServiceProvider(ctrl)
-				mockRSVPService.EXPECT().ListRSVPs().
-					Return(nil, nil)
-
-				return mockRSVPService
+			invitationList := &domain.InvitationList{
+				ListResponse: domain.ListResponse{Page: 2, PageSize: 3, Total: 6},
+				Invitations:  invitations,
 			}
 
 			testAPI.InvitationServiceFactory = func(ctx context.Context) interfaces.InvitationServiceProvider {
 				mockInvitationService := mock_interfaces.NewMockInvitationServiceProvider(ctrl)
-				mockInvitationService.EXPECT().ListInvitations(nil).
-					Return(invitations, nil)
+				mockInvitationService.EXPECT().ListInvitations(&domain.InvitationListRequest{
+					ListRequest: domain.ListRequest{Page: 2, PageSize: 3, Sort: "-greeting"},
+					CategoryID:  1,
+					Status:      domain.NotSent,
+				}).Return(invitationList, nil)
 
 				return mockInvitationService
 			}
 
-			responseBytes := HitEndpoint(testAPI, "GET", "/api/invitations", nil, http.StatusOK)
+			responseBytes := HitEndpoint(testAPI, "GET", "/api/invitations?page=2&pageSize=3&sort=-greeting&categoryID=1&status=NS", nil, http.StatusOK)
 
-			var invitationList []domain.Invitation
-			err := json.Unmarshal(responseBytes, &invitationList)
+			var returnedInvitationList domain.InvitationList
+			err := json.Unmarshal(responseBytes, &returnedInvitationList)
 			Expect(err).ToNot(HaveOccurred())
 
-			Expect(invitationList).To(Equal(invitations))
+			Expect(returnedInvitationList).To(Equal(*invitationList))
 		})
 
-		It("should return 500 Internal Server Error when an unknown rsvp service error occurs", func() {
-			testAPI.RSVPServiceFactory = func(ctx context.Context) interfaces.RSVPServiceProvider {
-				mockRSVPService := mock_interfaces.NewMockRSVPServiceProvider(ctrl)
-				mockRSVPService.EXPECT().ListRSVPs().
-					Return(nil, serviceErrors.NewGeneralServiceError())
+		It("should return 400 Bad Request when a query parameter is malformed", func() {
+			testAPI.InvitationServiceFactory = func(ctx context.Context) interfaces.InvitationServiceProvider {
+				mockInvitationService := mock_interfaces.NewMockInvitationServiceProvider(ctrl)
+				mockInvitationService.EXPECT().ListInvitations(gomock.Any()).Times(0)
 
-				return mockRSVPService
+				return mockInvitationService
 			}
 
+			responseBytes := HitEndpoint(testAPI, "GET", "/api/invitations?updatedSince=yesterday", nil, http.StatusBadRequest)
+
+			var badRequestError domain.CustomBadRequestError
+			err := json.Unmarshal(responseBytes, &badRequestError)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(badRequestError.Error).To(Equal("updatedSince must be an RFC 3339 timestamp"))
+		})
+
+		It("should return 400 Bad Request when a validation error occurs", func() {
 			testAPI.InvitationServiceFactory = func(ctx context.Context) interfaces.InvitationServiceProvider {
 				mockInvitationService := mock_interfaces.NewMockInvitationServiceProvider(ctrl)
-				mockInvitationService.EXPECT().ListInvitations(nil).Times(0)
+				mockInvitationService.EXPECT().ListInvitations(gomock.Any()).
+					Return(nil, serviceErrors.NewValidationError([]string{"sort field notes is invalid"}))
 
 				return mockInvitationService
 			}
 
-			HitEndpoint(testAPI, "GET", "/api/invitations", nil, http.StatusInternalServerError)
+			responseBytes := HitEndpoint(testAPI, "GET", "/api/invitations?sort=notes", nil, http.StatusBadRequest)
+
+			var badRequestError domain.CustomBadRequestError
+			err := json.Unmarshal(responseBytes, &badRequestError)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(badRequestError.Error).To(Equal("sort field notes is invalid"))
 		})
 
 		It("should return 500 Internal Server Error when an unknown invitation service error occurs", func() {
-			testAPI.RSVPServiceFactory = func(ctx context.Context) interfaces.RSVPServiceProvider {
-				mockRSVPService := mock_interfaces.NewMockRSVPServiceProvider(ctrl)
-				mockRSVPService.EXPECT().ListRSVPs().
-					Return(nil, nil)
-
-				return mockRSVPService
-			}
-
 			testAPI.InvitationServiceFactory = func(ctx context.Context) interfaces.InvitationServiceProvider {
 				mockInvitationService := mock_interfaces.NewMockInvitationServiceProvider(ctrl)
-				mockInvitationService.EXPECT().ListInvitations(nil).
+				mockInvitationService.EXPECT().ListInvitations(gomock.Any()).
 					Return(nil, serviceErrors.NewGeneralServiceError())
 
 				return mockInvitationService
@@ -268,8 +276,9 @@ var _ = Describe("Invitation", func() {
 					Notes:             "some notes 1",
 					MobilePhoneNumber: "91234123 1",
 				},
-				ID:     1,
-				Status: domain.Sent,
+				ID:      1,
+				Status:  domain.Sent,
+				Version: 1,
 			}
 		})
 
@@ -354,6 +363,34 @@ var _ = Describe("Invitation", func() {
 			Expect(badRequestError.Error).To(Equal("some validation error"))
 		})
 
+		It("should return 409 Conflict and the current invitation when the version is stale", func() {
+			currentInvitation := &domain.Invitation{
+				BaseInvitation: updateInvitationReq.BaseInvitation,
+				ID:             updateInvitationReq.ID,
+				PrivateID:      "some-private-id",
+				Status:         domain.Sent,
+				Version:        2,
+			}
+
+			testAPI.InvitationServiceFactory = func(ctx context.Context) interfaces.InvitationServiceProvider {
+				mockInvitationService := mock_interfaces.NewMockInvitationServiceProvider(ctrl)
+				mockInvitationService.EXPECT().UpdateInvitation(&updateInvitationReq).
+					Return(nil, NewInvitationVersionConflictError(currentInvitation))
+
+				return mockInvitationService
+			}
+
+			reqBytes, err := json.Marshal(updateInvitationReq)
+			Expect(err).ToNot(HaveOccurred())
+
+			responseBytes := HitEndpoint(testAPI, "PUT", "/api/invitations/1", bytes.NewBuffer(reqBytes), http.StatusConflict)
+
+			var invitation domain.Invitation
+			err = json.Unmarshal(responseBytes, &invitation)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(invitation).To(Equal(*currentInvitation))
+		})
+
 		It("should return 500 Internal Server Error when an unknown service error occurs", func() {
 			testAPI.InvitationServiceFactory = func(ctx context.Context) interfaces.InvitationServiceProvider {
 				mockInvitationService := mock_interfaces.NewMockInvitationServiceProvider(ctrl)
@@ -418,4 +455,243 @@ var _ = Describe("Invitation", func() {
 			HitEndpoint(testAPI, "DELETE", "/api/invitations/1", nil, http.StatusInternalServerError)
 		})
 	})
+
+	Context("restoring", func() {
+
+		It("should return 200 OK and the restored invitation given a valid id", func() {
+			restoredInvitation := &domain.Invitation{ID: 1, PrivateID: "some-private-id", Status: domain.NotSent}
+
+			testAPI.InvitationServiceFactory = func(ctx context.Context) interfaces.InvitationServiceProvider {
+				mockInvitationService := mock_interfaces.NewMockInvitationServiceProvider(ctrl)
+				mockInvitationService.EXPECT().RestoreInvitationByID(int64(1)).Return(restoredInvitation, nil)
+
+				return mockInvitationService
+			}
+
+			responseBytes := HitEndpoint(testAPI, "POST", "/api/invitations/1/restore", nil, http.StatusOK)
+
+			var returnedInvitation domain.Invitation
+			err := json.Unmarshal(responseBytes, &returnedInvitation)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(returnedInvitation).To(Equal(*restoredInvitation))
+		})
+
+		It("should return 400 Bad Request if the id is not valid", func() {
+			testAPI.InvitationServiceFactory = func(ctx context.Context) interfaces.InvitationServiceProvider {
+				return mock_interfaces.NewMockInvitationServiceProvider(ctrl)
+			}
+
+			HitEndpoint(testAPI, "POST", "/api/invitations/abc/restore", nil, http.StatusBadRequest)
+		})
+
+		It("should return 400 Bad Request when a validation error occurs", func() {
+			testAPI.InvitationServiceFactory = func(ctx context.Context) interfaces.InvitationServiceProvider {
+				mockInvitationService := mock_interfaces.NewMockInvitationServiceProvider(ctrl)
+				mockInvitationService.EXPECT().RestoreInvitationByID(int64(1)).Return(nil, serviceErrors.NewValidationError([]string{"category of the invitation must be restored first"}))
+
+				return mockInvitationService
+			}
+
+			responseBytes := HitEndpoint(testAPI, "POST", "/api/invitations/1/restore", nil, http.StatusBadRequest)
+			Expect(string(responseBytes)).To(ContainSubstring("category of the invitation must be restored first"))
+		})
+
+		It("should return 404 Not Found if the id is not in the trash", func() {
+			testAPI.InvitationServiceFactory = func(ctx context.Context) interfaces.InvitationServiceProvider {
+				mockInvitationService := mock_interfaces.NewMockInvitationServiceProvider(ctrl)
+				mockInvitationService.EXPECT().RestoreInvitationByID(int64(1)).Return(nil, NewInvitationNotFoundError())
+
+				return mockInvitationService
+			}
+
+			HitEndpoint(testAPI, "POST", "/api/invitations/1/restore", nil, http.StatusNotFound)
+		})
+
+		It("should return 500 Internal Server Error when an unknown service error occurs", func() {
+			testAPI.InvitationServiceFactory = func(ctx context.Context) interfaces.InvitationServiceProvider {
+				mockInvitationService := mock_interfaces.NewMockInvitationServiceProvider(ctrl)
+				mockInvitationService.EXPECT().RestoreInvitationByID(int64(1)).Return(nil, serviceErrors.NewGeneralServiceError())
+
+				return mockInvitationService
+			}
+
+			HitEndpoint(testAPI, "POST", "/api/invitations/1/restore", nil, http.StatusInternalServerError)
+		})
+	})
+
+	Context("guest links", func() {
+
+		var linkUpdateReq domain.InvitationLinkUpdateRequest
+
+		BeforeEach(func() {
+			linkUpdateReq = domain.InvitationLinkUpdateRequest{
+				ID:                       1,
+				LinkExpiresAt:            "2030-01-02T03:04:05Z",
+				RequirePhoneConfirmation: true,
+				Version:                  1,
+			}
+		})
+
+		It("should return 200 OK and the invitation with its updated link settings", func() {
+			updatedInvitation := &domain.Invitation{ID: 1, LinkExpiresAt: "2030-01-02T03:04:05Z", RequirePhoneConfirmation: true, Version: 2}
+
+			testAPI.InvitationServiceFactory = func(ctx context.Context) interfaces.InvitationServiceProvider {
+				mockInvitationService := mock_interfaces.NewMockInvitationServiceProvider(ctrl)
+				mockInvitationService.EXPECT().UpdateInvitationLink(&linkUpdateReq).Return(updatedInvitation, nil)
+
+				return mockInvitationService
+			}
+
+			reqBytes, err := json.Marshal(linkUpdateReq)
+			Expect(err).ToNot(HaveOccurred())
+
+			responseBytes := HitEndpoint(testAPI, "PUT", "/api/invitations/1/link", bytes.NewBuffer(reqBytes), http.StatusOK)
+
+			var invitation domain.Invitation
+			Expect(json.Unmarshal(responseBytes, &invitation)).To(Succeed())
+			Expect(invitation).To(Equal(*updatedInvitation))
+		})
+
+		It("should return 400 Bad Request if the params id does not match the request id", func() {
+			reqBytes, err := json.Marshal(linkUpdateReq)
+			Expect(err).ToNot(HaveOccurred())
+
+			HitEndpoint(testAPI, "PUT", "/api/invitations/2/link", bytes.NewBuffer(reqBytes), http.StatusBadRequest)
+		})
+
+		It("should return 400 Bad Request when a validation error occurs", func() {
+			testAPI.InvitationServiceFactory = func(ctx context.Context) interfaces.InvitationServiceProvider {
+				mockInvitationService := mock_interfaces.NewMockInvitationServiceProvider(ctrl)
+				mockInvitationService.EXPECT().UpdateInvitationLink(&linkUpdateReq).Return(nil, serviceErrors.NewValidationError([]string{"invitation link expiry must be in RFC 3339"}))
+
+				return mockInvitationService
+			}
+
+			reqBytes, err := json.Marshal(linkUpdateReq)
+			Expect(err).ToNot(HaveOccurred())
+
+			responseBytes := HitEndpoint(testAPI, "PUT", "/api/invitations/1/link", bytes.NewBuffer(reqBytes), http.StatusBadRequest)
+			Expect(string(responseBytes)).To(ContainSubstring("invitation link expiry must be in RFC 3339"))
+		})
+
+		It("should return 200 OK and the invitation with its link revoked", func() {
+			testAPI.InvitationServiceFactory = func(ctx context.Context) interfaces.InvitationServiceProvider {
+				mockInvitationService := mock_interfaces.NewMockInvitationServiceProvider(ctrl)
+				mockInvitationService.EXPECT().RevokeInvitationLinkByID(int64(1)).Return(&domain.Invitation{ID: 1, LinkRevokedAt: "2026-10-17T00:00:00Z"}, nil)
+
+				return mockInvitationService
+			}
+
+			responseBytes := HitEndpoint(testAPI, "POST", "/api/invitations/1/link/revoke", nil, http.StatusOK)
+			Expect(string(responseBytes)).To(ContainSubstring(`"linkRevokedAt":"2026-10-17T00:00:00Z"`))
+		})
+
+		It("should return 200 OK and the invitation with its new private id", func() {
+			testAPI.InvitationServiceFactory = func(ctx context.Context) interfaces.InvitationServiceProvider {
+				mockInvitationService := mock_interfaces.NewMockInvitationServiceProvider(ctrl)
+				mockInvitationService.EXPECT().RotateInvitationLinkByID(int64(1)).Return(&domain.Invitation{ID: 1, PrivateID: "another-private-id"}, nil)
+
+				return mockInvitationService
+			}
+
+			responseBytes := HitEndpoint(testAPI, "POST", "/api/invitations/1/link/rotate", nil, http.StatusOK)
+			Expect(string(responseBytes)).To(ContainSubstring(`"privateID":"another-private-id"`))
+		})
+
+		It("should return 404 Not Found if the invitation cannot be found", func() {
+			testAPI.InvitationServiceFactory = func(ctx context.Context) interfaces.InvitationServiceProvider {
+				mockInvitationService := mock_interfaces.NewMockInvitationServiceProvider(ctrl)
+				mockInvitationService.EXPECT().RotateInvitationLinkByID(int64(1)).Return(nil, NewInvitationNotFoundError())
+
+				return mockInvitationService
+			}
+
+			HitEndpoint(testAPI, "POST", "/api/invitations/1/link/rotate", nil, http.StatusNotFound)
+		})
+	})
+
+	Context("sending", func() {
+
+		It("should return 200 OK and the messages sent for the invitation", func() {
+			sendResult := &domain.InvitationSendResult{InvitationID: 1, Messages: []domain.Message{{ID: 2, InvitationID: 1, Status: domain.MessageSent}}}
+
+			testAPI.InvitationServiceFactory = func(ctx context.Context) interfaces.InvitationServiceProvider {
+				mockInvitationService := mock_interfaces.NewMockInvitationServiceProvider(ctrl)
+				mockInvitationService.EXPECT().SendInvitationByID(int64(1)).Return(sendResult, nil)
+
+				return mockInvitationService
+			}
+
+			responseBytes := HitEndpoint(testAPI, "POST", "/api/invitations/1/send", nil, http.StatusOK)
+
+			var result domain.InvitationSendResult
+			Expect(json.Unmarshal(responseBytes, &result)).To(Succeed())
+			Expect(result).To(Equal(*sendResult))
+		})
+
+		It("should return 502 Bad Gateway and the failed messages when the providers reject them", func() {
+			failedMessages := []domain.Message{{ID: 2, InvitationID: 1, Status: domain.MessageFailed, Error: "invalid number"}}
+
+			testAPI.InvitationServiceFactory = func(ctx context.Context) interfaces.InvitationServiceProvider {
+				mockInvitationService := mock_interfaces.NewMockInvitationServiceProvider(ctrl)
+				mockInvitationService.EXPECT().SendInvitationByID(int64(1)).Return(nil, NewInvitationSendFailedError(failedMessages, "invalid number"))
+
+				return mockInvitationService
+			}
+
+			responseBytes := HitEndpoint(testAPI, "POST", "/api/invitations/1/send", nil, http.StatusBadGateway)
+
+			var result domain.InvitationSendResult
+			Expect(json.Unmarshal(responseBytes, &result)).To(Succeed())
+			Expect(result.Messages).To(Equal(failedMessages))
+			Expect(result.Error).To(ContainSubstring("invalid number"))
+		})
+
+		It("should return 400 Bad Request when the invitation has no mobile phone number or email", func() {
+			testAPI.InvitationServiceFactory = func(ctx context.Context) interfaces.InvitationServiceProvider {
+				mockInvitationService := mock_interfaces.NewMockInvitationServiceProvider(ctrl)
+				mockInvitationService.EXPECT().SendInvitationByID(int64(1)).Return(nil, serviceErrors.NewValidationError([]string{"invitation has no mobile phone number or email to send to"}))
+
+				return mockInvitationService
+			}
+
+			responseBytes := HitEndpoint(testAPI, "POST", "/api/invitations/1/send", nil, http.StatusBadRequest)
+			Expect(string(responseBytes)).To(ContainSubstring("invitation has no mobile phone number"))
+		})
+
+		It("should return 200 OK and the results of sending every invitation in the category", func() {
+			bulkSendResult := &domain.InvitationBulkSendResult{Sent: 1, Results: []domain.InvitationSendResult{{InvitationID: 1}}}
+
+			testAPI.InvitationServiceFactory = func(ctx context.Context) interfaces.InvitationServiceProvider {
+				mockInvitationService := mock_interfaces.NewMockInvitationServiceProvider(ctrl)
+				mockInvitationService.EXPECT().SendInvitations(&domain.InvitationBulkSendRequest{CategoryID: 3, Resend: true}).Return(bulkSendResult, nil)
+
+				return mockInvitationService
+			}
+
+			responseBytes := HitEndpoint(testAPI, "POST", "/api/categories/3/send?resend=true", nil, http.StatusOK)
+
+			var result domain.InvitationBulkSendResult
+			Expect(json.Unmarshal(responseBytes, &result)).To(Succeed())
+			Expect(result).To(Equal(*bulkSendResult))
+		})
+
+		It("should return 200 OK and the messages sent for the invitation", func() {
+			messages := []domain.Message{{ID: 1, InvitationID: 1, Status: domain.MessageFailed}, {ID: 2, InvitationID: 1, Status: domain.MessageSent}}
+
+			testAPI.InvitationServiceFactory = func(ctx context.Context) interfaces.InvitationServiceProvider {
+				mockInvitationService := mock_interfaces.NewMockInvitationServiceProvider(ctrl)
+				mockInvitationService.EXPECT().ListInvitationMessages(int64(1)).Return(messages, nil)
+
+				return mockInvitationService
+			}
+
+			responseBytes := HitEndpoint(testAPI, "GET", "/api/invitations/1/messages", nil, http.StatusOK)
+
+			var result []domain.Message
+			Expect(json.Unmarshal(responseBytes, &result)).To(Succeed())
+			Expect(result).To(Equal(messages))
+		})
+	})
 })
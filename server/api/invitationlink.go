@@ -0,0 +1,179 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/rawfish-dev/rsvp-starter/server/domain"
+	"github.com/rawfish-dev/rsvp-starter/server/interfaces"
+	serviceErrors "github.com/rawfish-dev/rsvp-starter/server/services/errors"
+	"github.com/rawfish-dev/rsvp-starter/server/services/invitation"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/gin-gonic/gin"
+	"golang.org/x/net/context"
+)
+
+// guestPhoneNumberHeader carries the mobile phone number guests confirm for links that ask for it
+const guestPhoneNumberHeader = "X-Guest-Phone-Number"
+
+func updateInvitationLink(api *API) func(c *gin.Context) {
+	return func(c *gin.Context) {
+		ctxlogger := logrus.New()
+		ctx := context.Background()
+		ctx = context.WithValue(ctx, "logger", ctxlogger)
+		ctx = contextWithActor(ctx, c)
+
+		invitationService := api.InvitationServiceFactory(ctx)
+
+		var linkUpdateRequest domain.InvitationLinkUpdateRequest
+		err := c.BindJSON(&linkUpdateRequest)
+		if err != nil {
+			ctxlogger.Errorf("invitation api - unable to update invitation link while unwrapping request due to %v", err)
+			c.JSON(domain.NewInvalidJSONBodyError())
+			return
+		}
+
+		if c.Param("id") != fmt.Sprintf("%v", linkUpdateRequest.ID) {
+			ctxlogger.Warnf("invitation api - unable to update invitation link as params id %v don't match request id %v", c.Param("id"), linkUpdateRequest.ID)
+			c.AbortWithStatus(http.StatusBadRequest)
+			return
+		}
+
+		updatedInvitation, err := invitationService.UpdateInvitationLink(&linkUpdateRequest)
+		if err != nil {
+			switch err := err.(type) {
+			case serviceErrors.ValidationError:
+				ctxlogger.Warnf("invitation api - unable to update invitation link due to validation error %v", err)
+				c.JSON(domain.NewCustomBadRequestError(err.Error()))
+				return
+			case invitation.InvitationNotFoundError:
+				c.AbortWithStatus(http.StatusNotFound)
+				return
+			case invitation.InvitationVersionConflictError:
+				ctxlogger.Warnf("invitation api - unable to update invitation link %v due to %v", linkUpdateRequest.ID, err)
+				c.JSON(http.StatusConflict, err.Current)
+				return
+			}
+
+			ctxlogger.Errorf("invitation api - unable to update invitation link due to %v", err)
+			c.AbortWithStatus(http.StatusInternalServerError)
+			return
+		}
+
+		c.JSON(http.StatusOK, updatedInvitation)
+		return
+	}
+}
+
+func revokeInvitationLink(api *API) func(c *gin.Context) {
+	return changeInvitationLink(api, "revoke", func(invitationService interfaces.InvitationServiceProvider, invitationID int64) (*domain.Invitation, error) {
+		return invitationService.RevokeInvitationLinkByID(invitationID)
+	})
+}
+
+func rotateInvitationLink(api *API) func(c *gin.Context) {
+	return changeInvitationLink(api, "rotate", func(invitationService interfaces.InvitationServiceProvider, invitationID int64) (*domain.Invitation, error) {
+		return invitationService.RotateInvitationLinkByID(invitationID)
+	})
+}
+
+// changeInvitationLink handles the link actions that only need the id of the invitation
+func changeInvitationLink(api *API, action string, change func(interfaces.InvitationServiceProvider, int64) (*domain.Invitation, error)) func(c *gin.Context) {
+	return func(c *gin.Context) {
+		ctxlogger := logrus.New()
+		ctx := context.Background()
+		ctx = context.WithValue(ctx, "logger", ctxlogger)
+		ctx = contextWithActor(ctx, c)
+
+		invitationService := api.InvitationServiceFactory(ctx)
+
+		invitationIDStr := c.Param("id")
+		invitationID, err := strconv.ParseInt(invitationIDStr, 10, 64)
+		if err != nil {
+			ctxlogger.Warnf("invitation api - unable to %v invitation link as params id %v could not be converted due to %v", action, c.Param("id"), err)
+			c.AbortWithStatus(http.StatusBadRequest)
+			return
+		}
+
+		changedInvitation, err := change(invitationService, invitationID)
+		if err != nil {
+			switch err.(type) {
+			case invitation.InvitationNotFoundError:
+				c.AbortWithStatus(http.StatusNotFound)
+				return
+			}
+
+			ctxlogger.Errorf("invitation api - unable to %v invitation link due to %v", action, err)
+			c.AbortWithStatus(http.StatusInternalServerError)
+			return
+		}
+
+		c.JSON(http.StatusOK, changedInvitation)
+		return
+	}
+}
+
+// authorizeGuest responds for the guest link when it cannot be used and returns false. Guests giving
+// the wrong mobile phone number count as failed attempts against the private id, which are kept apart
+// from admin logins.
+func authorizeGuest(api *API, ctx context.Context, c *gin.Context, ctxlogger interfaces.Logger, privateID string) (*domain.Invitation, bool) {
+	invitationService := api.InvitationServiceFactory(ctx)
+	guestThrottle := api.GuestThrottleFactory(ctx)
+
+	mobilePhoneNumber := c.Request.Header.Get(guestPhoneNumberHeader)
+	throttleKey := domain.GuestActor + ":" + privateID
+	clientIP := requestClientIP(api, c)
+
+	if mobilePhoneNumber != "" && isLoginThrottled(c, ctxlogger, guestThrottle, throttleKey, clientIP) {
+		return nil, false
+	}
+
+	guestInvitation, err := invitationService.AuthorizeGuest(privateID, mobilePhoneNumber)
+	if err != nil {
+		switch err.(type) {
+		case invitation.InvitationPhoneConfirmationRequiredError:
+			if mobilePhoneNumber != "" {
+				ctxlogger.Warnf("rsvp api - unable to authorize guest of %v due to an incorrect mobile phone number", privateID)
+
+				err = guestThrottle.RecordFailure(throttleKey, clientIP)
+				if err != nil {
+					ctxlogger.Errorf("rsvp api - unable to record failed phone confirmation due to %v", err)
+				}
+			}
+
+			c.JSON(http.StatusForbidden, domain.PhoneConfirmationRequired{PhoneConfirmationRequired: true})
+			return nil, false
+		}
+
+		if mobilePhoneNumber != "" {
+			releaseLoginAttempt(ctxlogger, guestThrottle, throttleKey, clientIP)
+		}
+
+		switch err.(type) {
+		case invitation.InvitationNotFoundError:
+			c.AbortWithStatus(http.StatusNotFound)
+			return nil, false
+		case invitation.InvitationLinkGoneError:
+			c.AbortWithStatus(http.StatusGone)
+			return nil, false
+		}
+
+		ctxlogger.Errorf("rsvp api - unable to authorize guest due to %v", err)
+		c.AbortWithStatus(http.StatusInternalServerError)
+		return nil, false
+	}
+
+	switch {
+	case mobilePhoneNumber != "" && guestInvitation.RequirePhoneConfirmation:
+		err = guestThrottle.RecordSuccess(throttleKey, clientIP)
+		if err != nil {
+			ctxlogger.Errorf("rsvp api - unable to clear failed phone confirmations due to %v", err)
+		}
+	case mobilePhoneNumber != "":
+		releaseLoginAttempt(ctxlogger, guestThrottle, throttleKey, clientIP)
+	}
+
+	return guestInvitation, true
+}
@@ -0,0 +1,141 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/rawfish-dev/rsvp-starter/server/domain"
+	serviceErrors "github.com/rawfish-dev/rsvp-starter/server/services/errors"
+	"github.com/rawfish-dev/rsvp-starter/server/services/invitation"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/gin-gonic/gin"
+	"golang.org/x/net/context"
+)
+
+func sendInvitation(api *API) func(c *gin.Context) {
+	return func(c *gin.Context) {
+		ctxlogger := logrus.New()
+		ctx := context.Background()
+		ctx = context.WithValue(ctx, "logger", ctxlogger)
+		ctx = contextWithActor(ctx, c)
+
+		invitationService := api.InvitationServiceFactory(ctx)
+
+		invitationIDStr := c.Param("id")
+		invitationID, err := strconv.ParseInt(invitationIDStr, 10, 64)
+		if err != nil {
+			ctxlogger.Warnf("invitation api - unable to send invitation as params id %v could not be converted due to %v", c.Param("id"), err)
+			c.AbortWithStatus(http.StatusBadRequest)
+			return
+		}
+
+		sendResult, err := invitationService.SendInvitationByID(invitationID)
+		if err != nil {
+			switch err := err.(type) {
+			case serviceErrors.ValidationError:
+				ctxlogger.Warnf("invitation api - unable to send invitation due to validation error %v", err)
+				c.JSON(domain.NewCustomBadRequestError(err.Error()))
+				return
+			case invitation.InvitationNotFoundError:
+				c.AbortWithStatus(http.StatusNotFound)
+				return
+			case invitation.InvitationSendFailedError:
+				// Send back the failed messages so the control panel can show why the providers rejected them
+				ctxlogger.Warnf("invitation api - unable to send invitation %v due to %v", invitationID, err)
+				c.JSON(http.StatusBadGateway, domain.InvitationSendResult{InvitationID: invitationID, Messages: err.Messages, Error: err.Error()})
+				return
+			}
+
+			ctxlogger.Errorf("invitation api - unable to send invitation due to %v", err)
+			c.AbortWithStatus(http.StatusInternalServerError)
+			return
+		}
+
+		c.JSON(http.StatusOK, sendResult)
+		return
+	}
+}
+
+func sendCategoryInvitations(api *API) func(c *gin.Context) {
+	return func(c *gin.Context) {
+		ctxlogger := logrus.New()
+		ctx := context.Background()
+		ctx = context.WithValue(ctx, "logger", ctxlogger)
+		ctx = contextWithActor(ctx, c)
+
+		invitationService := api.InvitationServiceFactory(ctx)
+
+		categoryIDStr := c.Param("id")
+		categoryID, err := strconv.ParseInt(categoryIDStr, 10, 64)
+		if err != nil {
+			ctxlogger.Warnf("invitation api - unable to send invitations as params id %v could not be converted due to %v", c.Param("id"), err)
+			c.AbortWithStatus(http.StatusBadRequest)
+			return
+		}
+
+		query := &listQuery{c: c}
+		resend := query.bool("resend")
+		if query.err != nil {
+			ctxlogger.Warnf("invitation api - unable to send invitations due to invalid query %v", query.err)
+			c.JSON(domain.NewCustomBadRequestError(query.err.Error()))
+			return
+		}
+
+		bulkSendRequest := domain.InvitationBulkSendRequest{
+			CategoryID: categoryID,
+			Resend:     resend != nil && *resend,
+		}
+
+		bulkSendResult, err := invitationService.SendInvitations(&bulkSendRequest)
+		if err != nil {
+			switch err.(type) {
+			case serviceErrors.ValidationError:
+				ctxlogger.Warnf("invitation api - unable to send invitations due to validation error %v", err)
+				c.JSON(domain.NewCustomBadRequestError(err.Error()))
+				return
+			}
+
+			ctxlogger.Errorf("invitation api - unable to send invitations of category %v due to %v", categoryID, err)
+			c.AbortWithStatus(http.StatusInternalServerError)
+			return
+		}
+
+		c.JSON(http.StatusOK, bulkSendResult)
+		return
+	}
+}
+
+func listInvitationMessages(api *API) func(c *gin.Context) {
+	return func(c *gin.Context) {
+		ctxlogger := logrus.New()
+		ctx := context.Background()
+		ctx = context.WithValue(ctx, "logger", ctxlogger)
+
+		invitationService := api.InvitationServiceFactory(ctx)
+
+		invitationIDStr := c.Param("id")
+		invitationID, err := strconv.ParseInt(invitationIDStr, 10, 64)
+		if err != nil {
+			ctxlogger.Warnf("invitation api - unable to list invitation messages as params id %v could not be converted due to %v", c.Param("id"), err)
+			c.AbortWithStatus(http.StatusBadRequest)
+			return
+		}
+
+		messages, err := invitationService.ListInvitationMessages(invitationID)
+		if err != nil {
+			switch err.(type) {
+			case invitation.InvitationNotFoundError:
+				c.AbortWithStatus(http.StatusNotFound)
+				return
+			}
+
+			ctxlogger.Errorf("invitation api - unable to list messages of invitation %v due to %v", invitationID, err)
+			c.AbortWithStatus(http.StatusInternalServerError)
+			return
+		}
+
+		c.JSON(http.StatusOK, messages)
+		return
+	}
+}
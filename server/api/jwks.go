@@ -0,0 +1,30 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/gin-gonic/gin"
+	"golang.org/x/net/context"
+)
+
+// getJWKS publishes the public keys tokens are signed with so other services can verify them
+func getJWKS(api *API) func(c *gin.Context) {
+	return func(c *gin.Context) {
+		ctxlogger := logrus.New()
+		ctx := context.Background()
+		ctx = context.WithValue(ctx, "logger", ctxlogger)
+
+		jwtService := api.JWTServiceFactory(ctx)
+
+		keySet, err := jwtService.PublicKeys()
+		if err != nil {
+			ctxlogger.Errorf("jwks api - unable to list public keys due to %v", err)
+			c.AbortWithStatus(http.StatusInternalServerError)
+			return
+		}
+
+		c.JSON(http.StatusOK, keySet)
+		return
+	}
+}
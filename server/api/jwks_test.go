@@ -0,0 +1,54 @@
+package api_test
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/rawfish-dev/rsvp-starter/server/api"
+	"github.com/rawfish-dev/rsvp-starter/server/config"
+	"github.com/rawfish-dev/rsvp-starter/server/domain"
+	"github.com/rawfish-dev/rsvp-starter/server/interfaces"
+	mock_interfaces "github.com/rawfish-dev/rsvp-starter/server/mock"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"golang.org/x/net/context"
+)
+
+var _ = Describe("JWKS", func() {
+
+	var mockCtrl *gomock.Controller
+	var mockJWTService *mock_interfaces.MockJWTServiceProvider
+	var testAPI *api.API
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		mockJWTService = mock_interfaces.NewMockJWTServiceProvider(mockCtrl)
+
+		testAPI = api.NewAPI(config.LoadConfig())
+		testAPI.JWTServiceFactory = func(context.Context) interfaces.JWTServiceProvider {
+			return mockJWTService
+		}
+		testAPI.InitRoutes()
+	})
+
+	AfterEach(func() {
+		mockCtrl.Finish()
+	})
+
+	It("should publish the public keys without logging in", func() {
+		keySet := &domain.JSONWebKeySet{
+			Keys: []domain.JSONWebKey{
+				{KeyType: "OKP", KeyID: "some-key-id", Use: "sig", Algorithm: config.EdDSAAlgorithm, Curve: "Ed25519", X: "some-x"},
+			},
+		}
+		mockJWTService.EXPECT().PublicKeys().Return(keySet, nil)
+
+		respBody := HitEndpoint(testAPI, "GET", "/.well-known/jwks.json", nil, http.StatusOK)
+
+		var returnedKeySet domain.JSONWebKeySet
+		Expect(json.Unmarshal(respBody, &returnedKeySet)).To(Succeed())
+		Expect(returnedKeySet).To(Equal(*keySet))
+	})
+})
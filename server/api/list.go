@@ -0,0 +1,90 @@
+package api
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/rawfish-dev/rsvp-starter/server/domain"
+
+	"github.com/gin-gonic/gin"
+)
+
+// listQuery reads the query parameters of list endpoints, keeping the first malformed one as err
+// so handlers only check once after reading everything they need.
+type listQuery struct {
+	c   *gin.Context
+	err error
+}
+
+func (l *listQuery) listRequest() domain.ListRequest {
+	return domain.ListRequest{
+		Page:     l.int("page"),
+		PageSize: l.int("pageSize"),
+		Sort:     l.c.Query("sort"),
+	}
+}
+
+func (l *listQuery) int(key string) int {
+	value := l.c.Query(key)
+	if value == "" {
+		return 0
+	}
+
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		l.fail(fmt.Errorf("%v must be a number", key))
+	}
+
+	return parsed
+}
+
+func (l *listQuery) int64(key string) int64 {
+	value := l.c.Query(key)
+	if value == "" {
+		return 0
+	}
+
+	parsed, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		l.fail(fmt.Errorf("%v must be a number", key))
+	}
+
+	return parsed
+}
+
+func (l *listQuery) bool(key string) *bool {
+	value := l.c.Query(key)
+	if value == "" {
+		return nil
+	}
+
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		l.fail(fmt.Errorf("%v must be true or false", key))
+		return nil
+	}
+
+	return &parsed
+}
+
+func (l *listQuery) time(key string) *time.Time {
+	value := l.c.Query(key)
+	if value == "" {
+		return nil
+	}
+
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		l.fail(fmt.Errorf("%v must be an RFC 3339 timestamp", key))
+		return nil
+	}
+
+	return &parsed
+}
+
+func (l *listQuery) fail(err error) {
+	if l.err == nil {
+		l.err = err
+	}
+}
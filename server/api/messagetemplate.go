@@ -0,0 +1,199 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/rawfish-dev/rsvp-starter/server/domain"
+	serviceErrors "github.com/rawfish-dev/rsvp-starter/server/services/errors"
+	"github.com/rawfish-dev/rsvp-starter/server/services/messagetemplate"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/gin-gonic/gin"
+	"golang.org/x/net/context"
+)
+
+func createMessageTemplate(api *API) func(c *gin.Context) {
+	return func(c *gin.Context) {
+		ctxlogger := logrus.New()
+		ctx := context.Background()
+		ctx = context.WithValue(ctx, "logger", ctxlogger)
+		ctx = contextWithActor(ctx, c)
+
+		templateService := api.MessageTemplateServiceFactory(ctx)
+
+		var templateCreateRequest domain.MessageTemplateCreateRequest
+		err := c.BindJSON(&templateCreateRequest)
+		if err != nil {
+			ctxlogger.Errorf("message template api - unable to create new template while unwrapping request due to %v", err)
+			c.JSON(domain.NewInvalidJSONBodyError())
+			return
+		}
+
+		newTemplate, err := templateService.CreateMessageTemplate(&templateCreateRequest)
+		if err != nil {
+			switch err.(type) {
+			case serviceErrors.ValidationError:
+				ctxlogger.Warnf("message template api - unable to create new template due to validation error %v", err)
+				c.JSON(domain.NewCustomBadRequestError(err.Error()))
+				return
+			}
+
+			ctxlogger.Errorf("message template api - unable to create new template due to %v", err)
+			c.AbortWithStatus(http.StatusInternalServerError)
+			return
+		}
+
+		c.JSON(http.StatusOK, newTemplate)
+		return
+	}
+}
+
+func listMessageTemplates(api *API) func(c *gin.Context) {
+	return func(c *gin.Context) {
+		ctxlogger := logrus.New()
+		ctx := context.Background()
+		ctx = context.WithValue(ctx, "logger", ctxlogger)
+
+		templateService := api.MessageTemplateServiceFactory(ctx)
+
+		templates, err := templateService.ListMessageTemplates()
+		if err != nil {
+			ctxlogger.Errorf("message template api - unable to list templates due to %v", err)
+			c.AbortWithStatus(http.StatusInternalServerError)
+			return
+		}
+
+		if templates == nil {
+			templates = []domain.MessageTemplate{}
+		}
+
+		c.JSON(http.StatusOK, templates)
+		return
+	}
+}
+
+func updateMessageTemplate(api *API) func(c *gin.Context) {
+	return func(c *gin.Context) {
+		ctxlogger := logrus.New()
+		ctx := context.Background()
+		ctx = context.WithValue(ctx, "logger", ctxlogger)
+		ctx = contextWithActor(ctx, c)
+
+		templateService := api.MessageTemplateServiceFactory(ctx)
+
+		var templateUpdateRequest domain.MessageTemplateUpdateRequest
+		err := c.BindJSON(&templateUpdateRequest)
+		if err != nil {
+			ctxlogger.Errorf("message template api - unable to update template while unwrapping request due to %v", err)
+			c.JSON(domain.NewInvalidJSONBodyError())
+			return
+		}
+
+		if c.Param("id") != fmt.Sprintf("%v", templateUpdateRequest.ID) {
+			ctxlogger.Warnf("message template api - unable to update template as params id %v don't match request id %v", c.Param("id"), templateUpdateRequest.ID)
+			c.AbortWithStatus(http.StatusBadRequest)
+			return
+		}
+
+		updatedTemplate, err := templateService.UpdateMessageTemplate(&templateUpdateRequest)
+		if err != nil {
+			switch err.(type) {
+			case messagetemplate.MessageTemplateNotFoundError:
+				c.AbortWithStatus(http.StatusNotFound)
+				return
+			case serviceErrors.ValidationError:
+				ctxlogger.Warnf("message template api - unable to update template due to validation error %v", err)
+				c.JSON(domain.NewCustomBadRequestError(err.Error()))
+				return
+			}
+
+			ctxlogger.Errorf("message template api - unable to update template due to %v", err)
+			c.AbortWithStatus(http.StatusInternalServerError)
+			return
+		}
+
+		c.JSON(http.StatusOK, updatedTemplate)
+		return
+	}
+}
+
+func deleteMessageTemplate(api *API) func(c *gin.Context) {
+	return func(c *gin.Context) {
+		ctxlogger := logrus.New()
+		ctx := context.Background()
+		ctx = context.WithValue(ctx, "logger", ctxlogger)
+		ctx = contextWithActor(ctx, c)
+
+		templateService := api.MessageTemplateServiceFactory(ctx)
+
+		templateID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			ctxlogger.Warnf("message template api - unable to delete template as params id %v could not be converted due to %v", c.Param("id"), err)
+			c.AbortWithStatus(http.StatusBadRequest)
+			return
+		}
+
+		err = templateService.DeleteMessageTemplateByID(templateID)
+		if err != nil {
+			switch err.(type) {
+			case messagetemplate.MessageTemplateNotFoundError:
+				c.AbortWithStatus(http.StatusNotFound)
+				return
+			}
+
+			ctxlogger.Errorf("message template api - unable to delete template due to %v", err)
+			c.AbortWithStatus(http.StatusInternalServerError)
+			return
+		}
+
+		return
+	}
+}
+
+// previewMessageTemplate shows what the template would look like for the invitation given in the
+// invitationID query parameter
+func previewMessageTemplate(api *API) func(c *gin.Context) {
+	return func(c *gin.Context) {
+		ctxlogger := logrus.New()
+		ctx := context.Background()
+		ctx = context.WithValue(ctx, "logger", ctxlogger)
+
+		templateService := api.MessageTemplateServiceFactory(ctx)
+
+		templateID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			ctxlogger.Warnf("message template api - unable to preview template as params id %v could not be converted due to %v", c.Param("id"), err)
+			c.AbortWithStatus(http.StatusBadRequest)
+			return
+		}
+
+		invitationID, err := strconv.ParseInt(c.Query("invitationID"), 10, 64)
+		if err != nil {
+			ctxlogger.Warnf("message template api - unable to preview template as invitation id %v could not be converted due to %v", c.Query("invitationID"), err)
+			c.JSON(domain.NewCustomBadRequestError("invitationID must be the id of an invitation"))
+			return
+		}
+
+		rendered, err := templateService.PreviewMessageTemplate(templateID, invitationID)
+		if err != nil {
+			switch err.(type) {
+			case messagetemplate.MessageTemplateNotFoundError:
+				c.AbortWithStatus(http.StatusNotFound)
+				return
+			case serviceErrors.ValidationError:
+				ctxlogger.Warnf("message template api - unable to preview template due to validation error %v", err)
+				c.JSON(domain.NewCustomBadRequestError(err.Error()))
+				return
+			}
+
+			ctxlogger.Errorf("message template api - unable to preview template due to %v", err)
+			c.AbortWithStatus(http.StatusInternalServerError)
+			return
+		}
+
+		c.JSON(http.StatusOK, rendered)
+		return
+	}
+}
@@ -0,0 +1,180 @@
+package api_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+
+	"github.com/rawfish-dev/rsvp-starter/server/api"
+	"github.com/rawfish-dev/rsvp-starter/server/config"
+	"github.com/rawfish-dev/rsvp-starter/server/domain"
+	"github.com/rawfish-dev/rsvp-starter/server/interfaces"
+	"github.com/rawfish-dev/rsvp-starter/server/mock"
+	serviceErrors "github.com/rawfish-dev/rsvp-starter/server/services/errors"
+	"github.com/rawfish-dev/rsvp-starter/server/services/messagetemplate"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"golang.org/x/net/context"
+)
+
+var _ = Describe("Message template", func() {
+
+	var ctrl *gomock.Controller
+	var testAPI *api.API
+	var baseTemplate domain.BaseMessageTemplate
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+
+		testConfig := config.LoadConfig()
+		testAPI = api.NewAPI(testConfig)
+
+		testAPI.SessionServiceFactory = func(ctx context.Context) interfaces.SessionServiceProvider {
+			mockSessionService := mock_interfaces.NewMockSessionServiceProvider(ctrl)
+			mockSessionService.EXPECT().IsSessionValid("").Return(true, nil)
+			mockSessionService.EXPECT().Username("").Return("admin", nil)
+			mockSessionService.EXPECT().Role("").Return(domain.RoleOwner, nil)
+
+			return mockSessionService
+		}
+
+		testAPI.InitRoutes()
+
+		baseTemplate = domain.BaseMessageTemplate{
+			Name:    "invitation",
+			Kind:    domain.InvitationTemplate,
+			Channel: domain.SMSChannel,
+			Body:    "{{.Greeting}}, reply at {{.RSVPLink}}",
+		}
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	Context("creation", func() {
+
+		It("should return 200 OK and create a template given valid values", func() {
+			createTemplateReq := domain.MessageTemplateCreateRequest{BaseMessageTemplate: baseTemplate}
+
+			testAPI.MessageTemplateServiceFactory = func(ctx context.Context) interfaces.MessageTemplateServiceProvider {
+				mockTemplateService := mock_interfaces.NewMockMessageTemplateServiceProvider(ctrl)
+				mockTemplateService.EXPECT().CreateMessageTemplate(&createTemplateReq).
+					Return(&domain.MessageTemplate{ID: 1, BaseMessageTemplate: baseTemplate}, nil)
+
+				return mockTemplateService
+			}
+
+			reqBytes, err := json.Marshal(createTemplateReq)
+			Expect(err).ToNot(HaveOccurred())
+
+			responseBytes := HitEndpoint(testAPI, "POST", "/api/templates", bytes.NewBuffer(reqBytes), http.StatusOK)
+
+			var newTemplate domain.MessageTemplate
+			err = json.Unmarshal(responseBytes, &newTemplate)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(newTemplate.ID).To(Equal(int64(1)))
+			Expect(newTemplate.BaseMessageTemplate).To(Equal(baseTemplate))
+		})
+
+		It("should return 400 Bad Request if the template is invalid", func() {
+			testAPI.MessageTemplateServiceFactory = func(ctx context.Context) interfaces.MessageTemplateServiceProvider {
+				mockTemplateService := mock_interfaces.NewMockMessageTemplateServiceProvider(ctrl)
+				mockTemplateService.EXPECT().CreateMessageTemplate(gomock.Any()).
+					Return(nil, serviceErrors.NewValidationError([]string{"template body is invalid"}))
+
+				return mockTemplateService
+			}
+
+			reqBytes, err := json.Marshal(domain.MessageTemplateCreateRequest{BaseMessageTemplate: baseTemplate})
+			Expect(err).ToNot(HaveOccurred())
+
+			responseBytes := HitEndpoint(testAPI, "POST", "/api/templates", bytes.NewBuffer(reqBytes), http.StatusBadRequest)
+			Expect(string(responseBytes)).To(ContainSubstring("template body is invalid"))
+		})
+	})
+
+	Context("listing", func() {
+
+		It("should return 200 OK and an empty list when there are no templates", func() {
+			testAPI.MessageTemplateServiceFactory = func(ctx context.Context) interfaces.MessageTemplateServiceProvider {
+				mockTemplateService := mock_interfaces.NewMockMessageTemplateServiceProvider(ctrl)
+				mockTemplateService.EXPECT().ListMessageTemplates().Return(nil, nil)
+
+				return mockTemplateService
+			}
+
+			responseBytes := HitEndpoint(testAPI, "GET", "/api/templates", nil, http.StatusOK)
+			Expect(string(responseBytes)).To(MatchJSON("[]"))
+		})
+	})
+
+	Context("update", func() {
+
+		It("should return 200 OK and update the template given valid values", func() {
+			updateTemplateReq := domain.MessageTemplateUpdateRequest{ID: 1, BaseMessageTemplate: baseTemplate}
+
+			testAPI.MessageTemplateServiceFactory = func(ctx context.Context) interfaces.MessageTemplateServiceProvider {
+				mockTemplateService := mock_interfaces.NewMockMessageTemplateServiceProvider(ctrl)
+				mockTemplateService.EXPECT().UpdateMessageTemplate(&updateTemplateReq).
+					Return(&domain.MessageTemplate{ID: 1, BaseMessageTemplate: baseTemplate}, nil)
+
+				return mockTemplateService
+			}
+
+			reqBytes, err := json.Marshal(updateTemplateReq)
+			Expect(err).ToNot(HaveOccurred())
+
+			HitEndpoint(testAPI, "PUT", "/api/templates/1", bytes.NewBuffer(reqBytes), http.StatusOK)
+		})
+
+		It("should return 400 Bad Request if the ids do not match", func() {
+			reqBytes, err := json.Marshal(domain.MessageTemplateUpdateRequest{ID: 2, BaseMessageTemplate: baseTemplate})
+			Expect(err).ToNot(HaveOccurred())
+
+			HitEndpoint(testAPI, "PUT", "/api/templates/1", bytes.NewBuffer(reqBytes), http.StatusBadRequest)
+		})
+	})
+
+	Context("deletion", func() {
+
+		It("should return 404 Not Found for unknown templates", func() {
+			testAPI.MessageTemplateServiceFactory = func(ctx context.Context) interfaces.MessageTemplateServiceProvider {
+				mockTemplateService := mock_interfaces.NewMockMessageTemplateServiceProvider(ctrl)
+				mockTemplateService.EXPECT().DeleteMessageTemplateByID(int64(1)).
+					Return(messagetemplate.NewMessageTemplateNotFoundError())
+
+				return mockTemplateService
+			}
+
+			HitEndpoint(testAPI, "DELETE", "/api/templates/1", nil, http.StatusNotFound)
+		})
+	})
+
+	Context("preview", func() {
+
+		It("should return 200 OK and the template filled in for the invitation", func() {
+			testAPI.MessageTemplateServiceFactory = func(ctx context.Context) interfaces.MessageTemplateServiceProvider {
+				mockTemplateService := mock_interfaces.NewMockMessageTemplateServiceProvider(ctrl)
+				mockTemplateService.EXPECT().PreviewMessageTemplate(int64(1), int64(2)).
+					Return(&domain.RenderedMessage{TemplateID: 1, Body: "Ah Ma, reply at https://wedding.example.com/rsvp/abc"}, nil)
+
+				return mockTemplateService
+			}
+
+			responseBytes := HitEndpoint(testAPI, "GET", "/api/templates/1/preview?invitationID=2", nil, http.StatusOK)
+
+			var rendered domain.RenderedMessage
+			err := json.Unmarshal(responseBytes, &rendered)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(rendered.Body).To(Equal("Ah Ma, reply at https://wedding.example.com/rsvp/abc"))
+		})
+
+		It("should return 400 Bad Request without an invitation to preview with", func() {
+			responseBytes := HitEndpoint(testAPI, "GET", "/api/templates/1/preview", nil, http.StatusBadRequest)
+			Expect(string(responseBytes)).To(ContainSubstring("invitationID"))
+		})
+	})
+})
@@ -5,17 +5,28 @@ import (
 
 	"github.com/rawfish-dev/rsvp-starter/server/domain"
 	"github.com/rawfish-dev/rsvp-starter/server/interfaces"
+	"github.com/rawfish-dev/rsvp-starter/server/services/apikey"
+	"github.com/rawfish-dev/rsvp-starter/server/services/session"
 
 	"github.com/gin-gonic/gin"
+	"golang.org/x/net/context"
 )
 
 const (
-	authHeaderKey = "X-Auth-Header"
+	authHeaderKey   = "X-Auth-Header"
+	apiKeyHeaderKey = "X-API-Key"
 )
 
-// SessionMiddleware rejects requests without the correct auth header value and packs it into the context if present
-func SessionMiddleware(sessionService interfaces.SessionServiceProvider) gin.HandlerFunc {
+// SessionMiddleware rejects requests without the correct auth header value and packs it into the context if present.
+// With sliding sessions, a renewed auth token is returned in the same header of the response. Requests may send an
+// API key instead, in which case its scopes are packed into the context in place of a role.
+func SessionMiddleware(sessionService interfaces.SessionServiceProvider, apiKeyService interfaces.APIKeyServiceProvider, slidingSessions bool) gin.HandlerFunc {
 	return func(c *gin.Context) {
+		if key := c.Request.Header.Get(apiKeyHeaderKey); key != "" {
+			authenticateAPIKey(c, apiKeyService, key)
+			return
+		}
+
 		authToken := c.Request.Header.Get(authHeaderKey)
 
 		exists, err := sessionService.IsSessionValid(authToken)
@@ -28,8 +39,120 @@ func SessionMiddleware(sessionService interfaces.SessionServiceProvider) gin.Han
 			return
 		}
 
+		username, err := sessionService.Username(authToken)
+		if err != nil {
+			c.AbortWithStatus(http.StatusInternalServerError)
+			return
+		}
+
+		role, err := sessionService.Role(authToken)
+		if err != nil {
+			c.AbortWithStatus(http.StatusInternalServerError)
+			return
+		}
+
+		if slidingSessions {
+			renewedAuthToken, err := sessionService.Renew(authToken)
+			if err != nil {
+				switch err.(type) {
+				case session.SessionNotFoundError:
+					c.AbortWithStatus(http.StatusUnauthorized)
+					return
+				}
+
+				c.AbortWithStatus(http.StatusInternalServerError)
+				return
+			}
+			if renewedAuthToken != "" {
+				c.Header(authHeaderKey, renewedAuthToken)
+			}
+		}
+
 		c.Set(domain.ContextAuthToken, authToken)
+		c.Set(domain.ContextActor, domain.NewAdminActor(username))
+		c.Set(domain.ContextRole, role)
 
 		c.Next()
 	}
 }
+
+func authenticateAPIKey(c *gin.Context, apiKeyService interfaces.APIKeyServiceProvider, key string) {
+	apiKey, err := apiKeyService.Authenticate(key)
+	if err != nil {
+		switch err.(type) {
+		case apikey.APIKeyInvalidError:
+			c.AbortWithStatus(http.StatusUnauthorized)
+			return
+		}
+
+		c.AbortWithStatus(http.StatusInternalServerError)
+		return
+	}
+
+	c.Set(domain.ContextActor, domain.NewAPIKeyActor(apiKey.Name))
+	c.Set(domain.ContextScopes, apiKey.Scopes)
+
+	c.Next()
+}
+
+// RequireRole rejects requests from sessions whose role is not one of the given roles. It relies on the
+// role packed into the context by SessionMiddleware, so requests made with an API key are always rejected.
+func RequireRole(roles ...domain.Role) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		role, _ := c.Get(domain.ContextRole)
+
+		for _, allowedRole := range roles {
+			if role == allowedRole {
+				c.Next()
+				return
+			}
+		}
+
+		c.AbortWithStatus(http.StatusForbidden)
+	}
+}
+
+// RequireRoleOrScope lets sessions through on one of the given roles like RequireRole, and requests made
+// with an API key through when the key has the given scope
+func RequireRoleOrScope(scope domain.Scope, roles ...domain.Role) gin.HandlerFunc {
+	requireRole := RequireRole(roles...)
+
+	return func(c *gin.Context) {
+		scopes, usesAPIKey := c.Get(domain.ContextScopes)
+		if !usesAPIKey {
+			requireRole(c)
+			return
+		}
+
+		if domain.HasScope(scopes.([]domain.Scope), scope) {
+			c.Next()
+			return
+		}
+
+		c.AbortWithStatus(http.StatusForbidden)
+	}
+}
+
+// RequireSession rejects requests made with an API key, for routes that act on the session or account
+// of whoever is signed in
+func RequireSession() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, usesAPIKey := c.Get(domain.ContextScopes); usesAPIKey {
+			c.AbortWithStatus(http.StatusForbidden)
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// contextWithActor carries the actor set by the session middleware over to the services so their
+// changes can be audited
+func contextWithActor(ctx context.Context, c *gin.Context) context.Context {
+	actor, exists := c.Get(domain.ContextActor)
+	if !exists {
+		return ctx
+	}
+
+	return context.WithValue(ctx, domain.ContextActor, actor)
+}
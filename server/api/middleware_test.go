@@ -4,12 +4,16 @@ import (
 	"bytes"
 	"encoding/json"
 	"net/http"
+	"net/http/httptest"
 
 	"github.com/rawfish-dev/rsvp-starter/server/api"
 	"github.com/rawfish-dev/rsvp-starter/server/config"
+	"github.com/rawfish-dev/rsvp-starter/server/domain"
 	"github.com/rawfish-dev/rsvp-starter/server/interfaces"
 	"github.com/rawfish-dev/rsvp-starter/server/mock"
+	"github.com/rawfish-dev/rsvp-starter/server/services/apikey"
 	serviceErrors "github.com/rawfish-dev/rsvp-starter/server/services/errors"
+	"github.com/rawfish-dev/rsvp-starter/server/services/session"
 
 	"github.com/golang/mock/gomock"
 	. "github.com/onsi/ginkgo"
@@ -71,4 +75,258 @@ var _ = Describe("Middleware", func() {
 
 		HitEndpoint(testAPI, "POST", "/api/invitations", bytes.NewBuffer(reqBytes), http.StatusUnauthorized)
 	})
+
+	It("should return 500 Internal Server Error when the username of a valid session cannot be read", func() {
+		testConfig := config.LoadConfig()
+		testAPI = api.NewAPI(testConfig)
+
+		testAPI.SessionServiceFactory = func(ctx context.Context) interfaces.SessionServiceProvider {
+			mockSessionService := mock_interfaces.NewMockSessionServiceProvider(ctrl)
+			mockSessionService.EXPECT().IsSessionValid("").Return(true, nil)
+			mockSessionService.EXPECT().Username("").
+				Return("", serviceErrors.NewGeneralServiceError())
+
+			return mockSessionService
+		}
+
+		testAPI.InitRoutes()
+
+		// Just use any protected route with any request body
+		reqBytes, err := json.Marshal(`{}`)
+		Expect(err).ToNot(HaveOccurred())
+
+		HitEndpoint(testAPI, "POST", "/api/invitations", bytes.NewBuffer(reqBytes), http.StatusInternalServerError)
+	})
+
+	sessionWithRole := func(role domain.Role, roleErr error) func(ctx context.Context) interfaces.SessionServiceProvider {
+		return func(ctx context.Context) interfaces.SessionServiceProvider {
+			mockSessionService := mock_interfaces.NewMockSessionServiceProvider(ctrl)
+			mockSessionService.EXPECT().IsSessionValid("").Return(true, nil)
+			mockSessionService.EXPECT().Username("").Return("admin", nil)
+			mockSessionService.EXPECT().Role("").Return(role, roleErr)
+
+			return mockSessionService
+		}
+	}
+
+	It("should return 500 Internal Server Error when the role of a valid session cannot be read", func() {
+		testConfig := config.LoadConfig()
+		testAPI = api.NewAPI(testConfig)
+		testAPI.SessionServiceFactory = sessionWithRole("", serviceErrors.NewGeneralServiceError())
+		testAPI.InitRoutes()
+
+		HitEndpoint(testAPI, "GET", "/api/categories", nil, http.StatusInternalServerError)
+	})
+
+	It("should let viewers list records", func() {
+		testConfig := config.LoadConfig()
+		testAPI = api.NewAPI(testConfig)
+		testAPI.SessionServiceFactory = sessionWithRole(domain.RoleViewer, nil)
+		testAPI.CategoryServiceFactory = func(ctx context.Context) interfaces.CategoryServiceProvider {
+			mockCategoryService := mock_interfaces.NewMockCategoryServiceProvider(ctrl)
+			mockCategoryService.EXPECT().ListCategories(gomock.Any()).Return(&domain.CategoryList{}, nil)
+
+			return mockCategoryService
+		}
+		testAPI.InitRoutes()
+
+		HitEndpoint(testAPI, "GET", "/api/categories", nil, http.StatusOK)
+	})
+
+	It("should return 403 Forbidden when viewers try to delete records", func() {
+		testConfig := config.LoadConfig()
+		testAPI = api.NewAPI(testConfig)
+		testAPI.SessionServiceFactory = sessionWithRole(domain.RoleViewer, nil)
+		testAPI.InitRoutes()
+
+		HitEndpoint(testAPI, "DELETE", "/api/invitations/1", nil, http.StatusForbidden)
+	})
+
+	It("should return 403 Forbidden when door staff try to list invitations", func() {
+		testConfig := config.LoadConfig()
+		testAPI = api.NewAPI(testConfig)
+		testAPI.SessionServiceFactory = sessionWithRole(domain.RoleDoorStaff, nil)
+		testAPI.InitRoutes()
+
+		HitEndpoint(testAPI, "GET", "/api/invitations", nil, http.StatusForbidden)
+	})
+
+	It("should let door staff check guests in", func() {
+		testConfig := config.LoadConfig()
+		testAPI = api.NewAPI(testConfig)
+		testAPI.SessionServiceFactory = sessionWithRole(domain.RoleDoorStaff, nil)
+		testAPI.RSVPServiceFactory = func(ctx context.Context) interfaces.RSVPServiceProvider {
+			mockRSVPService := mock_interfaces.NewMockRSVPServiceProvider(ctrl)
+			mockRSVPService.EXPECT().CheckInRSVPByID(int64(1)).Return(&domain.RSVP{ID: 1, CheckedInAt: "2026-10-17T19:00:00Z"}, nil)
+
+			return mockRSVPService
+		}
+		testAPI.InitRoutes()
+
+		HitEndpoint(testAPI, "POST", "/api/rsvps/1/check-in", nil, http.StatusOK)
+	})
+
+	It("should return 403 Forbidden when door staff try to change rsvps", func() {
+		testConfig := config.LoadConfig()
+		testAPI = api.NewAPI(testConfig)
+		testAPI.SessionServiceFactory = sessionWithRole(domain.RoleDoorStaff, nil)
+		testAPI.InitRoutes()
+
+		HitEndpoint(testAPI, "DELETE", "/api/rsvps/1", nil, http.StatusForbidden)
+	})
+
+	It("should return 403 Forbidden when viewers try to check guests in", func() {
+		testConfig := config.LoadConfig()
+		testAPI = api.NewAPI(testConfig)
+		testAPI.SessionServiceFactory = sessionWithRole(domain.RoleViewer, nil)
+		testAPI.InitRoutes()
+
+		HitEndpoint(testAPI, "POST", "/api/rsvps/1/check-in", nil, http.StatusForbidden)
+	})
+
+	It("should return 403 Forbidden when editors try to manage users", func() {
+		testConfig := config.LoadConfig()
+		testAPI = api.NewAPI(testConfig)
+		testAPI.SessionServiceFactory = sessionWithRole(domain.RoleEditor, nil)
+		testAPI.InitRoutes()
+
+		HitEndpoint(testAPI, "GET", "/api/users", nil, http.StatusForbidden)
+	})
+
+	It("should return 403 Forbidden when the session has no role", func() {
+		testConfig := config.LoadConfig()
+		testAPI = api.NewAPI(testConfig)
+		testAPI.SessionServiceFactory = sessionWithRole("", nil)
+		testAPI.InitRoutes()
+
+		HitEndpoint(testAPI, "GET", "/api/rsvps", nil, http.StatusForbidden)
+	})
+
+	It("should return a renewed auth token in the response header with sliding sessions", func() {
+		testConfig := config.LoadConfig()
+		testAPI = api.NewAPI(testConfig)
+		testAPI.SlidingSessions = true
+		testAPI.SessionServiceFactory = func(ctx context.Context) interfaces.SessionServiceProvider {
+			mockSessionService := mock_interfaces.NewMockSessionServiceProvider(ctrl)
+			mockSessionService.EXPECT().IsSessionValid("").Return(true, nil)
+			mockSessionService.EXPECT().Username("").Return("admin", nil)
+			mockSessionService.EXPECT().Role("").Return(domain.RoleViewer, nil)
+			mockSessionService.EXPECT().Renew("").Return("renewed-auth-token", nil)
+
+			return mockSessionService
+		}
+		testAPI.CategoryServiceFactory = func(ctx context.Context) interfaces.CategoryServiceProvider {
+			mockCategoryService := mock_interfaces.NewMockCategoryServiceProvider(ctrl)
+			mockCategoryService.EXPECT().ListCategories(gomock.Any()).Return(&domain.CategoryList{}, nil)
+
+			return mockCategoryService
+		}
+		testAPI.InitRoutes()
+
+		request, err := http.NewRequest("GET", "/api/categories", nil)
+		Expect(err).ToNot(HaveOccurred())
+
+		response := httptest.NewRecorder()
+		testAPI.Router.ServeHTTP(response, request)
+
+		Expect(response.Code).To(Equal(http.StatusOK))
+		Expect(response.Header().Get("X-Auth-Header")).To(Equal("renewed-auth-token"))
+	})
+
+	It("should return 401 Unauthorized when the session ends instead of being renewed", func() {
+		testConfig := config.LoadConfig()
+		testAPI = api.NewAPI(testConfig)
+		testAPI.SlidingSessions = true
+		testAPI.SessionServiceFactory = func(ctx context.Context) interfaces.SessionServiceProvider {
+			mockSessionService := mock_interfaces.NewMockSessionServiceProvider(ctrl)
+			mockSessionService.EXPECT().IsSessionValid("").Return(true, nil)
+			mockSessionService.EXPECT().Username("").Return("admin", nil)
+			mockSessionService.EXPECT().Role("").Return(domain.RoleViewer, nil)
+			mockSessionService.EXPECT().Renew("").Return("", session.NewSessionNotFoundError())
+
+			return mockSessionService
+		}
+		testAPI.InitRoutes()
+
+		HitEndpoint(testAPI, "GET", "/api/categories", nil, http.StatusUnauthorized)
+	})
+
+	Context("api keys", func() {
+
+		BeforeEach(func() {
+			testConfig := config.LoadConfig()
+			testAPI = api.NewAPI(testConfig)
+
+			// Requests with an API key never look at the session
+			testAPI.SessionServiceFactory = func(ctx context.Context) interfaces.SessionServiceProvider {
+				return mock_interfaces.NewMockSessionServiceProvider(ctrl)
+			}
+		})
+
+		apiKeyWithScopes := func(scopes ...domain.Scope) func(ctx context.Context) interfaces.APIKeyServiceProvider {
+			return func(ctx context.Context) interfaces.APIKeyServiceProvider {
+				mockAPIKeyService := mock_interfaces.NewMockAPIKeyServiceProvider(ctrl)
+				mockAPIKeyService.EXPECT().Authenticate("rsvp_some-key").
+					Return(&domain.APIKey{ID: 1, Name: "spreadsheet", Scopes: scopes}, nil).AnyTimes()
+
+				return mockAPIKeyService
+			}
+		}
+
+		hitWithAPIKey := func(method, url string, expectedStatus int) {
+			request, err := http.NewRequest(method, url, nil)
+			Expect(err).ToNot(HaveOccurred())
+			request.Header.Set("X-API-Key", "rsvp_some-key")
+
+			response := httptest.NewRecorder()
+			testAPI.Router.ServeHTTP(response, request)
+
+			Expect(response.Code).To(Equal(expectedStatus))
+		}
+
+		It("should let api keys through with the scope of the route and audit changes under the key", func() {
+			testAPI.APIKeyServiceFactory = apiKeyWithScopes(domain.ScopeInvitationsWrite)
+			testAPI.InvitationServiceFactory = func(ctx context.Context) interfaces.InvitationServiceProvider {
+				Expect(ctx.Value(domain.ContextActor)).To(Equal(domain.NewAPIKeyActor("spreadsheet")))
+
+				mockInvitationService := mock_interfaces.NewMockInvitationServiceProvider(ctrl)
+				mockInvitationService.EXPECT().DeleteInvitationByID(int64(1)).Return(nil)
+
+				return mockInvitationService
+			}
+			testAPI.InitRoutes()
+
+			hitWithAPIKey("DELETE", "/api/invitations/1", http.StatusOK)
+		})
+
+		It("should return 403 Forbidden when the api key does not have the scope of the route", func() {
+			testAPI.APIKeyServiceFactory = apiKeyWithScopes(domain.ScopeInvitationsRead)
+			testAPI.InitRoutes()
+
+			hitWithAPIKey("DELETE", "/api/invitations/1", http.StatusForbidden)
+			hitWithAPIKey("GET", "/api/rsvps", http.StatusForbidden)
+		})
+
+		It("should return 403 Forbidden for routes that do not take api keys", func() {
+			testAPI.APIKeyServiceFactory = apiKeyWithScopes(domain.Scopes...)
+			testAPI.InitRoutes()
+
+			hitWithAPIKey("GET", "/api/users", http.StatusForbidden)
+			hitWithAPIKey("GET", "/api/api-keys", http.StatusForbidden)
+			hitWithAPIKey("GET", "/api/sessions", http.StatusForbidden)
+			hitWithAPIKey("PUT", "/api/account/password", http.StatusForbidden)
+		})
+
+		It("should return 401 Unauthorized for invalid api keys", func() {
+			testAPI.APIKeyServiceFactory = func(ctx context.Context) interfaces.APIKeyServiceProvider {
+				mockAPIKeyService := mock_interfaces.NewMockAPIKeyServiceProvider(ctrl)
+				mockAPIKeyService.EXPECT().Authenticate("rsvp_some-key").Return(nil, apikey.NewAPIKeyInvalidError())
+
+				return mockAPIKeyService
+			}
+			testAPI.InitRoutes()
+
+			hitWithAPIKey("GET", "/api/invitations", http.StatusUnauthorized)
+		})
+	})
 })
@@ -0,0 +1,29 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/gin-gonic/gin"
+	"golang.org/x/net/context"
+)
+
+func listReminders(api *API) func(c *gin.Context) {
+	return func(c *gin.Context) {
+		ctxlogger := logrus.New()
+		ctx := context.Background()
+		ctx = context.WithValue(ctx, "logger", ctxlogger)
+
+		invitationService := api.InvitationServiceFactory(ctx)
+
+		reminderList, err := invitationService.ListReminders()
+		if err != nil {
+			ctxlogger.Errorf("reminder api - unable to list reminders due to %v", err)
+			c.AbortWithStatus(http.StatusInternalServerError)
+			return
+		}
+
+		c.JSON(http.StatusOK, reminderList)
+		return
+	}
+}
@@ -0,0 +1,85 @@
+package api_test
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/rawfish-dev/rsvp-starter/server/api"
+	"github.com/rawfish-dev/rsvp-starter/server/config"
+	"github.com/rawfish-dev/rsvp-starter/server/domain"
+	"github.com/rawfish-dev/rsvp-starter/server/interfaces"
+	"github.com/rawfish-dev/rsvp-starter/server/mock"
+	serviceErrors "github.com/rawfish-dev/rsvp-starter/server/services/errors"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"golang.org/x/net/context"
+)
+
+var _ = Describe("Reminder", func() {
+
+	var ctrl *gomock.Controller
+	var testAPI *api.API
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+
+		testConfig := config.LoadConfig()
+		testAPI = api.NewAPI(testConfig)
+
+		testAPI.SessionServiceFactory = func(ctx context.Context) interfaces.SessionServiceProvider {
+			mockSessionService := mock_interfaces.NewMockSessionServiceProvider(ctrl)
+			mockSessionService.EXPECT().IsSessionValid("").Return(true, nil)
+			mockSessionService.EXPECT().Username("").Return("admin", nil)
+			mockSessionService.EXPECT().Role("").Return(domain.RoleViewer, nil)
+
+			return mockSessionService
+		}
+
+		testAPI.InitRoutes()
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	It("should return 200 OK and the upcoming and sent reminders", func() {
+		testAPI.InvitationServiceFactory = func(ctx context.Context) interfaces.InvitationServiceProvider {
+			mockInvitationService := mock_interfaces.NewMockInvitationServiceProvider(ctrl)
+			mockInvitationService.EXPECT().ListReminders().Return(&domain.ReminderList{
+				Enabled: true,
+				Upcoming: []domain.UpcomingReminder{
+					{InvitationID: 1, Greeting: "Ah Ma", ReminderNumber: 1, DueAt: "2026-10-13T10:00:00Z"},
+				},
+				Sent: []domain.Message{
+					{ID: 2, InvitationID: 3, Kind: domain.ReminderMessage, Status: domain.MessageSent},
+				},
+			}, nil)
+
+			return mockInvitationService
+		}
+
+		responseBytes := HitEndpoint(testAPI, "GET", "/api/reminders", nil, http.StatusOK)
+
+		var reminderList domain.ReminderList
+		err := json.Unmarshal(responseBytes, &reminderList)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(reminderList.Enabled).To(BeTrue())
+		Expect(reminderList.Upcoming).To(HaveLen(1))
+		Expect(reminderList.Upcoming[0].DueAt).To(Equal("2026-10-13T10:00:00Z"))
+		Expect(reminderList.Sent).To(HaveLen(1))
+		Expect(reminderList.Sent[0].Kind).To(Equal(domain.ReminderMessage))
+	})
+
+	It("should return 500 Internal Server Error if the reminders cannot be worked out", func() {
+		testAPI.InvitationServiceFactory = func(ctx context.Context) interfaces.InvitationServiceProvider {
+			mockInvitationService := mock_interfaces.NewMockInvitationServiceProvider(ctrl)
+			mockInvitationService.EXPECT().ListReminders().Return(nil, serviceErrors.NewGeneralServiceError())
+
+			return mockInvitationService
+		}
+
+		HitEndpoint(testAPI, "GET", "/api/reminders", nil, http.StatusInternalServerError)
+	})
+})
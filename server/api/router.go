@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"net/http"
 
+	"github.com/rawfish-dev/rsvp-starter/server/domain"
+
 	"github.com/Sirupsen/logrus"
 	"github.com/gin-gonic/gin"
 	"golang.org/x/net/context"
@@ -19,6 +21,9 @@ func (a *API) InitRoutes() {
 	a.Router.Static("/static", "./static")
 	a.Router.LoadHTMLFiles("index.html")
 
+	// Public keys for verifying tokens live at the well-known path rather than under the api namespace
+	a.Router.GET("/.well-known/jwks.json", getJWKS(a))
+
 	// Catch all unmatched routes here
 	a.Router.NoRoute(func(c *gin.Context) {
 		c.HTML(http.StatusOK, "index.html", nil)
@@ -29,35 +34,104 @@ func (a *API) InitRoutes() {
 	// No auth required
 	{
 		apiNameSpace.GET("/healthcheck", healthcheck)
+		apiNameSpace.GET("/captcha", getCaptchaSettings(a))
 		apiNameSpace.POST("/sessions", createSession(a))
+		apiNameSpace.POST("/sessions/refresh", refreshSession(a))
+		apiNameSpace.POST("/sessions/two-factor", verifyTwoFactor(a))
 
 		apiNameSpace.GET("/rsvps/:id", getRSVP(a))
+
+		// Providers authenticate with a signature over the body instead
+		apiNameSpace.POST("/webhooks/messages", updateMessageStatus(a))
 	}
 
 	// Initialise logger for the session service
 	ctxlogger := logrus.New()
 	ctx := context.Background()
 	ctx = context.WithValue(ctx, "logger", ctxlogger)
-	apiNameSpace.Use(SessionMiddleware(a.SessionServiceFactory(ctx)))
+	apiNameSpace.Use(SessionMiddleware(a.SessionServiceFactory(ctx), a.APIKeyServiceFactory(ctx), a.SlidingSessions))
+
+	// Roles allowed through by each group of routes below. Requests made with an API key only get through
+	// the routes that also name a scope.
+	owners := RequireRole(domain.RoleOwner)
+	editors := RequireRole(domain.RoleOwner, domain.RoleEditor)
+	doorStaff := RequireRole(domain.RoleOwner, domain.RoleEditor, domain.RoleViewer, domain.RoleDoorStaff)
+	signedIn := RequireSession()
+
+	editorsOr := func(scope domain.Scope) gin.HandlerFunc {
+		return RequireRoleOrScope(scope, domain.RoleOwner, domain.RoleEditor)
+	}
+	viewersOr := func(scope domain.Scope) gin.HandlerFunc {
+		return RequireRoleOrScope(scope, domain.RoleOwner, domain.RoleEditor, domain.RoleViewer)
+	}
+	doorStaffOr := func(scope domain.Scope) gin.HandlerFunc {
+		return RequireRoleOrScope(scope, domain.RoleOwner, domain.RoleEditor, domain.RoleViewer, domain.RoleDoorStaff)
+	}
+	// Viewers are read only, so they are left out of checking guests in at the door
+	checkInStaffOr := func(scope domain.Scope) gin.HandlerFunc {
+		return RequireRoleOrScope(scope, domain.RoleOwner, domain.RoleEditor, domain.RoleDoorStaff)
+	}
 
 	// Auth required
 	{
-		apiNameSpace.DELETE("/sessions", destroySession(a))
-
-		apiNameSpace.POST("/categories", createCategory(a))
-		apiNameSpace.GET("/categories", listCategories(a))
-		apiNameSpace.PUT("/categories/:id", updateCategory(a))
-		apiNameSpace.DELETE("/categories/:id", deleteCategory(a))
-
-		apiNameSpace.POST("/invitations", createInvitation(a))
-		apiNameSpace.GET("/invitations", listInvitations(a))
-		apiNameSpace.PUT("/invitations/:id", updateInvitation(a))
-		apiNameSpace.DELETE("/invitations/:id", deleteInvitation(a))
-
-		apiNameSpace.POST("/rsvps", createRSVP(a))
-		apiNameSpace.GET("/rsvps", listRSVPs(a))
-		apiNameSpace.PUT("/rsvps/:id", updateRSVP(a))
-		apiNameSpace.DELETE("/rsvps/:id", deleteRSVP(a))
+		apiNameSpace.DELETE("/sessions", signedIn, destroySession(a))
+		apiNameSpace.GET("/sessions", signedIn, listSessions(a))
+		apiNameSpace.DELETE("/sessions/:id", signedIn, revokeSession(a))
+
+		apiNameSpace.POST("/categories", editorsOr(domain.ScopeCategoriesWrite), createCategory(a))
+		apiNameSpace.GET("/categories", viewersOr(domain.ScopeCategoriesRead), listCategories(a))
+		apiNameSpace.PUT("/categories/:id", editorsOr(domain.ScopeCategoriesWrite), updateCategory(a))
+		apiNameSpace.DELETE("/categories/:id", editorsOr(domain.ScopeCategoriesWrite), deleteCategory(a))
+		apiNameSpace.POST("/categories/:id/restore", editorsOr(domain.ScopeCategoriesWrite), restoreCategory(a))
+		apiNameSpace.POST("/categories/:id/send", editorsOr(domain.ScopeInvitationsWrite), sendCategoryInvitations(a))
+
+		apiNameSpace.POST("/invitations", editorsOr(domain.ScopeInvitationsWrite), createInvitation(a))
+		apiNameSpace.GET("/invitations", viewersOr(domain.ScopeInvitationsRead), listInvitations(a))
+		apiNameSpace.PUT("/invitations/:id", editorsOr(domain.ScopeInvitationsWrite), updateInvitation(a))
+		apiNameSpace.DELETE("/invitations/:id", editorsOr(domain.ScopeInvitationsWrite), deleteInvitation(a))
+		apiNameSpace.POST("/invitations/:id/restore", editorsOr(domain.ScopeInvitationsWrite), restoreInvitation(a))
+		apiNameSpace.PUT("/invitations/:id/link", editorsOr(domain.ScopeInvitationsWrite), updateInvitationLink(a))
+		apiNameSpace.POST("/invitations/:id/link/revoke", editorsOr(domain.ScopeInvitationsWrite), revokeInvitationLink(a))
+		apiNameSpace.POST("/invitations/:id/link/rotate", editorsOr(domain.ScopeInvitationsWrite), rotateInvitationLink(a))
+		apiNameSpace.POST("/invitations/:id/send", editorsOr(domain.ScopeInvitationsWrite), sendInvitation(a))
+		apiNameSpace.GET("/invitations/:id/messages", viewersOr(domain.ScopeInvitationsRead), listInvitationMessages(a))
+
+		apiNameSpace.GET("/reminders", viewersOr(domain.ScopeInvitationsRead), listReminders(a))
+
+		apiNameSpace.POST("/templates", editorsOr(domain.ScopeInvitationsWrite), createMessageTemplate(a))
+		apiNameSpace.GET("/templates", viewersOr(domain.ScopeInvitationsRead), listMessageTemplates(a))
+		apiNameSpace.PUT("/templates/:id", editorsOr(domain.ScopeInvitationsWrite), updateMessageTemplate(a))
+		apiNameSpace.DELETE("/templates/:id", editorsOr(domain.ScopeInvitationsWrite), deleteMessageTemplate(a))
+		apiNameSpace.GET("/templates/:id/preview", viewersOr(domain.ScopeInvitationsRead), previewMessageTemplate(a))
+
+		apiNameSpace.POST("/rsvps", editorsOr(domain.ScopeRSVPsWrite), createRSVP(a))
+		apiNameSpace.GET("/rsvps", doorStaffOr(domain.ScopeRSVPsRead), listRSVPs(a))
+		apiNameSpace.PUT("/rsvps/:id", editorsOr(domain.ScopeRSVPsWrite), updateRSVP(a))
+		apiNameSpace.DELETE("/rsvps/:id", editorsOr(domain.ScopeRSVPsWrite), deleteRSVP(a))
+		apiNameSpace.POST("/rsvps/:id/restore", editorsOr(domain.ScopeRSVPsWrite), restoreRSVP(a))
+		apiNameSpace.POST("/rsvps/:id/check-in", checkInStaffOr(domain.ScopeRSVPsWrite), checkInRSVP(a))
+
+		apiNameSpace.GET("/search", doorStaff, searchGuests(a))
+
+		apiNameSpace.GET("/trash", editors, listTrash(a))
+
+		apiNameSpace.GET("/audit", owners, listAuditEntries(a))
+
+		apiNameSpace.POST("/users", owners, createUser(a))
+		apiNameSpace.GET("/users", owners, listUsers(a))
+		apiNameSpace.PUT("/users/:id/role", owners, updateUserRole(a))
+		apiNameSpace.POST("/users/:id/disable", owners, disableUser(a))
+		apiNameSpace.PUT("/users/:id/password", owners, resetPassword(a))
+		apiNameSpace.POST("/users/:id/totp/reset", owners, resetTOTP(a))
+
+		apiNameSpace.POST("/api-keys", owners, createAPIKey(a))
+		apiNameSpace.GET("/api-keys", owners, listAPIKeys(a))
+		apiNameSpace.POST("/api-keys/:id/revoke", owners, revokeAPIKey(a))
+
+		apiNameSpace.PUT("/account/password", signedIn, changePassword(a))
+		apiNameSpace.POST("/account/totp", signedIn, beginTOTPEnrollment(a))
+		apiNameSpace.POST("/account/totp/confirm", signedIn, confirmTOTPEnrollment(a))
+		apiNameSpace.DELETE("/account/totp", signedIn, disableTOTP(a))
 	}
 }
 
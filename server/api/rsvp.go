@@ -7,7 +7,6 @@ import (
 
 	"github.com/rawfish-dev/rsvp-starter/server/domain"
 	serviceErrors "github.com/rawfish-dev/rsvp-starter/server/services/errors"
-	"github.com/rawfish-dev/rsvp-starter/server/services/invitation"
 	"github.com/rawfish-dev/rsvp-starter/server/services/rsvp"
 
 	"github.com/Sirupsen/logrus"
@@ -22,7 +21,6 @@ func getRSVP(api *API) func(c *gin.Context) {
 		ctx = context.WithValue(ctx, "logger", ctxlogger)
 
 		rsvpService := api.RSVPServiceFactory(ctx)
-		invitationService := api.InvitationServiceFactory(ctx)
 
 		// Only private invitations can be fetched
 		invitationPrivateID := c.Param("id")
@@ -32,39 +30,31 @@ func getRSVP(api *API) func(c *gin.Context) {
 			return
 		}
 
+		guestInvitation, ok := authorizeGuest(api, ctx, c, ctxlogger, invitationPrivateID)
+		if !ok {
+			return
+		}
+
 		// If a RSVP record can be found, the guest has already RSVP-ed
 		privateRSVP, err := rsvpService.RetrievePrivateRSVP(invitationPrivateID)
 		if err != nil {
 			switch err.(type) {
 			case rsvp.RSVPNotFoundError:
 
-				// In the event the RSVP cannot be found, check if the invitation exists
-				retrievedInvitation, err := invitationService.RetrieveInvitationByPrivateID(invitationPrivateID)
-				if err != nil {
-					switch err.(type) {
-					case invitation.InvitationNotFoundError:
-						c.AbortWithStatus(http.StatusNotFound)
-						return
-					}
-
-					ctxlogger.Errorf("rsvp api - unable to retrieve private rsvp due to %v", err)
-					c.AbortWithStatus(http.StatusInternalServerError)
-					return
-				}
-
 				// Invitation exists but the guest has not yet RSVP-ed
 				privateRSVP = &domain.RSVP{
 					BaseRSVP: domain.BaseRSVP{
-						FullName:          retrievedInvitation.Greeting,
+						FullName:          guestInvitation.Greeting,
 						Attending:         true,
-						GuestCount:        retrievedInvitation.MaximumGuestCount,
+						GuestCount:        guestInvitation.MaximumGuestCount,
 						SpecialDiet:       false,
 						Remarks:           "",
-						MobilePhoneNumber: retrievedInvitation.MobilePhoneNumber,
+						MobilePhoneNumber: guestInvitation.MobilePhoneNumber,
+						Email:             guestInvitation.Email,
 					},
-					InvitationPrivateID: retrievedInvitation.PrivateID,
+					InvitationPrivateID: guestInvitation.PrivateID,
 					Completed:           false,
-					UpdatedAt:           retrievedInvitation.UpdatedAt,
+					UpdatedAt:           guestInvitation.UpdatedAt,
 				}
 
 				c.JSON(http.StatusOK, privateRSVP)
@@ -89,6 +79,7 @@ func createRSVP(api *API) func(c *gin.Context) {
 		ctxlogger := logrus.New()
 		ctx := context.Background()
 		ctx = context.WithValue(ctx, "logger", ctxlogger)
+		ctx = contextWithActor(ctx, c)
 
 		rsvpService := api.RSVPServiceFactory(ctx)
 
@@ -127,14 +118,34 @@ func listRSVPs(api *API) func(c *gin.Context) {
 
 		rsvpService := api.RSVPServiceFactory(ctx)
 
-		allRSVPs, err := rsvpService.ListRSVPs()
+		query := &listQuery{c: c}
+		rsvpListRequest := domain.RSVPListRequest{
+			ListRequest:  query.listRequest(),
+			Attending:    query.bool("attending"),
+			SpecialDiet:  query.bool("specialDiet"),
+			UpdatedSince: query.time("updatedSince"),
+		}
+		if query.err != nil {
+			ctxlogger.Warnf("rsvp api - unable to list rsvps due to invalid query %v", query.err)
+			c.JSON(domain.NewCustomBadRequestError(query.err.Error()))
+			return
+		}
+
+		rsvpList, err := rsvpService.ListRSVPs(&rsvpListRequest)
 		if err != nil {
-			ctxlogger.Errorf("rsvp api - unable to list all rsvps due to %v", err)
+			switch err.(type) {
+			case serviceErrors.ValidationError:
+				ctxlogger.Warnf("rsvp api - unable to list rsvps due to validation error %v", err)
+				c.JSON(domain.NewCustomBadRequestError(err.Error()))
+				return
+			}
+
+			ctxlogger.Errorf("rsvp api - unable to list rsvps due to %v", err)
 			c.AbortWithStatus(http.StatusInternalServerError)
 			return
 		}
 
-		c.JSON(http.StatusOK, allRSVPs)
+		c.JSON(http.StatusOK, rsvpList)
 		return
 	}
 }
@@ -144,6 +155,7 @@ func updateRSVP(api *API) func(c *gin.Context) {
 		ctxlogger := logrus.New()
 		ctx := context.Background()
 		ctx = context.WithValue(ctx, "logger", ctxlogger)
+		ctx = contextWithActor(ctx, c)
 
 		rsvpService := api.RSVPServiceFactory(ctx)
 
@@ -163,11 +175,19 @@ func updateRSVP(api *API) func(c *gin.Context) {
 
 		updatedRSVP, err := rsvpService.UpdateRSVP(&rsvpUpdateRequest)
 		if err != nil {
-			switch err.(type) {
+			switch err := err.(type) {
 			case serviceErrors.ValidationError:
 				ctxlogger.Errorf("rsvp api - unable to update rsvp due to validation error %v", err)
 				c.JSON(domain.NewCustomBadRequestError(err.Error()))
 				return
+			case rsvp.RSVPNotFoundError:
+				c.AbortWithStatus(http.StatusNotFound)
+				return
+			case rsvp.RSVPVersionConflictError:
+				// Send back the latest rsvp so the control panel can refresh the stale copy
+				ctxlogger.Warnf("rsvp api - unable to update rsvp %v due to %v", rsvpUpdateRequest.ID, err)
+				c.JSON(http.StatusConflict, err.Current)
+				return
 			}
 
 			ctxlogger.Errorf("rsvp api - unable to update rsvp due to %v", err)
@@ -185,6 +205,7 @@ func deleteRSVP(api *API) func(c *gin.Context) {
 		ctxlogger := logrus.New()
 		ctx := context.Background()
 		ctx = context.WithValue(ctx, "logger", ctxlogger)
+		ctx = contextWithActor(ctx, c)
 
 		rsvpService := api.RSVPServiceFactory(ctx)
 
@@ -212,3 +233,77 @@ func deleteRSVP(api *API) func(c *gin.Context) {
 		return
 	}
 }
+
+func restoreRSVP(api *API) func(c *gin.Context) {
+	return func(c *gin.Context) {
+		ctxlogger := logrus.New()
+		ctx := context.Background()
+		ctx = context.WithValue(ctx, "logger", ctxlogger)
+		ctx = contextWithActor(ctx, c)
+
+		rsvpService := api.RSVPServiceFactory(ctx)
+
+		rsvpIDStr := c.Param("id")
+		rsvpID, err := strconv.ParseInt(rsvpIDStr, 10, 64)
+		if err != nil {
+			ctxlogger.Warnf("rsvp api - unable to restore rsvp as params id %v could not be converted due to %v", c.Param("id"), err)
+			c.AbortWithStatus(http.StatusBadRequest)
+			return
+		}
+
+		restoredRSVP, err := rsvpService.RestoreRSVPByID(rsvpID)
+		if err != nil {
+			switch err.(type) {
+			case rsvp.RSVPNotFoundError:
+				c.AbortWithStatus(http.StatusNotFound)
+				return
+			case serviceErrors.ValidationError:
+				ctxlogger.Warnf("rsvp api - unable to restore rsvp due to validation error %v", err)
+				c.JSON(domain.NewCustomBadRequestError(err.Error()))
+				return
+			}
+
+			ctxlogger.Errorf("rsvp api - unable to restore rsvp due to %v", err)
+			c.AbortWithStatus(http.StatusInternalServerError)
+			return
+		}
+
+		c.JSON(http.StatusOK, restoredRSVP)
+		return
+	}
+}
+
+func checkInRSVP(api *API) func(c *gin.Context) {
+	return func(c *gin.Context) {
+		ctxlogger := logrus.New()
+		ctx := context.Background()
+		ctx = context.WithValue(ctx, "logger", ctxlogger)
+		ctx = contextWithActor(ctx, c)
+
+		rsvpService := api.RSVPServiceFactory(ctx)
+
+		rsvpIDStr := c.Param("id")
+		rsvpID, err := strconv.ParseInt(rsvpIDStr, 10, 64)
+		if err != nil {
+			ctxlogger.Warnf("rsvp api - unable to check in rsvp as params id %v could not be converted due to %v", c.Param("id"), err)
+			c.AbortWithStatus(http.StatusBadRequest)
+			return
+		}
+
+		checkedInRSVP, err := rsvpService.CheckInRSVPByID(rsvpID)
+		if err != nil {
+			switch err.(type) {
+			case rsvp.RSVPNotFoundError:
+				c.AbortWithStatus(http.StatusNotFound)
+				return
+			}
+
+			ctxlogger.Errorf("rsvp api - unable to check in rsvp due to %v", err)
+			c.AbortWithStatus(http.StatusInternalServerError)
+			return
+		}
+
+		c.JSON(http.StatusOK, checkedInRSVP)
+		return
+	}
+}
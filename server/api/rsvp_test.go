@@ -4,6 +4,8 @@ import (
 	"bytes"
 	"encoding/json"
 	"net/http"
+	"net/http/httptest"
+	"time"
 
 	"github.com/rawfish-dev/rsvp-starter/server/api"
 	"github.com/rawfish-dev/rsvp-starter/server/config"
@@ -11,6 +13,7 @@ import (
 	"github.com/rawfish-dev/rsvp-starter/server/interfaces"
 	"github.com/rawfish-dev/rsvp-starter/server/mock"
 	serviceErrors "github.com/rawfish-dev/rsvp-starter/server/services/errors"
+	"github.com/rawfish-dev/rsvp-starter/server/services/invitation"
 	. "github.com/rawfish-dev/rsvp-starter/server/services/rsvp"
 
 	"github.com/golang/mock/gomock"
@@ -33,6 +36,8 @@ var _ = Describe("RSVP", func() {
 		testAPI.SessionServiceFactory = func(ctx context.Context) interfaces.SessionServiceProvider {
 			mockSessionService := mock_interfaces.NewMockSessionServiceProvider(ctrl)
 			mockSessionService.EXPECT().IsSessionValid("").Return(true, nil)
+			mockSessionService.EXPECT().Username("").Return("admin", nil)
+			mockSessionService.EXPECT().Role("").Return(domain.RoleOwner, nil)
 
 			return mockSessionService
 		}
@@ -74,6 +79,8 @@ var _ = Describe("RSVP", func() {
 
 		It("should return 200 OK and create a rsvp given valid values", func() {
 			testAPI.RSVPServiceFactory = func(ctx context.Context) interfaces.RSVPServiceProvider {
+				Expect(ctx.Value(domain.ContextActor)).To(Equal(domain.NewAdminActor("admin")))
+
 				mockRSVPService := mock_interfaces.NewMockRSVPServiceProvider(ctrl)
 				mockRSVPService.EXPECT().CreateRSVP(&createRSVPreq).
 					Return(&rsvp, nil)
@@ -177,27 +184,55 @@ var _ = Describe("RSVP", func() {
 				},
 			}
 
+			rsvpList := &domain.RSVPList{
+				ListResponse: domain.ListResponse{Page: 1, PageSize: domain.DefaultPageSize, Total: 3},
+				RSVPs:        rsvps,
+			}
+
+			attending := true
+			noSpecialDiet := false
+			updatedSince := time.Date(2017, 12, 11, 0, 0, 0, 0, time.UTC)
+
 			testAPI.RSVPServiceFactory = func(ctx context.Context) interfaces.RSVPServiceProvider {
 				mockRSVPService := mock_interfaces.NewMockRSVPServiceProvider(ctrl)
-				mockRSVPService.EXPECT().ListRSVPs().
-					Return(rsvps, nil)
+				mockRSVPService.EXPECT().ListRSVPs(&domain.RSVPListRequest{
+					Attending:    &attending,
+					SpecialDiet:  &noSpecialDiet,
+					UpdatedSince: &updatedSince,
+				}).Return(rsvpList, nil)
 
 				return mockRSVPService
 			}
 
-			responseBytes := HitEndpoint(testAPI, "GET", "/api/rsvps", nil, http.StatusOK)
+			responseBytes := HitEndpoint(testAPI, "GET", "/api/rsvps?attending=true&specialDiet=false&updatedSince=2017-12-11T00:00:00Z", nil, http.StatusOK)
 
-			var rsvpList []domain.RSVP
-			err := json.Unmarshal(responseBytes, &rsvpList)
+			var returnedRSVPList domain.RSVPList
+			err := json.Unmarshal(responseBytes, &returnedRSVPList)
 			Expect(err).ToNot(HaveOccurred())
 
-			Expect(rsvpList).To(Equal(rsvps))
+			Expect(returnedRSVPList).To(Equal(*rsvpList))
+		})
+
+		It("should return 400 Bad Request when a query parameter is malformed", func() {
+			testAPI.RSVPServiceFactory = func(ctx context.Context) interfaces.RSVPServiceProvider {
+				mockRSVPService := mock_interfaces.NewMockRSVPServiceProvider(ctrl)
+				mockRSVPService.EXPECT().ListRSVPs(gomock.Any()).Times(0)
+
+				return mockRSVPService
+			}
+
+			responseBytes := HitEndpoint(testAPI, "GET", "/api/rsvps?attending=maybe", nil, http.StatusBadRequest)
+
+			var badRequestError domain.CustomBadRequestError
+			err := json.Unmarshal(responseBytes, &badRequestError)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(badRequestError.Error).To(Equal("attending must be true or false"))
 		})
 
 		It("should return 500 Internal Server Error when an unknown service error occurs", func() {
 			testAPI.RSVPServiceFactory = func(ctx context.Context) interfaces.RSVPServiceProvider {
 				mockRSVPService := mock_interfaces.NewMockRSVPServiceProvider(ctrl)
-				mockRSVPService.EXPECT().ListRSVPs().
+				mockRSVPService.EXPECT().ListRSVPs(gomock.Any()).
 					Return(nil, serviceErrors.NewGeneralServiceError())
 
 				return mockRSVPService
@@ -223,6 +258,7 @@ var _ = Describe("RSVP", func() {
 				},
 				ID:                  1,
 				InvitationPrivateID: "some-private-id-3",
+				Version:             1,
 			}
 		})
 
@@ -289,6 +325,34 @@ var _ = Describe("RSVP", func() {
 			Expect(badRequestError.Error).To(Equal("some validation error"))
 		})
 
+		It("should return 409 Conflict and the current rsvp when the version is stale", func() {
+			currentRSVP := &domain.RSVP{
+				BaseRSVP:            updateRSVPReq.BaseRSVP,
+				ID:                  updateRSVPReq.ID,
+				InvitationPrivateID: updateRSVPReq.InvitationPrivateID,
+				Completed:           true,
+				Version:             2,
+			}
+
+			testAPI.RSVPServiceFactory = func(ctx context.Context) interfaces.RSVPServiceProvider {
+				mockRSVPService := mock_interfaces.NewMockRSVPServiceProvider(ctrl)
+				mockRSVPService.EXPECT().UpdateRSVP(&updateRSVPReq).
+					Return(nil, NewRSVPVersionConflictError(currentRSVP))
+
+				return mockRSVPService
+			}
+
+			reqBytes, err := json.Marshal(updateRSVPReq)
+			Expect(err).ToNot(HaveOccurred())
+
+			responseBytes := HitEndpoint(testAPI, "PUT", "/api/rsvps/1", bytes.NewBuffer(reqBytes), http.StatusConflict)
+
+			var rsvp domain.RSVP
+			err = json.Unmarshal(responseBytes, &rsvp)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(rsvp).To(Equal(*currentRSVP))
+		})
+
 		It("should return 500 Internal Server Error when an unknown service error occurs", func() {
 			testAPI.RSVPServiceFactory = func(ctx context.Context) interfaces.RSVPServiceProvider {
 				mockRSVPService := mock_interfaces.NewMockRSVPServiceProvider(ctrl)
@@ -353,4 +417,257 @@ var _ = Describe("RSVP", func() {
 			HitEndpoint(testAPI, "DELETE", "/api/rsvps/1", nil, http.StatusInternalServerError)
 		})
 	})
+
+	Context("restoring", func() {
+
+		It("should return 200 OK and the restored rsvp given a valid id", func() {
+			restoredRSVP := &domain.RSVP{ID: 1, InvitationPrivateID: "some-private-id", Completed: true}
+
+			testAPI.RSVPServiceFactory = func(ctx context.Context) interfaces.RSVPServiceProvider {
+				mockRSVPService := mock_interfaces.NewMockRSVPServiceProvider(ctrl)
+				mockRSVPService.EXPECT().RestoreRSVPByID(int64(1)).Return(restoredRSVP, nil)
+
+				return mockRSVPService
+			}
+
+			responseBytes := HitEndpoint(testAPI, "POST", "/api/rsvps/1/restore", nil, http.StatusOK)
+
+			var returnedRSVP domain.RSVP
+			err := json.Unmarshal(responseBytes, &returnedRSVP)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(returnedRSVP).To(Equal(*restoredRSVP))
+		})
+
+		It("should return 400 Bad Request if the id is not valid", func() {
+			testAPI.RSVPServiceFactory = func(ctx context.Context) interfaces.RSVPServiceProvider {
+				return mock_interfaces.NewMockRSVPServiceProvider(ctrl)
+			}
+
+			HitEndpoint(testAPI, "POST", "/api/rsvps/abc/restore", nil, http.StatusBadRequest)
+		})
+
+		It("should return 400 Bad Request when a validation error occurs", func() {
+			testAPI.RSVPServiceFactory = func(ctx context.Context) interfaces.RSVPServiceProvider {
+				mockRSVPService := mock_interfaces.NewMockRSVPServiceProvider(ctrl)
+				mockRSVPService.EXPECT().RestoreRSVPByID(int64(1)).Return(nil, serviceErrors.NewValidationError([]string{"invitation of the rsvp must be restored first"}))
+
+				return mockRSVPService
+			}
+
+			responseBytes := HitEndpoint(testAPI, "POST", "/api/rsvps/1/restore", nil, http.StatusBadRequest)
+			Expect(string(responseBytes)).To(ContainSubstring("invitation of the rsvp must be restored first"))
+		})
+
+		It("should return 404 Not Found if the id is not in the trash", func() {
+			testAPI.RSVPServiceFactory = func(ctx context.Context) interfaces.RSVPServiceProvider {
+				mockRSVPService := mock_interfaces.NewMockRSVPServiceProvider(ctrl)
+				mockRSVPService.EXPECT().RestoreRSVPByID(int64(1)).Return(nil, NewRSVPNotFoundError())
+
+				return mockRSVPService
+			}
+
+			HitEndpoint(testAPI, "POST", "/api/rsvps/1/restore", nil, http.StatusNotFound)
+		})
+
+		It("should return 500 Internal Server Error when an unknown service error occurs", func() {
+			testAPI.RSVPServiceFactory = func(ctx context.Context) interfaces.RSVPServiceProvider {
+				mockRSVPService := mock_interfaces.NewMockRSVPServiceProvider(ctrl)
+				mockRSVPService.EXPECT().RestoreRSVPByID(int64(1)).Return(nil, serviceErrors.NewGeneralServiceError())
+
+				return mockRSVPService
+			}
+
+			HitEndpoint(testAPI, "POST", "/api/rsvps/1/restore", nil, http.StatusInternalServerError)
+		})
+	})
+
+	Context("checking in", func() {
+
+		It("should return 200 OK and the checked in rsvp given a valid id", func() {
+			checkedInRSVP := &domain.RSVP{ID: 1, InvitationPrivateID: "some-private-id", Completed: true, CheckedInAt: "2026-10-17T19:00:00Z"}
+
+			testAPI.RSVPServiceFactory = func(ctx context.Context) interfaces.RSVPServiceProvider {
+				mockRSVPService := mock_interfaces.NewMockRSVPServiceProvider(ctrl)
+				mockRSVPService.EXPECT().CheckInRSVPByID(int64(1)).Return(checkedInRSVP, nil)
+
+				return mockRSVPService
+			}
+
+			responseBytes := HitEndpoint(testAPI, "POST", "/api/rsvps/1/check-in", nil, http.StatusOK)
+
+			var returnedRSVP domain.RSVP
+			err := json.Unmarshal(responseBytes, &returnedRSVP)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(returnedRSVP).To(Equal(*checkedInRSVP))
+		})
+
+		It("should return 400 Bad Request if the id is not valid", func() {
+			testAPI.RSVPServiceFactory = func(ctx context.Context) interfaces.RSVPServiceProvider {
+				return mock_interfaces.NewMockRSVPServiceProvider(ctrl)
+			}
+
+			HitEndpoint(testAPI, "POST", "/api/rsvps/abc/check-in", nil, http.StatusBadRequest)
+		})
+
+		It("should return 404 Not Found if the id cannot be found", func() {
+			testAPI.RSVPServiceFactory = func(ctx context.Context) interfaces.RSVPServiceProvider {
+				mockRSVPService := mock_interfaces.NewMockRSVPServiceProvider(ctrl)
+				mockRSVPService.EXPECT().CheckInRSVPByID(int64(1)).Return(nil, NewRSVPNotFoundError())
+
+				return mockRSVPService
+			}
+
+			HitEndpoint(testAPI, "POST", "/api/rsvps/1/check-in", nil, http.StatusNotFound)
+		})
+
+		It("should return 500 Internal Server Error when an unknown service error occurs", func() {
+			testAPI.RSVPServiceFactory = func(ctx context.Context) interfaces.RSVPServiceProvider {
+				mockRSVPService := mock_interfaces.NewMockRSVPServiceProvider(ctrl)
+				mockRSVPService.EXPECT().CheckInRSVPByID(int64(1)).Return(nil, serviceErrors.NewGeneralServiceError())
+
+				return mockRSVPService
+			}
+
+			HitEndpoint(testAPI, "POST", "/api/rsvps/1/check-in", nil, http.StatusInternalServerError)
+		})
+	})
+})
+
+var _ = Describe("Guest RSVP", func() {
+
+	var ctrl *gomock.Controller
+	var testAPI *api.API
+	var mockInvitationService *mock_interfaces.MockInvitationServiceProvider
+	var mockGuestThrottle *mock_interfaces.MockLoginThrottleServiceProvider
+	var guestInvitation *domain.Invitation
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+
+		guestInvitation = &domain.Invitation{
+			BaseInvitation: domain.BaseInvitation{
+				Greeting:          "Mitten Lin",
+				MaximumGuestCount: 2,
+				MobilePhoneNumber: "91231234",
+			},
+			ID:        1,
+			PrivateID: "some-private-id",
+		}
+		mockInvitationService = mock_interfaces.NewMockInvitationServiceProvider(ctrl)
+		mockGuestThrottle = mock_interfaces.NewMockLoginThrottleServiceProvider(ctrl)
+
+		testConfig := config.LoadConfig()
+		testAPI = api.NewAPI(testConfig)
+
+		testAPI.SessionServiceFactory = func(ctx context.Context) interfaces.SessionServiceProvider {
+			return mock_interfaces.NewMockSessionServiceProvider(ctrl)
+		}
+		testAPI.InvitationServiceFactory = func(ctx context.Context) interfaces.InvitationServiceProvider {
+			return mockInvitationService
+		}
+		testAPI.GuestThrottleFactory = func(ctx context.Context) interfaces.LoginThrottleServiceProvider {
+			return mockGuestThrottle
+		}
+
+		testAPI.InitRoutes()
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	hitWithPhoneNumber := func(method, url, mobilePhoneNumber string) *httptest.ResponseRecorder {
+		request, err := http.NewRequest(method, url, nil)
+		Expect(err).ToNot(HaveOccurred())
+		request.Header.Set("X-Guest-Phone-Number", mobilePhoneNumber)
+
+		response := httptest.NewRecorder()
+		testAPI.Router.ServeHTTP(response, request)
+
+		return response
+	}
+
+	Context("retrieval", func() {
+
+		It("should return 200 OK and a new rsvp filled in from the invitation if the guests have not replied", func() {
+			mockInvitationService.EXPECT().AuthorizeGuest("some-private-id", "").Return(guestInvitation, nil)
+			testAPI.RSVPServiceFactory = func(ctx context.Context) interfaces.RSVPServiceProvider {
+				mockRSVPService := mock_interfaces.NewMockRSVPServiceProvider(ctrl)
+				mockRSVPService.EXPECT().RetrievePrivateRSVP("some-private-id").Return(nil, NewRSVPNotFoundError())
+
+				return mockRSVPService
+			}
+
+			responseBytes := HitEndpoint(testAPI, "GET", "/api/rsvps/some-private-id", nil, http.StatusOK)
+
+			var privateRSVP domain.RSVP
+			Expect(json.Unmarshal(responseBytes, &privateRSVP)).To(Succeed())
+			Expect(privateRSVP.FullName).To(Equal("Mitten Lin"))
+			Expect(privateRSVP.GuestCount).To(Equal(2))
+			Expect(privateRSVP.InvitationPrivateID).To(Equal("some-private-id"))
+			Expect(privateRSVP.Completed).To(BeFalse())
+		})
+
+		It("should return 404 Not Found for unknown links", func() {
+			mockInvitationService.EXPECT().AuthorizeGuest("some-private-id", "").Return(nil, invitation.NewInvitationNotFoundError())
+
+			HitEndpoint(testAPI, "GET", "/api/rsvps/some-private-id", nil, http.StatusNotFound)
+		})
+
+		It("should return 410 Gone for links that were revoked, expired or rotated", func() {
+			mockInvitationService.EXPECT().AuthorizeGuest("some-private-id", "").Return(nil, invitation.NewInvitationLinkGoneError())
+
+			HitEndpoint(testAPI, "GET", "/api/rsvps/some-private-id", nil, http.StatusGone)
+		})
+
+		It("should return 403 Forbidden asking for the mobile phone number without counting a failure", func() {
+			mockInvitationService.EXPECT().AuthorizeGuest("some-private-id", "").Return(nil, invitation.NewInvitationPhoneConfirmationRequiredError())
+
+			responseBytes := HitEndpoint(testAPI, "GET", "/api/rsvps/some-private-id", nil, http.StatusForbidden)
+
+			var phoneConfirmationRequired domain.PhoneConfirmationRequired
+			Expect(json.Unmarshal(responseBytes, &phoneConfirmationRequired)).To(Succeed())
+			Expect(phoneConfirmationRequired.PhoneConfirmationRequired).To(BeTrue())
+		})
+
+		It("should count an incorrect mobile phone number as a failed login against the link", func() {
+			mockGuestThrottle.EXPECT().ReserveAttempt("guest:some-private-id", gomock.Any()).Return(time.Duration(0), nil)
+			mockInvitationService.EXPECT().AuthorizeGuest("some-private-id", "98769876").Return(nil, invitation.NewInvitationPhoneConfirmationRequiredError())
+			mockGuestThrottle.EXPECT().RecordFailure("guest:some-private-id", gomock.Any()).Return(nil)
+
+			response := hitWithPhoneNumber("GET", "/api/rsvps/some-private-id", "98769876")
+			Expect(response.Code).To(Equal(http.StatusForbidden))
+		})
+
+		It("should return 429 Too Many Requests once the link has had too many incorrect mobile phone numbers", func() {
+			mockGuestThrottle.EXPECT().ReserveAttempt("guest:some-private-id", gomock.Any()).Return(time.Minute, nil)
+
+			response := hitWithPhoneNumber("GET", "/api/rsvps/some-private-id", "98769876")
+			Expect(response.Code).To(Equal(http.StatusTooManyRequests))
+			Expect(response.Header().Get("Retry-After")).To(Equal("60"))
+		})
+
+		It("should return the rsvp once the mobile phone number is confirmed", func() {
+			guestInvitation.RequirePhoneConfirmation = true
+
+			mockGuestThrottle.EXPECT().ReserveAttempt("guest:some-private-id", gomock.Any()).Return(time.Duration(0), nil)
+			mockInvitationService.EXPECT().AuthorizeGuest("some-private-id", "91231234").Return(guestInvitation, nil)
+			mockGuestThrottle.EXPECT().RecordSuccess("guest:some-private-id", gomock.Any()).Return(nil)
+			testAPI.RSVPServiceFactory = func(ctx context.Context) interfaces.RSVPServiceProvider {
+				mockRSVPService := mock_interfaces.NewMockRSVPServiceProvider(ctrl)
+				mockRSVPService.EXPECT().RetrievePrivateRSVP("some-private-id").Return(&domain.RSVP{ID: 1, InvitationPrivateID: "some-private-id"}, nil)
+
+				return mockRSVPService
+			}
+
+			response := hitWithPhoneNumber("GET", "/api/rsvps/some-private-id", "91231234")
+			Expect(response.Code).To(Equal(http.StatusOK))
+
+			var privateRSVP domain.RSVP
+			Expect(json.Unmarshal(response.Body.Bytes(), &privateRSVP)).To(Succeed())
+			Expect(privateRSVP.Completed).To(BeTrue())
+		})
+	})
 })
@@ -0,0 +1,50 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/rawfish-dev/rsvp-starter/server/domain"
+	serviceErrors "github.com/rawfish-dev/rsvp-starter/server/services/errors"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/gin-gonic/gin"
+	"golang.org/x/net/context"
+)
+
+func searchGuests(api *API) func(c *gin.Context) {
+	return func(c *gin.Context) {
+		ctxlogger := logrus.New()
+		ctx := context.Background()
+		ctx = context.WithValue(ctx, "logger", ctxlogger)
+
+		searchService := api.SearchServiceFactory(ctx)
+
+		query := &listQuery{c: c}
+		searchRequest := domain.SearchRequest{
+			Query: c.Query("q"),
+			Limit: query.int("limit"),
+		}
+		if query.err != nil {
+			ctxlogger.Warnf("search api - unable to search due to invalid query %v", query.err)
+			c.JSON(domain.NewCustomBadRequestError(query.err.Error()))
+			return
+		}
+
+		searchResults, err := searchService.Search(&searchRequest)
+		if err != nil {
+			switch err.(type) {
+			case serviceErrors.ValidationError:
+				ctxlogger.Warnf("search api - unable to search due to validation error %v", err)
+				c.JSON(domain.NewCustomBadRequestError(err.Error()))
+				return
+			}
+
+			ctxlogger.Errorf("search api - unable to search due to %v", err)
+			c.AbortWithStatus(http.StatusInternalServerError)
+			return
+		}
+
+		c.JSON(http.StatusOK, searchResults)
+		return
+	}
+}
@@ -0,0 +1,152 @@
+package api_test
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/rawfish-dev/rsvp-starter/server/api"
+	"github.com/rawfish-dev/rsvp-starter/server/config"
+	"github.com/rawfish-dev/rsvp-starter/server/domain"
+	"github.com/rawfish-dev/rsvp-starter/server/interfaces"
+	"github.com/rawfish-dev/rsvp-starter/server/mock"
+	serviceErrors "github.com/rawfish-dev/rsvp-starter/server/services/errors"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"golang.org/x/net/context"
+)
+
+var _ = Describe("Search", func() {
+
+	var ctrl *gomock.Controller
+	var testAPI *api.API
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+
+		testConfig := config.LoadConfig()
+		testAPI = api.NewAPI(testConfig)
+
+		testAPI.SessionServiceFactory = func(ctx context.Context) interfaces.SessionServiceProvider {
+			mockSessionService := mock_interfaces.NewMockSessionServiceProvider(ctrl)
+			mockSessionService.EXPECT().IsSessionValid("").Return(true, nil)
+			mockSessionService.EXPECT().Username("").Return("admin", nil)
+			mockSessionService.EXPECT().Role("").Return(domain.RoleOwner, nil)
+
+			return mockSessionService
+		}
+
+		testAPI.InitRoutes()
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	Context("searching", func() {
+
+		It("should return 200 OK and the ranked results", func() {
+			searchResults := &domain.SearchResults{
+				Query: "4421",
+				Results: []domain.SearchResult{
+					{
+						Type: domain.InvitationSearchResult,
+						Rank: 0.6,
+						Invitation: &domain.Invitation{
+							BaseInvitation: domain.BaseInvitation{
+								CategoryID:        1,
+								Greeting:          "Cousin Ah Boy",
+								MaximumGuestCount: 2,
+								MobilePhoneNumber: "+6591234421",
+							},
+							ID:        1,
+							PrivateID: "some-private-id",
+							Status:    domain.Sent,
+							UpdatedAt: "2017-12-13",
+							Version:   1,
+						},
+					},
+					{
+						Type: domain.RSVPSearchResult,
+						Rank: 0.4,
+						RSVP: &domain.RSVP{
+							BaseRSVP: domain.BaseRSVP{
+								FullName:          "Ah Girl",
+								Attending:         true,
+								GuestCount:        1,
+								MobilePhoneNumber: "94421000",
+							},
+							ID:                  2,
+							InvitationPrivateID: "other-private-id",
+							Completed:           true,
+							UpdatedAt:           "2017-12-13",
+							Version:             1,
+						},
+					},
+				},
+			}
+
+			testAPI.SearchServiceFactory = func(ctx context.Context) interfaces.SearchServiceProvider {
+				mockSearchService := mock_interfaces.NewMockSearchServiceProvider(ctrl)
+				mockSearchService.EXPECT().Search(&domain.SearchRequest{Query: "4421", Limit: 10}).
+					Return(searchResults, nil)
+
+				return mockSearchService
+			}
+
+			responseBytes := HitEndpoint(testAPI, "GET", "/api/search?q=4421&limit=10", nil, http.StatusOK)
+
+			var returnedSearchResults domain.SearchResults
+			err := json.Unmarshal(responseBytes, &returnedSearchResults)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(returnedSearchResults).To(Equal(*searchResults))
+		})
+
+		It("should return 400 Bad Request when the limit is malformed", func() {
+			testAPI.SearchServiceFactory = func(ctx context.Context) interfaces.SearchServiceProvider {
+				mockSearchService := mock_interfaces.NewMockSearchServiceProvider(ctrl)
+				mockSearchService.EXPECT().Search(gomock.Any()).Times(0)
+
+				return mockSearchService
+			}
+
+			responseBytes := HitEndpoint(testAPI, "GET", "/api/search?q=tan&limit=all", nil, http.StatusBadRequest)
+
+			var badRequestError domain.CustomBadRequestError
+			err := json.Unmarshal(responseBytes, &badRequestError)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(badRequestError.Error).To(Equal("limit must be a number"))
+		})
+
+		It("should return 400 Bad Request when validation fails", func() {
+			testAPI.SearchServiceFactory = func(ctx context.Context) interfaces.SearchServiceProvider {
+				mockSearchService := mock_interfaces.NewMockSearchServiceProvider(ctrl)
+				mockSearchService.EXPECT().Search(&domain.SearchRequest{Query: "a"}).
+					Return(nil, serviceErrors.NewValidationError([]string{"search query must be between 2 to 100 characters"}))
+
+				return mockSearchService
+			}
+
+			responseBytes := HitEndpoint(testAPI, "GET", "/api/search?q=a", nil, http.StatusBadRequest)
+
+			var badRequestError domain.CustomBadRequestError
+			err := json.Unmarshal(responseBytes, &badRequestError)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(badRequestError.Error).To(Equal("search query must be between 2 to 100 characters"))
+		})
+
+		It("should return 500 Internal Server Error when an unknown service error occurs", func() {
+			testAPI.SearchServiceFactory = func(ctx context.Context) interfaces.SearchServiceProvider {
+				mockSearchService := mock_interfaces.NewMockSearchServiceProvider(ctrl)
+				mockSearchService.EXPECT().Search(gomock.Any()).
+					Return(nil, serviceErrors.NewGeneralServiceError())
+
+				return mockSearchService
+			}
+
+			HitEndpoint(testAPI, "GET", "/api/search?q=tan", nil, http.StatusInternalServerError)
+		})
+	})
+})
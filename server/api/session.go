@@ -1,10 +1,16 @@
 package api
 
 import (
+	"fmt"
+	"math"
+	"net"
 	"net/http"
 	"strings"
 
 	"github.com/rawfish-dev/rsvp-starter/server/domain"
+	"github.com/rawfish-dev/rsvp-starter/server/interfaces"
+	"github.com/rawfish-dev/rsvp-starter/server/services/security"
+	"github.com/rawfish-dev/rsvp-starter/server/services/session"
 
 	"github.com/Sirupsen/logrus"
 	"github.com/gin-gonic/gin"
@@ -18,7 +24,10 @@ func createSession(api *API) func(c *gin.Context) {
 		ctx = context.WithValue(ctx, "logger", ctxlogger)
 
 		securityService := api.SecurityServiceFactory(ctx)
+		captchaVerifier := api.CaptchaVerifierFactory(ctx)
+		loginThrottle := api.LoginThrottleFactory(ctx)
 		sessionService := api.SessionServiceFactory(ctx)
+		userService := api.UserServiceFactory(ctx)
 
 		var sessionCreateRequest domain.SessionCreateRequest
 		err := c.BindJSON(&sessionCreateRequest)
@@ -28,8 +37,14 @@ func createSession(api *API) func(c *gin.Context) {
 			return
 		}
 		sessionCreateRequest.Username = strings.ToLower(sessionCreateRequest.Username)
+		clientIP := requestClientIP(api, c)
 
-		if !securityService.VerifyReCAPTCHA(sessionCreateRequest.ReCAPTCHAToken) {
+		if isLoginThrottled(c, ctxlogger, loginThrottle, sessionCreateRequest.Username, clientIP) {
+			return
+		}
+
+		if !captchaVerifier.Verify(sessionCreateRequest.ReCAPTCHAToken, clientIP) {
+			releaseLoginAttempt(ctxlogger, loginThrottle, sessionCreateRequest.Username, clientIP)
 			c.AbortWithStatus(http.StatusBadRequest)
 			return
 		}
@@ -37,23 +52,258 @@ func createSession(api *API) func(c *gin.Context) {
 		valid := securityService.ValidateCredentials(sessionCreateRequest.Username, sessionCreateRequest.Password)
 		if !valid {
 			ctxlogger.Warn("session api - unable to create new session due to unrecognised credentials")
+
+			err = loginThrottle.RecordFailure(sessionCreateRequest.Username, clientIP)
+			if err != nil {
+				ctxlogger.Errorf("session api - unable to record failed login due to %v", err)
+			}
+
 			c.AbortWithStatus(http.StatusUnauthorized)
 			return
 		}
 
-		authToken, err := sessionService.CreateWithExpiry(sessionCreateRequest.Username)
+		user, err := userService.RetrieveUserByUsername(sessionCreateRequest.Username)
+		if err != nil {
+			ctxlogger.Errorf("session api - unable to create new session while retrieving user due to %v", err)
+			releaseLoginAttempt(ctxlogger, loginThrottle, sessionCreateRequest.Username, clientIP)
+			c.AbortWithStatus(http.StatusInternalServerError)
+			return
+		}
+
+		// Failed logins are only forgotten once the code checks out as well, otherwise the password
+		// could be used to keep guessing codes
+		if user.TOTPEnabled {
+			releaseLoginAttempt(ctxlogger, loginThrottle, sessionCreateRequest.Username, clientIP)
+
+			preAuthTokenResponse, err := securityService.GeneratePreAuthToken(user.Username)
+			if err != nil {
+				ctxlogger.Errorf("session api - unable to create new session while generating pre-auth token due to %v", err)
+				c.AbortWithStatus(http.StatusInternalServerError)
+				return
+			}
+
+			c.JSON(http.StatusOK, preAuthTokenResponse)
+			return
+		}
+
+		err = loginThrottle.RecordSuccess(sessionCreateRequest.Username, clientIP)
+		if err != nil {
+			ctxlogger.Errorf("session api - unable to clear failed logins due to %v", err)
+		}
+
+		respondWithNewSession(c, ctxlogger, sessionService, user)
+		return
+	}
+}
+
+// verifyTwoFactor is the second step of logging in for users with two-factor authentication. It
+// exchanges the pre-auth token from the first step and a code for a session.
+func verifyTwoFactor(api *API) func(c *gin.Context) {
+	return func(c *gin.Context) {
+		ctxlogger := logrus.New()
+		ctx := context.Background()
+		ctx = context.WithValue(ctx, "logger", ctxlogger)
+
+		securityService := api.SecurityServiceFactory(ctx)
+		loginThrottle := api.LoginThrottleFactory(ctx)
+		sessionService := api.SessionServiceFactory(ctx)
+		userService := api.UserServiceFactory(ctx)
+
+		var twoFactorRequest domain.SessionTwoFactorRequest
+		err := c.BindJSON(&twoFactorRequest)
+		if err != nil {
+			ctxlogger.Errorf("session api - unable to verify two-factor code while unwrapping request due to %v", err)
+			c.AbortWithStatus(http.StatusBadRequest)
+			return
+		}
+
+		username, err := securityService.ParsePreAuthToken(twoFactorRequest.PreAuthToken)
+		if err != nil {
+			switch err.(type) {
+			case security.PreAuthTokenInvalidError:
+				ctxlogger.Warn("session api - unable to verify two-factor code due to an invalid pre-auth token")
+				c.AbortWithStatus(http.StatusUnauthorized)
+				return
+			}
+
+			ctxlogger.Errorf("session api - unable to verify two-factor code due to %v", err)
+			c.AbortWithStatus(http.StatusInternalServerError)
+			return
+		}
+		clientIP := requestClientIP(api, c)
+
+		if isLoginThrottled(c, ctxlogger, loginThrottle, username, clientIP) {
+			return
+		}
+
+		valid, err := securityService.ValidateTwoFactorCode(username, twoFactorRequest.Code)
 		if err != nil {
-			ctxlogger.Errorf("session api - unable to create new session due to %v", err)
+			ctxlogger.Errorf("session api - unable to verify two-factor code due to %v", err)
+			releaseLoginAttempt(ctxlogger, loginThrottle, username, clientIP)
 			c.AbortWithStatus(http.StatusInternalServerError)
 			return
 		}
+		if !valid {
+			ctxlogger.Warn("session api - unable to create new session due to an incorrect two-factor code")
+
+			err = loginThrottle.RecordFailure(username, clientIP)
+			if err != nil {
+				ctxlogger.Errorf("session api - unable to record failed login due to %v", err)
+			}
 
-		sessionCreateResponse := &domain.SessionCreateResponse{
-			Username:  strings.Title(sessionCreateRequest.Username),
-			AuthToken: authToken,
+			c.AbortWithStatus(http.StatusUnauthorized)
+			return
 		}
 
-		c.JSON(http.StatusOK, sessionCreateResponse)
+		err = loginThrottle.RecordSuccess(username, clientIP)
+		if err != nil {
+			ctxlogger.Errorf("session api - unable to clear failed logins due to %v", err)
+		}
+
+		user, err := userService.RetrieveUserByUsername(username)
+		if err != nil {
+			ctxlogger.Errorf("session api - unable to create new session while retrieving user due to %v", err)
+			c.AbortWithStatus(http.StatusInternalServerError)
+			return
+		}
+
+		respondWithNewSession(c, ctxlogger, sessionService, user)
+		return
+	}
+}
+
+// isLoginThrottled responds with 429 and when to retry if the username or client IP has failed to log
+// in too often. Otherwise the attempt is reserved and has to be settled once it is known how it went.
+func isLoginThrottled(c *gin.Context, ctxlogger interfaces.Logger, loginThrottle interfaces.LoginThrottleServiceProvider, username, clientIP string) bool {
+	wait, err := loginThrottle.ReserveAttempt(username, clientIP)
+	if err != nil {
+		ctxlogger.Errorf("session api - unable to check failed logins due to %v", err)
+		c.AbortWithStatus(http.StatusInternalServerError)
+		return true
+	}
+
+	if wait > 0 {
+		ctxlogger.Warnf("session api - unable to create new session for %v from %v for another %v due to failed logins", username, clientIP, wait)
+		c.Header("Retry-After", fmt.Sprintf("%v", int(math.Ceil(wait.Seconds()))))
+		c.AbortWithStatus(http.StatusTooManyRequests)
+		return true
+	}
+
+	return false
+}
+
+// releaseLoginAttempt gives back an attempt that did not get as far as checking the credentials
+func releaseLoginAttempt(ctxlogger interfaces.Logger, loginThrottle interfaces.LoginThrottleServiceProvider, username, clientIP string) {
+	err := loginThrottle.ReleaseAttempt(username, clientIP)
+	if err != nil {
+		ctxlogger.Errorf("session api - unable to release login attempt due to %v", err)
+	}
+}
+
+// requestClientIP is the address the request came from. X-Forwarded-For and X-Real-Ip are only
+// believed when they were set by a trusted proxy, as anyone else can set them to dodge the login
+// throttle.
+func requestClientIP(api *API, c *gin.Context) string {
+	remoteIP, _, err := net.SplitHostPort(strings.TrimSpace(c.Request.RemoteAddr))
+	if err != nil {
+		remoteIP = strings.TrimSpace(c.Request.RemoteAddr)
+	}
+
+	if !isTrustedProxy(api.TrustedProxies, remoteIP) {
+		return remoteIP
+	}
+
+	// Each proxy appends the address it got the request from, so the client is the last address not
+	// added by one of the trusted proxies
+	forwardedIP := ""
+	forwardedFor := strings.Split(c.Request.Header.Get("X-Forwarded-For"), ",")
+	for idx := len(forwardedFor) - 1; idx >= 0; idx-- {
+		ip := strings.TrimSpace(forwardedFor[idx])
+		if net.ParseIP(ip) == nil {
+			break
+		}
+
+		forwardedIP = ip
+		if !isTrustedProxy(api.TrustedProxies, ip) {
+			break
+		}
+	}
+	if forwardedIP != "" {
+		return forwardedIP
+	}
+
+	realIP := strings.TrimSpace(c.Request.Header.Get("X-Real-Ip"))
+	if net.ParseIP(realIP) != nil {
+		return realIP
+	}
+
+	return remoteIP
+}
+
+func isTrustedProxy(trustedProxies []*net.IPNet, ipStr string) bool {
+	ip := net.ParseIP(ipStr)
+	if ip == nil {
+		return false
+	}
+
+	for _, trustedProxy := range trustedProxies {
+		if trustedProxy.Contains(ip) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func respondWithNewSession(c *gin.Context, ctxlogger interfaces.Logger, sessionService interfaces.SessionServiceProvider, user *domain.User) {
+	tokens, err := sessionService.CreateWithExpiry(user.Username, user.Role)
+	if err != nil {
+		ctxlogger.Errorf("session api - unable to create new session due to %v", err)
+		c.AbortWithStatus(http.StatusInternalServerError)
+		return
+	}
+
+	sessionCreateResponse := &domain.SessionCreateResponse{
+		SessionTokens: *tokens,
+		Username:      strings.Title(user.Username),
+		Role:          user.Role,
+	}
+
+	c.JSON(http.StatusOK, sessionCreateResponse)
+}
+
+// refreshSession is open to requests without a valid auth token since the auth token is usually the
+// thing that has expired
+func refreshSession(api *API) func(c *gin.Context) {
+	return func(c *gin.Context) {
+		ctxlogger := logrus.New()
+		ctx := context.Background()
+		ctx = context.WithValue(ctx, "logger", ctxlogger)
+
+		sessionService := api.SessionServiceFactory(ctx)
+
+		var sessionRefreshRequest domain.SessionRefreshRequest
+		err := c.BindJSON(&sessionRefreshRequest)
+		if err != nil {
+			ctxlogger.Errorf("session api - unable to refresh session while unwrapping request due to %v", err)
+			c.AbortWithStatus(http.StatusBadRequest)
+			return
+		}
+
+		tokens, err := sessionService.Refresh(sessionRefreshRequest.RefreshToken)
+		if err != nil {
+			switch err.(type) {
+			case session.RefreshTokenInvalidError:
+				c.AbortWithStatus(http.StatusUnauthorized)
+				return
+			}
+
+			ctxlogger.Errorf("session api - unable to refresh session due to %v", err)
+			c.AbortWithStatus(http.StatusInternalServerError)
+			return
+		}
+
+		c.JSON(http.StatusOK, tokens)
 		return
 	}
 }
@@ -89,3 +339,63 @@ func destroySession(api *API) func(c *gin.Context) {
 		return
 	}
 }
+
+func listSessions(api *API) func(c *gin.Context) {
+	return func(c *gin.Context) {
+		ctxlogger := logrus.New()
+		ctx := context.Background()
+		ctx = context.WithValue(ctx, "logger", ctxlogger)
+
+		sessionService := api.SessionServiceFactory(ctx)
+
+		authToken, exists := c.Get(domain.ContextAuthToken)
+		if !exists {
+			ctxlogger.Error("session api - context does not contain the auth token")
+			c.AbortWithStatus(http.StatusInternalServerError)
+			return
+		}
+
+		sessions, err := sessionService.ListSessions(authToken.(string))
+		if err != nil {
+			ctxlogger.Errorf("session api - unable to list sessions due to %v", err)
+			c.AbortWithStatus(http.StatusInternalServerError)
+			return
+		}
+
+		c.JSON(http.StatusOK, sessions)
+		return
+	}
+}
+
+func revokeSession(api *API) func(c *gin.Context) {
+	return func(c *gin.Context) {
+		ctxlogger := logrus.New()
+		ctx := context.Background()
+		ctx = context.WithValue(ctx, "logger", ctxlogger)
+
+		sessionService := api.SessionServiceFactory(ctx)
+
+		authToken, exists := c.Get(domain.ContextAuthToken)
+		if !exists {
+			ctxlogger.Error("session api - context does not contain the auth token")
+			c.AbortWithStatus(http.StatusInternalServerError)
+			return
+		}
+
+		err := sessionService.Revoke(authToken.(string), c.Param("id"))
+		if err != nil {
+			switch err.(type) {
+			case session.SessionNotFoundError:
+				c.AbortWithStatus(http.StatusNotFound)
+				return
+			}
+
+			ctxlogger.Errorf("session api - unable to revoke session due to %v", err)
+			c.AbortWithStatus(http.StatusInternalServerError)
+			return
+		}
+
+		// Should return 200 OK by default
+		return
+	}
+}
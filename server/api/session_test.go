@@ -0,0 +1,402 @@
+package api_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/rawfish-dev/rsvp-starter/server/api"
+	"github.com/rawfish-dev/rsvp-starter/server/config"
+	"github.com/rawfish-dev/rsvp-starter/server/domain"
+	"github.com/rawfish-dev/rsvp-starter/server/interfaces"
+	"github.com/rawfish-dev/rsvp-starter/server/mock"
+	serviceErrors "github.com/rawfish-dev/rsvp-starter/server/services/errors"
+	"github.com/rawfish-dev/rsvp-starter/server/services/security"
+	. "github.com/rawfish-dev/rsvp-starter/server/services/session"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"golang.org/x/net/context"
+)
+
+var _ = Describe("Session", func() {
+
+	var ctrl *gomock.Controller
+	var testAPI *api.API
+	var mockSessionService *mock_interfaces.MockSessionServiceProvider
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+
+		testConfig := config.LoadConfig()
+		testAPI = api.NewAPI(testConfig)
+
+		// The same session service checks the session and serves the request
+		mockSessionService = mock_interfaces.NewMockSessionServiceProvider(ctrl)
+		mockSessionService.EXPECT().IsSessionValid("").Return(true, nil)
+		mockSessionService.EXPECT().Username("").Return("admin", nil)
+		mockSessionService.EXPECT().Role("").Return(domain.RoleViewer, nil)
+
+		testAPI.SessionServiceFactory = func(ctx context.Context) interfaces.SessionServiceProvider {
+			return mockSessionService
+		}
+
+		testAPI.InitRoutes()
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	Context("listing", func() {
+
+		It("should return 200 OK and the sessions of the user", func() {
+			sessions := []domain.Session{
+				{ID: "first", Username: "admin", Current: true},
+				{ID: "second", Username: "admin"},
+			}
+			mockSessionService.EXPECT().ListSessions("").Return(sessions, nil)
+
+			responseBytes := HitEndpoint(testAPI, "GET", "/api/sessions", nil, http.StatusOK)
+
+			var listedSessions []domain.Session
+			Expect(json.Unmarshal(responseBytes, &listedSessions)).To(Succeed())
+			Expect(listedSessions).To(Equal(sessions))
+		})
+	})
+
+	Context("revoking", func() {
+
+		It("should return 200 OK when the session is revoked", func() {
+			mockSessionService.EXPECT().Revoke("", "second").Return(nil)
+
+			HitEndpoint(testAPI, "DELETE", "/api/sessions/second", nil, http.StatusOK)
+		})
+
+		It("should return 404 Not Found when the session does not belong to the user", func() {
+			mockSessionService.EXPECT().Revoke("", "unknown").Return(NewSessionNotFoundError())
+
+			HitEndpoint(testAPI, "DELETE", "/api/sessions/unknown", nil, http.StatusNotFound)
+		})
+	})
+})
+
+var _ = Describe("Session refresh", func() {
+
+	var ctrl *gomock.Controller
+	var testAPI *api.API
+	var mockSessionService *mock_interfaces.MockSessionServiceProvider
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+
+		testConfig := config.LoadConfig()
+		testAPI = api.NewAPI(testConfig)
+
+		// Refreshing does not go through the session middleware
+		mockSessionService = mock_interfaces.NewMockSessionServiceProvider(ctrl)
+		testAPI.SessionServiceFactory = func(ctx context.Context) interfaces.SessionServiceProvider {
+			return mockSessionService
+		}
+
+		testAPI.InitRoutes()
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	It("should return 200 OK and new tokens", func() {
+		tokens := &domain.SessionTokens{AuthToken: "new-auth-token", RefreshToken: "new-refresh-token"}
+		mockSessionService.EXPECT().Refresh("some-refresh-token").Return(tokens, nil)
+
+		reqBytes, err := json.Marshal(domain.SessionRefreshRequest{RefreshToken: "some-refresh-token"})
+		Expect(err).ToNot(HaveOccurred())
+
+		responseBytes := HitEndpoint(testAPI, "POST", "/api/sessions/refresh", bytes.NewBuffer(reqBytes), http.StatusOK)
+
+		var refreshedTokens domain.SessionTokens
+		Expect(json.Unmarshal(responseBytes, &refreshedTokens)).To(Succeed())
+		Expect(refreshedTokens).To(Equal(*tokens))
+	})
+
+	It("should return 401 Unauthorized when the refresh token is invalid", func() {
+		mockSessionService.EXPECT().Refresh("used-refresh-token").Return(nil, NewRefreshTokenInvalidError())
+
+		reqBytes, err := json.Marshal(domain.SessionRefreshRequest{RefreshToken: "used-refresh-token"})
+		Expect(err).ToNot(HaveOccurred())
+
+		HitEndpoint(testAPI, "POST", "/api/sessions/refresh", bytes.NewBuffer(reqBytes), http.StatusUnauthorized)
+	})
+
+	It("should return 500 Internal Server Error when an unknown error occurs", func() {
+		mockSessionService.EXPECT().Refresh("some-refresh-token").Return(nil, serviceErrors.NewGeneralServiceError())
+
+		reqBytes, err := json.Marshal(domain.SessionRefreshRequest{RefreshToken: "some-refresh-token"})
+		Expect(err).ToNot(HaveOccurred())
+
+		HitEndpoint(testAPI, "POST", "/api/sessions/refresh", bytes.NewBuffer(reqBytes), http.StatusInternalServerError)
+	})
+})
+
+var _ = Describe("Session creation", func() {
+
+	var ctrl *gomock.Controller
+	var testAPI *api.API
+	var mockSecurityService *mock_interfaces.MockSecurityServiceProvider
+	var mockCaptchaVerifier *mock_interfaces.MockCaptchaVerifier
+	var mockLoginThrottle *mock_interfaces.MockLoginThrottleServiceProvider
+	var mockUserService *mock_interfaces.MockUserServiceProvider
+	var mockSessionService *mock_interfaces.MockSessionServiceProvider
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+
+		testConfig := config.LoadConfig()
+		testAPI = api.NewAPI(testConfig)
+
+		// Logging in does not go through the session middleware
+		mockSecurityService = mock_interfaces.NewMockSecurityServiceProvider(ctrl)
+		testAPI.SecurityServiceFactory = func(ctx context.Context) interfaces.SecurityServiceProvider {
+			return mockSecurityService
+		}
+		mockCaptchaVerifier = mock_interfaces.NewMockCaptchaVerifier(ctrl)
+		testAPI.CaptchaVerifierFactory = func(ctx context.Context) interfaces.CaptchaVerifier {
+			return mockCaptchaVerifier
+		}
+		mockLoginThrottle = mock_interfaces.NewMockLoginThrottleServiceProvider(ctrl)
+		testAPI.LoginThrottleFactory = func(ctx context.Context) interfaces.LoginThrottleServiceProvider {
+			return mockLoginThrottle
+		}
+		mockUserService = mock_interfaces.NewMockUserServiceProvider(ctrl)
+		testAPI.UserServiceFactory = func(ctx context.Context) interfaces.UserServiceProvider {
+			return mockUserService
+		}
+		mockSessionService = mock_interfaces.NewMockSessionServiceProvider(ctrl)
+		testAPI.SessionServiceFactory = func(ctx context.Context) interfaces.SessionServiceProvider {
+			return mockSessionService
+		}
+
+		testAPI.InitRoutes()
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	loginRequest := func(password string) []byte {
+		reqBytes, err := json.Marshal(domain.SessionCreateRequest{Username: "Kevin", Password: password, ReCAPTCHAToken: "some-token"})
+		Expect(err).ToNot(HaveOccurred())
+
+		return reqBytes
+	}
+
+	It("should return 200 OK and clear failed logins given valid credentials", func() {
+		gomock.InOrder(
+			mockLoginThrottle.EXPECT().ReserveAttempt("kevin", gomock.Any()).Return(time.Duration(0), nil),
+			mockCaptchaVerifier.EXPECT().Verify("some-token", gomock.Any()).Return(true),
+			mockSecurityService.EXPECT().ValidateCredentials("kevin", "some password").Return(true),
+			mockUserService.EXPECT().RetrieveUserByUsername("kevin").Return(&domain.User{ID: 1, Username: "kevin", Role: domain.RoleOwner}, nil),
+			mockLoginThrottle.EXPECT().RecordSuccess("kevin", gomock.Any()).Return(nil),
+			mockSessionService.EXPECT().CreateWithExpiry("kevin", domain.RoleOwner).Return(&domain.SessionTokens{AuthToken: "some-auth-token"}, nil),
+		)
+
+		responseBytes := HitEndpoint(testAPI, "POST", "/api/sessions", bytes.NewBuffer(loginRequest("some password")), http.StatusOK)
+
+		var sessionCreateResponse domain.SessionCreateResponse
+		Expect(json.Unmarshal(responseBytes, &sessionCreateResponse)).To(Succeed())
+		Expect(sessionCreateResponse.AuthToken).To(Equal("some-auth-token"))
+	})
+
+	It("should return 200 OK and a pre-auth token instead of a session when two-factor authentication is enabled", func() {
+		preAuthTokenResponse := &domain.PreAuthTokenResponse{TwoFactorRequired: true, PreAuthToken: "some-pre-auth-token"}
+
+		gomock.InOrder(
+			mockLoginThrottle.EXPECT().ReserveAttempt("kevin", gomock.Any()).Return(time.Duration(0), nil),
+			mockCaptchaVerifier.EXPECT().Verify("some-token", gomock.Any()).Return(true),
+			mockSecurityService.EXPECT().ValidateCredentials("kevin", "some password").Return(true),
+			mockUserService.EXPECT().RetrieveUserByUsername("kevin").Return(&domain.User{ID: 1, Username: "kevin", TOTPEnabled: true}, nil),
+			mockLoginThrottle.EXPECT().ReleaseAttempt("kevin", gomock.Any()).Return(nil),
+			mockSecurityService.EXPECT().GeneratePreAuthToken("kevin").Return(preAuthTokenResponse, nil),
+			mockLoginThrottle.EXPECT().RecordSuccess(gomock.Any(), gomock.Any()).Times(0),
+			mockSessionService.EXPECT().CreateWithExpiry(gomock.Any(), gomock.Any()).Times(0),
+		)
+
+		responseBytes := HitEndpoint(testAPI, "POST", "/api/sessions", bytes.NewBuffer(loginRequest("some password")), http.StatusOK)
+
+		var returnedResponse domain.PreAuthTokenResponse
+		Expect(json.Unmarshal(responseBytes, &returnedResponse)).To(Succeed())
+		Expect(returnedResponse).To(Equal(*preAuthTokenResponse))
+	})
+
+	It("should return 401 Unauthorized and record the failure given invalid credentials", func() {
+		gomock.InOrder(
+			mockLoginThrottle.EXPECT().ReserveAttempt("kevin", gomock.Any()).Return(time.Duration(0), nil),
+			mockCaptchaVerifier.EXPECT().Verify("some-token", gomock.Any()).Return(true),
+			mockSecurityService.EXPECT().ValidateCredentials("kevin", "wrong password").Return(false),
+			mockLoginThrottle.EXPECT().RecordFailure("kevin", gomock.Any()).Return(nil),
+		)
+
+		HitEndpoint(testAPI, "POST", "/api/sessions", bytes.NewBuffer(loginRequest("wrong password")), http.StatusUnauthorized)
+	})
+
+	It("should return 429 Too Many Requests with when to retry while locked out", func() {
+		gomock.InOrder(
+			mockLoginThrottle.EXPECT().ReserveAttempt("kevin", gomock.Any()).Return(time.Second*90+time.Millisecond, nil),
+			mockCaptchaVerifier.EXPECT().Verify(gomock.Any(), gomock.Any()).Times(0),
+			mockSecurityService.EXPECT().ValidateCredentials(gomock.Any(), gomock.Any()).Times(0),
+		)
+
+		request, err := http.NewRequest("POST", "/api/sessions", bytes.NewBuffer(loginRequest("some password")))
+		Expect(err).ToNot(HaveOccurred())
+
+		response := httptest.NewRecorder()
+		testAPI.Router.ServeHTTP(response, request)
+
+		Expect(response.Code).To(Equal(http.StatusTooManyRequests))
+		Expect(response.Header().Get("Retry-After")).To(Equal("91"))
+	})
+
+	It("should return 400 Bad Request and give back the attempt when the captcha fails", func() {
+		gomock.InOrder(
+			mockLoginThrottle.EXPECT().ReserveAttempt("kevin", gomock.Any()).Return(time.Duration(0), nil),
+			mockCaptchaVerifier.EXPECT().Verify("some-token", gomock.Any()).Return(false),
+			mockLoginThrottle.EXPECT().ReleaseAttempt("kevin", gomock.Any()).Return(nil),
+			mockSecurityService.EXPECT().ValidateCredentials(gomock.Any(), gomock.Any()).Times(0),
+		)
+
+		HitEndpoint(testAPI, "POST", "/api/sessions", bytes.NewBuffer(loginRequest("some password")), http.StatusBadRequest)
+	})
+
+	Context("client ip", func() {
+
+		loginFrom := func(remoteAddr, forwardedFor string) {
+			request, err := http.NewRequest("POST", "/api/sessions", bytes.NewBuffer(loginRequest("some password")))
+			Expect(err).ToNot(HaveOccurred())
+			request.RemoteAddr = remoteAddr
+			request.Header.Set("X-Forwarded-For", forwardedFor)
+			request.Header.Set("X-Real-Ip", "198.51.100.99")
+
+			response := httptest.NewRecorder()
+			testAPI.Router.ServeHTTP(response, request)
+
+			Expect(response.Code).To(Equal(http.StatusTooManyRequests))
+		}
+
+		It("should throttle by the address the request came from without its port and ignore forwarding headers", func() {
+			mockLoginThrottle.EXPECT().ReserveAttempt("kevin", "203.0.113.7").Return(time.Minute, nil)
+
+			loginFrom("203.0.113.7:54321", "198.51.100.1")
+		})
+
+		It("should throttle by the forwarded address of requests from trusted proxies", func() {
+			_, trustedProxies, err := net.ParseCIDR("10.0.0.0/8")
+			Expect(err).ToNot(HaveOccurred())
+			testAPI.TrustedProxies = []*net.IPNet{trustedProxies}
+
+			mockLoginThrottle.EXPECT().ReserveAttempt("kevin", "198.51.100.1").Return(time.Minute, nil)
+
+			loginFrom("10.0.0.2:443", "203.0.113.7, 198.51.100.1, 10.0.0.3")
+		})
+	})
+})
+
+var _ = Describe("Session two-factor verification", func() {
+
+	var ctrl *gomock.Controller
+	var testAPI *api.API
+	var mockSecurityService *mock_interfaces.MockSecurityServiceProvider
+	var mockLoginThrottle *mock_interfaces.MockLoginThrottleServiceProvider
+	var mockUserService *mock_interfaces.MockUserServiceProvider
+	var mockSessionService *mock_interfaces.MockSessionServiceProvider
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+
+		testConfig := config.LoadConfig()
+		testAPI = api.NewAPI(testConfig)
+
+		mockSecurityService = mock_interfaces.NewMockSecurityServiceProvider(ctrl)
+		testAPI.SecurityServiceFactory = func(ctx context.Context) interfaces.SecurityServiceProvider {
+			return mockSecurityService
+		}
+		mockLoginThrottle = mock_interfaces.NewMockLoginThrottleServiceProvider(ctrl)
+		testAPI.LoginThrottleFactory = func(ctx context.Context) interfaces.LoginThrottleServiceProvider {
+			return mockLoginThrottle
+		}
+		mockUserService = mock_interfaces.NewMockUserServiceProvider(ctrl)
+		testAPI.UserServiceFactory = func(ctx context.Context) interfaces.UserServiceProvider {
+			return mockUserService
+		}
+		mockSessionService = mock_interfaces.NewMockSessionServiceProvider(ctrl)
+		testAPI.SessionServiceFactory = func(ctx context.Context) interfaces.SessionServiceProvider {
+			return mockSessionService
+		}
+
+		testAPI.InitRoutes()
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	twoFactorRequest := func(code string) *bytes.Buffer {
+		reqBytes, err := json.Marshal(domain.SessionTwoFactorRequest{PreAuthToken: "some-pre-auth-token", Code: code})
+		Expect(err).ToNot(HaveOccurred())
+
+		return bytes.NewBuffer(reqBytes)
+	}
+
+	It("should return 200 OK and a session given a valid code", func() {
+		gomock.InOrder(
+			mockSecurityService.EXPECT().ParsePreAuthToken("some-pre-auth-token").Return("kevin", nil),
+			mockLoginThrottle.EXPECT().ReserveAttempt("kevin", gomock.Any()).Return(time.Duration(0), nil),
+			mockSecurityService.EXPECT().ValidateTwoFactorCode("kevin", "123456").Return(true, nil),
+			mockLoginThrottle.EXPECT().RecordSuccess("kevin", gomock.Any()).Return(nil),
+			mockUserService.EXPECT().RetrieveUserByUsername("kevin").Return(&domain.User{ID: 1, Username: "kevin", Role: domain.RoleEditor, TOTPEnabled: true}, nil),
+			mockSessionService.EXPECT().CreateWithExpiry("kevin", domain.RoleEditor).Return(&domain.SessionTokens{AuthToken: "some-auth-token"}, nil),
+		)
+
+		responseBytes := HitEndpoint(testAPI, "POST", "/api/sessions/two-factor", twoFactorRequest("123456"), http.StatusOK)
+
+		var sessionCreateResponse domain.SessionCreateResponse
+		Expect(json.Unmarshal(responseBytes, &sessionCreateResponse)).To(Succeed())
+		Expect(sessionCreateResponse.AuthToken).To(Equal("some-auth-token"))
+		Expect(sessionCreateResponse.Role).To(Equal(domain.RoleEditor))
+	})
+
+	It("should return 401 Unauthorized and record the failure given an incorrect code", func() {
+		gomock.InOrder(
+			mockSecurityService.EXPECT().ParsePreAuthToken("some-pre-auth-token").Return("kevin", nil),
+			mockLoginThrottle.EXPECT().ReserveAttempt("kevin", gomock.Any()).Return(time.Duration(0), nil),
+			mockSecurityService.EXPECT().ValidateTwoFactorCode("kevin", "000000").Return(false, nil),
+			mockLoginThrottle.EXPECT().RecordFailure("kevin", gomock.Any()).Return(nil),
+			mockSessionService.EXPECT().CreateWithExpiry(gomock.Any(), gomock.Any()).Times(0),
+		)
+
+		HitEndpoint(testAPI, "POST", "/api/sessions/two-factor", twoFactorRequest("000000"), http.StatusUnauthorized)
+	})
+
+	It("should return 401 Unauthorized given an invalid pre-auth token", func() {
+		gomock.InOrder(
+			mockSecurityService.EXPECT().ParsePreAuthToken("some-pre-auth-token").Return("", security.NewPreAuthTokenInvalidError()),
+			mockSecurityService.EXPECT().ValidateTwoFactorCode(gomock.Any(), gomock.Any()).Times(0),
+		)
+
+		HitEndpoint(testAPI, "POST", "/api/sessions/two-factor", twoFactorRequest("123456"), http.StatusUnauthorized)
+	})
+
+	It("should return 429 Too Many Requests while locked out", func() {
+		gomock.InOrder(
+			mockSecurityService.EXPECT().ParsePreAuthToken("some-pre-auth-token").Return("kevin", nil),
+			mockLoginThrottle.EXPECT().ReserveAttempt("kevin", gomock.Any()).Return(time.Minute, nil),
+			mockSecurityService.EXPECT().ValidateTwoFactorCode(gomock.Any(), gomock.Any()).Times(0),
+		)
+
+		HitEndpoint(testAPI, "POST", "/api/sessions/two-factor", twoFactorRequest("123456"), http.StatusTooManyRequests)
+	})
+})
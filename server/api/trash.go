@@ -0,0 +1,29 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/gin-gonic/gin"
+	"golang.org/x/net/context"
+)
+
+func listTrash(api *API) func(c *gin.Context) {
+	return func(c *gin.Context) {
+		ctxlogger := logrus.New()
+		ctx := context.Background()
+		ctx = context.WithValue(ctx, "logger", ctxlogger)
+
+		trashService := api.TrashServiceFactory(ctx)
+
+		trash, err := trashService.ListTrash()
+		if err != nil {
+			ctxlogger.Errorf("trash api - unable to list trash due to %v", err)
+			c.AbortWithStatus(http.StatusInternalServerError)
+			return
+		}
+
+		c.JSON(http.StatusOK, trash)
+		return
+	}
+}
@@ -0,0 +1,101 @@
+package api_test
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/rawfish-dev/rsvp-starter/server/api"
+	"github.com/rawfish-dev/rsvp-starter/server/config"
+	"github.com/rawfish-dev/rsvp-starter/server/domain"
+	"github.com/rawfish-dev/rsvp-starter/server/interfaces"
+	"github.com/rawfish-dev/rsvp-starter/server/mock"
+	serviceErrors "github.com/rawfish-dev/rsvp-starter/server/services/errors"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"golang.org/x/net/context"
+)
+
+var _ = Describe("Trash", func() {
+
+	var ctrl *gomock.Controller
+	var testAPI *api.API
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+
+		testConfig := config.LoadConfig()
+		testAPI = api.NewAPI(testConfig)
+
+		testAPI.SessionServiceFactory = func(ctx context.Context) interfaces.SessionServiceProvider {
+			mockSessionService := mock_interfaces.NewMockSessionServiceProvider(ctrl)
+			mockSessionService.EXPECT().IsSessionValid("").Return(true, nil)
+			mockSessionService.EXPECT().Username("").Return("admin", nil)
+			mockSessionService.EXPECT().Role("").Return(domain.RoleOwner, nil)
+
+			return mockSessionService
+		}
+
+		testAPI.InitRoutes()
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	Context("listing", func() {
+
+		It("should return 200 OK and the deleted records", func() {
+			trash := &domain.Trash{
+				Categories: []domain.Category{
+					{ID: 1, Tag: "family", DeletedAt: "2026-10-17T10:00:00Z"},
+				},
+				Invitations: []domain.Invitation{
+					{
+						BaseInvitation: domain.BaseInvitation{CategoryID: 1, Greeting: "ah ma", MaximumGuestCount: 2},
+						ID:             2,
+						PrivateID:      "some-private-id",
+						Status:         domain.NotSent,
+						DeletedAt:      "2026-10-17T09:00:00Z",
+					},
+				},
+				RSVPs: []domain.RSVP{
+					{
+						BaseRSVP:            domain.BaseRSVP{FullName: "ah ma", Attending: true, GuestCount: 2},
+						ID:                  3,
+						InvitationPrivateID: "some-private-id",
+						Completed:           true,
+						DeletedAt:           "2026-10-17T08:00:00Z",
+					},
+				},
+			}
+
+			testAPI.TrashServiceFactory = func(ctx context.Context) interfaces.TrashServiceProvider {
+				mockTrashService := mock_interfaces.NewMockTrashServiceProvider(ctrl)
+				mockTrashService.EXPECT().ListTrash().Return(trash, nil)
+
+				return mockTrashService
+			}
+
+			responseBytes := HitEndpoint(testAPI, "GET", "/api/trash", nil, http.StatusOK)
+
+			var returnedTrash domain.Trash
+			err := json.Unmarshal(responseBytes, &returnedTrash)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(returnedTrash).To(Equal(*trash))
+		})
+
+		It("should return 500 Internal Server Error when an unknown service error occurs", func() {
+			testAPI.TrashServiceFactory = func(ctx context.Context) interfaces.TrashServiceProvider {
+				mockTrashService := mock_interfaces.NewMockTrashServiceProvider(ctrl)
+				mockTrashService.EXPECT().ListTrash().Return(nil, serviceErrors.NewGeneralServiceError())
+
+				return mockTrashService
+			}
+
+			HitEndpoint(testAPI, "GET", "/api/trash", nil, http.StatusInternalServerError)
+		})
+	})
+})
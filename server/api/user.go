@@ -0,0 +1,390 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/rawfish-dev/rsvp-starter/server/domain"
+	serviceErrors "github.com/rawfish-dev/rsvp-starter/server/services/errors"
+	"github.com/rawfish-dev/rsvp-starter/server/services/user"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/gin-gonic/gin"
+	"golang.org/x/net/context"
+)
+
+func createUser(api *API) func(c *gin.Context) {
+	return func(c *gin.Context) {
+		ctxlogger := logrus.New()
+		ctx := context.Background()
+		ctx = context.WithValue(ctx, "logger", ctxlogger)
+		ctx = contextWithActor(ctx, c)
+
+		userService := api.UserServiceFactory(ctx)
+
+		var userCreateRequest domain.UserCreateRequest
+		err := c.BindJSON(&userCreateRequest)
+		if err != nil {
+			ctxlogger.Errorf("user api - unable to create new user while unwrapping request due to %v", err)
+			c.AbortWithStatus(http.StatusBadRequest)
+			return
+		}
+
+		newUser, err := userService.CreateUser(&userCreateRequest)
+		if err != nil {
+			switch err.(type) {
+			case serviceErrors.ValidationError:
+				ctxlogger.Warnf("user api - unable to create new user due to validation error %v", err)
+				c.JSON(domain.NewCustomBadRequestError(err.Error()))
+				return
+			}
+
+			ctxlogger.Errorf("user api - unable to create new user due to %v", err)
+			c.AbortWithStatus(http.StatusInternalServerError)
+			return
+		}
+
+		c.JSON(http.StatusOK, newUser)
+		return
+	}
+}
+
+func listUsers(api *API) func(c *gin.Context) {
+	return func(c *gin.Context) {
+		ctxlogger := logrus.New()
+		ctx := context.Background()
+		ctx = context.WithValue(ctx, "logger", ctxlogger)
+
+		userService := api.UserServiceFactory(ctx)
+
+		users, err := userService.ListUsers()
+		if err != nil {
+			ctxlogger.Errorf("user api - unable to list users due to %v", err)
+			c.AbortWithStatus(http.StatusInternalServerError)
+			return
+		}
+
+		c.JSON(http.StatusOK, users)
+		return
+	}
+}
+
+func disableUser(api *API) func(c *gin.Context) {
+	return func(c *gin.Context) {
+		ctxlogger := logrus.New()
+		ctx := context.Background()
+		ctx = context.WithValue(ctx, "logger", ctxlogger)
+		ctx = contextWithActor(ctx, c)
+
+		userService := api.UserServiceFactory(ctx)
+
+		userID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			ctxlogger.Warnf("user api - unable to disable user as params id %v could not be converted due to %v", c.Param("id"), err)
+			c.AbortWithStatus(http.StatusBadRequest)
+			return
+		}
+
+		disabledUser, err := userService.DisableUserByID(userID)
+		if err != nil {
+			switch err.(type) {
+			case user.UserNotFoundError:
+				c.AbortWithStatus(http.StatusNotFound)
+				return
+			case serviceErrors.ValidationError:
+				ctxlogger.Warnf("user api - unable to disable user due to validation error %v", err)
+				c.JSON(domain.NewCustomBadRequestError(err.Error()))
+				return
+			}
+
+			ctxlogger.Errorf("user api - unable to disable user due to %v", err)
+			c.AbortWithStatus(http.StatusInternalServerError)
+			return
+		}
+
+		c.JSON(http.StatusOK, disabledUser)
+		return
+	}
+}
+
+func updateUserRole(api *API) func(c *gin.Context) {
+	return func(c *gin.Context) {
+		ctxlogger := logrus.New()
+		ctx := context.Background()
+		ctx = context.WithValue(ctx, "logger", ctxlogger)
+		ctx = contextWithActor(ctx, c)
+
+		userService := api.UserServiceFactory(ctx)
+
+		var roleUpdateRequest domain.UserRoleUpdateRequest
+		err := c.BindJSON(&roleUpdateRequest)
+		if err != nil {
+			ctxlogger.Errorf("user api - unable to update role while unwrapping request due to %v", err)
+			c.AbortWithStatus(http.StatusBadRequest)
+			return
+		}
+
+		if c.Param("id") != fmt.Sprintf("%v", roleUpdateRequest.ID) {
+			ctxlogger.Warnf("user api - unable to update role as params id %v don't match request id %v", c.Param("id"), roleUpdateRequest.ID)
+			c.AbortWithStatus(http.StatusBadRequest)
+			return
+		}
+
+		updatedUser, err := userService.UpdateUserRole(&roleUpdateRequest)
+		if err != nil {
+			switch err.(type) {
+			case user.UserNotFoundError:
+				c.AbortWithStatus(http.StatusNotFound)
+				return
+			case serviceErrors.ValidationError:
+				ctxlogger.Warnf("user api - unable to update role due to validation error %v", err)
+				c.JSON(domain.NewCustomBadRequestError(err.Error()))
+				return
+			}
+
+			ctxlogger.Errorf("user api - unable to update role due to %v", err)
+			c.AbortWithStatus(http.StatusInternalServerError)
+			return
+		}
+
+		c.JSON(http.StatusOK, updatedUser)
+		return
+	}
+}
+
+func resetPassword(api *API) func(c *gin.Context) {
+	return func(c *gin.Context) {
+		ctxlogger := logrus.New()
+		ctx := context.Background()
+		ctx = context.WithValue(ctx, "logger", ctxlogger)
+		ctx = contextWithActor(ctx, c)
+
+		userService := api.UserServiceFactory(ctx)
+
+		var passwordResetRequest domain.UserPasswordResetRequest
+		err := c.BindJSON(&passwordResetRequest)
+		if err != nil {
+			ctxlogger.Errorf("user api - unable to reset password while unwrapping request due to %v", err)
+			c.AbortWithStatus(http.StatusBadRequest)
+			return
+		}
+
+		if c.Param("id") != fmt.Sprintf("%v", passwordResetRequest.ID) {
+			ctxlogger.Warnf("user api - unable to reset password as params id %v don't match request id %v", c.Param("id"), passwordResetRequest.ID)
+			c.AbortWithStatus(http.StatusBadRequest)
+			return
+		}
+
+		updatedUser, err := userService.ResetPassword(&passwordResetRequest)
+		if err != nil {
+			switch err.(type) {
+			case user.UserNotFoundError:
+				c.AbortWithStatus(http.StatusNotFound)
+				return
+			case serviceErrors.ValidationError:
+				ctxlogger.Warnf("user api - unable to reset password due to validation error %v", err)
+				c.JSON(domain.NewCustomBadRequestError(err.Error()))
+				return
+			}
+
+			ctxlogger.Errorf("user api - unable to reset password due to %v", err)
+			c.AbortWithStatus(http.StatusInternalServerError)
+			return
+		}
+
+		c.JSON(http.StatusOK, updatedUser)
+		return
+	}
+}
+
+// changePassword lets the signed in user change their own password from the control panel
+func changePassword(api *API) func(c *gin.Context) {
+	return func(c *gin.Context) {
+		ctxlogger := logrus.New()
+		ctx := context.Background()
+		ctx = context.WithValue(ctx, "logger", ctxlogger)
+		ctx = contextWithActor(ctx, c)
+
+		userService := api.UserServiceFactory(ctx)
+
+		var passwordChangeRequest domain.PasswordChangeRequest
+		err := c.BindJSON(&passwordChangeRequest)
+		if err != nil {
+			ctxlogger.Errorf("user api - unable to change password while unwrapping request due to %v", err)
+			c.AbortWithStatus(http.StatusBadRequest)
+			return
+		}
+
+		actor, _ := ctx.Value(domain.ContextActor).(domain.Actor)
+
+		err = userService.ChangePassword(actor.Username, &passwordChangeRequest)
+		if err != nil {
+			switch err.(type) {
+			case user.UserNotFoundError:
+				c.AbortWithStatus(http.StatusNotFound)
+				return
+			case serviceErrors.ValidationError:
+				ctxlogger.Warnf("user api - unable to change password due to validation error %v", err)
+				c.JSON(domain.NewCustomBadRequestError(err.Error()))
+				return
+			}
+
+			ctxlogger.Errorf("user api - unable to change password due to %v", err)
+			c.AbortWithStatus(http.StatusInternalServerError)
+			return
+		}
+
+		return
+	}
+}
+
+func beginTOTPEnrollment(api *API) func(c *gin.Context) {
+	return func(c *gin.Context) {
+		ctxlogger := logrus.New()
+		ctx := context.Background()
+		ctx = context.WithValue(ctx, "logger", ctxlogger)
+		ctx = contextWithActor(ctx, c)
+
+		userService := api.UserServiceFactory(ctx)
+
+		actor, _ := ctx.Value(domain.ContextActor).(domain.Actor)
+
+		enrollment, err := userService.BeginTOTPEnrollment(actor.Username)
+		if err != nil {
+			switch err.(type) {
+			case user.UserNotFoundError:
+				c.AbortWithStatus(http.StatusNotFound)
+				return
+			case serviceErrors.ValidationError:
+				ctxlogger.Warnf("user api - unable to begin two-factor enrollment due to validation error %v", err)
+				c.JSON(domain.NewCustomBadRequestError(err.Error()))
+				return
+			}
+
+			ctxlogger.Errorf("user api - unable to begin two-factor enrollment due to %v", err)
+			c.AbortWithStatus(http.StatusInternalServerError)
+			return
+		}
+
+		c.JSON(http.StatusOK, enrollment)
+		return
+	}
+}
+
+func confirmTOTPEnrollment(api *API) func(c *gin.Context) {
+	return func(c *gin.Context) {
+		ctxlogger := logrus.New()
+		ctx := context.Background()
+		ctx = context.WithValue(ctx, "logger", ctxlogger)
+		ctx = contextWithActor(ctx, c)
+
+		userService := api.UserServiceFactory(ctx)
+
+		var totpConfirmRequest domain.TOTPConfirmRequest
+		err := c.BindJSON(&totpConfirmRequest)
+		if err != nil {
+			ctxlogger.Errorf("user api - unable to confirm two-factor enrollment while unwrapping request due to %v", err)
+			c.AbortWithStatus(http.StatusBadRequest)
+			return
+		}
+
+		actor, _ := ctx.Value(domain.ContextActor).(domain.Actor)
+
+		recoveryCodes, err := userService.ConfirmTOTPEnrollment(actor.Username, &totpConfirmRequest)
+		if err != nil {
+			switch err.(type) {
+			case user.UserNotFoundError:
+				c.AbortWithStatus(http.StatusNotFound)
+				return
+			case serviceErrors.ValidationError:
+				ctxlogger.Warnf("user api - unable to confirm two-factor enrollment due to validation error %v", err)
+				c.JSON(domain.NewCustomBadRequestError(err.Error()))
+				return
+			}
+
+			ctxlogger.Errorf("user api - unable to confirm two-factor enrollment due to %v", err)
+			c.AbortWithStatus(http.StatusInternalServerError)
+			return
+		}
+
+		c.JSON(http.StatusOK, recoveryCodes)
+		return
+	}
+}
+
+func disableTOTP(api *API) func(c *gin.Context) {
+	return func(c *gin.Context) {
+		ctxlogger := logrus.New()
+		ctx := context.Background()
+		ctx = context.WithValue(ctx, "logger", ctxlogger)
+		ctx = contextWithActor(ctx, c)
+
+		userService := api.UserServiceFactory(ctx)
+
+		var totpDisableRequest domain.TOTPDisableRequest
+		err := c.BindJSON(&totpDisableRequest)
+		if err != nil {
+			ctxlogger.Errorf("user api - unable to disable two-factor authentication while unwrapping request due to %v", err)
+			c.AbortWithStatus(http.StatusBadRequest)
+			return
+		}
+
+		actor, _ := ctx.Value(domain.ContextActor).(domain.Actor)
+
+		err = userService.DisableTOTP(actor.Username, &totpDisableRequest)
+		if err != nil {
+			switch err.(type) {
+			case user.UserNotFoundError:
+				c.AbortWithStatus(http.StatusNotFound)
+				return
+			case serviceErrors.ValidationError:
+				ctxlogger.Warnf("user api - unable to disable two-factor authentication due to validation error %v", err)
+				c.JSON(domain.NewCustomBadRequestError(err.Error()))
+				return
+			}
+
+			ctxlogger.Errorf("user api - unable to disable two-factor authentication due to %v", err)
+			c.AbortWithStatus(http.StatusInternalServerError)
+			return
+		}
+
+		return
+	}
+}
+
+// resetTOTP lets owners turn off two-factor authentication for users who have lost their authenticator
+// app and their recovery codes
+func resetTOTP(api *API) func(c *gin.Context) {
+	return func(c *gin.Context) {
+		ctxlogger := logrus.New()
+		ctx := context.Background()
+		ctx = context.WithValue(ctx, "logger", ctxlogger)
+		ctx = contextWithActor(ctx, c)
+
+		userService := api.UserServiceFactory(ctx)
+
+		userID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			ctxlogger.Warnf("user api - unable to reset two-factor authentication as params id %v could not be converted due to %v", c.Param("id"), err)
+			c.AbortWithStatus(http.StatusBadRequest)
+			return
+		}
+
+		updatedUser, err := userService.ResetTOTPByID(userID)
+		if err != nil {
+			switch err.(type) {
+			case user.UserNotFoundError:
+				c.AbortWithStatus(http.StatusNotFound)
+				return
+			}
+
+			ctxlogger.Errorf("user api - unable to reset two-factor authentication due to %v", err)
+			c.AbortWithStatus(http.StatusInternalServerError)
+			return
+		}
+
+		c.JSON(http.StatusOK, updatedUser)
+		return
+	}
+}
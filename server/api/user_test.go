@@ -0,0 +1,299 @@
+package api_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+
+	"github.com/rawfish-dev/rsvp-starter/server/api"
+	"github.com/rawfish-dev/rsvp-starter/server/config"
+	"github.com/rawfish-dev/rsvp-starter/server/domain"
+	"github.com/rawfish-dev/rsvp-starter/server/interfaces"
+	"github.com/rawfish-dev/rsvp-starter/server/mock"
+	serviceErrors "github.com/rawfish-dev/rsvp-starter/server/services/errors"
+	. "github.com/rawfish-dev/rsvp-starter/server/services/user"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"golang.org/x/net/context"
+)
+
+var _ = Describe("User", func() {
+
+	var ctrl *gomock.Controller
+	var testAPI *api.API
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+
+		testConfig := config.LoadConfig()
+		testAPI = api.NewAPI(testConfig)
+
+		testAPI.SessionServiceFactory = func(ctx context.Context) interfaces.SessionServiceProvider {
+			mockSessionService := mock_interfaces.NewMockSessionServiceProvider(ctrl)
+			mockSessionService.EXPECT().IsSessionValid("").Return(true, nil)
+			mockSessionService.EXPECT().Username("").Return("admin", nil)
+			mockSessionService.EXPECT().Role("").Return(domain.RoleOwner, nil)
+
+			return mockSessionService
+		}
+
+		testAPI.InitRoutes()
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	Context("creation", func() {
+
+		var createUserReq domain.UserCreateRequest
+
+		BeforeEach(func() {
+			createUserReq = domain.UserCreateRequest{Username: "jenny", Password: "some password", Role: domain.RoleEditor}
+		})
+
+		It("should return 200 OK and the new user without its password hash", func() {
+			testAPI.UserServiceFactory = func(ctx context.Context) interfaces.UserServiceProvider {
+				mockUserService := mock_interfaces.NewMockUserServiceProvider(ctrl)
+				mockUserService.EXPECT().CreateUser(&createUserReq).
+					Return(&domain.User{ID: 2, Username: "jenny", PasswordHash: "some-hash"}, nil)
+
+				return mockUserService
+			}
+
+			reqBytes, err := json.Marshal(createUserReq)
+			Expect(err).ToNot(HaveOccurred())
+
+			responseBytes := HitEndpoint(testAPI, "POST", "/api/users", bytes.NewBuffer(reqBytes), http.StatusOK)
+			Expect(responseBytes).ToNot(ContainSubstring("some-hash"))
+
+			var newUser domain.User
+			err = json.Unmarshal(responseBytes, &newUser)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(newUser.ID).To(Equal(int64(2)))
+			Expect(newUser.Username).To(Equal("jenny"))
+		})
+
+		It("should return 400 Bad Request when a validation error occurs", func() {
+			testAPI.UserServiceFactory = func(ctx context.Context) interfaces.UserServiceProvider {
+				mockUserService := mock_interfaces.NewMockUserServiceProvider(ctrl)
+				mockUserService.EXPECT().CreateUser(&createUserReq).
+					Return(nil, serviceErrors.NewValidationError([]string{"username already exists"}))
+
+				return mockUserService
+			}
+
+			reqBytes, err := json.Marshal(createUserReq)
+			Expect(err).ToNot(HaveOccurred())
+
+			responseBytes := HitEndpoint(testAPI, "POST", "/api/users", bytes.NewBuffer(reqBytes), http.StatusBadRequest)
+
+			var badRequestError domain.CustomBadRequestError
+			err = json.Unmarshal(responseBytes, &badRequestError)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(badRequestError.Error).To(Equal("username already exists"))
+		})
+	})
+
+	Context("listing", func() {
+
+		It("should return 200 OK and every user", func() {
+			users := []domain.User{{ID: 1, Username: "admin"}, {ID: 2, Username: "jenny", Disabled: true}}
+
+			testAPI.UserServiceFactory = func(ctx context.Context) interfaces.UserServiceProvider {
+				mockUserService := mock_interfaces.NewMockUserServiceProvider(ctrl)
+				mockUserService.EXPECT().ListUsers().Return(users, nil)
+
+				return mockUserService
+			}
+
+			responseBytes := HitEndpoint(testAPI, "GET", "/api/users", nil, http.StatusOK)
+
+			var retrievedUsers []domain.User
+			err := json.Unmarshal(responseBytes, &retrievedUsers)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(retrievedUsers).To(Equal(users))
+		})
+	})
+
+	Context("disabling", func() {
+
+		It("should return 200 OK and the disabled user", func() {
+			testAPI.UserServiceFactory = func(ctx context.Context) interfaces.UserServiceProvider {
+				mockUserService := mock_interfaces.NewMockUserServiceProvider(ctrl)
+				mockUserService.EXPECT().DisableUserByID(int64(2)).Return(&domain.User{ID: 2, Username: "jenny", Disabled: true}, nil)
+
+				return mockUserService
+			}
+
+			HitEndpoint(testAPI, "POST", "/api/users/2/disable", nil, http.StatusOK)
+		})
+
+		It("should return 400 Bad Request when disabling the last enabled owner", func() {
+			testAPI.UserServiceFactory = func(ctx context.Context) interfaces.UserServiceProvider {
+				mockUserService := mock_interfaces.NewMockUserServiceProvider(ctrl)
+				mockUserService.EXPECT().DisableUserByID(int64(1)).
+					Return(nil, serviceErrors.NewValidationError([]string{"the last enabled owner cannot be disabled"}))
+
+				return mockUserService
+			}
+
+			HitEndpoint(testAPI, "POST", "/api/users/1/disable", nil, http.StatusBadRequest)
+		})
+
+		It("should return 404 Not Found when the user does not exist", func() {
+			testAPI.UserServiceFactory = func(ctx context.Context) interfaces.UserServiceProvider {
+				mockUserService := mock_interfaces.NewMockUserServiceProvider(ctrl)
+				mockUserService.EXPECT().DisableUserByID(int64(3)).Return(nil, NewUserNotFoundError())
+
+				return mockUserService
+			}
+
+			HitEndpoint(testAPI, "POST", "/api/users/3/disable", nil, http.StatusNotFound)
+		})
+	})
+
+	Context("role updates", func() {
+
+		It("should return 200 OK and the user with the new role", func() {
+			roleReq := domain.UserRoleUpdateRequest{ID: 2, Role: domain.RoleViewer}
+
+			testAPI.UserServiceFactory = func(ctx context.Context) interfaces.UserServiceProvider {
+				mockUserService := mock_interfaces.NewMockUserServiceProvider(ctrl)
+				mockUserService.EXPECT().UpdateUserRole(&roleReq).Return(&domain.User{ID: 2, Username: "jenny", Role: domain.RoleViewer}, nil)
+
+				return mockUserService
+			}
+
+			reqBytes, err := json.Marshal(roleReq)
+			Expect(err).ToNot(HaveOccurred())
+
+			responseBytes := HitEndpoint(testAPI, "PUT", "/api/users/2/role", bytes.NewBuffer(reqBytes), http.StatusOK)
+
+			var updatedUser domain.User
+			Expect(json.Unmarshal(responseBytes, &updatedUser)).To(Succeed())
+			Expect(updatedUser.Role).To(Equal(domain.RoleViewer))
+		})
+
+		It("should return 400 Bad Request when the params id does not match the request", func() {
+			reqBytes, err := json.Marshal(domain.UserRoleUpdateRequest{ID: 3, Role: domain.RoleViewer})
+			Expect(err).ToNot(HaveOccurred())
+
+			HitEndpoint(testAPI, "PUT", "/api/users/2/role", bytes.NewBuffer(reqBytes), http.StatusBadRequest)
+		})
+	})
+
+	Context("passwords", func() {
+
+		It("should return 200 OK after resetting the password of a user", func() {
+			resetReq := domain.UserPasswordResetRequest{ID: 2, Password: "new password"}
+
+			testAPI.UserServiceFactory = func(ctx context.Context) interfaces.UserServiceProvider {
+				mockUserService := mock_interfaces.NewMockUserServiceProvider(ctrl)
+				mockUserService.EXPECT().ResetPassword(&resetReq).Return(&domain.User{ID: 2, Username: "jenny"}, nil)
+
+				return mockUserService
+			}
+
+			reqBytes, err := json.Marshal(resetReq)
+			Expect(err).ToNot(HaveOccurred())
+
+			HitEndpoint(testAPI, "PUT", "/api/users/2/password", bytes.NewBuffer(reqBytes), http.StatusOK)
+		})
+
+		It("should return 400 Bad Request if the params id does not match the request id", func() {
+			reqBytes, err := json.Marshal(domain.UserPasswordResetRequest{ID: 2, Password: "new password"})
+			Expect(err).ToNot(HaveOccurred())
+
+			HitEndpoint(testAPI, "PUT", "/api/users/3/password", bytes.NewBuffer(reqBytes), http.StatusBadRequest)
+		})
+
+		It("should change the password of the signed in user", func() {
+			changeReq := domain.PasswordChangeRequest{CurrentPassword: "old password", NewPassword: "new password"}
+
+			testAPI.UserServiceFactory = func(ctx context.Context) interfaces.UserServiceProvider {
+				mockUserService := mock_interfaces.NewMockUserServiceProvider(ctrl)
+				mockUserService.EXPECT().ChangePassword("admin", &changeReq).Return(nil)
+
+				return mockUserService
+			}
+
+			reqBytes, err := json.Marshal(changeReq)
+			Expect(err).ToNot(HaveOccurred())
+
+			HitEndpoint(testAPI, "PUT", "/api/account/password", bytes.NewBuffer(reqBytes), http.StatusOK)
+		})
+
+		It("should return 400 Bad Request when the current password is incorrect", func() {
+			testAPI.UserServiceFactory = func(ctx context.Context) interfaces.UserServiceProvider {
+				mockUserService := mock_interfaces.NewMockUserServiceProvider(ctrl)
+				mockUserService.EXPECT().ChangePassword("admin", gomock.Any()).
+					Return(serviceErrors.NewValidationError([]string{"current password is incorrect"}))
+
+				return mockUserService
+			}
+
+			reqBytes, err := json.Marshal(domain.PasswordChangeRequest{CurrentPassword: "wrong password", NewPassword: "new password"})
+			Expect(err).ToNot(HaveOccurred())
+
+			HitEndpoint(testAPI, "PUT", "/api/account/password", bytes.NewBuffer(reqBytes), http.StatusBadRequest)
+		})
+	})
+
+	Context("two-factor authentication", func() {
+
+		It("should return 200 OK and a new secret for the signed in user", func() {
+			enrollment := &domain.TOTPEnrollment{Secret: "SOMESECRET", ProvisioningURI: "otpauth://totp/RSVP%20Starter:admin?secret=SOMESECRET"}
+
+			testAPI.UserServiceFactory = func(ctx context.Context) interfaces.UserServiceProvider {
+				mockUserService := mock_interfaces.NewMockUserServiceProvider(ctrl)
+				mockUserService.EXPECT().BeginTOTPEnrollment("admin").Return(enrollment, nil)
+
+				return mockUserService
+			}
+
+			responseBytes := HitEndpoint(testAPI, "POST", "/api/account/totp", nil, http.StatusOK)
+
+			var returnedEnrollment domain.TOTPEnrollment
+			Expect(json.Unmarshal(responseBytes, &returnedEnrollment)).To(Succeed())
+			Expect(returnedEnrollment).To(Equal(*enrollment))
+		})
+
+		It("should return 200 OK and the recovery codes once a code is confirmed", func() {
+			confirmReq := domain.TOTPConfirmRequest{Code: "123456"}
+
+			testAPI.UserServiceFactory = func(ctx context.Context) interfaces.UserServiceProvider {
+				mockUserService := mock_interfaces.NewMockUserServiceProvider(ctrl)
+				mockUserService.EXPECT().ConfirmTOTPEnrollment("admin", &confirmReq).Return(&domain.RecoveryCodes{Codes: []string{"abcde-12345"}}, nil)
+
+				return mockUserService
+			}
+
+			reqBytes, err := json.Marshal(confirmReq)
+			Expect(err).ToNot(HaveOccurred())
+
+			responseBytes := HitEndpoint(testAPI, "POST", "/api/account/totp/confirm", bytes.NewBuffer(reqBytes), http.StatusOK)
+
+			var recoveryCodes domain.RecoveryCodes
+			Expect(json.Unmarshal(responseBytes, &recoveryCodes)).To(Succeed())
+			Expect(recoveryCodes.Codes).To(Equal([]string{"abcde-12345"}))
+		})
+
+		It("should return 400 Bad Request when the password to disable it is incorrect", func() {
+			testAPI.UserServiceFactory = func(ctx context.Context) interfaces.UserServiceProvider {
+				mockUserService := mock_interfaces.NewMockUserServiceProvider(ctrl)
+				mockUserService.EXPECT().DisableTOTP("admin", gomock.Any()).
+					Return(serviceErrors.NewValidationError([]string{"password is incorrect"}))
+
+				return mockUserService
+			}
+
+			reqBytes, err := json.Marshal(domain.TOTPDisableRequest{Password: "wrong password"})
+			Expect(err).ToNot(HaveOccurred())
+
+			HitEndpoint(testAPI, "DELETE", "/api/account/totp", bytes.NewBuffer(reqBytes), http.StatusBadRequest)
+		})
+	})
+})
@@ -0,0 +1,77 @@
+package api
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/rawfish-dev/rsvp-starter/server/domain"
+	serviceErrors "github.com/rawfish-dev/rsvp-starter/server/services/errors"
+	"github.com/rawfish-dev/rsvp-starter/server/services/invitation"
+	"github.com/rawfish-dev/rsvp-starter/server/services/messaging"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/gin-gonic/gin"
+	"golang.org/x/net/context"
+)
+
+// Headers providers send the signature of a status report and the unix timestamp it was signed at in
+const (
+	webhookTimestampHeader = "X-Webhook-Timestamp"
+	webhookSignatureHeader = "X-Webhook-Signature"
+)
+
+func updateMessageStatus(api *API) func(c *gin.Context) {
+	return func(c *gin.Context) {
+		ctxlogger := logrus.New()
+		ctx := context.Background()
+		ctx = context.WithValue(ctx, "logger", ctxlogger)
+
+		// The signature covers the raw body, so it is read before being unwrapped
+		body, err := ioutil.ReadAll(c.Request.Body)
+		if err != nil {
+			ctxlogger.Errorf("webhook api - unable to read message status update due to %v", err)
+			c.AbortWithStatus(http.StatusBadRequest)
+			return
+		}
+
+		err = messaging.VerifyStatusUpdate(api.StatusWebhookSecret, c.Request.Header.Get(webhookTimestampHeader),
+			c.Request.Header.Get(webhookSignatureHeader), body, time.Now())
+		if err != nil {
+			ctxlogger.Warnf("webhook api - unable to accept message status update due to %v", err)
+			c.AbortWithStatus(http.StatusUnauthorized)
+			return
+		}
+
+		var messageStatusUpdate domain.MessageStatusUpdate
+		err = json.Unmarshal(body, &messageStatusUpdate)
+		if err != nil {
+			ctxlogger.Errorf("webhook api - unable to update message status while unwrapping request due to %v", err)
+			c.JSON(domain.NewInvalidJSONBodyError())
+			return
+		}
+
+		invitationService := api.InvitationServiceFactory(ctx)
+
+		updatedMessage, err := invitationService.UpdateMessageStatus(&messageStatusUpdate)
+		if err != nil {
+			switch err.(type) {
+			case serviceErrors.ValidationError:
+				ctxlogger.Errorf("webhook api - unable to update message status due to validation error %v", err)
+				c.JSON(domain.NewCustomBadRequestError(err.Error()))
+				return
+			case invitation.MessageNotFoundError:
+				c.AbortWithStatus(http.StatusNotFound)
+				return
+			}
+
+			ctxlogger.Errorf("webhook api - unable to update message status due to %v", err)
+			c.AbortWithStatus(http.StatusInternalServerError)
+			return
+		}
+
+		c.JSON(http.StatusOK, updatedMessage)
+		return
+	}
+}
@@ -0,0 +1,112 @@
+package api_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"time"
+
+	"github.com/rawfish-dev/rsvp-starter/server/api"
+	"github.com/rawfish-dev/rsvp-starter/server/config"
+	"github.com/rawfish-dev/rsvp-starter/server/domain"
+	"github.com/rawfish-dev/rsvp-starter/server/interfaces"
+	"github.com/rawfish-dev/rsvp-starter/server/mock"
+	serviceErrors "github.com/rawfish-dev/rsvp-starter/server/services/errors"
+	"github.com/rawfish-dev/rsvp-starter/server/services/invitation"
+	"github.com/rawfish-dev/rsvp-starter/server/services/messaging"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"golang.org/x/net/context"
+)
+
+var _ = Describe("Message status webhook", func() {
+
+	var ctrl *gomock.Controller
+	var testAPI *api.API
+	var mockInvitationService *mock_interfaces.MockInvitationServiceProvider
+	var body []byte
+
+	postStatusUpdate := func(signature string, timestamp int64) *httptest.ResponseRecorder {
+		request, err := http.NewRequest("POST", "/api/webhooks/messages", bytes.NewReader(body))
+		Expect(err).ToNot(HaveOccurred())
+		request.Header.Set("X-Webhook-Timestamp", strconv.FormatInt(timestamp, 10))
+		request.Header.Set("X-Webhook-Signature", signature)
+
+		response := httptest.NewRecorder()
+		testAPI.Router.ServeHTTP(response, request)
+
+		return response
+	}
+
+	postSignedStatusUpdate := func() *httptest.ResponseRecorder {
+		now := time.Now().Unix()
+		return postStatusUpdate(messaging.SignStatusUpdate("secret", now, body), now)
+	}
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+
+		testConfig := config.LoadConfig()
+		testAPI = api.NewAPI(testConfig)
+		testAPI.StatusWebhookSecret = "secret"
+
+		mockInvitationService = mock_interfaces.NewMockInvitationServiceProvider(ctrl)
+		testAPI.InvitationServiceFactory = func(ctx context.Context) interfaces.InvitationServiceProvider {
+			return mockInvitationService
+		}
+
+		testAPI.InitRoutes()
+
+		body = []byte(`{"provider":"twilio","providerMessageID":"SM123","status":"delivered"}`)
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	It("should return 200 OK and the message with the status the provider reported", func() {
+		mockInvitationService.EXPECT().UpdateMessageStatus(&domain.MessageStatusUpdate{
+			Provider:          "twilio",
+			ProviderMessageID: "SM123",
+			Status:            domain.MessageDelivered,
+		}).Return(&domain.Message{ID: 2, Status: domain.MessageDelivered}, nil)
+
+		response := postSignedStatusUpdate()
+		Expect(response.Code).To(Equal(http.StatusOK))
+
+		var message domain.Message
+		Expect(json.Unmarshal(response.Body.Bytes(), &message)).To(Succeed())
+		Expect(message.ID).To(Equal(int64(2)))
+		Expect(message.Status).To(Equal(domain.MessageDelivered))
+	})
+
+	It("should return 401 Unauthorized if the signature does not match or is too old", func() {
+		mockInvitationService.EXPECT().UpdateMessageStatus(gomock.Any()).Times(0)
+
+		now := time.Now().Unix()
+		Expect(postStatusUpdate(messaging.SignStatusUpdate("other", now, body), now).Code).To(Equal(http.StatusUnauthorized))
+
+		then := time.Now().Add(-time.Hour).Unix()
+		Expect(postStatusUpdate(messaging.SignStatusUpdate("secret", then, body), then).Code).To(Equal(http.StatusUnauthorized))
+	})
+
+	It("should return 400 Bad Request if the body is not JSON or the update is invalid", func() {
+		body = []byte(`{"status":`)
+		Expect(postSignedStatusUpdate().Code).To(Equal(http.StatusBadRequest))
+
+		body = []byte(`{"status":"read"}`)
+		mockInvitationService.EXPECT().UpdateMessageStatus(gomock.Any()).Return(
+			nil, serviceErrors.NewValidationError([]string{"message status is invalid"}))
+		Expect(postSignedStatusUpdate().Code).To(Equal(http.StatusBadRequest))
+	})
+
+	It("should return 404 Not Found if the message cannot be found", func() {
+		mockInvitationService.EXPECT().UpdateMessageStatus(gomock.Any()).Return(nil, invitation.NewMessageNotFoundError())
+
+		Expect(postSignedStatusUpdate().Code).To(Equal(http.StatusNotFound))
+	})
+})
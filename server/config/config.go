@@ -1,25 +1,107 @@
 package config
 
 import (
+	"io/ioutil"
+	"net"
 	"os"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/Sirupsen/logrus"
+	"golang.org/x/crypto/bcrypt"
 )
 
 const (
-	defaultHTTPPort = 6001
-	sessionDuration = time.Minute * 20
+	defaultHTTPPort           = 6001
+	defaultSQLitePath         = "rsvp_starter.db"
+	defaultSessionDuration    = time.Minute * 20
+	defaultRefreshDuration    = time.Hour * 24 * 7
+	defaultTrashRetention     = time.Hour * 24 * 30
+	defaultTrashPurgeInterval = time.Hour
+	defaultLoginMaxAttempts   = 5
+	defaultLoginMaxPerIP      = 20
+	defaultLoginBackoff       = time.Second
+	defaultLoginLockout       = time.Minute * 15
+	defaultLoginAttemptWindow = time.Hour
+	defaultTOTPIssuer         = "RSVP Starter"
+	defaultPreAuthDuration    = time.Minute * 5
+	defaultCaptchaTimeout     = time.Second * 5
+	defaultCaptchaThreshold   = 0.5
+	defaultMessagingTimeout   = time.Second * 10
+	defaultPublicURL          = "http://localhost:6001"
+	defaultTwilioAPIURL       = "https://api.twilio.com"
+	defaultSMTPPort           = 587
+	defaultEmailTimeout       = time.Second * 10
+	defaultReminderDelay      = time.Hour * 72
+	defaultReminderMaxCount   = 2
+	defaultQuietHours         = "21:00-09:00"
 )
 
+// Supported values for STORAGE_DRIVER.
+const (
+	PostgresDriver = "postgres"
+	SQLiteDriver   = "sqlite"
+	MemoryDriver   = "memory"
+)
+
+// Supported values for CAPTCHA_PROVIDER. The always pass and always fail stubs are for tests and
+// offline environments.
+const (
+	ReCAPTCHAV2Provider = "recaptcha"
+	ReCAPTCHAV3Provider = "recaptcha_v3"
+	HCaptchaProvider    = "hcaptcha"
+	TurnstileProvider   = "turnstile"
+	AlwaysPassProvider  = "always_pass"
+	AlwaysFailProvider  = "always_fail"
+)
+
+// Supported values for MESSAGING_PROVIDER. The stdout and file providers write messages out instead of
+// sending them, for development and offline environments.
+const (
+	TwilioMessagingProvider  = "twilio"
+	WebhookMessagingProvider = "webhook"
+	StdoutMessagingProvider  = "stdout"
+	FileMessagingProvider    = "file"
+)
+
+// Supported values for EMAIL_PROVIDER. As with messaging, the stdout and file providers write emails out
+// instead of sending them.
+const (
+	SMTPEmailProvider   = "smtp"
+	StdoutEmailProvider = "stdout"
+	FileEmailProvider   = "file"
+)
+
+// captchaVerifyURLs are the verify endpoints used unless CAPTCHA_VERIFY_URL points elsewhere
+var captchaVerifyURLs = map[string]string{
+	ReCAPTCHAV2Provider: "https://www.google.com/recaptcha/api/siteverify",
+	ReCAPTCHAV3Provider: "https://www.google.com/recaptcha/api/siteverify",
+	HCaptchaProvider:    "https://hcaptcha.com/siteverify",
+	TurnstileProvider:   "https://challenges.cloudflare.com/turnstile/v0/siteverify",
+}
+
 // Config holds necessary config values.
 type Config struct {
-	HTTPPort int
-	Postgres PostgresConfig
-	Session  SessionConfig
-	JWT      JWTConfig
+	HTTPPort  int
+	Storage   StorageConfig
+	Postgres  PostgresConfig
+	SQLite    SQLiteConfig
+	Session   SessionConfig
+	JWT       JWTConfig
+	Trash     TrashConfig
+	Security  SecurityConfig
+	Login     LoginConfig
+	Captcha   CaptchaConfig
+	Messaging MessagingConfig
+	Email     EmailConfig
+	Reminder  ReminderConfig
+}
+
+// StorageConfig selects which storage backend the API uses.
+type StorageConfig struct {
+	Driver string
 }
 
 // PostgresConfig contains the connection URL and other DB options.
@@ -29,15 +111,118 @@ type PostgresConfig struct {
 	MaxConnections int
 }
 
-// SessionConfig contains the duration of each valid session.
+// SQLiteConfig contains the path to the database file, which is created if it does not exist.
+type SQLiteConfig struct {
+	Path string
+}
+
+// SessionConfig contains how long auth tokens and refresh tokens stay valid. A session ends once its
+// refresh token has not been used for the refresh duration. With sliding sessions, auth tokens past half
+// of their duration are renewed by any authenticated request.
 type SessionConfig struct {
-	Duration time.Duration
+	Duration        time.Duration
+	RefreshDuration time.Duration
+	Sliding         bool
 }
 
-// JWTConfig contains the config values required to create valid JWTs.
+// JWTConfig contains the config values required to create valid JWTs. Tokens are signed with the
+// signing key and name it in their kid header. Tokens naming one of the verification keys are accepted
+// as well so keys can be rotated without logging everyone out.
 type JWTConfig struct {
-	HMACSecret  string
-	TokenIssuer string
+	SigningKey       JWTKey
+	VerificationKeys []JWTKey
+	TokenIssuer      string
+}
+
+// TrashConfig contains how long deleted records are kept before they are purged and how often the
+// purge runs. A purge interval of zero or less disables purging.
+type TrashConfig struct {
+	Retention     time.Duration
+	PurgeInterval time.Duration
+}
+
+// SecurityConfig contains the bcrypt cost used to hash admin passwords. Existing hashes keep the cost
+// they were created with until the password is next changed. The TOTP issuer names the account in
+// authenticator apps, and the pre-auth duration is how long users with two-factor authentication have
+// to enter a code after their password.
+type SecurityConfig struct {
+	BcryptCost      int
+	TOTPIssuer      string
+	PreAuthDuration time.Duration
+}
+
+// LoginConfig contains the limits on failed logins. Every failure for a username doubles how long it
+// has to wait before the next attempt, starting at the backoff, until MaxAttempts failures lock it out
+// for the lockout duration. A client IP is locked out the same way after MaxAttemptsPerIP failures
+// across any usernames. Failures are forgotten once the attempt window passes without another one.
+// The client IP is the address a request came from, unless it came through one of the trusted proxies
+// in which case the X-Forwarded-For or X-Real-Ip header they set is used.
+type LoginConfig struct {
+	MaxAttempts      int
+	MaxAttemptsPerIP int
+	Backoff          time.Duration
+	LockoutDuration  time.Duration
+	AttemptWindow    time.Duration
+	TrustedProxies   []*net.IPNet
+}
+
+// CaptchaConfig selects the CAPTCHA provider that logins and guest RSVPs are checked against. The site
+// key is handed to the client for rendering the widget and the secret is used to verify its tokens.
+// reCAPTCHA v3 tokens also need a score of at least the score threshold.
+type CaptchaConfig struct {
+	Provider       string
+	SiteKey        string
+	Secret         string
+	VerifyURL      string
+	Timeout        time.Duration
+	ScoreThreshold float64
+}
+
+// MessagingConfig selects the provider that text messages to guests are sent through. Twilio needs the
+// account sid and auth token, the webhook provider posts each message to the webhook url and the file
+// provider appends them to the file path. Messages are sent from the From number where the provider
+// takes one, and link to their invitation under the public url. Providers report how messages fared
+// through the status webhook, signing each report with the status webhook secret.
+type MessagingConfig struct {
+	Provider   string
+	From       string
+	AccountSID string
+	AuthToken  string
+	APIURL     string
+	WebhookURL string
+	FilePath   string
+	Timeout    time.Duration
+	PublicURL  string
+
+	StatusWebhookSecret string
+}
+
+// EmailConfig selects how emails to guests are sent. The SMTP server is logged into with the username
+// and password when they are set, and switched to TLS when it offers STARTTLS. The file provider
+// appends emails to the file path. Emails are sent from the From address.
+type EmailConfig struct {
+	Provider string
+	From     string
+	SMTPHost string
+	SMTPPort int
+	Username string
+	Password string
+	FilePath string
+	Timeout  time.Duration
+}
+
+// ReminderConfig contains when guests who have not replied to a sent invitation are reminded. A reminder
+// is due once the delay has passed since the invitation or the last reminder was sent, up to MaxCount
+// reminders. Reminders falling due within the quiet hours, given as times of day in the location, wait
+// until the quiet hours end. Due reminders are sent once per interval, and an interval of zero or less
+// turns sending them off.
+type ReminderConfig struct {
+	Interval        time.Duration
+	Delay           time.Duration
+	MaxCount        int
+	QuietHoursStart time.Duration
+	QuietHoursEnd   time.Duration
+	Location        *time.Location
 }
 
 var (
@@ -49,11 +234,27 @@ var (
 // This function panics if required environment values are not set properly.
 func LoadConfig() Config {
 	once.Do(func() {
+		storageConfig := loadStorageConfig()
+
 		config = Config{
-			HTTPPort: parseHTTPPort(),
-			Postgres: loadPostgresConfig(),
-			Session:  loadSessionConfig(),
-			JWT:      loadJWTConfig(),
+			HTTPPort:  parseHTTPPort(),
+			Storage:   storageConfig,
+			Session:   loadSessionConfig(),
+			JWT:       loadJWTConfig(),
+			Trash:     loadTrashConfig(),
+			Security:  loadSecurityConfig(),
+			Login:     loadLoginConfig(),
+			Captcha:   loadCaptchaConfig(),
+			Messaging: loadMessagingConfig(),
+			Email:     loadEmailConfig(),
+			Reminder:  loadReminderConfig(),
+		}
+
+		switch storageConfig.Driver {
+		case PostgresDriver:
+			config.Postgres = loadPostgresConfig()
+		case SQLiteDriver:
+			config.SQLite = loadSQLiteConfig()
 		}
 	})
 
@@ -74,6 +275,25 @@ func parseHTTPPort() int {
 	return int(httpPort)
 }
 
+func loadStorageConfig() StorageConfig {
+	driver, ok := os.LookupEnv("STORAGE_DRIVER")
+	if !ok || driver == "" {
+		return StorageConfig{
+			Driver: PostgresDriver,
+		}
+	}
+
+	switch driver {
+	case PostgresDriver, SQLiteDriver, MemoryDriver:
+	default:
+		logrus.Fatalf("STORAGE_DRIVER value '%s' is not one of %v, %v, %v", driver, PostgresDriver, SQLiteDriver, MemoryDriver)
+	}
+
+	return StorageConfig{
+		Driver: driver,
+	}
+}
+
 func loadPostgresConfig() PostgresConfig {
 	postgresURL, ok := os.LookupEnv("POSTGRES_URL")
 	if !ok {
@@ -87,25 +307,441 @@ func loadPostgresConfig() PostgresConfig {
 	}
 }
 
-func loadSessionConfig() SessionConfig {
-	return SessionConfig{
-		Duration: sessionDuration,
+func loadSQLiteConfig() SQLiteConfig {
+	sqlitePath, ok := os.LookupEnv("SQLITE_PATH")
+	if !ok || sqlitePath == "" {
+		sqlitePath = defaultSQLitePath
+	}
+
+	return SQLiteConfig{
+		Path: sqlitePath,
 	}
 }
 
-func loadJWTConfig() JWTConfig {
-	hmacSecret, ok := os.LookupEnv("HMAC_SECRET")
-	if !ok {
-		logrus.Fatal("HMAC_SECRET not set")
+func loadSessionConfig() SessionConfig {
+	sessionConfig := SessionConfig{
+		Duration:        parseDuration("SESSION_DURATION", defaultSessionDuration),
+		RefreshDuration: parseDuration("SESSION_REFRESH_DURATION", defaultRefreshDuration),
 	}
 
+	if sessionConfig.Duration <= 0 {
+		logrus.Fatal("SESSION_DURATION must be more than 0")
+	}
+	if sessionConfig.RefreshDuration < sessionConfig.Duration {
+		logrus.Fatal("SESSION_REFRESH_DURATION cannot be shorter than SESSION_DURATION")
+	}
+
+	slidingStr, ok := os.LookupEnv("SESSION_SLIDING")
+	if ok && slidingStr != "" {
+		sliding, err := strconv.ParseBool(slidingStr)
+		if err != nil {
+			logrus.Fatalf("SESSION_SLIDING value '%s' could not be parsed due to %s", slidingStr, err.Error())
+		}
+		sessionConfig.Sliding = sliding
+	}
+
+	return sessionConfig
+}
+
+func loadJWTConfig() JWTConfig {
 	tokenIssuer, ok := os.LookupEnv("TOKEN_ISSUER")
 	if !ok {
 		logrus.Fatal("TOKEN_ISSUER not set")
 	}
 
+	algorithm, ok := os.LookupEnv("JWT_ALGORITHM")
+	if !ok || algorithm == "" {
+		algorithm = HS256Algorithm
+	}
+
+	var signingKey JWTKey
+
+	if algorithm == HS256Algorithm {
+		hmacSecret, ok := os.LookupEnv("HMAC_SECRET")
+		if !ok {
+			logrus.Fatal("HMAC_SECRET not set")
+		}
+
+		signingKey = NewHMACJWTKey(hmacSecret)
+	} else {
+		privateKeyFile, ok := os.LookupEnv("JWT_PRIVATE_KEY_FILE")
+		if !ok || privateKeyFile == "" {
+			logrus.Fatalf("JWT_PRIVATE_KEY_FILE not set for JWT_ALGORITHM %v", algorithm)
+		}
+
+		signingKey = readJWTKey("JWT_PRIVATE_KEY_FILE", algorithm, privateKeyFile)
+		if !signingKey.CanSign() {
+			logrus.Fatalf("JWT_PRIVATE_KEY_FILE '%s' holds a public key rather than a private key", privateKeyFile)
+		}
+	}
+
 	return JWTConfig{
-		HMACSecret:  hmacSecret,
-		TokenIssuer: tokenIssuer,
+		SigningKey:       signingKey,
+		VerificationKeys: parseJWTVerificationKeys(),
+		TokenIssuer:      tokenIssuer,
+	}
+}
+
+// parseJWTVerificationKeys reads JWT_VERIFICATION_KEYS, a comma separated list of algorithm:key pairs
+// where the key is the secret for HS256 and the path of a PEM file otherwise
+func parseJWTVerificationKeys() []JWTKey {
+	verificationKeysStr, ok := os.LookupEnv("JWT_VERIFICATION_KEYS")
+	if !ok || verificationKeysStr == "" {
+		return nil
+	}
+
+	var verificationKeys []JWTKey
+
+	for _, entry := range strings.Split(verificationKeysStr, ",") {
+		parts := strings.SplitN(strings.TrimSpace(entry), ":", 2)
+		if len(parts) != 2 || parts[1] == "" {
+			logrus.Fatalf("JWT_VERIFICATION_KEYS entry '%s' must be in the form algorithm:key", entry)
+		}
+
+		if parts[0] == HS256Algorithm {
+			verificationKeys = append(verificationKeys, NewHMACJWTKey(parts[1]))
+			continue
+		}
+
+		verificationKeys = append(verificationKeys, readJWTKey("JWT_VERIFICATION_KEYS", parts[0], parts[1]))
+	}
+
+	return verificationKeys
+}
+
+func readJWTKey(key, algorithm, path string) JWTKey {
+	pemBytes, err := ioutil.ReadFile(path)
+	if err != nil {
+		logrus.Fatalf("%s file '%s' could not be read due to %s", key, path, err.Error())
+	}
+
+	jwtKey, err := ParseJWTKey(algorithm, pemBytes)
+	if err != nil {
+		logrus.Fatalf("%s file '%s' could not be parsed due to %s", key, path, err.Error())
+	}
+
+	return jwtKey
+}
+
+func loadTrashConfig() TrashConfig {
+	return TrashConfig{
+		Retention:     parseDuration("TRASH_RETENTION", defaultTrashRetention),
+		PurgeInterval: parseDuration("TRASH_PURGE_INTERVAL", defaultTrashPurgeInterval),
+	}
+}
+
+func loadSecurityConfig() SecurityConfig {
+	securityConfig := SecurityConfig{
+		BcryptCost:      parseBcryptCost(),
+		TOTPIssuer:      defaultTOTPIssuer,
+		PreAuthDuration: parseDuration("PRE_AUTH_DURATION", defaultPreAuthDuration),
+	}
+
+	totpIssuer, ok := os.LookupEnv("TOTP_ISSUER")
+	if ok && totpIssuer != "" {
+		securityConfig.TOTPIssuer = totpIssuer
+	}
+
+	if securityConfig.PreAuthDuration <= 0 {
+		logrus.Fatal("PRE_AUTH_DURATION must be more than 0")
+	}
+
+	return securityConfig
+}
+
+func parseBcryptCost() int {
+	bcryptCostStr, ok := os.LookupEnv("BCRYPT_COST")
+	if !ok || bcryptCostStr == "" {
+		return bcrypt.DefaultCost
+	}
+
+	bcryptCost, err := strconv.Atoi(bcryptCostStr)
+	if err != nil {
+		logrus.Fatalf("BCRYPT_COST value '%s' could not be parsed due to %s", bcryptCostStr, err.Error())
+	}
+	if bcryptCost < bcrypt.MinCost || bcryptCost > bcrypt.MaxCost {
+		logrus.Fatalf("BCRYPT_COST value '%s' must be between %v and %v", bcryptCostStr, bcrypt.MinCost, bcrypt.MaxCost)
+	}
+
+	return bcryptCost
+}
+
+func loadLoginConfig() LoginConfig {
+	return LoginConfig{
+		MaxAttempts:      parsePositiveInt("LOGIN_MAX_ATTEMPTS", defaultLoginMaxAttempts),
+		MaxAttemptsPerIP: parsePositiveInt("LOGIN_MAX_ATTEMPTS_PER_IP", defaultLoginMaxPerIP),
+		Backoff:          parseDuration("LOGIN_BACKOFF", defaultLoginBackoff),
+		LockoutDuration:  parseDuration("LOGIN_LOCKOUT_DURATION", defaultLoginLockout),
+		AttemptWindow:    parseDuration("LOGIN_ATTEMPT_WINDOW", defaultLoginAttemptWindow),
+		TrustedProxies:   parseTrustedProxies(),
+	}
+}
+
+// parseTrustedProxies reads TRUSTED_PROXIES, a comma separated list of IP addresses and CIDR ranges
+func parseTrustedProxies() []*net.IPNet {
+	trustedProxiesStr, ok := os.LookupEnv("TRUSTED_PROXIES")
+	if !ok || trustedProxiesStr == "" {
+		return nil
+	}
+
+	var trustedProxies []*net.IPNet
+
+	for _, entry := range strings.Split(trustedProxiesStr, ",") {
+		entry = strings.TrimSpace(entry)
+
+		if ip := net.ParseIP(entry); ip != nil {
+			bits := 8 * len(ip.To16())
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 32
+			}
+
+			trustedProxies = append(trustedProxies, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+
+		_, ipNet, err := net.ParseCIDR(entry)
+		if err != nil {
+			logrus.Fatalf("TRUSTED_PROXIES entry '%s' must be an IP address or CIDR range", entry)
+		}
+
+		trustedProxies = append(trustedProxies, ipNet)
+	}
+
+	return trustedProxies
+}
+
+func loadCaptchaConfig() CaptchaConfig {
+	captchaConfig := CaptchaConfig{
+		Provider:       ReCAPTCHAV2Provider,
+		SiteKey:        os.Getenv("CAPTCHA_SITE_KEY"),
+		Secret:         os.Getenv("CAPTCHA_SECRET"),
+		Timeout:        parseDuration("CAPTCHA_TIMEOUT", defaultCaptchaTimeout),
+		ScoreThreshold: defaultCaptchaThreshold,
+	}
+
+	provider, ok := os.LookupEnv("CAPTCHA_PROVIDER")
+	if ok && provider != "" {
+		captchaConfig.Provider = provider
+	}
+
+	switch captchaConfig.Provider {
+	case AlwaysPassProvider, AlwaysFailProvider:
+		return captchaConfig
+	case ReCAPTCHAV2Provider, ReCAPTCHAV3Provider, HCaptchaProvider, TurnstileProvider:
+	default:
+		logrus.Fatalf("CAPTCHA_PROVIDER value '%s' is not one of %v, %v, %v, %v, %v, %v", captchaConfig.Provider,
+			ReCAPTCHAV2Provider, ReCAPTCHAV3Provider, HCaptchaProvider, TurnstileProvider, AlwaysPassProvider, AlwaysFailProvider)
+	}
+
+	// Without a secret every token fails to verify, which keeps logins closed rather than open
+	if captchaConfig.Secret == "" {
+		logrus.Warnf("CAPTCHA_SECRET not set so %v tokens cannot be verified", captchaConfig.Provider)
+	}
+
+	captchaConfig.VerifyURL = captchaVerifyURLs[captchaConfig.Provider]
+	verifyURL, ok := os.LookupEnv("CAPTCHA_VERIFY_URL")
+	if ok && verifyURL != "" {
+		captchaConfig.VerifyURL = verifyURL
+	}
+
+	if captchaConfig.Timeout <= 0 {
+		logrus.Fatal("CAPTCHA_TIMEOUT must be more than 0")
+	}
+
+	thresholdStr, ok := os.LookupEnv("CAPTCHA_SCORE_THRESHOLD")
+	if ok && thresholdStr != "" {
+		threshold, err := strconv.ParseFloat(thresholdStr, 64)
+		if err != nil {
+			logrus.Fatalf("CAPTCHA_SCORE_THRESHOLD value '%s' could not be parsed due to %s", thresholdStr, err.Error())
+		}
+		if threshold < 0 || threshold > 1 {
+			logrus.Fatalf("CAPTCHA_SCORE_THRESHOLD value '%s' must be between 0 and 1", thresholdStr)
+		}
+		captchaConfig.ScoreThreshold = threshold
+	}
+
+	return captchaConfig
+}
+
+func loadMessagingConfig() MessagingConfig {
+	messagingConfig := MessagingConfig{
+		Provider:   StdoutMessagingProvider,
+		From:       os.Getenv("MESSAGING_FROM"),
+		AccountSID: os.Getenv("TWILIO_ACCOUNT_SID"),
+		AuthToken:  os.Getenv("TWILIO_AUTH_TOKEN"),
+		APIURL:     defaultTwilioAPIURL,
+		WebhookURL: os.Getenv("MESSAGING_WEBHOOK_URL"),
+		FilePath:   os.Getenv("MESSAGING_FILE_PATH"),
+		Timeout:    parseDuration("MESSAGING_TIMEOUT", defaultMessagingTimeout),
+		PublicURL:  defaultPublicURL,
+
+		StatusWebhookSecret: os.Getenv("STATUS_WEBHOOK_SECRET"),
+	}
+
+	provider, ok := os.LookupEnv("MESSAGING_PROVIDER")
+	if ok && provider != "" {
+		messagingConfig.Provider = provider
+	}
+
+	switch messagingConfig.Provider {
+	case TwilioMessagingProvider:
+		if messagingConfig.AccountSID == "" || messagingConfig.AuthToken == "" {
+			logrus.Fatal("TWILIO_ACCOUNT_SID and TWILIO_AUTH_TOKEN must be set for the twilio messaging provider")
+		}
+		if messagingConfig.From == "" {
+			logrus.Fatal("MESSAGING_FROM must be set for the twilio messaging provider")
+		}
+	case WebhookMessagingProvider:
+		if messagingConfig.WebhookURL == "" {
+			logrus.Fatal("MESSAGING_WEBHOOK_URL must be set for the webhook messaging provider")
+		}
+	case FileMessagingProvider:
+		if messagingConfig.FilePath == "" {
+			logrus.Fatal("MESSAGING_FILE_PATH must be set for the file messaging provider")
+		}
+	case StdoutMessagingProvider:
+	default:
+		logrus.Fatalf("MESSAGING_PROVIDER value '%s' is not one of %v, %v, %v, %v", messagingConfig.Provider,
+			TwilioMessagingProvider, WebhookMessagingProvider, StdoutMessagingProvider, FileMessagingProvider)
+	}
+
+	apiURL, ok := os.LookupEnv("TWILIO_API_URL")
+	if ok && apiURL != "" {
+		messagingConfig.APIURL = apiURL
 	}
+
+	if messagingConfig.Timeout <= 0 {
+		logrus.Fatal("MESSAGING_TIMEOUT must be more than 0")
+	}
+
+	publicURL, ok := os.LookupEnv("PUBLIC_URL")
+	if ok && publicURL != "" {
+		messagingConfig.PublicURL = publicURL
+	}
+	messagingConfig.PublicURL = strings.TrimRight(messagingConfig.PublicURL, "/")
+
+	return messagingConfig
+}
+
+func loadEmailConfig() EmailConfig {
+	emailConfig := EmailConfig{
+		Provider: StdoutEmailProvider,
+		From:     os.Getenv("EMAIL_FROM"),
+		SMTPHost: os.Getenv("SMTP_HOST"),
+		SMTPPort: parsePositiveInt("SMTP_PORT", defaultSMTPPort),
+		Username: os.Getenv("SMTP_USERNAME"),
+		Password: os.Getenv("SMTP_PASSWORD"),
+		FilePath: os.Getenv("EMAIL_FILE_PATH"),
+		Timeout:  parseDuration("EMAIL_TIMEOUT", defaultEmailTimeout),
+	}
+
+	provider, ok := os.LookupEnv("EMAIL_PROVIDER")
+	if ok && provider != "" {
+		emailConfig.Provider = provider
+	}
+
+	switch emailConfig.Provider {
+	case SMTPEmailProvider:
+		if emailConfig.SMTPHost == "" {
+			logrus.Fatal("SMTP_HOST must be set for the smtp email provider")
+		}
+		if emailConfig.From == "" {
+			logrus.Fatal("EMAIL_FROM must be set for the smtp email provider")
+		}
+	case FileEmailProvider:
+		if emailConfig.FilePath == "" {
+			logrus.Fatal("EMAIL_FILE_PATH must be set for the file email provider")
+		}
+	case StdoutEmailProvider:
+	default:
+		logrus.Fatalf("EMAIL_PROVIDER value '%s' is not one of %v, %v, %v", emailConfig.Provider,
+			SMTPEmailProvider, StdoutEmailProvider, FileEmailProvider)
+	}
+
+	if emailConfig.Timeout <= 0 {
+		logrus.Fatal("EMAIL_TIMEOUT must be more than 0")
+	}
+
+	return emailConfig
+}
+
+func loadReminderConfig() ReminderConfig {
+	reminderConfig := ReminderConfig{
+		Interval: parseDuration("REMINDER_INTERVAL", 0),
+		Delay:    parseDuration("REMINDER_DELAY", defaultReminderDelay),
+		MaxCount: parsePositiveInt("REMINDER_MAX_COUNT", defaultReminderMaxCount),
+		Location: time.Local,
+	}
+
+	if reminderConfig.Delay <= 0 {
+		logrus.Fatal("REMINDER_DELAY must be more than 0")
+	}
+
+	quietHours, ok := os.LookupEnv("REMINDER_QUIET_HOURS")
+	if !ok || quietHours == "" {
+		quietHours = defaultQuietHours
+	}
+	reminderConfig.QuietHoursStart, reminderConfig.QuietHoursEnd = parseQuietHours(quietHours)
+
+	timezone, ok := os.LookupEnv("REMINDER_TIMEZONE")
+	if ok && timezone != "" {
+		location, err := time.LoadLocation(timezone)
+		if err != nil {
+			logrus.Fatalf("REMINDER_TIMEZONE value '%s' could not be loaded due to %s", timezone, err.Error())
+		}
+		reminderConfig.Location = location
+	}
+
+	return reminderConfig
+}
+
+// parseQuietHours reads a range of times of day such as 21:00-09:00 into how long after midnight each
+// of them is
+func parseQuietHours(quietHours string) (start, end time.Duration) {
+	times := strings.Split(quietHours, "-")
+	if len(times) != 2 {
+		logrus.Fatalf("REMINDER_QUIET_HOURS value '%s' must be a range of times such as %s", quietHours, defaultQuietHours)
+	}
+
+	var offsets [2]time.Duration
+	for idx, timeOfDay := range times {
+		parsed, err := time.Parse("15:04", strings.TrimSpace(timeOfDay))
+		if err != nil {
+			logrus.Fatalf("REMINDER_QUIET_HOURS value '%s' could not be parsed due to %s", quietHours, err.Error())
+		}
+		offsets[idx] = time.Duration(parsed.Hour())*time.Hour + time.Duration(parsed.Minute())*time.Minute
+	}
+
+	return offsets[0], offsets[1]
+}
+
+func parsePositiveInt(key string, defaultValue int) int {
+	valueStr, ok := os.LookupEnv(key)
+	if !ok || valueStr == "" {
+		return defaultValue
+	}
+
+	value, err := strconv.Atoi(valueStr)
+	if err != nil {
+		logrus.Fatalf("%s value '%s' could not be parsed due to %s", key, valueStr, err.Error())
+	}
+	if value <= 0 {
+		logrus.Fatalf("%s value '%s' must be more than 0", key, valueStr)
+	}
+
+	return value
+}
+
+func parseDuration(key string, defaultDuration time.Duration) time.Duration {
+	durationStr, ok := os.LookupEnv(key)
+	if !ok || durationStr == "" {
+		return defaultDuration
+	}
+
+	duration, err := time.ParseDuration(durationStr)
+	if err != nil {
+		logrus.Fatalf("%s value '%s' could not be parsed due to %s", key, durationStr, err.Error())
+	}
+
+	return duration
 }
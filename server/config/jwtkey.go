@@ -0,0 +1,135 @@
+package config
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+)
+
+// Algorithms JWTs can be signed with
+const (
+	HS256Algorithm = "HS256"
+	RS256Algorithm = "RS256"
+	ES256Algorithm = "ES256"
+	EdDSAAlgorithm = "EdDSA"
+)
+
+const minimumRSAKeyBits = 2048
+
+// JWTKey is an HMAC secret for HS256, or a key pair for RS256, ES256 and EdDSA. Keys read from a public
+// key have no private key and can only verify tokens. The id is a thumbprint of the secret or public
+// key, so it never needs configuring and two different keys never share one.
+type JWTKey struct {
+	ID         string
+	Algorithm  string
+	Secret     []byte
+	PrivateKey crypto.Signer
+	PublicKey  crypto.PublicKey
+}
+
+// IsAsymmetric tells keys with a public half that can be published apart from HMAC secrets
+func (k JWTKey) IsAsymmetric() bool {
+	return k.Algorithm != HS256Algorithm
+}
+
+// CanSign is false for keys that were read from a public key
+func (k JWTKey) CanSign() bool {
+	return len(k.Secret) > 0 || k.PrivateKey != nil
+}
+
+func NewHMACJWTKey(secret string) JWTKey {
+	return JWTKey{
+		ID:        keyID([]byte(secret)),
+		Algorithm: HS256Algorithm,
+		Secret:    []byte(secret),
+	}
+}
+
+// ParseJWTKey reads a PEM encoded private or public key for the algorithm. Private keys may be PKCS #8,
+// PKCS #1 for RSA or SEC 1 for ECDSA and public keys PKIX.
+func ParseJWTKey(algorithm string, pemBytes []byte) (JWTKey, error) {
+	block, _ := pem.Decode(pemBytes)
+	if block == nil {
+		return JWTKey{}, errors.New("key is not PEM encoded")
+	}
+
+	var privateKey crypto.Signer
+	var publicKey crypto.PublicKey
+
+	if parsedKey, err := x509.ParsePKIXPublicKey(block.Bytes); err == nil {
+		publicKey = parsedKey
+	} else if parsedKey, err := x509.ParsePKCS8PrivateKey(block.Bytes); err == nil {
+		signer, ok := parsedKey.(crypto.Signer)
+		if !ok {
+			return JWTKey{}, errors.New("private key cannot sign")
+		}
+		privateKey = signer
+	} else if parsedKey, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		privateKey = parsedKey
+	} else if parsedKey, err := x509.ParseECPrivateKey(block.Bytes); err == nil {
+		privateKey = parsedKey
+	} else {
+		return JWTKey{}, errors.New("key is not a supported private or public key")
+	}
+
+	if privateKey != nil {
+		publicKey = privateKey.Public()
+	}
+
+	err := checkKeyAlgorithm(algorithm, publicKey)
+	if err != nil {
+		return JWTKey{}, err
+	}
+
+	publicKeyBytes, err := x509.MarshalPKIXPublicKey(publicKey)
+	if err != nil {
+		return JWTKey{}, err
+	}
+
+	return JWTKey{
+		ID:         keyID(publicKeyBytes),
+		Algorithm:  algorithm,
+		PrivateKey: privateKey,
+		PublicKey:  publicKey,
+	}, nil
+}
+
+func checkKeyAlgorithm(algorithm string, publicKey crypto.PublicKey) error {
+	switch algorithm {
+	case RS256Algorithm:
+		rsaKey, ok := publicKey.(*rsa.PublicKey)
+		if !ok {
+			return fmt.Errorf("%v needs an RSA key", algorithm)
+		}
+		if rsaKey.N.BitLen() < minimumRSAKeyBits {
+			return fmt.Errorf("%v needs an RSA key of at least %v bits", algorithm, minimumRSAKeyBits)
+		}
+	case ES256Algorithm:
+		ecdsaKey, ok := publicKey.(*ecdsa.PublicKey)
+		if !ok || ecdsaKey.Curve != elliptic.P256() {
+			return fmt.Errorf("%v needs an ECDSA key on the P-256 curve", algorithm)
+		}
+	case EdDSAAlgorithm:
+		if _, ok := publicKey.(ed25519.PublicKey); !ok {
+			return fmt.Errorf("%v needs an Ed25519 key", algorithm)
+		}
+	default:
+		return fmt.Errorf("%v is not one of %v, %v or %v", algorithm, RS256Algorithm, ES256Algorithm, EdDSAAlgorithm)
+	}
+
+	return nil
+}
+
+func keyID(keyMaterial []byte) string {
+	hash := sha256.Sum256(keyMaterial)
+
+	return base64.RawURLEncoding.EncodeToString(hash[:12])
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"github.com/rawfish-dev/rsvp-starter/server/config"
+	"github.com/rawfish-dev/rsvp-starter/server/domain"
+	"github.com/rawfish-dev/rsvp-starter/server/services/cache"
+	"github.com/rawfish-dev/rsvp-starter/server/services/jwt"
+	"github.com/rawfish-dev/rsvp-starter/server/services/memory"
+	"github.com/rawfish-dev/rsvp-starter/server/services/session"
+	"github.com/rawfish-dev/rsvp-starter/server/services/user"
+
+	"github.com/Sirupsen/logrus"
+	"golang.org/x/net/context"
+)
+
+const (
+	demoUsername = "demo"
+	demoPassword = "password"
+)
+
+// seedDemoData fills the shared in-memory store, which is the same instance the API storage factories return.
+func seedDemoData(loadedConfig config.Config) {
+	ctxlogger := logrus.New()
+	ctx := context.Background()
+	ctx = context.WithValue(ctx, "logger", ctxlogger)
+
+	memoryService := memory.NewService(ctx)
+
+	err := memoryService.SeedDemoData()
+	if err != nil {
+		ctxlogger.Fatalf("demo - unable to seed demo data due to %v", err)
+	}
+
+	sessionService := session.NewService(ctx, loadedConfig.Session, jwt.NewService(ctx, loadedConfig.JWT), cache.NewService(ctx), memoryService)
+
+	_, err = user.NewService(ctx, loadedConfig.Security, memoryService, sessionService).CreateUser(&domain.UserCreateRequest{
+		Username: demoUsername,
+		Password: demoPassword,
+		Role:     domain.RoleOwner,
+	})
+	if err != nil {
+		ctxlogger.Fatalf("demo - unable to seed demo user due to %v", err)
+	}
+}
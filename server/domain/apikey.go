@@ -0,0 +1,70 @@
+package domain
+
+// Scope is an area of the admin API an API key may read or write
+type Scope string
+
+const (
+	ScopeCategoriesRead   Scope = "categories:read"
+	ScopeCategoriesWrite  Scope = "categories:write"
+	ScopeInvitationsRead  Scope = "invitations:read"
+	ScopeInvitationsWrite Scope = "invitations:write"
+	ScopeRSVPsRead        Scope = "rsvps:read"
+	ScopeRSVPsWrite       Scope = "rsvps:write"
+)
+
+var Scopes = []Scope{
+	ScopeCategoriesRead,
+	ScopeCategoriesWrite,
+	ScopeInvitationsRead,
+	ScopeInvitationsWrite,
+	ScopeRSVPsRead,
+	ScopeRSVPsWrite,
+}
+
+func IsValidScope(scope Scope) bool {
+	for _, validScope := range Scopes {
+		if scope == validScope {
+			return true
+		}
+	}
+
+	return false
+}
+
+func HasScope(scopes []Scope, scope Scope) bool {
+	for _, grantedScope := range scopes {
+		if grantedScope == scope {
+			return true
+		}
+	}
+
+	return false
+}
+
+// APIKeyCreateRequest leaves ExpiresAt empty for keys that never expire, otherwise it is in RFC 3339
+type APIKeyCreateRequest struct {
+	Name      string  `json:"name"`
+	Scopes    []Scope `json:"scopes"`
+	ExpiresAt string  `json:"expiresAt"`
+}
+
+// APIKey lets scripts call the admin API without logging in. Only a hash of the key is kept and the
+// prefix is there to tell keys apart. ExpiresAt, LastUsedAt and RevokedAt are empty until they apply.
+type APIKey struct {
+	ID         int64   `json:"id"`
+	Name       string  `json:"name"`
+	Prefix     string  `json:"prefix"`
+	KeyHash    string  `json:"-"`
+	Scopes     []Scope `json:"scopes"`
+	CreatedBy  string  `json:"createdBy"`
+	ExpiresAt  string  `json:"expiresAt"`
+	LastUsedAt string  `json:"lastUsedAt"`
+	RevokedAt  string  `json:"revokedAt"`
+	CreatedAt  string  `json:"createdAt"`
+}
+
+// NewAPIKey is returned once when the key is created as it cannot be recovered afterwards
+type NewAPIKey struct {
+	APIKey
+	Key string `json:"key"`
+}
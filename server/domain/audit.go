@@ -0,0 +1,107 @@
+package domain
+
+import (
+	"encoding/json"
+)
+
+type AuditAction string
+
+const (
+	AuditCreated   AuditAction = "created"
+	AuditUpdated   AuditAction = "updated"
+	AuditDeleted   AuditAction = "deleted"
+	AuditRestored  AuditAction = "restored"
+	AuditLockedOut AuditAction = "locked_out"
+	AuditRevoked   AuditAction = "revoked"
+	AuditSent      AuditAction = "sent"
+	AuditReminded  AuditAction = "reminded"
+	AuditCheckedIn AuditAction = "checked_in"
+)
+
+type AuditEntityType string
+
+const (
+	CategoryAuditEntity   AuditEntityType = "category"
+	InvitationAuditEntity AuditEntityType = "invitation"
+	RSVPAuditEntity       AuditEntityType = "rsvp"
+	UserAuditEntity       AuditEntityType = "user"
+	LoginAuditEntity      AuditEntityType = "login"
+	APIKeyAuditEntity     AuditEntityType = "api_key"
+	TemplateAuditEntity   AuditEntityType = "template"
+)
+
+func IsValidAuditEntityType(entityType AuditEntityType) bool {
+	for _, validEntityType := range []AuditEntityType{CategoryAuditEntity, InvitationAuditEntity, RSVPAuditEntity, UserAuditEntity, LoginAuditEntity, APIKeyAuditEntity, TemplateAuditEntity} {
+		if entityType == validEntityType {
+			return true
+		}
+	}
+
+	return false
+}
+
+const (
+	// GuestActor is recorded for changes made by guests through their private invitation link
+	GuestActor = "guest"
+
+	// SystemActor is recorded for changes made outside of a request such as seeding demo data
+	SystemActor = "system"
+
+	// APIKeyActorPrefix comes before the name of the API key a change was made with. Usernames cannot
+	// contain a colon so the two are never confused.
+	APIKeyActorPrefix = "api_key:"
+
+	SortByAuditCreatedAt = "createdAt"
+)
+
+// Actor is whoever made a change. Guests are told apart by the private id of their invitation.
+type Actor struct {
+	Username            string
+	InvitationPrivateID string
+}
+
+func NewAdminActor(username string) Actor {
+	return Actor{Username: username}
+}
+
+func NewAPIKeyActor(name string) Actor {
+	return Actor{Username: APIKeyActorPrefix + name}
+}
+
+func NewGuestActor(invitationPrivateID string) Actor {
+	return Actor{Username: GuestActor, InvitationPrivateID: invitationPrivateID}
+}
+
+// AuditEntryCreateRequest holds JSON snapshots of the entity before and after the change, either of
+// which is the JSON null when the entity did not exist on that side of the change.
+type AuditEntryCreateRequest struct {
+	Actor      Actor
+	Action     AuditAction
+	EntityType AuditEntityType
+	EntityID   int64
+	Before     json.RawMessage
+	After      json.RawMessage
+}
+
+type AuditEntry struct {
+	ID                       int64           `json:"id"`
+	Actor                    string          `json:"actor"`
+	ActorInvitationPrivateID string          `json:"actorInvitationPrivateID,omitempty"`
+	Action                   AuditAction     `json:"action"`
+	EntityType               AuditEntityType `json:"entityType"`
+	EntityID                 int64           `json:"entityID"`
+	Before                   json.RawMessage `json:"before"`
+	After                    json.RawMessage `json:"after"`
+	CreatedAt                string          `json:"createdAt"`
+}
+
+type AuditListRequest struct {
+	ListRequest
+	EntityType AuditEntityType
+	EntityID   int64
+}
+
+type AuditList struct {
+	ListResponse
+	Entries []AuditEntry `json:"entries"`
+}
@@ -0,0 +1,7 @@
+package domain
+
+// CaptchaSettings are public so the client can render the widget of the configured provider
+type CaptchaSettings struct {
+	Provider string `json:"provider"`
+	SiteKey  string `json:"siteKey"`
+}
@@ -10,7 +10,8 @@ type CategoryUpdateRequest struct {
 }
 
 type Category struct {
-	ID    int64  `json:"id"`
-	Tag   string `json:"tag"`
-	Total int    `json:"total"`
+	ID        int64  `json:"id"`
+	Tag       string `json:"tag"`
+	Total     int    `json:"total"`
+	DeletedAt string `json:"deletedAt,omitempty"`
 }
@@ -2,4 +2,7 @@ package domain
 
 const (
 	ContextAuthToken = "authToken"
+	ContextActor     = "actor"
+	ContextRole      = "role"
+	ContextScopes    = "scopes"
 )
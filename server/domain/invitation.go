@@ -6,6 +6,8 @@ type BaseInvitation struct {
 	MaximumGuestCount int    `json:"maximumGuestCount"`
 	Notes             string `json:"notes"`
 	MobilePhoneNumber string `json:"mobilePhoneNumber"`
+	Email             string `json:"email"`
+	Language          string `json:"language"`
 }
 
 type InvitationCreateRequest struct {
@@ -14,14 +16,42 @@ type InvitationCreateRequest struct {
 
 type InvitationUpdateRequest struct {
 	BaseInvitation
-	ID     int64      `json:"id"`
-	Status RSVPStatus `json:"status"`
+	ID      int64      `json:"id"`
+	Status  RSVPStatus `json:"status"`
+	Version int64      `json:"version"`
 }
 
+// Invitation is reached by guests through the link holding its private id. LinkExpiresAt and
+// LinkRevokedAt are empty until they apply, and RequirePhoneConfirmation makes guests enter the mobile
+// phone number of the invitation before the link shows anything.
 type Invitation struct {
 	BaseInvitation
-	ID        int64      `json:"id"`
-	PrivateID string     `json:"privateID"`
-	Status    RSVPStatus `json:"status"`
-	UpdatedAt string     `json:"updatedAt"`
+	ID                       int64      `json:"id"`
+	PrivateID                string     `json:"privateID"`
+	Status                   RSVPStatus `json:"status"`
+	LinkExpiresAt            string     `json:"linkExpiresAt"`
+	LinkRevokedAt            string     `json:"linkRevokedAt"`
+	RequirePhoneConfirmation bool       `json:"requirePhoneConfirmation"`
+	UpdatedAt                string     `json:"updatedAt"`
+	Version                  int64      `json:"version"`
+	DeletedAt                string     `json:"deletedAt,omitempty"`
+
+	// Delivery is only filled in when listing invitations, with a delivery for each channel the
+	// invitation has been sent on
+	Delivery []MessageDelivery `json:"delivery,omitempty"`
+}
+
+// InvitationLinkUpdateRequest leaves LinkExpiresAt empty for links that never expire, otherwise it is in
+// RFC 3339
+type InvitationLinkUpdateRequest struct {
+	ID                       int64  `json:"id"`
+	LinkExpiresAt            string `json:"linkExpiresAt"`
+	RequirePhoneConfirmation bool   `json:"requirePhoneConfirmation"`
+	Version                  int64  `json:"version"`
+}
+
+// PhoneConfirmationRequired is returned to guests whose link needs the mobile phone number of the
+// invitation, which they send back in the X-Guest-Phone-Number header
+type PhoneConfirmationRequired struct {
+	PhoneConfirmationRequired bool `json:"phoneConfirmationRequired"`
 }
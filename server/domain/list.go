@@ -0,0 +1,115 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultPageSize = 50
+	MaximumPageSize = 200
+
+	SortByCategoryTag   = "tag"
+	SortByCategoryTotal = "total"
+
+	SortByInvitationGreeting          = "greeting"
+	SortByInvitationMaximumGuestCount = "maximumGuestCount"
+	SortByInvitationStatus            = "status"
+	SortByInvitationUpdatedAt         = "updatedAt"
+
+	SortByRSVPFullName   = "fullName"
+	SortByRSVPGuestCount = "guestCount"
+	SortByRSVPUpdatedAt  = "updatedAt"
+)
+
+// ListRequest is the paging and ordering shared by every list. Sort holds a field name which is
+// prefixed with "-" to sort in descending order.
+type ListRequest struct {
+	Page     int
+	PageSize int
+	Sort     string
+}
+
+// ApplyDefaults starts from the first page with the default page size and defaultSort when the
+// request leaves them out.
+func (l *ListRequest) ApplyDefaults(defaultSort string) {
+	if l.Page == 0 {
+		l.Page = 1
+	}
+	if l.PageSize == 0 {
+		l.PageSize = DefaultPageSize
+	}
+	if l.Sort == "" {
+		l.Sort = defaultSort
+	}
+}
+
+func (l ListRequest) Validate(sortFields ...string) (errorMessages []string) {
+	if l.Page < 1 {
+		errorMessages = append(errorMessages, "page must be at least 1")
+	}
+	if l.PageSize < 1 || l.PageSize > MaximumPageSize {
+		errorMessages = append(errorMessages, fmt.Sprintf("page size must be between %v to %v", 1, MaximumPageSize))
+	}
+
+	field, _ := l.SortField()
+	for _, sortField := range sortFields {
+		if field == sortField {
+			return errorMessages
+		}
+	}
+
+	return append(errorMessages, fmt.Sprintf("sort field %v is invalid", field))
+}
+
+func (l ListRequest) Offset() int {
+	return (l.Page - 1) * l.PageSize
+}
+
+func (l ListRequest) SortField() (field string, descending bool) {
+	if strings.HasPrefix(l.Sort, "-") {
+		return l.Sort[1:], true
+	}
+
+	return l.Sort, false
+}
+
+type ListResponse struct {
+	Page     int `json:"page"`
+	PageSize int `json:"pageSize"`
+	Total    int `json:"total"`
+}
+
+type CategoryListRequest struct {
+	ListRequest
+}
+
+type CategoryList struct {
+	ListResponse
+	Categories []Category `json:"categories"`
+}
+
+type InvitationListRequest struct {
+	ListRequest
+	CategoryID   int64
+	Status       RSVPStatus
+	UpdatedSince *time.Time
+}
+
+type InvitationList struct {
+	ListResponse
+	Invitations []Invitation `json:"invitations"`
+}
+
+type RSVPListRequest struct {
+	ListRequest
+	Attending    *bool
+	SpecialDiet  *bool
+	UpdatedSince *time.Time
+}
+
+type RSVPList struct {
+	ListResponse
+	RSVPs []RSVP `json:"rsvps"`
+}
@@ -0,0 +1,109 @@
+package domain
+
+type MessageChannel string
+
+const (
+	SMSChannel   MessageChannel = "sms"
+	EmailChannel MessageChannel = "email"
+)
+
+// MessageKind tells invitations apart from the reminders and RSVP confirmations sent after them.
+// Messages sent before kinds were recorded are unclassified, as nothing kept about them reliably tells
+// which they were.
+type MessageKind string
+
+const (
+	UnclassifiedMessage MessageKind = ""
+	InvitationMessage   MessageKind = "invitation"
+	ReminderMessage     MessageKind = "reminder"
+	ConfirmationMessage MessageKind = "confirmation"
+)
+
+type MessageStatus string
+
+// Messages the provider accepts are queued until it reports how they fared. Delivered and failed are
+// final, though a message reported as delivered can still fail later, such as an email that bounces.
+const (
+	MessageQueued    MessageStatus = "queued"
+	MessageSent      MessageStatus = "sent"
+	MessageDelivered MessageStatus = "delivered"
+	MessageFailed    MessageStatus = "failed"
+)
+
+func IsValidMessageStatus(status MessageStatus) bool {
+	for _, validStatus := range []MessageStatus{MessageQueued, MessageSent, MessageDelivered, MessageFailed} {
+		if status == validStatus {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Message records each attempt to send something to a guest. Only emails have a subject, and the body of
+// an email is its plain text version. ProviderMessageID is empty for messages the provider did not
+// accept, and Error holds the reason it gave or the reason it reported later. UpdatedAt is when the
+// status last changed.
+type Message struct {
+	ID                int64          `json:"id"`
+	InvitationID      int64          `json:"invitationID"`
+	Kind              MessageKind    `json:"kind"`
+	Channel           MessageChannel `json:"channel"`
+	Recipient         string         `json:"recipient"`
+	Subject           string         `json:"subject,omitempty"`
+	Body              string         `json:"body"`
+	Provider          string         `json:"provider"`
+	ProviderMessageID string         `json:"providerMessageID"`
+	Status            MessageStatus  `json:"status"`
+	Error             string         `json:"error"`
+	CreatedAt         string         `json:"createdAt"`
+	UpdatedAt         string         `json:"updatedAt"`
+}
+
+// MessageStatusUpdate is a provider reporting how a message it accepted has fared since
+type MessageStatusUpdate struct {
+	Provider          string        `json:"provider"`
+	ProviderMessageID string        `json:"providerMessageID"`
+	Status            MessageStatus `json:"status"`
+	Error             string        `json:"error"`
+}
+
+// MessageDelivery is how the latest invitation or reminder sent to a guest on a channel has fared
+type MessageDelivery struct {
+	Channel   MessageChannel `json:"channel"`
+	MessageID int64          `json:"messageID"`
+	Status    MessageStatus  `json:"status"`
+	Error     string         `json:"error,omitempty"`
+	UpdatedAt string         `json:"updatedAt"`
+}
+
+// Email holds both a plain text and an HTML version of the body, which mail clients choose between
+type Email struct {
+	To       string
+	Subject  string
+	TextBody string
+	HTMLBody string
+}
+
+// InvitationSendResult holds a message for each channel the invitation was sent on. Messages is empty
+// for invitations that were skipped without trying to send them, such as those without a mobile phone
+// number or email.
+type InvitationSendResult struct {
+	InvitationID int64     `json:"invitationID"`
+	Messages     []Message `json:"messages,omitempty"`
+	Error        string    `json:"error,omitempty"`
+}
+
+// InvitationBulkSendRequest sends every invitation in the category that has not been sent yet, along
+// with those already sent but not replied to when Resend is set
+type InvitationBulkSendRequest struct {
+	CategoryID int64
+	Resend     bool
+}
+
+type InvitationBulkSendResult struct {
+	Sent    int                    `json:"sent"`
+	Failed  int                    `json:"failed"`
+	Skipped int                    `json:"skipped"`
+	Results []InvitationSendResult `json:"results"`
+}
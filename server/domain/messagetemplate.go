@@ -0,0 +1,54 @@
+package domain
+
+// MessageTemplateKind is what a template is used for
+type MessageTemplateKind string
+
+const (
+	InvitationTemplate MessageTemplateKind = "invitation"
+	ReminderTemplate   MessageTemplateKind = "reminder"
+)
+
+// BaseMessageTemplate is used for messages of its kind sent on its channel. A CategoryID of 0 makes the
+// template apply to every category and an empty Language to every language, so the most specific
+// template for an invitation is picked. Subject is only used by emails.
+type BaseMessageTemplate struct {
+	Name       string              `json:"name"`
+	Kind       MessageTemplateKind `json:"kind"`
+	Channel    MessageChannel      `json:"channel"`
+	CategoryID int64               `json:"categoryID"`
+	Language   string              `json:"language"`
+	Subject    string              `json:"subject"`
+	Body       string              `json:"body"`
+}
+
+type MessageTemplateCreateRequest struct {
+	BaseMessageTemplate
+}
+
+type MessageTemplateUpdateRequest struct {
+	BaseMessageTemplate
+	ID int64 `json:"id"`
+}
+
+type MessageTemplate struct {
+	BaseMessageTemplate
+	ID        int64  `json:"id"`
+	CreatedAt string `json:"createdAt"`
+	UpdatedAt string `json:"updatedAt"`
+}
+
+// MessageTemplateData is what the subject and body of a template can refer to, such as {{.Greeting}}
+type MessageTemplateData struct {
+	Greeting          string
+	MaximumGuestCount int
+	RSVPLink          string
+	CategoryTag       string
+}
+
+// RenderedMessage is a template filled in for an invitation. HTMLBody is only rendered for emails.
+type RenderedMessage struct {
+	TemplateID int64  `json:"templateID"`
+	Subject    string `json:"subject,omitempty"`
+	Body       string `json:"body"`
+	HTMLBody   string `json:"html,omitempty"`
+}
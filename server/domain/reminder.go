@@ -0,0 +1,24 @@
+package domain
+
+// UpcomingReminder is the next reminder for an invitation that has been sent but not replied to.
+// ReminderNumber counts from 1 for the first reminder. DueAt may already have passed for reminders
+// waiting on the next run of the scheduler.
+type UpcomingReminder struct {
+	InvitationID   int64  `json:"invitationID"`
+	Greeting       string `json:"greeting"`
+	ReminderNumber int    `json:"reminderNumber"`
+	DueAt          string `json:"dueAt"`
+}
+
+// ReminderList holds the reminders that are coming up, soonest first, and those already sent. Enabled
+// is false when the scheduler is turned off, in which case upcoming reminders are not sent.
+type ReminderList struct {
+	Enabled  bool               `json:"enabled"`
+	Upcoming []UpcomingReminder `json:"upcoming"`
+	Sent     []Message          `json:"sent"`
+}
+
+type ReminderSendResult struct {
+	Sent   int `json:"sent"`
+	Failed int `json:"failed"`
+}
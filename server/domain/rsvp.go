@@ -26,6 +26,7 @@ type BaseRSVP struct {
 	SpecialDiet       bool   `json:"specialDiet"`
 	Remarks           string `json:"remarks"`
 	MobilePhoneNumber string `json:"mobilePhoneNumber"`
+	Email             string `json:"email"`
 }
 
 type RSVPCreateRequest struct {
@@ -38,6 +39,7 @@ type RSVPUpdateRequest struct {
 	BaseRSVP
 	ID                  int64  `json:"id"`
 	InvitationPrivateID string `json:"invitationPrivateID"`
+	Version             int64  `json:"version"`
 }
 
 type RSVP struct {
@@ -46,4 +48,7 @@ type RSVP struct {
 	InvitationPrivateID string `json:"invitationPrivateID,omitempty"`
 	Completed           bool   `json:"completed"`
 	UpdatedAt           string `json:"updatedAt"`
+	Version             int64  `json:"version,omitempty"`
+	CheckedInAt         string `json:"checkedInAt,omitempty"`
+	DeletedAt           string `json:"deletedAt,omitempty"`
 }
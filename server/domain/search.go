@@ -0,0 +1,66 @@
+package domain
+
+import (
+	"strings"
+	"unicode"
+)
+
+type SearchResultType string
+
+const (
+	InvitationSearchResult SearchResultType = "invitation"
+	RSVPSearchResult       SearchResultType = "rsvp"
+)
+
+const (
+	DefaultSearchLimit = 20
+	MaximumSearchLimit = 50
+
+	// MinimumPhoneFragmentLength keeps short numbers in a query such as "table 2" from matching
+	// every phone number containing that digit.
+	MinimumPhoneFragmentLength = 3
+)
+
+type SearchRequest struct {
+	Query string
+	Limit int
+}
+
+// PhoneFragment returns the digits of the query so phone numbers match regardless of spacing or
+// punctuation, or an empty string when there are too few digits to be a useful fragment.
+func (s SearchRequest) PhoneFragment() string {
+	digits := strings.Map(func(r rune) rune {
+		if unicode.IsDigit(r) {
+			return r
+		}
+		return -1
+	}, s.Query)
+
+	if len(digits) < MinimumPhoneFragmentLength {
+		return ""
+	}
+
+	return digits
+}
+
+// SearchResult holds either an invitation or an rsvp depending on Type. Results with a higher rank
+// are better matches.
+type SearchResult struct {
+	Type       SearchResultType `json:"type"`
+	Rank       float64          `json:"rank"`
+	Invitation *Invitation      `json:"invitation,omitempty"`
+	RSVP       *RSVP            `json:"rsvp,omitempty"`
+}
+
+func (s SearchResult) ID() int64 {
+	if s.Type == InvitationSearchResult {
+		return s.Invitation.ID
+	}
+
+	return s.RSVP.ID
+}
+
+type SearchResults struct {
+	Query   string         `json:"query"`
+	Results []SearchResult `json:"results"`
+}
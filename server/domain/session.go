@@ -6,7 +6,52 @@ type SessionCreateRequest struct {
 	ReCAPTCHAToken string `json:"reCAPTCHA"`
 }
 
-type SessionCreateResponse struct {
+// Session is one signed in browser or device. It expires once its refresh token goes unused for the
+// refresh duration. Current marks the session making the request.
+type Session struct {
+	ID        string `json:"id"`
 	Username  string `json:"username"`
-	AuthToken string `json:"authToken"`
+	CreatedAt string `json:"createdAt"`
+	ExpiresAt string `json:"expiresAt"`
+	Current   bool   `json:"current"`
+}
+
+type SessionRefreshRequest struct {
+	RefreshToken string `json:"refreshToken"`
+}
+
+// SessionTokens are handed out on login and on every refresh. A refresh token can only be used once as
+// each refresh returns a new one.
+type SessionTokens struct {
+	AuthToken          string `json:"authToken"`
+	AuthTokenExpiresAt string `json:"authTokenExpiresAt"`
+	RefreshToken       string `json:"refreshToken"`
+}
+
+type SessionCreateResponse struct {
+	SessionTokens
+	Username string `json:"username"`
+	Role     Role   `json:"role"`
+}
+
+// PreAuthTokenResponse is returned by logins of users with two-factor authentication instead of a
+// session. The pre-auth token is exchanged along with a code for the session.
+type PreAuthTokenResponse struct {
+	TwoFactorRequired     bool   `json:"twoFactorRequired"`
+	PreAuthToken          string `json:"preAuthToken"`
+	PreAuthTokenExpiresAt string `json:"preAuthTokenExpiresAt"`
+}
+
+// SessionTwoFactorRequest takes either a code from an authenticator app or one of the recovery codes
+type SessionTwoFactorRequest struct {
+	PreAuthToken string `json:"preAuthToken"`
+	Code         string `json:"code"`
+}
+
+// LoginLockout is recorded in the audit log when too many failed logins lock out a username or a
+// client IP. Username is blank for client IP lockouts.
+type LoginLockout struct {
+	Username    string `json:"username,omitempty"`
+	ClientIP    string `json:"clientIP"`
+	LockedUntil string `json:"lockedUntil"`
 }
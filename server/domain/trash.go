@@ -0,0 +1,15 @@
+package domain
+
+// Trash holds the records that were deleted but not purged yet, most recently deleted first.
+type Trash struct {
+	Categories  []Category   `json:"categories"`
+	Invitations []Invitation `json:"invitations"`
+	RSVPs       []RSVP       `json:"rsvps"`
+}
+
+// PurgeResult counts the records permanently removed from the trash.
+type PurgeResult struct {
+	Categories  int `json:"categories"`
+	Invitations int `json:"invitations"`
+	RSVPs       int `json:"rsvps"`
+}
@@ -0,0 +1,84 @@
+package domain
+
+// Role decides which control panel endpoints a user may call
+type Role string
+
+const (
+	RoleOwner     Role = "owner"
+	RoleEditor    Role = "editor"
+	RoleViewer    Role = "viewer"
+	RoleDoorStaff Role = "door_staff"
+)
+
+func IsValidRole(role Role) bool {
+	for _, validRole := range []Role{RoleOwner, RoleEditor, RoleViewer, RoleDoorStaff} {
+		if role == validRole {
+			return true
+		}
+	}
+
+	return false
+}
+
+type UserCreateRequest struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+	Role     Role   `json:"role"`
+}
+
+type UserRoleUpdateRequest struct {
+	ID   int64 `json:"id"`
+	Role Role  `json:"role"`
+}
+
+type UserPasswordResetRequest struct {
+	ID       int64  `json:"id"`
+	Password string `json:"password"`
+}
+
+// PasswordChangeRequest is made by signed in users for their own account so it needs the current
+// password rather than an id.
+type PasswordChangeRequest struct {
+	CurrentPassword string `json:"currentPassword"`
+	NewPassword     string `json:"newPassword"`
+}
+
+// TOTPEnrollment is returned when a user starts setting up two-factor authentication. The provisioning
+// URI is shown as a QR code for authenticator apps to scan, and the secret is for typing in by hand.
+type TOTPEnrollment struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioningURI"`
+}
+
+// TOTPConfirmRequest finishes enrollment with a code from the authenticator app to show it was set
+// up correctly
+type TOTPConfirmRequest struct {
+	Code string `json:"code"`
+}
+
+type TOTPDisableRequest struct {
+	Password string `json:"password"`
+}
+
+// RecoveryCodes are shown once when two-factor authentication is enabled. Each one logs in once in
+// place of a code from the authenticator app.
+type RecoveryCodes struct {
+	Codes []string `json:"recoveryCodes"`
+}
+
+// User is an admin allowed into the control panel. Usernames are kept in lower case and the password
+// hash never leaves the server, nor do the TOTP secret and recovery code hashes. TOTPLastStep is the
+// time step of the last accepted code so that a code cannot be used twice.
+type User struct {
+	ID                 int64    `json:"id"`
+	Username           string   `json:"username"`
+	PasswordHash       string   `json:"-"`
+	Role               Role     `json:"role"`
+	Disabled           bool     `json:"disabled"`
+	TOTPSecret         string   `json:"-"`
+	TOTPEnabled        bool     `json:"totpEnabled"`
+	TOTPLastStep       int64    `json:"-"`
+	RecoveryCodeHashes []string `json:"-"`
+	CreatedAt          string   `json:"createdAt"`
+	UpdatedAt          string   `json:"updatedAt"`
+}
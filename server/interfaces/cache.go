@@ -5,5 +5,6 @@ type CacheServiceProvider interface {
 	SetWithExpiry(key string, value string, expiryInSeconds int) (err error)
 	Delete(key string) (err error)
 	Exists(key string) (exists bool, err error)
+	Keys(prefix string) (keys []string, err error)
 	Flush() (err error)
 }
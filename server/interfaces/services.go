@@ -7,8 +7,15 @@ import (
 )
 
 type SessionServiceProvider interface {
-	CreateWithExpiry(username string) (authToken string, err error)
+	CreateWithExpiry(username string, role domain.Role) (tokens *domain.SessionTokens, err error)
+	Refresh(refreshToken string) (tokens *domain.SessionTokens, err error)
+	Renew(authToken string) (renewedAuthToken string, err error)
 	IsSessionValid(authToken string) (valid bool, err error)
+	Username(authToken string) (username string, err error)
+	Role(authToken string) (role domain.Role, err error)
+	ListSessions(authToken string) (sessions []domain.Session, err error)
+	Revoke(authToken, sessionID string) (err error)
+	RevokeAll(username string) (err error)
 	Destroy(authToken string) (err error)
 }
 
@@ -16,33 +23,120 @@ type JWTServiceProvider interface {
 	GenerateAuthToken(additionalClaims map[string]string, duration time.Duration) (authToken string, err error)
 	ParseToken(token string) (claims map[string]interface{}, err error)
 	IsAuthTokenValid(authToken string) (valid bool)
+	PublicKeys() (keySet *domain.JSONWebKeySet, err error)
 }
 
 type SecurityServiceProvider interface {
 	ValidateCredentials(username, password string) (valid bool)
-	VerifyReCAPTCHA(token string) (valid bool)
+	GeneratePreAuthToken(username string) (*domain.PreAuthTokenResponse, error)
+	ParsePreAuthToken(preAuthToken string) (username string, err error)
+	ValidateTwoFactorCode(username, code string) (valid bool, err error)
+}
+
+type CaptchaVerifier interface {
+	Verify(token, remoteIP string) (valid bool)
+}
+
+type LoginThrottleServiceProvider interface {
+	ReserveAttempt(username, clientIP string) (wait time.Duration, err error)
+	RecordFailure(username, clientIP string) (err error)
+	RecordSuccess(username, clientIP string) (err error)
+	ReleaseAttempt(username, clientIP string) (err error)
 }
 
 type CategoryServiceProvider interface {
 	CreateCategory(*domain.CategoryCreateRequest) (*domain.Category, error)
-	ListCategories() ([]domain.Category, error)
+	ListCategories(*domain.CategoryListRequest) (*domain.CategoryList, error)
 	UpdateCategory(*domain.CategoryUpdateRequest) (*domain.Category, error)
 	DeleteCategoryByID(categoryID int64) error
+	RestoreCategoryByID(categoryID int64) (*domain.Category, error)
 }
 
 type InvitationServiceProvider interface {
 	CreateInvitation(*domain.InvitationCreateRequest) (*domain.Invitation, error)
-	ListInvitations([]domain.RSVP) ([]domain.Invitation, error)
+	ListInvitations(*domain.InvitationListRequest) (*domain.InvitationList, error)
 	UpdateInvitation(*domain.InvitationUpdateRequest) (*domain.Invitation, error)
 	DeleteInvitationByID(invitationID int64) error
+	RestoreInvitationByID(invitationID int64) (*domain.Invitation, error)
 	RetrieveInvitationByPrivateID(privateID string) (*domain.Invitation, error)
-	// SendInvitation()
+	UpdateInvitationLink(*domain.InvitationLinkUpdateRequest) (*domain.Invitation, error)
+	RevokeInvitationLinkByID(invitationID int64) (*domain.Invitation, error)
+	RotateInvitationLinkByID(invitationID int64) (*domain.Invitation, error)
+	AuthorizeGuest(privateID, mobilePhoneNumber string) (*domain.Invitation, error)
+	SendInvitationByID(invitationID int64) (*domain.InvitationSendResult, error)
+	SendInvitations(*domain.InvitationBulkSendRequest) (*domain.InvitationBulkSendResult, error)
+	ListInvitationMessages(invitationID int64) ([]domain.Message, error)
+	UpdateMessageStatus(*domain.MessageStatusUpdate) (*domain.Message, error)
+	ListReminders() (*domain.ReminderList, error)
+	SendDueReminders(now time.Time) (*domain.ReminderSendResult, error)
+}
+
+// MessagingProvider sends text messages to guests and returns the id the provider gave the message
+type MessagingProvider interface {
+	Name() string
+	SendSMS(to, body string) (providerMessageID string, err error)
+}
+
+// EmailSender sends emails to guests and returns the id the message was sent with
+type EmailSender interface {
+	Name() string
+	SendEmail(*domain.Email) (providerMessageID string, err error)
+}
+
+type MigrationServiceProvider interface {
+	Up() ([]domain.MigrationStatus, error)
+	Down() (*domain.MigrationStatus, error)
+	Status() ([]domain.MigrationStatus, error)
 }
 
 type RSVPServiceProvider interface {
 	CreateRSVP(*domain.RSVPCreateRequest) (*domain.RSVP, error)
-	ListRSVPs() ([]domain.RSVP, error)
+	ListRSVPs(*domain.RSVPListRequest) (*domain.RSVPList, error)
 	UpdateRSVP(*domain.RSVPUpdateRequest) (*domain.RSVP, error)
 	DeleteRSVPByID(rsvpID int64) error
+	RestoreRSVPByID(rsvpID int64) (*domain.RSVP, error)
+	CheckInRSVPByID(rsvpID int64) (*domain.RSVP, error)
 	RetrievePrivateRSVP(invitationPrivateID string) (*domain.RSVP, error)
 }
+
+type SearchServiceProvider interface {
+	Search(*domain.SearchRequest) (*domain.SearchResults, error)
+}
+
+type TrashServiceProvider interface {
+	ListTrash() (*domain.Trash, error)
+	Purge(deletedBefore time.Time) (*domain.PurgeResult, error)
+}
+
+type AuditServiceProvider interface {
+	ListAuditEntries(*domain.AuditListRequest) (*domain.AuditList, error)
+}
+
+type UserServiceProvider interface {
+	CreateUser(*domain.UserCreateRequest) (*domain.User, error)
+	ListUsers() ([]domain.User, error)
+	RetrieveUserByUsername(username string) (*domain.User, error)
+	DisableUserByID(userID int64) (*domain.User, error)
+	UpdateUserRole(*domain.UserRoleUpdateRequest) (*domain.User, error)
+	ResetPassword(*domain.UserPasswordResetRequest) (*domain.User, error)
+	ChangePassword(username string, req *domain.PasswordChangeRequest) error
+	BeginTOTPEnrollment(username string) (*domain.TOTPEnrollment, error)
+	ConfirmTOTPEnrollment(username string, req *domain.TOTPConfirmRequest) (*domain.RecoveryCodes, error)
+	DisableTOTP(username string, req *domain.TOTPDisableRequest) error
+	ResetTOTPByID(userID int64) (*domain.User, error)
+}
+
+type MessageTemplateServiceProvider interface {
+	CreateMessageTemplate(*domain.MessageTemplateCreateRequest) (*domain.MessageTemplate, error)
+	ListMessageTemplates() ([]domain.MessageTemplate, error)
+	UpdateMessageTemplate(*domain.MessageTemplateUpdateRequest) (*domain.MessageTemplate, error)
+	DeleteMessageTemplateByID(templateID int64) error
+	PreviewMessageTemplate(templateID, invitationID int64) (*domain.RenderedMessage, error)
+}
+
+type APIKeyServiceProvider interface {
+	CreateAPIKey(*domain.APIKeyCreateRequest) (*domain.NewAPIKey, error)
+	ListAPIKeys() ([]domain.APIKey, error)
+	RevokeAPIKeyByID(apiKeyID int64) (*domain.APIKey, error)
+	Authenticate(key string) (apiKey *domain.APIKey, err error)
+}
@@ -1,31 +1,127 @@
 package interfaces
 
 import (
+	"time"
+
 	"github.com/rawfish-dev/rsvp-starter/server/domain"
 )
 
+// Storage is implemented by every storage backend. List methods expect a request that the services
+// have already validated and defaulted, and return the requested page along with the total number
+// of records matching the filters.
+//
+// Delete methods move records to the trash rather than removing them. Every other method ignores
+// records in the trash except the Deleted ones, Restore which moves them back and Purge which
+// removes those deleted before the given time for good. A category in the trash is only purged once
+// no invitation refers to it, including invitations that are still in the trash themselves.
+type Storage interface {
+	CategoryStorage
+	InvitationStorage
+	RSVPStorage
+	SearchStorage
+	AuditStorage
+	UserStorage
+	APIKeyStorage
+	MessageStorage
+	MessageTemplateStorage
+
+	// WithTx runs fn against a storage bound to a single transaction. The transaction is committed
+	// when fn returns nil and rolled back otherwise, in which case the error from fn is returned as is.
+	// Calling WithTx on the storage passed to fn joins the transaction that is already open.
+	WithTx(fn func(tx Storage) error) error
+}
+
 type CategoryStorage interface {
 	InsertCategory(*domain.CategoryCreateRequest) (*domain.Category, error)
 	FindCategoryByID(categoryID int64) (*domain.Category, error)
-	ListCategories() ([]domain.Category, error)
+	ListCategories(*domain.CategoryListRequest) (categories []domain.Category, total int, err error)
 	UpdateCategory(*domain.Category) (*domain.Category, error)
 	DeleteCategory(*domain.Category) error
+	FindDeletedCategoryByID(categoryID int64) (*domain.Category, error)
+	ListDeletedCategories() ([]domain.Category, error)
+	RestoreCategory(*domain.Category) (*domain.Category, error)
+	PurgeCategories(deletedBefore time.Time) (purged int, err error)
 }
 
 type InvitationStorage interface {
 	InsertInvitation(*domain.InvitationCreateRequest) (*domain.Invitation, error)
 	FindInvitationByID(invitationID int64) (*domain.Invitation, error)
 	FindInvitationByPrivateID(privateID string) (*domain.Invitation, error)
-	ListInvitations() ([]domain.Invitation, error)
+	ListInvitations(*domain.InvitationListRequest) (invitations []domain.Invitation, total int, err error)
 	UpdateInvitation(*domain.Invitation) (*domain.Invitation, error)
 	DeleteInvitation(*domain.Invitation) error
+	FindDeletedInvitationByID(invitationID int64) (*domain.Invitation, error)
+	ListDeletedInvitations() ([]domain.Invitation, error)
+	RestoreInvitation(*domain.Invitation) (*domain.Invitation, error)
+	PurgeInvitations(deletedBefore time.Time) (purged int, err error)
+	InsertRetiredInvitationLink(invitationID int64, privateID string) error
+	IsInvitationLinkRetired(privateID string) (retired bool, err error)
 }
 
 type RSVPStorage interface {
 	InsertRSVP(*domain.RSVPCreateRequest) (*domain.RSVP, error)
 	FindRSVPByID(rsvpID int64) (*domain.RSVP, error)
 	FindRSVPByInvitationPrivateID(invitationPrivateID string) (*domain.RSVP, error)
-	ListRSVPs() ([]domain.RSVP, error)
+	ListRSVPs(*domain.RSVPListRequest) (rsvps []domain.RSVP, total int, err error)
 	UpdateRSVP(*domain.RSVP) (*domain.RSVP, error)
 	DeleteRSVP(*domain.RSVP) error
+	FindDeletedRSVPByID(rsvpID int64) (*domain.RSVP, error)
+	ListDeletedRSVPs() ([]domain.RSVP, error)
+	RestoreRSVP(*domain.RSVP) (*domain.RSVP, error)
+	CheckInRSVP(*domain.RSVP) (*domain.RSVP, error)
+	PurgeRSVPs(deletedBefore time.Time) (purged int, err error)
+}
+
+// SearchStorage returns at most req.Limit invitations and rsvps matching the query, ordered from
+// the best match.
+type SearchStorage interface {
+	Search(*domain.SearchRequest) ([]domain.SearchResult, error)
+}
+
+// AuditStorage keeps an append only log of changes. Entries are listed from the most recent.
+type AuditStorage interface {
+	InsertAuditEntry(*domain.AuditEntryCreateRequest) (*domain.AuditEntry, error)
+	ListAuditEntries(*domain.AuditListRequest) (entries []domain.AuditEntry, total int, err error)
+}
+
+// UserStorage keeps the admin accounts. Users are never deleted, only disabled, so their audit
+// entries keep pointing at someone.
+type UserStorage interface {
+	InsertUser(*domain.User) (*domain.User, error)
+	FindUserByID(userID int64) (*domain.User, error)
+	FindUserByUsername(username string) (*domain.User, error)
+	ListUsers() ([]domain.User, error)
+	UpdateUser(*domain.User) (*domain.User, error)
+}
+
+// APIKeyStorage keeps the API keys. Keys are revoked rather than deleted so their audit entries keep
+// pointing at something, and revoked keys are listed along with the rest.
+type APIKeyStorage interface {
+	InsertAPIKey(*domain.APIKey) (*domain.APIKey, error)
+	FindAPIKeyByID(apiKeyID int64) (*domain.APIKey, error)
+	FindAPIKeyByHash(keyHash string) (*domain.APIKey, error)
+	ListAPIKeys() ([]domain.APIKey, error)
+	RevokeAPIKey(apiKeyID int64) (*domain.APIKey, error)
+	UpdateAPIKeyLastUsed(apiKeyID int64, lastUsedAt time.Time) error
+}
+
+// MessageStorage keeps every message sent to guests. Messages are listed from the oldest. Finding a
+// message by its provider message id returns the latest one should the provider reuse ids.
+type MessageStorage interface {
+	InsertMessage(*domain.Message) (*domain.Message, error)
+	FindMessageByProviderMessageID(provider, providerMessageID string) (*domain.Message, error)
+	ListMessagesByInvitationID(invitationID int64) ([]domain.Message, error)
+	ListMessagesByInvitationIDs(invitationIDs []int64) ([]domain.Message, error)
+	ListMessagesByKind(kind domain.MessageKind) ([]domain.Message, error)
+	UpdateMessage(*domain.Message) (*domain.Message, error)
+}
+
+// MessageTemplateStorage keeps the wording of messages. Templates are removed for good when deleted as
+// nothing refers to them, and are listed from the oldest.
+type MessageTemplateStorage interface {
+	InsertMessageTemplate(*domain.MessageTemplateCreateRequest) (*domain.MessageTemplate, error)
+	FindMessageTemplateByID(templateID int64) (*domain.MessageTemplate, error)
+	ListMessageTemplates() ([]domain.MessageTemplate, error)
+	UpdateMessageTemplate(*domain.MessageTemplate) (*domain.MessageTemplate, error)
+	DeleteMessageTemplate(*domain.MessageTemplate) error
 }
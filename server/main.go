@@ -1,14 +1,51 @@
 package main
 
 import (
+	"flag"
+	"os"
+
 	"github.com/rawfish-dev/rsvp-starter/server/api"
 	"github.com/rawfish-dev/rsvp-starter/server/config"
 )
 
 func main() {
+	demo := flag.Bool("demo", false, "run against in-memory storage seeded with sample data")
+	flag.Parse()
+
+	// Demo mode needs no database so it always overrides STORAGE_DRIVER
+	if *demo {
+		os.Setenv("STORAGE_DRIVER", config.MemoryDriver)
+	}
+
 	loadedConfig := config.LoadConfig()
 
+	// Schema changes are applied with `server migrate up|down|status` instead of starting the API
+	args := flag.Args()
+	if len(args) > 0 && args[0] == "migrate" {
+		runMigrations(loadedConfig, args[1:])
+		return
+	}
+
+	// Admin users are managed with `server users list|create|disable|reset-password|reset-totp`
+	if len(args) > 0 && args[0] == "users" {
+		if loadedConfig.Storage.Driver == config.SQLiteDriver {
+			autoMigrate(loadedConfig)
+		}
+		runUsers(loadedConfig, args[1:])
+		return
+	}
+
+	switch {
+	case *demo:
+		seedDemoData(loadedConfig)
+	case loadedConfig.Storage.Driver == config.SQLiteDriver:
+		autoMigrate(loadedConfig)
+	}
+
 	reactReduxBasicsAPI := api.NewAPI(loadedConfig)
 
+	go runPurgeJob(reactReduxBasicsAPI, loadedConfig.Trash)
+	go runReminderJob(reactReduxBasicsAPI, loadedConfig.Reminder)
+
 	reactReduxBasicsAPI.Run()
 }
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/rawfish-dev/rsvp-starter/server/config"
+	"github.com/rawfish-dev/rsvp-starter/server/interfaces"
+	"github.com/rawfish-dev/rsvp-starter/server/services/migration"
+	"github.com/rawfish-dev/rsvp-starter/server/services/postgres"
+	"github.com/rawfish-dev/rsvp-starter/server/services/sqlite"
+
+	"github.com/Sirupsen/logrus"
+	"golang.org/x/net/context"
+)
+
+const migrateUsage = "usage: server migrate up|down|status"
+
+func runMigrations(loadedConfig config.Config, args []string) {
+	ctxlogger := logrus.New()
+	ctx := context.Background()
+	ctx = context.WithValue(ctx, "logger", ctxlogger)
+
+	if len(args) != 1 {
+		ctxlogger.Fatal(migrateUsage)
+	}
+
+	migrationService, db := newMigrationService(ctx, loadedConfig)
+	defer db.Close()
+
+	switch args[0] {
+	case "up":
+		applied, err := migrationService.Up()
+		if err != nil {
+			ctxlogger.Fatalf("migrate - unable to apply migrations due to %v", err)
+		}
+		if len(applied) == 0 {
+			fmt.Println("no pending migrations")
+		}
+		for idx := range applied {
+			fmt.Printf("applied %v %v\n", applied[idx].Version, applied[idx].Name)
+		}
+
+	case "down":
+		rolledBack, err := migrationService.Down()
+		if err != nil {
+			ctxlogger.Fatalf("migrate - unable to roll back migration due to %v", err)
+		}
+		if rolledBack == nil {
+			fmt.Println("no applied migrations to roll back")
+			break
+		}
+		fmt.Printf("rolled back %v %v\n", rolledBack.Version, rolledBack.Name)
+
+	case "status":
+		statuses, err := migrationService.Status()
+		if err != nil {
+			ctxlogger.Fatalf("migrate - unable to retrieve migration status due to %v", err)
+		}
+
+		writer := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(writer, "APPLIED AT\tVERSION\tNAME")
+		for idx := range statuses {
+			appliedAt := "pending"
+			if statuses[idx].AppliedAt != nil {
+				appliedAt = statuses[idx].AppliedAt.Format(time.RFC3339)
+			}
+			fmt.Fprintf(writer, "%v\t%v\t%v\n", appliedAt, statuses[idx].Version, statuses[idx].Name)
+		}
+		writer.Flush()
+
+	default:
+		ctxlogger.Fatal(migrateUsage)
+	}
+}
+
+// autoMigrate brings local SQLite databases up to date on start since there is no separate deploy step for them.
+func autoMigrate(loadedConfig config.Config) {
+	ctxlogger := logrus.New()
+	ctx := context.Background()
+	ctx = context.WithValue(ctx, "logger", ctxlogger)
+
+	// The storage backend is left open since it is the same one the API or users command goes on to use
+	migrationService, _ := newMigrationService(ctx, loadedConfig)
+
+	_, err := migrationService.Up()
+	if err != nil {
+		ctxlogger.Fatalf("migrate - unable to apply migrations due to %v", err)
+	}
+}
+
+// newMigrationService also returns the storage backend it opened so one-shot commands can close it once done.
+func newMigrationService(ctx context.Context, loadedConfig config.Config) (interfaces.MigrationServiceProvider, io.Closer) {
+	ctxLogger := ctx.Value("logger").(interfaces.Logger)
+
+	switch loadedConfig.Storage.Driver {
+	case config.MemoryDriver:
+		ctxLogger.Fatal("migrate - the memory storage driver has no schema to migrate")
+	case config.SQLiteDriver:
+		sqliteService := sqlite.NewService(ctx, loadedConfig.SQLite)
+		return migration.NewService(ctx, sqliteService.DB(), sqlite.Migrations), sqliteService
+	}
+
+	postgresService := postgres.NewService(ctx, loadedConfig.Postgres)
+	return migration.NewService(ctx, postgresService.DB(), postgres.Migrations), postgresService
+}
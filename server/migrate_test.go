@@ -0,0 +1,48 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/rawfish-dev/rsvp-starter/server/api"
+	"github.com/rawfish-dev/rsvp-starter/server/config"
+	"github.com/rawfish-dev/rsvp-starter/server/domain"
+
+	"github.com/Sirupsen/logrus"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"golang.org/x/net/context"
+)
+
+var _ = Describe("Migrate", func() {
+
+	Context("automatic migrations", func() {
+
+		It("should leave the sqlite database open for the API and users command", func() {
+			dir, err := ioutil.TempDir("", "rsvp-starter")
+			Expect(err).ToNot(HaveOccurred())
+			defer os.RemoveAll(dir)
+
+			loadedConfig := config.Config{
+				Storage: config.StorageConfig{Driver: config.SQLiteDriver},
+				SQLite:  config.SQLiteConfig{Path: filepath.Join(dir, "rsvp.db")},
+			}
+
+			autoMigrate(loadedConfig)
+
+			ctxlogger := logrus.New()
+			ctx := context.Background()
+			ctx = context.WithValue(ctx, "logger", ctxlogger)
+
+			userStorage := api.NewStorage(ctx, loadedConfig)
+
+			_, err = userStorage.InsertUser(&domain.User{Username: "admin", PasswordHash: "hash", Role: domain.RoleOwner})
+			Expect(err).ToNot(HaveOccurred())
+
+			users, err := userStorage.ListUsers()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(users).To(HaveLen(1))
+		})
+	})
+})
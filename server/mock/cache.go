@@ -70,6 +70,17 @@ func (_mr *_MockCacheServiceProviderRecorder) Exists(arg0 interface{}) *gomock.C
 	return _mr.mock.ctrl.RecordCall(_mr.mock, "Exists", arg0)
 }
 
+func (_m *MockCacheServiceProvider) Keys(prefix string) ([]string, error) {
+	ret := _m.ctrl.Call(_m, "Keys", prefix)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockCacheServiceProviderRecorder) Keys(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "Keys", arg0)
+}
+
 func (_m *MockCacheServiceProvider) Flush() error {
 	ret := _m.ctrl.Call(_m, "Flush")
 	ret0, _ := ret[0].(error)
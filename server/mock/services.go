@@ -30,15 +30,37 @@ func (_m *MockSessionServiceProvider) EXPECT() *_MockSessionServiceProviderRecor
 	return _m.recorder
 }
 
-func (_m *MockSessionServiceProvider) CreateWithExpiry(username string) (string, error) {
-	ret := _m.ctrl.Call(_m, "CreateWithExpiry", username)
+func (_m *MockSessionServiceProvider) CreateWithExpiry(username string, role domain.Role) (*domain.SessionTokens, error) {
+	ret := _m.ctrl.Call(_m, "CreateWithExpiry", username, role)
+	ret0, _ := ret[0].(*domain.SessionTokens)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockSessionServiceProviderRecorder) CreateWithExpiry(arg0, arg1 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "CreateWithExpiry", arg0, arg1)
+}
+
+func (_m *MockSessionServiceProvider) Refresh(refreshToken string) (*domain.SessionTokens, error) {
+	ret := _m.ctrl.Call(_m, "Refresh", refreshToken)
+	ret0, _ := ret[0].(*domain.SessionTokens)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockSessionServiceProviderRecorder) Refresh(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "Refresh", arg0)
+}
+
+func (_m *MockSessionServiceProvider) Renew(authToken string) (string, error) {
+	ret := _m.ctrl.Call(_m, "Renew", authToken)
 	ret0, _ := ret[0].(string)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-func (_mr *_MockSessionServiceProviderRecorder) CreateWithExpiry(arg0 interface{}) *gomock.Call {
-	return _mr.mock.ctrl.RecordCall(_mr.mock, "CreateWithExpiry", arg0)
+func (_mr *_MockSessionServiceProviderRecorder) Renew(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "Renew", arg0)
 }
 
 func (_m *MockSessionServiceProvider) IsSessionValid(authToken string) (bool, error) {
@@ -52,6 +74,59 @@ func (_mr *_MockSessionServiceProviderRecorder) IsSessionValid(arg0 interface{})
 	return _mr.mock.ctrl.RecordCall(_mr.mock, "IsSessionValid", arg0)
 }
 
+func (_m *MockSessionServiceProvider) Username(authToken string) (string, error) {
+	ret := _m.ctrl.Call(_m, "Username", authToken)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockSessionServiceProviderRecorder) Username(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "Username", arg0)
+}
+
+func (_m *MockSessionServiceProvider) Role(authToken string) (domain.Role, error) {
+	ret := _m.ctrl.Call(_m, "Role", authToken)
+	ret0, _ := ret[0].(domain.Role)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockSessionServiceProviderRecorder) Role(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "Role", arg0)
+}
+
+func (_m *MockSessionServiceProvider) ListSessions(authToken string) ([]domain.Session, error) {
+	ret := _m.ctrl.Call(_m, "ListSessions", authToken)
+	ret0, _ := ret[0].([]domain.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockSessionServiceProviderRecorder) ListSessions(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "ListSessions", arg0)
+}
+
+func (_m *MockSessionServiceProvider) Revoke(authToken string, sessionID string) error {
+	ret := _m.ctrl.Call(_m, "Revoke", authToken, sessionID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+func (_mr *_MockSessionServiceProviderRecorder) Revoke(arg0, arg1 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "Revoke", arg0, arg1)
+}
+
+func (_m *MockSessionServiceProvider) RevokeAll(username string) error {
+	ret := _m.ctrl.Call(_m, "RevokeAll", username)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+func (_mr *_MockSessionServiceProviderRecorder) RevokeAll(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "RevokeAll", arg0)
+}
+
 func (_m *MockSessionServiceProvider) Destroy(authToken string) error {
 	ret := _m.ctrl.Call(_m, "Destroy", authToken)
 	ret0, _ := ret[0].(error)
@@ -115,6 +190,17 @@ func (_mr *_MockJWTServiceProviderRecorder) IsAuthTokenValid(arg0 interface{}) *
 	return _mr.mock.ctrl.RecordCall(_mr.mock, "IsAuthTokenValid", arg0)
 }
 
+func (_m *MockJWTServiceProvider) PublicKeys() (*domain.JSONWebKeySet, error) {
+	ret := _m.ctrl.Call(_m, "PublicKeys")
+	ret0, _ := ret[0].(*domain.JSONWebKeySet)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockJWTServiceProviderRecorder) PublicKeys() *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "PublicKeys")
+}
+
 // Mock of SecurityServiceProvider interface
 type MockSecurityServiceProvider struct {
 	ctrl     *gomock.Controller
@@ -146,14 +232,130 @@ func (_mr *_MockSecurityServiceProviderRecorder) ValidateCredentials(arg0, arg1
 	return _mr.mock.ctrl.RecordCall(_mr.mock, "ValidateCredentials", arg0, arg1)
 }
 
-func (_m *MockSecurityServiceProvider) VerifyReCAPTCHA(token string) bool {
-	ret := _m.ctrl.Call(_m, "VerifyReCAPTCHA", token)
+func (_m *MockSecurityServiceProvider) GeneratePreAuthToken(username string) (*domain.PreAuthTokenResponse, error) {
+	ret := _m.ctrl.Call(_m, "GeneratePreAuthToken", username)
+	ret0, _ := ret[0].(*domain.PreAuthTokenResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockSecurityServiceProviderRecorder) GeneratePreAuthToken(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "GeneratePreAuthToken", arg0)
+}
+
+func (_m *MockSecurityServiceProvider) ParsePreAuthToken(preAuthToken string) (string, error) {
+	ret := _m.ctrl.Call(_m, "ParsePreAuthToken", preAuthToken)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockSecurityServiceProviderRecorder) ParsePreAuthToken(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "ParsePreAuthToken", arg0)
+}
+
+func (_m *MockSecurityServiceProvider) ValidateTwoFactorCode(username string, code string) (bool, error) {
+	ret := _m.ctrl.Call(_m, "ValidateTwoFactorCode", username, code)
 	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockSecurityServiceProviderRecorder) ValidateTwoFactorCode(arg0, arg1 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "ValidateTwoFactorCode", arg0, arg1)
+}
+
+// Mock of CaptchaVerifier interface
+type MockCaptchaVerifier struct {
+	ctrl     *gomock.Controller
+	recorder *_MockCaptchaVerifierRecorder
+}
+
+// Recorder for MockCaptchaVerifier (not exported)
+type _MockCaptchaVerifierRecorder struct {
+	mock *MockCaptchaVerifier
+}
+
+func NewMockCaptchaVerifier(ctrl *gomock.Controller) *MockCaptchaVerifier {
+	mock := &MockCaptchaVerifier{ctrl: ctrl}
+	mock.recorder = &_MockCaptchaVerifierRecorder{mock}
+	return mock
+}
+
+func (_m *MockCaptchaVerifier) EXPECT() *_MockCaptchaVerifierRecorder {
+	return _m.recorder
+}
+
+func (_m *MockCaptchaVerifier) Verify(token string, remoteIP string) bool {
+	ret := _m.ctrl.Call(_m, "Verify", token, remoteIP)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+func (_mr *_MockCaptchaVerifierRecorder) Verify(arg0, arg1 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "Verify", arg0, arg1)
+}
+
+// Mock of LoginThrottleServiceProvider interface
+type MockLoginThrottleServiceProvider struct {
+	ctrl     *gomock.Controller
+	recorder *_MockLoginThrottleServiceProviderRecorder
+}
+
+// Recorder for MockLoginThrottleServiceProvider (not exported)
+type _MockLoginThrottleServiceProviderRecorder struct {
+	mock *MockLoginThrottleServiceProvider
+}
+
+func NewMockLoginThrottleServiceProvider(ctrl *gomock.Controller) *MockLoginThrottleServiceProvider {
+	mock := &MockLoginThrottleServiceProvider{ctrl: ctrl}
+	mock.recorder = &_MockLoginThrottleServiceProviderRecorder{mock}
+	return mock
+}
+
+func (_m *MockLoginThrottleServiceProvider) EXPECT() *_MockLoginThrottleServiceProviderRecorder {
+	return _m.recorder
+}
+
+func (_m *MockLoginThrottleServiceProvider) ReserveAttempt(username string, clientIP string) (time.Duration, error) {
+	ret := _m.ctrl.Call(_m, "ReserveAttempt", username, clientIP)
+	ret0, _ := ret[0].(time.Duration)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockLoginThrottleServiceProviderRecorder) ReserveAttempt(arg0, arg1 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "ReserveAttempt", arg0, arg1)
+}
+
+func (_m *MockLoginThrottleServiceProvider) RecordFailure(username string, clientIP string) error {
+	ret := _m.ctrl.Call(_m, "RecordFailure", username, clientIP)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+func (_mr *_MockLoginThrottleServiceProviderRecorder) RecordFailure(arg0, arg1 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "RecordFailure", arg0, arg1)
+}
+
+func (_m *MockLoginThrottleServiceProvider) RecordSuccess(username string, clientIP string) error {
+	ret := _m.ctrl.Call(_m, "RecordSuccess", username, clientIP)
+	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-func (_mr *_MockSecurityServiceProviderRecorder) VerifyReCAPTCHA(arg0 interface{}) *gomock.Call {
-	return _mr.mock.ctrl.RecordCall(_mr.mock, "VerifyReCAPTCHA", arg0)
+func (_mr *_MockLoginThrottleServiceProviderRecorder) RecordSuccess(arg0, arg1 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "RecordSuccess", arg0, arg1)
+}
+
+func (_m *MockLoginThrottleServiceProvider) ReleaseAttempt(username string, clientIP string) error {
+	ret := _m.ctrl.Call(_m, "ReleaseAttempt", username, clientIP)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+func (_mr *_MockLoginThrottleServiceProviderRecorder) ReleaseAttempt(arg0, arg1 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "ReleaseAttempt", arg0, arg1)
 }
 
 // Mock of CategoryServiceProvider interface
@@ -188,15 +390,15 @@ func (_mr *_MockCategoryServiceProviderRecorder) CreateCategory(arg0 interface{}
 	return _mr.mock.ctrl.RecordCall(_mr.mock, "CreateCategory", arg0)
 }
 
-func (_m *MockCategoryServiceProvider) ListCategories() ([]domain.Category, error) {
-	ret := _m.ctrl.Call(_m, "ListCategories")
-	ret0, _ := ret[0].([]domain.Category)
+func (_m *MockCategoryServiceProvider) ListCategories(_param0 *domain.CategoryListRequest) (*domain.CategoryList, error) {
+	ret := _m.ctrl.Call(_m, "ListCategories", _param0)
+	ret0, _ := ret[0].(*domain.CategoryList)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-func (_mr *_MockCategoryServiceProviderRecorder) ListCategories() *gomock.Call {
-	return _mr.mock.ctrl.RecordCall(_mr.mock, "ListCategories")
+func (_mr *_MockCategoryServiceProviderRecorder) ListCategories(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "ListCategories", arg0)
 }
 
 func (_m *MockCategoryServiceProvider) UpdateCategory(_param0 *domain.CategoryUpdateRequest) (*domain.Category, error) {
@@ -220,6 +422,17 @@ func (_mr *_MockCategoryServiceProviderRecorder) DeleteCategoryByID(arg0 interfa
 	return _mr.mock.ctrl.RecordCall(_mr.mock, "DeleteCategoryByID", arg0)
 }
 
+func (_m *MockCategoryServiceProvider) RestoreCategoryByID(categoryID int64) (*domain.Category, error) {
+	ret := _m.ctrl.Call(_m, "RestoreCategoryByID", categoryID)
+	ret0, _ := ret[0].(*domain.Category)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockCategoryServiceProviderRecorder) RestoreCategoryByID(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "RestoreCategoryByID", arg0)
+}
+
 // Mock of InvitationServiceProvider interface
 type MockInvitationServiceProvider struct {
 	ctrl     *gomock.Controller
@@ -252,9 +465,9 @@ func (_mr *_MockInvitationServiceProviderRecorder) CreateInvitation(arg0 interfa
 	return _mr.mock.ctrl.RecordCall(_mr.mock, "CreateInvitation", arg0)
 }
 
-func (_m *MockInvitationServiceProvider) ListInvitations(_param0 []domain.RSVP) ([]domain.Invitation, error) {
+func (_m *MockInvitationServiceProvider) ListInvitations(_param0 *domain.InvitationListRequest) (*domain.InvitationList, error) {
 	ret := _m.ctrl.Call(_m, "ListInvitations", _param0)
-	ret0, _ := ret[0].([]domain.Invitation)
+	ret0, _ := ret[0].(*domain.InvitationList)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -284,6 +497,17 @@ func (_mr *_MockInvitationServiceProviderRecorder) DeleteInvitationByID(arg0 int
 	return _mr.mock.ctrl.RecordCall(_mr.mock, "DeleteInvitationByID", arg0)
 }
 
+func (_m *MockInvitationServiceProvider) RestoreInvitationByID(invitationID int64) (*domain.Invitation, error) {
+	ret := _m.ctrl.Call(_m, "RestoreInvitationByID", invitationID)
+	ret0, _ := ret[0].(*domain.Invitation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockInvitationServiceProviderRecorder) RestoreInvitationByID(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "RestoreInvitationByID", arg0)
+}
+
 func (_m *MockInvitationServiceProvider) RetrieveInvitationByPrivateID(privateID string) (*domain.Invitation, error) {
 	ret := _m.ctrl.Call(_m, "RetrieveInvitationByPrivateID", privateID)
 	ret0, _ := ret[0].(*domain.Invitation)
@@ -295,6 +519,254 @@ func (_mr *_MockInvitationServiceProviderRecorder) RetrieveInvitationByPrivateID
 	return _mr.mock.ctrl.RecordCall(_mr.mock, "RetrieveInvitationByPrivateID", arg0)
 }
 
+func (_m *MockInvitationServiceProvider) UpdateInvitationLink(_param0 *domain.InvitationLinkUpdateRequest) (*domain.Invitation, error) {
+	ret := _m.ctrl.Call(_m, "UpdateInvitationLink", _param0)
+	ret0, _ := ret[0].(*domain.Invitation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockInvitationServiceProviderRecorder) UpdateInvitationLink(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "UpdateInvitationLink", arg0)
+}
+
+func (_m *MockInvitationServiceProvider) RevokeInvitationLinkByID(invitationID int64) (*domain.Invitation, error) {
+	ret := _m.ctrl.Call(_m, "RevokeInvitationLinkByID", invitationID)
+	ret0, _ := ret[0].(*domain.Invitation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockInvitationServiceProviderRecorder) RevokeInvitationLinkByID(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "RevokeInvitationLinkByID", arg0)
+}
+
+func (_m *MockInvitationServiceProvider) RotateInvitationLinkByID(invitationID int64) (*domain.Invitation, error) {
+	ret := _m.ctrl.Call(_m, "RotateInvitationLinkByID", invitationID)
+	ret0, _ := ret[0].(*domain.Invitation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockInvitationServiceProviderRecorder) RotateInvitationLinkByID(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "RotateInvitationLinkByID", arg0)
+}
+
+func (_m *MockInvitationServiceProvider) AuthorizeGuest(privateID string, mobilePhoneNumber string) (*domain.Invitation, error) {
+	ret := _m.ctrl.Call(_m, "AuthorizeGuest", privateID, mobilePhoneNumber)
+	ret0, _ := ret[0].(*domain.Invitation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockInvitationServiceProviderRecorder) AuthorizeGuest(arg0, arg1 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "AuthorizeGuest", arg0, arg1)
+}
+
+func (_m *MockInvitationServiceProvider) SendInvitationByID(invitationID int64) (*domain.InvitationSendResult, error) {
+	ret := _m.ctrl.Call(_m, "SendInvitationByID", invitationID)
+	ret0, _ := ret[0].(*domain.InvitationSendResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockInvitationServiceProviderRecorder) SendInvitationByID(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "SendInvitationByID", arg0)
+}
+
+func (_m *MockInvitationServiceProvider) SendInvitations(_param0 *domain.InvitationBulkSendRequest) (*domain.InvitationBulkSendResult, error) {
+	ret := _m.ctrl.Call(_m, "SendInvitations", _param0)
+	ret0, _ := ret[0].(*domain.InvitationBulkSendResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockInvitationServiceProviderRecorder) SendInvitations(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "SendInvitations", arg0)
+}
+
+func (_m *MockInvitationServiceProvider) ListInvitationMessages(invitationID int64) ([]domain.Message, error) {
+	ret := _m.ctrl.Call(_m, "ListInvitationMessages", invitationID)
+	ret0, _ := ret[0].([]domain.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockInvitationServiceProviderRecorder) ListInvitationMessages(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "ListInvitationMessages", arg0)
+}
+
+func (_m *MockInvitationServiceProvider) UpdateMessageStatus(_param0 *domain.MessageStatusUpdate) (*domain.Message, error) {
+	ret := _m.ctrl.Call(_m, "UpdateMessageStatus", _param0)
+	ret0, _ := ret[0].(*domain.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockInvitationServiceProviderRecorder) UpdateMessageStatus(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "UpdateMessageStatus", arg0)
+}
+
+func (_m *MockInvitationServiceProvider) ListReminders() (*domain.ReminderList, error) {
+	ret := _m.ctrl.Call(_m, "ListReminders")
+	ret0, _ := ret[0].(*domain.ReminderList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockInvitationServiceProviderRecorder) ListReminders() *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "ListReminders")
+}
+
+func (_m *MockInvitationServiceProvider) SendDueReminders(now time.Time) (*domain.ReminderSendResult, error) {
+	ret := _m.ctrl.Call(_m, "SendDueReminders", now)
+	ret0, _ := ret[0].(*domain.ReminderSendResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockInvitationServiceProviderRecorder) SendDueReminders(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "SendDueReminders", arg0)
+}
+
+// Mock of MessagingProvider interface
+type MockMessagingProvider struct {
+	ctrl     *gomock.Controller
+	recorder *_MockMessagingProviderRecorder
+}
+
+// Recorder for MockMessagingProvider (not exported)
+type _MockMessagingProviderRecorder struct {
+	mock *MockMessagingProvider
+}
+
+func NewMockMessagingProvider(ctrl *gomock.Controller) *MockMessagingProvider {
+	mock := &MockMessagingProvider{ctrl: ctrl}
+	mock.recorder = &_MockMessagingProviderRecorder{mock}
+	return mock
+}
+
+func (_m *MockMessagingProvider) EXPECT() *_MockMessagingProviderRecorder {
+	return _m.recorder
+}
+
+func (_m *MockMessagingProvider) Name() string {
+	ret := _m.ctrl.Call(_m, "Name")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+func (_mr *_MockMessagingProviderRecorder) Name() *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "Name")
+}
+
+func (_m *MockMessagingProvider) SendSMS(to string, body string) (string, error) {
+	ret := _m.ctrl.Call(_m, "SendSMS", to, body)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockMessagingProviderRecorder) SendSMS(arg0, arg1 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "SendSMS", arg0, arg1)
+}
+
+// Mock of EmailSender interface
+type MockEmailSender struct {
+	ctrl     *gomock.Controller
+	recorder *_MockEmailSenderRecorder
+}
+
+// Recorder for MockEmailSender (not exported)
+type _MockEmailSenderRecorder struct {
+	mock *MockEmailSender
+}
+
+func NewMockEmailSender(ctrl *gomock.Controller) *MockEmailSender {
+	mock := &MockEmailSender{ctrl: ctrl}
+	mock.recorder = &_MockEmailSenderRecorder{mock}
+	return mock
+}
+
+func (_m *MockEmailSender) EXPECT() *_MockEmailSenderRecorder {
+	return _m.recorder
+}
+
+func (_m *MockEmailSender) Name() string {
+	ret := _m.ctrl.Call(_m, "Name")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+func (_mr *_MockEmailSenderRecorder) Name() *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "Name")
+}
+
+func (_m *MockEmailSender) SendEmail(_param0 *domain.Email) (string, error) {
+	ret := _m.ctrl.Call(_m, "SendEmail", _param0)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockEmailSenderRecorder) SendEmail(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "SendEmail", arg0)
+}
+
+// Mock of MigrationServiceProvider interface
+type MockMigrationServiceProvider struct {
+	ctrl     *gomock.Controller
+	recorder *_MockMigrationServiceProviderRecorder
+}
+
+// Recorder for MockMigrationServiceProvider (not exported)
+type _MockMigrationServiceProviderRecorder struct {
+	mock *MockMigrationServiceProvider
+}
+
+func NewMockMigrationServiceProvider(ctrl *gomock.Controller) *MockMigrationServiceProvider {
+	mock := &MockMigrationServiceProvider{ctrl: ctrl}
+	mock.recorder = &_MockMigrationServiceProviderRecorder{mock}
+	return mock
+}
+
+func (_m *MockMigrationServiceProvider) EXPECT() *_MockMigrationServiceProviderRecorder {
+	return _m.recorder
+}
+
+func (_m *MockMigrationServiceProvider) Up() ([]domain.MigrationStatus, error) {
+	ret := _m.ctrl.Call(_m, "Up")
+	ret0, _ := ret[0].([]domain.MigrationStatus)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockMigrationServiceProviderRecorder) Up() *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "Up")
+}
+
+func (_m *MockMigrationServiceProvider) Down() (*domain.MigrationStatus, error) {
+	ret := _m.ctrl.Call(_m, "Down")
+	ret0, _ := ret[0].(*domain.MigrationStatus)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockMigrationServiceProviderRecorder) Down() *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "Down")
+}
+
+func (_m *MockMigrationServiceProvider) Status() ([]domain.MigrationStatus, error) {
+	ret := _m.ctrl.Call(_m, "Status")
+	ret0, _ := ret[0].([]domain.MigrationStatus)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockMigrationServiceProviderRecorder) Status() *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "Status")
+}
+
 // Mock of RSVPServiceProvider interface
 type MockRSVPServiceProvider struct {
 	ctrl     *gomock.Controller
@@ -327,15 +799,15 @@ func (_mr *_MockRSVPServiceProviderRecorder) CreateRSVP(arg0 interface{}) *gomoc
 	return _mr.mock.ctrl.RecordCall(_mr.mock, "CreateRSVP", arg0)
 }
 
-func (_m *MockRSVPServiceProvider) ListRSVPs() ([]domain.RSVP, error) {
-	ret := _m.ctrl.Call(_m, "ListRSVPs")
-	ret0, _ := ret[0].([]domain.RSVP)
+func (_m *MockRSVPServiceProvider) ListRSVPs(_param0 *domain.RSVPListRequest) (*domain.RSVPList, error) {
+	ret := _m.ctrl.Call(_m, "ListRSVPs", _param0)
+	ret0, _ := ret[0].(*domain.RSVPList)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-func (_mr *_MockRSVPServiceProviderRecorder) ListRSVPs() *gomock.Call {
-	return _mr.mock.ctrl.RecordCall(_mr.mock, "ListRSVPs")
+func (_mr *_MockRSVPServiceProviderRecorder) ListRSVPs(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "ListRSVPs", arg0)
 }
 
 func (_m *MockRSVPServiceProvider) UpdateRSVP(_param0 *domain.RSVPUpdateRequest) (*domain.RSVP, error) {
@@ -359,6 +831,28 @@ func (_mr *_MockRSVPServiceProviderRecorder) DeleteRSVPByID(arg0 interface{}) *g
 	return _mr.mock.ctrl.RecordCall(_mr.mock, "DeleteRSVPByID", arg0)
 }
 
+func (_m *MockRSVPServiceProvider) RestoreRSVPByID(rsvpID int64) (*domain.RSVP, error) {
+	ret := _m.ctrl.Call(_m, "RestoreRSVPByID", rsvpID)
+	ret0, _ := ret[0].(*domain.RSVP)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockRSVPServiceProviderRecorder) RestoreRSVPByID(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "RestoreRSVPByID", arg0)
+}
+
+func (_m *MockRSVPServiceProvider) CheckInRSVPByID(rsvpID int64) (*domain.RSVP, error) {
+	ret := _m.ctrl.Call(_m, "CheckInRSVPByID", rsvpID)
+	ret0, _ := ret[0].(*domain.RSVP)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockRSVPServiceProviderRecorder) CheckInRSVPByID(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "CheckInRSVPByID", arg0)
+}
+
 func (_m *MockRSVPServiceProvider) RetrievePrivateRSVP(invitationPrivateID string) (*domain.RSVP, error) {
 	ret := _m.ctrl.Call(_m, "RetrievePrivateRSVP", invitationPrivateID)
 	ret0, _ := ret[0].(*domain.RSVP)
@@ -369,3 +863,390 @@ func (_m *MockRSVPServiceProvider) RetrievePrivateRSVP(invitationPrivateID strin
 func (_mr *_MockRSVPServiceProviderRecorder) RetrievePrivateRSVP(arg0 interface{}) *gomock.Call {
 	return _mr.mock.ctrl.RecordCall(_mr.mock, "RetrievePrivateRSVP", arg0)
 }
+
+// Mock of SearchServiceProvider interface
+type MockSearchServiceProvider struct {
+	ctrl     *gomock.Controller
+	recorder *_MockSearchServiceProviderRecorder
+}
+
+// Recorder for MockSearchServiceProvider (not exported)
+type _MockSearchServiceProviderRecorder struct {
+	mock *MockSearchServiceProvider
+}
+
+func NewMockSearchServiceProvider(ctrl *gomock.Controller) *MockSearchServiceProvider {
+	mock := &MockSearchServiceProvider{ctrl: ctrl}
+	mock.recorder = &_MockSearchServiceProviderRecorder{mock}
+	return mock
+}
+
+func (_m *MockSearchServiceProvider) EXPECT() *_MockSearchServiceProviderRecorder {
+	return _m.recorder
+}
+
+func (_m *MockSearchServiceProvider) Search(_param0 *domain.SearchRequest) (*domain.SearchResults, error) {
+	ret := _m.ctrl.Call(_m, "Search", _param0)
+	ret0, _ := ret[0].(*domain.SearchResults)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockSearchServiceProviderRecorder) Search(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "Search", arg0)
+}
+
+// Mock of TrashServiceProvider interface
+type MockTrashServiceProvider struct {
+	ctrl     *gomock.Controller
+	recorder *_MockTrashServiceProviderRecorder
+}
+
+// Recorder for MockTrashServiceProvider (not exported)
+type _MockTrashServiceProviderRecorder struct {
+	mock *MockTrashServiceProvider
+}
+
+func NewMockTrashServiceProvider(ctrl *gomock.Controller) *MockTrashServiceProvider {
+	mock := &MockTrashServiceProvider{ctrl: ctrl}
+	mock.recorder = &_MockTrashServiceProviderRecorder{mock}
+	return mock
+}
+
+func (_m *MockTrashServiceProvider) EXPECT() *_MockTrashServiceProviderRecorder {
+	return _m.recorder
+}
+
+func (_m *MockTrashServiceProvider) ListTrash() (*domain.Trash, error) {
+	ret := _m.ctrl.Call(_m, "ListTrash")
+	ret0, _ := ret[0].(*domain.Trash)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockTrashServiceProviderRecorder) ListTrash() *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "ListTrash")
+}
+
+func (_m *MockTrashServiceProvider) Purge(deletedBefore time.Time) (*domain.PurgeResult, error) {
+	ret := _m.ctrl.Call(_m, "Purge", deletedBefore)
+	ret0, _ := ret[0].(*domain.PurgeResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockTrashServiceProviderRecorder) Purge(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "Purge", arg0)
+}
+
+// Mock of AuditServiceProvider interface
+type MockAuditServiceProvider struct {
+	ctrl     *gomock.Controller
+	recorder *_MockAuditServiceProviderRecorder
+}
+
+// Recorder for MockAuditServiceProvider (not exported)
+type _MockAuditServiceProviderRecorder struct {
+	mock *MockAuditServiceProvider
+}
+
+func NewMockAuditServiceProvider(ctrl *gomock.Controller) *MockAuditServiceProvider {
+	mock := &MockAuditServiceProvider{ctrl: ctrl}
+	mock.recorder = &_MockAuditServiceProviderRecorder{mock}
+	return mock
+}
+
+func (_m *MockAuditServiceProvider) EXPECT() *_MockAuditServiceProviderRecorder {
+	return _m.recorder
+}
+
+func (_m *MockAuditServiceProvider) ListAuditEntries(_param0 *domain.AuditListRequest) (*domain.AuditList, error) {
+	ret := _m.ctrl.Call(_m, "ListAuditEntries", _param0)
+	ret0, _ := ret[0].(*domain.AuditList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockAuditServiceProviderRecorder) ListAuditEntries(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "ListAuditEntries", arg0)
+}
+
+// Mock of UserServiceProvider interface
+type MockUserServiceProvider struct {
+	ctrl     *gomock.Controller
+	recorder *_MockUserServiceProviderRecorder
+}
+
+// Recorder for MockUserServiceProvider (not exported)
+type _MockUserServiceProviderRecorder struct {
+	mock *MockUserServiceProvider
+}
+
+func NewMockUserServiceProvider(ctrl *gomock.Controller) *MockUserServiceProvider {
+	mock := &MockUserServiceProvider{ctrl: ctrl}
+	mock.recorder = &_MockUserServiceProviderRecorder{mock}
+	return mock
+}
+
+func (_m *MockUserServiceProvider) EXPECT() *_MockUserServiceProviderRecorder {
+	return _m.recorder
+}
+
+func (_m *MockUserServiceProvider) CreateUser(_param0 *domain.UserCreateRequest) (*domain.User, error) {
+	ret := _m.ctrl.Call(_m, "CreateUser", _param0)
+	ret0, _ := ret[0].(*domain.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockUserServiceProviderRecorder) CreateUser(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "CreateUser", arg0)
+}
+
+func (_m *MockUserServiceProvider) ListUsers() ([]domain.User, error) {
+	ret := _m.ctrl.Call(_m, "ListUsers")
+	ret0, _ := ret[0].([]domain.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockUserServiceProviderRecorder) ListUsers() *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "ListUsers")
+}
+
+func (_m *MockUserServiceProvider) RetrieveUserByUsername(username string) (*domain.User, error) {
+	ret := _m.ctrl.Call(_m, "RetrieveUserByUsername", username)
+	ret0, _ := ret[0].(*domain.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockUserServiceProviderRecorder) RetrieveUserByUsername(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "RetrieveUserByUsername", arg0)
+}
+
+func (_m *MockUserServiceProvider) DisableUserByID(userID int64) (*domain.User, error) {
+	ret := _m.ctrl.Call(_m, "DisableUserByID", userID)
+	ret0, _ := ret[0].(*domain.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockUserServiceProviderRecorder) DisableUserByID(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "DisableUserByID", arg0)
+}
+
+func (_m *MockUserServiceProvider) UpdateUserRole(_param0 *domain.UserRoleUpdateRequest) (*domain.User, error) {
+	ret := _m.ctrl.Call(_m, "UpdateUserRole", _param0)
+	ret0, _ := ret[0].(*domain.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockUserServiceProviderRecorder) UpdateUserRole(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "UpdateUserRole", arg0)
+}
+
+func (_m *MockUserServiceProvider) ResetPassword(_param0 *domain.UserPasswordResetRequest) (*domain.User, error) {
+	ret := _m.ctrl.Call(_m, "ResetPassword", _param0)
+	ret0, _ := ret[0].(*domain.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockUserServiceProviderRecorder) ResetPassword(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "ResetPassword", arg0)
+}
+
+func (_m *MockUserServiceProvider) ChangePassword(username string, req *domain.PasswordChangeRequest) error {
+	ret := _m.ctrl.Call(_m, "ChangePassword", username, req)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+func (_mr *_MockUserServiceProviderRecorder) ChangePassword(arg0, arg1 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "ChangePassword", arg0, arg1)
+}
+
+func (_m *MockUserServiceProvider) BeginTOTPEnrollment(username string) (*domain.TOTPEnrollment, error) {
+	ret := _m.ctrl.Call(_m, "BeginTOTPEnrollment", username)
+	ret0, _ := ret[0].(*domain.TOTPEnrollment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockUserServiceProviderRecorder) BeginTOTPEnrollment(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "BeginTOTPEnrollment", arg0)
+}
+
+func (_m *MockUserServiceProvider) ConfirmTOTPEnrollment(username string, req *domain.TOTPConfirmRequest) (*domain.RecoveryCodes, error) {
+	ret := _m.ctrl.Call(_m, "ConfirmTOTPEnrollment", username, req)
+	ret0, _ := ret[0].(*domain.RecoveryCodes)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockUserServiceProviderRecorder) ConfirmTOTPEnrollment(arg0, arg1 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "ConfirmTOTPEnrollment", arg0, arg1)
+}
+
+func (_m *MockUserServiceProvider) DisableTOTP(username string, req *domain.TOTPDisableRequest) error {
+	ret := _m.ctrl.Call(_m, "DisableTOTP", username, req)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+func (_mr *_MockUserServiceProviderRecorder) DisableTOTP(arg0, arg1 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "DisableTOTP", arg0, arg1)
+}
+
+func (_m *MockUserServiceProvider) ResetTOTPByID(userID int64) (*domain.User, error) {
+	ret := _m.ctrl.Call(_m, "ResetTOTPByID", userID)
+	ret0, _ := ret[0].(*domain.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockUserServiceProviderRecorder) ResetTOTPByID(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "ResetTOTPByID", arg0)
+}
+
+// Mock of MessageTemplateServiceProvider interface
+type MockMessageTemplateServiceProvider struct {
+	ctrl     *gomock.Controller
+	recorder *_MockMessageTemplateServiceProviderRecorder
+}
+
+// Recorder for MockMessageTemplateServiceProvider (not exported)
+type _MockMessageTemplateServiceProviderRecorder struct {
+	mock *MockMessageTemplateServiceProvider
+}
+
+func NewMockMessageTemplateServiceProvider(ctrl *gomock.Controller) *MockMessageTemplateServiceProvider {
+	mock := &MockMessageTemplateServiceProvider{ctrl: ctrl}
+	mock.recorder = &_MockMessageTemplateServiceProviderRecorder{mock}
+	return mock
+}
+
+func (_m *MockMessageTemplateServiceProvider) EXPECT() *_MockMessageTemplateServiceProviderRecorder {
+	return _m.recorder
+}
+
+func (_m *MockMessageTemplateServiceProvider) CreateMessageTemplate(_param0 *domain.MessageTemplateCreateRequest) (*domain.MessageTemplate, error) {
+	ret := _m.ctrl.Call(_m, "CreateMessageTemplate", _param0)
+	ret0, _ := ret[0].(*domain.MessageTemplate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockMessageTemplateServiceProviderRecorder) CreateMessageTemplate(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "CreateMessageTemplate", arg0)
+}
+
+func (_m *MockMessageTemplateServiceProvider) ListMessageTemplates() ([]domain.MessageTemplate, error) {
+	ret := _m.ctrl.Call(_m, "ListMessageTemplates")
+	ret0, _ := ret[0].([]domain.MessageTemplate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockMessageTemplateServiceProviderRecorder) ListMessageTemplates() *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "ListMessageTemplates")
+}
+
+func (_m *MockMessageTemplateServiceProvider) UpdateMessageTemplate(_param0 *domain.MessageTemplateUpdateRequest) (*domain.MessageTemplate, error) {
+	ret := _m.ctrl.Call(_m, "UpdateMessageTemplate", _param0)
+	ret0, _ := ret[0].(*domain.MessageTemplate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockMessageTemplateServiceProviderRecorder) UpdateMessageTemplate(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "UpdateMessageTemplate", arg0)
+}
+
+func (_m *MockMessageTemplateServiceProvider) DeleteMessageTemplateByID(templateID int64) error {
+	ret := _m.ctrl.Call(_m, "DeleteMessageTemplateByID", templateID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+func (_mr *_MockMessageTemplateServiceProviderRecorder) DeleteMessageTemplateByID(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "DeleteMessageTemplateByID", arg0)
+}
+
+func (_m *MockMessageTemplateServiceProvider) PreviewMessageTemplate(templateID int64, invitationID int64) (*domain.RenderedMessage, error) {
+	ret := _m.ctrl.Call(_m, "PreviewMessageTemplate", templateID, invitationID)
+	ret0, _ := ret[0].(*domain.RenderedMessage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockMessageTemplateServiceProviderRecorder) PreviewMessageTemplate(arg0, arg1 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "PreviewMessageTemplate", arg0, arg1)
+}
+
+// Mock of APIKeyServiceProvider interface
+type MockAPIKeyServiceProvider struct {
+	ctrl     *gomock.Controller
+	recorder *_MockAPIKeyServiceProviderRecorder
+}
+
+// Recorder for MockAPIKeyServiceProvider (not exported)
+type _MockAPIKeyServiceProviderRecorder struct {
+	mock *MockAPIKeyServiceProvider
+}
+
+func NewMockAPIKeyServiceProvider(ctrl *gomock.Controller) *MockAPIKeyServiceProvider {
+	mock := &MockAPIKeyServiceProvider{ctrl: ctrl}
+	mock.recorder = &_MockAPIKeyServiceProviderRecorder{mock}
+	return mock
+}
+
+func (_m *MockAPIKeyServiceProvider) EXPECT() *_MockAPIKeyServiceProviderRecorder {
+	return _m.recorder
+}
+
+func (_m *MockAPIKeyServiceProvider) CreateAPIKey(_param0 *domain.APIKeyCreateRequest) (*domain.NewAPIKey, error) {
+	ret := _m.ctrl.Call(_m, "CreateAPIKey", _param0)
+	ret0, _ := ret[0].(*domain.NewAPIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockAPIKeyServiceProviderRecorder) CreateAPIKey(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "CreateAPIKey", arg0)
+}
+
+func (_m *MockAPIKeyServiceProvider) ListAPIKeys() ([]domain.APIKey, error) {
+	ret := _m.ctrl.Call(_m, "ListAPIKeys")
+	ret0, _ := ret[0].([]domain.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockAPIKeyServiceProviderRecorder) ListAPIKeys() *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "ListAPIKeys")
+}
+
+func (_m *MockAPIKeyServiceProvider) RevokeAPIKeyByID(apiKeyID int64) (*domain.APIKey, error) {
+	ret := _m.ctrl.Call(_m, "RevokeAPIKeyByID", apiKeyID)
+	ret0, _ := ret[0].(*domain.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockAPIKeyServiceProviderRecorder) RevokeAPIKeyByID(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "RevokeAPIKeyByID", arg0)
+}
+
+func (_m *MockAPIKeyServiceProvider) Authenticate(key string) (*domain.APIKey, error) {
+	ret := _m.ctrl.Call(_m, "Authenticate", key)
+	ret0, _ := ret[0].(*domain.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockAPIKeyServiceProviderRecorder) Authenticate(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "Authenticate", arg0)
+}
@@ -6,251 +6,1476 @@ package mock_interfaces
 import (
 	gomock "github.com/golang/mock/gomock"
 	domain "github.com/rawfish-dev/rsvp-starter/server/domain"
+	interfaces "github.com/rawfish-dev/rsvp-starter/server/interfaces"
+	time "time"
 )
 
-// Mock of CategoryStorage interface
-type MockCategoryStorage struct {
+// Mock of Storage interface
+type MockStorage struct {
 	ctrl     *gomock.Controller
-	recorder *_MockCategoryStorageRecorder
+	recorder *_MockStorageRecorder
 }
 
-// Recorder for MockCategoryStorage (not exported)
-type _MockCategoryStorageRecorder struct {
-	mock *MockCategoryStorage
+// Recorder for MockStorage (not exported)
+type _MockStorageRecorder struct {
+	mock *MockStorage
 }
 
-func NewMockCategoryStorage(ctrl *gomock.Controller) *MockCategoryStorage {
-	mock := &MockCategoryStorage{ctrl: ctrl}
-	mock.recorder = &_MockCategoryStorageRecorder{mock}
+func NewMockStorage(ctrl *gomock.Controller) *MockStorage {
+	mock := &MockStorage{ctrl: ctrl}
+	mock.recorder = &_MockStorageRecorder{mock}
 	return mock
 }
 
-func (_m *MockCategoryStorage) EXPECT() *_MockCategoryStorageRecorder {
+func (_m *MockStorage) EXPECT() *_MockStorageRecorder {
 	return _m.recorder
 }
 
-func (_m *MockCategoryStorage) InsertCategory(_param0 *domain.CategoryCreateRequest) (*domain.Category, error) {
+func (_m *MockStorage) InsertCategory(_param0 *domain.CategoryCreateRequest) (*domain.Category, error) {
 	ret := _m.ctrl.Call(_m, "InsertCategory", _param0)
 	ret0, _ := ret[0].(*domain.Category)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-func (_mr *_MockCategoryStorageRecorder) InsertCategory(arg0 interface{}) *gomock.Call {
+func (_mr *_MockStorageRecorder) InsertCategory(arg0 interface{}) *gomock.Call {
 	return _mr.mock.ctrl.RecordCall(_mr.mock, "InsertCategory", arg0)
 }
 
-func (_m *MockCategoryStorage) FindCategoryByID(categoryID int64) (*domain.Category, error) {
+func (_m *MockStorage) FindCategoryByID(categoryID int64) (*domain.Category, error) {
 	ret := _m.ctrl.Call(_m, "FindCategoryByID", categoryID)
 	ret0, _ := ret[0].(*domain.Category)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-func (_mr *_MockCategoryStorageRecorder) FindCategoryByID(arg0 interface{}) *gomock.Call {
+func (_mr *_MockStorageRecorder) FindCategoryByID(arg0 interface{}) *gomock.Call {
 	return _mr.mock.ctrl.RecordCall(_mr.mock, "FindCategoryByID", arg0)
 }
 
-func (_m *MockCategoryStorage) ListCategories() ([]domain.Category, error) {
-	ret := _m.ctrl.Call(_m, "ListCategories")
+func (_m *MockStorage) ListCategories(_param0 *domain.CategoryListRequest) ([]domain.Category, int, error) {
+	ret := _m.ctrl.Call(_m, "ListCategories", _param0)
 	ret0, _ := ret[0].([]domain.Category)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
 }
 
-func (_mr *_MockCategoryStorageRecorder) ListCategories() *gomock.Call {
-	return _mr.mock.ctrl.RecordCall(_mr.mock, "ListCategories")
+func (_mr *_MockStorageRecorder) ListCategories(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "ListCategories", arg0)
 }
 
-func (_m *MockCategoryStorage) UpdateCategory(_param0 *domain.Category) (*domain.Category, error) {
+func (_m *MockStorage) UpdateCategory(_param0 *domain.Category) (*domain.Category, error) {
 	ret := _m.ctrl.Call(_m, "UpdateCategory", _param0)
 	ret0, _ := ret[0].(*domain.Category)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-func (_mr *_MockCategoryStorageRecorder) UpdateCategory(arg0 interface{}) *gomock.Call {
+func (_mr *_MockStorageRecorder) UpdateCategory(arg0 interface{}) *gomock.Call {
 	return _mr.mock.ctrl.RecordCall(_mr.mock, "UpdateCategory", arg0)
 }
 
-func (_m *MockCategoryStorage) DeleteCategory(_param0 *domain.Category) error {
+func (_m *MockStorage) DeleteCategory(_param0 *domain.Category) error {
 	ret := _m.ctrl.Call(_m, "DeleteCategory", _param0)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-func (_mr *_MockCategoryStorageRecorder) DeleteCategory(arg0 interface{}) *gomock.Call {
+func (_mr *_MockStorageRecorder) DeleteCategory(arg0 interface{}) *gomock.Call {
 	return _mr.mock.ctrl.RecordCall(_mr.mock, "DeleteCategory", arg0)
 }
 
-// Mock of InvitationStorage interface
-type MockInvitationStorage struct {
-	ctrl     *gomock.Controller
-	recorder *_MockInvitationStorageRecorder
+func (_m *MockStorage) FindDeletedCategoryByID(categoryID int64) (*domain.Category, error) {
+	ret := _m.ctrl.Call(_m, "FindDeletedCategoryByID", categoryID)
+	ret0, _ := ret[0].(*domain.Category)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// Recorder for MockInvitationStorage (not exported)
-type _MockInvitationStorageRecorder struct {
-	mock *MockInvitationStorage
+func (_mr *_MockStorageRecorder) FindDeletedCategoryByID(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "FindDeletedCategoryByID", arg0)
 }
 
-func NewMockInvitationStorage(ctrl *gomock.Controller) *MockInvitationStorage {
-	mock := &MockInvitationStorage{ctrl: ctrl}
-	mock.recorder = &_MockInvitationStorageRecorder{mock}
-	return mock
+func (_m *MockStorage) ListDeletedCategories() ([]domain.Category, error) {
+	ret := _m.ctrl.Call(_m, "ListDeletedCategories")
+	ret0, _ := ret[0].([]domain.Category)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-func (_m *MockInvitationStorage) EXPECT() *_MockInvitationStorageRecorder {
-	return _m.recorder
+func (_mr *_MockStorageRecorder) ListDeletedCategories() *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "ListDeletedCategories")
 }
 
-func (_m *MockInvitationStorage) InsertInvitation(_param0 *domain.InvitationCreateRequest) (*domain.Invitation, error) {
+func (_m *MockStorage) RestoreCategory(_param0 *domain.Category) (*domain.Category, error) {
+	ret := _m.ctrl.Call(_m, "RestoreCategory", _param0)
+	ret0, _ := ret[0].(*domain.Category)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockStorageRecorder) RestoreCategory(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "RestoreCategory", arg0)
+}
+
+func (_m *MockStorage) PurgeCategories(deletedBefore time.Time) (int, error) {
+	ret := _m.ctrl.Call(_m, "PurgeCategories", deletedBefore)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockStorageRecorder) PurgeCategories(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "PurgeCategories", arg0)
+}
+
+func (_m *MockStorage) InsertInvitation(_param0 *domain.InvitationCreateRequest) (*domain.Invitation, error) {
 	ret := _m.ctrl.Call(_m, "InsertInvitation", _param0)
 	ret0, _ := ret[0].(*domain.Invitation)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-func (_mr *_MockInvitationStorageRecorder) InsertInvitation(arg0 interface{}) *gomock.Call {
+func (_mr *_MockStorageRecorder) InsertInvitation(arg0 interface{}) *gomock.Call {
 	return _mr.mock.ctrl.RecordCall(_mr.mock, "InsertInvitation", arg0)
 }
 
-func (_m *MockInvitationStorage) FindInvitationByID(invitationID int64) (*domain.Invitation, error) {
+func (_m *MockStorage) FindInvitationByID(invitationID int64) (*domain.Invitation, error) {
 	ret := _m.ctrl.Call(_m, "FindInvitationByID", invitationID)
 	ret0, _ := ret[0].(*domain.Invitation)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-func (_mr *_MockInvitationStorageRecorder) FindInvitationByID(arg0 interface{}) *gomock.Call {
+func (_mr *_MockStorageRecorder) FindInvitationByID(arg0 interface{}) *gomock.Call {
 	return _mr.mock.ctrl.RecordCall(_mr.mock, "FindInvitationByID", arg0)
 }
 
-func (_m *MockInvitationStorage) FindInvitationByPrivateID(privateID string) (*domain.Invitation, error) {
+func (_m *MockStorage) FindInvitationByPrivateID(privateID string) (*domain.Invitation, error) {
 	ret := _m.ctrl.Call(_m, "FindInvitationByPrivateID", privateID)
 	ret0, _ := ret[0].(*domain.Invitation)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-func (_mr *_MockInvitationStorageRecorder) FindInvitationByPrivateID(arg0 interface{}) *gomock.Call {
+func (_mr *_MockStorageRecorder) FindInvitationByPrivateID(arg0 interface{}) *gomock.Call {
 	return _mr.mock.ctrl.RecordCall(_mr.mock, "FindInvitationByPrivateID", arg0)
 }
 
-func (_m *MockInvitationStorage) ListInvitations() ([]domain.Invitation, error) {
-	ret := _m.ctrl.Call(_m, "ListInvitations")
+func (_m *MockStorage) ListInvitations(_param0 *domain.InvitationListRequest) ([]domain.Invitation, int, error) {
+	ret := _m.ctrl.Call(_m, "ListInvitations", _param0)
 	ret0, _ := ret[0].([]domain.Invitation)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
 }
 
-func (_mr *_MockInvitationStorageRecorder) ListInvitations() *gomock.Call {
-	return _mr.mock.ctrl.RecordCall(_mr.mock, "ListInvitations")
+func (_mr *_MockStorageRecorder) ListInvitations(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "ListInvitations", arg0)
 }
 
-func (_m *MockInvitationStorage) UpdateInvitation(_param0 *domain.Invitation) (*domain.Invitation, error) {
+func (_m *MockStorage) UpdateInvitation(_param0 *domain.Invitation) (*domain.Invitation, error) {
 	ret := _m.ctrl.Call(_m, "UpdateInvitation", _param0)
 	ret0, _ := ret[0].(*domain.Invitation)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-func (_mr *_MockInvitationStorageRecorder) UpdateInvitation(arg0 interface{}) *gomock.Call {
+func (_mr *_MockStorageRecorder) UpdateInvitation(arg0 interface{}) *gomock.Call {
 	return _mr.mock.ctrl.RecordCall(_mr.mock, "UpdateInvitation", arg0)
 }
 
-func (_m *MockInvitationStorage) DeleteInvitation(_param0 *domain.Invitation) error {
+func (_m *MockStorage) DeleteInvitation(_param0 *domain.Invitation) error {
 	ret := _m.ctrl.Call(_m, "DeleteInvitation", _param0)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-func (_mr *_MockInvitationStorageRecorder) DeleteInvitation(arg0 interface{}) *gomock.Call {
+func (_mr *_MockStorageRecorder) DeleteInvitation(arg0 interface{}) *gomock.Call {
 	return _mr.mock.ctrl.RecordCall(_mr.mock, "DeleteInvitation", arg0)
 }
 
-// Mock of RSVPStorage interface
-type MockRSVPStorage struct {
-	ctrl     *gomock.Controller
-	recorder *_MockRSVPStorageRecorder
+func (_m *MockStorage) FindDeletedInvitationByID(invitationID int64) (*domain.Invitation, error) {
+	ret := _m.ctrl.Call(_m, "FindDeletedInvitationByID", invitationID)
+	ret0, _ := ret[0].(*domain.Invitation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// Recorder for MockRSVPStorage (not exported)
-type _MockRSVPStorageRecorder struct {
-	mock *MockRSVPStorage
+func (_mr *_MockStorageRecorder) FindDeletedInvitationByID(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "FindDeletedInvitationByID", arg0)
 }
 
-func NewMockRSVPStorage(ctrl *gomock.Controller) *MockRSVPStorage {
-	mock := &MockRSVPStorage{ctrl: ctrl}
-	mock.recorder = &_MockRSVPStorageRecorder{mock}
-	return mock
+func (_m *MockStorage) ListDeletedInvitations() ([]domain.Invitation, error) {
+	ret := _m.ctrl.Call(_m, "ListDeletedInvitations")
+	ret0, _ := ret[0].([]domain.Invitation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-func (_m *MockRSVPStorage) EXPECT() *_MockRSVPStorageRecorder {
-	return _m.recorder
+func (_mr *_MockStorageRecorder) ListDeletedInvitations() *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "ListDeletedInvitations")
 }
 
-func (_m *MockRSVPStorage) InsertRSVP(_param0 *domain.RSVPCreateRequest) (*domain.RSVP, error) {
+func (_m *MockStorage) RestoreInvitation(_param0 *domain.Invitation) (*domain.Invitation, error) {
+	ret := _m.ctrl.Call(_m, "RestoreInvitation", _param0)
+	ret0, _ := ret[0].(*domain.Invitation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockStorageRecorder) RestoreInvitation(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "RestoreInvitation", arg0)
+}
+
+func (_m *MockStorage) PurgeInvitations(deletedBefore time.Time) (int, error) {
+	ret := _m.ctrl.Call(_m, "PurgeInvitations", deletedBefore)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockStorageRecorder) PurgeInvitations(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "PurgeInvitations", arg0)
+}
+
+func (_m *MockStorage) InsertRetiredInvitationLink(invitationID int64, privateID string) error {
+	ret := _m.ctrl.Call(_m, "InsertRetiredInvitationLink", invitationID, privateID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+func (_mr *_MockStorageRecorder) InsertRetiredInvitationLink(arg0, arg1 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "InsertRetiredInvitationLink", arg0, arg1)
+}
+
+func (_m *MockStorage) IsInvitationLinkRetired(privateID string) (bool, error) {
+	ret := _m.ctrl.Call(_m, "IsInvitationLinkRetired", privateID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockStorageRecorder) IsInvitationLinkRetired(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "IsInvitationLinkRetired", arg0)
+}
+
+func (_m *MockStorage) InsertRSVP(_param0 *domain.RSVPCreateRequest) (*domain.RSVP, error) {
 	ret := _m.ctrl.Call(_m, "InsertRSVP", _param0)
 	ret0, _ := ret[0].(*domain.RSVP)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-func (_mr *_MockRSVPStorageRecorder) InsertRSVP(arg0 interface{}) *gomock.Call {
+func (_mr *_MockStorageRecorder) InsertRSVP(arg0 interface{}) *gomock.Call {
 	return _mr.mock.ctrl.RecordCall(_mr.mock, "InsertRSVP", arg0)
 }
 
-func (_m *MockRSVPStorage) FindRSVPByID(rsvpID int64) (*domain.RSVP, error) {
+func (_m *MockStorage) FindRSVPByID(rsvpID int64) (*domain.RSVP, error) {
 	ret := _m.ctrl.Call(_m, "FindRSVPByID", rsvpID)
 	ret0, _ := ret[0].(*domain.RSVP)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-func (_mr *_MockRSVPStorageRecorder) FindRSVPByID(arg0 interface{}) *gomock.Call {
+func (_mr *_MockStorageRecorder) FindRSVPByID(arg0 interface{}) *gomock.Call {
 	return _mr.mock.ctrl.RecordCall(_mr.mock, "FindRSVPByID", arg0)
 }
 
-func (_m *MockRSVPStorage) FindRSVPByInvitationPrivateID(invitationPrivateID string) (*domain.RSVP, error) {
+func (_m *MockStorage) FindRSVPByInvitationPrivateID(invitationPrivateID string) (*domain.RSVP, error) {
 	ret := _m.ctrl.Call(_m, "FindRSVPByInvitationPrivateID", invitationPrivateID)
 	ret0, _ := ret[0].(*domain.RSVP)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-func (_mr *_MockRSVPStorageRecorder) FindRSVPByInvitationPrivateID(arg0 interface{}) *gomock.Call {
+func (_mr *_MockStorageRecorder) FindRSVPByInvitationPrivateID(arg0 interface{}) *gomock.Call {
 	return _mr.mock.ctrl.RecordCall(_mr.mock, "FindRSVPByInvitationPrivateID", arg0)
 }
 
-func (_m *MockRSVPStorage) ListRSVPs() ([]domain.RSVP, error) {
-	ret := _m.ctrl.Call(_m, "ListRSVPs")
+func (_m *MockStorage) ListRSVPs(_param0 *domain.RSVPListRequest) ([]domain.RSVP, int, error) {
+	ret := _m.ctrl.Call(_m, "ListRSVPs", _param0)
 	ret0, _ := ret[0].([]domain.RSVP)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
 }
 
-func (_mr *_MockRSVPStorageRecorder) ListRSVPs() *gomock.Call {
-	return _mr.mock.ctrl.RecordCall(_mr.mock, "ListRSVPs")
+func (_mr *_MockStorageRecorder) ListRSVPs(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "ListRSVPs", arg0)
 }
 
-func (_m *MockRSVPStorage) UpdateRSVP(_param0 *domain.RSVP) (*domain.RSVP, error) {
+func (_m *MockStorage) UpdateRSVP(_param0 *domain.RSVP) (*domain.RSVP, error) {
 	ret := _m.ctrl.Call(_m, "UpdateRSVP", _param0)
 	ret0, _ := ret[0].(*domain.RSVP)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-func (_mr *_MockRSVPStorageRecorder) UpdateRSVP(arg0 interface{}) *gomock.Call {
+func (_mr *_MockStorageRecorder) UpdateRSVP(arg0 interface{}) *gomock.Call {
 	return _mr.mock.ctrl.RecordCall(_mr.mock, "UpdateRSVP", arg0)
 }
 
-func (_m *MockRSVPStorage) DeleteRSVP(_param0 *domain.RSVP) error {
+func (_m *MockStorage) DeleteRSVP(_param0 *domain.RSVP) error {
 	ret := _m.ctrl.Call(_m, "DeleteRSVP", _param0)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-func (_mr *_MockRSVPStorageRecorder) DeleteRSVP(arg0 interface{}) *gomock.Call {
+func (_mr *_MockStorageRecorder) DeleteRSVP(arg0 interface{}) *gomock.Call {
 	return _mr.mock.ctrl.RecordCall(_mr.mock, "DeleteRSVP", arg0)
 }
+
+func (_m *MockStorage) FindDeletedRSVPByID(rsvpID int64) (*domain.RSVP, error) {
+	ret := _m.ctrl.Call(_m, "FindDeletedRSVPByID", rsvpID)
+	ret0, _ := ret[0].(*domain.RSVP)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockStorageRecorder) FindDeletedRSVPByID(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "FindDeletedRSVPByID", arg0)
+}
+
+func (_m *MockStorage) ListDeletedRSVPs() ([]domain.RSVP, error) {
+	ret := _m.ctrl.Call(_m, "ListDeletedRSVPs")
+	ret0, _ := ret[0].([]domain.RSVP)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockStorageRecorder) ListDeletedRSVPs() *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "ListDeletedRSVPs")
+}
+
+func (_m *MockStorage) RestoreRSVP(_param0 *domain.RSVP) (*domain.RSVP, error) {
+	ret := _m.ctrl.Call(_m, "RestoreRSVP", _param0)
+	ret0, _ := ret[0].(*domain.RSVP)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockStorageRecorder) RestoreRSVP(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "RestoreRSVP", arg0)
+}
+
+func (_m *MockStorage) CheckInRSVP(_param0 *domain.RSVP) (*domain.RSVP, error) {
+	ret := _m.ctrl.Call(_m, "CheckInRSVP", _param0)
+	ret0, _ := ret[0].(*domain.RSVP)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockStorageRecorder) CheckInRSVP(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "CheckInRSVP", arg0)
+}
+
+func (_m *MockStorage) PurgeRSVPs(deletedBefore time.Time) (int, error) {
+	ret := _m.ctrl.Call(_m, "PurgeRSVPs", deletedBefore)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockStorageRecorder) PurgeRSVPs(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "PurgeRSVPs", arg0)
+}
+
+func (_m *MockStorage) Search(_param0 *domain.SearchRequest) ([]domain.SearchResult, error) {
+	ret := _m.ctrl.Call(_m, "Search", _param0)
+	ret0, _ := ret[0].([]domain.SearchResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockStorageRecorder) Search(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "Search", arg0)
+}
+
+func (_m *MockStorage) InsertAuditEntry(_param0 *domain.AuditEntryCreateRequest) (*domain.AuditEntry, error) {
+	ret := _m.ctrl.Call(_m, "InsertAuditEntry", _param0)
+	ret0, _ := ret[0].(*domain.AuditEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockStorageRecorder) InsertAuditEntry(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "InsertAuditEntry", arg0)
+}
+
+func (_m *MockStorage) ListAuditEntries(_param0 *domain.AuditListRequest) ([]domain.AuditEntry, int, error) {
+	ret := _m.ctrl.Call(_m, "ListAuditEntries", _param0)
+	ret0, _ := ret[0].([]domain.AuditEntry)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+func (_mr *_MockStorageRecorder) ListAuditEntries(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "ListAuditEntries", arg0)
+}
+
+func (_m *MockStorage) InsertUser(_param0 *domain.User) (*domain.User, error) {
+	ret := _m.ctrl.Call(_m, "InsertUser", _param0)
+	ret0, _ := ret[0].(*domain.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockStorageRecorder) InsertUser(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "InsertUser", arg0)
+}
+
+func (_m *MockStorage) FindUserByID(userID int64) (*domain.User, error) {
+	ret := _m.ctrl.Call(_m, "FindUserByID", userID)
+	ret0, _ := ret[0].(*domain.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockStorageRecorder) FindUserByID(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "FindUserByID", arg0)
+}
+
+func (_m *MockStorage) FindUserByUsername(username string) (*domain.User, error) {
+	ret := _m.ctrl.Call(_m, "FindUserByUsername", username)
+	ret0, _ := ret[0].(*domain.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockStorageRecorder) FindUserByUsername(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "FindUserByUsername", arg0)
+}
+
+func (_m *MockStorage) ListUsers() ([]domain.User, error) {
+	ret := _m.ctrl.Call(_m, "ListUsers")
+	ret0, _ := ret[0].([]domain.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockStorageRecorder) ListUsers() *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "ListUsers")
+}
+
+func (_m *MockStorage) UpdateUser(_param0 *domain.User) (*domain.User, error) {
+	ret := _m.ctrl.Call(_m, "UpdateUser", _param0)
+	ret0, _ := ret[0].(*domain.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockStorageRecorder) UpdateUser(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "UpdateUser", arg0)
+}
+
+func (_m *MockStorage) InsertAPIKey(_param0 *domain.APIKey) (*domain.APIKey, error) {
+	ret := _m.ctrl.Call(_m, "InsertAPIKey", _param0)
+	ret0, _ := ret[0].(*domain.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockStorageRecorder) InsertAPIKey(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "InsertAPIKey", arg0)
+}
+
+func (_m *MockStorage) FindAPIKeyByID(apiKeyID int64) (*domain.APIKey, error) {
+	ret := _m.ctrl.Call(_m, "FindAPIKeyByID", apiKeyID)
+	ret0, _ := ret[0].(*domain.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockStorageRecorder) FindAPIKeyByID(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "FindAPIKeyByID", arg0)
+}
+
+func (_m *MockStorage) FindAPIKeyByHash(keyHash string) (*domain.APIKey, error) {
+	ret := _m.ctrl.Call(_m, "FindAPIKeyByHash", keyHash)
+	ret0, _ := ret[0].(*domain.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockStorageRecorder) FindAPIKeyByHash(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "FindAPIKeyByHash", arg0)
+}
+
+func (_m *MockStorage) ListAPIKeys() ([]domain.APIKey, error) {
+	ret := _m.ctrl.Call(_m, "ListAPIKeys")
+	ret0, _ := ret[0].([]domain.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockStorageRecorder) ListAPIKeys() *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "ListAPIKeys")
+}
+
+func (_m *MockStorage) RevokeAPIKey(apiKeyID int64) (*domain.APIKey, error) {
+	ret := _m.ctrl.Call(_m, "RevokeAPIKey", apiKeyID)
+	ret0, _ := ret[0].(*domain.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockStorageRecorder) RevokeAPIKey(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "RevokeAPIKey", arg0)
+}
+
+func (_m *MockStorage) UpdateAPIKeyLastUsed(apiKeyID int64, lastUsedAt time.Time) error {
+	ret := _m.ctrl.Call(_m, "UpdateAPIKeyLastUsed", apiKeyID, lastUsedAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+func (_mr *_MockStorageRecorder) UpdateAPIKeyLastUsed(arg0, arg1 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "UpdateAPIKeyLastUsed", arg0, arg1)
+}
+
+func (_m *MockStorage) InsertMessage(_param0 *domain.Message) (*domain.Message, error) {
+	ret := _m.ctrl.Call(_m, "InsertMessage", _param0)
+	ret0, _ := ret[0].(*domain.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockStorageRecorder) InsertMessage(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "InsertMessage", arg0)
+}
+
+func (_m *MockStorage) FindMessageByProviderMessageID(provider string, providerMessageID string) (*domain.Message, error) {
+	ret := _m.ctrl.Call(_m, "FindMessageByProviderMessageID", provider, providerMessageID)
+	ret0, _ := ret[0].(*domain.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockStorageRecorder) FindMessageByProviderMessageID(arg0, arg1 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "FindMessageByProviderMessageID", arg0, arg1)
+}
+
+func (_m *MockStorage) ListMessagesByInvitationID(invitationID int64) ([]domain.Message, error) {
+	ret := _m.ctrl.Call(_m, "ListMessagesByInvitationID", invitationID)
+	ret0, _ := ret[0].([]domain.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockStorageRecorder) ListMessagesByInvitationID(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "ListMessagesByInvitationID", arg0)
+}
+
+func (_m *MockStorage) ListMessagesByInvitationIDs(invitationIDs []int64) ([]domain.Message, error) {
+	ret := _m.ctrl.Call(_m, "ListMessagesByInvitationIDs", invitationIDs)
+	ret0, _ := ret[0].([]domain.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockStorageRecorder) ListMessagesByInvitationIDs(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "ListMessagesByInvitationIDs", arg0)
+}
+
+func (_m *MockStorage) ListMessagesByKind(kind domain.MessageKind) ([]domain.Message, error) {
+	ret := _m.ctrl.Call(_m, "ListMessagesByKind", kind)
+	ret0, _ := ret[0].([]domain.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockStorageRecorder) ListMessagesByKind(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "ListMessagesByKind", arg0)
+}
+
+func (_m *MockStorage) UpdateMessage(_param0 *domain.Message) (*domain.Message, error) {
+	ret := _m.ctrl.Call(_m, "UpdateMessage", _param0)
+	ret0, _ := ret[0].(*domain.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockStorageRecorder) UpdateMessage(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "UpdateMessage", arg0)
+}
+
+func (_m *MockStorage) InsertMessageTemplate(_param0 *domain.MessageTemplateCreateRequest) (*domain.MessageTemplate, error) {
+	ret := _m.ctrl.Call(_m, "InsertMessageTemplate", _param0)
+	ret0, _ := ret[0].(*domain.MessageTemplate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockStorageRecorder) InsertMessageTemplate(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "InsertMessageTemplate", arg0)
+}
+
+func (_m *MockStorage) FindMessageTemplateByID(templateID int64) (*domain.MessageTemplate, error) {
+	ret := _m.ctrl.Call(_m, "FindMessageTemplateByID", templateID)
+	ret0, _ := ret[0].(*domain.MessageTemplate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockStorageRecorder) FindMessageTemplateByID(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "FindMessageTemplateByID", arg0)
+}
+
+func (_m *MockStorage) ListMessageTemplates() ([]domain.MessageTemplate, error) {
+	ret := _m.ctrl.Call(_m, "ListMessageTemplates")
+	ret0, _ := ret[0].([]domain.MessageTemplate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockStorageRecorder) ListMessageTemplates() *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "ListMessageTemplates")
+}
+
+func (_m *MockStorage) UpdateMessageTemplate(_param0 *domain.MessageTemplate) (*domain.MessageTemplate, error) {
+	ret := _m.ctrl.Call(_m, "UpdateMessageTemplate", _param0)
+	ret0, _ := ret[0].(*domain.MessageTemplate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockStorageRecorder) UpdateMessageTemplate(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "UpdateMessageTemplate", arg0)
+}
+
+func (_m *MockStorage) DeleteMessageTemplate(_param0 *domain.MessageTemplate) error {
+	ret := _m.ctrl.Call(_m, "DeleteMessageTemplate", _param0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+func (_mr *_MockStorageRecorder) DeleteMessageTemplate(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "DeleteMessageTemplate", arg0)
+}
+
+func (_m *MockStorage) WithTx(fn func(interfaces.Storage) error) error {
+	ret := _m.ctrl.Call(_m, "WithTx", fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+func (_mr *_MockStorageRecorder) WithTx(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "WithTx", arg0)
+}
+
+// Mock of CategoryStorage interface
+type MockCategoryStorage struct {
+	ctrl     *gomock.Controller
+	recorder *_MockCategoryStorageRecorder
+}
+
+// Recorder for MockCategoryStorage (not exported)
+type _MockCategoryStorageRecorder struct {
+	mock *MockCategoryStorage
+}
+
+func NewMockCategoryStorage(ctrl *gomock.Controller) *MockCategoryStorage {
+	mock := &MockCategoryStorage{ctrl: ctrl}
+	mock.recorder = &_MockCategoryStorageRecorder{mock}
+	return mock
+}
+
+func (_m *MockCategoryStorage) EXPECT() *_MockCategoryStorageRecorder {
+	return _m.recorder
+}
+
+func (_m *MockCategoryStorage) InsertCategory(_param0 *domain.CategoryCreateRequest) (*domain.Category, error) {
+	ret := _m.ctrl.Call(_m, "InsertCategory", _param0)
+	ret0, _ := ret[0].(*domain.Category)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockCategoryStorageRecorder) InsertCategory(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "InsertCategory", arg0)
+}
+
+func (_m *MockCategoryStorage) FindCategoryByID(categoryID int64) (*domain.Category, error) {
+	ret := _m.ctrl.Call(_m, "FindCategoryByID", categoryID)
+	ret0, _ := ret[0].(*domain.Category)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockCategoryStorageRecorder) FindCategoryByID(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "FindCategoryByID", arg0)
+}
+
+func (_m *MockCategoryStorage) ListCategories(_param0 *domain.CategoryListRequest) ([]domain.Category, int, error) {
+	ret := _m.ctrl.Call(_m, "ListCategories", _param0)
+	ret0, _ := ret[0].([]domain.Category)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+func (_mr *_MockCategoryStorageRecorder) ListCategories(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "ListCategories", arg0)
+}
+
+func (_m *MockCategoryStorage) UpdateCategory(_param0 *domain.Category) (*domain.Category, error) {
+	ret := _m.ctrl.Call(_m, "UpdateCategory", _param0)
+	ret0, _ := ret[0].(*domain.Category)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockCategoryStorageRecorder) UpdateCategory(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "UpdateCategory", arg0)
+}
+
+func (_m *MockCategoryStorage) DeleteCategory(_param0 *domain.Category) error {
+	ret := _m.ctrl.Call(_m, "DeleteCategory", _param0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+func (_mr *_MockCategoryStorageRecorder) DeleteCategory(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "DeleteCategory", arg0)
+}
+
+func (_m *MockCategoryStorage) FindDeletedCategoryByID(categoryID int64) (*domain.Category, error) {
+	ret := _m.ctrl.Call(_m, "FindDeletedCategoryByID", categoryID)
+	ret0, _ := ret[0].(*domain.Category)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockCategoryStorageRecorder) FindDeletedCategoryByID(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "FindDeletedCategoryByID", arg0)
+}
+
+func (_m *MockCategoryStorage) ListDeletedCategories() ([]domain.Category, error) {
+	ret := _m.ctrl.Call(_m, "ListDeletedCategories")
+	ret0, _ := ret[0].([]domain.Category)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockCategoryStorageRecorder) ListDeletedCategories() *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "ListDeletedCategories")
+}
+
+func (_m *MockCategoryStorage) RestoreCategory(_param0 *domain.Category) (*domain.Category, error) {
+	ret := _m.ctrl.Call(_m, "RestoreCategory", _param0)
+	ret0, _ := ret[0].(*domain.Category)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockCategoryStorageRecorder) RestoreCategory(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "RestoreCategory", arg0)
+}
+
+func (_m *MockCategoryStorage) PurgeCategories(deletedBefore time.Time) (int, error) {
+	ret := _m.ctrl.Call(_m, "PurgeCategories", deletedBefore)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockCategoryStorageRecorder) PurgeCategories(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "PurgeCategories", arg0)
+}
+
+// Mock of InvitationStorage interface
+type MockInvitationStorage struct {
+	ctrl     *gomock.Controller
+	recorder *_MockInvitationStorageRecorder
+}
+
+// Recorder for MockInvitationStorage (not exported)
+type _MockInvitationStorageRecorder struct {
+	mock *MockInvitationStorage
+}
+
+func NewMockInvitationStorage(ctrl *gomock.Controller) *MockInvitationStorage {
+	mock := &MockInvitationStorage{ctrl: ctrl}
+	mock.recorder = &_MockInvitationStorageRecorder{mock}
+	return mock
+}
+
+func (_m *MockInvitationStorage) EXPECT() *_MockInvitationStorageRecorder {
+	return _m.recorder
+}
+
+func (_m *MockInvitationStorage) InsertInvitation(_param0 *domain.InvitationCreateRequest) (*domain.Invitation, error) {
+	ret := _m.ctrl.Call(_m, "InsertInvitation", _param0)
+	ret0, _ := ret[0].(*domain.Invitation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockInvitationStorageRecorder) InsertInvitation(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "InsertInvitation", arg0)
+}
+
+func (_m *MockInvitationStorage) FindInvitationByID(invitationID int64) (*domain.Invitation, error) {
+	ret := _m.ctrl.Call(_m, "FindInvitationByID", invitationID)
+	ret0, _ := ret[0].(*domain.Invitation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockInvitationStorageRecorder) FindInvitationByID(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "FindInvitationByID", arg0)
+}
+
+func (_m *MockInvitationStorage) FindInvitationByPrivateID(privateID string) (*domain.Invitation, error) {
+	ret := _m.ctrl.Call(_m, "FindInvitationByPrivateID", privateID)
+	ret0, _ := ret[0].(*domain.Invitation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockInvitationStorageRecorder) FindInvitationByPrivateID(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "FindInvitationByPrivateID", arg0)
+}
+
+func (_m *MockInvitationStorage) ListInvitations(_param0 *domain.InvitationListRequest) ([]domain.Invitation, int, error) {
+	ret := _m.ctrl.Call(_m, "ListInvitations", _param0)
+	ret0, _ := ret[0].([]domain.Invitation)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+func (_mr *_MockInvitationStorageRecorder) ListInvitations(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "ListInvitations", arg0)
+}
+
+func (_m *MockInvitationStorage) UpdateInvitation(_param0 *domain.Invitation) (*domain.Invitation, error) {
+	ret := _m.ctrl.Call(_m, "UpdateInvitation", _param0)
+	ret0, _ := ret[0].(*domain.Invitation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockInvitationStorageRecorder) UpdateInvitation(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "UpdateInvitation", arg0)
+}
+
+func (_m *MockInvitationStorage) DeleteInvitation(_param0 *domain.Invitation) error {
+	ret := _m.ctrl.Call(_m, "DeleteInvitation", _param0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+func (_mr *_MockInvitationStorageRecorder) DeleteInvitation(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "DeleteInvitation", arg0)
+}
+
+func (_m *MockInvitationStorage) FindDeletedInvitationByID(invitationID int64) (*domain.Invitation, error) {
+	ret := _m.ctrl.Call(_m, "FindDeletedInvitationByID", invitationID)
+	ret0, _ := ret[0].(*domain.Invitation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockInvitationStorageRecorder) FindDeletedInvitationByID(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "FindDeletedInvitationByID", arg0)
+}
+
+func (_m *MockInvitationStorage) ListDeletedInvitations() ([]domain.Invitation, error) {
+	ret := _m.ctrl.Call(_m, "ListDeletedInvitations")
+	ret0, _ := ret[0].([]domain.Invitation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockInvitationStorageRecorder) ListDeletedInvitations() *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "ListDeletedInvitations")
+}
+
+func (_m *MockInvitationStorage) RestoreInvitation(_param0 *domain.Invitation) (*domain.Invitation, error) {
+	ret := _m.ctrl.Call(_m, "RestoreInvitation", _param0)
+	ret0, _ := ret[0].(*domain.Invitation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockInvitationStorageRecorder) RestoreInvitation(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "RestoreInvitation", arg0)
+}
+
+func (_m *MockInvitationStorage) PurgeInvitations(deletedBefore time.Time) (int, error) {
+	ret := _m.ctrl.Call(_m, "PurgeInvitations", deletedBefore)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockInvitationStorageRecorder) PurgeInvitations(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "PurgeInvitations", arg0)
+}
+
+func (_m *MockInvitationStorage) InsertRetiredInvitationLink(invitationID int64, privateID string) error {
+	ret := _m.ctrl.Call(_m, "InsertRetiredInvitationLink", invitationID, privateID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+func (_mr *_MockInvitationStorageRecorder) InsertRetiredInvitationLink(arg0, arg1 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "InsertRetiredInvitationLink", arg0, arg1)
+}
+
+func (_m *MockInvitationStorage) IsInvitationLinkRetired(privateID string) (bool, error) {
+	ret := _m.ctrl.Call(_m, "IsInvitationLinkRetired", privateID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockInvitationStorageRecorder) IsInvitationLinkRetired(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "IsInvitationLinkRetired", arg0)
+}
+
+// Mock of RSVPStorage interface
+type MockRSVPStorage struct {
+	ctrl     *gomock.Controller
+	recorder *_MockRSVPStorageRecorder
+}
+
+// Recorder for MockRSVPStorage (not exported)
+type _MockRSVPStorageRecorder struct {
+	mock *MockRSVPStorage
+}
+
+func NewMockRSVPStorage(ctrl *gomock.Controller) *MockRSVPStorage {
+	mock := &MockRSVPStorage{ctrl: ctrl}
+	mock.recorder = &_MockRSVPStorageRecorder{mock}
+	return mock
+}
+
+func (_m *MockRSVPStorage) EXPECT() *_MockRSVPStorageRecorder {
+	return _m.recorder
+}
+
+func (_m *MockRSVPStorage) InsertRSVP(_param0 *domain.RSVPCreateRequest) (*domain.RSVP, error) {
+	ret := _m.ctrl.Call(_m, "InsertRSVP", _param0)
+	ret0, _ := ret[0].(*domain.RSVP)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockRSVPStorageRecorder) InsertRSVP(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "InsertRSVP", arg0)
+}
+
+func (_m *MockRSVPStorage) FindRSVPByID(rsvpID int64) (*domain.RSVP, error) {
+	ret := _m.ctrl.Call(_m, "FindRSVPByID", rsvpID)
+	ret0, _ := ret[0].(*domain.RSVP)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockRSVPStorageRecorder) FindRSVPByID(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "FindRSVPByID", arg0)
+}
+
+func (_m *MockRSVPStorage) FindRSVPByInvitationPrivateID(invitationPrivateID string) (*domain.RSVP, error) {
+	ret := _m.ctrl.Call(_m, "FindRSVPByInvitationPrivateID", invitationPrivateID)
+	ret0, _ := ret[0].(*domain.RSVP)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockRSVPStorageRecorder) FindRSVPByInvitationPrivateID(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "FindRSVPByInvitationPrivateID", arg0)
+}
+
+func (_m *MockRSVPStorage) ListRSVPs(_param0 *domain.RSVPListRequest) ([]domain.RSVP, int, error) {
+	ret := _m.ctrl.Call(_m, "ListRSVPs", _param0)
+	ret0, _ := ret[0].([]domain.RSVP)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+func (_mr *_MockRSVPStorageRecorder) ListRSVPs(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "ListRSVPs", arg0)
+}
+
+func (_m *MockRSVPStorage) UpdateRSVP(_param0 *domain.RSVP) (*domain.RSVP, error) {
+	ret := _m.ctrl.Call(_m, "UpdateRSVP", _param0)
+	ret0, _ := ret[0].(*domain.RSVP)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockRSVPStorageRecorder) UpdateRSVP(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "UpdateRSVP", arg0)
+}
+
+func (_m *MockRSVPStorage) DeleteRSVP(_param0 *domain.RSVP) error {
+	ret := _m.ctrl.Call(_m, "DeleteRSVP", _param0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+func (_mr *_MockRSVPStorageRecorder) DeleteRSVP(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "DeleteRSVP", arg0)
+}
+
+func (_m *MockRSVPStorage) FindDeletedRSVPByID(rsvpID int64) (*domain.RSVP, error) {
+	ret := _m.ctrl.Call(_m, "FindDeletedRSVPByID", rsvpID)
+	ret0, _ := ret[0].(*domain.RSVP)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockRSVPStorageRecorder) FindDeletedRSVPByID(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "FindDeletedRSVPByID", arg0)
+}
+
+func (_m *MockRSVPStorage) ListDeletedRSVPs() ([]domain.RSVP, error) {
+	ret := _m.ctrl.Call(_m, "ListDeletedRSVPs")
+	ret0, _ := ret[0].([]domain.RSVP)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockRSVPStorageRecorder) ListDeletedRSVPs() *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "ListDeletedRSVPs")
+}
+
+func (_m *MockRSVPStorage) RestoreRSVP(_param0 *domain.RSVP) (*domain.RSVP, error) {
+	ret := _m.ctrl.Call(_m, "RestoreRSVP", _param0)
+	ret0, _ := ret[0].(*domain.RSVP)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockRSVPStorageRecorder) RestoreRSVP(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "RestoreRSVP", arg0)
+}
+
+func (_m *MockRSVPStorage) CheckInRSVP(_param0 *domain.RSVP) (*domain.RSVP, error) {
+	ret := _m.ctrl.Call(_m, "CheckInRSVP", _param0)
+	ret0, _ := ret[0].(*domain.RSVP)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockRSVPStorageRecorder) CheckInRSVP(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "CheckInRSVP", arg0)
+}
+
+func (_m *MockRSVPStorage) PurgeRSVPs(deletedBefore time.Time) (int, error) {
+	ret := _m.ctrl.Call(_m, "PurgeRSVPs", deletedBefore)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockRSVPStorageRecorder) PurgeRSVPs(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "PurgeRSVPs", arg0)
+}
+
+// Mock of SearchStorage interface
+type MockSearchStorage struct {
+	ctrl     *gomock.Controller
+	recorder *_MockSearchStorageRecorder
+}
+
+// Recorder for MockSearchStorage (not exported)
+type _MockSearchStorageRecorder struct {
+	mock *MockSearchStorage
+}
+
+func NewMockSearchStorage(ctrl *gomock.Controller) *MockSearchStorage {
+	mock := &MockSearchStorage{ctrl: ctrl}
+	mock.recorder = &_MockSearchStorageRecorder{mock}
+	return mock
+}
+
+func (_m *MockSearchStorage) EXPECT() *_MockSearchStorageRecorder {
+	return _m.recorder
+}
+
+func (_m *MockSearchStorage) Search(_param0 *domain.SearchRequest) ([]domain.SearchResult, error) {
+	ret := _m.ctrl.Call(_m, "Search", _param0)
+	ret0, _ := ret[0].([]domain.SearchResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockSearchStorageRecorder) Search(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "Search", arg0)
+}
+
+// Mock of AuditStorage interface
+type MockAuditStorage struct {
+	ctrl     *gomock.Controller
+	recorder *_MockAuditStorageRecorder
+}
+
+// Recorder for MockAuditStorage (not exported)
+type _MockAuditStorageRecorder struct {
+	mock *MockAuditStorage
+}
+
+func NewMockAuditStorage(ctrl *gomock.Controller) *MockAuditStorage {
+	mock := &MockAuditStorage{ctrl: ctrl}
+	mock.recorder = &_MockAuditStorageRecorder{mock}
+	return mock
+}
+
+func (_m *MockAuditStorage) EXPECT() *_MockAuditStorageRecorder {
+	return _m.recorder
+}
+
+func (_m *MockAuditStorage) InsertAuditEntry(_param0 *domain.AuditEntryCreateRequest) (*domain.AuditEntry, error) {
+	ret := _m.ctrl.Call(_m, "InsertAuditEntry", _param0)
+	ret0, _ := ret[0].(*domain.AuditEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockAuditStorageRecorder) InsertAuditEntry(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "InsertAuditEntry", arg0)
+}
+
+func (_m *MockAuditStorage) ListAuditEntries(_param0 *domain.AuditListRequest) ([]domain.AuditEntry, int, error) {
+	ret := _m.ctrl.Call(_m, "ListAuditEntries", _param0)
+	ret0, _ := ret[0].([]domain.AuditEntry)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+func (_mr *_MockAuditStorageRecorder) ListAuditEntries(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "ListAuditEntries", arg0)
+}
+
+// Mock of UserStorage interface
+type MockUserStorage struct {
+	ctrl     *gomock.Controller
+	recorder *_MockUserStorageRecorder
+}
+
+// Recorder for MockUserStorage (not exported)
+type _MockUserStorageRecorder struct {
+	mock *MockUserStorage
+}
+
+func NewMockUserStorage(ctrl *gomock.Controller) *MockUserStorage {
+	mock := &MockUserStorage{ctrl: ctrl}
+	mock.recorder = &_MockUserStorageRecorder{mock}
+	return mock
+}
+
+func (_m *MockUserStorage) EXPECT() *_MockUserStorageRecorder {
+	return _m.recorder
+}
+
+func (_m *MockUserStorage) InsertUser(_param0 *domain.User) (*domain.User, error) {
+	ret := _m.ctrl.Call(_m, "InsertUser", _param0)
+	ret0, _ := ret[0].(*domain.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockUserStorageRecorder) InsertUser(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "InsertUser", arg0)
+}
+
+func (_m *MockUserStorage) FindUserByID(userID int64) (*domain.User, error) {
+	ret := _m.ctrl.Call(_m, "FindUserByID", userID)
+	ret0, _ := ret[0].(*domain.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockUserStorageRecorder) FindUserByID(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "FindUserByID", arg0)
+}
+
+func (_m *MockUserStorage) FindUserByUsername(username string) (*domain.User, error) {
+	ret := _m.ctrl.Call(_m, "FindUserByUsername", username)
+	ret0, _ := ret[0].(*domain.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockUserStorageRecorder) FindUserByUsername(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "FindUserByUsername", arg0)
+}
+
+func (_m *MockUserStorage) ListUsers() ([]domain.User, error) {
+	ret := _m.ctrl.Call(_m, "ListUsers")
+	ret0, _ := ret[0].([]domain.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockUserStorageRecorder) ListUsers() *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "ListUsers")
+}
+
+func (_m *MockUserStorage) UpdateUser(_param0 *domain.User) (*domain.User, error) {
+	ret := _m.ctrl.Call(_m, "UpdateUser", _param0)
+	ret0, _ := ret[0].(*domain.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockUserStorageRecorder) UpdateUser(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "UpdateUser", arg0)
+}
+
+// Mock of APIKeyStorage interface
+type MockAPIKeyStorage struct {
+	ctrl     *gomock.Controller
+	recorder *_MockAPIKeyStorageRecorder
+}
+
+// Recorder for MockAPIKeyStorage (not exported)
+type _MockAPIKeyStorageRecorder struct {
+	mock *MockAPIKeyStorage
+}
+
+func NewMockAPIKeyStorage(ctrl *gomock.Controller) *MockAPIKeyStorage {
+	mock := &MockAPIKeyStorage{ctrl: ctrl}
+	mock.recorder = &_MockAPIKeyStorageRecorder{mock}
+	return mock
+}
+
+func (_m *MockAPIKeyStorage) EXPECT() *_MockAPIKeyStorageRecorder {
+	return _m.recorder
+}
+
+func (_m *MockAPIKeyStorage) InsertAPIKey(_param0 *domain.APIKey) (*domain.APIKey, error) {
+	ret := _m.ctrl.Call(_m, "InsertAPIKey", _param0)
+	ret0, _ := ret[0].(*domain.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockAPIKeyStorageRecorder) InsertAPIKey(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "InsertAPIKey", arg0)
+}
+
+func (_m *MockAPIKeyStorage) FindAPIKeyByID(apiKeyID int64) (*domain.APIKey, error) {
+	ret := _m.ctrl.Call(_m, "FindAPIKeyByID", apiKeyID)
+	ret0, _ := ret[0].(*domain.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockAPIKeyStorageRecorder) FindAPIKeyByID(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "FindAPIKeyByID", arg0)
+}
+
+func (_m *MockAPIKeyStorage) FindAPIKeyByHash(keyHash string) (*domain.APIKey, error) {
+	ret := _m.ctrl.Call(_m, "FindAPIKeyByHash", keyHash)
+	ret0, _ := ret[0].(*domain.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockAPIKeyStorageRecorder) FindAPIKeyByHash(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "FindAPIKeyByHash", arg0)
+}
+
+func (_m *MockAPIKeyStorage) ListAPIKeys() ([]domain.APIKey, error) {
+	ret := _m.ctrl.Call(_m, "ListAPIKeys")
+	ret0, _ := ret[0].([]domain.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockAPIKeyStorageRecorder) ListAPIKeys() *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "ListAPIKeys")
+}
+
+func (_m *MockAPIKeyStorage) RevokeAPIKey(apiKeyID int64) (*domain.APIKey, error) {
+	ret := _m.ctrl.Call(_m, "RevokeAPIKey", apiKeyID)
+	ret0, _ := ret[0].(*domain.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockAPIKeyStorageRecorder) RevokeAPIKey(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "RevokeAPIKey", arg0)
+}
+
+func (_m *MockAPIKeyStorage) UpdateAPIKeyLastUsed(apiKeyID int64, lastUsedAt time.Time) error {
+	ret := _m.ctrl.Call(_m, "UpdateAPIKeyLastUsed", apiKeyID, lastUsedAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+func (_mr *_MockAPIKeyStorageRecorder) UpdateAPIKeyLastUsed(arg0, arg1 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "UpdateAPIKeyLastUsed", arg0, arg1)
+}
+
+// Mock of MessageStorage interface
+type MockMessageStorage struct {
+	ctrl     *gomock.Controller
+	recorder *_MockMessageStorageRecorder
+}
+
+// Recorder for MockMessageStorage (not exported)
+type _MockMessageStorageRecorder struct {
+	mock *MockMessageStorage
+}
+
+func NewMockMessageStorage(ctrl *gomock.Controller) *MockMessageStorage {
+	mock := &MockMessageStorage{ctrl: ctrl}
+	mock.recorder = &_MockMessageStorageRecorder{mock}
+	return mock
+}
+
+func (_m *MockMessageStorage) EXPECT() *_MockMessageStorageRecorder {
+	return _m.recorder
+}
+
+func (_m *MockMessageStorage) InsertMessage(_param0 *domain.Message) (*domain.Message, error) {
+	ret := _m.ctrl.Call(_m, "InsertMessage", _param0)
+	ret0, _ := ret[0].(*domain.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockMessageStorageRecorder) InsertMessage(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "InsertMessage", arg0)
+}
+
+func (_m *MockMessageStorage) FindMessageByProviderMessageID(provider string, providerMessageID string) (*domain.Message, error) {
+	ret := _m.ctrl.Call(_m, "FindMessageByProviderMessageID", provider, providerMessageID)
+	ret0, _ := ret[0].(*domain.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockMessageStorageRecorder) FindMessageByProviderMessageID(arg0, arg1 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "FindMessageByProviderMessageID", arg0, arg1)
+}
+
+func (_m *MockMessageStorage) ListMessagesByInvitationID(invitationID int64) ([]domain.Message, error) {
+	ret := _m.ctrl.Call(_m, "ListMessagesByInvitationID", invitationID)
+	ret0, _ := ret[0].([]domain.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockMessageStorageRecorder) ListMessagesByInvitationID(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "ListMessagesByInvitationID", arg0)
+}
+
+func (_m *MockMessageStorage) ListMessagesByInvitationIDs(invitationIDs []int64) ([]domain.Message, error) {
+	ret := _m.ctrl.Call(_m, "ListMessagesByInvitationIDs", invitationIDs)
+	ret0, _ := ret[0].([]domain.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockMessageStorageRecorder) ListMessagesByInvitationIDs(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "ListMessagesByInvitationIDs", arg0)
+}
+
+func (_m *MockMessageStorage) ListMessagesByKind(kind domain.MessageKind) ([]domain.Message, error) {
+	ret := _m.ctrl.Call(_m, "ListMessagesByKind", kind)
+	ret0, _ := ret[0].([]domain.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockMessageStorageRecorder) ListMessagesByKind(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "ListMessagesByKind", arg0)
+}
+
+func (_m *MockMessageStorage) UpdateMessage(_param0 *domain.Message) (*domain.Message, error) {
+	ret := _m.ctrl.Call(_m, "UpdateMessage", _param0)
+	ret0, _ := ret[0].(*domain.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockMessageStorageRecorder) UpdateMessage(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "UpdateMessage", arg0)
+}
+
+// Mock of MessageTemplateStorage interface
+type MockMessageTemplateStorage struct {
+	ctrl     *gomock.Controller
+	recorder *_MockMessageTemplateStorageRecorder
+}
+
+// Recorder for MockMessageTemplateStorage (not exported)
+type _MockMessageTemplateStorageRecorder struct {
+	mock *MockMessageTemplateStorage
+}
+
+func NewMockMessageTemplateStorage(ctrl *gomock.Controller) *MockMessageTemplateStorage {
+	mock := &MockMessageTemplateStorage{ctrl: ctrl}
+	mock.recorder = &_MockMessageTemplateStorageRecorder{mock}
+	return mock
+}
+
+func (_m *MockMessageTemplateStorage) EXPECT() *_MockMessageTemplateStorageRecorder {
+	return _m.recorder
+}
+
+func (_m *MockMessageTemplateStorage) InsertMessageTemplate(_param0 *domain.MessageTemplateCreateRequest) (*domain.MessageTemplate, error) {
+	ret := _m.ctrl.Call(_m, "InsertMessageTemplate", _param0)
+	ret0, _ := ret[0].(*domain.MessageTemplate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockMessageTemplateStorageRecorder) InsertMessageTemplate(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "InsertMessageTemplate", arg0)
+}
+
+func (_m *MockMessageTemplateStorage) FindMessageTemplateByID(templateID int64) (*domain.MessageTemplate, error) {
+	ret := _m.ctrl.Call(_m, "FindMessageTemplateByID", templateID)
+	ret0, _ := ret[0].(*domain.MessageTemplate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockMessageTemplateStorageRecorder) FindMessageTemplateByID(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "FindMessageTemplateByID", arg0)
+}
+
+func (_m *MockMessageTemplateStorage) ListMessageTemplates() ([]domain.MessageTemplate, error) {
+	ret := _m.ctrl.Call(_m, "ListMessageTemplates")
+	ret0, _ := ret[0].([]domain.MessageTemplate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockMessageTemplateStorageRecorder) ListMessageTemplates() *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "ListMessageTemplates")
+}
+
+func (_m *MockMessageTemplateStorage) UpdateMessageTemplate(_param0 *domain.MessageTemplate) (*domain.MessageTemplate, error) {
+	ret := _m.ctrl.Call(_m, "UpdateMessageTemplate", _param0)
+	ret0, _ := ret[0].(*domain.MessageTemplate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockMessageTemplateStorageRecorder) UpdateMessageTemplate(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "UpdateMessageTemplate", arg0)
+}
+
+func (_m *MockMessageTemplateStorage) DeleteMessageTemplate(_param0 *domain.MessageTemplate) error {
+	ret := _m.ctrl.Call(_m, "DeleteMessageTemplate", _param0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+func (_mr *_MockMessageTemplateStorageRecorder) DeleteMessageTemplate(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "DeleteMessageTemplate", arg0)
+}
@@ -0,0 +1,20 @@
+package mock_interfaces
+
+import (
+	gomock "github.com/golang/mock/gomock"
+	interfaces "github.com/rawfish-dev/rsvp-starter/server/interfaces"
+)
+
+// MockTransactionalStorage runs WithTx callbacks straight against the wrapped mock so tests only
+// need expectations for the storage calls made inside the transaction. It is not generated.
+type MockTransactionalStorage struct {
+	*MockStorage
+}
+
+func NewMockTransactionalStorage(ctrl *gomock.Controller) *MockTransactionalStorage {
+	return &MockTransactionalStorage{NewMockStorage(ctrl)}
+}
+
+func (_m *MockTransactionalStorage) WithTx(fn func(interfaces.Storage) error) error {
+	return fn(_m)
+}
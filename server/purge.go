@@ -0,0 +1,36 @@
+package main
+
+import (
+	"time"
+
+	"github.com/rawfish-dev/rsvp-starter/server/api"
+	"github.com/rawfish-dev/rsvp-starter/server/config"
+
+	"github.com/Sirupsen/logrus"
+	"golang.org/x/net/context"
+)
+
+// runPurgeJob permanently removes records that have been in the trash for longer than the retention
+// period, once per purge interval for as long as the server runs.
+func runPurgeJob(trashAPI *api.API, trashConfig config.TrashConfig) {
+	if trashConfig.PurgeInterval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(trashConfig.PurgeInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		ctxlogger := logrus.New()
+		ctx := context.Background()
+		ctx = context.WithValue(ctx, "logger", ctxlogger)
+
+		result, err := trashAPI.TrashServiceFactory(ctx).Purge(time.Now().Add(-trashConfig.Retention))
+		if err != nil {
+			ctxlogger.Errorf("purge - unable to purge the trash due to %v", err)
+			continue
+		}
+
+		ctxlogger.Infof("purge - purged %v categories, %v invitations and %v rsvps", result.Categories, result.Invitations, result.RSVPs)
+	}
+}
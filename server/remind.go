@@ -0,0 +1,38 @@
+package main
+
+import (
+	"time"
+
+	"github.com/rawfish-dev/rsvp-starter/server/api"
+	"github.com/rawfish-dev/rsvp-starter/server/config"
+
+	"github.com/Sirupsen/logrus"
+	"golang.org/x/net/context"
+)
+
+// runReminderJob reminds guests who have not replied to their invitation, checking for reminders that
+// are due once per reminder interval for as long as the server runs.
+func runReminderJob(reminderAPI *api.API, reminderConfig config.ReminderConfig) {
+	if reminderConfig.Interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(reminderConfig.Interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		ctxlogger := logrus.New()
+		ctx := context.Background()
+		ctx = context.WithValue(ctx, "logger", ctxlogger)
+
+		result, err := reminderAPI.InvitationServiceFactory(ctx).SendDueReminders(time.Now())
+		if err != nil {
+			ctxlogger.Errorf("remind - unable to send reminders due to %v", err)
+			continue
+		}
+
+		if result.Sent > 0 || result.Failed > 0 {
+			ctxlogger.Infof("remind - sent %v reminders and failed to send %v", result.Sent, result.Failed)
+		}
+	}
+}
@@ -5,10 +5,12 @@ POSTGRES_DB_NAME="wedding_rsvp_test"
 TESTCONTEXT=${1:--r}
 printf "TESTING CONTEXT ${TESTCONTEXT}\n"
 
+export POSTGRES_URL="postgres://${POSTGRES_USER}@${POSTGRES_ADDR}/${POSTGRES_DB_NAME}?sslmode=disable"
+export POSTGRES_TEST_URL="${POSTGRES_URL}"
+export HMAC_SECRET="really_secret"
+export TOKEN_ISSUER="rsvp_starter_test"
+
 psql -h $POSTGRES_ADDR -U $POSTGRES_USER -c "DROP DATABASE IF EXISTS ${POSTGRES_DB_NAME};" && \
 psql -h $POSTGRES_ADDR -U $POSTGRES_USER -c "CREATE DATABASE ${POSTGRES_DB_NAME};" && \
-goose -env test up && \
-POSTGRES_URL="postgres://${POSTGRES_USER}@${POSTGRES_ADDR}/${POSTGRES_DB_NAME}?sslmode=disable" \
-HMAC_SECRET="really_secret" \
-TOKEN_ISSUER="rsvp_starter_test" \
+go run *.go migrate up && \
 ginkgo ${TESTCONTEXT}
@@ -0,0 +1,219 @@
+package apikey
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rawfish-dev/rsvp-starter/server/domain"
+	"github.com/rawfish-dev/rsvp-starter/server/interfaces"
+	"github.com/rawfish-dev/rsvp-starter/server/services/audit"
+	serviceErrors "github.com/rawfish-dev/rsvp-starter/server/services/errors"
+	"github.com/rawfish-dev/rsvp-starter/server/services/storage"
+
+	"golang.org/x/net/context"
+)
+
+const (
+	NameMinLength = 1
+	NameMaxLength = 50
+
+	// Keys look like rsvp_ followed by the random part in hex, the start of which is kept as the prefix
+	keyPrefix       = "rsvp_"
+	keyRandomLength = 24
+	keyPrefixLength = 8
+)
+
+var _ interfaces.APIKeyServiceProvider = new(service)
+
+type service struct {
+	ctx           context.Context
+	apiKeyStorage interfaces.Storage
+}
+
+func NewService(ctx context.Context, apiKeyStorage interfaces.Storage) *service {
+	return &service{ctx, apiKeyStorage}
+}
+
+// CreateAPIKey returns the key itself only this once, after which just its hash is kept
+func (s *service) CreateAPIKey(req *domain.APIKeyCreateRequest) (*domain.NewAPIKey, error) {
+	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
+
+	req.Name = strings.TrimSpace(req.Name)
+
+	errorMessages := validateAPIKeyCreateRequest(req)
+	if len(errorMessages) > 0 {
+		return nil, serviceErrors.NewValidationError(errorMessages)
+	}
+
+	keyBytes := make([]byte, keyRandomLength)
+
+	_, err := rand.Read(keyBytes)
+	if err != nil {
+		ctxLogger.Errorf("api key service - unable to generate api key due to %v", err)
+		return nil, serviceErrors.NewGeneralServiceError()
+	}
+
+	randomPart := hex.EncodeToString(keyBytes)
+	key := keyPrefix + randomPart
+
+	var newAPIKey *domain.APIKey
+
+	err = s.apiKeyStorage.WithTx(func(tx interfaces.Storage) error {
+		var err error
+
+		newAPIKey, err = tx.InsertAPIKey(&domain.APIKey{
+			Name:      req.Name,
+			Prefix:    randomPart[:keyPrefixLength],
+			KeyHash:   HashKey(key),
+			Scopes:    uniqueScopes(req.Scopes),
+			CreatedBy: audit.ActorFromContext(s.ctx).Username,
+			ExpiresAt: req.ExpiresAt,
+		})
+		if err != nil {
+			return serviceErrors.NewGeneralServiceError()
+		}
+
+		return audit.Record(s.ctx, tx, domain.AuditCreated, domain.APIKeyAuditEntity, newAPIKey.ID, nil, newAPIKey)
+	})
+	if err != nil {
+		return nil, serviceErrors.FromTransaction(err)
+	}
+
+	return &domain.NewAPIKey{APIKey: *newAPIKey, Key: key}, nil
+}
+
+func (s *service) ListAPIKeys() ([]domain.APIKey, error) {
+	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
+
+	apiKeys, err := s.apiKeyStorage.ListAPIKeys()
+	if err != nil {
+		ctxLogger.Error("api key service - unable to list api keys")
+		return nil, serviceErrors.NewGeneralServiceError()
+	}
+
+	return apiKeys, nil
+}
+
+func (s *service) RevokeAPIKeyByID(apiKeyID int64) (*domain.APIKey, error) {
+	var revokedAPIKey *domain.APIKey
+
+	err := s.apiKeyStorage.WithTx(func(tx interfaces.Storage) error {
+		apiKey, err := tx.FindAPIKeyByID(apiKeyID)
+		if err != nil {
+			switch err.(type) {
+			case storage.StorageRecordNotFoundError:
+				return NewAPIKeyNotFoundError()
+			}
+
+			return serviceErrors.NewGeneralServiceError()
+		}
+
+		if apiKey.RevokedAt != "" {
+			revokedAPIKey = apiKey
+			return nil
+		}
+
+		revokedAPIKey, err = tx.RevokeAPIKey(apiKeyID)
+		if err != nil {
+			return serviceErrors.NewGeneralServiceError()
+		}
+
+		return audit.Record(s.ctx, tx, domain.AuditRevoked, domain.APIKeyAuditEntity, apiKeyID, apiKey, revokedAPIKey)
+	})
+	if err != nil {
+		return nil, serviceErrors.FromTransaction(err)
+	}
+
+	return revokedAPIKey, nil
+}
+
+// Authenticate returns the api key for the key as long as it has neither expired nor been revoked and
+// notes that it was used
+func (s *service) Authenticate(key string) (*domain.APIKey, error) {
+	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
+
+	apiKey, err := s.apiKeyStorage.FindAPIKeyByHash(HashKey(key))
+	if err != nil {
+		switch err.(type) {
+		case storage.StorageRecordNotFoundError:
+			return nil, NewAPIKeyInvalidError()
+		}
+
+		return nil, serviceErrors.NewGeneralServiceError()
+	}
+
+	if apiKey.RevokedAt != "" {
+		ctxLogger.Warnf("api key service - rejected revoked api key %v", apiKey.Prefix)
+		return nil, NewAPIKeyInvalidError()
+	}
+
+	now := time.Now().UTC()
+
+	if apiKey.ExpiresAt != "" {
+		expiresAt, err := time.Parse(time.RFC3339, apiKey.ExpiresAt)
+		if err != nil || !now.Before(expiresAt) {
+			ctxLogger.Warnf("api key service - rejected expired api key %v", apiKey.Prefix)
+			return nil, NewAPIKeyInvalidError()
+		}
+	}
+
+	err = s.apiKeyStorage.UpdateAPIKeyLastUsed(apiKey.ID, now)
+	if err != nil {
+		ctxLogger.Errorf("api key service - unable to note that api key %v was used due to %v", apiKey.Prefix, err)
+		return nil, serviceErrors.NewGeneralServiceError()
+	}
+	apiKey.LastUsedAt = now.Format(time.RFC3339)
+
+	return apiKey, nil
+}
+
+// HashKey needs no salt or stretching as keys are long and random, unlike passwords
+func HashKey(key string) string {
+	hash := sha256.Sum256([]byte(key))
+
+	return hex.EncodeToString(hash[:])
+}
+
+// validateAPIKeyCreateRequest also turns the expiry into UTC
+func validateAPIKeyCreateRequest(req *domain.APIKeyCreateRequest) (errorMessages []string) {
+	if len(req.Name) < NameMinLength || len(req.Name) > NameMaxLength {
+		errorMessages = append(errorMessages, fmt.Sprintf("name must be between %v and %v characters", NameMinLength, NameMaxLength))
+	}
+
+	if len(req.Scopes) == 0 {
+		errorMessages = append(errorMessages, "at least one scope is required")
+	}
+	for _, scope := range req.Scopes {
+		if !domain.IsValidScope(scope) {
+			errorMessages = append(errorMessages, fmt.Sprintf("scope %v must be one of %v", scope, domain.Scopes))
+		}
+	}
+
+	if req.ExpiresAt != "" {
+		expiresAt, err := time.Parse(time.RFC3339, req.ExpiresAt)
+		switch {
+		case err != nil:
+			errorMessages = append(errorMessages, "expiry must be a timestamp such as 2006-01-02T15:04:05Z")
+		case !expiresAt.After(time.Now()):
+			errorMessages = append(errorMessages, "expiry must be in the future")
+		default:
+			req.ExpiresAt = expiresAt.UTC().Format(time.RFC3339)
+		}
+	}
+
+	return errorMessages
+}
+
+func uniqueScopes(scopes []domain.Scope) (unique []domain.Scope) {
+	for _, scope := range scopes {
+		if !domain.HasScope(unique, scope) {
+			unique = append(unique, scope)
+		}
+	}
+
+	return unique
+}
@@ -0,0 +1,166 @@
+package apikey_test
+
+import (
+	"time"
+
+	"github.com/rawfish-dev/rsvp-starter/server/domain"
+	"github.com/rawfish-dev/rsvp-starter/server/interfaces"
+	"github.com/rawfish-dev/rsvp-starter/server/mock"
+	. "github.com/rawfish-dev/rsvp-starter/server/services/apikey"
+	serviceErrors "github.com/rawfish-dev/rsvp-starter/server/services/errors"
+	"github.com/rawfish-dev/rsvp-starter/server/services/storage"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"golang.org/x/net/context"
+)
+
+var _ = Describe("APIKey", func() {
+
+	var ctrl *gomock.Controller
+	var mockAPIKeyStorage *mock_interfaces.MockTransactionalStorage
+	var testAPIKeyService interfaces.APIKeyServiceProvider
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+
+		ctxlogger := logrus.New()
+		ctx := context.Background()
+		ctx = context.WithValue(ctx, "logger", ctxlogger)
+		ctx = context.WithValue(ctx, domain.ContextActor, domain.NewAdminActor("admin"))
+
+		mockAPIKeyStorage = mock_interfaces.NewMockTransactionalStorage(ctrl)
+		testAPIKeyService = NewService(ctx, mockAPIKeyStorage)
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	Context("creation", func() {
+
+		It("should keep only the hash of the key and return the key once", func() {
+			var insertedAPIKey *domain.APIKey
+
+			gomock.InOrder(
+				mockAPIKeyStorage.EXPECT().InsertAPIKey(gomock.Any()).
+					Do(func(apiKey *domain.APIKey) {
+						insertedAPIKey = apiKey
+					}).
+					Return(&domain.APIKey{ID: 1, Name: "spreadsheet"}, nil),
+				mockAPIKeyStorage.EXPECT().InsertAuditEntry(gomock.Any()).Return(&domain.AuditEntry{}, nil),
+			)
+
+			newAPIKey, err := testAPIKeyService.CreateAPIKey(&domain.APIKeyCreateRequest{
+				Name:      " spreadsheet ",
+				Scopes:    []domain.Scope{domain.ScopeInvitationsRead, domain.ScopeRSVPsWrite, domain.ScopeInvitationsRead},
+				ExpiresAt: "2100-01-02T11:04:05+08:00",
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(newAPIKey.ID).To(Equal(int64(1)))
+			Expect(newAPIKey.Key).To(HavePrefix("rsvp_"))
+
+			Expect(insertedAPIKey.Name).To(Equal("spreadsheet"))
+			Expect(insertedAPIKey.KeyHash).To(Equal(HashKey(newAPIKey.Key)))
+			Expect(newAPIKey.Key).To(HavePrefix("rsvp_" + insertedAPIKey.Prefix))
+			Expect(insertedAPIKey.Scopes).To(Equal([]domain.Scope{domain.ScopeInvitationsRead, domain.ScopeRSVPsWrite}))
+			Expect(insertedAPIKey.CreatedBy).To(Equal("admin"))
+			Expect(insertedAPIKey.ExpiresAt).To(Equal("2100-01-02T03:04:05Z"))
+		})
+
+		It("should not allow missing names, unknown scopes or past expiries", func() {
+			_, err := testAPIKeyService.CreateAPIKey(&domain.APIKeyCreateRequest{
+				Name:      " ",
+				Scopes:    []domain.Scope{"users:write"},
+				ExpiresAt: "2000-01-01T00:00:00Z",
+			})
+			Expect(err).To(BeAssignableToTypeOf(serviceErrors.ValidationError{}))
+			Expect(err.Error()).To(ContainSubstring("name must be between 1 and 50 characters"))
+			Expect(err.Error()).To(ContainSubstring("scope users:write must be one of"))
+			Expect(err.Error()).To(ContainSubstring("expiry must be in the future"))
+		})
+
+		It("should require at least one scope", func() {
+			_, err := testAPIKeyService.CreateAPIKey(&domain.APIKeyCreateRequest{Name: "spreadsheet"})
+			Expect(err).To(BeAssignableToTypeOf(serviceErrors.ValidationError{}))
+			Expect(err.Error()).To(Equal("at least one scope is required"))
+		})
+	})
+
+	Context("revocation", func() {
+
+		It("should revoke the api key and audit it", func() {
+			gomock.InOrder(
+				mockAPIKeyStorage.EXPECT().FindAPIKeyByID(int64(1)).Return(&domain.APIKey{ID: 1}, nil),
+				mockAPIKeyStorage.EXPECT().RevokeAPIKey(int64(1)).Return(&domain.APIKey{ID: 1, RevokedAt: "2026-10-17T12:00:00Z"}, nil),
+				mockAPIKeyStorage.EXPECT().InsertAuditEntry(gomock.Any()).
+					Do(func(req *domain.AuditEntryCreateRequest) {
+						Expect(req.Action).To(Equal(domain.AuditRevoked))
+						Expect(req.EntityType).To(Equal(domain.APIKeyAuditEntity))
+					}).
+					Return(&domain.AuditEntry{}, nil),
+			)
+
+			revokedAPIKey, err := testAPIKeyService.RevokeAPIKeyByID(1)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(revokedAPIKey.RevokedAt).ToNot(BeEmpty())
+		})
+
+		It("should leave api keys that are already revoked alone", func() {
+			mockAPIKeyStorage.EXPECT().FindAPIKeyByID(int64(1)).Return(&domain.APIKey{ID: 1, RevokedAt: "2026-10-17T12:00:00Z"}, nil)
+
+			_, err := testAPIKeyService.RevokeAPIKeyByID(1)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should return a not found error for unknown api keys", func() {
+			mockAPIKeyStorage.EXPECT().FindAPIKeyByID(int64(1)).Return(nil, storage.NewStorageRecordNotFoundError())
+
+			_, err := testAPIKeyService.RevokeAPIKeyByID(1)
+			Expect(err).To(BeAssignableToTypeOf(NewAPIKeyNotFoundError()))
+		})
+	})
+
+	Context("authentication", func() {
+
+		It("should return the api key and note that it was used", func() {
+			gomock.InOrder(
+				mockAPIKeyStorage.EXPECT().FindAPIKeyByHash(HashKey("rsvp_some-key")).Return(&domain.APIKey{ID: 1}, nil),
+				mockAPIKeyStorage.EXPECT().UpdateAPIKeyLastUsed(int64(1), gomock.Any()).
+					Do(func(apiKeyID int64, lastUsedAt time.Time) {
+						Expect(lastUsedAt).To(BeTemporally("~", time.Now(), time.Second))
+					}).
+					Return(nil),
+			)
+
+			apiKey, err := testAPIKeyService.Authenticate("rsvp_some-key")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(apiKey.ID).To(Equal(int64(1)))
+			Expect(apiKey.LastUsedAt).ToNot(BeEmpty())
+		})
+
+		It("should reject unknown api keys", func() {
+			mockAPIKeyStorage.EXPECT().FindAPIKeyByHash(gomock.Any()).Return(nil, storage.NewStorageRecordNotFoundError())
+
+			_, err := testAPIKeyService.Authenticate("rsvp_some-key")
+			Expect(err).To(BeAssignableToTypeOf(NewAPIKeyInvalidError()))
+		})
+
+		It("should reject revoked api keys", func() {
+			mockAPIKeyStorage.EXPECT().FindAPIKeyByHash(gomock.Any()).Return(&domain.APIKey{ID: 1, RevokedAt: "2026-10-17T12:00:00Z"}, nil)
+
+			_, err := testAPIKeyService.Authenticate("rsvp_some-key")
+			Expect(err).To(BeAssignableToTypeOf(NewAPIKeyInvalidError()))
+		})
+
+		It("should reject expired api keys", func() {
+			expiresAt := time.Now().Add(-time.Minute).UTC().Format(time.RFC3339)
+			mockAPIKeyStorage.EXPECT().FindAPIKeyByHash(gomock.Any()).Return(&domain.APIKey{ID: 1, ExpiresAt: expiresAt}, nil)
+
+			_, err := testAPIKeyService.Authenticate("rsvp_some-key")
+			Expect(err).To(BeAssignableToTypeOf(NewAPIKeyInvalidError()))
+		})
+	})
+})
@@ -0,0 +1,28 @@
+package apikey
+
+var _ error = new(APIKeyNotFoundError)
+var _ error = new(APIKeyInvalidError)
+
+type APIKeyNotFoundError struct {
+}
+
+func NewAPIKeyNotFoundError() error {
+	return APIKeyNotFoundError{}
+}
+
+func (a APIKeyNotFoundError) Error() string {
+	return "api key not found"
+}
+
+// APIKeyInvalidError is returned for keys that are unknown, expired or revoked alike so callers cannot
+// tell which
+type APIKeyInvalidError struct {
+}
+
+func NewAPIKeyInvalidError() error {
+	return APIKeyInvalidError{}
+}
+
+func (a APIKeyInvalidError) Error() string {
+	return "api key is invalid"
+}
@@ -0,0 +1,102 @@
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/rawfish-dev/rsvp-starter/server/domain"
+	"github.com/rawfish-dev/rsvp-starter/server/interfaces"
+	serviceErrors "github.com/rawfish-dev/rsvp-starter/server/services/errors"
+
+	"golang.org/x/net/context"
+)
+
+var _ interfaces.AuditServiceProvider = new(service)
+
+type service struct {
+	ctx          context.Context
+	auditStorage interfaces.Storage
+}
+
+func NewService(ctx context.Context, auditStorage interfaces.Storage) *service {
+	return &service{ctx, auditStorage}
+}
+
+func (s *service) ListAuditEntries(req *domain.AuditListRequest) (*domain.AuditList, error) {
+	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
+
+	req.ApplyDefaults("-" + domain.SortByAuditCreatedAt)
+
+	errorMessages := req.Validate(domain.SortByAuditCreatedAt)
+	if req.EntityType != "" && !domain.IsValidAuditEntityType(req.EntityType) {
+		errorMessages = append(errorMessages, fmt.Sprintf("entity type %v is invalid", req.EntityType))
+	}
+	if req.EntityID != 0 && req.EntityType == "" {
+		errorMessages = append(errorMessages, "entity type is required to filter by entity id")
+	}
+	if len(errorMessages) > 0 {
+		return nil, serviceErrors.NewValidationError(errorMessages)
+	}
+
+	entries, total, err := s.auditStorage.ListAuditEntries(req)
+	if err != nil {
+		ctxLogger.Errorf("audit service - unable to list audit entries due to %v", err)
+		return nil, serviceErrors.NewGeneralServiceError()
+	}
+
+	auditList := &domain.AuditList{
+		ListResponse: domain.ListResponse{
+			Page:     req.Page,
+			PageSize: req.PageSize,
+			Total:    total,
+		},
+		Entries: entries,
+	}
+
+	return auditList, nil
+}
+
+// ActorFromContext returns the actor the api put in the context, or the system actor for changes
+// made outside of a request.
+func ActorFromContext(ctx context.Context) domain.Actor {
+	actor, ok := ctx.Value(domain.ContextActor).(domain.Actor)
+	if !ok || actor.Username == "" {
+		return domain.Actor{Username: domain.SystemActor}
+	}
+
+	return actor
+}
+
+// Record writes an audit entry for a change made by the actor in ctx. It is meant to be called with
+// the storage of the transaction making the change so the entry is only kept if the change is.
+// before and after are snapshots of the entity and are nil on the side where it did not exist.
+func Record(ctx context.Context, tx interfaces.Storage, action domain.AuditAction, entityType domain.AuditEntityType, entityID int64, before, after interface{}) error {
+	ctxLogger := ctx.Value("logger").(interfaces.Logger)
+
+	beforeJSON, err := json.Marshal(before)
+	if err != nil {
+		ctxLogger.Errorf("audit service - unable to marshal %v %v before it was %v due to %v", entityType, entityID, action, err)
+		return serviceErrors.NewGeneralServiceError()
+	}
+
+	afterJSON, err := json.Marshal(after)
+	if err != nil {
+		ctxLogger.Errorf("audit service - unable to marshal %v %v after it was %v due to %v", entityType, entityID, action, err)
+		return serviceErrors.NewGeneralServiceError()
+	}
+
+	_, err = tx.InsertAuditEntry(&domain.AuditEntryCreateRequest{
+		Actor:      ActorFromContext(ctx),
+		Action:     action,
+		EntityType: entityType,
+		EntityID:   entityID,
+		Before:     beforeJSON,
+		After:      afterJSON,
+	})
+	if err != nil {
+		ctxLogger.Errorf("audit service - unable to record %v %v being %v due to %v", entityType, entityID, action, err)
+		return serviceErrors.NewGeneralServiceError()
+	}
+
+	return nil
+}
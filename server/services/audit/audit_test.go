@@ -0,0 +1,146 @@
+package audit_test
+
+import (
+	"github.com/rawfish-dev/rsvp-starter/server/domain"
+	"github.com/rawfish-dev/rsvp-starter/server/interfaces"
+	"github.com/rawfish-dev/rsvp-starter/server/mock"
+	. "github.com/rawfish-dev/rsvp-starter/server/services/audit"
+	serviceErrors "github.com/rawfish-dev/rsvp-starter/server/services/errors"
+	"github.com/rawfish-dev/rsvp-starter/server/services/storage"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"golang.org/x/net/context"
+)
+
+var _ = Describe("Audit", func() {
+
+	var ctrl *gomock.Controller
+	var ctx context.Context
+	var mockAuditStorage *mock_interfaces.MockTransactionalStorage
+	var testAuditService interfaces.AuditServiceProvider
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+
+		ctxlogger := logrus.New()
+		ctx = context.Background()
+		ctx = context.WithValue(ctx, "logger", ctxlogger)
+
+		mockAuditStorage = mock_interfaces.NewMockTransactionalStorage(ctrl)
+		testAuditService = NewService(ctx, mockAuditStorage)
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	Context("listing", func() {
+
+		It("should list the latest entries first by default", func() {
+			entries := []domain.AuditEntry{{ID: 2}, {ID: 1}}
+
+			mockAuditStorage.EXPECT().ListAuditEntries(gomock.Any()).
+				Do(func(req *domain.AuditListRequest) {
+					Expect(req.Sort).To(Equal("-createdAt"))
+					Expect(req.Page).To(Equal(1))
+				}).
+				Return(entries, 2, nil)
+
+			auditList, err := testAuditService.ListAuditEntries(&domain.AuditListRequest{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(auditList.Entries).To(Equal(entries))
+			Expect(auditList.Total).To(Equal(2))
+		})
+
+		It("should filter by entity", func() {
+			req := &domain.AuditListRequest{EntityType: domain.InvitationAuditEntity, EntityID: 3}
+
+			mockAuditStorage.EXPECT().ListAuditEntries(req).Return([]domain.AuditEntry{}, 0, nil)
+
+			_, err := testAuditService.ListAuditEntries(req)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should not allow an unknown entity type", func() {
+			mockAuditStorage.EXPECT().ListAuditEntries(gomock.Any()).Times(0)
+
+			_, err := testAuditService.ListAuditEntries(&domain.AuditListRequest{EntityType: "guest"})
+			Expect(err).To(BeAssignableToTypeOf(serviceErrors.ValidationError{}))
+			Expect(err.Error()).To(Equal("entity type guest is invalid"))
+		})
+
+		It("should not allow filtering by entity id without an entity type", func() {
+			mockAuditStorage.EXPECT().ListAuditEntries(gomock.Any()).Times(0)
+
+			_, err := testAuditService.ListAuditEntries(&domain.AuditListRequest{EntityID: 3})
+			Expect(err).To(BeAssignableToTypeOf(serviceErrors.ValidationError{}))
+			Expect(err.Error()).To(Equal("entity type is required to filter by entity id"))
+		})
+
+		It("should return a general error if the entries cannot be listed", func() {
+			mockAuditStorage.EXPECT().ListAuditEntries(gomock.Any()).Return(nil, 0, storage.NewStorageOperationError())
+
+			_, err := testAuditService.ListAuditEntries(&domain.AuditListRequest{})
+			Expect(err).To(BeAssignableToTypeOf(serviceErrors.GeneralServiceError{}))
+		})
+	})
+
+	Context("recording", func() {
+
+		It("should record the actor of the request with snapshots of the change", func() {
+			ctx = context.WithValue(ctx, domain.ContextActor, domain.NewAdminActor("admin"))
+			before := domain.Category{ID: 1, Tag: "family"}
+			after := domain.Category{ID: 1, Tag: "friends"}
+
+			mockAuditStorage.EXPECT().InsertAuditEntry(gomock.Any()).
+				Do(func(req *domain.AuditEntryCreateRequest) {
+					Expect(req.Actor).To(Equal(domain.NewAdminActor("admin")))
+					Expect(req.Action).To(Equal(domain.AuditUpdated))
+					Expect(req.EntityType).To(Equal(domain.CategoryAuditEntity))
+					Expect(req.EntityID).To(Equal(int64(1)))
+					Expect(req.Before).To(MatchJSON(`{"id":1,"tag":"family","total":0}`))
+					Expect(req.After).To(MatchJSON(`{"id":1,"tag":"friends","total":0}`))
+				}).
+				Return(&domain.AuditEntry{}, nil)
+
+			err := Record(ctx, mockAuditStorage, domain.AuditUpdated, domain.CategoryAuditEntity, 1, before, after)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should record a guest by the private id of their invitation", func() {
+			ctx = context.WithValue(ctx, domain.ContextActor, domain.NewGuestActor("some-private-id"))
+
+			mockAuditStorage.EXPECT().InsertAuditEntry(gomock.Any()).
+				Do(func(req *domain.AuditEntryCreateRequest) {
+					Expect(req.Actor.Username).To(Equal(domain.GuestActor))
+					Expect(req.Actor.InvitationPrivateID).To(Equal("some-private-id"))
+					Expect(req.Before).To(MatchJSON("null"))
+				}).
+				Return(&domain.AuditEntry{}, nil)
+
+			err := Record(ctx, mockAuditStorage, domain.AuditCreated, domain.RSVPAuditEntity, 1, nil, domain.RSVP{ID: 1})
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should fall back to the system actor outside of a request", func() {
+			mockAuditStorage.EXPECT().InsertAuditEntry(gomock.Any()).
+				Do(func(req *domain.AuditEntryCreateRequest) {
+					Expect(req.Actor.Username).To(Equal(domain.SystemActor))
+				}).
+				Return(&domain.AuditEntry{}, nil)
+
+			err := Record(ctx, mockAuditStorage, domain.AuditDeleted, domain.RSVPAuditEntity, 1, domain.RSVP{ID: 1}, nil)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should return a general error if the entry cannot be stored", func() {
+			mockAuditStorage.EXPECT().InsertAuditEntry(gomock.Any()).Return(nil, storage.NewStorageOperationError())
+
+			err := Record(ctx, mockAuditStorage, domain.AuditDeleted, domain.RSVPAuditEntity, 1, domain.RSVP{ID: 1}, nil)
+			Expect(err).To(BeAssignableToTypeOf(serviceErrors.GeneralServiceError{}))
+		})
+	})
+})
@@ -2,6 +2,8 @@ package cache
 
 import (
 	"fmt"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 
@@ -39,6 +41,9 @@ func NewService(ctx context.Context) *service {
 }
 
 func (s *service) Get(key string) (value string, err error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
 	wrappedValue, ok := s.storage[key]
 	if !ok {
 		return "", nil
@@ -99,10 +104,29 @@ func (s *service) Delete(key string) (err error) {
 }
 
 func (s *service) Exists(key string) (exists bool, err error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
 	_, ok := s.storage[key]
 	return ok, nil
 }
 
+// Keys returns the sorted keys starting with the given prefix
+func (s *service) Keys(prefix string) (keys []string, err error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	keys = []string{}
+	for key := range s.storage {
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	return keys, nil
+}
+
 func (s *service) Flush() (err error) {
 	s.mutex.Lock()
 	defer s.mutex.Unlock()
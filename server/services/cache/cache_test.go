@@ -1,6 +1,8 @@
 package cache_test
 
 import (
+	"fmt"
+	"sync"
 	"time"
 
 	"github.com/rawfish-dev/rsvp-starter/server/interfaces"
@@ -81,6 +83,48 @@ var _ = Describe("Cache", func() {
 		})
 	})
 
+	Context("keys", func() {
+		It("should list keys with the given prefix in order", func() {
+			Expect(testCacheService.SetWithExpiry("session:kevin:2", "some_value", 5)).To(Succeed())
+			Expect(testCacheService.SetWithExpiry("session:kevin:1", "some_value", 5)).To(Succeed())
+			Expect(testCacheService.SetWithExpiry("session:jenny:1", "some_value", 5)).To(Succeed())
+
+			keys, err := testCacheService.Keys("session:kevin:")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(keys).To(Equal([]string{"session:kevin:1", "session:kevin:2"}))
+		})
+
+		It("should return no keys when nothing matches", func() {
+			keys, err := testCacheService.Keys("session:")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(keys).To(BeEmpty())
+		})
+	})
+
+	Context("concurrency", func() {
+		It("should allow keys to be read and written from many goroutines at once", func() {
+			var wg sync.WaitGroup
+			for idx := 0; idx < 20; idx++ {
+				wg.Add(1)
+				go func(idx int) {
+					defer GinkgoRecover()
+					defer wg.Done()
+
+					key := fmt.Sprintf("session:kevin:%v", idx%4)
+					Expect(testCacheService.SetWithExpiry(key, "some_value", 60)).To(Succeed())
+					_, err := testCacheService.Get(key)
+					Expect(err).ToNot(HaveOccurred())
+					_, err = testCacheService.Exists(key)
+					Expect(err).ToNot(HaveOccurred())
+					_, err = testCacheService.Keys("session:kevin:")
+					Expect(err).ToNot(HaveOccurred())
+					Expect(testCacheService.Delete(key)).To(Succeed())
+				}(idx)
+			}
+			wg.Wait()
+		})
+	})
+
 	Context("expiry", func() {
 		It("should expire keys according to their expiry times", func() {
 			err := testCacheService.SetWithExpiry("some_key", "some_value", 1)
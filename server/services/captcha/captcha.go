@@ -0,0 +1,112 @@
+package captcha
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+
+	"github.com/rawfish-dev/rsvp-starter/server/config"
+	"github.com/rawfish-dev/rsvp-starter/server/interfaces"
+
+	"golang.org/x/net/context"
+)
+
+var _ interfaces.CaptchaVerifier = new(service)
+
+// service verifies tokens against the siteverify endpoint of reCAPTCHA, hCaptcha or Turnstile, which
+// all take the same form fields and answer in the same shape
+type service struct {
+	ctx           context.Context
+	captchaConfig config.CaptchaConfig
+	client        *http.Client
+}
+
+// NewVerifier returns the verifier for the configured provider
+func NewVerifier(ctx context.Context, captchaConfig config.CaptchaConfig) interfaces.CaptchaVerifier {
+	switch captchaConfig.Provider {
+	case config.AlwaysPassProvider:
+		return NewStub(true)
+	case config.AlwaysFailProvider:
+		return NewStub(false)
+	}
+
+	return NewService(ctx, captchaConfig)
+}
+
+func NewService(ctx context.Context, captchaConfig config.CaptchaConfig) *service {
+	return &service{
+		ctx:           ctx,
+		captchaConfig: captchaConfig,
+		client:        &http.Client{Timeout: captchaConfig.Timeout},
+	}
+}
+
+type siteVerifyResponse struct {
+	Success    bool     `json:"success"`
+	ErrorCodes []string `json:"error-codes"`
+
+	// Only reCAPTCHA v3 scores tokens, from 0.0 for a likely bot to 1.0 for a likely person
+	Score *float64 `json:"score"`
+}
+
+// Verify fails closed, so tokens are rejected whenever the provider cannot be reached in time
+func (s *service) Verify(token, remoteIP string) (valid bool) {
+	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
+
+	if s.captchaConfig.Secret == "" {
+		ctxLogger.Errorf("captcha service - unable to verify %v token as no secret is configured", s.captchaConfig.Provider)
+		return false
+	}
+
+	form := url.Values{}
+	form.Set("secret", s.captchaConfig.Secret)
+	form.Set("response", token)
+	if remoteIP != "" {
+		form.Set("remoteip", remoteIP)
+	}
+
+	resp, err := s.client.PostForm(s.captchaConfig.VerifyURL, form)
+	if err != nil {
+		ctxLogger.Errorf("captcha service - unable to complete %v verify due to %v", s.captchaConfig.Provider, err)
+		return false
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		ctxLogger.Errorf("captcha service - %v verify responded with status %v", s.captchaConfig.Provider, resp.StatusCode)
+		return false
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		ctxLogger.Errorf("captcha service - unable to read %v response body due to %v", s.captchaConfig.Provider, err)
+		return false
+	}
+
+	var verifyResp siteVerifyResponse
+
+	err = json.Unmarshal(body, &verifyResp)
+	if err != nil {
+		ctxLogger.Errorf("captcha service - unable to unwrap %v response body due to %v", s.captchaConfig.Provider, err)
+		return false
+	}
+
+	if len(verifyResp.ErrorCodes) > 0 {
+		ctxLogger.Warnf("captcha service - validation of %v token failed due to %v", s.captchaConfig.Provider, verifyResp.ErrorCodes)
+		return false
+	}
+
+	if !verifyResp.Success {
+		return false
+	}
+
+	if s.captchaConfig.Provider == config.ReCAPTCHAV3Provider {
+		if verifyResp.Score == nil || *verifyResp.Score < s.captchaConfig.ScoreThreshold {
+			ctxLogger.Warnf("captcha service - reCAPTCHA v3 token scored below the threshold of %v", s.captchaConfig.ScoreThreshold)
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,140 @@
+package captcha_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"time"
+
+	"github.com/rawfish-dev/rsvp-starter/server/config"
+	. "github.com/rawfish-dev/rsvp-starter/server/services/captcha"
+
+	"github.com/Sirupsen/logrus"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"golang.org/x/net/context"
+)
+
+var _ = Describe("Captcha", func() {
+
+	var ctx context.Context
+	var fakeServer *httptest.Server
+	var receivedForm url.Values
+	var responseStatus int
+	var responseBody string
+	var responseDelay time.Duration
+	var captchaConfig config.CaptchaConfig
+
+	BeforeEach(func() {
+		ctxlogger := logrus.New()
+		ctx = context.WithValue(context.Background(), "logger", ctxlogger)
+
+		receivedForm = nil
+		responseStatus = http.StatusOK
+		responseBody = `{"success": true}`
+		responseDelay = 0
+
+		fakeServer = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			r.ParseForm()
+			receivedForm = r.PostForm
+
+			time.Sleep(responseDelay)
+
+			w.WriteHeader(responseStatus)
+			fmt.Fprint(w, responseBody)
+		}))
+
+		captchaConfig = config.CaptchaConfig{
+			Provider:       config.HCaptchaProvider,
+			Secret:         "some-secret",
+			VerifyURL:      fakeServer.URL,
+			Timeout:        time.Second,
+			ScoreThreshold: 0.5,
+		}
+	})
+
+	AfterEach(func() {
+		fakeServer.Close()
+	})
+
+	It("should send the secret, token and client ip to the provider", func() {
+		Expect(NewService(ctx, captchaConfig).Verify("some-token", "127.0.0.1")).To(BeTrue())
+
+		Expect(receivedForm.Get("secret")).To(Equal("some-secret"))
+		Expect(receivedForm.Get("response")).To(Equal("some-token"))
+		Expect(receivedForm.Get("remoteip")).To(Equal("127.0.0.1"))
+	})
+
+	It("should reject tokens the provider does not accept", func() {
+		responseBody = `{"success": false}`
+
+		Expect(NewService(ctx, captchaConfig).Verify("some-token", "127.0.0.1")).To(BeFalse())
+	})
+
+	It("should reject tokens when the provider returns error codes", func() {
+		responseBody = `{"success": true, "error-codes": ["timeout-or-duplicate"]}`
+
+		Expect(NewService(ctx, captchaConfig).Verify("some-token", "127.0.0.1")).To(BeFalse())
+	})
+
+	It("should reject tokens when the provider responds with an error status", func() {
+		responseStatus = http.StatusInternalServerError
+
+		Expect(NewService(ctx, captchaConfig).Verify("some-token", "127.0.0.1")).To(BeFalse())
+	})
+
+	It("should reject tokens when the provider does not respond in time", func() {
+		captchaConfig.Timeout = 10 * time.Millisecond
+		responseDelay = 100 * time.Millisecond
+
+		Expect(NewService(ctx, captchaConfig).Verify("some-token", "127.0.0.1")).To(BeFalse())
+	})
+
+	It("should reject tokens without calling the provider when no secret is configured", func() {
+		captchaConfig.Secret = ""
+
+		Expect(NewService(ctx, captchaConfig).Verify("some-token", "127.0.0.1")).To(BeFalse())
+		Expect(receivedForm).To(BeNil())
+	})
+
+	Context("reCAPTCHA v3", func() {
+
+		BeforeEach(func() {
+			captchaConfig.Provider = config.ReCAPTCHAV3Provider
+		})
+
+		It("should accept tokens scoring at least the threshold", func() {
+			responseBody = `{"success": true, "score": 0.5}`
+
+			Expect(NewService(ctx, captchaConfig).Verify("some-token", "127.0.0.1")).To(BeTrue())
+		})
+
+		It("should reject tokens scoring below the threshold", func() {
+			responseBody = `{"success": true, "score": 0.4}`
+
+			Expect(NewService(ctx, captchaConfig).Verify("some-token", "127.0.0.1")).To(BeFalse())
+		})
+
+		It("should reject tokens without a score", func() {
+			Expect(NewService(ctx, captchaConfig).Verify("some-token", "127.0.0.1")).To(BeFalse())
+		})
+	})
+
+	Context("stubs", func() {
+
+		It("should pass every token without calling the provider", func() {
+			captchaConfig.Provider = config.AlwaysPassProvider
+
+			Expect(NewVerifier(ctx, captchaConfig).Verify("some-token", "127.0.0.1")).To(BeTrue())
+			Expect(receivedForm).To(BeNil())
+		})
+
+		It("should fail every token without calling the provider", func() {
+			captchaConfig.Provider = config.AlwaysFailProvider
+
+			Expect(NewVerifier(ctx, captchaConfig).Verify("some-token", "127.0.0.1")).To(BeFalse())
+			Expect(receivedForm).To(BeNil())
+		})
+	})
+})
@@ -5,8 +5,9 @@ import (
 
 	"github.com/rawfish-dev/rsvp-starter/server/domain"
 	"github.com/rawfish-dev/rsvp-starter/server/interfaces"
+	"github.com/rawfish-dev/rsvp-starter/server/services/audit"
 	serviceErrors "github.com/rawfish-dev/rsvp-starter/server/services/errors"
-	"github.com/rawfish-dev/rsvp-starter/server/services/postgres"
+	"github.com/rawfish-dev/rsvp-starter/server/services/storage"
 	"github.com/rawfish-dev/rsvp-starter/server/utils"
 
 	"golang.org/x/net/context"
@@ -21,10 +22,10 @@ var _ interfaces.CategoryServiceProvider = new(service)
 
 type service struct {
 	ctx             context.Context
-	categoryStorage interfaces.CategoryStorage
+	categoryStorage interfaces.Storage
 }
 
-func NewService(ctx context.Context, categoryStorage interfaces.CategoryStorage) *service {
+func NewService(ctx context.Context, categoryStorage interfaces.Storage) *service {
 	return &service{
 		ctx:             ctx,
 		categoryStorage: categoryStorage,
@@ -37,30 +38,57 @@ func (s *service) CreateCategory(req *domain.CategoryCreateRequest) (*domain.Cat
 		return nil, serviceErrors.NewValidationError(errorMessages)
 	}
 
-	newCategory, err := s.categoryStorage.InsertCategory(req)
-	if err != nil {
-		switch err.(type) {
-		case postgres.PostgresCategoryTagUniqueConstraintError:
-			errorMessage := []string{"category tag already exists"}
-			return nil, serviceErrors.NewValidationError(errorMessage)
+	var newCategory *domain.Category
+
+	err := s.categoryStorage.WithTx(func(tx interfaces.Storage) error {
+		var err error
+
+		newCategory, err = tx.InsertCategory(req)
+		if err != nil {
+			switch err.(type) {
+			case storage.StorageCategoryTagUniqueConstraintError:
+				errorMessage := []string{"category tag already exists"}
+				return serviceErrors.NewValidationError(errorMessage)
+			}
+
+			return serviceErrors.NewGeneralServiceError()
 		}
 
-		return nil, serviceErrors.NewGeneralServiceError()
+		return audit.Record(s.ctx, tx, domain.AuditCreated, domain.CategoryAuditEntity, newCategory.ID, nil, newCategory)
+	})
+	if err != nil {
+		return nil, serviceErrors.FromTransaction(err)
 	}
 
 	return newCategory, nil
 }
 
-func (s *service) ListCategories() ([]domain.Category, error) {
+func (s *service) ListCategories(req *domain.CategoryListRequest) (*domain.CategoryList, error) {
 	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
 
-	categories, err := s.categoryStorage.ListCategories()
+	req.ApplyDefaults("-" + domain.SortByCategoryTag)
+
+	errorMessages := req.Validate(domain.SortByCategoryTag, domain.SortByCategoryTotal)
+	if len(errorMessages) > 0 {
+		return nil, serviceErrors.NewValidationError(errorMessages)
+	}
+
+	categories, total, err := s.categoryStorage.ListCategories(req)
 	if err != nil {
-		ctxLogger.Error("category service - unable to list all categories")
+		ctxLogger.Error("category service - unable to list categories")
 		return nil, serviceErrors.NewGeneralServiceError()
 	}
 
-	return categories, nil
+	categoryList := &domain.CategoryList{
+		ListResponse: domain.ListResponse{
+			Page:     req.Page,
+			PageSize: req.PageSize,
+			Total:    total,
+		},
+		Categories: categories,
+	}
+
+	return categoryList, nil
 }
 
 func (s *service) UpdateCategory(req *domain.CategoryUpdateRequest) (*domain.Category, error) {
@@ -69,56 +97,109 @@ func (s *service) UpdateCategory(req *domain.CategoryUpdateRequest) (*domain.Cat
 		return nil, serviceErrors.NewValidationError(errorMessages)
 	}
 
-	category, err := s.categoryStorage.FindCategoryByID(req.ID)
-	if err != nil {
-		switch err.(type) {
-		case postgres.PostgresRecordNotFoundError:
-			return nil, NewCategoryNotFoundError()
+	var updatedCategory *domain.Category
+
+	err := s.categoryStorage.WithTx(func(tx interfaces.Storage) error {
+		category, err := tx.FindCategoryByID(req.ID)
+		if err != nil {
+			switch err.(type) {
+			case storage.StorageRecordNotFoundError:
+				return NewCategoryNotFoundError()
+			}
+
+			return serviceErrors.NewGeneralServiceError()
 		}
 
-		return nil, serviceErrors.NewGeneralServiceError()
-	}
+		before := *category
+		category.Tag = req.Tag
 
-	category.Tag = req.Tag
+		updatedCategory, err = tx.UpdateCategory(category)
+		if err != nil {
+			switch err.(type) {
+			case storage.StorageCategoryTagUniqueConstraintError:
+				errorMessage := []string{"category tag already exists"}
+				return serviceErrors.NewValidationError(errorMessage)
+			}
 
-	updatedCategory, err := s.categoryStorage.UpdateCategory(category)
-	if err != nil {
-		switch err.(type) {
-		case postgres.PostgresCategoryTagUniqueConstraintError:
-			errorMessage := []string{"category tag already exists"}
-			return nil, serviceErrors.NewValidationError(errorMessage)
+			return serviceErrors.NewGeneralServiceError()
 		}
 
-		return nil, serviceErrors.NewGeneralServiceError()
+		return audit.Record(s.ctx, tx, domain.AuditUpdated, domain.CategoryAuditEntity, category.ID, before, updatedCategory)
+	})
+	if err != nil {
+		return nil, serviceErrors.FromTransaction(err)
 	}
 
 	return updatedCategory, nil
 }
 
 func (s *service) DeleteCategoryByID(categoryID int64) error {
-	category, err := s.categoryStorage.FindCategoryByID(categoryID)
-	if err != nil {
-		switch err.(type) {
-		case postgres.PostgresRecordNotFoundError:
-			return NewCategoryNotFoundError()
+	err := s.categoryStorage.WithTx(func(tx interfaces.Storage) error {
+		category, err := tx.FindCategoryByID(categoryID)
+		if err != nil {
+			switch err.(type) {
+			case storage.StorageRecordNotFoundError:
+				return NewCategoryNotFoundError()
+			}
+
+			return serviceErrors.NewGeneralServiceError()
 		}
 
-		return serviceErrors.NewGeneralServiceError()
-	}
+		err = tx.DeleteCategory(category)
+		if err != nil {
+			switch err.(type) {
+			case storage.StorageRecordNotFoundError:
+				return NewCategoryNotFoundError()
+			}
 
-	err = s.categoryStorage.DeleteCategory(category)
-	if err != nil {
-		switch err.(type) {
-		case postgres.PostgresRecordNotFoundError:
-			return NewCategoryNotFoundError()
+			return serviceErrors.NewGeneralServiceError()
 		}
 
-		return serviceErrors.NewGeneralServiceError()
+		return audit.Record(s.ctx, tx, domain.AuditDeleted, domain.CategoryAuditEntity, category.ID, category, nil)
+	})
+	if err != nil {
+		return serviceErrors.FromTransaction(err)
 	}
 
 	return nil
 }
 
+func (s *service) RestoreCategoryByID(categoryID int64) (*domain.Category, error) {
+	var restoredCategory *domain.Category
+
+	err := s.categoryStorage.WithTx(func(tx interfaces.Storage) error {
+		category, err := tx.FindDeletedCategoryByID(categoryID)
+		if err != nil {
+			switch err.(type) {
+			case storage.StorageRecordNotFoundError:
+				return NewCategoryNotFoundError()
+			}
+
+			return serviceErrors.NewGeneralServiceError()
+		}
+
+		restoredCategory, err = tx.RestoreCategory(category)
+		if err != nil {
+			switch err.(type) {
+			case storage.StorageCategoryTagUniqueConstraintError:
+				errorMessage := []string{"category tag already exists"}
+				return serviceErrors.NewValidationError(errorMessage)
+			case storage.StorageRecordNotFoundError:
+				return NewCategoryNotFoundError()
+			}
+
+			return serviceErrors.NewGeneralServiceError()
+		}
+
+		return audit.Record(s.ctx, tx, domain.AuditRestored, domain.CategoryAuditEntity, category.ID, category, restoredCategory)
+	})
+	if err != nil {
+		return nil, serviceErrors.FromTransaction(err)
+	}
+
+	return restoredCategory, nil
+}
+
 func validateCategoryCreateRequest(req *domain.CategoryCreateRequest) (errorMessages []string) {
 	if !utils.IsWithin(len(req.Tag), TagMinLength, TagMaxLength) {
 		errorMessages = append(errorMessages, fmt.Sprintf("category tag must be between %v to %v characters", TagMinLength, TagMaxLength))
@@ -9,7 +9,7 @@ import (
 	"github.com/rawfish-dev/rsvp-starter/server/mock"
 	. "github.com/rawfish-dev/rsvp-starter/server/services/category"
 	serviceErrors "github.com/rawfish-dev/rsvp-starter/server/services/errors"
-	"github.com/rawfish-dev/rsvp-starter/server/services/postgres"
+	"github.com/rawfish-dev/rsvp-starter/server/services/storage"
 
 	"github.com/Sirupsen/logrus"
 	"github.com/golang/mock/gomock"
@@ -21,7 +21,7 @@ import (
 var _ = Describe("Category", func() {
 
 	var ctrl *gomock.Controller
-	var mockCategoryStorage *mock_interfaces.MockCategoryStorage
+	var mockCategoryStorage *mock_interfaces.MockTransactionalStorage
 	var testCategoryService interfaces.CategoryServiceProvider
 
 	BeforeEach(func() {
@@ -31,7 +31,7 @@ var _ = Describe("Category", func() {
 		ctx := context.Background()
 		ctx = context.WithValue(ctx, "logger", ctxlogger)
 
-		mockCategoryStorage = mock_interfaces.NewMockCategoryStorage(ctrl)
+		mockCategoryStorage = mock_interfaces.NewMockTransactionalStorage(ctrl)
 		testCategoryService = NewService(ctx, mockCategoryStorage)
 	})
 
@@ -54,6 +54,7 @@ var _ = Describe("Category", func() {
 				ID:  1,
 				Tag: "some tag",
 			}, nil)
+			mockCategoryStorage.EXPECT().InsertAuditEntry(gomock.Any()).Return(&domain.AuditEntry{}, nil)
 
 			newCategory, err := testCategoryService.CreateCategory(req)
 			Expect(err).ToNot(HaveOccurred())
@@ -65,7 +66,7 @@ var _ = Describe("Category", func() {
 
 		It("should not allow categories with duplicate tags", func() {
 			mockCategoryStorage.EXPECT().InsertCategory(req).
-				Return(nil, postgres.NewPostgresCategoryTagUniqueConstraintError())
+				Return(nil, storage.NewStorageCategoryTagUniqueConstraintError())
 
 			newCategory, err := testCategoryService.CreateCategory(req)
 			Expect(err).To(HaveOccurred())
@@ -105,8 +106,10 @@ var _ = Describe("Category", func() {
 
 	Context("retrieval", func() {
 
-		It("should return all categories sorted alphabetically by tags", func() {
-			mockCategoryStorage.EXPECT().ListCategories().Return(
+		It("should return the first page of categories sorted by tag in descending order by default", func() {
+			mockCategoryStorage.EXPECT().ListCategories(&domain.CategoryListRequest{
+				ListRequest: domain.ListRequest{Page: 1, PageSize: domain.DefaultPageSize, Sort: "-tag"},
+			}).Return(
 				[]domain.Category{
 					{
 						Tag: "some tag 3",
@@ -117,23 +120,40 @@ var _ = Describe("Category", func() {
 					{
 						Tag: "some tag",
 					},
-				}, nil)
+				}, 3, nil)
 
-			allCategories, err := testCategoryService.ListCategories()
+			categoryList, err := testCategoryService.ListCategories(&domain.CategoryListRequest{})
 			Expect(err).ToNot(HaveOccurred())
-			Expect(allCategories).To(HaveLen(3))
-			Expect(allCategories[0].Tag).To(Equal("some tag 3"))
-			Expect(allCategories[1].Tag).To(Equal("some tag 2"))
-			Expect(allCategories[2].Tag).To(Equal("some tag"))
+			Expect(categoryList.Page).To(Equal(1))
+			Expect(categoryList.PageSize).To(Equal(domain.DefaultPageSize))
+			Expect(categoryList.Total).To(Equal(3))
+			Expect(categoryList.Categories).To(HaveLen(3))
+			Expect(categoryList.Categories[0].Tag).To(Equal("some tag 3"))
+			Expect(categoryList.Categories[1].Tag).To(Equal("some tag 2"))
+			Expect(categoryList.Categories[2].Tag).To(Equal("some tag"))
 		})
 
 		It("should return an empty slice if no categories exist", func() {
-			mockCategoryStorage.EXPECT().ListCategories().Return(
-				[]domain.Category{}, nil)
+			mockCategoryStorage.EXPECT().ListCategories(gomock.Any()).Return(
+				[]domain.Category{}, 0, nil)
 
-			allCategories, err := testCategoryService.ListCategories()
+			categoryList, err := testCategoryService.ListCategories(&domain.CategoryListRequest{})
 			Expect(err).ToNot(HaveOccurred())
-			Expect(allCategories).To(BeEmpty())
+			Expect(categoryList.Categories).To(BeEmpty())
+			Expect(categoryList.Total).To(BeZero())
+		})
+
+		It("should return an error if the page size or sort field is invalid", func() {
+			// Validation should catch it before any attempt to storage is made
+			mockCategoryStorage.EXPECT().ListCategories(gomock.Any()).Times(0)
+
+			categoryList, err := testCategoryService.ListCategories(&domain.CategoryListRequest{
+				ListRequest: domain.ListRequest{PageSize: domain.MaximumPageSize + 1, Sort: "createdAt"},
+			})
+			Expect(err).To(BeAssignableToTypeOf(serviceErrors.ValidationError{}))
+			Expect(err.Error()).To(Equal(
+				fmt.Sprintf("page size must be between 1 to %v; sort field createdAt is invalid", domain.MaximumPageSize)))
+			Expect(categoryList).To(BeNil())
 		})
 	})
 
@@ -164,6 +184,7 @@ var _ = Describe("Category", func() {
 						Tag:   "some updated tag",
 						Total: 0,
 					}, nil),
+				mockCategoryStorage.EXPECT().InsertAuditEntry(gomock.Any()).Return(&domain.AuditEntry{}, nil),
 			)
 
 			updatedCategory, err := testCategoryService.UpdateCategory(updateReq)
@@ -176,7 +197,7 @@ var _ = Describe("Category", func() {
 
 		It("should return an error if the category id cannot be found", func() {
 			mockCategoryStorage.EXPECT().FindCategoryByID(int64(123123123123)).Return(
-				nil, postgres.NewPostgresRecordNotFoundError())
+				nil, storage.NewStorageRecordNotFoundError())
 
 			updateReq.ID = 123123123123
 
@@ -197,7 +218,7 @@ var _ = Describe("Category", func() {
 				mockCategoryStorage.EXPECT().FindCategoryByID(int64(1)).Return(
 					category, nil),
 				mockCategoryStorage.EXPECT().UpdateCategory(category).Return(
-					nil, postgres.NewPostgresCategoryTagUniqueConstraintError()),
+					nil, storage.NewStorageCategoryTagUniqueConstraintError()),
 			)
 
 			updatedCategory, err := testCategoryService.UpdateCategory(updateReq)
@@ -253,6 +274,7 @@ var _ = Describe("Category", func() {
 				mockCategoryStorage.EXPECT().FindCategoryByID(int64(1)).Return(
 					category, nil),
 				mockCategoryStorage.EXPECT().DeleteCategory(category).Return(nil),
+				mockCategoryStorage.EXPECT().InsertAuditEntry(gomock.Any()).Return(&domain.AuditEntry{}, nil),
 			)
 
 			err := testCategoryService.DeleteCategoryByID(1)
@@ -265,11 +287,58 @@ var _ = Describe("Category", func() {
 
 		It("should return an error if the category id cannot be found", func() {
 			mockCategoryStorage.EXPECT().FindCategoryByID(int64(123123123)).Return(
-				nil, postgres.NewPostgresRecordNotFoundError())
+				nil, storage.NewStorageRecordNotFoundError())
 
 			err := testCategoryService.DeleteCategoryByID(123123123)
 			Expect(err).To(HaveOccurred())
 			Expect(err).To(BeAssignableToTypeOf(CategoryNotFoundError{}))
 		})
 	})
+
+	Context("restoring", func() {
+
+		It("should restore a deleted category", func() {
+			category := &domain.Category{
+				ID:        1,
+				Tag:       "some tag",
+				DeletedAt: "2026-10-17T10:00:00Z",
+			}
+			restoredCategory := &domain.Category{
+				ID:  1,
+				Tag: "some tag",
+			}
+
+			gomock.InOrder(
+				mockCategoryStorage.EXPECT().FindDeletedCategoryByID(int64(1)).Return(category, nil),
+				mockCategoryStorage.EXPECT().RestoreCategory(category).Return(restoredCategory, nil),
+				mockCategoryStorage.EXPECT().InsertAuditEntry(gomock.Any()).Return(&domain.AuditEntry{}, nil),
+			)
+
+			result, err := testCategoryService.RestoreCategoryByID(1)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result).To(Equal(restoredCategory))
+		})
+
+		It("should not restore a category whose tag has been taken since it was deleted", func() {
+			category := &domain.Category{ID: 1, Tag: "some tag"}
+
+			gomock.InOrder(
+				mockCategoryStorage.EXPECT().FindDeletedCategoryByID(int64(1)).Return(category, nil),
+				mockCategoryStorage.EXPECT().RestoreCategory(category).Return(
+					nil, storage.NewStorageCategoryTagUniqueConstraintError()),
+			)
+
+			_, err := testCategoryService.RestoreCategoryByID(1)
+			Expect(err).To(BeAssignableToTypeOf(serviceErrors.ValidationError{}))
+			Expect(err.Error()).To(Equal("category tag already exists"))
+		})
+
+		It("should return an error if the category is not in the trash", func() {
+			mockCategoryStorage.EXPECT().FindDeletedCategoryByID(int64(123123123)).Return(
+				nil, storage.NewStorageRecordNotFoundError())
+
+			_, err := testCategoryService.RestoreCategoryByID(123123123)
+			Expect(err).To(BeAssignableToTypeOf(CategoryNotFoundError{}))
+		})
+	})
 })
@@ -2,6 +2,8 @@ package errors
 
 import (
 	"strings"
+
+	"github.com/rawfish-dev/rsvp-starter/server/services/storage"
 )
 
 type GeneralServiceError struct {
@@ -12,8 +14,9 @@ func NewGeneralServiceError() error {
 	return GeneralServiceError{}
 }
 
+// Error does not repeat the cause, which is logged where the failure happened.
 func (g GeneralServiceError) Error() string {
-	return ""
+	return "unexpected error, see the log for details"
 }
 
 type ValidationError struct {
@@ -27,3 +30,14 @@ func NewValidationError(errorMessages []string) error {
 func (v ValidationError) Error() (fullErrorMessage string) {
 	return strings.Join(v.errorMessages, "; ")
 }
+
+// FromTransaction passes errors returned by a WithTx callback through unchanged and turns a failure
+// to begin or commit the transaction itself into a GeneralServiceError.
+func FromTransaction(err error) error {
+	switch err.(type) {
+	case storage.StorageOperationError:
+		return NewGeneralServiceError()
+	}
+
+	return err
+}
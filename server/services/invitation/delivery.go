@@ -0,0 +1,114 @@
+package invitation
+
+import (
+	"github.com/rawfish-dev/rsvp-starter/server/domain"
+	"github.com/rawfish-dev/rsvp-starter/server/interfaces"
+	serviceErrors "github.com/rawfish-dev/rsvp-starter/server/services/errors"
+	"github.com/rawfish-dev/rsvp-starter/server/services/storage"
+)
+
+// statusProgress orders the statuses so a report that arrives late cannot undo a newer one. Failed comes
+// last as a delivered email can still bounce.
+var statusProgress = map[domain.MessageStatus]int{
+	domain.MessageQueued:    0,
+	domain.MessageSent:      1,
+	domain.MessageDelivered: 2,
+	domain.MessageFailed:    3,
+}
+
+// UpdateMessageStatus applies a status reported by a provider. Reports that would move the message back to
+// an earlier status, such as a retried webhook, leave the message as it is.
+func (s *service) UpdateMessageStatus(req *domain.MessageStatusUpdate) (*domain.Message, error) {
+	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
+
+	errorMessages := validateMessageStatusUpdate(req)
+	if len(errorMessages) > 0 {
+		return nil, serviceErrors.NewValidationError(errorMessages)
+	}
+
+	var updatedMessage *domain.Message
+
+	err := s.invitationStorage.WithTx(func(tx interfaces.Storage) error {
+		message, err := tx.FindMessageByProviderMessageID(req.Provider, req.ProviderMessageID)
+		if err != nil {
+			switch err.(type) {
+			case storage.StorageRecordNotFoundError:
+				return NewMessageNotFoundError()
+			}
+
+			return serviceErrors.NewGeneralServiceError()
+		}
+
+		if statusProgress[req.Status] <= statusProgress[message.Status] {
+			ctxLogger.Infof("invitation service - ignoring %v status for message %v as it is already %v", req.Status, message.ID, message.Status)
+			updatedMessage = message
+			return nil
+		}
+
+		message.Status = req.Status
+		if req.Status == domain.MessageFailed {
+			message.Error = req.Error
+		}
+
+		updatedMessage, err = tx.UpdateMessage(message)
+		if err != nil {
+			ctxLogger.Errorf("invitation service - unable to update status of message %v due to %v", message.ID, err)
+			return serviceErrors.NewGeneralServiceError()
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, serviceErrors.FromTransaction(err)
+	}
+
+	return updatedMessage, nil
+}
+
+// deliveries picks the latest invitation or reminder sent to each invitation on each channel. Messages
+// are expected in the order they were sent.
+func deliveries(messages []domain.Message) map[int64][]domain.MessageDelivery {
+	latest := map[int64]map[domain.MessageChannel]domain.Message{}
+	for _, message := range messages {
+		if message.Kind != domain.InvitationMessage && message.Kind != domain.ReminderMessage {
+			continue
+		}
+		if latest[message.InvitationID] == nil {
+			latest[message.InvitationID] = map[domain.MessageChannel]domain.Message{}
+		}
+		latest[message.InvitationID][message.Channel] = message
+	}
+
+	deliveries := map[int64][]domain.MessageDelivery{}
+	for invitationID, byChannel := range latest {
+		for _, channel := range []domain.MessageChannel{domain.SMSChannel, domain.EmailChannel} {
+			message, ok := byChannel[channel]
+			if !ok {
+				continue
+			}
+			deliveries[invitationID] = append(deliveries[invitationID], domain.MessageDelivery{
+				Channel:   channel,
+				MessageID: message.ID,
+				Status:    message.Status,
+				Error:     message.Error,
+				UpdatedAt: message.UpdatedAt,
+			})
+		}
+	}
+
+	return deliveries
+}
+
+func validateMessageStatusUpdate(req *domain.MessageStatusUpdate) (errorMessages []string) {
+	if req.Provider == "" {
+		errorMessages = append(errorMessages, "message provider is required")
+	}
+	if req.ProviderMessageID == "" {
+		errorMessages = append(errorMessages, "message provider message id is required")
+	}
+	if !domain.IsValidMessageStatus(req.Status) {
+		errorMessages = append(errorMessages, "message status is invalid")
+	}
+
+	return errorMessages
+}
@@ -0,0 +1,94 @@
+package invitation_test
+
+import (
+	"github.com/rawfish-dev/rsvp-starter/server/config"
+	"github.com/rawfish-dev/rsvp-starter/server/domain"
+	"github.com/rawfish-dev/rsvp-starter/server/interfaces"
+	"github.com/rawfish-dev/rsvp-starter/server/mock"
+	serviceErrors "github.com/rawfish-dev/rsvp-starter/server/services/errors"
+	. "github.com/rawfish-dev/rsvp-starter/server/services/invitation"
+	"github.com/rawfish-dev/rsvp-starter/server/services/storage"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"golang.org/x/net/context"
+)
+
+var _ = Describe("Message delivery status", func() {
+
+	var ctrl *gomock.Controller
+	var mockInvitationStorage *mock_interfaces.MockTransactionalStorage
+	var testInvitationService interfaces.InvitationServiceProvider
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+
+		ctxlogger := logrus.New()
+		ctx := context.Background()
+		ctx = context.WithValue(ctx, "logger", ctxlogger)
+
+		mockInvitationStorage = mock_interfaces.NewMockTransactionalStorage(ctrl)
+		testInvitationService = NewService(ctx, config.MessagingConfig{}, config.ReminderConfig{}, nil, nil, mockInvitationStorage)
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	It("should record the status the provider reported along with its reason for failures", func() {
+		gomock.InOrder(
+			mockInvitationStorage.EXPECT().FindMessageByProviderMessageID("twilio", "SM123").Return(
+				&domain.Message{ID: 2, Status: domain.MessageDelivered}, nil),
+			mockInvitationStorage.EXPECT().UpdateMessage(&domain.Message{ID: 2, Status: domain.MessageFailed, Error: "carrier rejected"}).Return(
+				&domain.Message{ID: 2, Status: domain.MessageFailed, Error: "carrier rejected"}, nil),
+		)
+
+		message, err := testInvitationService.UpdateMessageStatus(&domain.MessageStatusUpdate{
+			Provider:          "twilio",
+			ProviderMessageID: "SM123",
+			Status:            domain.MessageFailed,
+			Error:             "carrier rejected",
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(message.Status).To(Equal(domain.MessageFailed))
+		Expect(message.Error).To(Equal("carrier rejected"))
+	})
+
+	It("should leave the message as it is if the status reported is older than the one recorded", func() {
+		mockInvitationStorage.EXPECT().FindMessageByProviderMessageID("twilio", "SM123").Return(
+			&domain.Message{ID: 2, Status: domain.MessageDelivered}, nil)
+		mockInvitationStorage.EXPECT().UpdateMessage(gomock.Any()).Times(0)
+
+		message, err := testInvitationService.UpdateMessageStatus(&domain.MessageStatusUpdate{
+			Provider:          "twilio",
+			ProviderMessageID: "SM123",
+			Status:            domain.MessageSent,
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(message.Status).To(Equal(domain.MessageDelivered))
+	})
+
+	It("should return an error if the message cannot be found", func() {
+		mockInvitationStorage.EXPECT().FindMessageByProviderMessageID("twilio", "SM404").Return(
+			nil, storage.NewStorageRecordNotFoundError())
+
+		message, err := testInvitationService.UpdateMessageStatus(&domain.MessageStatusUpdate{
+			Provider:          "twilio",
+			ProviderMessageID: "SM404",
+			Status:            domain.MessageDelivered,
+		})
+		Expect(err).To(BeAssignableToTypeOf(MessageNotFoundError{}))
+		Expect(message).To(BeNil())
+	})
+
+	It("should return an error if the update is incomplete or the status is invalid", func() {
+		mockInvitationStorage.EXPECT().FindMessageByProviderMessageID(gomock.Any(), gomock.Any()).Times(0)
+
+		message, err := testInvitationService.UpdateMessageStatus(&domain.MessageStatusUpdate{Status: "read"})
+		Expect(err).To(BeAssignableToTypeOf(serviceErrors.ValidationError{}))
+		Expect(err.Error()).To(Equal("message provider is required; message provider message id is required; message status is invalid"))
+		Expect(message).To(BeNil())
+	})
+})
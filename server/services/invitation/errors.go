@@ -1,6 +1,15 @@
 package invitation
 
+import (
+	"github.com/rawfish-dev/rsvp-starter/server/domain"
+)
+
 var _ error = new(InvitationNotFoundError)
+var _ error = new(InvitationVersionConflictError)
+var _ error = new(InvitationLinkGoneError)
+var _ error = new(InvitationPhoneConfirmationRequiredError)
+var _ error = new(InvitationSendFailedError)
+var _ error = new(MessageNotFoundError)
 
 type InvitationNotFoundError struct {
 }
@@ -12,3 +21,66 @@ func NewInvitationNotFoundError() error {
 func (i InvitationNotFoundError) Error() string {
 	return "invitation not found"
 }
+
+// InvitationVersionConflictError carries the latest invitation so the caller can show what changed.
+type InvitationVersionConflictError struct {
+	Current *domain.Invitation
+}
+
+func NewInvitationVersionConflictError(current *domain.Invitation) error {
+	return InvitationVersionConflictError{current}
+}
+
+func (i InvitationVersionConflictError) Error() string {
+	return "invitation has been modified since it was retrieved"
+}
+
+// InvitationLinkGoneError is returned for links that were revoked, have expired or were replaced by a
+// new private id, so guests can be told to ask for a new link rather than that it never existed
+type InvitationLinkGoneError struct {
+}
+
+func NewInvitationLinkGoneError() error {
+	return InvitationLinkGoneError{}
+}
+
+func (i InvitationLinkGoneError) Error() string {
+	return "invitation link is no longer valid"
+}
+
+type InvitationPhoneConfirmationRequiredError struct {
+}
+
+func NewInvitationPhoneConfirmationRequiredError() error {
+	return InvitationPhoneConfirmationRequiredError{}
+}
+
+func (i InvitationPhoneConfirmationRequiredError) Error() string {
+	return "invitation mobile phone number must be confirmed"
+}
+
+// InvitationSendFailedError carries the messages that were recorded for the failed attempt, which hold
+// the reasons the providers gave
+type InvitationSendFailedError struct {
+	Messages []domain.Message
+	Reason   string
+}
+
+func NewInvitationSendFailedError(messages []domain.Message, reason string) error {
+	return InvitationSendFailedError{messages, reason}
+}
+
+func (i InvitationSendFailedError) Error() string {
+	return "invitation could not be sent due to " + i.Reason
+}
+
+type MessageNotFoundError struct {
+}
+
+func NewMessageNotFoundError() error {
+	return MessageNotFoundError{}
+}
+
+func (m MessageNotFoundError) Error() string {
+	return "message not found"
+}
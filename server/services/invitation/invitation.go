@@ -3,10 +3,12 @@ package invitation
 import (
 	"fmt"
 
+	"github.com/rawfish-dev/rsvp-starter/server/config"
 	"github.com/rawfish-dev/rsvp-starter/server/domain"
 	"github.com/rawfish-dev/rsvp-starter/server/interfaces"
+	"github.com/rawfish-dev/rsvp-starter/server/services/audit"
 	serviceErrors "github.com/rawfish-dev/rsvp-starter/server/services/errors"
-	"github.com/rawfish-dev/rsvp-starter/server/services/postgres"
+	"github.com/rawfish-dev/rsvp-starter/server/services/storage"
 	"github.com/rawfish-dev/rsvp-starter/server/utils"
 
 	"golang.org/x/net/context"
@@ -20,6 +22,7 @@ const (
 	NoteMaxLength              = 500
 	MobilePhoneNumberMinLength = 8
 	MobilePhoneNumberMaxLength = 20
+	EmailMaxLength             = 254
 	defaultPhoneExtension      = "+65"
 )
 
@@ -27,11 +30,15 @@ var _ interfaces.InvitationServiceProvider = new(service)
 
 type service struct {
 	ctx               context.Context
-	invitationStorage interfaces.InvitationStorage
+	messagingConfig   config.MessagingConfig
+	reminderConfig    config.ReminderConfig
+	messagingProvider interfaces.MessagingProvider
+	emailSender       interfaces.EmailSender
+	invitationStorage interfaces.Storage
 }
 
-func NewService(ctx context.Context, invitationStorage interfaces.InvitationStorage) *service {
-	return &service{ctx, invitationStorage}
+func NewService(ctx context.Context, messagingConfig config.MessagingConfig, reminderConfig config.ReminderConfig, messagingProvider interfaces.MessagingProvider, emailSender interfaces.EmailSender, invitationStorage interfaces.Storage) *service {
+	return &service{ctx, messagingConfig, reminderConfig, messagingProvider, emailSender, invitationStorage}
 }
 
 func (s *service) CreateInvitation(req *domain.InvitationCreateRequest) (*domain.Invitation, error) {
@@ -45,49 +52,74 @@ func (s *service) CreateInvitation(req *domain.InvitationCreateRequest) (*domain
 		req.MobilePhoneNumber = defaultPhoneExtension
 	}
 
-	newInvitation, err := s.invitationStorage.InsertInvitation(req)
-	if err != nil {
-		errorMessage := []string{err.Error()}
+	var newInvitation *domain.Invitation
 
-		switch err.(type) {
-		case postgres.PostgresInvitationGreetingUniqueConstraintError, postgres.PostgresInvitationMobilePhoneNumberUniqueConstraintError:
-			return nil, serviceErrors.NewValidationError(errorMessage)
+	err := s.invitationStorage.WithTx(func(tx interfaces.Storage) error {
+		var err error
+
+		newInvitation, err = tx.InsertInvitation(req)
+		if err != nil {
+			errorMessage := []string{err.Error()}
+
+			switch err.(type) {
+			case storage.StorageInvitationGreetingUniqueConstraintError, storage.StorageInvitationMobilePhoneNumberUniqueConstraintError:
+				return serviceErrors.NewValidationError(errorMessage)
+			}
+
+			return serviceErrors.NewGeneralServiceError()
 		}
 
-		return nil, serviceErrors.NewGeneralServiceError()
+		return audit.Record(s.ctx, tx, domain.AuditCreated, domain.InvitationAuditEntity, newInvitation.ID, nil, newInvitation)
+	})
+	if err != nil {
+		return nil, serviceErrors.FromTransaction(err)
 	}
 
 	return newInvitation, nil
 }
 
-func (s *service) ListInvitations(rsvps []domain.RSVP) ([]domain.Invitation, error) {
+func (s *service) ListInvitations(req *domain.InvitationListRequest) (*domain.InvitationList, error) {
 	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
 
-	invitations, err := s.invitationStorage.ListInvitations()
+	req.ApplyDefaults("-" + domain.SortByInvitationUpdatedAt)
+
+	errorMessages := validateInvitationListRequest(req)
+	if len(errorMessages) > 0 {
+		return nil, serviceErrors.NewValidationError(errorMessages)
+	}
+
+	invitations, total, err := s.invitationStorage.ListInvitations(req)
 	if err != nil {
-		ctxLogger.Error("invitation service - unable to list all invitations")
+		ctxLogger.Error("invitation service - unable to list invitations")
 		return nil, serviceErrors.NewGeneralServiceError()
 	}
 
-	mappedRSVPs := make(map[string]domain.RSVP)
+	invitationIDs := make([]int64, len(invitations))
+	for idx := range invitations {
+		invitationIDs[idx] = invitations[idx].ID
+	}
 
-	// Track which invitation private ids have already been RSVP-ed
-	for idx := range rsvps {
-		mappedRSVPs[rsvps[idx].InvitationPrivateID] = rsvps[idx]
+	messages, err := s.invitationStorage.ListMessagesByInvitationIDs(invitationIDs)
+	if err != nil {
+		ctxLogger.Error("invitation service - unable to list messages of the invitations")
+		return nil, serviceErrors.NewGeneralServiceError()
 	}
 
+	delivery := deliveries(messages)
 	for idx := range invitations {
-		if rsvp, ok := mappedRSVPs[invitations[idx].PrivateID]; ok {
-			// Map invitation status according to whether the guests are attending
-			if rsvp.Attending {
-				invitations[idx].Status = domain.RepliedAttending
-			} else {
-				invitations[idx].Status = domain.RepliedNotAttending
-			}
-		}
+		invitations[idx].Delivery = delivery[invitations[idx].ID]
 	}
 
-	return invitations, nil
+	invitationList := &domain.InvitationList{
+		ListResponse: domain.ListResponse{
+			Page:     req.Page,
+			PageSize: req.PageSize,
+			Total:    total,
+		},
+		Invitations: invitations,
+	}
+
+	return invitationList, nil
 }
 
 func (s *service) UpdateInvitation(req *domain.InvitationUpdateRequest) (*domain.Invitation, error) {
@@ -96,68 +128,157 @@ func (s *service) UpdateInvitation(req *domain.InvitationUpdateRequest) (*domain
 		return nil, serviceErrors.NewValidationError(errorMessages)
 	}
 
-	invitation, err := s.invitationStorage.FindInvitationByID(req.ID)
-	if err != nil {
-		switch err.(type) {
-		case postgres.PostgresRecordNotFoundError:
-			return nil, NewInvitationNotFoundError()
-		}
+	var updatedInvitation *domain.Invitation
 
-		return nil, serviceErrors.NewGeneralServiceError()
-	}
+	err := s.invitationStorage.WithTx(func(tx interfaces.Storage) error {
+		invitation, err := tx.FindInvitationByID(req.ID)
+		if err != nil {
+			switch err.(type) {
+			case storage.StorageRecordNotFoundError:
+				return NewInvitationNotFoundError()
+			}
 
-	invitation.CategoryID = req.CategoryID
-	invitation.Greeting = req.Greeting
-	invitation.MaximumGuestCount = req.MaximumGuestCount
-	invitation.Notes = req.Notes
-	invitation.MobilePhoneNumber = req.MobilePhoneNumber
-	invitation.Status = req.Status
+			return serviceErrors.NewGeneralServiceError()
+		}
 
-	updatedInvitation, err := s.invitationStorage.UpdateInvitation(invitation)
-	if err != nil {
-		errorMessage := []string{err.Error()}
+		if invitation.Version != req.Version {
+			return NewInvitationVersionConflictError(invitation)
+		}
 
-		switch err.(type) {
-		case postgres.PostgresInvitationGreetingUniqueConstraintError,
-			postgres.PostgresInvitationMobilePhoneNumberUniqueConstraintError:
-			return nil, serviceErrors.NewValidationError(errorMessage)
+		before := *invitation
+		invitation.CategoryID = req.CategoryID
+		invitation.Greeting = req.Greeting
+		invitation.MaximumGuestCount = req.MaximumGuestCount
+		invitation.Notes = req.Notes
+		invitation.MobilePhoneNumber = req.MobilePhoneNumber
+		invitation.Email = req.Email
+		invitation.Language = req.Language
+		invitation.Status = req.Status
+
+		updatedInvitation, err = tx.UpdateInvitation(invitation)
+		if err != nil {
+			errorMessage := []string{err.Error()}
+
+			switch err.(type) {
+			case storage.StorageInvitationGreetingUniqueConstraintError,
+				storage.StorageInvitationMobilePhoneNumberUniqueConstraintError:
+				return serviceErrors.NewValidationError(errorMessage)
+			case storage.StorageVersionConflictError:
+				return s.invitationVersionConflict(tx, req.ID)
+			}
+
+			return serviceErrors.NewGeneralServiceError()
 		}
 
-		return nil, serviceErrors.NewGeneralServiceError()
+		return audit.Record(s.ctx, tx, domain.AuditUpdated, domain.InvitationAuditEntity, invitation.ID, before, updatedInvitation)
+	})
+	if err != nil {
+		return nil, serviceErrors.FromTransaction(err)
 	}
 
 	return updatedInvitation, nil
 }
 
 func (s *service) DeleteInvitationByID(invitationID int64) error {
-	invitation, err := s.invitationStorage.FindInvitationByID(invitationID)
+	err := s.invitationStorage.WithTx(func(tx interfaces.Storage) error {
+		invitation, err := tx.FindInvitationByID(invitationID)
+		if err != nil {
+			switch err.(type) {
+			case storage.StorageRecordNotFoundError:
+				return NewInvitationNotFoundError()
+			}
+
+			return serviceErrors.NewGeneralServiceError()
+		}
+
+		err = tx.DeleteInvitation(invitation)
+		if err != nil {
+			switch err.(type) {
+			case storage.StorageRecordNotFoundError:
+				return NewInvitationNotFoundError()
+			}
+
+			return serviceErrors.NewGeneralServiceError()
+		}
+
+		return audit.Record(s.ctx, tx, domain.AuditDeleted, domain.InvitationAuditEntity, invitation.ID, invitation, nil)
+	})
 	if err != nil {
-		switch err.(type) {
-		case postgres.PostgresRecordNotFoundError:
-			return NewInvitationNotFoundError()
+		return serviceErrors.FromTransaction(err)
+	}
+
+	return nil
+}
+
+func (s *service) RestoreInvitationByID(invitationID int64) (*domain.Invitation, error) {
+	var restoredInvitation *domain.Invitation
+
+	err := s.invitationStorage.WithTx(func(tx interfaces.Storage) error {
+		invitation, err := tx.FindDeletedInvitationByID(invitationID)
+		if err != nil {
+			switch err.(type) {
+			case storage.StorageRecordNotFoundError:
+				return NewInvitationNotFoundError()
+			}
+
+			return serviceErrors.NewGeneralServiceError()
 		}
 
-		return serviceErrors.NewGeneralServiceError()
+		_, err = tx.FindCategoryByID(invitation.CategoryID)
+		if err != nil {
+			switch err.(type) {
+			case storage.StorageRecordNotFoundError:
+				errorMessage := []string{"category of the invitation must be restored first"}
+				return serviceErrors.NewValidationError(errorMessage)
+			}
+
+			return serviceErrors.NewGeneralServiceError()
+		}
+
+		restoredInvitation, err = tx.RestoreInvitation(invitation)
+		if err != nil {
+			errorMessage := []string{err.Error()}
+
+			switch err.(type) {
+			case storage.StorageInvitationGreetingUniqueConstraintError,
+				storage.StorageInvitationMobilePhoneNumberUniqueConstraintError:
+				return serviceErrors.NewValidationError(errorMessage)
+			case storage.StorageRecordNotFoundError:
+				return NewInvitationNotFoundError()
+			}
+
+			return serviceErrors.NewGeneralServiceError()
+		}
+
+		return audit.Record(s.ctx, tx, domain.AuditRestored, domain.InvitationAuditEntity, invitation.ID, invitation, restoredInvitation)
+	})
+	if err != nil {
+		return nil, serviceErrors.FromTransaction(err)
 	}
 
-	err = s.invitationStorage.DeleteInvitation(invitation)
+	return restoredInvitation, nil
+}
+
+// invitationVersionConflict reloads the invitation that was changed by a concurrent update.
+func (s *service) invitationVersionConflict(tx interfaces.Storage, invitationID int64) error {
+	current, err := tx.FindInvitationByID(invitationID)
 	if err != nil {
 		switch err.(type) {
-		case postgres.PostgresRecordNotFoundError:
+		case storage.StorageRecordNotFoundError:
 			return NewInvitationNotFoundError()
 		}
 
 		return serviceErrors.NewGeneralServiceError()
 	}
 
-	return nil
+	return NewInvitationVersionConflictError(current)
 }
 
 func (s *service) RetrieveInvitationByPrivateID(privateID string) (*domain.Invitation, error) {
 	invitation, err := s.invitationStorage.FindInvitationByPrivateID(privateID)
 	if err != nil {
 		switch err.(type) {
-		case postgres.PostgresRecordNotFoundError:
+		case storage.StorageRecordNotFoundError:
 			return nil, NewInvitationNotFoundError()
 		}
 
@@ -180,6 +301,12 @@ func validateBaseInvitation(baseInvitation domain.BaseInvitation) (errorMessages
 	if len(baseInvitation.MobilePhoneNumber) > MobilePhoneNumberMaxLength {
 		errorMessages = append(errorMessages, fmt.Sprintf("invitation mobile phone number must be less than %v in length", MobilePhoneNumberMaxLength))
 	}
+	if baseInvitation.Email != "" && (len(baseInvitation.Email) > EmailMaxLength || !utils.IsValidEmail(baseInvitation.Email)) {
+		errorMessages = append(errorMessages, "invitation email must be a valid email address")
+	}
+	if baseInvitation.Language != "" && !utils.IsValidLanguageTag(baseInvitation.Language) {
+		errorMessages = append(errorMessages, "invitation language must be a language tag such as en or zh-Hans")
+	}
 
 	return errorMessages
 }
@@ -188,10 +315,30 @@ func validateInvitationCreateRequest(req *domain.InvitationCreateRequest) (error
 	return validateBaseInvitation(req.BaseInvitation)
 }
 
+func validateInvitationListRequest(req *domain.InvitationListRequest) (errorMessages []string) {
+	errorMessages = req.Validate(
+		domain.SortByInvitationGreeting,
+		domain.SortByInvitationMaximumGuestCount,
+		domain.SortByInvitationStatus,
+		domain.SortByInvitationUpdatedAt,
+	)
+
+	switch req.Status {
+	case "", domain.NotSent, domain.Sent, domain.RepliedAttending, domain.RepliedNotAttending:
+	default:
+		errorMessages = append(errorMessages, "status is invalid")
+	}
+
+	return errorMessages
+}
+
 func validateInvitationUpdateRequest(req *domain.InvitationUpdateRequest) (errorMessages []string) {
 	if req.ID <= 0 {
 		errorMessages = append(errorMessages, "invitation id is invalid")
 	}
+	if req.Version <= 0 {
+		errorMessages = append(errorMessages, "invitation version is invalid")
+	}
 	if !domain.IsValidRSVPStatus(req.Status) {
 		errorMessages = append(errorMessages, "status is invalid")
 	}
@@ -4,12 +4,13 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/rawfish-dev/rsvp-starter/server/config"
 	"github.com/rawfish-dev/rsvp-starter/server/domain"
 	"github.com/rawfish-dev/rsvp-starter/server/interfaces"
 	"github.com/rawfish-dev/rsvp-starter/server/mock"
 	serviceErrors "github.com/rawfish-dev/rsvp-starter/server/services/errors"
 	. "github.com/rawfish-dev/rsvp-starter/server/services/invitation"
-	"github.com/rawfish-dev/rsvp-starter/server/services/postgres"
+	"github.com/rawfish-dev/rsvp-starter/server/services/storage"
 
 	"github.com/Sirupsen/logrus"
 	"github.com/golang/mock/gomock"
@@ -21,7 +22,7 @@ import (
 var _ = Describe("Invitation", func() {
 
 	var ctrl *gomock.Controller
-	var mockInvitationStorage *mock_interfaces.MockInvitationStorage
+	var mockInvitationStorage *mock_interfaces.MockTransactionalStorage
 	var testInvitationService interfaces.InvitationServiceProvider
 
 	BeforeEach(func() {
@@ -31,8 +32,8 @@ var _ = Describe("Invitation", func() {
 		ctx := context.Background()
 		ctx = context.WithValue(ctx, "logger", ctxlogger)
 
-		mockInvitationStorage = mock_interfaces.NewMockInvitationStorage(ctrl)
-		testInvitationService = NewService(ctx, mockInvitationStorage)
+		mockInvitationStorage = mock_interfaces.NewMockTransactionalStorage(ctrl)
+		testInvitationService = NewService(ctx, config.MessagingConfig{}, config.ReminderConfig{}, mock_interfaces.NewMockMessagingProvider(ctrl), mock_interfaces.NewMockEmailSender(ctrl), mockInvitationStorage)
 	})
 
 	Context("creation", func() {
@@ -70,6 +71,7 @@ var _ = Describe("Invitation", func() {
 					PrivateID:      "some-private-id",
 					Status:         domain.NotSent,
 				}, nil)
+			mockInvitationStorage.EXPECT().InsertAuditEntry(gomock.Any()).Return(&domain.AuditEntry{}, nil)
 
 			newInvitation, err := testInvitationService.CreateInvitation(req)
 			Expect(err).ToNot(HaveOccurred())
@@ -90,7 +92,8 @@ var _ = Describe("Invitation", func() {
 			baseInvitation.MobilePhoneNumber = "+65" // Default phone extension
 			mockInvitationStorage.EXPECT().InsertInvitation(&domain.InvitationCreateRequest{
 				BaseInvitation: baseInvitation,
-			})
+			}).Return(&domain.Invitation{ID: 1, BaseInvitation: baseInvitation}, nil)
+			mockInvitationStorage.EXPECT().InsertAuditEntry(gomock.Any()).Return(&domain.AuditEntry{}, nil)
 
 			testInvitationService.CreateInvitation(req)
 		})
@@ -98,7 +101,7 @@ var _ = Describe("Invitation", func() {
 		It("should not allow invitations with duplicate greetings", func() {
 			mockInvitationStorage.EXPECT().InsertInvitation(&domain.InvitationCreateRequest{
 				BaseInvitation: baseInvitation,
-			}).Return(nil, postgres.NewPostgresInvitationGreetingUniqueConstraintError())
+			}).Return(nil, storage.NewStorageInvitationGreetingUniqueConstraintError())
 
 			duplicateInvitation, err := testInvitationService.CreateInvitation(req)
 			Expect(err).To(HaveOccurred())
@@ -228,6 +231,73 @@ var _ = Describe("Invitation", func() {
 		// })
 	})
 
+	Context("listing", func() {
+
+		It("should pass the filters through with the default page and sort", func() {
+			invitations := []domain.Invitation{
+				{ID: 1, Status: domain.RepliedAttending},
+			}
+
+			mockInvitationStorage.EXPECT().ListInvitations(&domain.InvitationListRequest{
+				ListRequest: domain.ListRequest{Page: 1, PageSize: domain.DefaultPageSize, Sort: "-updatedAt"},
+				CategoryID:  2,
+				Status:      domain.RepliedAttending,
+			}).Return(invitations, 11, nil)
+			mockInvitationStorage.EXPECT().ListMessagesByInvitationIDs([]int64{1}).Return(nil, nil)
+
+			invitationList, err := testInvitationService.ListInvitations(&domain.InvitationListRequest{
+				CategoryID: 2,
+				Status:     domain.RepliedAttending,
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(invitationList.Invitations).To(Equal(invitations))
+			Expect(invitationList.Page).To(Equal(1))
+			Expect(invitationList.PageSize).To(Equal(domain.DefaultPageSize))
+			Expect(invitationList.Total).To(Equal(11))
+		})
+
+		It("should show how the latest invitation or reminder on each channel has fared", func() {
+			mockInvitationStorage.EXPECT().ListInvitations(gomock.Any()).Return(
+				[]domain.Invitation{{ID: 1}, {ID: 2}}, 2, nil)
+			mockInvitationStorage.EXPECT().ListMessagesByInvitationIDs([]int64{1, 2}).Return([]domain.Message{
+				{ID: 1, InvitationID: 1, Kind: domain.InvitationMessage, Channel: domain.EmailChannel, Status: domain.MessageDelivered},
+				{ID: 2, InvitationID: 1, Kind: domain.InvitationMessage, Channel: domain.SMSChannel, Status: domain.MessageFailed, Error: "unreachable"},
+				{ID: 3, InvitationID: 1, Kind: domain.ReminderMessage, Channel: domain.EmailChannel, Status: domain.MessageQueued, UpdatedAt: "2026-10-17T10:00:00Z"},
+				{ID: 4, InvitationID: 1, Kind: domain.ConfirmationMessage, Channel: domain.EmailChannel, Status: domain.MessageSent},
+			}, nil)
+
+			invitationList, err := testInvitationService.ListInvitations(&domain.InvitationListRequest{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(invitationList.Invitations[0].Delivery).To(Equal([]domain.MessageDelivery{
+				{Channel: domain.SMSChannel, MessageID: 2, Status: domain.MessageFailed, Error: "unreachable"},
+				{Channel: domain.EmailChannel, MessageID: 3, Status: domain.MessageQueued, UpdatedAt: "2026-10-17T10:00:00Z"},
+			}))
+			Expect(invitationList.Invitations[1].Delivery).To(BeNil())
+		})
+
+		It("should return an error if the page, sort field or status is invalid", func() {
+			// Validation should catch it before any attempt to storage is made
+			mockInvitationStorage.EXPECT().ListInvitations(gomock.Any()).Times(0)
+
+			invitationList, err := testInvitationService.ListInvitations(&domain.InvitationListRequest{
+				ListRequest: domain.ListRequest{Page: -1, Sort: "-notes"},
+				Status:      "XX",
+			})
+			Expect(err).To(BeAssignableToTypeOf(serviceErrors.ValidationError{}))
+			Expect(err.Error()).To(Equal("page must be at least 1; sort field notes is invalid; status is invalid"))
+			Expect(invitationList).To(BeNil())
+		})
+
+		It("should return a general error if the invitations cannot be listed", func() {
+			mockInvitationStorage.EXPECT().ListInvitations(gomock.Any()).Return(
+				nil, 0, storage.NewStorageOperationError())
+
+			invitationList, err := testInvitationService.ListInvitations(&domain.InvitationListRequest{})
+			Expect(err).To(BeAssignableToTypeOf(serviceErrors.GeneralServiceError{}))
+			Expect(invitationList).To(BeNil())
+		})
+	})
+
 	// Context("retrieval", func() {
 
 	// 	// TODO:: Improve, not a very useful test as ordering is in the postgres layer
@@ -309,8 +379,9 @@ var _ = Describe("Invitation", func() {
 					Notes:             "some updated notes",
 					MobilePhoneNumber: "91231236",
 				},
-				ID:     1,
-				Status: domain.Sent,
+				ID:      1,
+				Status:  domain.Sent,
+				Version: 1,
 			}
 		})
 
@@ -320,6 +391,7 @@ var _ = Describe("Invitation", func() {
 				ID:             1,
 				PrivateID:      "some-private-id",
 				Status:         domain.NotSent,
+				Version:        1,
 			}
 
 			modifiedInvitation := *invitation
@@ -336,6 +408,7 @@ var _ = Describe("Invitation", func() {
 					invitation, nil),
 				mockInvitationStorage.EXPECT().UpdateInvitation(&modifiedInvitation).Return(
 					&modifiedInvitation, nil),
+				mockInvitationStorage.EXPECT().InsertAuditEntry(gomock.Any()).Return(&domain.AuditEntry{}, nil),
 			)
 
 			updatedInvitation, err := testInvitationService.UpdateInvitation(updateReq)
@@ -354,7 +427,7 @@ var _ = Describe("Invitation", func() {
 		It("should return an error if the invitation cannot be found", func() {
 			gomock.InOrder(
 				mockInvitationStorage.EXPECT().FindInvitationByID(int64(123123123)).Return(
-					nil, postgres.NewPostgresRecordNotFoundError()),
+					nil, storage.NewStorageRecordNotFoundError()),
 				mockInvitationStorage.EXPECT().UpdateInvitation(gomock.Any()).Times(0),
 			)
 
@@ -366,18 +439,70 @@ var _ = Describe("Invitation", func() {
 			Expect(updatedInvitation).To(BeNil())
 		})
 
+		It("should return a version conflict error with the current invitation if the version is stale", func() {
+			currentInvitation := &domain.Invitation{
+				BaseInvitation: baseInvitation,
+				ID:             1,
+				PrivateID:      "some-private-id",
+				Status:         domain.Sent,
+				Version:        2,
+			}
+
+			gomock.InOrder(
+				mockInvitationStorage.EXPECT().FindInvitationByID(int64(1)).Return(currentInvitation, nil),
+				mockInvitationStorage.EXPECT().UpdateInvitation(gomock.Any()).Times(0),
+			)
+
+			updatedInvitation, err := testInvitationService.UpdateInvitation(updateReq)
+			Expect(err).To(HaveOccurred())
+			Expect(err).To(BeAssignableToTypeOf(InvitationVersionConflictError{}))
+			Expect(err.Error()).To(Equal("invitation has been modified since it was retrieved"))
+			Expect(err.(InvitationVersionConflictError).Current).To(Equal(currentInvitation))
+			Expect(updatedInvitation).To(BeNil())
+		})
+
+		It("should return a version conflict error with the current invitation if it changes before it is written", func() {
+			invitation := &domain.Invitation{
+				BaseInvitation: baseInvitation,
+				ID:             1,
+				PrivateID:      "some-private-id",
+				Status:         domain.NotSent,
+				Version:        1,
+			}
+			currentInvitation := &domain.Invitation{
+				BaseInvitation: baseInvitation,
+				ID:             1,
+				PrivateID:      "some-private-id",
+				Status:         domain.Sent,
+				Version:        2,
+			}
+
+			gomock.InOrder(
+				mockInvitationStorage.EXPECT().FindInvitationByID(int64(1)).Return(invitation, nil),
+				mockInvitationStorage.EXPECT().UpdateInvitation(invitation).Return(
+					nil, storage.NewStorageVersionConflictError()),
+				mockInvitationStorage.EXPECT().FindInvitationByID(int64(1)).Return(currentInvitation, nil),
+			)
+
+			updatedInvitation, err := testInvitationService.UpdateInvitation(updateReq)
+			Expect(err).To(BeAssignableToTypeOf(InvitationVersionConflictError{}))
+			Expect(err.(InvitationVersionConflictError).Current).To(Equal(currentInvitation))
+			Expect(updatedInvitation).To(BeNil())
+		})
+
 		It("should not allow invitations with duplicate greetings", func() {
 			invitation := &domain.Invitation{
 				BaseInvitation: baseInvitation,
 				ID:             1,
 				PrivateID:      "some-private-id",
 				Status:         domain.NotSent,
+				Version:        1,
 			}
 
 			gomock.InOrder(
 				mockInvitationStorage.EXPECT().FindInvitationByID(int64(1)).Return(invitation, nil),
 				mockInvitationStorage.EXPECT().UpdateInvitation(invitation).Return(
-					nil, postgres.NewPostgresInvitationGreetingUniqueConstraintError()),
+					nil, storage.NewStorageInvitationGreetingUniqueConstraintError()),
 			)
 
 			duplicateInvitation, err := testInvitationService.UpdateInvitation(updateReq)
@@ -503,6 +628,19 @@ var _ = Describe("Invitation", func() {
 		// 	Expect(updatedInvitation).To(BeNil())
 		// })
 
+		It("should return an error if version is missing", func() {
+			// Validation should catch it before any attempt to storage is made
+			mockInvitationStorage.EXPECT().FindInvitationByID(int64(1)).Times(0)
+
+			updateReq.Version = 0
+
+			updatedInvitation, err := testInvitationService.UpdateInvitation(updateReq)
+			Expect(err).To(HaveOccurred())
+			Expect(err).To(BeAssignableToTypeOf(serviceErrors.ValidationError{}))
+			Expect(err.Error()).To(Equal("invitation version is invalid"))
+			Expect(updatedInvitation).To(BeNil())
+		})
+
 		It("should return an error if status is invalid", func() {
 			// Validation should catch it before any attempt to storage is made
 			mockInvitationStorage.EXPECT().FindInvitationByID(int64(1)).Times(0)
@@ -536,6 +674,7 @@ var _ = Describe("Invitation", func() {
 			gomock.InOrder(
 				mockInvitationStorage.EXPECT().FindInvitationByID(int64(1)).Return(invitation, nil),
 				mockInvitationStorage.EXPECT().DeleteInvitation(invitation).Return(nil),
+				mockInvitationStorage.EXPECT().InsertAuditEntry(gomock.Any()).Return(&domain.AuditEntry{}, nil),
 			)
 
 			err := testInvitationService.DeleteInvitationByID(1)
@@ -544,11 +683,80 @@ var _ = Describe("Invitation", func() {
 
 		It("should return an error if the invitation cannot be found", func() {
 			mockInvitationStorage.EXPECT().FindInvitationByID(int64(123123123)).Return(
-				nil, postgres.NewPostgresRecordNotFoundError())
+				nil, storage.NewStorageRecordNotFoundError())
 
 			err := testInvitationService.DeleteInvitationByID(123123123)
 			Expect(err).To(HaveOccurred())
 			Expect(err).To(BeAssignableToTypeOf(InvitationNotFoundError{}))
 		})
 	})
+
+	Context("restoring", func() {
+
+		var invitation *domain.Invitation
+
+		BeforeEach(func() {
+			invitation = &domain.Invitation{
+				BaseInvitation: domain.BaseInvitation{
+					CategoryID:        1,
+					Greeting:          "ah ma and ah gong",
+					MaximumGuestCount: 2,
+					Notes:             "some notes",
+					MobilePhoneNumber: "91231234",
+				},
+				ID:        1,
+				PrivateID: "some-private-id",
+				Status:    domain.NotSent,
+				DeletedAt: "2026-10-17T10:00:00Z",
+			}
+		})
+
+		It("should restore a deleted invitation", func() {
+			restoredInvitation := *invitation
+			restoredInvitation.DeletedAt = ""
+
+			gomock.InOrder(
+				mockInvitationStorage.EXPECT().FindDeletedInvitationByID(int64(1)).Return(invitation, nil),
+				mockInvitationStorage.EXPECT().FindCategoryByID(int64(1)).Return(&domain.Category{ID: 1}, nil),
+				mockInvitationStorage.EXPECT().RestoreInvitation(invitation).Return(&restoredInvitation, nil),
+				mockInvitationStorage.EXPECT().InsertAuditEntry(gomock.Any()).Return(&domain.AuditEntry{}, nil),
+			)
+
+			result, err := testInvitationService.RestoreInvitationByID(1)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result).To(Equal(&restoredInvitation))
+		})
+
+		It("should not restore an invitation whose category is not restored", func() {
+			gomock.InOrder(
+				mockInvitationStorage.EXPECT().FindDeletedInvitationByID(int64(1)).Return(invitation, nil),
+				mockInvitationStorage.EXPECT().FindCategoryByID(int64(1)).Return(
+					nil, storage.NewStorageRecordNotFoundError()),
+			)
+
+			_, err := testInvitationService.RestoreInvitationByID(1)
+			Expect(err).To(BeAssignableToTypeOf(serviceErrors.ValidationError{}))
+			Expect(err.Error()).To(Equal("category of the invitation must be restored first"))
+		})
+
+		It("should not restore an invitation whose greeting has been taken since it was deleted", func() {
+			gomock.InOrder(
+				mockInvitationStorage.EXPECT().FindDeletedInvitationByID(int64(1)).Return(invitation, nil),
+				mockInvitationStorage.EXPECT().FindCategoryByID(int64(1)).Return(&domain.Category{ID: 1}, nil),
+				mockInvitationStorage.EXPECT().RestoreInvitation(invitation).Return(
+					nil, storage.NewStorageInvitationGreetingUniqueConstraintError()),
+			)
+
+			_, err := testInvitationService.RestoreInvitationByID(1)
+			Expect(err).To(BeAssignableToTypeOf(serviceErrors.ValidationError{}))
+		})
+
+		It("should return an error if the invitation is not in the trash", func() {
+			mockInvitationStorage.EXPECT().FindDeletedInvitationByID(int64(123123123)).Return(
+				nil, storage.NewStorageRecordNotFoundError())
+
+			_, err := testInvitationService.RestoreInvitationByID(123123123)
+			Expect(err).To(BeAssignableToTypeOf(InvitationNotFoundError{}))
+		})
+	})
 })
@@ -0,0 +1,265 @@
+package invitation
+
+import (
+	"strings"
+	"time"
+
+	"github.com/rawfish-dev/rsvp-starter/server/domain"
+	"github.com/rawfish-dev/rsvp-starter/server/interfaces"
+	"github.com/rawfish-dev/rsvp-starter/server/services/audit"
+	serviceErrors "github.com/rawfish-dev/rsvp-starter/server/services/errors"
+	"github.com/rawfish-dev/rsvp-starter/server/services/storage"
+
+	"github.com/satori/go.uuid"
+)
+
+// UpdateInvitationLink changes when the guest link expires and whether guests must confirm the mobile
+// phone number of the invitation
+func (s *service) UpdateInvitationLink(req *domain.InvitationLinkUpdateRequest) (*domain.Invitation, error) {
+	errorMessages := validateInvitationLinkUpdateRequest(req)
+	if len(errorMessages) > 0 {
+		return nil, serviceErrors.NewValidationError(errorMessages)
+	}
+
+	var updatedInvitation *domain.Invitation
+
+	err := s.invitationStorage.WithTx(func(tx interfaces.Storage) error {
+		invitation, err := tx.FindInvitationByID(req.ID)
+		if err != nil {
+			switch err.(type) {
+			case storage.StorageRecordNotFoundError:
+				return NewInvitationNotFoundError()
+			}
+
+			return serviceErrors.NewGeneralServiceError()
+		}
+
+		if invitation.Version != req.Version {
+			return NewInvitationVersionConflictError(invitation)
+		}
+
+		if req.RequirePhoneConfirmation && len(phoneDigits(invitation.MobilePhoneNumber)) < MobilePhoneNumberMinLength {
+			errorMessage := []string{"invitation mobile phone number must be set before guests can be asked to confirm it"}
+			return serviceErrors.NewValidationError(errorMessage)
+		}
+
+		before := *invitation
+		invitation.LinkExpiresAt = req.LinkExpiresAt
+		invitation.RequirePhoneConfirmation = req.RequirePhoneConfirmation
+
+		updatedInvitation, err = tx.UpdateInvitation(invitation)
+		if err != nil {
+			switch err.(type) {
+			case storage.StorageVersionConflictError:
+				return s.invitationVersionConflict(tx, req.ID)
+			}
+
+			return serviceErrors.NewGeneralServiceError()
+		}
+
+		return audit.Record(s.ctx, tx, domain.AuditUpdated, domain.InvitationAuditEntity, invitation.ID, before, updatedInvitation)
+	})
+	if err != nil {
+		return nil, serviceErrors.FromTransaction(err)
+	}
+
+	return updatedInvitation, nil
+}
+
+// RevokeInvitationLinkByID stops the guest link working until it is rotated. Revoking a link twice
+// leaves it as it was.
+func (s *service) RevokeInvitationLinkByID(invitationID int64) (*domain.Invitation, error) {
+	var revokedInvitation *domain.Invitation
+
+	err := s.invitationStorage.WithTx(func(tx interfaces.Storage) error {
+		invitation, err := tx.FindInvitationByID(invitationID)
+		if err != nil {
+			switch err.(type) {
+			case storage.StorageRecordNotFoundError:
+				return NewInvitationNotFoundError()
+			}
+
+			return serviceErrors.NewGeneralServiceError()
+		}
+
+		if invitation.LinkRevokedAt != "" {
+			revokedInvitation = invitation
+			return nil
+		}
+
+		before := *invitation
+		invitation.LinkRevokedAt = time.Now().UTC().Format(time.RFC3339)
+
+		revokedInvitation, err = tx.UpdateInvitation(invitation)
+		if err != nil {
+			return serviceErrors.NewGeneralServiceError()
+		}
+
+		return audit.Record(s.ctx, tx, domain.AuditRevoked, domain.InvitationAuditEntity, invitation.ID, before, revokedInvitation)
+	})
+	if err != nil {
+		return nil, serviceErrors.FromTransaction(err)
+	}
+
+	return revokedInvitation, nil
+}
+
+// RotateInvitationLinkByID gives the invitation a new private id, which also lifts any revocation. The
+// rsvp moves over to the new private id while the old one is kept so that it answers as gone.
+func (s *service) RotateInvitationLinkByID(invitationID int64) (*domain.Invitation, error) {
+	var rotatedInvitation *domain.Invitation
+
+	err := s.invitationStorage.WithTx(func(tx interfaces.Storage) error {
+		invitation, err := tx.FindInvitationByID(invitationID)
+		if err != nil {
+			switch err.(type) {
+			case storage.StorageRecordNotFoundError:
+				return NewInvitationNotFoundError()
+			}
+
+			return serviceErrors.NewGeneralServiceError()
+		}
+
+		before := *invitation
+
+		err = tx.InsertRetiredInvitationLink(invitation.ID, invitation.PrivateID)
+		if err != nil {
+			return serviceErrors.NewGeneralServiceError()
+		}
+
+		invitation.PrivateID = uuid.NewV4().String()
+		invitation.LinkRevokedAt = ""
+
+		rotatedInvitation, err = tx.UpdateInvitation(invitation)
+		if err != nil {
+			return serviceErrors.NewGeneralServiceError()
+		}
+
+		rsvp, err := tx.FindRSVPByInvitationPrivateID(before.PrivateID)
+		switch err.(type) {
+		case nil:
+			rsvp.InvitationPrivateID = rotatedInvitation.PrivateID
+
+			_, err = tx.UpdateRSVP(rsvp)
+			if err != nil {
+				return serviceErrors.NewGeneralServiceError()
+			}
+		case storage.StorageRecordNotFoundError:
+			// The guests have not replied yet
+		default:
+			return serviceErrors.NewGeneralServiceError()
+		}
+
+		return audit.Record(s.ctx, tx, domain.AuditUpdated, domain.InvitationAuditEntity, invitation.ID, before, rotatedInvitation)
+	})
+	if err != nil {
+		return nil, serviceErrors.FromTransaction(err)
+	}
+
+	return rotatedInvitation, nil
+}
+
+// AuthorizeGuest returns the invitation behind a guest link as long as the link still works and, when
+// the invitation asks for it, the guest gave its mobile phone number
+func (s *service) AuthorizeGuest(privateID, mobilePhoneNumber string) (*domain.Invitation, error) {
+	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
+
+	invitation, err := s.invitationStorage.FindInvitationByPrivateID(privateID)
+	if err != nil {
+		switch err.(type) {
+		case storage.StorageRecordNotFoundError:
+			retired, err := s.invitationStorage.IsInvitationLinkRetired(privateID)
+			if err != nil {
+				return nil, serviceErrors.NewGeneralServiceError()
+			}
+			if retired {
+				ctxLogger.Warnf("invitation service - unable to authorize guest as private id %v was rotated", privateID)
+				return nil, NewInvitationLinkGoneError()
+			}
+
+			return nil, NewInvitationNotFoundError()
+		}
+
+		return nil, serviceErrors.NewGeneralServiceError()
+	}
+
+	goneMessage, err := linkGoneMessage(invitation, time.Now())
+	if err != nil {
+		ctxLogger.Errorf("invitation service - unable to authorize guest due to %v", err)
+		return nil, serviceErrors.NewGeneralServiceError()
+	}
+	if goneMessage != "" {
+		ctxLogger.Warnf("invitation service - unable to authorize guest of invitation %v as the %v", invitation.ID, goneMessage)
+		return nil, NewInvitationLinkGoneError()
+	}
+
+	if invitation.RequirePhoneConfirmation && !phoneNumbersMatch(invitation.MobilePhoneNumber, mobilePhoneNumber) {
+		return nil, NewInvitationPhoneConfirmationRequiredError()
+	}
+
+	return invitation, nil
+}
+
+// linkGoneMessage tells why guests can no longer reply through the invitation link at the given time,
+// and is empty while the link still works
+func linkGoneMessage(invitation *domain.Invitation, at time.Time) (string, error) {
+	if invitation.LinkRevokedAt != "" {
+		return "invitation link was revoked", nil
+	}
+
+	if invitation.LinkExpiresAt != "" {
+		linkExpiresAt, err := time.Parse(time.RFC3339, invitation.LinkExpiresAt)
+		if err != nil {
+			return "", err
+		}
+
+		if !at.Before(linkExpiresAt) {
+			return "invitation link has expired", nil
+		}
+	}
+
+	return "", nil
+}
+
+// phoneNumbersMatch ignores spaces, dashes and the like, and lets guests leave out the country code
+// as long as enough of the number is left to be worth guessing
+func phoneNumbersMatch(expected, given string) bool {
+	expectedDigits := phoneDigits(expected)
+	givenDigits := phoneDigits(given)
+
+	if len(givenDigits) < MobilePhoneNumberMinLength || len(expectedDigits) < MobilePhoneNumberMinLength {
+		return false
+	}
+
+	return strings.HasSuffix(expectedDigits, givenDigits) || strings.HasSuffix(givenDigits, expectedDigits)
+}
+
+func phoneDigits(phoneNumber string) string {
+	return strings.Map(func(r rune) rune {
+		if r < '0' || r > '9' {
+			return -1
+		}
+
+		return r
+	}, phoneNumber)
+}
+
+func validateInvitationLinkUpdateRequest(req *domain.InvitationLinkUpdateRequest) (errorMessages []string) {
+	if req.ID <= 0 {
+		errorMessages = append(errorMessages, "invitation id is invalid")
+	}
+	if req.Version <= 0 {
+		errorMessages = append(errorMessages, "invitation version is invalid")
+	}
+
+	if req.LinkExpiresAt != "" {
+		linkExpiresAt, err := time.Parse(time.RFC3339, req.LinkExpiresAt)
+		if err != nil {
+			errorMessages = append(errorMessages, "invitation link expiry must be in RFC 3339")
+		} else {
+			req.LinkExpiresAt = linkExpiresAt.UTC().Format(time.RFC3339)
+		}
+	}
+
+	return errorMessages
+}
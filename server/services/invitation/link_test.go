@@ -0,0 +1,278 @@
+package invitation_test
+
+import (
+	"time"
+
+	"github.com/rawfish-dev/rsvp-starter/server/config"
+	"github.com/rawfish-dev/rsvp-starter/server/domain"
+	"github.com/rawfish-dev/rsvp-starter/server/interfaces"
+	"github.com/rawfish-dev/rsvp-starter/server/mock"
+	serviceErrors "github.com/rawfish-dev/rsvp-starter/server/services/errors"
+	. "github.com/rawfish-dev/rsvp-starter/server/services/invitation"
+	"github.com/rawfish-dev/rsvp-starter/server/services/storage"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"golang.org/x/net/context"
+)
+
+var _ = Describe("Invitation links", func() {
+
+	var ctrl *gomock.Controller
+	var mockInvitationStorage *mock_interfaces.MockTransactionalStorage
+	var testInvitationService interfaces.InvitationServiceProvider
+	var invitation *domain.Invitation
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+
+		ctxlogger := logrus.New()
+		ctx := context.Background()
+		ctx = context.WithValue(ctx, "logger", ctxlogger)
+
+		mockInvitationStorage = mock_interfaces.NewMockTransactionalStorage(ctrl)
+		testInvitationService = NewService(ctx, config.MessagingConfig{}, config.ReminderConfig{}, mock_interfaces.NewMockMessagingProvider(ctrl), mock_interfaces.NewMockEmailSender(ctrl), mockInvitationStorage)
+
+		invitation = &domain.Invitation{
+			BaseInvitation: domain.BaseInvitation{
+				CategoryID:        1,
+				Greeting:          "ah ma and ah gong",
+				MaximumGuestCount: 2,
+				MobilePhoneNumber: "+65 9123 1234",
+			},
+			ID:        1,
+			PrivateID: "some-private-id",
+			Status:    domain.Sent,
+			Version:   1,
+		}
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	Context("settings", func() {
+
+		var linkUpdateReq *domain.InvitationLinkUpdateRequest
+
+		BeforeEach(func() {
+			linkUpdateReq = &domain.InvitationLinkUpdateRequest{
+				ID:                       1,
+				LinkExpiresAt:            "2030-01-02T11:04:05+08:00",
+				RequirePhoneConfirmation: true,
+				Version:                  1,
+			}
+		})
+
+		It("should set the link expiry in UTC and require phone confirmation", func() {
+			gomock.InOrder(
+				mockInvitationStorage.EXPECT().FindInvitationByID(int64(1)).Return(invitation, nil),
+				mockInvitationStorage.EXPECT().UpdateInvitation(gomock.Any()).Do(func(updated *domain.Invitation) {
+					Expect(updated.LinkExpiresAt).To(Equal("2030-01-02T03:04:05Z"))
+					Expect(updated.RequirePhoneConfirmation).To(BeTrue())
+					Expect(updated.PrivateID).To(Equal("some-private-id"))
+				}).Return(invitation, nil),
+				mockInvitationStorage.EXPECT().InsertAuditEntry(gomock.Any()).Return(&domain.AuditEntry{}, nil),
+			)
+
+			updatedInvitation, err := testInvitationService.UpdateInvitationLink(linkUpdateReq)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(updatedInvitation.LinkExpiresAt).To(Equal("2030-01-02T03:04:05Z"))
+		})
+
+		It("should return an error if the link expiry is not in RFC 3339", func() {
+			linkUpdateReq.LinkExpiresAt = "2030-01-02"
+
+			updatedInvitation, err := testInvitationService.UpdateInvitationLink(linkUpdateReq)
+			Expect(err).To(BeAssignableToTypeOf(serviceErrors.ValidationError{}))
+			Expect(err.Error()).To(ContainSubstring("invitation link expiry must be in RFC 3339"))
+			Expect(updatedInvitation).To(BeNil())
+		})
+
+		It("should return an error if phone confirmation is required of an invitation without a mobile phone number", func() {
+			invitation.MobilePhoneNumber = "+65"
+
+			gomock.InOrder(
+				mockInvitationStorage.EXPECT().FindInvitationByID(int64(1)).Return(invitation, nil),
+				mockInvitationStorage.EXPECT().UpdateInvitation(gomock.Any()).Times(0),
+			)
+
+			updatedInvitation, err := testInvitationService.UpdateInvitationLink(linkUpdateReq)
+			Expect(err).To(BeAssignableToTypeOf(serviceErrors.ValidationError{}))
+			Expect(err.Error()).To(ContainSubstring("invitation mobile phone number must be set"))
+			Expect(updatedInvitation).To(BeNil())
+		})
+
+		It("should return a version conflict error with the current invitation if the version is stale", func() {
+			invitation.Version = 2
+
+			mockInvitationStorage.EXPECT().FindInvitationByID(int64(1)).Return(invitation, nil)
+
+			updatedInvitation, err := testInvitationService.UpdateInvitationLink(linkUpdateReq)
+			Expect(err).To(BeAssignableToTypeOf(InvitationVersionConflictError{}))
+			Expect(updatedInvitation).To(BeNil())
+		})
+	})
+
+	Context("revoking", func() {
+
+		It("should revoke the link", func() {
+			gomock.InOrder(
+				mockInvitationStorage.EXPECT().FindInvitationByID(int64(1)).Return(invitation, nil),
+				mockInvitationStorage.EXPECT().UpdateInvitation(invitation).Return(invitation, nil),
+				mockInvitationStorage.EXPECT().InsertAuditEntry(gomock.Any()).Do(func(req *domain.AuditEntryCreateRequest) {
+					Expect(req.Action).To(Equal(domain.AuditRevoked))
+				}).Return(&domain.AuditEntry{}, nil),
+			)
+
+			revokedInvitation, err := testInvitationService.RevokeInvitationLinkByID(1)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(revokedInvitation.LinkRevokedAt).ToNot(BeEmpty())
+		})
+
+		It("should leave a link that is already revoked as it was", func() {
+			invitation.LinkRevokedAt = "2026-01-02T03:04:05Z"
+
+			gomock.InOrder(
+				mockInvitationStorage.EXPECT().FindInvitationByID(int64(1)).Return(invitation, nil),
+				mockInvitationStorage.EXPECT().UpdateInvitation(gomock.Any()).Times(0),
+			)
+
+			revokedInvitation, err := testInvitationService.RevokeInvitationLinkByID(1)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(revokedInvitation.LinkRevokedAt).To(Equal("2026-01-02T03:04:05Z"))
+		})
+
+		It("should return an error if the invitation cannot be found", func() {
+			mockInvitationStorage.EXPECT().FindInvitationByID(int64(1)).Return(nil, storage.NewStorageRecordNotFoundError())
+
+			revokedInvitation, err := testInvitationService.RevokeInvitationLinkByID(1)
+			Expect(err).To(BeAssignableToTypeOf(InvitationNotFoundError{}))
+			Expect(revokedInvitation).To(BeNil())
+		})
+	})
+
+	Context("rotating", func() {
+
+		It("should retire the private id, lift the revocation and move the rsvp to the new private id", func() {
+			invitation.LinkRevokedAt = "2026-01-02T03:04:05Z"
+
+			var newPrivateID string
+			rsvp := &domain.RSVP{ID: 2, InvitationPrivateID: "some-private-id"}
+
+			gomock.InOrder(
+				mockInvitationStorage.EXPECT().FindInvitationByID(int64(1)).Return(invitation, nil),
+				mockInvitationStorage.EXPECT().InsertRetiredInvitationLink(int64(1), "some-private-id").Return(nil),
+				mockInvitationStorage.EXPECT().UpdateInvitation(gomock.Any()).Do(func(updated *domain.Invitation) {
+					newPrivateID = updated.PrivateID
+					Expect(newPrivateID).ToNot(Equal("some-private-id"))
+					Expect(updated.LinkRevokedAt).To(BeEmpty())
+				}).Return(invitation, nil),
+				mockInvitationStorage.EXPECT().FindRSVPByInvitationPrivateID("some-private-id").Return(rsvp, nil),
+				mockInvitationStorage.EXPECT().UpdateRSVP(rsvp).Do(func(updated *domain.RSVP) {
+					Expect(updated.InvitationPrivateID).To(Equal(newPrivateID))
+				}).Return(rsvp, nil),
+				mockInvitationStorage.EXPECT().InsertAuditEntry(gomock.Any()).Return(&domain.AuditEntry{}, nil),
+			)
+
+			rotatedInvitation, err := testInvitationService.RotateInvitationLinkByID(1)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(rotatedInvitation.PrivateID).To(Equal(newPrivateID))
+		})
+
+		It("should rotate the link of invitations the guests have not replied to", func() {
+			gomock.InOrder(
+				mockInvitationStorage.EXPECT().FindInvitationByID(int64(1)).Return(invitation, nil),
+				mockInvitationStorage.EXPECT().InsertRetiredInvitationLink(int64(1), "some-private-id").Return(nil),
+				mockInvitationStorage.EXPECT().UpdateInvitation(invitation).Return(invitation, nil),
+				mockInvitationStorage.EXPECT().FindRSVPByInvitationPrivateID("some-private-id").Return(nil, storage.NewStorageRecordNotFoundError()),
+				mockInvitationStorage.EXPECT().InsertAuditEntry(gomock.Any()).Return(&domain.AuditEntry{}, nil),
+			)
+
+			_, err := testInvitationService.RotateInvitationLinkByID(1)
+			Expect(err).ToNot(HaveOccurred())
+		})
+	})
+
+	Context("guest authorization", func() {
+
+		It("should return the invitation of a working link", func() {
+			invitation.LinkExpiresAt = time.Now().Add(time.Hour).UTC().Format(time.RFC3339)
+
+			mockInvitationStorage.EXPECT().FindInvitationByPrivateID("some-private-id").Return(invitation, nil)
+
+			guestInvitation, err := testInvitationService.AuthorizeGuest("some-private-id", "")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(guestInvitation).To(Equal(invitation))
+		})
+
+		It("should return a not found error for private ids that never existed", func() {
+			gomock.InOrder(
+				mockInvitationStorage.EXPECT().FindInvitationByPrivateID("unknown").Return(nil, storage.NewStorageRecordNotFoundError()),
+				mockInvitationStorage.EXPECT().IsInvitationLinkRetired("unknown").Return(false, nil),
+			)
+
+			_, err := testInvitationService.AuthorizeGuest("unknown", "")
+			Expect(err).To(BeAssignableToTypeOf(InvitationNotFoundError{}))
+		})
+
+		It("should return a gone error for private ids that were rotated", func() {
+			gomock.InOrder(
+				mockInvitationStorage.EXPECT().FindInvitationByPrivateID("old-private-id").Return(nil, storage.NewStorageRecordNotFoundError()),
+				mockInvitationStorage.EXPECT().IsInvitationLinkRetired("old-private-id").Return(true, nil),
+			)
+
+			_, err := testInvitationService.AuthorizeGuest("old-private-id", "")
+			Expect(err).To(BeAssignableToTypeOf(InvitationLinkGoneError{}))
+		})
+
+		It("should return a gone error for revoked links", func() {
+			invitation.LinkRevokedAt = "2026-01-02T03:04:05Z"
+
+			mockInvitationStorage.EXPECT().FindInvitationByPrivateID("some-private-id").Return(invitation, nil)
+
+			_, err := testInvitationService.AuthorizeGuest("some-private-id", "")
+			Expect(err).To(BeAssignableToTypeOf(InvitationLinkGoneError{}))
+		})
+
+		It("should return a gone error for expired links", func() {
+			invitation.LinkExpiresAt = time.Now().Add(-time.Minute).UTC().Format(time.RFC3339)
+
+			mockInvitationStorage.EXPECT().FindInvitationByPrivateID("some-private-id").Return(invitation, nil)
+
+			_, err := testInvitationService.AuthorizeGuest("some-private-id", "")
+			Expect(err).To(BeAssignableToTypeOf(InvitationLinkGoneError{}))
+		})
+
+		Context("with phone confirmation", func() {
+
+			BeforeEach(func() {
+				invitation.RequirePhoneConfirmation = true
+
+				mockInvitationStorage.EXPECT().FindInvitationByPrivateID("some-private-id").Return(invitation, nil)
+			})
+
+			It("should ask for the mobile phone number", func() {
+				_, err := testInvitationService.AuthorizeGuest("some-private-id", "")
+				Expect(err).To(BeAssignableToTypeOf(InvitationPhoneConfirmationRequiredError{}))
+			})
+
+			It("should accept the mobile phone number written differently or without the country code", func() {
+				_, err := testInvitationService.AuthorizeGuest("some-private-id", "9123-1234")
+				Expect(err).ToNot(HaveOccurred())
+			})
+
+			It("should reject an incorrect mobile phone number", func() {
+				_, err := testInvitationService.AuthorizeGuest("some-private-id", "98769876")
+				Expect(err).To(BeAssignableToTypeOf(InvitationPhoneConfirmationRequiredError{}))
+			})
+
+			It("should reject just the last few digits of the mobile phone number", func() {
+				_, err := testInvitationService.AuthorizeGuest("some-private-id", "1234")
+				Expect(err).To(BeAssignableToTypeOf(InvitationPhoneConfirmationRequiredError{}))
+			})
+		})
+	})
+})
@@ -0,0 +1,252 @@
+package invitation
+
+import (
+	"sort"
+	"time"
+
+	"github.com/rawfish-dev/rsvp-starter/server/domain"
+	"github.com/rawfish-dev/rsvp-starter/server/interfaces"
+	serviceErrors "github.com/rawfish-dev/rsvp-starter/server/services/errors"
+	"github.com/rawfish-dev/rsvp-starter/server/services/storage"
+)
+
+// scheduledReminder is the next reminder of an invitation and the earliest it can be sent
+type scheduledReminder struct {
+	invitation     domain.Invitation
+	reminderNumber int
+	dueAt          time.Time
+}
+
+func (s *service) ListReminders() (*domain.ReminderList, error) {
+	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
+
+	reminders, err := s.scheduleReminders(time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	sentReminders, err := s.invitationStorage.ListMessagesByKind(domain.ReminderMessage)
+	if err != nil {
+		ctxLogger.Error("invitation service - unable to list sent reminders")
+		return nil, serviceErrors.NewGeneralServiceError()
+	}
+
+	reminderList := &domain.ReminderList{
+		Enabled:  s.reminderConfig.Interval > 0,
+		Upcoming: make([]domain.UpcomingReminder, len(reminders)),
+		Sent:     sentReminders,
+	}
+	if reminderList.Sent == nil {
+		reminderList.Sent = []domain.Message{}
+	}
+
+	for idx, reminder := range reminders {
+		reminderList.Upcoming[idx] = domain.UpcomingReminder{
+			InvitationID:   reminder.invitation.ID,
+			Greeting:       reminder.invitation.Greeting,
+			ReminderNumber: reminder.reminderNumber,
+			DueAt:          reminder.dueAt.UTC().Format(time.RFC3339),
+		}
+	}
+
+	return reminderList, nil
+}
+
+// SendDueReminders reminds the guests of every invitation whose next reminder is due by now. Nothing is
+// sent during quiet hours, so reminders that fell due then go out on the first run after.
+func (s *service) SendDueReminders(now time.Time) (*domain.ReminderSendResult, error) {
+	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
+
+	sendResult := &domain.ReminderSendResult{}
+
+	if !s.outsideQuietHours(now).Equal(now) {
+		return sendResult, nil
+	}
+
+	reminders, err := s.scheduleReminders(now)
+	if err != nil {
+		return nil, err
+	}
+
+	for idx := range reminders {
+		reminder := &reminders[idx]
+		if reminder.dueAt.After(now) {
+			continue
+		}
+
+		// Read the invitation again as its link may have been revoked or the guests may have replied
+		// while the reminders before it were being sent
+		invitation, err := s.invitationStorage.FindInvitationByID(reminder.invitation.ID)
+		switch err.(type) {
+		case nil:
+		case storage.StorageRecordNotFoundError:
+			continue
+		default:
+			return nil, serviceErrors.NewGeneralServiceError()
+		}
+
+		if invitation.Status != domain.Sent {
+			continue
+		}
+
+		goneMessage, err := linkGoneMessage(invitation, now)
+		if err != nil {
+			ctxLogger.Errorf("invitation service - unable to read when the link of invitation %v expires due to %v", invitation.ID, err)
+			return nil, serviceErrors.NewGeneralServiceError()
+		}
+		if goneMessage != "" {
+			ctxLogger.Infof("invitation service - not reminding invitation %v as the %v", invitation.ID, goneMessage)
+			continue
+		}
+
+		_, err = s.send(invitation, reminderWording)
+		switch err.(type) {
+		case nil:
+			sendResult.Sent++
+		case InvitationSendFailedError:
+			sendResult.Failed++
+		default:
+			return nil, err
+		}
+	}
+
+	return sendResult, nil
+}
+
+// scheduleReminders works out the next reminder of every invitation that has been sent but not replied
+// to, soonest first. Reminders are due the reminder delay after the last invitation or reminder that was
+// accepted, leaving out invitations that have had as many reminders as allowed, those that were never
+// sent through the app, or only before message kinds were recorded, and those whose link is revoked or
+// will have expired by the time the reminder is sent. Attempts that were not accepted count towards
+// the reminders allowed so guests with a wrong number are not tried forever.
+func (s *service) scheduleReminders(now time.Time) ([]scheduledReminder, error) {
+	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
+
+	invitations, err := s.listEveryInvitation(domain.InvitationListRequest{Status: domain.Sent})
+	if err != nil {
+		return nil, err
+	}
+
+	invitationMessages, err := s.invitationStorage.ListMessagesByKind(domain.InvitationMessage)
+	if err != nil {
+		ctxLogger.Error("invitation service - unable to list sent invitations")
+		return nil, serviceErrors.NewGeneralServiceError()
+	}
+
+	reminderMessages, err := s.invitationStorage.ListMessagesByKind(domain.ReminderMessage)
+	if err != nil {
+		ctxLogger.Error("invitation service - unable to list sent reminders")
+		return nil, serviceErrors.NewGeneralServiceError()
+	}
+
+	lastSentAt := map[int64]time.Time{}
+	for _, message := range append(invitationMessages, reminderMessages...) {
+		if message.Status == domain.MessageFailed {
+			continue
+		}
+
+		sentAt, err := time.Parse(time.RFC3339, message.CreatedAt)
+		if err != nil {
+			ctxLogger.Errorf("invitation service - unable to read when message %v was sent due to %v", message.ID, err)
+			return nil, serviceErrors.NewGeneralServiceError()
+		}
+
+		if sentAt.After(lastSentAt[message.InvitationID]) {
+			lastSentAt[message.InvitationID] = sentAt
+		}
+	}
+
+	// Each reminder is sent on every channel the invitation has, so the channel with the most reminders
+	// tells how many there have been
+	reminderCounts := map[int64]map[domain.MessageChannel]int{}
+	for _, message := range reminderMessages {
+		if reminderCounts[message.InvitationID] == nil {
+			reminderCounts[message.InvitationID] = map[domain.MessageChannel]int{}
+		}
+		reminderCounts[message.InvitationID][message.Channel]++
+	}
+
+	var reminders []scheduledReminder
+
+	for _, invitation := range invitations {
+		if !hasMobilePhoneNumber(&invitation) && !hasEmail(&invitation) {
+			continue
+		}
+
+		sentAt, ok := lastSentAt[invitation.ID]
+		if !ok {
+			continue
+		}
+
+		reminderCount := 0
+		for _, count := range reminderCounts[invitation.ID] {
+			if count > reminderCount {
+				reminderCount = count
+			}
+		}
+		if reminderCount >= s.reminderConfig.MaxCount {
+			continue
+		}
+
+		dueAt := s.outsideQuietHours(sentAt.Add(s.reminderConfig.Delay))
+
+		sendAt := dueAt
+		if now.After(sendAt) {
+			sendAt = now
+		}
+
+		goneMessage, err := linkGoneMessage(&invitation, sendAt)
+		if err != nil {
+			ctxLogger.Errorf("invitation service - unable to read when the link of invitation %v expires due to %v", invitation.ID, err)
+			return nil, serviceErrors.NewGeneralServiceError()
+		}
+		if goneMessage != "" {
+			continue
+		}
+
+		reminders = append(reminders, scheduledReminder{
+			invitation:     invitation,
+			reminderNumber: reminderCount + 1,
+			dueAt:          dueAt,
+		})
+	}
+
+	sort.Sort(remindersByDueAt(reminders))
+
+	return reminders, nil
+}
+
+// outsideQuietHours moves a time within the quiet hours to when they end. Quiet hours that start later
+// in the day than they end run past midnight, and those that start when they end are turned off.
+func (s *service) outsideQuietHours(t time.Time) time.Time {
+	start, end := s.reminderConfig.QuietHoursStart, s.reminderConfig.QuietHoursEnd
+	if start == end {
+		return t
+	}
+
+	local := t.In(s.reminderConfig.Location)
+	midnight := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, local.Location())
+	sinceMidnight := local.Sub(midnight)
+
+	switch {
+	case start < end && sinceMidnight >= start && sinceMidnight < end:
+		return midnight.Add(end)
+	case start > end && sinceMidnight >= start:
+		return midnight.AddDate(0, 0, 1).Add(end)
+	case start > end && sinceMidnight < end:
+		return midnight.Add(end)
+	}
+
+	return t
+}
+
+type remindersByDueAt []scheduledReminder
+
+func (r remindersByDueAt) Len() int      { return len(r) }
+func (r remindersByDueAt) Swap(i, j int) { r[i], r[j] = r[j], r[i] }
+func (r remindersByDueAt) Less(i, j int) bool {
+	if r[i].dueAt.Equal(r[j].dueAt) {
+		return r[i].invitation.ID < r[j].invitation.ID
+	}
+	return r[i].dueAt.Before(r[j].dueAt)
+}
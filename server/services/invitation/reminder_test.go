@@ -0,0 +1,184 @@
+package invitation_test
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/rawfish-dev/rsvp-starter/server/config"
+	"github.com/rawfish-dev/rsvp-starter/server/domain"
+	"github.com/rawfish-dev/rsvp-starter/server/interfaces"
+	"github.com/rawfish-dev/rsvp-starter/server/mock"
+	. "github.com/rawfish-dev/rsvp-starter/server/services/invitation"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"golang.org/x/net/context"
+)
+
+var _ = Describe("Reminders", func() {
+
+	var ctrl *gomock.Controller
+	var mockInvitationStorage *mock_interfaces.MockTransactionalStorage
+	var mockMessagingProvider *mock_interfaces.MockMessagingProvider
+	var testInvitationService interfaces.InvitationServiceProvider
+	var invitations []domain.Invitation
+	var invitationMessages []domain.Message
+	var reminderMessages []domain.Message
+
+	sentInvitation := func(id int64) domain.Invitation {
+		return domain.Invitation{
+			BaseInvitation: domain.BaseInvitation{
+				CategoryID:        1,
+				Greeting:          fmt.Sprintf("Guest %v", id),
+				MaximumGuestCount: 2,
+				MobilePhoneNumber: "+6591231234",
+			},
+			ID:        id,
+			PrivateID: fmt.Sprintf("private-%v", id),
+			Status:    domain.Sent,
+			Version:   1,
+		}
+	}
+
+	message := func(invitationID int64, kind domain.MessageKind, status domain.MessageStatus, createdAt string) domain.Message {
+		return domain.Message{
+			InvitationID: invitationID,
+			Kind:         kind,
+			Channel:      domain.SMSChannel,
+			Status:       status,
+			CreatedAt:    createdAt,
+		}
+	}
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+
+		ctxlogger := logrus.New()
+		ctx := context.Background()
+		ctx = context.WithValue(ctx, "logger", ctxlogger)
+
+		messagingConfig := config.MessagingConfig{
+			PublicURL: "https://wedding.example.com",
+		}
+		reminderConfig := config.ReminderConfig{
+			Interval:        time.Minute * 15,
+			Delay:           time.Hour * 72,
+			MaxCount:        2,
+			QuietHoursStart: time.Hour * 21,
+			QuietHoursEnd:   time.Hour * 9,
+			Location:        time.UTC,
+		}
+
+		mockInvitationStorage = mock_interfaces.NewMockTransactionalStorage(ctrl)
+		mockMessagingProvider = mock_interfaces.NewMockMessagingProvider(ctrl)
+		mockMessagingProvider.EXPECT().Name().Return("twilio").AnyTimes()
+		testInvitationService = NewService(ctx, messagingConfig, reminderConfig, mockMessagingProvider, mock_interfaces.NewMockEmailSender(ctrl), mockInvitationStorage)
+
+		// 1 is due its first reminder, 2 is due its second during quiet hours, 3 has had every reminder,
+		// 4 was marked as sent by hand and 5 was never accepted by the provider
+		invitations = []domain.Invitation{sentInvitation(1), sentInvitation(2), sentInvitation(3), sentInvitation(4), sentInvitation(5)}
+		invitationMessages = []domain.Message{
+			message(1, domain.InvitationMessage, domain.MessageSent, "2026-10-10T10:00:00Z"),
+			message(2, domain.InvitationMessage, domain.MessageSent, "2026-10-09T10:00:00Z"),
+			message(3, domain.InvitationMessage, domain.MessageSent, "2026-10-01T10:00:00Z"),
+			message(5, domain.InvitationMessage, domain.MessageFailed, "2026-10-01T10:00:00Z"),
+		}
+		reminderMessages = []domain.Message{
+			message(2, domain.ReminderMessage, domain.MessageSent, "2026-10-12T23:00:00Z"),
+			message(3, domain.ReminderMessage, domain.MessageSent, "2026-10-04T10:00:00Z"),
+			message(3, domain.ReminderMessage, domain.MessageFailed, "2026-10-07T10:00:00Z"),
+		}
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	It("should list the upcoming reminders soonest first along with those sent", func() {
+		mockInvitationStorage.EXPECT().ListInvitations(gomock.Any()).Do(func(req *domain.InvitationListRequest) {
+			Expect(req.Status).To(Equal(domain.Sent))
+		}).Return(invitations, len(invitations), nil)
+		mockInvitationStorage.EXPECT().ListMessagesByKind(domain.InvitationMessage).Return(invitationMessages, nil)
+		mockInvitationStorage.EXPECT().ListMessagesByKind(domain.ReminderMessage).Return(reminderMessages, nil).Times(2)
+
+		reminderList, err := testInvitationService.ListReminders()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(reminderList.Enabled).To(BeTrue())
+		Expect(reminderList.Upcoming).To(Equal([]domain.UpcomingReminder{
+			{InvitationID: 1, Greeting: "Guest 1", ReminderNumber: 1, DueAt: "2026-10-13T10:00:00Z"},
+			{InvitationID: 2, Greeting: "Guest 2", ReminderNumber: 2, DueAt: "2026-10-16T09:00:00Z"},
+		}))
+		Expect(reminderList.Sent).To(Equal(reminderMessages))
+	})
+
+	It("should send the reminders that are due without changing the status of the invitation", func() {
+		mockInvitationStorage.EXPECT().ListInvitations(gomock.Any()).Return(invitations, len(invitations), nil)
+		mockInvitationStorage.EXPECT().ListMessagesByKind(domain.InvitationMessage).Return(invitationMessages, nil)
+		mockInvitationStorage.EXPECT().ListMessagesByKind(domain.ReminderMessage).Return(reminderMessages, nil)
+
+		gomock.InOrder(
+			mockInvitationStorage.EXPECT().FindInvitationByID(int64(1)).Return(&invitations[0], nil),
+			mockInvitationStorage.EXPECT().ListMessageTemplates().Return(nil, nil),
+			mockMessagingProvider.EXPECT().SendSMS("+6591231234", "Guest 1, we have not heard from you yet. Let us know if you can make it at https://wedding.example.com/rsvp/private-1").Return("SM123", nil),
+			mockInvitationStorage.EXPECT().InsertMessage(gomock.Any()).Do(func(message *domain.Message) {
+				Expect(message.InvitationID).To(Equal(int64(1)))
+				Expect(message.Kind).To(Equal(domain.ReminderMessage))
+			}).Return(&domain.Message{ID: 10, Status: domain.MessageSent}, nil),
+			mockInvitationStorage.EXPECT().FindInvitationByID(int64(1)).Return(&invitations[0], nil),
+			mockInvitationStorage.EXPECT().UpdateInvitation(gomock.Any()).Times(0),
+			mockInvitationStorage.EXPECT().InsertAuditEntry(gomock.Any()).Do(func(req *domain.AuditEntryCreateRequest) {
+				Expect(req.Action).To(Equal(domain.AuditReminded))
+			}).Return(&domain.AuditEntry{}, nil),
+		)
+
+		sendResult, err := testInvitationService.SendDueReminders(time.Date(2026, 10, 13, 12, 0, 0, 0, time.UTC))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(sendResult.Sent).To(Equal(1))
+		Expect(sendResult.Failed).To(Equal(0))
+	})
+
+	It("should not schedule reminders for invitations whose link is revoked or will have expired", func() {
+		invitations[0].LinkRevokedAt = "2026-10-11T10:00:00Z"
+		invitations[1].LinkExpiresAt = "2026-10-15T00:00:00Z"
+
+		mockInvitationStorage.EXPECT().ListInvitations(gomock.Any()).Return(invitations, len(invitations), nil)
+		mockInvitationStorage.EXPECT().ListMessagesByKind(domain.InvitationMessage).Return(invitationMessages, nil)
+		mockInvitationStorage.EXPECT().ListMessagesByKind(domain.ReminderMessage).Return(reminderMessages, nil).Times(2)
+
+		reminderList, err := testInvitationService.ListReminders()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(reminderList.Upcoming).To(BeEmpty())
+	})
+
+	It("should not send a reminder whose link was revoked after it was scheduled", func() {
+		mockInvitationStorage.EXPECT().ListInvitations(gomock.Any()).Return(invitations, len(invitations), nil)
+		mockInvitationStorage.EXPECT().ListMessagesByKind(domain.InvitationMessage).Return(invitationMessages, nil)
+		mockInvitationStorage.EXPECT().ListMessagesByKind(domain.ReminderMessage).Return(reminderMessages, nil)
+
+		revokedInvitation := invitations[0]
+		revokedInvitation.LinkRevokedAt = "2026-10-13T11:59:00Z"
+		mockInvitationStorage.EXPECT().FindInvitationByID(int64(1)).Return(&revokedInvitation, nil)
+		mockMessagingProvider.EXPECT().SendSMS(gomock.Any(), gomock.Any()).Times(0)
+		mockInvitationStorage.EXPECT().InsertMessage(gomock.Any()).Times(0)
+
+		sendResult, err := testInvitationService.SendDueReminders(time.Date(2026, 10, 13, 12, 0, 0, 0, time.UTC))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(sendResult.Sent).To(Equal(0))
+		Expect(sendResult.Failed).To(Equal(0))
+	})
+
+	It("should not send anything during quiet hours", func() {
+		mockInvitationStorage.EXPECT().ListInvitations(gomock.Any()).Times(0)
+		mockMessagingProvider.EXPECT().SendSMS(gomock.Any(), gomock.Any()).Times(0)
+
+		sendResult, err := testInvitationService.SendDueReminders(time.Date(2026, 10, 13, 22, 0, 0, 0, time.UTC))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(sendResult.Sent).To(Equal(0))
+
+		sendResult, err = testInvitationService.SendDueReminders(time.Date(2026, 10, 14, 8, 59, 0, 0, time.UTC))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(sendResult.Sent).To(Equal(0))
+	})
+})
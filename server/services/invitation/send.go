@@ -0,0 +1,410 @@
+package invitation
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"strings"
+	"time"
+
+	"github.com/rawfish-dev/rsvp-starter/server/domain"
+	"github.com/rawfish-dev/rsvp-starter/server/interfaces"
+	"github.com/rawfish-dev/rsvp-starter/server/services/audit"
+	serviceErrors "github.com/rawfish-dev/rsvp-starter/server/services/errors"
+	"github.com/rawfish-dev/rsvp-starter/server/services/messagetemplate"
+	"github.com/rawfish-dev/rsvp-starter/server/services/storage"
+)
+
+const noContactMessage = "invitation has no mobile phone number or email to send to"
+
+// wording is what an invitation or a reminder says when no message template fits the invitation. The
+// texts are formatted with the greeting and then the RSVP link.
+type wording struct {
+	messageKind  domain.MessageKind
+	templateKind domain.MessageTemplateKind
+	auditAction  domain.AuditAction
+	smsText      string
+	emailSubject string
+	emailText    string
+	emailHTML    *template.Template
+}
+
+var invitationWording = wording{
+	messageKind:  domain.InvitationMessage,
+	templateKind: domain.InvitationTemplate,
+	auditAction:  domain.AuditSent,
+	smsText:      "%v, you're invited! Let us know if you can make it at %v",
+	emailSubject: "You're invited!",
+	emailText:    "%v,\n\nYou're invited! Let us know if you can make it at %v\n",
+	emailHTML: template.Must(template.New("invitation").Parse(`<p>{{.Greeting}},</p>
+<p>You're invited! Let us know if you can make it <a href="{{.Link}}">here</a>.</p>
+<p>If the link does not work, copy this address into your browser: {{.Link}}</p>
+`)),
+}
+
+var reminderWording = wording{
+	messageKind:  domain.ReminderMessage,
+	templateKind: domain.ReminderTemplate,
+	auditAction:  domain.AuditReminded,
+	smsText:      "%v, we have not heard from you yet. Let us know if you can make it at %v",
+	emailSubject: "Will you be joining us?",
+	emailText:    "%v,\n\nWe have not heard from you yet. Let us know if you can make it at %v\n",
+	emailHTML: template.Must(template.New("reminder").Parse(`<p>{{.Greeting}},</p>
+<p>We have not heard from you yet. Let us know if you can make it <a href="{{.Link}}">here</a>.</p>
+<p>If the link does not work, copy this address into your browser: {{.Link}}</p>
+`)),
+}
+
+// SendInvitationByID sends the guest a link to their invitation by text and by email, whichever the
+// invitation has, as long as the link has not been revoked or expired. Invitations that have not been sent yet are marked as sent once any of the messages
+// is accepted.
+func (s *service) SendInvitationByID(invitationID int64) (*domain.InvitationSendResult, error) {
+	invitation, err := s.invitationStorage.FindInvitationByID(invitationID)
+	if err != nil {
+		switch err.(type) {
+		case storage.StorageRecordNotFoundError:
+			return nil, NewInvitationNotFoundError()
+		}
+
+		return nil, serviceErrors.NewGeneralServiceError()
+	}
+
+	if !hasMobilePhoneNumber(invitation) && !hasEmail(invitation) {
+		return nil, serviceErrors.NewValidationError([]string{noContactMessage})
+	}
+
+	goneMessage, err := s.invitationLinkGone(invitation)
+	if err != nil {
+		return nil, err
+	}
+	if goneMessage != "" {
+		return nil, serviceErrors.NewValidationError([]string{goneMessage})
+	}
+
+	messages, err := s.send(invitation, invitationWording)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.InvitationSendResult{InvitationID: invitation.ID, Messages: messages}, nil
+}
+
+// SendInvitations sends every invitation in the category that has not been replied to yet. A failure
+// to send one invitation is recorded in its result rather than stopping the rest from being sent, and
+// invitations without contact details or with a revoked or expired link are skipped.
+func (s *service) SendInvitations(req *domain.InvitationBulkSendRequest) (*domain.InvitationBulkSendResult, error) {
+	if req.CategoryID <= 0 {
+		return nil, serviceErrors.NewValidationError([]string{"category id is required"})
+	}
+
+	_, err := s.invitationStorage.FindCategoryByID(req.CategoryID)
+	if err != nil {
+		switch err.(type) {
+		case storage.StorageRecordNotFoundError:
+			return nil, serviceErrors.NewValidationError([]string{"category does not exist"})
+		}
+
+		return nil, serviceErrors.NewGeneralServiceError()
+	}
+
+	invitations, err := s.listEveryInvitation(domain.InvitationListRequest{CategoryID: req.CategoryID})
+	if err != nil {
+		return nil, err
+	}
+
+	bulkSendResult := &domain.InvitationBulkSendResult{
+		Results: []domain.InvitationSendResult{},
+	}
+
+	for idx := range invitations {
+		invitation := &invitations[idx]
+
+		if invitation.Status != domain.NotSent && !(req.Resend && invitation.Status == domain.Sent) {
+			continue
+		}
+
+		sendResult := domain.InvitationSendResult{InvitationID: invitation.ID}
+
+		if !hasMobilePhoneNumber(invitation) && !hasEmail(invitation) {
+			sendResult.Error = noContactMessage
+			bulkSendResult.Skipped++
+			bulkSendResult.Results = append(bulkSendResult.Results, sendResult)
+			continue
+		}
+
+		goneMessage, err := s.invitationLinkGone(invitation)
+		if err != nil {
+			return nil, err
+		}
+		if goneMessage != "" {
+			sendResult.Error = goneMessage
+			bulkSendResult.Skipped++
+			bulkSendResult.Results = append(bulkSendResult.Results, sendResult)
+			continue
+		}
+
+		messages, err := s.send(invitation, invitationWording)
+		switch err := err.(type) {
+		case nil:
+			bulkSendResult.Sent++
+		case InvitationSendFailedError:
+			sendResult.Error = err.Error()
+			bulkSendResult.Failed++
+		default:
+			return nil, err
+		}
+
+		sendResult.Messages = messages
+		bulkSendResult.Results = append(bulkSendResult.Results, sendResult)
+	}
+
+	return bulkSendResult, nil
+}
+
+func (s *service) ListInvitationMessages(invitationID int64) ([]domain.Message, error) {
+	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
+
+	_, err := s.invitationStorage.FindInvitationByID(invitationID)
+	if err != nil {
+		switch err.(type) {
+		case storage.StorageRecordNotFoundError:
+			return nil, NewInvitationNotFoundError()
+		}
+
+		return nil, serviceErrors.NewGeneralServiceError()
+	}
+
+	messages, err := s.invitationStorage.ListMessagesByInvitationID(invitationID)
+	if err != nil {
+		ctxLogger.Errorf("invitation service - unable to list messages of invitation %v", invitationID)
+		return nil, serviceErrors.NewGeneralServiceError()
+	}
+
+	return messages, nil
+}
+
+// invitationLinkGone tells why the link of an invitation about to be sent no longer works, if it does not
+func (s *service) invitationLinkGone(invitation *domain.Invitation) (string, error) {
+	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
+
+	goneMessage, err := linkGoneMessage(invitation, time.Now())
+	if err != nil {
+		ctxLogger.Errorf("invitation service - unable to read when the link of invitation %v expires due to %v", invitation.ID, err)
+		return "", serviceErrors.NewGeneralServiceError()
+	}
+
+	return goneMessage, nil
+}
+
+// send records every message whether or not it was accepted. The providers are called outside of the
+// transaction so a slow provider does not hold it open.
+func (s *service) send(invitation *domain.Invitation, w wording) ([]domain.Message, error) {
+	var messages []*domain.Message
+	if hasMobilePhoneNumber(invitation) {
+		messages = append(messages, s.sendSMS(invitation, w))
+	}
+	if hasEmail(invitation) {
+		messages = append(messages, s.sendEmail(invitation, w))
+	}
+
+	var failures []string
+	for _, message := range messages {
+		if message.Status == domain.MessageFailed {
+			failures = append(failures, message.Error)
+		}
+	}
+	delivered := len(failures) < len(messages)
+
+	var newMessages []domain.Message
+
+	err := s.invitationStorage.WithTx(func(tx interfaces.Storage) error {
+		for _, message := range messages {
+			newMessage, err := tx.InsertMessage(message)
+			if err != nil {
+				return serviceErrors.NewGeneralServiceError()
+			}
+
+			newMessages = append(newMessages, *newMessage)
+		}
+
+		if !delivered {
+			return nil
+		}
+
+		// Read the invitation again as it may have changed while the messages were being sent
+		sentInvitation, err := tx.FindInvitationByID(invitation.ID)
+		if err != nil {
+			return serviceErrors.NewGeneralServiceError()
+		}
+
+		before := *sentInvitation
+		if w.messageKind == domain.InvitationMessage && sentInvitation.Status == domain.NotSent {
+			sentInvitation.Status = domain.Sent
+
+			sentInvitation, err = tx.UpdateInvitation(sentInvitation)
+			if err != nil {
+				return serviceErrors.NewGeneralServiceError()
+			}
+		}
+
+		return audit.Record(s.ctx, tx, w.auditAction, domain.InvitationAuditEntity, invitation.ID, before, sentInvitation)
+	})
+	if err != nil {
+		return nil, serviceErrors.FromTransaction(err)
+	}
+
+	if !delivered {
+		return newMessages, NewInvitationSendFailedError(newMessages, strings.Join(failures, "; "))
+	}
+
+	return newMessages, nil
+}
+
+func (s *service) sendSMS(invitation *domain.Invitation, w wording) *domain.Message {
+	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
+
+	message := &domain.Message{
+		InvitationID: invitation.ID,
+		Kind:         w.messageKind,
+		Channel:      domain.SMSChannel,
+		Recipient:    invitation.MobilePhoneNumber,
+		Body:         fmt.Sprintf(w.smsText, invitation.Greeting, s.invitationLink(invitation.PrivateID)),
+		Provider:     s.messagingProvider.Name(),
+		Status:       domain.MessageQueued,
+	}
+
+	rendered, err := messagetemplate.Render(s.ctx, s.invitationStorage, w.templateKind, domain.SMSChannel, invitation, s.invitationLink(invitation.PrivateID))
+	if err != nil {
+		ctxLogger.Errorf("invitation service - unable to build %v text for invitation %v due to %v", w.messageKind, invitation.ID, err)
+		message.Status = domain.MessageFailed
+		message.Error = "unable to build the text"
+		return message
+	}
+	if rendered != nil {
+		message.Body = rendered.Body
+	}
+
+	providerMessageID, err := s.messagingProvider.SendSMS(message.Recipient, message.Body)
+	if err != nil {
+		ctxLogger.Warnf("invitation service - unable to text %v for invitation %v due to %v", w.messageKind, invitation.ID, err)
+		message.Status = domain.MessageFailed
+		message.Error = err.Error()
+	}
+	message.ProviderMessageID = providerMessageID
+
+	return message
+}
+
+func (s *service) sendEmail(invitation *domain.Invitation, w wording) *domain.Message {
+	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
+
+	email := &domain.Email{
+		To:       invitation.Email,
+		Subject:  w.emailSubject,
+		TextBody: fmt.Sprintf(w.emailText, invitation.Greeting, s.invitationLink(invitation.PrivateID)),
+	}
+
+	message := &domain.Message{
+		InvitationID: invitation.ID,
+		Kind:         w.messageKind,
+		Channel:      domain.EmailChannel,
+		Recipient:    email.To,
+		Subject:      email.Subject,
+		Body:         email.TextBody,
+		Provider:     s.emailSender.Name(),
+		Status:       domain.MessageQueued,
+	}
+
+	err := s.buildEmail(invitation, email, w)
+	if err != nil {
+		ctxLogger.Errorf("invitation service - unable to build %v email for invitation %v due to %v", w.messageKind, invitation.ID, err)
+		message.Status = domain.MessageFailed
+		message.Error = "unable to build the email"
+		return message
+	}
+	message.Subject = email.Subject
+	message.Body = email.TextBody
+
+	providerMessageID, err := s.emailSender.SendEmail(email)
+	if err != nil {
+		ctxLogger.Warnf("invitation service - unable to email %v for invitation %v due to %v", w.messageKind, invitation.ID, err)
+		message.Status = domain.MessageFailed
+		message.Error = err.Error()
+	}
+	message.ProviderMessageID = providerMessageID
+
+	return message
+}
+
+// buildEmail fills in the email from the template that fits the invitation best, keeping the default
+// subject and wording for whatever the template leaves out
+func (s *service) buildEmail(invitation *domain.Invitation, email *domain.Email, w wording) error {
+	link := s.invitationLink(invitation.PrivateID)
+
+	rendered, err := messagetemplate.Render(s.ctx, s.invitationStorage, w.templateKind, domain.EmailChannel, invitation, link)
+	if err != nil {
+		return err
+	}
+	if rendered != nil {
+		if rendered.Subject != "" {
+			email.Subject = rendered.Subject
+		}
+		email.TextBody = rendered.Body
+		email.HTMLBody = rendered.HTMLBody
+		return nil
+	}
+
+	var html bytes.Buffer
+	err = w.emailHTML.Execute(&html, map[string]string{
+		"Greeting": invitation.Greeting,
+		"Link":     link,
+	})
+	if err != nil {
+		return err
+	}
+	email.HTMLBody = html.String()
+
+	return nil
+}
+
+// listEveryInvitation goes through every page of the invitations matching the filter
+func (s *service) listEveryInvitation(filter domain.InvitationListRequest) ([]domain.Invitation, error) {
+	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
+
+	var everyInvitation []domain.Invitation
+
+	for page := 1; ; page++ {
+		req := filter
+		req.ListRequest = domain.ListRequest{
+			Page:     page,
+			PageSize: domain.MaximumPageSize,
+			Sort:     domain.SortByInvitationGreeting,
+		}
+
+		invitations, total, err := s.invitationStorage.ListInvitations(&req)
+		if err != nil {
+			ctxLogger.Errorf("invitation service - unable to list invitations of category %v with status %v", filter.CategoryID, filter.Status)
+			return nil, serviceErrors.NewGeneralServiceError()
+		}
+
+		everyInvitation = append(everyInvitation, invitations...)
+		if len(invitations) < domain.MaximumPageSize || len(everyInvitation) >= total {
+			return everyInvitation, nil
+		}
+	}
+}
+
+// invitationLink is where guests reply to their invitation
+func (s *service) invitationLink(privateID string) string {
+	return messagetemplate.RSVPLink(s.messagingConfig.PublicURL, privateID)
+}
+
+// hasMobilePhoneNumber leaves out invitations created without a number, which only hold the default
+// extension
+func hasMobilePhoneNumber(invitation *domain.Invitation) bool {
+	return len(phoneDigits(invitation.MobilePhoneNumber)) >= MobilePhoneNumberMinLength
+}
+
+func hasEmail(invitation *domain.Invitation) bool {
+	return invitation.Email != ""
+}
@@ -0,0 +1,390 @@
+package invitation_test
+
+import (
+	"errors"
+
+	"github.com/rawfish-dev/rsvp-starter/server/config"
+	"github.com/rawfish-dev/rsvp-starter/server/domain"
+	"github.com/rawfish-dev/rsvp-starter/server/interfaces"
+	"github.com/rawfish-dev/rsvp-starter/server/mock"
+	serviceErrors "github.com/rawfish-dev/rsvp-starter/server/services/errors"
+	. "github.com/rawfish-dev/rsvp-starter/server/services/invitation"
+	"github.com/rawfish-dev/rsvp-starter/server/services/storage"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"golang.org/x/net/context"
+)
+
+var _ = Describe("Sending invitations", func() {
+
+	var ctrl *gomock.Controller
+	var mockInvitationStorage *mock_interfaces.MockTransactionalStorage
+	var mockMessagingProvider *mock_interfaces.MockMessagingProvider
+	var mockEmailSender *mock_interfaces.MockEmailSender
+	var testInvitationService interfaces.InvitationServiceProvider
+	var invitation *domain.Invitation
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+
+		ctxlogger := logrus.New()
+		ctx := context.Background()
+		ctx = context.WithValue(ctx, "logger", ctxlogger)
+
+		messagingConfig := config.MessagingConfig{
+			PublicURL: "https://wedding.example.com",
+		}
+
+		mockInvitationStorage = mock_interfaces.NewMockTransactionalStorage(ctrl)
+		mockMessagingProvider = mock_interfaces.NewMockMessagingProvider(ctrl)
+		mockMessagingProvider.EXPECT().Name().Return("twilio").AnyTimes()
+		mockEmailSender = mock_interfaces.NewMockEmailSender(ctrl)
+		mockEmailSender.EXPECT().Name().Return("smtp").AnyTimes()
+		testInvitationService = NewService(ctx, messagingConfig, config.ReminderConfig{}, mockMessagingProvider, mockEmailSender, mockInvitationStorage)
+
+		invitation = &domain.Invitation{
+			BaseInvitation: domain.BaseInvitation{
+				CategoryID:        1,
+				Greeting:          "Ah Ma and Ah Gong",
+				MaximumGuestCount: 2,
+				MobilePhoneNumber: "+6591231234",
+			},
+			ID:        1,
+			PrivateID: "some-private-id",
+			Status:    domain.NotSent,
+			Version:   1,
+		}
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	Context("one invitation", func() {
+
+		It("should text the invitation link, record the message and mark the invitation as sent", func() {
+			gomock.InOrder(
+				mockInvitationStorage.EXPECT().FindInvitationByID(int64(1)).Return(invitation, nil),
+				mockInvitationStorage.EXPECT().ListMessageTemplates().Return(nil, nil),
+				mockMessagingProvider.EXPECT().SendSMS("+6591231234", "Ah Ma and Ah Gong, you're invited! Let us know if you can make it at https://wedding.example.com/rsvp/some-private-id").Return("SM123", nil),
+				mockInvitationStorage.EXPECT().InsertMessage(gomock.Any()).Do(func(message *domain.Message) {
+					Expect(message.InvitationID).To(Equal(int64(1)))
+					Expect(message.Channel).To(Equal(domain.SMSChannel))
+					Expect(message.Provider).To(Equal("twilio"))
+					Expect(message.ProviderMessageID).To(Equal("SM123"))
+					Expect(message.Status).To(Equal(domain.MessageQueued))
+				}).Return(&domain.Message{ID: 2, InvitationID: 1, Status: domain.MessageQueued}, nil),
+				mockInvitationStorage.EXPECT().FindInvitationByID(int64(1)).Return(invitation, nil),
+				mockInvitationStorage.EXPECT().UpdateInvitation(gomock.Any()).Do(func(updated *domain.Invitation) {
+					Expect(updated.Status).To(Equal(domain.Sent))
+				}).Return(invitation, nil),
+				mockInvitationStorage.EXPECT().InsertAuditEntry(gomock.Any()).Do(func(req *domain.AuditEntryCreateRequest) {
+					Expect(req.Action).To(Equal(domain.AuditSent))
+				}).Return(&domain.AuditEntry{}, nil),
+			)
+
+			sendResult, err := testInvitationService.SendInvitationByID(1)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(sendResult.InvitationID).To(Equal(int64(1)))
+			Expect(sendResult.Messages).To(HaveLen(1))
+			Expect(sendResult.Messages[0].ID).To(Equal(int64(2)))
+		})
+
+		It("should email the invitation link as well when the invitation has an email", func() {
+			invitation.Email = "ahma@example.com"
+
+			gomock.InOrder(
+				mockInvitationStorage.EXPECT().FindInvitationByID(int64(1)).Return(invitation, nil),
+				mockInvitationStorage.EXPECT().ListMessageTemplates().Return(nil, nil),
+				mockMessagingProvider.EXPECT().SendSMS(gomock.Any(), gomock.Any()).Return("SM123", nil),
+				mockInvitationStorage.EXPECT().ListMessageTemplates().Return(nil, nil),
+				mockEmailSender.EXPECT().SendEmail(gomock.Any()).Do(func(email *domain.Email) {
+					Expect(email.To).To(Equal("ahma@example.com"))
+					Expect(email.Subject).ToNot(BeEmpty())
+					Expect(email.TextBody).To(ContainSubstring("Ah Ma and Ah Gong"))
+					Expect(email.TextBody).To(ContainSubstring("https://wedding.example.com/rsvp/some-private-id"))
+					Expect(email.HTMLBody).To(ContainSubstring(`<a href="https://wedding.example.com/rsvp/some-private-id">`))
+				}).Return("<abc@example.com>", nil),
+				mockInvitationStorage.EXPECT().InsertMessage(gomock.Any()).Return(&domain.Message{ID: 2, Channel: domain.SMSChannel}, nil),
+				mockInvitationStorage.EXPECT().InsertMessage(gomock.Any()).Do(func(message *domain.Message) {
+					Expect(message.Channel).To(Equal(domain.EmailChannel))
+					Expect(message.Recipient).To(Equal("ahma@example.com"))
+					Expect(message.Provider).To(Equal("smtp"))
+					Expect(message.ProviderMessageID).To(Equal("<abc@example.com>"))
+				}).Return(&domain.Message{ID: 3, Channel: domain.EmailChannel}, nil),
+				mockInvitationStorage.EXPECT().FindInvitationByID(int64(1)).Return(invitation, nil),
+				mockInvitationStorage.EXPECT().UpdateInvitation(gomock.Any()).Return(invitation, nil),
+				mockInvitationStorage.EXPECT().InsertAuditEntry(gomock.Any()).Return(&domain.AuditEntry{}, nil),
+			)
+
+			sendResult, err := testInvitationService.SendInvitationByID(1)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(sendResult.Messages).To(HaveLen(2))
+		})
+
+		It("should use the invitation templates that fit the category and language of the invitation", func() {
+			invitation.Email = "ahma@example.com"
+			invitation.Language = "zh"
+
+			templates := []domain.MessageTemplate{
+				{
+					ID: 1,
+					BaseMessageTemplate: domain.BaseMessageTemplate{
+						Kind:    domain.InvitationTemplate,
+						Channel: domain.SMSChannel,
+						Body:    "{{.Greeting}}, come! {{.RSVPLink}}",
+					},
+				},
+				{
+					ID: 2,
+					BaseMessageTemplate: domain.BaseMessageTemplate{
+						Kind:       domain.InvitationTemplate,
+						Channel:    domain.SMSChannel,
+						CategoryID: 1,
+						Language:   "zh",
+						Body:       "{{.Greeting}}, {{.CategoryTag}} {{.MaximumGuestCount}} {{.RSVPLink}}",
+					},
+				},
+				{
+					ID: 3,
+					BaseMessageTemplate: domain.BaseMessageTemplate{
+						Kind:     domain.InvitationTemplate,
+						Channel:  domain.EmailChannel,
+						Language: "zh",
+						Subject:  "Invitation for {{.Greeting}}",
+						Body:     "Dear {{.Greeting}},\n\nReply at {{.RSVPLink}}",
+					},
+				},
+			}
+
+			gomock.InOrder(
+				mockInvitationStorage.EXPECT().FindInvitationByID(int64(1)).Return(invitation, nil),
+				mockInvitationStorage.EXPECT().ListMessageTemplates().Return(templates, nil),
+				mockInvitationStorage.EXPECT().FindCategoryByID(int64(1)).Return(&domain.Category{ID: 1, Tag: "family"}, nil),
+				mockMessagingProvider.EXPECT().SendSMS("+6591231234", "Ah Ma and Ah Gong, family 2 https://wedding.example.com/rsvp/some-private-id").Return("SM123", nil),
+				mockInvitationStorage.EXPECT().ListMessageTemplates().Return(templates, nil),
+				mockInvitationStorage.EXPECT().FindCategoryByID(int64(1)).Return(&domain.Category{ID: 1, Tag: "family"}, nil),
+				mockEmailSender.EXPECT().SendEmail(gomock.Any()).Do(func(email *domain.Email) {
+					Expect(email.Subject).To(Equal("Invitation for Ah Ma and Ah Gong"))
+					Expect(email.TextBody).To(Equal("Dear Ah Ma and Ah Gong,\n\nReply at https://wedding.example.com/rsvp/some-private-id"))
+					Expect(email.HTMLBody).To(ContainSubstring(`<a href="https://wedding.example.com/rsvp/some-private-id">`))
+				}).Return("<abc@example.com>", nil),
+				mockInvitationStorage.EXPECT().InsertMessage(gomock.Any()).Do(func(message *domain.Message) {
+					Expect(message.Body).To(Equal("Ah Ma and Ah Gong, family 2 https://wedding.example.com/rsvp/some-private-id"))
+				}).Return(&domain.Message{ID: 2, Channel: domain.SMSChannel}, nil),
+				mockInvitationStorage.EXPECT().InsertMessage(gomock.Any()).Do(func(message *domain.Message) {
+					Expect(message.Subject).To(Equal("Invitation for Ah Ma and Ah Gong"))
+				}).Return(&domain.Message{ID: 3, Channel: domain.EmailChannel}, nil),
+				mockInvitationStorage.EXPECT().FindInvitationByID(int64(1)).Return(invitation, nil),
+				mockInvitationStorage.EXPECT().UpdateInvitation(gomock.Any()).Return(invitation, nil),
+				mockInvitationStorage.EXPECT().InsertAuditEntry(gomock.Any()).Return(&domain.AuditEntry{}, nil),
+			)
+
+			sendResult, err := testInvitationService.SendInvitationByID(1)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(sendResult.Messages).To(HaveLen(2))
+		})
+
+		It("should mark the invitation as sent when only the email is accepted", func() {
+			invitation.MobilePhoneNumber = "+65"
+			invitation.Email = "ahma@example.com"
+
+			gomock.InOrder(
+				mockInvitationStorage.EXPECT().FindInvitationByID(int64(1)).Return(invitation, nil),
+				mockMessagingProvider.EXPECT().SendSMS(gomock.Any(), gomock.Any()).Times(0),
+				mockInvitationStorage.EXPECT().ListMessageTemplates().Return(nil, nil),
+				mockEmailSender.EXPECT().SendEmail(gomock.Any()).Return("<abc@example.com>", nil),
+				mockInvitationStorage.EXPECT().InsertMessage(gomock.Any()).Return(&domain.Message{ID: 2, Channel: domain.EmailChannel}, nil),
+				mockInvitationStorage.EXPECT().FindInvitationByID(int64(1)).Return(invitation, nil),
+				mockInvitationStorage.EXPECT().UpdateInvitation(gomock.Any()).Do(func(updated *domain.Invitation) {
+					Expect(updated.Status).To(Equal(domain.Sent))
+				}).Return(invitation, nil),
+				mockInvitationStorage.EXPECT().InsertAuditEntry(gomock.Any()).Return(&domain.AuditEntry{}, nil),
+			)
+
+			sendResult, err := testInvitationService.SendInvitationByID(1)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(sendResult.Messages).To(HaveLen(1))
+		})
+
+		It("should leave the status of an invitation that was already sent as it was", func() {
+			invitation.Status = domain.Sent
+
+			gomock.InOrder(
+				mockInvitationStorage.EXPECT().FindInvitationByID(int64(1)).Return(invitation, nil),
+				mockInvitationStorage.EXPECT().ListMessageTemplates().Return(nil, nil),
+				mockMessagingProvider.EXPECT().SendSMS(gomock.Any(), gomock.Any()).Return("SM123", nil),
+				mockInvitationStorage.EXPECT().InsertMessage(gomock.Any()).Return(&domain.Message{ID: 2}, nil),
+				mockInvitationStorage.EXPECT().FindInvitationByID(int64(1)).Return(invitation, nil),
+				mockInvitationStorage.EXPECT().UpdateInvitation(gomock.Any()).Times(0),
+				mockInvitationStorage.EXPECT().InsertAuditEntry(gomock.Any()).Return(&domain.AuditEntry{}, nil),
+			)
+
+			_, err := testInvitationService.SendInvitationByID(1)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should record the failed message and return a send failed error when the provider rejects it", func() {
+			gomock.InOrder(
+				mockInvitationStorage.EXPECT().FindInvitationByID(int64(1)).Return(invitation, nil),
+				mockInvitationStorage.EXPECT().ListMessageTemplates().Return(nil, nil),
+				mockMessagingProvider.EXPECT().SendSMS(gomock.Any(), gomock.Any()).Return("", errors.New("invalid number")),
+				mockInvitationStorage.EXPECT().InsertMessage(gomock.Any()).Do(func(message *domain.Message) {
+					Expect(message.Status).To(Equal(domain.MessageFailed))
+					Expect(message.Error).To(Equal("invalid number"))
+				}).Return(&domain.Message{ID: 2, Status: domain.MessageFailed, Error: "invalid number"}, nil),
+				mockInvitationStorage.EXPECT().UpdateInvitation(gomock.Any()).Times(0),
+			)
+
+			sendResult, err := testInvitationService.SendInvitationByID(1)
+			Expect(err).To(BeAssignableToTypeOf(InvitationSendFailedError{}))
+			Expect(err.(InvitationSendFailedError).Messages[0].ID).To(Equal(int64(2)))
+			Expect(err.Error()).To(ContainSubstring("invalid number"))
+			Expect(sendResult).To(BeNil())
+		})
+
+		It("should return an error without sending if the invitation has no mobile phone number or email", func() {
+			invitation.MobilePhoneNumber = "+65"
+
+			gomock.InOrder(
+				mockInvitationStorage.EXPECT().FindInvitationByID(int64(1)).Return(invitation, nil),
+				mockMessagingProvider.EXPECT().SendSMS(gomock.Any(), gomock.Any()).Times(0),
+			)
+
+			sendResult, err := testInvitationService.SendInvitationByID(1)
+			Expect(err).To(BeAssignableToTypeOf(serviceErrors.ValidationError{}))
+			Expect(err.Error()).To(ContainSubstring("invitation has no mobile phone number or email"))
+			Expect(sendResult).To(BeNil())
+		})
+
+		It("should return an error without sending if the invitation link was revoked or has expired", func() {
+			invitation.LinkRevokedAt = "2026-10-01T10:00:00Z"
+
+			mockInvitationStorage.EXPECT().FindInvitationByID(int64(1)).Return(invitation, nil)
+			mockMessagingProvider.EXPECT().SendSMS(gomock.Any(), gomock.Any()).Times(0)
+			mockInvitationStorage.EXPECT().UpdateInvitation(gomock.Any()).Times(0)
+
+			sendResult, err := testInvitationService.SendInvitationByID(1)
+			Expect(err).To(BeAssignableToTypeOf(serviceErrors.ValidationError{}))
+			Expect(err.Error()).To(ContainSubstring("invitation link was revoked"))
+			Expect(sendResult).To(BeNil())
+
+			invitation.LinkRevokedAt = ""
+			invitation.LinkExpiresAt = "2026-10-01T10:00:00Z"
+
+			mockInvitationStorage.EXPECT().FindInvitationByID(int64(1)).Return(invitation, nil)
+
+			sendResult, err = testInvitationService.SendInvitationByID(1)
+			Expect(err).To(BeAssignableToTypeOf(serviceErrors.ValidationError{}))
+			Expect(err.Error()).To(ContainSubstring("invitation link has expired"))
+			Expect(sendResult).To(BeNil())
+		})
+
+		It("should return a not found error for unknown invitations", func() {
+			mockInvitationStorage.EXPECT().FindInvitationByID(int64(1)).Return(nil, storage.NewStorageRecordNotFoundError())
+
+			sendResult, err := testInvitationService.SendInvitationByID(1)
+			Expect(err).To(BeAssignableToTypeOf(InvitationNotFoundError{}))
+			Expect(sendResult).To(BeNil())
+		})
+	})
+
+	Context("a category", func() {
+
+		It("should send the invitations not sent yet and report those skipped or failed", func() {
+			withoutNumber := *invitation
+			withoutNumber.ID = 2
+			withoutNumber.MobilePhoneNumber = "+65"
+
+			rejected := *invitation
+			rejected.ID = 3
+
+			alreadySent := *invitation
+			alreadySent.ID = 4
+			alreadySent.Status = domain.Sent
+
+			replied := *invitation
+			replied.ID = 5
+			replied.Status = domain.RepliedAttending
+
+			mockInvitationStorage.EXPECT().FindCategoryByID(int64(1)).Return(&domain.Category{ID: 1}, nil)
+			mockInvitationStorage.EXPECT().ListInvitations(gomock.Any()).Do(func(req *domain.InvitationListRequest) {
+				Expect(req.CategoryID).To(Equal(int64(1)))
+				Expect(req.Page).To(Equal(1))
+			}).Return([]domain.Invitation{*invitation, withoutNumber, rejected, alreadySent, replied}, 5, nil)
+
+			mockInvitationStorage.EXPECT().ListMessageTemplates().Return(nil, nil)
+			mockMessagingProvider.EXPECT().SendSMS(gomock.Any(), gomock.Any()).Return("SM123", nil)
+			mockInvitationStorage.EXPECT().ListMessageTemplates().Return(nil, nil)
+			mockMessagingProvider.EXPECT().SendSMS(gomock.Any(), gomock.Any()).Return("", errors.New("invalid number"))
+			mockInvitationStorage.EXPECT().InsertMessage(gomock.Any()).Return(&domain.Message{ID: 1, Status: domain.MessageSent}, nil)
+			mockInvitationStorage.EXPECT().InsertMessage(gomock.Any()).Return(&domain.Message{ID: 2, Status: domain.MessageFailed, Error: "invalid number"}, nil)
+			mockInvitationStorage.EXPECT().FindInvitationByID(int64(1)).Return(invitation, nil)
+			mockInvitationStorage.EXPECT().UpdateInvitation(gomock.Any()).Return(invitation, nil)
+			mockInvitationStorage.EXPECT().InsertAuditEntry(gomock.Any()).Return(&domain.AuditEntry{}, nil)
+
+			bulkSendResult, err := testInvitationService.SendInvitations(&domain.InvitationBulkSendRequest{CategoryID: 1})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(bulkSendResult.Sent).To(Equal(1))
+			Expect(bulkSendResult.Failed).To(Equal(1))
+			Expect(bulkSendResult.Skipped).To(Equal(1))
+			Expect(bulkSendResult.Results).To(HaveLen(3))
+			Expect(bulkSendResult.Results[0].InvitationID).To(Equal(int64(1)))
+			Expect(bulkSendResult.Results[1].InvitationID).To(Equal(int64(2)))
+			Expect(bulkSendResult.Results[1].Messages).To(BeEmpty())
+			Expect(bulkSendResult.Results[1].Error).To(ContainSubstring("no mobile phone number"))
+			Expect(bulkSendResult.Results[2].InvitationID).To(Equal(int64(3)))
+			Expect(bulkSendResult.Results[2].Error).To(ContainSubstring("invalid number"))
+		})
+
+		It("should skip invitations whose link was revoked or has expired without marking them as sent", func() {
+			revoked := *invitation
+			revoked.LinkRevokedAt = "2026-10-01T10:00:00Z"
+
+			expired := *invitation
+			expired.ID = 2
+			expired.LinkExpiresAt = "2026-10-01T10:00:00Z"
+
+			mockInvitationStorage.EXPECT().FindCategoryByID(int64(1)).Return(&domain.Category{ID: 1}, nil)
+			mockInvitationStorage.EXPECT().ListInvitations(gomock.Any()).Return([]domain.Invitation{revoked, expired}, 2, nil)
+			mockMessagingProvider.EXPECT().SendSMS(gomock.Any(), gomock.Any()).Times(0)
+			mockInvitationStorage.EXPECT().UpdateInvitation(gomock.Any()).Times(0)
+
+			bulkSendResult, err := testInvitationService.SendInvitations(&domain.InvitationBulkSendRequest{CategoryID: 1})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(bulkSendResult.Sent).To(Equal(0))
+			Expect(bulkSendResult.Skipped).To(Equal(2))
+			Expect(bulkSendResult.Results).To(HaveLen(2))
+			Expect(bulkSendResult.Results[0].Error).To(ContainSubstring("invitation link was revoked"))
+			Expect(bulkSendResult.Results[1].Error).To(ContainSubstring("invitation link has expired"))
+		})
+
+		It("should send invitations that were already sent again when resending", func() {
+			invitation.Status = domain.Sent
+
+			mockInvitationStorage.EXPECT().FindCategoryByID(int64(1)).Return(&domain.Category{ID: 1}, nil)
+			mockInvitationStorage.EXPECT().ListInvitations(gomock.Any()).Return([]domain.Invitation{*invitation}, 1, nil)
+			mockInvitationStorage.EXPECT().ListMessageTemplates().Return(nil, nil)
+			mockMessagingProvider.EXPECT().SendSMS(gomock.Any(), gomock.Any()).Return("SM123", nil)
+			mockInvitationStorage.EXPECT().InsertMessage(gomock.Any()).Return(&domain.Message{ID: 1, Status: domain.MessageSent}, nil)
+			mockInvitationStorage.EXPECT().FindInvitationByID(int64(1)).Return(invitation, nil)
+			mockInvitationStorage.EXPECT().InsertAuditEntry(gomock.Any()).Return(&domain.AuditEntry{}, nil)
+
+			bulkSendResult, err := testInvitationService.SendInvitations(&domain.InvitationBulkSendRequest{CategoryID: 1, Resend: true})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(bulkSendResult.Sent).To(Equal(1))
+		})
+
+		It("should return an error if the category does not exist", func() {
+			mockInvitationStorage.EXPECT().FindCategoryByID(int64(1)).Return(nil, storage.NewStorageRecordNotFoundError())
+
+			bulkSendResult, err := testInvitationService.SendInvitations(&domain.InvitationBulkSendRequest{CategoryID: 1})
+			Expect(err).To(BeAssignableToTypeOf(serviceErrors.ValidationError{}))
+			Expect(err.Error()).To(ContainSubstring("category does not exist"))
+			Expect(bulkSendResult).To(BeNil())
+		})
+	})
+})
@@ -0,0 +1,49 @@
+package jwt
+
+import (
+	"crypto/ed25519"
+
+	gjwt "github.com/dgrijalva/jwt-go"
+)
+
+// signingMethodEdDSA adds Ed25519 signatures, which the vendored jwt-go predates
+type signingMethodEdDSA struct{}
+
+var SigningMethodEdDSA gjwt.SigningMethod = new(signingMethodEdDSA)
+
+func init() {
+	gjwt.RegisterSigningMethod(SigningMethodEdDSA.Alg(), func() gjwt.SigningMethod {
+		return SigningMethodEdDSA
+	})
+}
+
+func (m *signingMethodEdDSA) Alg() string {
+	return "EdDSA"
+}
+
+func (m *signingMethodEdDSA) Verify(signingString, signature string, key interface{}) error {
+	publicKey, ok := key.(ed25519.PublicKey)
+	if !ok {
+		return gjwt.ErrInvalidKey
+	}
+
+	sig, err := gjwt.DecodeSegment(signature)
+	if err != nil {
+		return err
+	}
+
+	if !ed25519.Verify(publicKey, []byte(signingString), sig) {
+		return gjwt.ErrSignatureInvalid
+	}
+
+	return nil
+}
+
+func (m *signingMethodEdDSA) Sign(signingString string, key interface{}) (string, error) {
+	privateKey, ok := key.(ed25519.PrivateKey)
+	if !ok {
+		return "", gjwt.ErrInvalidKey
+	}
+
+	return gjwt.EncodeSegment(ed25519.Sign(privateKey, []byte(signingString))), nil
+}
@@ -0,0 +1,78 @@
+package jwt
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"math/big"
+
+	"github.com/rawfish-dev/rsvp-starter/server/config"
+	"github.com/rawfish-dev/rsvp-starter/server/domain"
+	serviceErrors "github.com/rawfish-dev/rsvp-starter/server/services/errors"
+)
+
+// PublicKeys returns the public half of every asymmetric key tokens may be signed with. HMAC secrets
+// are never published.
+func (s *service) PublicKeys() (*domain.JSONWebKeySet, error) {
+	keySet := &domain.JSONWebKeySet{Keys: []domain.JSONWebKey{}}
+
+	for _, key := range append([]config.JWTKey{s.jwtConfig.SigningKey}, s.jwtConfig.VerificationKeys...) {
+		if !key.IsAsymmetric() || hasKey(keySet, key.ID) {
+			continue
+		}
+
+		webKey := domain.JSONWebKey{
+			KeyID:     key.ID,
+			Use:       "sig",
+			Algorithm: key.Algorithm,
+		}
+
+		switch publicKey := key.PublicKey.(type) {
+		case *rsa.PublicKey:
+			webKey.KeyType = "RSA"
+			webKey.N = encodeBase64URL(publicKey.N.Bytes())
+			webKey.E = encodeBase64URL(big.NewInt(int64(publicKey.E)).Bytes())
+		case *ecdsa.PublicKey:
+			byteSize := (publicKey.Curve.Params().BitSize + 7) / 8
+
+			webKey.KeyType = "EC"
+			webKey.Curve = publicKey.Curve.Params().Name
+			webKey.X = encodeBase64URL(padLeft(publicKey.X.Bytes(), byteSize))
+			webKey.Y = encodeBase64URL(padLeft(publicKey.Y.Bytes(), byteSize))
+		case ed25519.PublicKey:
+			webKey.KeyType = "OKP"
+			webKey.Curve = "Ed25519"
+			webKey.X = encodeBase64URL(publicKey)
+		default:
+			return nil, serviceErrors.NewGeneralServiceError()
+		}
+
+		keySet.Keys = append(keySet.Keys, webKey)
+	}
+
+	return keySet, nil
+}
+
+func hasKey(keySet *domain.JSONWebKeySet, keyID string) bool {
+	for _, webKey := range keySet.Keys {
+		if webKey.KeyID == keyID {
+			return true
+		}
+	}
+
+	return false
+}
+
+func encodeBase64URL(data []byte) string {
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// padLeft keeps elliptic curve coordinates at the full size of the curve as RFC 7518 requires
+func padLeft(data []byte, size int) []byte {
+	if len(data) >= size {
+		return data
+	}
+
+	return append(make([]byte, size-len(data)), data...)
+}
@@ -34,7 +34,10 @@ func (s *service) GenerateAuthToken(additionalClaims map[string]string, duration
 	currentTime := time.Now()
 	expiryTime := currentTime.Add(duration)
 
-	baseJWT := gjwt.New(gjwt.GetSigningMethod("HS256"))
+	signingKey := s.jwtConfig.SigningKey
+
+	baseJWT := gjwt.New(gjwt.GetSigningMethod(signingKey.Algorithm))
+	baseJWT.Header["kid"] = signingKey.ID
 
 	// Write additional claims first in case base claims are present
 	for claimKey, claimValue := range additionalClaims {
@@ -47,7 +50,11 @@ func (s *service) GenerateAuthToken(additionalClaims map[string]string, duration
 	baseJWT.Claims["exp"] = expiryTime.Unix()
 
 	// Sign and get the complete encoded token as a string
-	return baseJWT.SignedString([]byte(s.jwtConfig.HMACSecret))
+	if signingKey.IsAsymmetric() {
+		return baseJWT.SignedString(signingKey.PrivateKey)
+	}
+
+	return baseJWT.SignedString(signingKey.Secret)
 }
 
 func (s *service) ParseToken(token string) (claims map[string]interface{}, err error) {
@@ -108,8 +115,40 @@ func (s *service) IsAuthTokenValid(token string) (valid bool) {
 	return baseJWT.Valid
 }
 
+// parseJWTString only accepts tokens signed with the algorithm of the key named by their kid header, so
+// a token cannot pass off a public key as an HMAC secret or go unsigned. Tokens from before key ids were
+// added have none and are checked against the signing key.
 func (s *service) parseJWTString(token string) (baseJWT *gjwt.Token, err error) {
 	return gjwt.Parse(token, func(baseJWT *gjwt.Token) (interface{}, error) {
-		return []byte(s.jwtConfig.HMACSecret), nil
+		keyID, _ := baseJWT.Header["kid"].(string)
+
+		key, found := s.findKey(keyID)
+		if !found {
+			return nil, fmt.Errorf("key %q is unknown", keyID)
+		}
+
+		if baseJWT.Method.Alg() != key.Algorithm {
+			return nil, fmt.Errorf("algorithm %v does not match the %v of key %q", baseJWT.Method.Alg(), key.Algorithm, keyID)
+		}
+
+		if key.IsAsymmetric() {
+			return key.PublicKey, nil
+		}
+
+		return key.Secret, nil
 	})
 }
+
+func (s *service) findKey(keyID string) (key config.JWTKey, found bool) {
+	if keyID == "" || keyID == s.jwtConfig.SigningKey.ID {
+		return s.jwtConfig.SigningKey, true
+	}
+
+	for _, verificationKey := range s.jwtConfig.VerificationKeys {
+		if verificationKey.ID == keyID {
+			return verificationKey, true
+		}
+	}
+
+	return config.JWTKey{}, false
+}
@@ -1,6 +1,14 @@
 package jwt_test
 
 import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"strings"
 	"time"
 
 	"github.com/rawfish-dev/rsvp-starter/server/config"
@@ -8,6 +16,7 @@ import (
 	. "github.com/rawfish-dev/rsvp-starter/server/services/jwt"
 
 	"github.com/Sirupsen/logrus"
+	gjwt "github.com/dgrijalva/jwt-go"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	"golang.org/x/net/context"
@@ -15,16 +24,17 @@ import (
 
 var _ = Describe("Jwt", func() {
 
+	var ctx context.Context
 	var testJWTService interfaces.JWTServiceProvider
 	var jwtConfig config.JWTConfig
 
 	BeforeEach(func() {
 		ctxlogger := logrus.New()
-		ctx := context.Background()
+		ctx = context.Background()
 		ctx = context.WithValue(ctx, "logger", ctxlogger)
 
 		jwtConfig = config.JWTConfig{
-			HMACSecret:  "some-secret-hmac",
+			SigningKey:  config.NewHMACJWTKey("some-secret-hmac"),
 			TokenIssuer: "rsvp-starter-test",
 		}
 
@@ -170,4 +180,188 @@ var _ = Describe("Jwt", func() {
 			Expect(valid).To(BeTrue())
 		})
 	})
+
+	Context("keys", func() {
+
+		var userClaims map[string]string
+
+		BeforeEach(func() {
+			userClaims = map[string]string{
+				"userID": "123123",
+			}
+		})
+
+		It("should name the signing key in the kid header", func() {
+			token, err := testJWTService.GenerateAuthToken(userClaims, 10*time.Second)
+			Expect(err).ToNot(HaveOccurred())
+
+			baseJWT, _ := gjwt.Parse(token, func(*gjwt.Token) (interface{}, error) { return nil, nil })
+			Expect(baseJWT.Header["kid"]).To(Equal(jwtConfig.SigningKey.ID))
+		})
+
+		It("should accept tokens signed with a key that has been rotated out", func() {
+			token, err := testJWTService.GenerateAuthToken(userClaims, 10*time.Second)
+			Expect(err).ToNot(HaveOccurred())
+
+			rotatedJWTService := NewService(ctx, config.JWTConfig{
+				SigningKey:       config.NewHMACJWTKey("another-secret-hmac"),
+				VerificationKeys: []config.JWTKey{jwtConfig.SigningKey},
+				TokenIssuer:      "rsvp-starter-test",
+			})
+
+			claims, err := rotatedJWTService.ParseToken(token)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(claims["userID"].(string)).To(Equal("123123"))
+		})
+
+		It("should reject tokens signed with a key that has been dropped", func() {
+			token, err := testJWTService.GenerateAuthToken(userClaims, 10*time.Second)
+			Expect(err).ToNot(HaveOccurred())
+
+			rotatedJWTService := NewService(ctx, config.JWTConfig{
+				SigningKey:  config.NewHMACJWTKey("another-secret-hmac"),
+				TokenIssuer: "rsvp-starter-test",
+			})
+
+			claims, err := rotatedJWTService.ParseToken(token)
+			Expect(err).To(HaveOccurred())
+			Expect(claims).To(BeNil())
+		})
+
+		It("should reject tokens that use a different algorithm to their key", func() {
+			rsaKey := generateJWTKey(config.RS256Algorithm)
+
+			// Signs with the public key as an HMAC secret, which the key must not accept
+			publicKeyBytes, err := x509.MarshalPKIXPublicKey(rsaKey.PublicKey)
+			Expect(err).ToNot(HaveOccurred())
+
+			forgedJWT := gjwt.New(gjwt.SigningMethodHS256)
+			forgedJWT.Header["kid"] = rsaKey.ID
+			forgedJWT.Claims["iss"] = "rsvp-starter-test"
+			forgedJWT.Claims["exp"] = time.Now().Add(time.Minute).Unix()
+
+			token, err := forgedJWT.SignedString(publicKeyBytes)
+			Expect(err).ToNot(HaveOccurred())
+
+			rsaJWTService := NewService(ctx, config.JWTConfig{SigningKey: rsaKey, TokenIssuer: "rsvp-starter-test"})
+
+			claims, err := rsaJWTService.ParseToken(token)
+			Expect(err).To(HaveOccurred())
+			Expect(claims).To(BeNil())
+		})
+
+		It("should reject unsigned tokens", func() {
+			token, err := testJWTService.GenerateAuthToken(userClaims, 10*time.Second)
+			Expect(err).ToNot(HaveOccurred())
+
+			segments := strings.Split(token, ".")
+			unsignedHeader := gjwt.EncodeSegment([]byte(`{"alg":"none","typ":"JWT"}`))
+
+			claims, err := testJWTService.ParseToken(unsignedHeader + "." + segments[1] + ".")
+			Expect(err).To(HaveOccurred())
+			Expect(claims).To(BeNil())
+		})
+
+		for _, algorithm := range []string{config.RS256Algorithm, config.ES256Algorithm, config.EdDSAAlgorithm} {
+			algorithm := algorithm
+
+			It("should sign and verify tokens with "+algorithm, func() {
+				signingKey := generateJWTKey(algorithm)
+				asymmetricJWTService := NewService(ctx, config.JWTConfig{SigningKey: signingKey, TokenIssuer: "rsvp-starter-test"})
+
+				token, err := asymmetricJWTService.GenerateAuthToken(userClaims, 10*time.Second)
+				Expect(err).ToNot(HaveOccurred())
+
+				claims, err := asymmetricJWTService.ParseToken(token)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(claims["userID"].(string)).To(Equal("123123"))
+			})
+		}
+
+		It("should verify tokens with only the public key", func() {
+			signingKey := generateJWTKey(config.ES256Algorithm)
+			signingJWTService := NewService(ctx, config.JWTConfig{SigningKey: signingKey, TokenIssuer: "rsvp-starter-test"})
+
+			token, err := signingJWTService.GenerateAuthToken(userClaims, 10*time.Second)
+			Expect(err).ToNot(HaveOccurred())
+
+			publicKeyBytes, err := x509.MarshalPKIXPublicKey(signingKey.PublicKey)
+			Expect(err).ToNot(HaveOccurred())
+
+			verificationKey, err := config.ParseJWTKey(config.ES256Algorithm, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicKeyBytes}))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(verificationKey.ID).To(Equal(signingKey.ID))
+			Expect(verificationKey.CanSign()).To(BeFalse())
+
+			verifyingJWTService := NewService(ctx, config.JWTConfig{
+				SigningKey:       jwtConfig.SigningKey,
+				VerificationKeys: []config.JWTKey{verificationKey},
+				TokenIssuer:      "rsvp-starter-test",
+			})
+
+			Expect(verifyingJWTService.IsAuthTokenValid(token)).To(BeTrue())
+		})
+
+		It("should publish asymmetric keys but never HMAC secrets", func() {
+			rsaKey := generateJWTKey(config.RS256Algorithm)
+			ecdsaKey := generateJWTKey(config.ES256Algorithm)
+			ed25519Key := generateJWTKey(config.EdDSAAlgorithm)
+
+			asymmetricJWTService := NewService(ctx, config.JWTConfig{
+				SigningKey:       rsaKey,
+				VerificationKeys: []config.JWTKey{jwtConfig.SigningKey, ecdsaKey, ed25519Key, rsaKey},
+				TokenIssuer:      "rsvp-starter-test",
+			})
+
+			keySet, err := asymmetricJWTService.PublicKeys()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(keySet.Keys).To(HaveLen(3))
+
+			Expect(keySet.Keys[0].KeyID).To(Equal(rsaKey.ID))
+			Expect(keySet.Keys[0].KeyType).To(Equal("RSA"))
+			Expect(keySet.Keys[0].Algorithm).To(Equal(config.RS256Algorithm))
+			Expect(keySet.Keys[0].Use).To(Equal("sig"))
+			Expect(keySet.Keys[0].E).To(Equal("AQAB"))
+			Expect(keySet.Keys[0].N).ToNot(BeEmpty())
+
+			Expect(keySet.Keys[1].KeyID).To(Equal(ecdsaKey.ID))
+			Expect(keySet.Keys[1].KeyType).To(Equal("EC"))
+			Expect(keySet.Keys[1].Curve).To(Equal("P-256"))
+			Expect(keySet.Keys[1].X).To(HaveLen(43))
+			Expect(keySet.Keys[1].Y).To(HaveLen(43))
+
+			Expect(keySet.Keys[2].KeyID).To(Equal(ed25519Key.ID))
+			Expect(keySet.Keys[2].KeyType).To(Equal("OKP"))
+			Expect(keySet.Keys[2].Curve).To(Equal("Ed25519"))
+			Expect(keySet.Keys[2].X).To(HaveLen(43))
+
+			keySet, err = testJWTService.PublicKeys()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(keySet.Keys).To(BeEmpty())
+		})
+	})
 })
+
+// generateJWTKey goes through PEM the same way keys read from files do
+func generateJWTKey(algorithm string) config.JWTKey {
+	var privateKey interface{}
+	var err error
+
+	switch algorithm {
+	case config.RS256Algorithm:
+		privateKey, err = rsa.GenerateKey(rand.Reader, 2048)
+	case config.ES256Algorithm:
+		privateKey, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	case config.EdDSAAlgorithm:
+		_, privateKey, err = ed25519.GenerateKey(rand.Reader)
+	}
+	Expect(err).ToNot(HaveOccurred())
+
+	privateKeyBytes, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	Expect(err).ToNot(HaveOccurred())
+
+	jwtKey, err := config.ParseJWTKey(algorithm, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privateKeyBytes}))
+	Expect(err).ToNot(HaveOccurred())
+
+	return jwtKey
+}
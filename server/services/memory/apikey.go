@@ -0,0 +1,178 @@
+package memory
+
+import (
+	"sort"
+	"time"
+
+	"github.com/rawfish-dev/rsvp-starter/server/domain"
+	"github.com/rawfish-dev/rsvp-starter/server/interfaces"
+	"github.com/rawfish-dev/rsvp-starter/server/services/storage"
+)
+
+// apiKey leaves ExpiresAt, LastUsedAt and RevokedAt as the zero time until they apply
+type apiKey struct {
+	ID         int64
+	Name       string
+	Prefix     string
+	KeyHash    string
+	Scopes     []domain.Scope
+	CreatedBy  string
+	ExpiresAt  time.Time
+	LastUsedAt time.Time
+	RevokedAt  time.Time
+	CreatedAt  time.Time
+}
+
+func (a apiKey) toDomain() domain.APIKey {
+	return domain.APIKey{
+		ID:         a.ID,
+		Name:       a.Name,
+		Prefix:     a.Prefix,
+		KeyHash:    a.KeyHash,
+		Scopes:     append([]domain.Scope(nil), a.Scopes...),
+		CreatedBy:  a.CreatedBy,
+		ExpiresAt:  formatOptionalTime(a.ExpiresAt),
+		LastUsedAt: formatOptionalTime(a.LastUsedAt),
+		RevokedAt:  formatOptionalTime(a.RevokedAt),
+		CreatedAt:  a.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+func formatOptionalTime(timestamp time.Time) string {
+	if timestamp.IsZero() {
+		return ""
+	}
+
+	return timestamp.Format(time.RFC3339)
+}
+
+func (s *service) InsertAPIKey(domainAPIKey *domain.APIKey) (*domain.APIKey, error) {
+	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
+
+	expiresAt, err := storage.ParseOptionalTimestamp(domainAPIKey.ExpiresAt)
+	if err != nil {
+		ctxLogger.Errorf("memory service - unable to insert api key due to %v", err)
+		return nil, storage.NewStorageOperationError()
+	}
+
+	s.lock()
+	defer s.unlock()
+
+	s.lastAPIKeyID++
+
+	apiKey := apiKey{
+		ID:        s.lastAPIKeyID,
+		Name:      domainAPIKey.Name,
+		Prefix:    domainAPIKey.Prefix,
+		KeyHash:   domainAPIKey.KeyHash,
+		Scopes:    append([]domain.Scope(nil), domainAPIKey.Scopes...),
+		CreatedBy: domainAPIKey.CreatedBy,
+		CreatedAt: s.now(),
+	}
+	if expiresAt != nil {
+		apiKey.ExpiresAt = *expiresAt
+	}
+	s.apiKeys[apiKey.ID] = apiKey
+
+	newAPIKey := apiKey.toDomain()
+
+	return &newAPIKey, nil
+}
+
+func (s *service) FindAPIKeyByID(apiKeyID int64) (*domain.APIKey, error) {
+	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
+
+	s.rlock()
+	defer s.runlock()
+
+	apiKey, ok := s.apiKeys[apiKeyID]
+	if !ok {
+		ctxLogger.Warnf("memory service - unable to find api key with id %v", apiKeyID)
+		return nil, storage.NewStorageRecordNotFoundError()
+	}
+
+	domainAPIKey := apiKey.toDomain()
+
+	return &domainAPIKey, nil
+}
+
+func (s *service) FindAPIKeyByHash(keyHash string) (*domain.APIKey, error) {
+	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
+
+	s.rlock()
+	defer s.runlock()
+
+	for _, apiKey := range s.apiKeys {
+		if apiKey.KeyHash == keyHash {
+			domainAPIKey := apiKey.toDomain()
+			return &domainAPIKey, nil
+		}
+	}
+
+	ctxLogger.Warn("memory service - unable to find api key with the given hash")
+	return nil, storage.NewStorageRecordNotFoundError()
+}
+
+func (s *service) ListAPIKeys() ([]domain.APIKey, error) {
+	s.rlock()
+	defer s.runlock()
+
+	apiKeys := make([]apiKey, 0, len(s.apiKeys))
+	for _, apiKey := range s.apiKeys {
+		apiKeys = append(apiKeys, apiKey)
+	}
+	sort.Sort(apiKeysByID(apiKeys))
+
+	domainAPIKeys := make([]domain.APIKey, len(apiKeys))
+	for idx := range apiKeys {
+		domainAPIKeys[idx] = apiKeys[idx].toDomain()
+	}
+
+	return domainAPIKeys, nil
+}
+
+func (s *service) RevokeAPIKey(apiKeyID int64) (*domain.APIKey, error) {
+	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
+
+	s.lock()
+	defer s.unlock()
+
+	apiKey, ok := s.apiKeys[apiKeyID]
+	if !ok {
+		ctxLogger.Warnf("memory service - unable to revoke api key with id %v as it does not exist", apiKeyID)
+		return nil, storage.NewStorageRecordNotFoundError()
+	}
+
+	if apiKey.RevokedAt.IsZero() {
+		apiKey.RevokedAt = s.now()
+		s.apiKeys[apiKey.ID] = apiKey
+	}
+
+	revokedAPIKey := apiKey.toDomain()
+
+	return &revokedAPIKey, nil
+}
+
+func (s *service) UpdateAPIKeyLastUsed(apiKeyID int64, lastUsedAt time.Time) error {
+	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
+
+	s.lock()
+	defer s.unlock()
+
+	apiKey, ok := s.apiKeys[apiKeyID]
+	if !ok {
+		ctxLogger.Warnf("memory service - unable to update api key with id %v as it does not exist", apiKeyID)
+		return storage.NewStorageRecordNotFoundError()
+	}
+
+	apiKey.LastUsedAt = lastUsedAt.UTC()
+	s.apiKeys[apiKey.ID] = apiKey
+
+	return nil
+}
+
+type apiKeysByID []apiKey
+
+func (b apiKeysByID) Len() int           { return len(b) }
+func (b apiKeysByID) Swap(i, j int)      { b[i], b[j] = b[j], b[i] }
+func (b apiKeysByID) Less(i, j int) bool { return b[i].ID < b[j].ID }
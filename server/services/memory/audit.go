@@ -0,0 +1,91 @@
+package memory
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/rawfish-dev/rsvp-starter/server/domain"
+)
+
+type auditEntry struct {
+	ID                       int64
+	Actor                    string
+	ActorInvitationPrivateID string
+	Action                   string
+	EntityType               string
+	EntityID                 int64
+	Before                   json.RawMessage
+	After                    json.RawMessage
+	CreatedAt                time.Time
+}
+
+func (a auditEntry) toDomain() domain.AuditEntry {
+	return domain.AuditEntry{
+		ID:                       a.ID,
+		Actor:                    a.Actor,
+		ActorInvitationPrivateID: a.ActorInvitationPrivateID,
+		Action:                   domain.AuditAction(a.Action),
+		EntityType:               domain.AuditEntityType(a.EntityType),
+		EntityID:                 a.EntityID,
+		Before:                   a.Before,
+		After:                    a.After,
+		CreatedAt:                a.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+func (s *service) InsertAuditEntry(req *domain.AuditEntryCreateRequest) (*domain.AuditEntry, error) {
+	s.lock()
+	defer s.unlock()
+
+	s.lastAuditEntryID++
+
+	entry := auditEntry{
+		ID:                       s.lastAuditEntryID,
+		Actor:                    req.Actor.Username,
+		ActorInvitationPrivateID: req.Actor.InvitationPrivateID,
+		Action:                   string(req.Action),
+		EntityType:               string(req.EntityType),
+		EntityID:                 req.EntityID,
+		Before:                   req.Before,
+		After:                    req.After,
+		CreatedAt:                s.now(),
+	}
+	s.auditEntries = append(s.auditEntries, entry)
+
+	newEntry := entry.toDomain()
+
+	return &newEntry, nil
+}
+
+func (s *service) ListAuditEntries(req *domain.AuditListRequest) ([]domain.AuditEntry, int, error) {
+	s.rlock()
+	defer s.runlock()
+
+	// Entries are appended with increasing ids and timestamps so insertion order is creation order
+	entries := make([]auditEntry, 0, len(s.auditEntries))
+	for _, entry := range s.auditEntries {
+		if req.EntityType != "" && entry.EntityType != string(req.EntityType) {
+			continue
+		}
+		if req.EntityID != 0 && entry.EntityID != req.EntityID {
+			continue
+		}
+
+		entries = append(entries, entry)
+	}
+
+	if _, descending := req.SortField(); descending {
+		for left, right := 0, len(entries)-1; left < right; left, right = left+1, right-1 {
+			entries[left], entries[right] = entries[right], entries[left]
+		}
+	}
+
+	start, end := pageBounds(req.ListRequest, len(entries))
+
+	domainEntries := make([]domain.AuditEntry, 0, end-start)
+	for idx := start; idx < end; idx++ {
+		domainEntries = append(domainEntries, entries[idx].toDomain())
+	}
+
+	return domainEntries, len(entries), nil
+}
@@ -0,0 +1,284 @@
+package memory
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rawfish-dev/rsvp-starter/server/domain"
+	"github.com/rawfish-dev/rsvp-starter/server/interfaces"
+	"github.com/rawfish-dev/rsvp-starter/server/services/storage"
+)
+
+type category struct {
+	baseModel
+	Tag string
+}
+
+func (s *service) InsertCategory(req *domain.CategoryCreateRequest) (*domain.Category, error) {
+	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
+
+	s.lock()
+	defer s.unlock()
+
+	if s.isCategoryTagTaken(req.Tag, 0) {
+		ctxLogger.Warn("memory service - unable to insert category with a duplicate tag")
+		return nil, storage.NewStorageCategoryTagUniqueConstraintError()
+	}
+
+	s.lastCategoryID++
+	timestamp := s.now()
+
+	category := category{
+		baseModel: baseModel{
+			ID:        s.lastCategoryID,
+			CreatedAt: timestamp,
+			UpdatedAt: timestamp,
+		},
+		Tag: req.Tag,
+	}
+	s.categories[category.ID] = category
+
+	newCategory := &domain.Category{
+		ID:  category.ID,
+		Tag: category.Tag,
+	}
+
+	return newCategory, nil
+}
+
+func (s *service) FindCategoryByID(categoryID int64) (*domain.Category, error) {
+	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
+
+	s.rlock()
+	defer s.runlock()
+
+	category, ok := s.categories[categoryID]
+	if !ok {
+		ctxLogger.Warnf("memory service - unable to find category with id %v", categoryID)
+		return nil, storage.NewStorageRecordNotFoundError()
+	}
+
+	domainCategory := s.toDomainCategory(category)
+
+	return &domainCategory, nil
+}
+
+func (s *service) ListCategories(req *domain.CategoryListRequest) ([]domain.Category, int, error) {
+	s.rlock()
+	defer s.runlock()
+
+	domainCategories := make([]domain.Category, 0, len(s.categories))
+	for _, category := range s.categories {
+		domainCategories = append(domainCategories, s.toDomainCategory(category))
+	}
+
+	field, descending := req.SortField()
+	sort.Sort(categoriesBy{domainCategories, categoryComparisons[field], descending})
+
+	start, end := pageBounds(req.ListRequest, len(domainCategories))
+
+	return domainCategories[start:end], len(domainCategories), nil
+}
+
+func (s *service) UpdateCategory(domainCategory *domain.Category) (*domain.Category, error) {
+	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
+
+	s.lock()
+	defer s.unlock()
+
+	category, ok := s.categories[domainCategory.ID]
+	if !ok {
+		ctxLogger.Warnf("memory service - unable to update category with id %v as it does not exist", domainCategory.ID)
+		return nil, storage.NewStorageRecordNotFoundError()
+	}
+
+	if s.isCategoryTagTaken(domainCategory.Tag, domainCategory.ID) {
+		ctxLogger.Warn("memory service - unable to update category with a duplicate tag")
+		return nil, storage.NewStorageCategoryTagUniqueConstraintError()
+	}
+
+	category.Tag = domainCategory.Tag
+	category.UpdatedAt = s.now()
+	s.categories[category.ID] = category
+
+	return domainCategory, nil
+}
+
+func (s *service) DeleteCategory(domainCategory *domain.Category) error {
+	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
+
+	s.lock()
+	defer s.unlock()
+
+	if _, ok := s.categories[domainCategory.ID]; !ok {
+		ctxLogger.Warnf("memory service - unable to delete category with id %v as it does not exist", domainCategory.ID)
+		return storage.NewStorageRecordNotFoundError()
+	}
+
+	// Invitations reference their category just like the foreign key in postgres
+	if s.countInvitationsInCategory(domainCategory.ID) > 0 {
+		ctxLogger.Errorf("memory service - unable to delete category with id %v as invitations still reference it", domainCategory.ID)
+		return storage.NewStorageOperationError()
+	}
+
+	category := s.categories[domainCategory.ID]
+	category.DeletedAt = s.now()
+	s.deletedCategories[category.ID] = category
+	delete(s.categories, category.ID)
+
+	return nil
+}
+
+func (s *service) FindDeletedCategoryByID(categoryID int64) (*domain.Category, error) {
+	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
+
+	s.rlock()
+	defer s.runlock()
+
+	category, ok := s.deletedCategories[categoryID]
+	if !ok {
+		ctxLogger.Warnf("memory service - unable to find deleted category with id %v", categoryID)
+		return nil, storage.NewStorageRecordNotFoundError()
+	}
+
+	domainCategory := s.toDomainCategory(category)
+
+	return &domainCategory, nil
+}
+
+func (s *service) ListDeletedCategories() ([]domain.Category, error) {
+	s.rlock()
+	defer s.runlock()
+
+	categories := make([]category, 0, len(s.deletedCategories))
+	for _, category := range s.deletedCategories {
+		categories = append(categories, category)
+	}
+	sort.Sort(categoriesByDeletedAt(categories))
+
+	domainCategories := make([]domain.Category, len(categories))
+	for idx := range categories {
+		domainCategories[idx] = s.toDomainCategory(categories[idx])
+	}
+
+	return domainCategories, nil
+}
+
+func (s *service) RestoreCategory(domainCategory *domain.Category) (*domain.Category, error) {
+	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
+
+	s.lock()
+	defer s.unlock()
+
+	category, ok := s.deletedCategories[domainCategory.ID]
+	if !ok {
+		ctxLogger.Warnf("memory service - unable to restore category with id %v as it is not deleted", domainCategory.ID)
+		return nil, storage.NewStorageRecordNotFoundError()
+	}
+
+	if s.isCategoryTagTaken(category.Tag, category.ID) {
+		ctxLogger.Warn("memory service - unable to restore category with a duplicate tag")
+		return nil, storage.NewStorageCategoryTagUniqueConstraintError()
+	}
+
+	category.DeletedAt = time.Time{}
+	s.categories[category.ID] = category
+	delete(s.deletedCategories, category.ID)
+
+	restoredCategory := s.toDomainCategory(category)
+
+	return &restoredCategory, nil
+}
+
+func (s *service) PurgeCategories(deletedBefore time.Time) (int, error) {
+	s.lock()
+	defer s.unlock()
+
+	// Invitations in the trash still reference their category just like the foreign key in postgres
+	referenced := make(map[int64]bool)
+	for _, invitation := range s.invitations {
+		referenced[invitation.CategoryID] = true
+	}
+	for _, invitation := range s.deletedInvitations {
+		referenced[invitation.CategoryID] = true
+	}
+
+	purged := 0
+	for id, category := range s.deletedCategories {
+		if category.DeletedAt.Before(deletedBefore) && !referenced[id] {
+			delete(s.deletedCategories, id)
+			purged++
+		}
+	}
+
+	return purged, nil
+}
+
+func (s *service) isCategoryTagTaken(tag string, excludeID int64) bool {
+	for id, category := range s.categories {
+		if id != excludeID && equalFold(category.Tag, tag) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s *service) countInvitationsInCategory(categoryID int64) int {
+	total := 0
+	for _, invitation := range s.invitations {
+		if invitation.CategoryID == categoryID {
+			total++
+		}
+	}
+
+	return total
+}
+
+func (s *service) toDomainCategory(category category) domain.Category {
+	return domain.Category{
+		ID:        category.ID,
+		Tag:       category.Tag,
+		Total:     s.countInvitationsInCategory(category.ID),
+		DeletedAt: category.deletedAt(),
+	}
+}
+
+var categoryComparisons = map[string]func(left, right domain.Category) int{
+	domain.SortByCategoryTag: func(left, right domain.Category) int {
+		return strings.Compare(strings.ToLower(left.Tag), strings.ToLower(right.Tag))
+	},
+	domain.SortByCategoryTotal: func(left, right domain.Category) int {
+		return compareInt64s(int64(left.Total), int64(right.Total))
+	},
+}
+
+// categoriesBy sorts by the comparison of a single field, or by id alone when there is none
+type categoriesBy struct {
+	categories []domain.Category
+	compare    func(left, right domain.Category) int
+	descending bool
+}
+
+func (b categoriesBy) Len() int { return len(b.categories) }
+func (b categoriesBy) Swap(i, j int) {
+	b.categories[i], b.categories[j] = b.categories[j], b.categories[i]
+}
+func (b categoriesBy) Less(i, j int) bool {
+	result := 0
+	if b.compare != nil {
+		result = b.compare(b.categories[i], b.categories[j])
+	}
+
+	return less(result, b.categories[i].ID, b.categories[j].ID, b.descending)
+}
+
+// categoriesByDeletedAt sorts the trash from the most recently deleted
+type categoriesByDeletedAt []category
+
+func (b categoriesByDeletedAt) Len() int      { return len(b) }
+func (b categoriesByDeletedAt) Swap(i, j int) { b[i], b[j] = b[j], b[i] }
+func (b categoriesByDeletedAt) Less(i, j int) bool {
+	return less(compareTimes(b[i].DeletedAt, b[j].DeletedAt), b[i].ID, b[j].ID, true)
+}
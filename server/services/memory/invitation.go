@@ -0,0 +1,438 @@
+package memory
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rawfish-dev/rsvp-starter/server/domain"
+	"github.com/rawfish-dev/rsvp-starter/server/interfaces"
+	"github.com/rawfish-dev/rsvp-starter/server/services/storage"
+
+	"github.com/satori/go.uuid"
+)
+
+// invitation leaves LinkExpiresAt and LinkRevokedAt as the zero time until they apply
+type invitation struct {
+	baseModel
+	CategoryID               int64
+	PrivateID                string
+	Greeting                 string
+	MaximumGuestCount        int
+	Status                   string
+	Notes                    string
+	MobilePhoneNumber        string
+	Email                    string
+	Language                 string
+	LinkExpiresAt            time.Time
+	LinkRevokedAt            time.Time
+	RequirePhoneConfirmation bool
+	Version                  int64
+}
+
+func (i invitation) toDomain() domain.Invitation {
+	return domain.Invitation{
+		BaseInvitation: domain.BaseInvitation{
+			CategoryID:        i.CategoryID,
+			Greeting:          i.Greeting,
+			MaximumGuestCount: i.MaximumGuestCount,
+			Notes:             i.Notes,
+			MobilePhoneNumber: i.MobilePhoneNumber,
+			Email:             i.Email,
+			Language:          i.Language,
+		},
+		ID:                       i.ID,
+		PrivateID:                i.PrivateID,
+		Status:                   domain.RSVPStatus(i.Status),
+		LinkExpiresAt:            formatOptionalTime(i.LinkExpiresAt),
+		LinkRevokedAt:            formatOptionalTime(i.LinkRevokedAt),
+		RequirePhoneConfirmation: i.RequirePhoneConfirmation,
+		UpdatedAt:                i.UpdatedAt.Format(time.RFC3339),
+		Version:                  i.Version,
+		DeletedAt:                i.deletedAt(),
+	}
+}
+
+// applyReply overrides the stored status once the guests replied, just as the sql backends derive
+// it from the joined rsvp.
+func (i *invitation) applyReply(attendance map[string]bool) {
+	if attending, ok := attendance[i.PrivateID]; ok {
+		i.Status = string(domain.RepliedNotAttending)
+		if attending {
+			i.Status = string(domain.RepliedAttending)
+		}
+	}
+}
+
+// rsvpAttendance maps invitation private ids to whether the guests are attending and must be
+// called with the lock held.
+func (s *service) rsvpAttendance() map[string]bool {
+	attendance := make(map[string]bool, len(s.rsvps))
+	for _, rsvp := range s.rsvps {
+		attendance[rsvp.InvitationPrivateID] = rsvp.Attending
+	}
+
+	return attendance
+}
+
+func (s *service) InsertInvitation(req *domain.InvitationCreateRequest) (*domain.Invitation, error) {
+	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
+
+	s.lock()
+	defer s.unlock()
+
+	invitation := invitation{
+		CategoryID:        req.CategoryID,
+		PrivateID:         uuid.NewV4().String(),
+		Greeting:          req.Greeting,
+		MaximumGuestCount: req.MaximumGuestCount,
+		Status:            string(domain.NotSent),
+		Notes:             req.Notes,
+		MobilePhoneNumber: req.MobilePhoneNumber,
+		Email:             req.Email,
+		Language:          req.Language,
+		Version:           1,
+	}
+
+	err := s.checkInvitationConstraints(invitation)
+	if err != nil {
+		ctxLogger.Warnf("memory service - unable to insert invitation %v due to %v", invitation.Greeting, err)
+		return nil, err
+	}
+
+	s.lastInvitationID++
+	timestamp := s.now()
+
+	invitation.ID = s.lastInvitationID
+	invitation.CreatedAt = timestamp
+	invitation.UpdatedAt = timestamp
+	s.invitations[invitation.ID] = invitation
+
+	newInvitation := invitation.toDomain()
+
+	return &newInvitation, nil
+}
+
+func (s *service) FindInvitationByID(invitationID int64) (*domain.Invitation, error) {
+	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
+
+	s.rlock()
+	defer s.runlock()
+
+	invitation, ok := s.invitations[invitationID]
+	if !ok {
+		ctxLogger.Warnf("memory service - unable to find invitation with id %v", invitationID)
+		return nil, storage.NewStorageRecordNotFoundError()
+	}
+
+	domainInvitation := invitation.toDomain()
+
+	return &domainInvitation, nil
+}
+
+func (s *service) FindInvitationByPrivateID(privateID string) (*domain.Invitation, error) {
+	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
+
+	s.rlock()
+	defer s.runlock()
+
+	for _, invitation := range s.invitations {
+		if invitation.PrivateID == privateID {
+			domainInvitation := invitation.toDomain()
+			return &domainInvitation, nil
+		}
+	}
+
+	ctxLogger.Warnf("memory service - unable to find invitation with private id %v", privateID)
+	return nil, storage.NewStorageRecordNotFoundError()
+}
+
+func (s *service) ListInvitations(req *domain.InvitationListRequest) ([]domain.Invitation, int, error) {
+	s.rlock()
+	defer s.runlock()
+
+	attendance := s.rsvpAttendance()
+
+	invitations := make([]invitation, 0, len(s.invitations))
+	for _, invitation := range s.invitations {
+		invitation.applyReply(attendance)
+
+		if req.CategoryID != 0 && invitation.CategoryID != req.CategoryID {
+			continue
+		}
+		if req.Status != "" && invitation.Status != string(req.Status) {
+			continue
+		}
+		if req.UpdatedSince != nil && invitation.UpdatedAt.Before(*req.UpdatedSince) {
+			continue
+		}
+
+		invitations = append(invitations, invitation)
+	}
+
+	field, descending := req.SortField()
+	sort.Sort(invitationsBy{invitations, invitationComparisons[field], descending})
+
+	start, end := pageBounds(req.ListRequest, len(invitations))
+
+	domainInvitations := make([]domain.Invitation, 0, end-start)
+	for idx := start; idx < end; idx++ {
+		domainInvitations = append(domainInvitations, invitations[idx].toDomain())
+	}
+
+	return domainInvitations, len(invitations), nil
+}
+
+func (s *service) UpdateInvitation(domainInvitation *domain.Invitation) (*domain.Invitation, error) {
+	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
+
+	linkExpiresAt, err := storage.ParseOptionalTimestamp(domainInvitation.LinkExpiresAt)
+	if err != nil {
+		ctxLogger.Errorf("memory service - unable to update invitation %v due to %v", domainInvitation.ID, err)
+		return nil, storage.NewStorageOperationError()
+	}
+
+	linkRevokedAt, err := storage.ParseOptionalTimestamp(domainInvitation.LinkRevokedAt)
+	if err != nil {
+		ctxLogger.Errorf("memory service - unable to update invitation %v due to %v", domainInvitation.ID, err)
+		return nil, storage.NewStorageOperationError()
+	}
+
+	s.lock()
+	defer s.unlock()
+
+	invitation, ok := s.invitations[domainInvitation.ID]
+	if !ok {
+		ctxLogger.Warnf("memory service - unable to update invitation with id %v as it does not exist", domainInvitation.ID)
+		return nil, storage.NewStorageRecordNotFoundError()
+	}
+
+	if invitation.Version != domainInvitation.Version {
+		ctxLogger.Warnf("memory service - unable to update invitation with id %v as version %v is not the latest", domainInvitation.ID, domainInvitation.Version)
+		return nil, storage.NewStorageVersionConflictError()
+	}
+
+	invitation.CategoryID = domainInvitation.CategoryID
+	invitation.PrivateID = domainInvitation.PrivateID
+	invitation.Greeting = domainInvitation.Greeting
+	invitation.MaximumGuestCount = domainInvitation.MaximumGuestCount
+	invitation.Status = string(domainInvitation.Status)
+	invitation.Notes = domainInvitation.Notes
+	invitation.MobilePhoneNumber = domainInvitation.MobilePhoneNumber
+	invitation.Email = domainInvitation.Email
+	invitation.Language = domainInvitation.Language
+	invitation.LinkExpiresAt = time.Time{}
+	if linkExpiresAt != nil {
+		invitation.LinkExpiresAt = *linkExpiresAt
+	}
+	invitation.LinkRevokedAt = time.Time{}
+	if linkRevokedAt != nil {
+		invitation.LinkRevokedAt = *linkRevokedAt
+	}
+	invitation.RequirePhoneConfirmation = domainInvitation.RequirePhoneConfirmation
+
+	err = s.checkInvitationConstraints(invitation)
+	if err != nil {
+		ctxLogger.Warnf("memory service - unable to update invitation %v due to %v", invitation.ID, err)
+		return nil, err
+	}
+
+	invitation.UpdatedAt = s.now()
+	invitation.Version++
+	s.invitations[invitation.ID] = invitation
+
+	domainInvitation.UpdatedAt = invitation.UpdatedAt.Format(time.RFC3339)
+	domainInvitation.Version = invitation.Version
+
+	return domainInvitation, nil
+}
+
+func (s *service) DeleteInvitation(domainInvitation *domain.Invitation) error {
+	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
+
+	s.lock()
+	defer s.unlock()
+
+	if _, ok := s.invitations[domainInvitation.ID]; !ok {
+		ctxLogger.Warnf("memory service - unable to delete invitation with id %v as it does not exist", domainInvitation.ID)
+		return storage.NewStorageRecordNotFoundError()
+	}
+
+	invitation := s.invitations[domainInvitation.ID]
+	invitation.DeletedAt = s.now()
+	s.deletedInvitations[invitation.ID] = invitation
+	delete(s.invitations, invitation.ID)
+
+	return nil
+}
+
+func (s *service) FindDeletedInvitationByID(invitationID int64) (*domain.Invitation, error) {
+	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
+
+	s.rlock()
+	defer s.runlock()
+
+	invitation, ok := s.deletedInvitations[invitationID]
+	if !ok {
+		ctxLogger.Warnf("memory service - unable to find deleted invitation with id %v", invitationID)
+		return nil, storage.NewStorageRecordNotFoundError()
+	}
+
+	domainInvitation := invitation.toDomain()
+
+	return &domainInvitation, nil
+}
+
+func (s *service) ListDeletedInvitations() ([]domain.Invitation, error) {
+	s.rlock()
+	defer s.runlock()
+
+	invitations := make([]invitation, 0, len(s.deletedInvitations))
+	for _, invitation := range s.deletedInvitations {
+		invitations = append(invitations, invitation)
+	}
+	sort.Sort(invitationsByDeletedAt(invitations))
+
+	domainInvitations := make([]domain.Invitation, len(invitations))
+	for idx := range invitations {
+		domainInvitations[idx] = invitations[idx].toDomain()
+	}
+
+	return domainInvitations, nil
+}
+
+func (s *service) RestoreInvitation(domainInvitation *domain.Invitation) (*domain.Invitation, error) {
+	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
+
+	s.lock()
+	defer s.unlock()
+
+	invitation, ok := s.deletedInvitations[domainInvitation.ID]
+	if !ok {
+		ctxLogger.Warnf("memory service - unable to restore invitation with id %v as it is not deleted", domainInvitation.ID)
+		return nil, storage.NewStorageRecordNotFoundError()
+	}
+
+	err := s.checkInvitationConstraints(invitation)
+	if err != nil {
+		ctxLogger.Warnf("memory service - unable to restore invitation %v due to %v", invitation.ID, err)
+		return nil, err
+	}
+
+	invitation.DeletedAt = time.Time{}
+	s.invitations[invitation.ID] = invitation
+	delete(s.deletedInvitations, invitation.ID)
+
+	restoredInvitation := invitation.toDomain()
+
+	return &restoredInvitation, nil
+}
+
+func (s *service) PurgeInvitations(deletedBefore time.Time) (int, error) {
+	s.lock()
+	defer s.unlock()
+
+	purged := 0
+	for id, invitation := range s.deletedInvitations {
+		if invitation.DeletedAt.Before(deletedBefore) {
+			delete(s.deletedInvitations, id)
+			purged++
+
+			for messageID, message := range s.messages {
+				if message.InvitationID == id {
+					delete(s.messages, messageID)
+				}
+			}
+			for privateID, invitationID := range s.retiredInvitationLinks {
+				if invitationID == id {
+					delete(s.retiredInvitationLinks, privateID)
+				}
+			}
+		}
+	}
+
+	return purged, nil
+}
+
+func (s *service) InsertRetiredInvitationLink(invitationID int64, privateID string) error {
+	s.lock()
+	defer s.unlock()
+
+	s.retiredInvitationLinks[privateID] = invitationID
+
+	return nil
+}
+
+func (s *service) IsInvitationLinkRetired(privateID string) (bool, error) {
+	s.rlock()
+	defer s.runlock()
+
+	_, retired := s.retiredInvitationLinks[privateID]
+
+	return retired, nil
+}
+
+// checkInvitationConstraints must be called with the write lock held.
+func (s *service) checkInvitationConstraints(candidate invitation) error {
+	if _, ok := s.categories[candidate.CategoryID]; !ok {
+		return storage.NewStorageOperationError()
+	}
+
+	for id, invitation := range s.invitations {
+		if id == candidate.ID {
+			continue
+		}
+		if equalFold(invitation.Greeting, candidate.Greeting) {
+			return storage.NewStorageInvitationGreetingUniqueConstraintError()
+		}
+		if invitation.PrivateID == candidate.PrivateID {
+			return storage.NewStorageOperationError()
+		}
+	}
+
+	return nil
+}
+
+var invitationComparisons = map[string]func(left, right invitation) int{
+	domain.SortByInvitationGreeting: func(left, right invitation) int {
+		return strings.Compare(strings.ToLower(left.Greeting), strings.ToLower(right.Greeting))
+	},
+	domain.SortByInvitationMaximumGuestCount: func(left, right invitation) int {
+		return compareInt64s(int64(left.MaximumGuestCount), int64(right.MaximumGuestCount))
+	},
+	domain.SortByInvitationStatus: func(left, right invitation) int {
+		return strings.Compare(left.Status, right.Status)
+	},
+	domain.SortByInvitationUpdatedAt: func(left, right invitation) int {
+		return compareTimes(left.UpdatedAt, right.UpdatedAt)
+	},
+}
+
+// invitationsBy sorts by the comparison of a single field, or by id alone when there is none
+type invitationsBy struct {
+	invitations []invitation
+	compare     func(left, right invitation) int
+	descending  bool
+}
+
+func (b invitationsBy) Len() int { return len(b.invitations) }
+func (b invitationsBy) Swap(i, j int) {
+	b.invitations[i], b.invitations[j] = b.invitations[j], b.invitations[i]
+}
+func (b invitationsBy) Less(i, j int) bool {
+	result := 0
+	if b.compare != nil {
+		result = b.compare(b.invitations[i], b.invitations[j])
+	}
+
+	return less(result, b.invitations[i].ID, b.invitations[j].ID, b.descending)
+}
+
+// invitationsByDeletedAt sorts the trash from the most recently deleted
+type invitationsByDeletedAt []invitation
+
+func (b invitationsByDeletedAt) Len() int      { return len(b) }
+func (b invitationsByDeletedAt) Swap(i, j int) { b[i], b[j] = b[j], b[i] }
+func (b invitationsByDeletedAt) Less(i, j int) bool {
+	return less(compareTimes(b[i].DeletedAt, b[j].DeletedAt), b[i].ID, b[j].ID, true)
+}
@@ -0,0 +1,58 @@
+package memory
+
+import (
+	"time"
+
+	"github.com/rawfish-dev/rsvp-starter/server/domain"
+)
+
+// pageBounds returns the bounds of the requested page within total sorted records.
+func pageBounds(req domain.ListRequest, total int) (start, end int) {
+	start = req.Offset()
+	if start > total {
+		start = total
+	}
+
+	end = start + req.PageSize
+	if end > total {
+		end = total
+	}
+
+	return start, end
+}
+
+// less orders by the result of a field comparison, falling back on the ids the same way the sql
+// backends break ties so both agree on which page a record lands on.
+func less(result int, leftID, rightID int64, descending bool) bool {
+	if result == 0 {
+		result = compareInt64s(leftID, rightID)
+	}
+
+	if descending {
+		return result > 0
+	}
+
+	return result < 0
+}
+
+func compareInt64s(left, right int64) int {
+	switch {
+	case left < right:
+		return -1
+	case left > right:
+		return 1
+	}
+
+	return 0
+}
+
+func compareTimes(left, right time.Time) int {
+	switch {
+	case left.Before(right):
+		return -1
+	case left.After(right):
+		return 1
+	}
+
+	return 0
+}
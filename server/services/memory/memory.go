@@ -0,0 +1,290 @@
+package memory
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rawfish-dev/rsvp-starter/server/interfaces"
+
+	"golang.org/x/net/context"
+)
+
+var _ interfaces.Storage = new(service)
+
+// service keeps every record in maps guarded by a single lock. It follows the same rules as the
+// migrated postgres schema: tags, greetings, invitation private ids and rsvp invitation private ids
+// are unique ignoring case where postgres uses LOWER() among records outside the trash, and invitations
+// must reference an existing category. Mobile phone numbers are not unique since the postgres index was dropped in 20161018134623.
+type service struct {
+	ctx   context.Context
+	mutex *sync.RWMutex
+
+	// inTx is set on the copy handed to WithTx callbacks, which already hold the write lock
+	inTx bool
+
+	*records
+}
+
+// records keeps deleted records apart from the live ones until they are purged, so only the methods
+// dealing with the trash ever need to look at them.
+type records struct {
+	categories  map[int64]category
+	invitations map[int64]invitation
+	rsvps       map[int64]rsvp
+
+	deletedCategories  map[int64]category
+	deletedInvitations map[int64]invitation
+	deletedRSVPs       map[int64]rsvp
+
+	users    map[int64]user
+	apiKeys  map[int64]apiKey
+	messages map[int64]message
+
+	messageTemplates map[int64]messageTemplate
+
+	// retiredInvitationLinks maps the private ids invitations used to have to the invitation ids
+	retiredInvitationLinks map[string]int64
+
+	// auditEntries is append only and kept in the order the entries were inserted
+	auditEntries []auditEntry
+
+	lastCategoryID        int64
+	lastInvitationID      int64
+	lastRSVPID            int64
+	lastAuditEntryID      int64
+	lastUserID            int64
+	lastAPIKeyID          int64
+	lastMessageID         int64
+	lastMessageTemplateID int64
+	lastTimestamp         time.Time
+}
+
+type baseModel struct {
+	ID        int64
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	DeletedAt time.Time
+}
+
+// deletedAt formats the deletion time for the domain models, which leave it empty for live records.
+func (b baseModel) deletedAt() string {
+	if b.DeletedAt.IsZero() {
+		return ""
+	}
+
+	return b.DeletedAt.Format(time.RFC3339)
+}
+
+var singletonService *service
+var once sync.Once
+
+func NewService(ctx context.Context) *service {
+	once.Do(func() {
+		singletonService = newService(ctx)
+	})
+
+	return singletonService
+}
+
+func newService(ctx context.Context) *service {
+	return &service{
+		ctx:   ctx,
+		mutex: &sync.RWMutex{},
+		records: &records{
+			categories:         make(map[int64]category),
+			invitations:        make(map[int64]invitation),
+			rsvps:              make(map[int64]rsvp),
+			deletedCategories:  make(map[int64]category),
+			deletedInvitations: make(map[int64]invitation),
+			deletedRSVPs:       make(map[int64]rsvp),
+			users:              make(map[int64]user),
+			apiKeys:            make(map[int64]apiKey),
+			messages:           make(map[int64]message),
+			messageTemplates:   make(map[int64]messageTemplate),
+
+			retiredInvitationLinks: make(map[string]int64),
+		},
+	}
+}
+
+// Flush removes every record, mainly so tests can start from a clean slate.
+func (s *service) Flush() {
+	s.lock()
+	defer s.unlock()
+
+	s.categories = make(map[int64]category)
+	s.invitations = make(map[int64]invitation)
+	s.rsvps = make(map[int64]rsvp)
+	s.deletedCategories = make(map[int64]category)
+	s.deletedInvitations = make(map[int64]invitation)
+	s.deletedRSVPs = make(map[int64]rsvp)
+	s.users = make(map[int64]user)
+	s.apiKeys = make(map[int64]apiKey)
+	s.messages = make(map[int64]message)
+	s.messageTemplates = make(map[int64]messageTemplate)
+	s.retiredInvitationLinks = make(map[string]int64)
+	s.auditEntries = nil
+}
+
+// WithTx holds the write lock for the whole of fn and restores a copy of the records taken
+// beforehand when fn fails, so other callers never observe a partially applied transaction.
+func (s *service) WithTx(fn func(tx interfaces.Storage) error) error {
+	if s.inTx {
+		return fn(s)
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	snapshot := s.records.copy()
+
+	// Restore the snapshot when fn fails or panics
+	committed := false
+	defer func() {
+		if !committed {
+			*s.records = snapshot
+		}
+	}()
+
+	err := fn(&service{ctx: s.ctx, mutex: s.mutex, inTx: true, records: s.records})
+	if err != nil {
+		return err
+	}
+
+	committed = true
+
+	return nil
+}
+
+func (r *records) copy() records {
+	copied := *r
+
+	copied.categories = copyCategories(r.categories)
+	copied.invitations = copyInvitations(r.invitations)
+	copied.rsvps = copyRSVPs(r.rsvps)
+	copied.deletedCategories = copyCategories(r.deletedCategories)
+	copied.deletedInvitations = copyInvitations(r.deletedInvitations)
+	copied.deletedRSVPs = copyRSVPs(r.deletedRSVPs)
+	copied.users = copyUsers(r.users)
+	copied.apiKeys = copyAPIKeys(r.apiKeys)
+	copied.messages = copyMessages(r.messages)
+	copied.messageTemplates = copyMessageTemplates(r.messageTemplates)
+	copied.retiredInvitationLinks = copyRetiredInvitationLinks(r.retiredInvitationLinks)
+	copied.auditEntries = append([]auditEntry(nil), r.auditEntries...)
+
+	return copied
+}
+
+func copyCategories(categories map[int64]category) map[int64]category {
+	copied := make(map[int64]category, len(categories))
+	for id, category := range categories {
+		copied[id] = category
+	}
+
+	return copied
+}
+
+func copyInvitations(invitations map[int64]invitation) map[int64]invitation {
+	copied := make(map[int64]invitation, len(invitations))
+	for id, invitation := range invitations {
+		copied[id] = invitation
+	}
+
+	return copied
+}
+
+func copyRSVPs(rsvps map[int64]rsvp) map[int64]rsvp {
+	copied := make(map[int64]rsvp, len(rsvps))
+	for id, rsvp := range rsvps {
+		copied[id] = rsvp
+	}
+
+	return copied
+}
+
+func copyUsers(users map[int64]user) map[int64]user {
+	copied := make(map[int64]user, len(users))
+	for id, user := range users {
+		copied[id] = user
+	}
+
+	return copied
+}
+
+func copyAPIKeys(apiKeys map[int64]apiKey) map[int64]apiKey {
+	copied := make(map[int64]apiKey, len(apiKeys))
+	for id, apiKey := range apiKeys {
+		copied[id] = apiKey
+	}
+
+	return copied
+}
+
+func copyMessages(messages map[int64]message) map[int64]message {
+	copied := make(map[int64]message, len(messages))
+	for id, message := range messages {
+		copied[id] = message
+	}
+
+	return copied
+}
+
+func copyMessageTemplates(messageTemplates map[int64]messageTemplate) map[int64]messageTemplate {
+	copied := make(map[int64]messageTemplate, len(messageTemplates))
+	for id, messageTemplate := range messageTemplates {
+		copied[id] = messageTemplate
+	}
+
+	return copied
+}
+
+func copyRetiredInvitationLinks(retiredInvitationLinks map[string]int64) map[string]int64 {
+	copied := make(map[string]int64, len(retiredInvitationLinks))
+	for privateID, invitationID := range retiredInvitationLinks {
+		copied[privateID] = invitationID
+	}
+
+	return copied
+}
+
+// The lock helpers are no-ops inside WithTx since the transaction already holds the write lock.
+func (s *service) lock() {
+	if !s.inTx {
+		s.mutex.Lock()
+	}
+}
+
+func (s *service) unlock() {
+	if !s.inTx {
+		s.mutex.Unlock()
+	}
+}
+
+func (s *service) rlock() {
+	if !s.inTx {
+		s.mutex.RLock()
+	}
+}
+
+func (s *service) runlock() {
+	if !s.inTx {
+		s.mutex.RUnlock()
+	}
+}
+
+// now must be called with the write lock held. Timestamps always move forward so ordering by
+// updated at is deterministic even when records are written within the same clock tick.
+func (s *service) now() time.Time {
+	timestamp := time.Now().UTC()
+	if !timestamp.After(s.lastTimestamp) {
+		timestamp = s.lastTimestamp.Add(time.Nanosecond)
+	}
+	s.lastTimestamp = timestamp
+
+	return timestamp
+}
+
+func equalFold(a, b string) bool {
+	return strings.ToLower(a) == strings.ToLower(b)
+}
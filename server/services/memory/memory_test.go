@@ -0,0 +1,102 @@
+package memory_test
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/rawfish-dev/rsvp-starter/server/domain"
+	"github.com/rawfish-dev/rsvp-starter/server/interfaces"
+	. "github.com/rawfish-dev/rsvp-starter/server/services/memory"
+	"github.com/rawfish-dev/rsvp-starter/server/services/storage/storagetest"
+
+	"github.com/Sirupsen/logrus"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"golang.org/x/net/context"
+)
+
+var _ = Describe("Memory", func() {
+
+	var testMemoryService interfaces.Storage
+
+	BeforeEach(func() {
+		ctxlogger := logrus.New()
+		ctx := context.Background()
+		ctx = context.WithValue(ctx, "logger", ctxlogger)
+
+		memoryService := NewService(ctx)
+		memoryService.Flush()
+
+		testMemoryService = memoryService
+	})
+
+	Context("storage contract", func() {
+
+		storagetest.StorageContract(func() interfaces.Storage {
+			return testMemoryService
+		})
+	})
+
+	Context("concurrency", func() {
+
+		var categoryID int64
+
+		BeforeEach(func() {
+			newCategory, err := testMemoryService.InsertCategory(&domain.CategoryCreateRequest{Tag: "friends"})
+			Expect(err).ToNot(HaveOccurred())
+
+			categoryID = newCategory.ID
+		})
+
+		It("should handle concurrent inserts", func() {
+			var waitGroup sync.WaitGroup
+			for idx := 0; idx < 20; idx++ {
+				waitGroup.Add(1)
+				go func(idx int) {
+					defer GinkgoRecover()
+					defer waitGroup.Done()
+
+					_, err := testMemoryService.InsertInvitation(&domain.InvitationCreateRequest{
+						BaseInvitation: domain.BaseInvitation{
+							CategoryID:        categoryID,
+							Greeting:          fmt.Sprintf("guest %v", idx),
+							MaximumGuestCount: 2,
+						},
+					})
+					Expect(err).ToNot(HaveOccurred())
+				}(idx)
+			}
+			waitGroup.Wait()
+
+			category, err := testMemoryService.FindCategoryByID(categoryID)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(category.Total).To(Equal(20))
+		})
+	})
+
+	Context("demo data", func() {
+
+		It("should seed categories, invitations and rsvps", func() {
+			ctxlogger := logrus.New()
+			ctx := context.Background()
+			ctx = context.WithValue(ctx, "logger", ctxlogger)
+
+			err := NewService(ctx).SeedDemoData()
+			Expect(err).ToNot(HaveOccurred())
+
+			listRequest := domain.ListRequest{Page: 1, PageSize: domain.DefaultPageSize}
+
+			categories, _, err := testMemoryService.ListCategories(&domain.CategoryListRequest{ListRequest: listRequest})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(categories).ToNot(BeEmpty())
+
+			invitations, _, err := testMemoryService.ListInvitations(&domain.InvitationListRequest{ListRequest: listRequest})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(invitations).ToNot(BeEmpty())
+
+			rsvps, _, err := testMemoryService.ListRSVPs(&domain.RSVPListRequest{ListRequest: listRequest})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(rsvps).ToNot(BeEmpty())
+		})
+	})
+})
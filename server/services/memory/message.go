@@ -0,0 +1,191 @@
+package memory
+
+import (
+	"sort"
+	"time"
+
+	"github.com/rawfish-dev/rsvp-starter/server/domain"
+	"github.com/rawfish-dev/rsvp-starter/server/interfaces"
+	"github.com/rawfish-dev/rsvp-starter/server/services/storage"
+)
+
+type message struct {
+	ID                int64
+	InvitationID      int64
+	Kind              string
+	Channel           string
+	Recipient         string
+	Subject           string
+	Body              string
+	Provider          string
+	ProviderMessageID string
+	Status            string
+	Error             string
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
+}
+
+func (m message) toDomain() domain.Message {
+	return domain.Message{
+		ID:                m.ID,
+		InvitationID:      m.InvitationID,
+		Kind:              domain.MessageKind(m.Kind),
+		Channel:           domain.MessageChannel(m.Channel),
+		Recipient:         m.Recipient,
+		Subject:           m.Subject,
+		Body:              m.Body,
+		Provider:          m.Provider,
+		ProviderMessageID: m.ProviderMessageID,
+		Status:            domain.MessageStatus(m.Status),
+		Error:             m.Error,
+		CreatedAt:         m.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:         m.UpdatedAt.Format(time.RFC3339),
+	}
+}
+
+func (s *service) InsertMessage(domainMessage *domain.Message) (*domain.Message, error) {
+	s.lock()
+	defer s.unlock()
+
+	s.lastMessageID++
+
+	message := message{
+		ID:                s.lastMessageID,
+		InvitationID:      domainMessage.InvitationID,
+		Kind:              string(domainMessage.Kind),
+		Channel:           string(domainMessage.Channel),
+		Recipient:         domainMessage.Recipient,
+		Subject:           domainMessage.Subject,
+		Body:              domainMessage.Body,
+		Provider:          domainMessage.Provider,
+		ProviderMessageID: domainMessage.ProviderMessageID,
+		Status:            string(domainMessage.Status),
+		Error:             domainMessage.Error,
+		CreatedAt:         s.now(),
+	}
+	message.UpdatedAt = message.CreatedAt
+	s.messages[message.ID] = message
+
+	newMessage := message.toDomain()
+
+	return &newMessage, nil
+}
+
+func (s *service) FindMessageByProviderMessageID(provider, providerMessageID string) (*domain.Message, error) {
+	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
+
+	s.rlock()
+	defer s.runlock()
+
+	var found message
+	for _, message := range s.messages {
+		if message.Provider != provider || message.ProviderMessageID != providerMessageID {
+			continue
+		}
+		if message.ID > found.ID {
+			found = message
+		}
+	}
+
+	if found.ID == 0 {
+		ctxLogger.Warnf("memory service - unable to find message %v from %v", providerMessageID, provider)
+		return nil, storage.NewStorageRecordNotFoundError()
+	}
+
+	domainMessage := found.toDomain()
+
+	return &domainMessage, nil
+}
+
+func (s *service) ListMessagesByInvitationID(invitationID int64) ([]domain.Message, error) {
+	s.rlock()
+	defer s.runlock()
+
+	var messages []message
+	for _, message := range s.messages {
+		if message.InvitationID == invitationID {
+			messages = append(messages, message)
+		}
+	}
+	sort.Sort(messagesByID(messages))
+
+	domainMessages := make([]domain.Message, len(messages))
+	for idx := range messages {
+		domainMessages[idx] = messages[idx].toDomain()
+	}
+
+	return domainMessages, nil
+}
+
+func (s *service) ListMessagesByKind(kind domain.MessageKind) ([]domain.Message, error) {
+	s.rlock()
+	defer s.runlock()
+
+	var messages []message
+	for _, message := range s.messages {
+		if message.Kind == string(kind) {
+			messages = append(messages, message)
+		}
+	}
+	sort.Sort(messagesByID(messages))
+
+	domainMessages := make([]domain.Message, len(messages))
+	for idx := range messages {
+		domainMessages[idx] = messages[idx].toDomain()
+	}
+
+	return domainMessages, nil
+}
+
+func (s *service) ListMessagesByInvitationIDs(invitationIDs []int64) ([]domain.Message, error) {
+	s.rlock()
+	defer s.runlock()
+
+	wanted := make(map[int64]bool, len(invitationIDs))
+	for _, invitationID := range invitationIDs {
+		wanted[invitationID] = true
+	}
+
+	var messages []message
+	for _, message := range s.messages {
+		if wanted[message.InvitationID] {
+			messages = append(messages, message)
+		}
+	}
+	sort.Sort(messagesByID(messages))
+
+	domainMessages := make([]domain.Message, len(messages))
+	for idx := range messages {
+		domainMessages[idx] = messages[idx].toDomain()
+	}
+
+	return domainMessages, nil
+}
+
+func (s *service) UpdateMessage(domainMessage *domain.Message) (*domain.Message, error) {
+	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
+
+	s.lock()
+	defer s.unlock()
+
+	message, ok := s.messages[domainMessage.ID]
+	if !ok {
+		ctxLogger.Warnf("memory service - unable to update message with id %v as it does not exist", domainMessage.ID)
+		return nil, storage.NewStorageRecordNotFoundError()
+	}
+
+	message.Status = string(domainMessage.Status)
+	message.Error = domainMessage.Error
+	message.UpdatedAt = s.now()
+	s.messages[message.ID] = message
+
+	updatedMessage := message.toDomain()
+
+	return &updatedMessage, nil
+}
+
+type messagesByID []message
+
+func (b messagesByID) Len() int           { return len(b) }
+func (b messagesByID) Swap(i, j int)      { b[i], b[j] = b[j], b[i] }
+func (b messagesByID) Less(i, j int) bool { return b[i].ID < b[j].ID }
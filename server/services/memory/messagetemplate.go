@@ -0,0 +1,177 @@
+package memory
+
+import (
+	"sort"
+	"time"
+
+	"github.com/rawfish-dev/rsvp-starter/server/domain"
+	"github.com/rawfish-dev/rsvp-starter/server/interfaces"
+	"github.com/rawfish-dev/rsvp-starter/server/services/storage"
+)
+
+type messageTemplate struct {
+	baseModel
+	Name       string
+	Kind       string
+	Channel    string
+	CategoryID int64
+	Language   string
+	Subject    string
+	Body       string
+}
+
+func (m messageTemplate) toDomain() domain.MessageTemplate {
+	return domain.MessageTemplate{
+		BaseMessageTemplate: domain.BaseMessageTemplate{
+			Name:       m.Name,
+			Kind:       domain.MessageTemplateKind(m.Kind),
+			Channel:    domain.MessageChannel(m.Channel),
+			CategoryID: m.CategoryID,
+			Language:   m.Language,
+			Subject:    m.Subject,
+			Body:       m.Body,
+		},
+		ID:        m.ID,
+		CreatedAt: m.CreatedAt.Format(time.RFC3339),
+		UpdatedAt: m.UpdatedAt.Format(time.RFC3339),
+	}
+}
+
+func (s *service) InsertMessageTemplate(req *domain.MessageTemplateCreateRequest) (*domain.MessageTemplate, error) {
+	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
+
+	s.lock()
+	defer s.unlock()
+
+	if s.isMessageTemplateTaken(req.BaseMessageTemplate, 0) {
+		ctxLogger.Warn("memory service - unable to insert message template for a kind, channel, category and language that already has one")
+		return nil, storage.NewStorageMessageTemplateUniqueConstraintError()
+	}
+
+	s.lastMessageTemplateID++
+	timestamp := s.now()
+
+	messageTemplate := messageTemplate{
+		baseModel: baseModel{
+			ID:        s.lastMessageTemplateID,
+			CreatedAt: timestamp,
+			UpdatedAt: timestamp,
+		},
+		Name:       req.Name,
+		Kind:       string(req.Kind),
+		Channel:    string(req.Channel),
+		CategoryID: req.CategoryID,
+		Language:   req.Language,
+		Subject:    req.Subject,
+		Body:       req.Body,
+	}
+	s.messageTemplates[messageTemplate.ID] = messageTemplate
+
+	newMessageTemplate := messageTemplate.toDomain()
+
+	return &newMessageTemplate, nil
+}
+
+func (s *service) FindMessageTemplateByID(templateID int64) (*domain.MessageTemplate, error) {
+	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
+
+	s.rlock()
+	defer s.runlock()
+
+	messageTemplate, ok := s.messageTemplates[templateID]
+	if !ok {
+		ctxLogger.Warnf("memory service - unable to find message template with id %v", templateID)
+		return nil, storage.NewStorageRecordNotFoundError()
+	}
+
+	domainMessageTemplate := messageTemplate.toDomain()
+
+	return &domainMessageTemplate, nil
+}
+
+func (s *service) ListMessageTemplates() ([]domain.MessageTemplate, error) {
+	s.rlock()
+	defer s.runlock()
+
+	messageTemplates := make([]messageTemplate, 0, len(s.messageTemplates))
+	for _, messageTemplate := range s.messageTemplates {
+		messageTemplates = append(messageTemplates, messageTemplate)
+	}
+	sort.Sort(messageTemplatesByID(messageTemplates))
+
+	domainMessageTemplates := make([]domain.MessageTemplate, len(messageTemplates))
+	for idx := range messageTemplates {
+		domainMessageTemplates[idx] = messageTemplates[idx].toDomain()
+	}
+
+	return domainMessageTemplates, nil
+}
+
+func (s *service) UpdateMessageTemplate(domainMessageTemplate *domain.MessageTemplate) (*domain.MessageTemplate, error) {
+	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
+
+	s.lock()
+	defer s.unlock()
+
+	messageTemplate, ok := s.messageTemplates[domainMessageTemplate.ID]
+	if !ok {
+		ctxLogger.Warnf("memory service - unable to update message template with id %v as it does not exist", domainMessageTemplate.ID)
+		return nil, storage.NewStorageRecordNotFoundError()
+	}
+
+	if s.isMessageTemplateTaken(domainMessageTemplate.BaseMessageTemplate, domainMessageTemplate.ID) {
+		ctxLogger.Warn("memory service - unable to update message template to a kind, channel, category and language that already has one")
+		return nil, storage.NewStorageMessageTemplateUniqueConstraintError()
+	}
+
+	messageTemplate.Name = domainMessageTemplate.Name
+	messageTemplate.Kind = string(domainMessageTemplate.Kind)
+	messageTemplate.Channel = string(domainMessageTemplate.Channel)
+	messageTemplate.CategoryID = domainMessageTemplate.CategoryID
+	messageTemplate.Language = domainMessageTemplate.Language
+	messageTemplate.Subject = domainMessageTemplate.Subject
+	messageTemplate.Body = domainMessageTemplate.Body
+	messageTemplate.UpdatedAt = s.now()
+	s.messageTemplates[messageTemplate.ID] = messageTemplate
+
+	updatedMessageTemplate := messageTemplate.toDomain()
+
+	return &updatedMessageTemplate, nil
+}
+
+func (s *service) DeleteMessageTemplate(domainMessageTemplate *domain.MessageTemplate) error {
+	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
+
+	s.lock()
+	defer s.unlock()
+
+	if _, ok := s.messageTemplates[domainMessageTemplate.ID]; !ok {
+		ctxLogger.Warnf("memory service - unable to delete message template with id %v as it does not exist", domainMessageTemplate.ID)
+		return storage.NewStorageRecordNotFoundError()
+	}
+
+	delete(s.messageTemplates, domainMessageTemplate.ID)
+
+	return nil
+}
+
+// isMessageTemplateTaken mirrors the unique index over the kind, channel, category and language
+func (s *service) isMessageTemplateTaken(base domain.BaseMessageTemplate, excludeID int64) bool {
+	for id, messageTemplate := range s.messageTemplates {
+		if id != excludeID &&
+			messageTemplate.Kind == string(base.Kind) &&
+			messageTemplate.Channel == string(base.Channel) &&
+			messageTemplate.CategoryID == base.CategoryID &&
+			equalFold(messageTemplate.Language, base.Language) {
+			return true
+		}
+	}
+
+	return false
+}
+
+type messageTemplatesByID []messageTemplate
+
+func (b messageTemplatesByID) Len() int           { return len(b) }
+func (b messageTemplatesByID) Swap(i, j int)      { b[i], b[j] = b[j], b[i] }
+func (b messageTemplatesByID) Less(i, j int) bool { return b[i].ID < b[j].ID }
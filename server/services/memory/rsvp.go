@@ -0,0 +1,359 @@
+package memory
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rawfish-dev/rsvp-starter/server/domain"
+	"github.com/rawfish-dev/rsvp-starter/server/interfaces"
+	"github.com/rawfish-dev/rsvp-starter/server/services/storage"
+)
+
+type rsvp struct {
+	baseModel
+	InvitationPrivateID string
+	FullName            string
+	Attending           bool
+	GuestCount          int
+	SpecialDiet         bool
+	Remarks             string
+	MobilePhoneNumber   string
+	Email               string
+	Version             int64
+	CheckedInAt         time.Time
+}
+
+func (r rsvp) toDomain() domain.RSVP {
+	return domain.RSVP{
+		BaseRSVP: domain.BaseRSVP{
+			FullName:          r.FullName,
+			Attending:         r.Attending,
+			GuestCount:        r.GuestCount,
+			SpecialDiet:       r.SpecialDiet,
+			Remarks:           r.Remarks,
+			MobilePhoneNumber: r.MobilePhoneNumber,
+			Email:             r.Email,
+		},
+		ID:                  r.ID,
+		InvitationPrivateID: r.InvitationPrivateID,
+		UpdatedAt:           r.UpdatedAt.Format(time.RFC3339),
+		Version:             r.Version,
+		CheckedInAt:         formatOptionalTime(r.CheckedInAt),
+		Completed:           true,
+		DeletedAt:           r.deletedAt(),
+	}
+}
+
+func (s *service) InsertRSVP(req *domain.RSVPCreateRequest) (*domain.RSVP, error) {
+	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
+
+	s.lock()
+	defer s.unlock()
+
+	if s.isRSVPPrivateIDTaken(req.InvitationPrivateID, 0) {
+		ctxLogger.Warnf("memory service - unable to insert rsvp with a duplicate private id %v", req.InvitationPrivateID)
+		return nil, storage.NewStorageRSVPPrivateIDUniqueConstraintError()
+	}
+
+	s.lastRSVPID++
+	timestamp := s.now()
+
+	rsvp := rsvp{
+		baseModel: baseModel{
+			ID:        s.lastRSVPID,
+			CreatedAt: timestamp,
+			UpdatedAt: timestamp,
+		},
+		InvitationPrivateID: req.InvitationPrivateID,
+		FullName:            req.FullName,
+		Attending:           req.Attending,
+		GuestCount:          req.GuestCount,
+		SpecialDiet:         req.SpecialDiet,
+		Remarks:             req.Remarks,
+		MobilePhoneNumber:   req.MobilePhoneNumber,
+		Email:               req.Email,
+		Version:             1,
+	}
+	s.rsvps[rsvp.ID] = rsvp
+
+	newRSVP := rsvp.toDomain()
+
+	return &newRSVP, nil
+}
+
+func (s *service) FindRSVPByID(rsvpID int64) (*domain.RSVP, error) {
+	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
+
+	s.rlock()
+	defer s.runlock()
+
+	rsvp, ok := s.rsvps[rsvpID]
+	if !ok {
+		ctxLogger.Warnf("memory service - unable to find rsvp with id %v", rsvpID)
+		return nil, storage.NewStorageRecordNotFoundError()
+	}
+
+	domainRSVP := rsvp.toDomain()
+
+	return &domainRSVP, nil
+}
+
+func (s *service) FindRSVPByInvitationPrivateID(invitationPrivateID string) (*domain.RSVP, error) {
+	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
+
+	s.rlock()
+	defer s.runlock()
+
+	for _, rsvp := range s.rsvps {
+		if rsvp.InvitationPrivateID == invitationPrivateID {
+			domainRSVP := rsvp.toDomain()
+
+			// Omit the id and version since no operations can be performed against it by guests
+			domainRSVP.ID = 0
+			domainRSVP.Version = 0
+
+			return &domainRSVP, nil
+		}
+	}
+
+	ctxLogger.Warnf("memory service - unable to find rsvp with invitation private id %v", invitationPrivateID)
+	return nil, storage.NewStorageRecordNotFoundError()
+}
+
+func (s *service) ListRSVPs(req *domain.RSVPListRequest) ([]domain.RSVP, int, error) {
+	s.rlock()
+	defer s.runlock()
+
+	rsvps := make([]rsvp, 0, len(s.rsvps))
+	for _, rsvp := range s.rsvps {
+		if req.Attending != nil && rsvp.Attending != *req.Attending {
+			continue
+		}
+		if req.SpecialDiet != nil && rsvp.SpecialDiet != *req.SpecialDiet {
+			continue
+		}
+		if req.UpdatedSince != nil && rsvp.UpdatedAt.Before(*req.UpdatedSince) {
+			continue
+		}
+
+		rsvps = append(rsvps, rsvp)
+	}
+
+	field, descending := req.SortField()
+	sort.Sort(rsvpsBy{rsvps, rsvpComparisons[field], descending})
+
+	start, end := pageBounds(req.ListRequest, len(rsvps))
+
+	domainRSVPs := make([]domain.RSVP, 0, end-start)
+	for idx := start; idx < end; idx++ {
+		domainRSVPs = append(domainRSVPs, rsvps[idx].toDomain())
+	}
+
+	return domainRSVPs, len(rsvps), nil
+}
+
+func (s *service) UpdateRSVP(domainRSVP *domain.RSVP) (*domain.RSVP, error) {
+	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
+
+	s.lock()
+	defer s.unlock()
+
+	rsvp, ok := s.rsvps[domainRSVP.ID]
+	if !ok {
+		ctxLogger.Warnf("memory service - unable to update rsvp with id %v as it does not exist", domainRSVP.ID)
+		return nil, storage.NewStorageRecordNotFoundError()
+	}
+
+	if rsvp.Version != domainRSVP.Version {
+		ctxLogger.Warnf("memory service - unable to update rsvp with id %v as version %v is not the latest", domainRSVP.ID, domainRSVP.Version)
+		return nil, storage.NewStorageVersionConflictError()
+	}
+
+	if s.isRSVPPrivateIDTaken(domainRSVP.InvitationPrivateID, domainRSVP.ID) {
+		ctxLogger.Warnf("memory service - unable to update rsvp with a duplicate private id %v", domainRSVP.InvitationPrivateID)
+		return nil, storage.NewStorageRSVPPrivateIDUniqueConstraintError()
+	}
+
+	rsvp.InvitationPrivateID = domainRSVP.InvitationPrivateID
+	rsvp.FullName = domainRSVP.FullName
+	rsvp.Attending = domainRSVP.Attending
+	rsvp.GuestCount = domainRSVP.GuestCount
+	rsvp.SpecialDiet = domainRSVP.SpecialDiet
+	rsvp.Remarks = domainRSVP.Remarks
+	rsvp.MobilePhoneNumber = domainRSVP.MobilePhoneNumber
+	rsvp.Email = domainRSVP.Email
+	rsvp.UpdatedAt = s.now()
+	rsvp.Version++
+	s.rsvps[rsvp.ID] = rsvp
+
+	domainRSVP.UpdatedAt = rsvp.UpdatedAt.Format(time.RFC3339)
+	domainRSVP.Version = rsvp.Version
+	domainRSVP.Completed = true
+
+	return domainRSVP, nil
+}
+
+func (s *service) DeleteRSVP(domainRSVP *domain.RSVP) error {
+	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
+
+	s.lock()
+	defer s.unlock()
+
+	if _, ok := s.rsvps[domainRSVP.ID]; !ok {
+		ctxLogger.Warnf("memory service - unable to delete rsvp with id %v as it does not exist", domainRSVP.ID)
+		return storage.NewStorageRecordNotFoundError()
+	}
+
+	rsvp := s.rsvps[domainRSVP.ID]
+	rsvp.DeletedAt = s.now()
+	s.deletedRSVPs[rsvp.ID] = rsvp
+	delete(s.rsvps, rsvp.ID)
+
+	return nil
+}
+
+func (s *service) FindDeletedRSVPByID(rsvpID int64) (*domain.RSVP, error) {
+	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
+
+	s.rlock()
+	defer s.runlock()
+
+	rsvp, ok := s.deletedRSVPs[rsvpID]
+	if !ok {
+		ctxLogger.Warnf("memory service - unable to find deleted rsvp with id %v", rsvpID)
+		return nil, storage.NewStorageRecordNotFoundError()
+	}
+
+	domainRSVP := rsvp.toDomain()
+
+	return &domainRSVP, nil
+}
+
+func (s *service) ListDeletedRSVPs() ([]domain.RSVP, error) {
+	s.rlock()
+	defer s.runlock()
+
+	rsvps := make([]rsvp, 0, len(s.deletedRSVPs))
+	for _, rsvp := range s.deletedRSVPs {
+		rsvps = append(rsvps, rsvp)
+	}
+	sort.Sort(rsvpsByDeletedAt(rsvps))
+
+	domainRSVPs := make([]domain.RSVP, len(rsvps))
+	for idx := range rsvps {
+		domainRSVPs[idx] = rsvps[idx].toDomain()
+	}
+
+	return domainRSVPs, nil
+}
+
+func (s *service) RestoreRSVP(domainRSVP *domain.RSVP) (*domain.RSVP, error) {
+	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
+
+	s.lock()
+	defer s.unlock()
+
+	rsvp, ok := s.deletedRSVPs[domainRSVP.ID]
+	if !ok {
+		ctxLogger.Warnf("memory service - unable to restore rsvp with id %v as it is not deleted", domainRSVP.ID)
+		return nil, storage.NewStorageRecordNotFoundError()
+	}
+
+	if s.isRSVPPrivateIDTaken(rsvp.InvitationPrivateID, rsvp.ID) {
+		ctxLogger.Warnf("memory service - unable to restore rsvp with a duplicate private id %v", rsvp.InvitationPrivateID)
+		return nil, storage.NewStorageRSVPPrivateIDUniqueConstraintError()
+	}
+
+	rsvp.DeletedAt = time.Time{}
+	s.rsvps[rsvp.ID] = rsvp
+	delete(s.deletedRSVPs, rsvp.ID)
+
+	restoredRSVP := rsvp.toDomain()
+
+	return &restoredRSVP, nil
+}
+
+func (s *service) CheckInRSVP(domainRSVP *domain.RSVP) (*domain.RSVP, error) {
+	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
+
+	s.lock()
+	defer s.unlock()
+
+	rsvp, ok := s.rsvps[domainRSVP.ID]
+	if !ok {
+		ctxLogger.Warnf("memory service - unable to check in rsvp with id %v as it does not exist", domainRSVP.ID)
+		return nil, storage.NewStorageRecordNotFoundError()
+	}
+
+	rsvp.CheckedInAt = s.now()
+	s.rsvps[rsvp.ID] = rsvp
+
+	checkedInRSVP := rsvp.toDomain()
+
+	return &checkedInRSVP, nil
+}
+
+func (s *service) PurgeRSVPs(deletedBefore time.Time) (int, error) {
+	s.lock()
+	defer s.unlock()
+
+	purged := 0
+	for id, rsvp := range s.deletedRSVPs {
+		if rsvp.DeletedAt.Before(deletedBefore) {
+			delete(s.deletedRSVPs, id)
+			purged++
+		}
+	}
+
+	return purged, nil
+}
+
+func (s *service) isRSVPPrivateIDTaken(invitationPrivateID string, excludeID int64) bool {
+	for id, rsvp := range s.rsvps {
+		if id != excludeID && rsvp.InvitationPrivateID == invitationPrivateID {
+			return true
+		}
+	}
+
+	return false
+}
+
+var rsvpComparisons = map[string]func(left, right rsvp) int{
+	domain.SortByRSVPFullName: func(left, right rsvp) int {
+		return strings.Compare(strings.ToLower(left.FullName), strings.ToLower(right.FullName))
+	},
+	domain.SortByRSVPGuestCount: func(left, right rsvp) int {
+		return compareInt64s(int64(left.GuestCount), int64(right.GuestCount))
+	},
+	domain.SortByRSVPUpdatedAt: func(left, right rsvp) int {
+		return compareTimes(left.UpdatedAt, right.UpdatedAt)
+	},
+}
+
+// rsvpsBy sorts by the comparison of a single field, or by id alone when there is none
+type rsvpsBy struct {
+	rsvps      []rsvp
+	compare    func(left, right rsvp) int
+	descending bool
+}
+
+func (b rsvpsBy) Len() int      { return len(b.rsvps) }
+func (b rsvpsBy) Swap(i, j int) { b.rsvps[i], b.rsvps[j] = b.rsvps[j], b.rsvps[i] }
+func (b rsvpsBy) Less(i, j int) bool {
+	result := 0
+	if b.compare != nil {
+		result = b.compare(b.rsvps[i], b.rsvps[j])
+	}
+
+	return less(result, b.rsvps[i].ID, b.rsvps[j].ID, b.descending)
+}
+
+// rsvpsByDeletedAt sorts the trash from the most recently deleted
+type rsvpsByDeletedAt []rsvp
+
+func (b rsvpsByDeletedAt) Len() int      { return len(b) }
+func (b rsvpsByDeletedAt) Swap(i, j int) { b[i], b[j] = b[j], b[i] }
+func (b rsvpsByDeletedAt) Less(i, j int) bool {
+	return less(compareTimes(b[i].DeletedAt, b[j].DeletedAt), b[i].ID, b[j].ID, true)
+}
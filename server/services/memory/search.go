@@ -0,0 +1,41 @@
+package memory
+
+import (
+	"github.com/rawfish-dev/rsvp-starter/server/domain"
+	"github.com/rawfish-dev/rsvp-starter/server/services/storage"
+)
+
+func (s *service) Search(req *domain.SearchRequest) ([]domain.SearchResult, error) {
+	s.rlock()
+	defer s.runlock()
+
+	attendance := s.rsvpAttendance()
+
+	var results []domain.SearchResult
+
+	for _, invitation := range s.invitations {
+		invitation.applyReply(attendance)
+
+		domainInvitation := invitation.toDomain()
+		if rank := storage.RankInvitation(req, &domainInvitation); rank > 0 {
+			results = append(results, domain.SearchResult{
+				Type:       domain.InvitationSearchResult,
+				Rank:       rank,
+				Invitation: &domainInvitation,
+			})
+		}
+	}
+
+	for _, rsvp := range s.rsvps {
+		domainRSVP := rsvp.toDomain()
+		if rank := storage.RankRSVP(req, &domainRSVP); rank > 0 {
+			results = append(results, domain.SearchResult{
+				Type: domain.RSVPSearchResult,
+				Rank: rank,
+				RSVP: &domainRSVP,
+			})
+		}
+	}
+
+	return storage.SortSearchResults(results, req.Limit), nil
+}
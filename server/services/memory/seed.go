@@ -0,0 +1,113 @@
+package memory
+
+import (
+	"github.com/rawfish-dev/rsvp-starter/server/domain"
+	"github.com/rawfish-dev/rsvp-starter/server/interfaces"
+)
+
+type demoInvitation struct {
+	categoryTag string
+	request     domain.InvitationCreateRequest
+	status      domain.RSVPStatus
+	rsvp        *domain.BaseRSVP
+}
+
+var demoCategoryTags = []string{"Family", "Friends", "Colleagues"}
+
+var demoInvitations = []demoInvitation{
+	{
+		categoryTag: "Family",
+		request: domain.InvitationCreateRequest{BaseInvitation: domain.BaseInvitation{
+			Greeting: "Uncle Ben and Aunt May", MaximumGuestCount: 2, MobilePhoneNumber: "91234567", Notes: "Seat near the front",
+		}},
+		status: domain.Sent,
+		rsvp: &domain.BaseRSVP{
+			FullName: "Ben Parker", Attending: true, GuestCount: 2, SpecialDiet: true, Remarks: "May is vegetarian", MobilePhoneNumber: "91234567",
+		},
+	},
+	{
+		categoryTag: "Family",
+		request: domain.InvitationCreateRequest{BaseInvitation: domain.BaseInvitation{
+			Greeting: "Grandma Rose", MaximumGuestCount: 1, MobilePhoneNumber: "92345678",
+		}},
+		status: domain.Sent,
+	},
+	{
+		categoryTag: "Friends",
+		request: domain.InvitationCreateRequest{BaseInvitation: domain.BaseInvitation{
+			Greeting: "Mary Jane", MaximumGuestCount: 2, MobilePhoneNumber: "93456789",
+		}},
+		status: domain.Sent,
+		rsvp: &domain.BaseRSVP{
+			FullName: "Mary Jane Watson", Attending: false, GuestCount: 0, Remarks: "Sorry, out of town!", MobilePhoneNumber: "93456789",
+		},
+	},
+	{
+		categoryTag: "Friends",
+		request: domain.InvitationCreateRequest{BaseInvitation: domain.BaseInvitation{
+			Greeting: "Harry and partner", MaximumGuestCount: 2, MobilePhoneNumber: "94567890",
+		}},
+		status: domain.NotSent,
+	},
+	{
+		categoryTag: "Colleagues",
+		request: domain.InvitationCreateRequest{BaseInvitation: domain.BaseInvitation{
+			Greeting: "Mr Jameson", MaximumGuestCount: 1, MobilePhoneNumber: "95678901", Notes: "Editor in chief",
+		}},
+		status: domain.Sent,
+		rsvp: &domain.BaseRSVP{
+			FullName: "J. Jonah Jameson", Attending: true, GuestCount: 1, MobilePhoneNumber: "95678901",
+		},
+	},
+}
+
+// SeedDemoData fills the store with a handful of categories, invitations and rsvps so the
+// dashboard has something to show when running in demo mode.
+func (s *service) SeedDemoData() error {
+	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
+
+	categoryIDs := make(map[string]int64)
+	for _, tag := range demoCategoryTags {
+		newCategory, err := s.InsertCategory(&domain.CategoryCreateRequest{Tag: tag})
+		if err != nil {
+			ctxLogger.Errorf("memory service - unable to seed category %v due to %v", tag, err)
+			return err
+		}
+		categoryIDs[tag] = newCategory.ID
+	}
+
+	for idx := range demoInvitations {
+		demo := demoInvitations[idx]
+		demo.request.CategoryID = categoryIDs[demo.categoryTag]
+
+		newInvitation, err := s.InsertInvitation(&demo.request)
+		if err != nil {
+			ctxLogger.Errorf("memory service - unable to seed invitation %v due to %v", demo.request.Greeting, err)
+			return err
+		}
+
+		newInvitation.Status = demo.status
+		_, err = s.UpdateInvitation(newInvitation)
+		if err != nil {
+			ctxLogger.Errorf("memory service - unable to seed invitation %v due to %v", demo.request.Greeting, err)
+			return err
+		}
+
+		if demo.rsvp == nil {
+			continue
+		}
+
+		_, err = s.InsertRSVP(&domain.RSVPCreateRequest{
+			BaseRSVP:            *demo.rsvp,
+			InvitationPrivateID: newInvitation.PrivateID,
+		})
+		if err != nil {
+			ctxLogger.Errorf("memory service - unable to seed rsvp for %v due to %v", demo.request.Greeting, err)
+			return err
+		}
+	}
+
+	ctxLogger.Infof("memory service - seeded %v categories and %v invitations", len(demoCategoryTags), len(demoInvitations))
+
+	return nil
+}
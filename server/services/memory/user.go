@@ -0,0 +1,169 @@
+package memory
+
+import (
+	"sort"
+	"time"
+
+	"github.com/rawfish-dev/rsvp-starter/server/domain"
+	"github.com/rawfish-dev/rsvp-starter/server/interfaces"
+	"github.com/rawfish-dev/rsvp-starter/server/services/storage"
+)
+
+type user struct {
+	ID                 int64
+	Username           string
+	PasswordHash       string
+	Role               domain.Role
+	Disabled           bool
+	TOTPSecret         string
+	TOTPEnabled        bool
+	TOTPLastStep       int64
+	RecoveryCodeHashes []string
+	CreatedAt          time.Time
+	UpdatedAt          time.Time
+}
+
+func (u user) toDomain() domain.User {
+	return domain.User{
+		ID:                 u.ID,
+		Username:           u.Username,
+		PasswordHash:       u.PasswordHash,
+		Role:               u.Role,
+		Disabled:           u.Disabled,
+		TOTPSecret:         u.TOTPSecret,
+		TOTPEnabled:        u.TOTPEnabled,
+		TOTPLastStep:       u.TOTPLastStep,
+		RecoveryCodeHashes: append([]string(nil), u.RecoveryCodeHashes...),
+		CreatedAt:          u.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:          u.UpdatedAt.Format(time.RFC3339),
+	}
+}
+
+func (s *service) InsertUser(domainUser *domain.User) (*domain.User, error) {
+	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
+
+	s.lock()
+	defer s.unlock()
+
+	if s.isUsernameTaken(domainUser.Username) {
+		ctxLogger.Warn("memory service - unable to insert user with a duplicate username")
+		return nil, storage.NewStorageUserUsernameUniqueConstraintError()
+	}
+
+	s.lastUserID++
+	timestamp := s.now()
+
+	user := user{
+		ID:                 s.lastUserID,
+		Username:           domainUser.Username,
+		PasswordHash:       domainUser.PasswordHash,
+		Role:               domainUser.Role,
+		Disabled:           domainUser.Disabled,
+		TOTPSecret:         domainUser.TOTPSecret,
+		TOTPEnabled:        domainUser.TOTPEnabled,
+		TOTPLastStep:       domainUser.TOTPLastStep,
+		RecoveryCodeHashes: append([]string(nil), domainUser.RecoveryCodeHashes...),
+		CreatedAt:          timestamp,
+		UpdatedAt:          timestamp,
+	}
+	s.users[user.ID] = user
+
+	newUser := user.toDomain()
+
+	return &newUser, nil
+}
+
+func (s *service) FindUserByID(userID int64) (*domain.User, error) {
+	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
+
+	s.rlock()
+	defer s.runlock()
+
+	user, ok := s.users[userID]
+	if !ok {
+		ctxLogger.Warnf("memory service - unable to find user with id %v", userID)
+		return nil, storage.NewStorageRecordNotFoundError()
+	}
+
+	domainUser := user.toDomain()
+
+	return &domainUser, nil
+}
+
+func (s *service) FindUserByUsername(username string) (*domain.User, error) {
+	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
+
+	s.rlock()
+	defer s.runlock()
+
+	for _, user := range s.users {
+		if user.Username == username {
+			domainUser := user.toDomain()
+			return &domainUser, nil
+		}
+	}
+
+	ctxLogger.Warnf("memory service - unable to find user with username %v", username)
+	return nil, storage.NewStorageRecordNotFoundError()
+}
+
+func (s *service) ListUsers() ([]domain.User, error) {
+	s.rlock()
+	defer s.runlock()
+
+	users := make([]user, 0, len(s.users))
+	for _, user := range s.users {
+		users = append(users, user)
+	}
+	sort.Sort(usersByUsername(users))
+
+	domainUsers := make([]domain.User, len(users))
+	for idx := range users {
+		domainUsers[idx] = users[idx].toDomain()
+	}
+
+	return domainUsers, nil
+}
+
+func (s *service) UpdateUser(domainUser *domain.User) (*domain.User, error) {
+	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
+
+	s.lock()
+	defer s.unlock()
+
+	user, ok := s.users[domainUser.ID]
+	if !ok {
+		ctxLogger.Warnf("memory service - unable to update user with id %v as it does not exist", domainUser.ID)
+		return nil, storage.NewStorageRecordNotFoundError()
+	}
+
+	user.PasswordHash = domainUser.PasswordHash
+	user.Role = domainUser.Role
+	user.Disabled = domainUser.Disabled
+	user.TOTPSecret = domainUser.TOTPSecret
+	user.TOTPEnabled = domainUser.TOTPEnabled
+	user.TOTPLastStep = domainUser.TOTPLastStep
+	user.RecoveryCodeHashes = append([]string(nil), domainUser.RecoveryCodeHashes...)
+	user.UpdatedAt = s.now()
+	s.users[user.ID] = user
+
+	updatedUser := user.toDomain()
+
+	return &updatedUser, nil
+}
+
+func (s *service) isUsernameTaken(username string) bool {
+	for _, user := range s.users {
+		if user.Username == username {
+			return true
+		}
+	}
+
+	return false
+}
+
+type usersByUsername []user
+
+func (b usersByUsername) Len() int           { return len(b) }
+func (b usersByUsername) Swap(i, j int)      { b[i], b[j] = b[j], b[i] }
+func (b usersByUsername) Less(i, j int) bool { return b[i].Username < b[j].Username }
@@ -0,0 +1,30 @@
+package messagetemplate
+
+var _ error = new(MessageTemplateNotFoundError)
+var _ error = new(MessageTemplateRenderError)
+
+type MessageTemplateNotFoundError struct {
+}
+
+func NewMessageTemplateNotFoundError() error {
+	return MessageTemplateNotFoundError{}
+}
+
+func (m MessageTemplateNotFoundError) Error() string {
+	return "message template not found"
+}
+
+// MessageTemplateRenderError is returned when a template cannot be filled in, which holds the reason
+// text/template gave
+type MessageTemplateRenderError struct {
+	TemplateID int64
+	Reason     string
+}
+
+func NewMessageTemplateRenderError(templateID int64, reason string) error {
+	return MessageTemplateRenderError{templateID, reason}
+}
+
+func (m MessageTemplateRenderError) Error() string {
+	return "unable to render message template due to " + m.Reason
+}
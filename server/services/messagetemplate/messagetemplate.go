@@ -0,0 +1,261 @@
+package messagetemplate
+
+import (
+	"fmt"
+
+	"github.com/rawfish-dev/rsvp-starter/server/config"
+	"github.com/rawfish-dev/rsvp-starter/server/domain"
+	"github.com/rawfish-dev/rsvp-starter/server/interfaces"
+	"github.com/rawfish-dev/rsvp-starter/server/services/audit"
+	serviceErrors "github.com/rawfish-dev/rsvp-starter/server/services/errors"
+	"github.com/rawfish-dev/rsvp-starter/server/services/storage"
+	"github.com/rawfish-dev/rsvp-starter/server/utils"
+
+	"golang.org/x/net/context"
+)
+
+const (
+	NameMinLength    = 1
+	NameMaxLength    = 100
+	SubjectMaxLength = 200
+	BodyMinLength    = 1
+	BodyMaxLength    = 2000
+)
+
+var _ interfaces.MessageTemplateServiceProvider = new(service)
+
+type service struct {
+	ctx             context.Context
+	messagingConfig config.MessagingConfig
+	templateStorage interfaces.Storage
+}
+
+func NewService(ctx context.Context, messagingConfig config.MessagingConfig, templateStorage interfaces.Storage) *service {
+	return &service{ctx, messagingConfig, templateStorage}
+}
+
+func (s *service) CreateMessageTemplate(req *domain.MessageTemplateCreateRequest) (*domain.MessageTemplate, error) {
+	errorMessages := validateBaseMessageTemplate(req.BaseMessageTemplate)
+	if len(errorMessages) > 0 {
+		return nil, serviceErrors.NewValidationError(errorMessages)
+	}
+
+	var newTemplate *domain.MessageTemplate
+
+	err := s.templateStorage.WithTx(func(tx interfaces.Storage) error {
+		err := validateCategory(tx, req.CategoryID)
+		if err != nil {
+			return err
+		}
+
+		newTemplate, err = tx.InsertMessageTemplate(req)
+		if err != nil {
+			switch err.(type) {
+			case storage.StorageMessageTemplateUniqueConstraintError:
+				return serviceErrors.NewValidationError([]string{err.Error()})
+			}
+
+			return serviceErrors.NewGeneralServiceError()
+		}
+
+		return audit.Record(s.ctx, tx, domain.AuditCreated, domain.TemplateAuditEntity, newTemplate.ID, nil, newTemplate)
+	})
+	if err != nil {
+		return nil, serviceErrors.FromTransaction(err)
+	}
+
+	return newTemplate, nil
+}
+
+func (s *service) ListMessageTemplates() ([]domain.MessageTemplate, error) {
+	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
+
+	templates, err := s.templateStorage.ListMessageTemplates()
+	if err != nil {
+		ctxLogger.Error("message template service - unable to list templates")
+		return nil, serviceErrors.NewGeneralServiceError()
+	}
+
+	return templates, nil
+}
+
+func (s *service) UpdateMessageTemplate(req *domain.MessageTemplateUpdateRequest) (*domain.MessageTemplate, error) {
+	errorMessages := validateMessageTemplateUpdateRequest(req)
+	if len(errorMessages) > 0 {
+		return nil, serviceErrors.NewValidationError(errorMessages)
+	}
+
+	var updatedTemplate *domain.MessageTemplate
+
+	err := s.templateStorage.WithTx(func(tx interfaces.Storage) error {
+		messageTemplate, err := tx.FindMessageTemplateByID(req.ID)
+		if err != nil {
+			switch err.(type) {
+			case storage.StorageRecordNotFoundError:
+				return NewMessageTemplateNotFoundError()
+			}
+
+			return serviceErrors.NewGeneralServiceError()
+		}
+
+		err = validateCategory(tx, req.CategoryID)
+		if err != nil {
+			return err
+		}
+
+		before := *messageTemplate
+		messageTemplate.BaseMessageTemplate = req.BaseMessageTemplate
+
+		updatedTemplate, err = tx.UpdateMessageTemplate(messageTemplate)
+		if err != nil {
+			switch err.(type) {
+			case storage.StorageMessageTemplateUniqueConstraintError:
+				return serviceErrors.NewValidationError([]string{err.Error()})
+			case storage.StorageRecordNotFoundError:
+				return NewMessageTemplateNotFoundError()
+			}
+
+			return serviceErrors.NewGeneralServiceError()
+		}
+
+		return audit.Record(s.ctx, tx, domain.AuditUpdated, domain.TemplateAuditEntity, messageTemplate.ID, before, updatedTemplate)
+	})
+	if err != nil {
+		return nil, serviceErrors.FromTransaction(err)
+	}
+
+	return updatedTemplate, nil
+}
+
+func (s *service) DeleteMessageTemplateByID(templateID int64) error {
+	err := s.templateStorage.WithTx(func(tx interfaces.Storage) error {
+		messageTemplate, err := tx.FindMessageTemplateByID(templateID)
+		if err != nil {
+			switch err.(type) {
+			case storage.StorageRecordNotFoundError:
+				return NewMessageTemplateNotFoundError()
+			}
+
+			return serviceErrors.NewGeneralServiceError()
+		}
+
+		err = tx.DeleteMessageTemplate(messageTemplate)
+		if err != nil {
+			switch err.(type) {
+			case storage.StorageRecordNotFoundError:
+				return NewMessageTemplateNotFoundError()
+			}
+
+			return serviceErrors.NewGeneralServiceError()
+		}
+
+		return audit.Record(s.ctx, tx, domain.AuditDeleted, domain.TemplateAuditEntity, messageTemplate.ID, messageTemplate, nil)
+	})
+	if err != nil {
+		return serviceErrors.FromTransaction(err)
+	}
+
+	return nil
+}
+
+// PreviewMessageTemplate fills in the template with the details of an invitation, whether or not the
+// template would be the one chosen for it
+func (s *service) PreviewMessageTemplate(templateID, invitationID int64) (*domain.RenderedMessage, error) {
+	messageTemplate, err := s.templateStorage.FindMessageTemplateByID(templateID)
+	if err != nil {
+		switch err.(type) {
+		case storage.StorageRecordNotFoundError:
+			return nil, NewMessageTemplateNotFoundError()
+		}
+
+		return nil, serviceErrors.NewGeneralServiceError()
+	}
+
+	invitation, err := s.templateStorage.FindInvitationByID(invitationID)
+	if err != nil {
+		switch err.(type) {
+		case storage.StorageRecordNotFoundError:
+			return nil, serviceErrors.NewValidationError([]string{"invitation does not exist"})
+		}
+
+		return nil, serviceErrors.NewGeneralServiceError()
+	}
+
+	data, err := templateData(s.ctx, s.templateStorage, invitation, RSVPLink(s.messagingConfig.PublicURL, invitation.PrivateID))
+	if err != nil {
+		return nil, err
+	}
+
+	rendered, err := renderTemplate(messageTemplate, data)
+	if err != nil {
+		return nil, serviceErrors.NewValidationError([]string{err.Error()})
+	}
+
+	return rendered, nil
+}
+
+func validateCategory(tx interfaces.Storage, categoryID int64) error {
+	if categoryID == 0 {
+		return nil
+	}
+
+	_, err := tx.FindCategoryByID(categoryID)
+	if err != nil {
+		switch err.(type) {
+		case storage.StorageRecordNotFoundError:
+			return serviceErrors.NewValidationError([]string{"category does not exist"})
+		}
+
+		return serviceErrors.NewGeneralServiceError()
+	}
+
+	return nil
+}
+
+func validateBaseMessageTemplate(baseTemplate domain.BaseMessageTemplate) (errorMessages []string) {
+	if !utils.IsWithin(len(baseTemplate.Name), NameMinLength, NameMaxLength) {
+		errorMessages = append(errorMessages, fmt.Sprintf("template name must be between %v to %v characters", NameMinLength, NameMaxLength))
+	}
+	switch baseTemplate.Kind {
+	case domain.InvitationTemplate, domain.ReminderTemplate:
+	default:
+		errorMessages = append(errorMessages, "template kind must be invitation or reminder")
+	}
+	switch baseTemplate.Channel {
+	case domain.SMSChannel, domain.EmailChannel:
+	default:
+		errorMessages = append(errorMessages, "template channel must be sms or email")
+	}
+	if baseTemplate.CategoryID < 0 {
+		errorMessages = append(errorMessages, "template category id is invalid")
+	}
+	if baseTemplate.Language != "" && !utils.IsValidLanguageTag(baseTemplate.Language) {
+		errorMessages = append(errorMessages, "template language must be a language tag such as en or zh-Hans")
+	}
+	if len(baseTemplate.Subject) > SubjectMaxLength {
+		errorMessages = append(errorMessages, fmt.Sprintf("template subject must be less than %v characters", SubjectMaxLength))
+	}
+	if baseTemplate.Channel == domain.SMSChannel && baseTemplate.Subject != "" {
+		errorMessages = append(errorMessages, "template subject is only used for emails")
+	}
+	if !utils.IsWithin(len(baseTemplate.Body), BodyMinLength, BodyMaxLength) {
+		errorMessages = append(errorMessages, fmt.Sprintf("template body must be between %v to %v characters", BodyMinLength, BodyMaxLength))
+	}
+
+	if _, err := execute(baseTemplate.Subject, sampleData); err != nil {
+		errorMessages = append(errorMessages, fmt.Sprintf("template subject is invalid: %v", err))
+	}
+	if _, err := execute(baseTemplate.Body, sampleData); err != nil {
+		errorMessages = append(errorMessages, fmt.Sprintf("template body is invalid: %v", err))
+	}
+
+	return errorMessages
+}
+
+func validateMessageTemplateUpdateRequest(req *domain.MessageTemplateUpdateRequest) (errorMessages []string) {
+	if req.ID <= 0 {
+		errorMessages = append(errorMessages, "template id is invalid")
+	}
+
+	return append(errorMessages, validateBaseMessageTemplate(req.BaseMessageTemplate)...)
+}
@@ -0,0 +1,13 @@
+package messagetemplate_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestMessageTemplate(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Message Template Suite")
+}
@@ -0,0 +1,333 @@
+package messagetemplate_test
+
+import (
+	"strings"
+
+	"github.com/rawfish-dev/rsvp-starter/server/config"
+	"github.com/rawfish-dev/rsvp-starter/server/domain"
+	"github.com/rawfish-dev/rsvp-starter/server/interfaces"
+	"github.com/rawfish-dev/rsvp-starter/server/mock"
+	serviceErrors "github.com/rawfish-dev/rsvp-starter/server/services/errors"
+	. "github.com/rawfish-dev/rsvp-starter/server/services/messagetemplate"
+	"github.com/rawfish-dev/rsvp-starter/server/services/storage"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"golang.org/x/net/context"
+)
+
+var _ = Describe("Message template", func() {
+
+	var ctrl *gomock.Controller
+	var ctx context.Context
+	var mockTemplateStorage *mock_interfaces.MockTransactionalStorage
+	var testTemplateService interfaces.MessageTemplateServiceProvider
+	var invitation *domain.Invitation
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+
+		ctxlogger := logrus.New()
+		ctx = context.Background()
+		ctx = context.WithValue(ctx, "logger", ctxlogger)
+
+		messagingConfig := config.MessagingConfig{
+			PublicURL: "https://wedding.example.com",
+		}
+
+		mockTemplateStorage = mock_interfaces.NewMockTransactionalStorage(ctrl)
+		testTemplateService = NewService(ctx, messagingConfig, mockTemplateStorage)
+
+		invitation = &domain.Invitation{
+			BaseInvitation: domain.BaseInvitation{
+				CategoryID:        1,
+				Greeting:          "Ah Ma and Ah Gong",
+				MaximumGuestCount: 2,
+				Language:          "zh-Hans",
+			},
+			ID:        1,
+			PrivateID: "some-private-id",
+		}
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	Context("creation", func() {
+
+		var req *domain.MessageTemplateCreateRequest
+
+		BeforeEach(func() {
+			req = &domain.MessageTemplateCreateRequest{
+				BaseMessageTemplate: domain.BaseMessageTemplate{
+					Name:       "family invitation",
+					Kind:       domain.InvitationTemplate,
+					Channel:    domain.EmailChannel,
+					CategoryID: 1,
+					Language:   "zh-Hans",
+					Subject:    "For {{.Greeting}}",
+					Body:       "{{.Greeting}}, reply at {{.RSVPLink}}",
+				},
+			}
+		})
+
+		It("should create a template given valid values", func() {
+			gomock.InOrder(
+				mockTemplateStorage.EXPECT().FindCategoryByID(int64(1)).Return(&domain.Category{ID: 1}, nil),
+				mockTemplateStorage.EXPECT().InsertMessageTemplate(req).Return(&domain.MessageTemplate{ID: 1, BaseMessageTemplate: req.BaseMessageTemplate}, nil),
+				mockTemplateStorage.EXPECT().InsertAuditEntry(gomock.Any()).Do(func(req *domain.AuditEntryCreateRequest) {
+					Expect(req.EntityType).To(Equal(domain.TemplateAuditEntity))
+				}).Return(&domain.AuditEntry{}, nil),
+			)
+
+			newTemplate, err := testTemplateService.CreateMessageTemplate(req)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(newTemplate.ID).To(Equal(int64(1)))
+		})
+
+		It("should not check the category of templates for every category", func() {
+			req.CategoryID = 0
+
+			mockTemplateStorage.EXPECT().FindCategoryByID(gomock.Any()).Times(0)
+			mockTemplateStorage.EXPECT().InsertMessageTemplate(req).Return(&domain.MessageTemplate{ID: 1}, nil)
+			mockTemplateStorage.EXPECT().InsertAuditEntry(gomock.Any()).Return(&domain.AuditEntry{}, nil)
+
+			_, err := testTemplateService.CreateMessageTemplate(req)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should return an error if the category does not exist", func() {
+			mockTemplateStorage.EXPECT().FindCategoryByID(int64(1)).Return(nil, storage.NewStorageRecordNotFoundError())
+			mockTemplateStorage.EXPECT().InsertMessageTemplate(gomock.Any()).Times(0)
+
+			newTemplate, err := testTemplateService.CreateMessageTemplate(req)
+			Expect(err).To(BeAssignableToTypeOf(serviceErrors.ValidationError{}))
+			Expect(err.Error()).To(Equal("category does not exist"))
+			Expect(newTemplate).To(BeNil())
+		})
+
+		It("should not allow two templates for the same kind, channel, category and language", func() {
+			mockTemplateStorage.EXPECT().FindCategoryByID(int64(1)).Return(&domain.Category{ID: 1}, nil)
+			mockTemplateStorage.EXPECT().InsertMessageTemplate(req).Return(nil, storage.NewStorageMessageTemplateUniqueConstraintError())
+
+			newTemplate, err := testTemplateService.CreateMessageTemplate(req)
+			Expect(err).To(BeAssignableToTypeOf(serviceErrors.ValidationError{}))
+			Expect(err.Error()).To(ContainSubstring("a template already exists"))
+			Expect(newTemplate).To(BeNil())
+		})
+
+		It("should return an error if the body refers to a variable that does not exist", func() {
+			req.Body = "{{.Greting}}, reply at {{.RSVPLink}}"
+
+			mockTemplateStorage.EXPECT().InsertMessageTemplate(gomock.Any()).Times(0)
+
+			newTemplate, err := testTemplateService.CreateMessageTemplate(req)
+			Expect(err).To(BeAssignableToTypeOf(serviceErrors.ValidationError{}))
+			Expect(err.Error()).To(ContainSubstring("template body is invalid"))
+			Expect(newTemplate).To(BeNil())
+		})
+
+		It("should return an error if the body cannot be parsed", func() {
+			req.Body = "{{.Greeting"
+
+			mockTemplateStorage.EXPECT().InsertMessageTemplate(gomock.Any()).Times(0)
+
+			_, err := testTemplateService.CreateMessageTemplate(req)
+			Expect(err).To(BeAssignableToTypeOf(serviceErrors.ValidationError{}))
+			Expect(err.Error()).To(ContainSubstring("template body is invalid"))
+		})
+
+		It("should return an error for unknown kinds, channels and languages", func() {
+			req.Kind = "thank you"
+			req.Channel = "post"
+			req.Language = "not a language"
+
+			mockTemplateStorage.EXPECT().InsertMessageTemplate(gomock.Any()).Times(0)
+
+			_, err := testTemplateService.CreateMessageTemplate(req)
+			Expect(err).To(BeAssignableToTypeOf(serviceErrors.ValidationError{}))
+			Expect(err.Error()).To(ContainSubstring("template kind must be invitation or reminder"))
+			Expect(err.Error()).To(ContainSubstring("template channel must be sms or email"))
+			Expect(err.Error()).To(ContainSubstring("template language must be a language tag"))
+		})
+
+		It("should return an error if a text template has a subject", func() {
+			req.Channel = domain.SMSChannel
+
+			mockTemplateStorage.EXPECT().InsertMessageTemplate(gomock.Any()).Times(0)
+
+			_, err := testTemplateService.CreateMessageTemplate(req)
+			Expect(err).To(BeAssignableToTypeOf(serviceErrors.ValidationError{}))
+			Expect(err.Error()).To(ContainSubstring("template subject is only used for emails"))
+		})
+
+		It("should return an error if the body is too long", func() {
+			req.Body = strings.Repeat("a", BodyMaxLength+1)
+
+			mockTemplateStorage.EXPECT().InsertMessageTemplate(gomock.Any()).Times(0)
+
+			_, err := testTemplateService.CreateMessageTemplate(req)
+			Expect(err).To(BeAssignableToTypeOf(serviceErrors.ValidationError{}))
+			Expect(err.Error()).To(ContainSubstring("template body must be between"))
+		})
+	})
+
+	Context("update", func() {
+
+		var req *domain.MessageTemplateUpdateRequest
+
+		BeforeEach(func() {
+			req = &domain.MessageTemplateUpdateRequest{
+				ID: 1,
+				BaseMessageTemplate: domain.BaseMessageTemplate{
+					Name:    "reminder",
+					Kind:    domain.ReminderTemplate,
+					Channel: domain.SMSChannel,
+					Body:    "{{.Greeting}}, we have not heard from you yet",
+				},
+			}
+		})
+
+		It("should update the template given valid values", func() {
+			existing := &domain.MessageTemplate{ID: 1}
+
+			gomock.InOrder(
+				mockTemplateStorage.EXPECT().FindMessageTemplateByID(int64(1)).Return(existing, nil),
+				mockTemplateStorage.EXPECT().UpdateMessageTemplate(gomock.Any()).Do(func(messageTemplate *domain.MessageTemplate) {
+					Expect(messageTemplate.Body).To(Equal("{{.Greeting}}, we have not heard from you yet"))
+				}).Return(&domain.MessageTemplate{ID: 1, BaseMessageTemplate: req.BaseMessageTemplate}, nil),
+				mockTemplateStorage.EXPECT().InsertAuditEntry(gomock.Any()).Return(&domain.AuditEntry{}, nil),
+			)
+
+			updatedTemplate, err := testTemplateService.UpdateMessageTemplate(req)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(updatedTemplate.Kind).To(Equal(domain.ReminderTemplate))
+		})
+
+		It("should return a not found error for unknown templates", func() {
+			mockTemplateStorage.EXPECT().FindMessageTemplateByID(int64(1)).Return(nil, storage.NewStorageRecordNotFoundError())
+
+			updatedTemplate, err := testTemplateService.UpdateMessageTemplate(req)
+			Expect(err).To(BeAssignableToTypeOf(MessageTemplateNotFoundError{}))
+			Expect(updatedTemplate).To(BeNil())
+		})
+	})
+
+	Context("deletion", func() {
+
+		It("should delete the template", func() {
+			existing := &domain.MessageTemplate{ID: 1}
+
+			gomock.InOrder(
+				mockTemplateStorage.EXPECT().FindMessageTemplateByID(int64(1)).Return(existing, nil),
+				mockTemplateStorage.EXPECT().DeleteMessageTemplate(existing).Return(nil),
+				mockTemplateStorage.EXPECT().InsertAuditEntry(gomock.Any()).Do(func(req *domain.AuditEntryCreateRequest) {
+					Expect(req.Action).To(Equal(domain.AuditDeleted))
+				}).Return(&domain.AuditEntry{}, nil),
+			)
+
+			err := testTemplateService.DeleteMessageTemplateByID(1)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should return a not found error for unknown templates", func() {
+			mockTemplateStorage.EXPECT().FindMessageTemplateByID(int64(1)).Return(nil, storage.NewStorageRecordNotFoundError())
+
+			err := testTemplateService.DeleteMessageTemplateByID(1)
+			Expect(err).To(BeAssignableToTypeOf(MessageTemplateNotFoundError{}))
+		})
+	})
+
+	Context("preview", func() {
+
+		It("should fill in the template with the details of the invitation", func() {
+			mockTemplateStorage.EXPECT().FindMessageTemplateByID(int64(1)).Return(&domain.MessageTemplate{
+				ID: 1,
+				BaseMessageTemplate: domain.BaseMessageTemplate{
+					Kind:    domain.InvitationTemplate,
+					Channel: domain.EmailChannel,
+					Subject: "For {{.Greeting}} ({{.CategoryTag}})",
+					Body:    "{{.Greeting}} & {{.MaximumGuestCount}} guests\n\nReply at {{.RSVPLink}}",
+				},
+			}, nil)
+			mockTemplateStorage.EXPECT().FindInvitationByID(int64(1)).Return(invitation, nil)
+			mockTemplateStorage.EXPECT().FindCategoryByID(int64(1)).Return(&domain.Category{ID: 1, Tag: "family"}, nil)
+
+			rendered, err := testTemplateService.PreviewMessageTemplate(1, 1)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(rendered.TemplateID).To(Equal(int64(1)))
+			Expect(rendered.Subject).To(Equal("For Ah Ma and Ah Gong (family)"))
+			Expect(rendered.Body).To(Equal("Ah Ma and Ah Gong & 2 guests\n\nReply at https://wedding.example.com/rsvp/some-private-id"))
+			Expect(rendered.HTMLBody).To(Equal("<p>Ah Ma and Ah Gong &amp; 2 guests</p>\n" +
+				`<p>Reply at <a href="https://wedding.example.com/rsvp/some-private-id">https://wedding.example.com/rsvp/some-private-id</a></p>` + "\n"))
+		})
+
+		It("should return an error if the invitation does not exist", func() {
+			mockTemplateStorage.EXPECT().FindMessageTemplateByID(int64(1)).Return(&domain.MessageTemplate{ID: 1}, nil)
+			mockTemplateStorage.EXPECT().FindInvitationByID(int64(2)).Return(nil, storage.NewStorageRecordNotFoundError())
+
+			rendered, err := testTemplateService.PreviewMessageTemplate(1, 2)
+			Expect(err).To(BeAssignableToTypeOf(serviceErrors.ValidationError{}))
+			Expect(err.Error()).To(Equal("invitation does not exist"))
+			Expect(rendered).To(BeNil())
+		})
+
+		It("should return a not found error for unknown templates", func() {
+			mockTemplateStorage.EXPECT().FindMessageTemplateByID(int64(1)).Return(nil, storage.NewStorageRecordNotFoundError())
+
+			rendered, err := testTemplateService.PreviewMessageTemplate(1, 1)
+			Expect(err).To(BeAssignableToTypeOf(MessageTemplateNotFoundError{}))
+			Expect(rendered).To(BeNil())
+		})
+	})
+
+	Context("selection", func() {
+
+		template := func(id int64, channel domain.MessageChannel, categoryID int64, language string) domain.MessageTemplate {
+			return domain.MessageTemplate{
+				ID: id,
+				BaseMessageTemplate: domain.BaseMessageTemplate{
+					Kind:       domain.InvitationTemplate,
+					Channel:    channel,
+					CategoryID: categoryID,
+					Language:   language,
+				},
+			}
+		}
+
+		It("should prefer templates for the category over those for the language", func() {
+			templates := []domain.MessageTemplate{
+				template(1, domain.SMSChannel, 0, ""),
+				template(2, domain.SMSChannel, 0, "zh-hans"),
+				template(3, domain.SMSChannel, 1, ""),
+				template(4, domain.EmailChannel, 1, "zh-Hans"),
+			}
+
+			Expect(Select(templates, domain.InvitationTemplate, domain.SMSChannel, invitation).ID).To(Equal(int64(3)))
+			Expect(Select(templates[:2], domain.InvitationTemplate, domain.SMSChannel, invitation).ID).To(Equal(int64(2)))
+			Expect(Select(templates, domain.InvitationTemplate, domain.EmailChannel, invitation).ID).To(Equal(int64(4)))
+		})
+
+		It("should leave out templates for other categories, languages and kinds", func() {
+			templates := []domain.MessageTemplate{
+				template(1, domain.SMSChannel, 2, ""),
+				template(2, domain.SMSChannel, 0, "en"),
+			}
+
+			Expect(Select(templates, domain.InvitationTemplate, domain.SMSChannel, invitation)).To(BeNil())
+			Expect(Select(templates, domain.ReminderTemplate, domain.SMSChannel, invitation)).To(BeNil())
+		})
+
+		It("should render nothing when no template fits so the default wording is used", func() {
+			mockTemplateStorage.EXPECT().ListMessageTemplates().Return([]domain.MessageTemplate{}, nil)
+			mockTemplateStorage.EXPECT().FindCategoryByID(gomock.Any()).Times(0)
+
+			rendered, err := Render(ctx, mockTemplateStorage, domain.InvitationTemplate, domain.SMSChannel, invitation, "https://wedding.example.com/rsvp/some-private-id")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(rendered).To(BeNil())
+		})
+	})
+})
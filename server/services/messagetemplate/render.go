@@ -0,0 +1,183 @@
+package messagetemplate
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"strings"
+	"text/template"
+
+	"github.com/rawfish-dev/rsvp-starter/server/domain"
+	"github.com/rawfish-dev/rsvp-starter/server/interfaces"
+	serviceErrors "github.com/rawfish-dev/rsvp-starter/server/services/errors"
+	"github.com/rawfish-dev/rsvp-starter/server/services/storage"
+
+	"golang.org/x/net/context"
+)
+
+// sampleData is what templates are checked against before they are saved, so mistakes such as
+// misspelled fields are caught then rather than when sending
+var sampleData = domain.MessageTemplateData{
+	Greeting:          "Uncle Tan",
+	MaximumGuestCount: 2,
+	RSVPLink:          "https://example.com/rsvp/some-private-id",
+	CategoryTag:       "family",
+}
+
+// RSVPLink is where guests reply to their invitation
+func RSVPLink(publicURL, privateID string) string {
+	return fmt.Sprintf("%v/rsvp/%v", publicURL, privateID)
+}
+
+// Render fills in the template that best fits the invitation. It returns nil when no template applies
+// so the caller can fall back to its own wording.
+func Render(ctx context.Context, templateStorage interfaces.Storage, kind domain.MessageTemplateKind, channel domain.MessageChannel, invitation *domain.Invitation, rsvpLink string) (*domain.RenderedMessage, error) {
+	ctxLogger := ctx.Value("logger").(interfaces.Logger)
+
+	templates, err := templateStorage.ListMessageTemplates()
+	if err != nil {
+		ctxLogger.Errorf("message template service - unable to list templates for invitation %v", invitation.ID)
+		return nil, serviceErrors.NewGeneralServiceError()
+	}
+
+	messageTemplate := Select(templates, kind, channel, invitation)
+	if messageTemplate == nil {
+		return nil, nil
+	}
+
+	data, err := templateData(ctx, templateStorage, invitation, rsvpLink)
+	if err != nil {
+		return nil, err
+	}
+
+	return renderTemplate(messageTemplate, data)
+}
+
+// Select picks the template for the invitation out of those of the given kind and channel. Templates
+// made for the category of the invitation come before those for every category, and then templates in
+// the language of the invitation before those for every language.
+func Select(templates []domain.MessageTemplate, kind domain.MessageTemplateKind, channel domain.MessageChannel, invitation *domain.Invitation) *domain.MessageTemplate {
+	var selected *domain.MessageTemplate
+	selectedScore := -1
+
+	for idx := range templates {
+		messageTemplate := &templates[idx]
+		if messageTemplate.Kind != kind || messageTemplate.Channel != channel {
+			continue
+		}
+
+		score := 0
+
+		switch messageTemplate.CategoryID {
+		case 0:
+		case invitation.CategoryID:
+			score += 2
+		default:
+			continue
+		}
+
+		switch {
+		case messageTemplate.Language == "":
+		case strings.EqualFold(messageTemplate.Language, invitation.Language):
+			score++
+		default:
+			continue
+		}
+
+		if score > selectedScore {
+			selected = messageTemplate
+			selectedScore = score
+		}
+	}
+
+	return selected
+}
+
+func templateData(ctx context.Context, templateStorage interfaces.Storage, invitation *domain.Invitation, rsvpLink string) (domain.MessageTemplateData, error) {
+	ctxLogger := ctx.Value("logger").(interfaces.Logger)
+
+	data := domain.MessageTemplateData{
+		Greeting:          invitation.Greeting,
+		MaximumGuestCount: invitation.MaximumGuestCount,
+		RSVPLink:          rsvpLink,
+	}
+
+	category, err := templateStorage.FindCategoryByID(invitation.CategoryID)
+	if err != nil {
+		switch err.(type) {
+		case storage.StorageRecordNotFoundError:
+			return data, nil
+		}
+
+		ctxLogger.Errorf("message template service - unable to find category %v of invitation %v", invitation.CategoryID, invitation.ID)
+		return data, serviceErrors.NewGeneralServiceError()
+	}
+	data.CategoryTag = category.Tag
+
+	return data, nil
+}
+
+func renderTemplate(messageTemplate *domain.MessageTemplate, data domain.MessageTemplateData) (*domain.RenderedMessage, error) {
+	rendered := &domain.RenderedMessage{
+		TemplateID: messageTemplate.ID,
+	}
+
+	var err error
+
+	if messageTemplate.Channel == domain.EmailChannel {
+		rendered.Subject, err = execute(messageTemplate.Subject, data)
+		if err != nil {
+			return nil, NewMessageTemplateRenderError(messageTemplate.ID, err.Error())
+		}
+	}
+
+	rendered.Body, err = execute(messageTemplate.Body, data)
+	if err != nil {
+		return nil, NewMessageTemplateRenderError(messageTemplate.ID, err.Error())
+	}
+
+	if messageTemplate.Channel == domain.EmailChannel {
+		rendered.HTMLBody = textToHTML(rendered.Body, data.RSVPLink)
+	}
+
+	return rendered, nil
+}
+
+func execute(text string, data domain.MessageTemplateData) (string, error) {
+	parsed, err := template.New("message").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", err
+	}
+
+	var out bytes.Buffer
+	err = parsed.Execute(&out, data)
+	if err != nil {
+		return "", err
+	}
+
+	return out.String(), nil
+}
+
+// textToHTML turns each paragraph of a plain text email into an HTML paragraph and makes the RSVP
+// link clickable, so a single template covers both parts of the email
+func textToHTML(text, rsvpLink string) string {
+	var htmlBody bytes.Buffer
+
+	text = strings.Replace(text, "\r\n", "\n", -1)
+	for _, paragraph := range strings.Split(strings.TrimSpace(text), "\n\n") {
+		paragraph = strings.TrimSpace(paragraph)
+		if paragraph == "" {
+			continue
+		}
+
+		escaped := strings.Replace(html.EscapeString(paragraph), "\n", "<br>\n", -1)
+		if rsvpLink != "" {
+			escapedLink := html.EscapeString(rsvpLink)
+			escaped = strings.Replace(escaped, escapedLink, fmt.Sprintf(`<a href="%v">%v</a>`, escapedLink, escapedLink), -1)
+		}
+
+		fmt.Fprintf(&htmlBody, "<p>%v</p>\n", escaped)
+	}
+
+	return htmlBody.String()
+}
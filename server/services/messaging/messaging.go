@@ -0,0 +1,49 @@
+package messaging
+
+import (
+	"os"
+
+	"github.com/rawfish-dev/rsvp-starter/server/config"
+	"github.com/rawfish-dev/rsvp-starter/server/interfaces"
+
+	"golang.org/x/net/context"
+)
+
+// NewProvider returns the provider selected by the messaging config
+func NewProvider(ctx context.Context, messagingConfig config.MessagingConfig) interfaces.MessagingProvider {
+	switch messagingConfig.Provider {
+	case config.TwilioMessagingProvider:
+		return NewTwilio(ctx, messagingConfig)
+	case config.WebhookMessagingProvider:
+		return NewWebhook(ctx, messagingConfig)
+	case config.FileMessagingProvider:
+		return NewFile(ctx, messagingConfig.FilePath)
+	}
+
+	return NewWriter(ctx, config.StdoutMessagingProvider, os.Stdout)
+}
+
+// NewEmailSender returns the email sender selected by the email config
+func NewEmailSender(ctx context.Context, emailConfig config.EmailConfig) interfaces.EmailSender {
+	switch emailConfig.Provider {
+	case config.SMTPEmailProvider:
+		return NewSMTP(ctx, emailConfig)
+	case config.FileEmailProvider:
+		return NewFile(ctx, emailConfig.FilePath)
+	}
+
+	return NewWriter(ctx, config.StdoutEmailProvider, os.Stdout)
+}
+
+// ProviderError is returned when a provider could not be reached or did not accept a message
+type ProviderError struct {
+	Reason string
+}
+
+func NewProviderError(reason string) error {
+	return ProviderError{reason}
+}
+
+func (p ProviderError) Error() string {
+	return p.Reason
+}
@@ -0,0 +1,13 @@
+package messaging_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestMessaging(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Messaging Suite")
+}
@@ -0,0 +1,184 @@
+package messaging_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/rawfish-dev/rsvp-starter/server/config"
+	. "github.com/rawfish-dev/rsvp-starter/server/services/messaging"
+
+	"github.com/Sirupsen/logrus"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"golang.org/x/net/context"
+)
+
+var _ = Describe("Messaging", func() {
+
+	var ctx context.Context
+	var fakeServer *httptest.Server
+	var receivedRequest *http.Request
+	var receivedBody []byte
+	var responseStatus int
+	var responseBody string
+	var messagingConfig config.MessagingConfig
+
+	BeforeEach(func() {
+		ctxlogger := logrus.New()
+		ctx = context.WithValue(context.Background(), "logger", ctxlogger)
+
+		receivedRequest = nil
+		receivedBody = nil
+		responseStatus = http.StatusCreated
+		responseBody = `{"sid": "SM123", "status": "queued"}`
+
+		fakeServer = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			receivedRequest = r
+			receivedBody, _ = ioutil.ReadAll(r.Body)
+
+			w.WriteHeader(responseStatus)
+			fmt.Fprint(w, responseBody)
+		}))
+
+		messagingConfig = config.MessagingConfig{
+			Provider:   config.TwilioMessagingProvider,
+			From:       "+6580000000",
+			AccountSID: "AC123",
+			AuthToken:  "some-token",
+			APIURL:     fakeServer.URL,
+			WebhookURL: fakeServer.URL + "/messages",
+			Timeout:    time.Second,
+		}
+	})
+
+	AfterEach(func() {
+		fakeServer.Close()
+	})
+
+	Context("twilio", func() {
+
+		It("should post the message to the account with basic auth and return its sid", func() {
+			providerMessageID, err := NewTwilio(ctx, messagingConfig).SendSMS("+6591234567", "You are invited")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(providerMessageID).To(Equal("SM123"))
+
+			Expect(receivedRequest.URL.Path).To(Equal("/2010-04-01/Accounts/AC123/Messages.json"))
+
+			username, password, ok := receivedRequest.BasicAuth()
+			Expect(ok).To(BeTrue())
+			Expect(username).To(Equal("AC123"))
+			Expect(password).To(Equal("some-token"))
+
+			form, err := url.ParseQuery(string(receivedBody))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(form.Get("To")).To(Equal("+6591234567"))
+			Expect(form.Get("From")).To(Equal("+6580000000"))
+			Expect(form.Get("Body")).To(Equal("You are invited"))
+		})
+
+		It("should return the reason twilio gives for rejecting a message", func() {
+			responseStatus = http.StatusBadRequest
+			responseBody = `{"code": 21211, "message": "The 'To' number is not a valid phone number."}`
+
+			_, err := NewTwilio(ctx, messagingConfig).SendSMS("+65123", "You are invited")
+			Expect(err).To(BeAssignableToTypeOf(ProviderError{}))
+			Expect(err.Error()).To(ContainSubstring("21211"))
+			Expect(err.Error()).To(ContainSubstring("not a valid phone number"))
+		})
+
+		It("should return an error when twilio cannot be reached", func() {
+			fakeServer.Close()
+
+			_, err := NewTwilio(ctx, messagingConfig).SendSMS("+6591234567", "You are invited")
+			Expect(err).To(BeAssignableToTypeOf(ProviderError{}))
+		})
+	})
+
+	Context("webhook", func() {
+
+		It("should post the message as json and return the id in the response", func() {
+			responseStatus = http.StatusOK
+			responseBody = `{"id": "some-id"}`
+
+			providerMessageID, err := NewWebhook(ctx, messagingConfig).SendSMS("+6591234567", "You are invited")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(providerMessageID).To(Equal("some-id"))
+
+			Expect(receivedRequest.URL.Path).To(Equal("/messages"))
+			Expect(receivedRequest.Header.Get("Content-Type")).To(Equal("application/json"))
+
+			var message map[string]string
+			Expect(json.Unmarshal(receivedBody, &message)).To(Succeed())
+			Expect(message).To(Equal(map[string]string{
+				"channel": "sms",
+				"to":      "+6591234567",
+				"from":    "+6580000000",
+				"body":    "You are invited",
+			}))
+		})
+
+		It("should accept messages when the response has no id", func() {
+			responseStatus = http.StatusAccepted
+			responseBody = ``
+
+			providerMessageID, err := NewWebhook(ctx, messagingConfig).SendSMS("+6591234567", "You are invited")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(providerMessageID).To(BeEmpty())
+		})
+
+		It("should return an error when the webhook responds with an error status", func() {
+			responseStatus = http.StatusServiceUnavailable
+
+			_, err := NewWebhook(ctx, messagingConfig).SendSMS("+6591234567", "You are invited")
+			Expect(err).To(BeAssignableToTypeOf(ProviderError{}))
+			Expect(err.Error()).To(ContainSubstring("503"))
+		})
+	})
+
+	Context("writers", func() {
+
+		It("should write each message as a line of json", func() {
+			var out bytes.Buffer
+			writer := NewWriter(ctx, config.StdoutMessagingProvider, &out)
+
+			providerMessageID, err := writer.SendSMS("+6591234567", "You are invited")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(providerMessageID).ToNot(BeEmpty())
+			Expect(writer.Name()).To(Equal(config.StdoutMessagingProvider))
+
+			var message map[string]string
+			Expect(json.Unmarshal(out.Bytes(), &message)).To(Succeed())
+			Expect(message["id"]).To(Equal(providerMessageID))
+			Expect(message["to"]).To(Equal("+6591234567"))
+			Expect(message["body"]).To(Equal("You are invited"))
+			Expect(out.String()).To(HaveSuffix("\n"))
+		})
+
+		It("should append messages to the file", func() {
+			dir, err := ioutil.TempDir("", "messaging")
+			Expect(err).ToNot(HaveOccurred())
+			defer os.RemoveAll(dir)
+
+			path := filepath.Join(dir, "messages.jsonl")
+			file := NewFile(ctx, path)
+
+			_, err = file.SendSMS("+6591234567", "You are invited")
+			Expect(err).ToNot(HaveOccurred())
+			_, err = file.SendSMS("+6598765432", "You are invited too")
+			Expect(err).ToNot(HaveOccurred())
+
+			written, err := ioutil.ReadFile(path)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(bytes.Count(written, []byte("\n"))).To(Equal(2))
+			Expect(string(written)).To(ContainSubstring("You are invited too"))
+		})
+	})
+})
@@ -0,0 +1,64 @@
+package messaging
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"time"
+)
+
+// statusUpdateTolerance is how far the timestamp of a status report may be from now, so a captured report
+// cannot be replayed later
+const statusUpdateTolerance = time.Minute * 5
+
+// SignStatusUpdate signs the body of a status report along with the unix timestamp it was sent at
+func SignStatusUpdate(secret string, timestamp int64, body []byte) string {
+	return hex.EncodeToString(statusUpdateMAC(secret, timestamp, body))
+}
+
+// VerifyStatusUpdate checks the signature a provider sent with a status report. Every report is rejected
+// when no secret is configured.
+func VerifyStatusUpdate(secret, timestampHeader, signatureHeader string, body []byte, now time.Time) error {
+	if secret == "" {
+		return NewSignatureError("status webhook is not configured")
+	}
+
+	timestamp, err := strconv.ParseInt(timestampHeader, 10, 64)
+	if err != nil {
+		return NewSignatureError("timestamp is invalid")
+	}
+
+	sentAt := time.Unix(timestamp, 0)
+	if sentAt.Before(now.Add(-statusUpdateTolerance)) || sentAt.After(now.Add(statusUpdateTolerance)) {
+		return NewSignatureError("timestamp is too far from now")
+	}
+
+	signature, err := hex.DecodeString(signatureHeader)
+	if err != nil || !hmac.Equal(signature, statusUpdateMAC(secret, timestamp, body)) {
+		return NewSignatureError("signature does not match")
+	}
+
+	return nil
+}
+
+func statusUpdateMAC(secret string, timestamp int64, body []byte) []byte {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10) + "."))
+	mac.Write(body)
+
+	return mac.Sum(nil)
+}
+
+// SignatureError is returned for status reports that were not signed with the status webhook secret
+type SignatureError struct {
+	Reason string
+}
+
+func NewSignatureError(reason string) error {
+	return SignatureError{reason}
+}
+
+func (s SignatureError) Error() string {
+	return s.Reason
+}
@@ -0,0 +1,47 @@
+package messaging_test
+
+import (
+	"strconv"
+	"time"
+
+	. "github.com/rawfish-dev/rsvp-starter/server/services/messaging"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Status update signatures", func() {
+
+	body := []byte(`{"provider":"twilio","providerMessageID":"SM123","status":"delivered"}`)
+	now := time.Date(2026, 10, 17, 10, 0, 0, 0, time.UTC)
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+
+	It("should accept a report signed with the secret", func() {
+		signature := SignStatusUpdate("secret", now.Unix(), body)
+
+		Expect(VerifyStatusUpdate("secret", timestamp, signature, body, now.Add(time.Minute))).To(Succeed())
+	})
+
+	It("should reject a report signed with another secret or whose body was changed", func() {
+		signature := SignStatusUpdate("other", now.Unix(), body)
+		Expect(VerifyStatusUpdate("secret", timestamp, signature, body, now)).To(BeAssignableToTypeOf(SignatureError{}))
+
+		signature = SignStatusUpdate("secret", now.Unix(), body)
+		Expect(VerifyStatusUpdate("secret", timestamp, signature, []byte(`{"status":"failed"}`), now)).To(
+			BeAssignableToTypeOf(SignatureError{}))
+	})
+
+	It("should reject a report sent too long ago or without a valid timestamp", func() {
+		signature := SignStatusUpdate("secret", now.Unix(), body)
+
+		Expect(VerifyStatusUpdate("secret", timestamp, signature, body, now.Add(time.Minute*6))).To(
+			MatchError("timestamp is too far from now"))
+		Expect(VerifyStatusUpdate("secret", "yesterday", signature, body, now)).To(MatchError("timestamp is invalid"))
+	})
+
+	It("should reject every report if no secret is configured", func() {
+		signature := SignStatusUpdate("", now.Unix(), body)
+
+		Expect(VerifyStatusUpdate("", timestamp, signature, body, now)).To(MatchError("status webhook is not configured"))
+	})
+})
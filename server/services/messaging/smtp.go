@@ -0,0 +1,183 @@
+package messaging
+
+import (
+	"bytes"
+	"crypto/tls"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rawfish-dev/rsvp-starter/server/config"
+	"github.com/rawfish-dev/rsvp-starter/server/domain"
+	"github.com/rawfish-dev/rsvp-starter/server/interfaces"
+
+	"github.com/satori/go.uuid"
+	"golang.org/x/net/context"
+)
+
+var _ interfaces.EmailSender = new(smtpSender)
+
+// smtpSender sends every email as multipart/alternative with a plain text and an HTML part. The whole
+// conversation with the server has to finish within the timeout.
+type smtpSender struct {
+	ctx         context.Context
+	emailConfig config.EmailConfig
+}
+
+func NewSMTP(ctx context.Context, emailConfig config.EmailConfig) *smtpSender {
+	return &smtpSender{
+		ctx:         ctx,
+		emailConfig: emailConfig,
+	}
+}
+
+func (s *smtpSender) Name() string {
+	return config.SMTPEmailProvider
+}
+
+// SendEmail returns the Message-ID header of the email, which is how bounces refer back to it
+func (s *smtpSender) SendEmail(email *domain.Email) (providerMessageID string, err error) {
+	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
+
+	from, err := mail.ParseAddress(s.emailConfig.From)
+	if err != nil {
+		ctxLogger.Errorf("messaging service - unable to parse email from address %v due to %v", s.emailConfig.From, err)
+		return "", NewProviderError("email from address is invalid")
+	}
+
+	messageID := fmt.Sprintf("<%v@%v>", uuid.NewV4().String(), from.Address[strings.LastIndex(from.Address, "@")+1:])
+
+	message, err := buildEmail(from, messageID, email)
+	if err != nil {
+		ctxLogger.Errorf("messaging service - unable to build email due to %v", err)
+		return "", NewProviderError("unable to build the email")
+	}
+
+	err = s.deliver(from.Address, email.To, message)
+	if err != nil {
+		ctxLogger.Warnf("messaging service - unable to send email through %v due to %v", s.emailConfig.SMTPHost, err)
+		return "", NewProviderError(err.Error())
+	}
+
+	return messageID, nil
+}
+
+func (s *smtpSender) deliver(from, to string, message []byte) error {
+	address := net.JoinHostPort(s.emailConfig.SMTPHost, strconv.Itoa(s.emailConfig.SMTPPort))
+
+	conn, err := net.DialTimeout("tcp", address, s.emailConfig.Timeout)
+	if err != nil {
+		return err
+	}
+	conn.SetDeadline(time.Now().Add(s.emailConfig.Timeout))
+
+	client, err := smtp.NewClient(conn, s.emailConfig.SMTPHost)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		err = client.StartTLS(&tls.Config{ServerName: s.emailConfig.SMTPHost})
+		if err != nil {
+			return err
+		}
+	}
+
+	if s.emailConfig.Username != "" {
+		err = client.Auth(smtp.PlainAuth("", s.emailConfig.Username, s.emailConfig.Password, s.emailConfig.SMTPHost))
+		if err != nil {
+			return err
+		}
+	}
+
+	err = client.Mail(from)
+	if err != nil {
+		return err
+	}
+
+	err = client.Rcpt(to)
+	if err != nil {
+		return err
+	}
+
+	dataWriter, err := client.Data()
+	if err != nil {
+		return err
+	}
+
+	_, err = dataWriter.Write(message)
+	if err != nil {
+		return err
+	}
+
+	err = dataWriter.Close()
+	if err != nil {
+		return err
+	}
+
+	return client.Quit()
+}
+
+// buildEmail puts the plain text part first as mail clients show the last part they can display
+func buildEmail(from *mail.Address, messageID string, email *domain.Email) ([]byte, error) {
+	var body bytes.Buffer
+	bodyWriter := multipart.NewWriter(&body)
+
+	parts := []struct {
+		contentType string
+		content     string
+	}{
+		{"text/plain; charset=utf-8", email.TextBody},
+		{"text/html; charset=utf-8", email.HTMLBody},
+	}
+
+	for _, part := range parts {
+		partHeader := textproto.MIMEHeader{}
+		partHeader.Set("Content-Type", part.contentType)
+		partHeader.Set("Content-Transfer-Encoding", "quoted-printable")
+
+		partWriter, err := bodyWriter.CreatePart(partHeader)
+		if err != nil {
+			return nil, err
+		}
+
+		encoder := quotedprintable.NewWriter(partWriter)
+		_, err = encoder.Write([]byte(part.content))
+		if err != nil {
+			return nil, err
+		}
+
+		err = encoder.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err := bodyWriter.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	var message bytes.Buffer
+	fmt.Fprintf(&message, "From: %v\r\n", from.String())
+	fmt.Fprintf(&message, "To: %v\r\n", email.To)
+	fmt.Fprintf(&message, "Subject: %v\r\n", mime.QEncoding.Encode("utf-8", email.Subject))
+	fmt.Fprintf(&message, "Date: %v\r\n", time.Now().Format(time.RFC1123Z))
+	fmt.Fprintf(&message, "Message-ID: %v\r\n", messageID)
+	fmt.Fprintf(&message, "MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&message, "Content-Type: multipart/alternative; boundary=%v\r\n", bodyWriter.Boundary())
+	fmt.Fprintf(&message, "\r\n")
+	message.Write(body.Bytes())
+
+	return message.Bytes(), nil
+}
@@ -0,0 +1,231 @@
+package messaging_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/mail"
+	"net/textproto"
+	"strings"
+	"time"
+
+	"github.com/rawfish-dev/rsvp-starter/server/config"
+	"github.com/rawfish-dev/rsvp-starter/server/domain"
+	. "github.com/rawfish-dev/rsvp-starter/server/services/messaging"
+
+	"github.com/Sirupsen/logrus"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"golang.org/x/net/context"
+)
+
+// fakeSMTPServer speaks just enough SMTP for a single email, rejecting recipients it is told to
+type fakeSMTPServer struct {
+	listener          net.Listener
+	rejectedRecipient string
+	received          chan fakeSMTPEnvelope
+}
+
+type fakeSMTPEnvelope struct {
+	from string
+	to   string
+	data string
+}
+
+func newFakeSMTPServer() *fakeSMTPServer {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	Expect(err).ToNot(HaveOccurred())
+
+	server := &fakeSMTPServer{
+		listener: listener,
+		received: make(chan fakeSMTPEnvelope, 1),
+	}
+	go server.serve()
+
+	return server
+}
+
+func (f *fakeSMTPServer) port() int {
+	return f.listener.Addr().(*net.TCPAddr).Port
+}
+
+func (f *fakeSMTPServer) serve() {
+	conn, err := f.listener.Accept()
+	if err != nil {
+		return
+	}
+
+	textConn := textproto.NewConn(conn)
+	defer textConn.Close()
+
+	textConn.PrintfLine("220 localhost ESMTP")
+
+	var envelope fakeSMTPEnvelope
+	for {
+		line, err := textConn.ReadLine()
+		if err != nil {
+			return
+		}
+
+		command := strings.ToUpper(strings.SplitN(line, " ", 2)[0])
+		switch command {
+		case "EHLO", "HELO":
+			textConn.PrintfLine("250 localhost")
+		case "MAIL":
+			envelope.from = strings.Trim(strings.TrimPrefix(line, "MAIL FROM:"), "<>")
+			textConn.PrintfLine("250 OK")
+		case "RCPT":
+			envelope.to = strings.Trim(strings.TrimPrefix(line, "RCPT TO:"), "<>")
+			if envelope.to == f.rejectedRecipient {
+				textConn.PrintfLine("550 mailbox unavailable")
+				continue
+			}
+			textConn.PrintfLine("250 OK")
+		case "DATA":
+			textConn.PrintfLine("354 go ahead")
+			data, err := textConn.ReadDotBytes()
+			if err != nil {
+				return
+			}
+			envelope.data = string(data)
+			textConn.PrintfLine("250 OK")
+			f.received <- envelope
+		case "QUIT":
+			textConn.PrintfLine("221 bye")
+			return
+		default:
+			textConn.PrintfLine("250 OK")
+		}
+	}
+}
+
+var _ = Describe("SMTP", func() {
+
+	var ctx context.Context
+	var fakeServer *fakeSMTPServer
+	var emailConfig config.EmailConfig
+	var email *domain.Email
+
+	BeforeEach(func() {
+		ctxlogger := logrus.New()
+		ctx = context.WithValue(context.Background(), "logger", ctxlogger)
+
+		fakeServer = newFakeSMTPServer()
+
+		emailConfig = config.EmailConfig{
+			Provider: config.SMTPEmailProvider,
+			From:     "Wedding RSVPs <rsvp@example.com>",
+			SMTPHost: "127.0.0.1",
+			SMTPPort: fakeServer.port(),
+			Timeout:  5 * time.Second,
+		}
+
+		email = &domain.Email{
+			To:       "guest@example.com",
+			Subject:  "You're invited ❤",
+			TextBody: "Let us know at http://localhost/rsvp/abc",
+			HTMLBody: `<p>Let us know <a href="http://localhost/rsvp/abc">here</a></p>`,
+		}
+	})
+
+	AfterEach(func() {
+		fakeServer.listener.Close()
+	})
+
+	It("should send the email with a plain text and an html part", func() {
+		sender := NewSMTP(ctx, emailConfig)
+		Expect(sender.Name()).To(Equal(config.SMTPEmailProvider))
+
+		providerMessageID, err := sender.SendEmail(email)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(providerMessageID).To(HaveSuffix("@example.com>"))
+
+		var envelope fakeSMTPEnvelope
+		Eventually(fakeServer.received).Should(Receive(&envelope))
+		Expect(envelope.from).To(Equal("rsvp@example.com"))
+		Expect(envelope.to).To(Equal("guest@example.com"))
+
+		message, err := mail.ReadMessage(strings.NewReader(envelope.data))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(message.Header.Get("To")).To(Equal("guest@example.com"))
+		Expect(message.Header.Get("Message-ID")).To(Equal(providerMessageID))
+
+		subject, err := new(mime.WordDecoder).DecodeHeader(message.Header.Get("Subject"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(subject).To(Equal("You're invited ❤"))
+
+		mediaType, params, err := mime.ParseMediaType(message.Header.Get("Content-Type"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(mediaType).To(Equal("multipart/alternative"))
+
+		parts := multipart.NewReader(message.Body, params["boundary"])
+		var contents []string
+		for {
+			part, err := parts.NextPart()
+			if err != nil {
+				break
+			}
+			content, err := ioutil.ReadAll(part)
+			Expect(err).ToNot(HaveOccurred())
+			contents = append(contents, part.Header.Get("Content-Type")+" "+string(content))
+		}
+
+		Expect(contents).To(Equal([]string{
+			"text/plain; charset=utf-8 " + email.TextBody,
+			"text/html; charset=utf-8 " + email.HTMLBody,
+		}))
+	})
+
+	It("should return the reason the server gives for rejecting the recipient", func() {
+		fakeServer.rejectedRecipient = "guest@example.com"
+
+		_, err := NewSMTP(ctx, emailConfig).SendEmail(email)
+		Expect(err).To(BeAssignableToTypeOf(ProviderError{}))
+		Expect(err.Error()).To(ContainSubstring("mailbox unavailable"))
+	})
+
+	It("should return an error when the server cannot be reached", func() {
+		fakeServer.listener.Close()
+		emailConfig.SMTPPort = fakeServer.port()
+
+		_, err := NewSMTP(ctx, emailConfig).SendEmail(email)
+		Expect(err).To(BeAssignableToTypeOf(ProviderError{}))
+	})
+
+	It("should not send from an invalid address", func() {
+		emailConfig.From = "not an address"
+
+		_, err := NewSMTP(ctx, emailConfig).SendEmail(email)
+		Expect(err).To(BeAssignableToTypeOf(ProviderError{}))
+	})
+})
+
+var _ = Describe("Email writers", func() {
+
+	It("should write each email as a line of json", func() {
+		ctx := context.WithValue(context.Background(), "logger", logrus.New())
+
+		var out strings.Builder
+		writer := NewWriter(ctx, config.StdoutEmailProvider, &out)
+
+		providerMessageID, err := writer.SendEmail(&domain.Email{
+			To:       "guest@example.com",
+			Subject:  "You're invited",
+			TextBody: "Let us know",
+			HTMLBody: "<p>Let us know</p>",
+		})
+		Expect(err).ToNot(HaveOccurred())
+
+		var written map[string]string
+		Expect(json.Unmarshal([]byte(out.String()), &written)).To(Succeed())
+		Expect(written["id"]).To(Equal(providerMessageID))
+		Expect(written["channel"]).To(Equal("email"))
+		Expect(written["to"]).To(Equal("guest@example.com"))
+		Expect(written["subject"]).To(Equal("You're invited"))
+		Expect(written["body"]).To(Equal("Let us know"))
+		Expect(written["html"]).To(Equal("<p>Let us know</p>"))
+		Expect(out.String()).To(HaveSuffix("\n"))
+	})
+})
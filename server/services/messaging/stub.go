@@ -0,0 +1,115 @@
+package messaging
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/rawfish-dev/rsvp-starter/server/config"
+	"github.com/rawfish-dev/rsvp-starter/server/domain"
+	"github.com/rawfish-dev/rsvp-starter/server/interfaces"
+
+	"github.com/satori/go.uuid"
+	"golang.org/x/net/context"
+)
+
+var _ interfaces.MessagingProvider = new(writer)
+var _ interfaces.EmailSender = new(writer)
+
+// writer writes messages and emails out as lines of JSON instead of sending them, for development and
+// offline environments
+type writer struct {
+	ctx  context.Context
+	name string
+
+	mutex *sync.Mutex
+	out   io.Writer
+}
+
+// writtenMessage is what each line holds
+type writtenMessage struct {
+	ID      string `json:"id"`
+	Channel string `json:"channel"`
+	To      string `json:"to"`
+	Subject string `json:"subject,omitempty"`
+	Body    string `json:"body"`
+	HTML    string `json:"html,omitempty"`
+	SentAt  string `json:"sentAt"`
+}
+
+func NewWriter(ctx context.Context, name string, out io.Writer) *writer {
+	return &writer{
+		ctx:   ctx,
+		name:  name,
+		mutex: &sync.Mutex{},
+		out:   out,
+	}
+}
+
+// fileWriter appends to the file on every write so it can be moved or truncated between messages
+type fileWriter struct {
+	path string
+}
+
+func (f fileWriter) Write(p []byte) (n int, err error) {
+	file, err := os.OpenFile(f.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	return file.Write(p)
+}
+
+// NewFile names itself after the file provider, which has the same name for messaging and email
+func NewFile(ctx context.Context, path string) *writer {
+	return NewWriter(ctx, config.FileMessagingProvider, fileWriter{path})
+}
+
+func (w *writer) Name() string {
+	return w.name
+}
+
+func (w *writer) SendSMS(to, body string) (providerMessageID string, err error) {
+	return w.write(writtenMessage{
+		Channel: string(domain.SMSChannel),
+		To:      to,
+		Body:    body,
+	})
+}
+
+func (w *writer) SendEmail(email *domain.Email) (providerMessageID string, err error) {
+	return w.write(writtenMessage{
+		Channel: string(domain.EmailChannel),
+		To:      email.To,
+		Subject: email.Subject,
+		Body:    email.TextBody,
+		HTML:    email.HTMLBody,
+	})
+}
+
+func (w *writer) write(message writtenMessage) (providerMessageID string, err error) {
+	ctxLogger := w.ctx.Value("logger").(interfaces.Logger)
+
+	message.ID = uuid.NewV4().String()
+	message.SentAt = time.Now().UTC().Format(time.RFC3339)
+
+	line, err := json.Marshal(message)
+	if err != nil {
+		ctxLogger.Errorf("messaging service - unable to wrap %v message due to %v", message.Channel, err)
+		return "", NewProviderError("unable to write the message")
+	}
+
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	_, err = w.out.Write(append(line, '\n'))
+	if err != nil {
+		ctxLogger.Errorf("messaging service - unable to write %v message to %v due to %v", message.Channel, w.name, err)
+		return "", NewProviderError("unable to write the message")
+	}
+
+	return message.ID, nil
+}
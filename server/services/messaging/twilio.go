@@ -0,0 +1,92 @@
+package messaging
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/rawfish-dev/rsvp-starter/server/config"
+	"github.com/rawfish-dev/rsvp-starter/server/interfaces"
+
+	"golang.org/x/net/context"
+)
+
+var _ interfaces.MessagingProvider = new(twilio)
+
+// twilio sends messages through the Twilio REST API. Other providers with a Twilio compatible API can
+// be used by pointing the api url at them.
+type twilio struct {
+	ctx             context.Context
+	messagingConfig config.MessagingConfig
+	client          *http.Client
+}
+
+func NewTwilio(ctx context.Context, messagingConfig config.MessagingConfig) *twilio {
+	return &twilio{
+		ctx:             ctx,
+		messagingConfig: messagingConfig,
+		client:          &http.Client{Timeout: messagingConfig.Timeout},
+	}
+}
+
+type twilioMessageResponse struct {
+	SID     string `json:"sid"`
+	Status  string `json:"status"`
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (t *twilio) Name() string {
+	return config.TwilioMessagingProvider
+}
+
+func (t *twilio) SendSMS(to, body string) (providerMessageID string, err error) {
+	ctxLogger := t.ctx.Value("logger").(interfaces.Logger)
+
+	form := url.Values{}
+	form.Set("To", to)
+	form.Set("From", t.messagingConfig.From)
+	form.Set("Body", body)
+
+	messagesURL := fmt.Sprintf("%v/2010-04-01/Accounts/%v/Messages.json", strings.TrimRight(t.messagingConfig.APIURL, "/"), t.messagingConfig.AccountSID)
+
+	req, err := http.NewRequest(http.MethodPost, messagesURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		ctxLogger.Errorf("messaging service - unable to create twilio request due to %v", err)
+		return "", NewProviderError("unable to reach twilio")
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth(t.messagingConfig.AccountSID, t.messagingConfig.AuthToken)
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		ctxLogger.Errorf("messaging service - unable to complete twilio request due to %v", err)
+		return "", NewProviderError("unable to reach twilio")
+	}
+	defer resp.Body.Close()
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		ctxLogger.Errorf("messaging service - unable to read twilio response body due to %v", err)
+		return "", NewProviderError("unable to read the response from twilio")
+	}
+
+	var messageResp twilioMessageResponse
+
+	err = json.Unmarshal(respBody, &messageResp)
+	if err != nil {
+		ctxLogger.Errorf("messaging service - unable to unwrap twilio response body with status %v due to %v", resp.StatusCode, err)
+		return "", NewProviderError(fmt.Sprintf("twilio responded with status %v", resp.StatusCode))
+	}
+
+	// Twilio explains rejected messages, such as those to invalid numbers, with a code and message
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		ctxLogger.Warnf("messaging service - twilio rejected message with status %v and code %v", resp.StatusCode, messageResp.Code)
+		return "", NewProviderError(fmt.Sprintf("twilio rejected the message with code %v: %v", messageResp.Code, messageResp.Message))
+	}
+
+	return messageResp.SID, nil
+}
@@ -0,0 +1,92 @@
+package messaging
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/rawfish-dev/rsvp-starter/server/config"
+	"github.com/rawfish-dev/rsvp-starter/server/domain"
+	"github.com/rawfish-dev/rsvp-starter/server/interfaces"
+
+	"golang.org/x/net/context"
+)
+
+var _ interfaces.MessagingProvider = new(webhook)
+
+// webhook posts each message as JSON to a url of our choosing, for gateways without a Twilio compatible
+// API. Any 2xx response counts as accepted, and the gateway may name the message with an id.
+type webhook struct {
+	ctx             context.Context
+	messagingConfig config.MessagingConfig
+	client          *http.Client
+}
+
+func NewWebhook(ctx context.Context, messagingConfig config.MessagingConfig) *webhook {
+	return &webhook{
+		ctx:             ctx,
+		messagingConfig: messagingConfig,
+		client:          &http.Client{Timeout: messagingConfig.Timeout},
+	}
+}
+
+type webhookMessageRequest struct {
+	Channel string `json:"channel"`
+	To      string `json:"to"`
+	From    string `json:"from,omitempty"`
+	Body    string `json:"body"`
+}
+
+type webhookMessageResponse struct {
+	ID string `json:"id"`
+}
+
+func (w *webhook) Name() string {
+	return config.WebhookMessagingProvider
+}
+
+func (w *webhook) SendSMS(to, body string) (providerMessageID string, err error) {
+	ctxLogger := w.ctx.Value("logger").(interfaces.Logger)
+
+	reqBody, err := json.Marshal(webhookMessageRequest{
+		Channel: string(domain.SMSChannel),
+		To:      to,
+		From:    w.messagingConfig.From,
+		Body:    body,
+	})
+	if err != nil {
+		ctxLogger.Errorf("messaging service - unable to wrap webhook request body due to %v", err)
+		return "", NewProviderError("unable to reach the messaging webhook")
+	}
+
+	resp, err := w.client.Post(w.messagingConfig.WebhookURL, "application/json", bytes.NewReader(reqBody))
+	if err != nil {
+		ctxLogger.Errorf("messaging service - unable to complete webhook request due to %v", err)
+		return "", NewProviderError("unable to reach the messaging webhook")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		ctxLogger.Warnf("messaging service - webhook responded with status %v", resp.StatusCode)
+		return "", NewProviderError(fmt.Sprintf("messaging webhook responded with status %v", resp.StatusCode))
+	}
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		ctxLogger.Errorf("messaging service - unable to read webhook response body due to %v", err)
+		return "", nil
+	}
+
+	// The message was accepted either way, so a response without an id is only worth a warning
+	var messageResp webhookMessageResponse
+
+	err = json.Unmarshal(respBody, &messageResp)
+	if err != nil {
+		ctxLogger.Warnf("messaging service - unable to unwrap webhook response body due to %v", err)
+		return "", nil
+	}
+
+	return messageResp.ID, nil
+}
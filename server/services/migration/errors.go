@@ -0,0 +1,53 @@
+package migration
+
+import (
+	"fmt"
+)
+
+type MigrationOperationError struct {
+}
+
+func NewMigrationOperationError() error {
+	return MigrationOperationError{}
+}
+
+func (m MigrationOperationError) Error() string {
+	return "unable to read schema migrations"
+}
+
+type MigrationFailedError struct {
+	version int64
+	name    string
+}
+
+func NewMigrationFailedError(version int64, name string) error {
+	return MigrationFailedError{version, name}
+}
+
+func (m MigrationFailedError) Error() string {
+	return fmt.Sprintf("migration %v %v failed", m.version, m.name)
+}
+
+type MigrationDuplicateVersionError struct {
+	version int64
+}
+
+func NewMigrationDuplicateVersionError(version int64) error {
+	return MigrationDuplicateVersionError{version}
+}
+
+func (m MigrationDuplicateVersionError) Error() string {
+	return fmt.Sprintf("migration version %v is defined more than once", m.version)
+}
+
+type MigrationUnknownVersionError struct {
+	version int64
+}
+
+func NewMigrationUnknownVersionError(version int64) error {
+	return MigrationUnknownVersionError{version}
+}
+
+func (m MigrationUnknownVersionError) Error() string {
+	return fmt.Sprintf("applied migration version %v is unknown", m.version)
+}
@@ -0,0 +1,330 @@
+package migration
+
+import (
+	"database/sql"
+	"sort"
+	"time"
+
+	"github.com/rawfish-dev/rsvp-starter/server/domain"
+	"github.com/rawfish-dev/rsvp-starter/server/interfaces"
+
+	"golang.org/x/net/context"
+)
+
+const (
+	createSchemaMigrationsTable = `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version bigint PRIMARY KEY,
+			name text NOT NULL,
+			applied_at timestamp NOT NULL
+		)
+	`
+	selectAppliedMigrations = `
+		SELECT version, applied_at
+		FROM schema_migrations
+		ORDER BY version ASC
+	`
+	insertAppliedMigration = `
+		INSERT INTO schema_migrations (version, name, applied_at)
+		VALUES ($1, $2, $3)
+	`
+	deleteAppliedMigration = `
+		DELETE FROM schema_migrations
+		WHERE version=$1
+	`
+	checkSchemaMigrationsTable = `
+		SELECT COUNT(*)
+		FROM schema_migrations
+	`
+	selectGooseMigrations = `
+		SELECT version_id, is_applied, tstamp
+		FROM goose_db_version
+		ORDER BY id ASC
+	`
+)
+
+// Migration is a single versioned schema change. Versions are applied in ascending order
+// and Down must undo everything Up does.
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+}
+
+var _ interfaces.MigrationServiceProvider = new(service)
+
+type service struct {
+	ctx        context.Context
+	db         *sql.DB
+	migrations []Migration
+}
+
+func NewService(ctx context.Context, db *sql.DB, migrations []Migration) *service {
+	sortedMigrations := make([]Migration, len(migrations))
+	copy(sortedMigrations, migrations)
+	sort.Sort(byVersion(sortedMigrations))
+
+	return &service{ctx, db, sortedMigrations}
+}
+
+func (s *service) Up() ([]domain.MigrationStatus, error) {
+	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
+
+	appliedAt, err := s.appliedMigrations()
+	if err != nil {
+		return nil, err
+	}
+
+	var applied []domain.MigrationStatus
+	for idx := range s.migrations {
+		migration := s.migrations[idx]
+		if _, ok := appliedAt[migration.Version]; ok {
+			continue
+		}
+
+		ctxLogger.Infof("migration service - applying %v %v", migration.Version, migration.Name)
+
+		now := time.Now().UTC()
+		err = s.inTransaction(func(tx *sql.Tx) error {
+			if _, err := tx.Exec(migration.Up); err != nil {
+				return err
+			}
+			_, err := tx.Exec(insertAppliedMigration, migration.Version, migration.Name, now)
+			return err
+		})
+		if err != nil {
+			ctxLogger.Errorf("migration service - unable to apply %v %v due to %v", migration.Version, migration.Name, err)
+			return applied, NewMigrationFailedError(migration.Version, migration.Name)
+		}
+
+		applied = append(applied, migrationStatus(migration, &now))
+	}
+
+	return applied, nil
+}
+
+func (s *service) Down() (*domain.MigrationStatus, error) {
+	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
+
+	appliedAt, err := s.appliedMigrations()
+	if err != nil {
+		return nil, err
+	}
+
+	// Roll back the latest applied migration only
+	for idx := len(s.migrations) - 1; idx >= 0; idx-- {
+		migration := s.migrations[idx]
+		if _, ok := appliedAt[migration.Version]; !ok {
+			continue
+		}
+
+		ctxLogger.Infof("migration service - rolling back %v %v", migration.Version, migration.Name)
+
+		err = s.inTransaction(func(tx *sql.Tx) error {
+			if _, err := tx.Exec(migration.Down); err != nil {
+				return err
+			}
+			_, err := tx.Exec(deleteAppliedMigration, migration.Version)
+			return err
+		})
+		if err != nil {
+			ctxLogger.Errorf("migration service - unable to roll back %v %v due to %v", migration.Version, migration.Name, err)
+			return nil, NewMigrationFailedError(migration.Version, migration.Name)
+		}
+
+		rolledBack := migrationStatus(migration, nil)
+		return &rolledBack, nil
+	}
+
+	ctxLogger.Warn("migration service - no applied migrations to roll back")
+	return nil, nil
+}
+
+func (s *service) Status() ([]domain.MigrationStatus, error) {
+	appliedAt, err := s.appliedMigrations()
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]domain.MigrationStatus, len(s.migrations))
+	for idx := range s.migrations {
+		var migrationAppliedAt *time.Time
+		if timestamp, ok := appliedAt[s.migrations[idx].Version]; ok {
+			migrationAppliedAt = &timestamp
+		}
+
+		statuses[idx] = migrationStatus(s.migrations[idx], migrationAppliedAt)
+	}
+
+	return statuses, nil
+}
+
+// appliedMigrations creates the bookkeeping table when missing and returns the applied versions.
+// Versions recorded in the database that this binary does not know about are treated as an error
+// since the schema is newer than the code.
+func (s *service) appliedMigrations() (map[int64]time.Time, error) {
+	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
+
+	for idx := 1; idx < len(s.migrations); idx++ {
+		if s.migrations[idx].Version == s.migrations[idx-1].Version {
+			ctxLogger.Errorf("migration service - version %v is defined more than once", s.migrations[idx].Version)
+			return nil, NewMigrationDuplicateVersionError(s.migrations[idx].Version)
+		}
+	}
+
+	// The check fails when the bookkeeping table does not exist yet, i.e. this binary has never migrated the database
+	var count int
+	if s.db.QueryRow(checkSchemaMigrationsTable).Scan(&count) != nil {
+		err := s.createSchemaMigrations()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	rows, err := s.db.Query(selectAppliedMigrations)
+	if err != nil {
+		ctxLogger.Errorf("migration service - unable to retrieve applied migrations due to %v", err)
+		return nil, NewMigrationOperationError()
+	}
+	defer rows.Close()
+
+	knownVersions := make(map[int64]bool)
+	for idx := range s.migrations {
+		knownVersions[s.migrations[idx].Version] = true
+	}
+
+	appliedAt := make(map[int64]time.Time)
+	for rows.Next() {
+		var version int64
+		var timestamp time.Time
+
+		err = rows.Scan(&version, &timestamp)
+		if err != nil {
+			ctxLogger.Errorf("migration service - unable to read applied migration due to %v", err)
+			return nil, NewMigrationOperationError()
+		}
+
+		if !knownVersions[version] {
+			ctxLogger.Errorf("migration service - applied version %v is not known to this binary", version)
+			return nil, NewMigrationUnknownVersionError(version)
+		}
+
+		appliedAt[version] = timestamp
+	}
+	if err = rows.Err(); err != nil {
+		ctxLogger.Errorf("migration service - unable to read applied migrations due to %v", err)
+		return nil, NewMigrationOperationError()
+	}
+
+	return appliedAt, nil
+}
+
+// createSchemaMigrations creates the bookkeeping table and records the versions goose already applied.
+// Databases created before migrations were compiled into the binary were managed by goose with the same
+// versions, so without this `migrate up` would try to create tables that already exist. Goose appends a
+// row for every apply and rollback, so the latest row for a version decides whether it is applied. Both
+// happen in one transaction so a failed adoption is retried on the next run.
+func (s *service) createSchemaMigrations() error {
+	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
+
+	gooseAppliedAt, err := s.gooseMigrations()
+	if err != nil {
+		return err
+	}
+
+	err = s.inTransaction(func(tx *sql.Tx) error {
+		if _, err := tx.Exec(createSchemaMigrationsTable); err != nil {
+			return err
+		}
+
+		for idx := range s.migrations {
+			migration := s.migrations[idx]
+			timestamp, ok := gooseAppliedAt[migration.Version]
+			if !ok {
+				continue
+			}
+
+			ctxLogger.Infof("migration service - marking %v %v as applied by goose", migration.Version, migration.Name)
+
+			if _, err := tx.Exec(insertAppliedMigration, migration.Version, migration.Name, timestamp); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		ctxLogger.Errorf("migration service - unable to create schema_migrations table due to %v", err)
+		return NewMigrationOperationError()
+	}
+
+	return nil
+}
+
+// gooseMigrations returns the versions goose has applied, or none when goose never managed the database.
+func (s *service) gooseMigrations() (map[int64]time.Time, error) {
+	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
+
+	gooseAppliedAt := make(map[int64]time.Time)
+
+	// An error here means there is no goose table to adopt
+	rows, err := s.db.Query(selectGooseMigrations)
+	if err != nil {
+		return gooseAppliedAt, nil
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var version int64
+		var isApplied bool
+		var timestamp time.Time
+
+		err = rows.Scan(&version, &isApplied, &timestamp)
+		if err != nil {
+			ctxLogger.Errorf("migration service - unable to read goose migration due to %v", err)
+			return nil, NewMigrationOperationError()
+		}
+
+		if isApplied {
+			gooseAppliedAt[version] = timestamp.UTC()
+		} else {
+			delete(gooseAppliedAt, version)
+		}
+	}
+	if err = rows.Err(); err != nil {
+		ctxLogger.Errorf("migration service - unable to read goose migrations due to %v", err)
+		return nil, NewMigrationOperationError()
+	}
+
+	return gooseAppliedAt, nil
+}
+
+func (s *service) inTransaction(fn func(tx *sql.Tx) error) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	err = fn(tx)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func migrationStatus(migration Migration, appliedAt *time.Time) domain.MigrationStatus {
+	return domain.MigrationStatus{
+		Version:   migration.Version,
+		Name:      migration.Name,
+		Applied:   appliedAt != nil,
+		AppliedAt: appliedAt,
+	}
+}
+
+type byVersion []Migration
+
+func (b byVersion) Len() int           { return len(b) }
+func (b byVersion) Less(i, j int) bool { return b[i].Version < b[j].Version }
+func (b byVersion) Swap(i, j int)      { b[i], b[j] = b[j], b[i] }
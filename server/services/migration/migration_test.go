@@ -0,0 +1,221 @@
+package migration_test
+
+import (
+	_ "github.com/mattn/go-sqlite3"
+
+	"database/sql"
+
+	"github.com/rawfish-dev/rsvp-starter/server/interfaces"
+	. "github.com/rawfish-dev/rsvp-starter/server/services/migration"
+
+	"github.com/Sirupsen/logrus"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"golang.org/x/net/context"
+)
+
+var _ = Describe("Migration", func() {
+
+	var ctx context.Context
+	var db *sql.DB
+	var migrations []Migration
+	var testMigrationService interfaces.MigrationServiceProvider
+
+	tableExists := func(name string) bool {
+		var count int
+		err := db.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE type='table' AND name=?", name).Scan(&count)
+		Expect(err).ToNot(HaveOccurred())
+		return count == 1
+	}
+
+	BeforeEach(func() {
+		ctxlogger := logrus.New()
+		ctx = context.Background()
+		ctx = context.WithValue(ctx, "logger", ctxlogger)
+
+		var err error
+		db, err = sql.Open("sqlite3", ":memory:")
+		Expect(err).ToNot(HaveOccurred())
+		db.SetMaxOpenConns(1)
+
+		// Deliberately out of order to ensure versions decide the order
+		migrations = []Migration{
+			{
+				Version: 2,
+				Name:    "CreateInvitations",
+				Up:      "CREATE TABLE invitations (id integer, category_id integer REFERENCES categories(id));",
+				Down:    "DROP TABLE invitations;",
+			},
+			{
+				Version: 1,
+				Name:    "CreateCategories",
+				Up:      "CREATE TABLE categories (id integer PRIMARY KEY);",
+				Down:    "DROP TABLE categories;",
+			},
+		}
+
+		testMigrationService = NewService(ctx, db, migrations)
+	})
+
+	AfterEach(func() {
+		db.Close()
+	})
+
+	Context("up", func() {
+
+		It("should apply all pending migrations in version order", func() {
+			applied, err := testMigrationService.Up()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(applied).To(HaveLen(2))
+			Expect(applied[0].Name).To(Equal("CreateCategories"))
+			Expect(applied[1].Name).To(Equal("CreateInvitations"))
+			Expect(tableExists("categories")).To(BeTrue())
+			Expect(tableExists("invitations")).To(BeTrue())
+		})
+
+		It("should not apply migrations more than once", func() {
+			_, err := testMigrationService.Up()
+			Expect(err).ToNot(HaveOccurred())
+
+			applied, err := testMigrationService.Up()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(applied).To(BeEmpty())
+		})
+
+		It("should roll back a failed migration and stop", func() {
+			migrations = append(migrations, Migration{
+				Version: 3,
+				Name:    "Broken",
+				Up:      "CREATE TABLE rsvps (id integer); NOT VALID SQL;",
+				Down:    "DROP TABLE rsvps;",
+			})
+			testMigrationService = NewService(ctx, db, migrations)
+
+			applied, err := testMigrationService.Up()
+			Expect(err).To(BeAssignableToTypeOf(MigrationFailedError{}))
+			Expect(applied).To(HaveLen(2))
+			Expect(tableExists("rsvps")).To(BeFalse())
+
+			statuses, err := testMigrationService.Status()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(statuses[2].Applied).To(BeFalse())
+		})
+
+		It("should reject duplicate versions", func() {
+			migrations = append(migrations, Migration{Version: 1, Name: "Duplicate"})
+			testMigrationService = NewService(ctx, db, migrations)
+
+			_, err := testMigrationService.Up()
+			Expect(err).To(BeAssignableToTypeOf(MigrationDuplicateVersionError{}))
+		})
+
+		It("should refuse to run against a schema newer than the binary", func() {
+			_, err := testMigrationService.Up()
+			Expect(err).ToNot(HaveOccurred())
+
+			testMigrationService = NewService(ctx, db, migrations[1:])
+
+			_, err = testMigrationService.Up()
+			Expect(err).To(BeAssignableToTypeOf(MigrationUnknownVersionError{}))
+		})
+	})
+
+	Context("databases managed by goose", func() {
+
+		BeforeEach(func() {
+			_, err := db.Exec(`
+				CREATE TABLE goose_db_version (
+					id integer PRIMARY KEY AUTOINCREMENT,
+					version_id bigint NOT NULL,
+					is_applied boolean NOT NULL,
+					tstamp timestamp DEFAULT CURRENT_TIMESTAMP
+				);
+				INSERT INTO goose_db_version (version_id, is_applied) VALUES (0, 1);
+				CREATE TABLE categories (id integer PRIMARY KEY);
+				INSERT INTO goose_db_version (version_id, is_applied) VALUES (1, 1);
+			`)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should mark the versions goose applied as applied instead of running them again", func() {
+			applied, err := testMigrationService.Up()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(applied).To(HaveLen(1))
+			Expect(applied[0].Name).To(Equal("CreateInvitations"))
+
+			statuses, err := testMigrationService.Status()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(statuses[0].Applied).To(BeTrue())
+			Expect(statuses[1].Applied).To(BeTrue())
+		})
+
+		It("should not adopt versions goose rolled back", func() {
+			_, err := db.Exec(`
+				CREATE TABLE invitations (id integer);
+				INSERT INTO goose_db_version (version_id, is_applied) VALUES (2, 1);
+				DROP TABLE invitations;
+				INSERT INTO goose_db_version (version_id, is_applied) VALUES (2, 0);
+			`)
+			Expect(err).ToNot(HaveOccurred())
+
+			statuses, err := testMigrationService.Status()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(statuses[0].Applied).To(BeTrue())
+			Expect(statuses[1].Applied).To(BeFalse())
+		})
+
+		It("should not adopt goose versions again after everything is rolled back", func() {
+			_, err := testMigrationService.Up()
+			Expect(err).ToNot(HaveOccurred())
+			_, err = testMigrationService.Down()
+			Expect(err).ToNot(HaveOccurred())
+			_, err = testMigrationService.Down()
+			Expect(err).ToNot(HaveOccurred())
+
+			statuses, err := testMigrationService.Status()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(statuses[0].Applied).To(BeFalse())
+			Expect(statuses[1].Applied).To(BeFalse())
+		})
+	})
+
+	Context("down", func() {
+
+		It("should roll back only the latest applied migration", func() {
+			_, err := testMigrationService.Up()
+			Expect(err).ToNot(HaveOccurred())
+
+			rolledBack, err := testMigrationService.Down()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(rolledBack.Version).To(Equal(int64(2)))
+			Expect(tableExists("invitations")).To(BeFalse())
+			Expect(tableExists("categories")).To(BeTrue())
+		})
+
+		It("should do nothing when no migrations have been applied", func() {
+			rolledBack, err := testMigrationService.Down()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(rolledBack).To(BeNil())
+		})
+	})
+
+	Context("status", func() {
+
+		It("should list every migration with whether it has been applied", func() {
+			_, err := testMigrationService.Up()
+			Expect(err).ToNot(HaveOccurred())
+			_, err = testMigrationService.Down()
+			Expect(err).ToNot(HaveOccurred())
+
+			statuses, err := testMigrationService.Status()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(statuses).To(HaveLen(2))
+			Expect(statuses[0].Version).To(Equal(int64(1)))
+			Expect(statuses[0].Applied).To(BeTrue())
+			Expect(statuses[0].AppliedAt).ToNot(BeNil())
+			Expect(statuses[1].Version).To(Equal(int64(2)))
+			Expect(statuses[1].Applied).To(BeFalse())
+			Expect(statuses[1].AppliedAt).To(BeNil())
+		})
+	})
+})
@@ -6,10 +6,20 @@ import (
 	"gopkg.in/gorp.v1"
 )
 
+// DeletedAt is only set once the record is moved to the trash
 type baseModel struct {
-	ID        int64     `db:"id"`
-	CreatedAt time.Time `db:"created_at"`
-	UpdatedAt time.Time `db:"updated_at"`
+	ID        int64      `db:"id"`
+	CreatedAt time.Time  `db:"created_at"`
+	UpdatedAt time.Time  `db:"updated_at"`
+	DeletedAt *time.Time `db:"deleted_at"`
+}
+
+func (b *baseModel) deletedAt() string {
+	if b.DeletedAt == nil {
+		return ""
+	}
+
+	return b.DeletedAt.Format(time.RFC3339)
 }
 
 func (b *baseModel) PreInsert(s gorp.SqlExecutor) error {
@@ -3,9 +3,11 @@ package postgres
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/rawfish-dev/rsvp-starter/server/domain"
 	"github.com/rawfish-dev/rsvp-starter/server/interfaces"
+	"github.com/rawfish-dev/rsvp-starter/server/services/storage"
 )
 
 type category struct {
@@ -24,7 +26,13 @@ var (
 		"tag",
 		"created_at",
 		"updated_at",
+		"deleted_at",
 	}, ",")
+
+	categorySortColumns = map[string]string{
+		domain.SortByCategoryTag:   "LOWER(categories.tag)",
+		domain.SortByCategoryTotal: "total",
+	}
 )
 
 func (s *service) InsertCategory(req *domain.CategoryCreateRequest) (*domain.Category, error) {
@@ -34,15 +42,15 @@ func (s *service) InsertCategory(req *domain.CategoryCreateRequest) (*domain.Cat
 		Tag: req.Tag,
 	}
 
-	err := s.gorpDB.Insert(category)
+	err := s.executor.Insert(category)
 	if err != nil {
 		if isCategoryTagUniqueConstraintError(err) {
 			ctxLogger.Warn("postgres service - unable to insert category with a duplicate tag")
-			return nil, NewPostgresCategoryTagUniqueConstraintError()
+			return nil, storage.NewStorageCategoryTagUniqueConstraintError()
 		}
 
 		ctxLogger.Errorf("postgres service - unable to insert category due to %v", err)
-		return nil, NewPostgresOperationError()
+		return nil, storage.NewStorageOperationError()
 	}
 
 	newCategory := &domain.Category{
@@ -60,22 +68,22 @@ func (s *service) FindCategoryByID(categoryID int64) (*domain.Category, error) {
 		SELECT %v, COUNT(invitations.id) as total
 		FROM categories
 		LEFT JOIN invitations
-        ON categories.id=invitations.category_id 
-		WHERE categories.id=$1
+        ON categories.id=invitations.category_id AND invitations.deleted_at IS NULL
+		WHERE categories.id=$1 AND categories.deleted_at IS NULL
 		GROUP BY categories.id
 	`, prependColumnsForJoin())
 
 	var category categoryAggregate
 
-	err := s.gorpDB.SelectOne(&category, query, categoryID)
+	err := s.executor.SelectOne(&category, query, categoryID)
 	if err != nil {
 		if isNotFoundError(err) {
 			ctxLogger.Warnf("postgres service - unable to find category with id %v", categoryID)
-			return nil, NewPostgresRecordNotFoundError()
+			return nil, storage.NewStorageRecordNotFoundError()
 		}
 
 		ctxLogger.Errorf("postgres service - unable to find category with id %v due to %v", categoryID, err)
-		return nil, NewPostgresOperationError()
+		return nil, storage.NewStorageOperationError()
 	}
 
 	domainCategory := &domain.Category{
@@ -87,24 +95,34 @@ func (s *service) FindCategoryByID(categoryID int64) (*domain.Category, error) {
 	return domainCategory, nil
 }
 
-func (s *service) ListCategories() ([]domain.Category, error) {
+func (s *service) ListCategories(req *domain.CategoryListRequest) ([]domain.Category, int, error) {
 	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
 
+	total, err := s.executor.SelectInt("SELECT COUNT(*) FROM categories WHERE deleted_at IS NULL")
+	if err != nil {
+		ctxLogger.Errorf("postgres service - unable to count categories due to %v", err)
+		return nil, 0, storage.NewStorageOperationError()
+	}
+
+	var filters listQuery
+	pageClause, args := filters.pageClause(req.ListRequest, categorySortColumns, "categories.id")
+
 	query := fmt.Sprintf(`
 		SELECT %v, COUNT(invitations.id) as total
 		FROM categories
 		LEFT JOIN invitations
-        ON categories.id=invitations.category_id
+        ON categories.id=invitations.category_id AND invitations.deleted_at IS NULL
+		WHERE categories.deleted_at IS NULL
 		GROUP BY categories.id
-        ORDER BY tag DESC
-	`, prependColumnsForJoin())
+		%v
+	`, prependColumnsForJoin(), pageClause)
 
 	var categories []categoryAggregate
 
-	_, err := s.gorpDB.Select(&categories, query)
+	_, err = s.executor.Select(&categories, query, args...)
 	if err != nil {
 		ctxLogger.Errorf("postgres service - unable to retrieve categories due to %v", err)
-		return nil, NewPostgresOperationError()
+		return nil, 0, storage.NewStorageOperationError()
 	}
 
 	domainCategories := make([]domain.Category, len(categories))
@@ -116,23 +134,32 @@ func (s *service) ListCategories() ([]domain.Category, error) {
 		}
 	}
 
-	return domainCategories, nil
+	return domainCategories, int(total), nil
 }
 
 func (s *service) UpdateCategory(domainCategory *domain.Category) (*domain.Category, error) {
 	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
 
-	category := &category{
-		baseModel: baseModel{
-			ID: domainCategory.ID,
-		},
-		Tag: domainCategory.Tag,
-	}
+	query := `
+		UPDATE categories
+		SET tag=$1, updated_at=$2
+		WHERE id=$3 AND deleted_at IS NULL
+	`
 
-	_, err := s.gorpDB.Update(category)
+	result, err := s.executor.Exec(query, domainCategory.Tag, time.Now(), domainCategory.ID)
 	if err != nil {
-		ctxLogger.Errorf("postgres service - unable to update category %+v due to %v", category, err)
-		return nil, NewPostgresOperationError()
+		if isCategoryTagUniqueConstraintError(err) {
+			ctxLogger.Warn("postgres service - unable to update category with a duplicate tag")
+			return nil, storage.NewStorageCategoryTagUniqueConstraintError()
+		}
+
+		ctxLogger.Errorf("postgres service - unable to update category %+v due to %v", domainCategory, err)
+		return nil, storage.NewStorageOperationError()
+	}
+
+	if rowsAffected, _ := result.RowsAffected(); rowsAffected == 0 {
+		ctxLogger.Warnf("postgres service - unable to update category with id %v as it does not exist", domainCategory.ID)
+		return nil, storage.NewStorageRecordNotFoundError()
 	}
 
 	return domainCategory, nil
@@ -141,21 +168,134 @@ func (s *service) UpdateCategory(domainCategory *domain.Category) (*domain.Categ
 func (s *service) DeleteCategory(domainCategory *domain.Category) error {
 	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
 
-	_, err := s.gorpDB.Delete(domainCategory)
+	// Invitations outside the trash keep their category just as the foreign key did for hard deletes
+	invitationCount, err := s.executor.SelectInt("SELECT COUNT(*) FROM invitations WHERE category_id=$1 AND deleted_at IS NULL", domainCategory.ID)
+	if err != nil {
+		ctxLogger.Errorf("postgres service - unable to count invitations of category with id %v due to %v", domainCategory.ID, err)
+		return storage.NewStorageOperationError()
+	}
+	if invitationCount > 0 {
+		ctxLogger.Errorf("postgres service - unable to delete category with id %v as invitations still reference it", domainCategory.ID)
+		return storage.NewStorageOperationError()
+	}
+
+	result, err := s.executor.Exec("UPDATE categories SET deleted_at=$1 WHERE id=$2 AND deleted_at IS NULL", time.Now(), domainCategory.ID)
 	if err != nil {
 		ctxLogger.Errorf("postgres service - unable to delete category with id %v due to %v", domainCategory.ID, err)
-		return NewPostgresOperationError()
+		return storage.NewStorageOperationError()
+	}
+
+	if rowsAffected, _ := result.RowsAffected(); rowsAffected == 0 {
+		ctxLogger.Warnf("postgres service - unable to delete category with id %v as it does not exist", domainCategory.ID)
+		return storage.NewStorageRecordNotFoundError()
 	}
 
 	return nil
 }
 
-func prependColumnsForJoin() string {
-	columns := strings.Split(categoryColumns, ",")
-	prependedColumns := make([]string, len(columns))
-	for idx := range columns {
-		prependedColumns[idx] = "categories." + columns[idx]
+func (s *service) FindDeletedCategoryByID(categoryID int64) (*domain.Category, error) {
+	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
+
+	query := fmt.Sprintf(`
+		SELECT %v
+		FROM categories
+		WHERE id=$1 AND deleted_at IS NOT NULL
+	`, categoryColumns)
+
+	var category category
+
+	err := s.executor.SelectOne(&category, query, categoryID)
+	if err != nil {
+		if isNotFoundError(err) {
+			ctxLogger.Warnf("postgres service - unable to find deleted category with id %v", categoryID)
+			return nil, storage.NewStorageRecordNotFoundError()
+		}
+
+		ctxLogger.Errorf("postgres service - unable to find deleted category with id %v due to %v", categoryID, err)
+		return nil, storage.NewStorageOperationError()
+	}
+
+	domainCategory := &domain.Category{
+		ID:        category.ID,
+		Tag:       category.Tag,
+		DeletedAt: category.deletedAt(),
+	}
+
+	return domainCategory, nil
+}
+
+func (s *service) ListDeletedCategories() ([]domain.Category, error) {
+	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
+
+	query := fmt.Sprintf(`
+		SELECT %v
+		FROM categories
+		WHERE deleted_at IS NOT NULL
+		ORDER BY deleted_at DESC, id DESC
+	`, categoryColumns)
+
+	var categories []category
+
+	_, err := s.executor.Select(&categories, query)
+	if err != nil {
+		ctxLogger.Errorf("postgres service - unable to retrieve deleted categories due to %v", err)
+		return nil, storage.NewStorageOperationError()
+	}
+
+	domainCategories := make([]domain.Category, len(categories))
+	for idx := range categories {
+		domainCategories[idx] = domain.Category{
+			ID:        categories[idx].ID,
+			Tag:       categories[idx].Tag,
+			DeletedAt: categories[idx].deletedAt(),
+		}
 	}
 
-	return strings.Join(prependedColumns, ",")
+	return domainCategories, nil
+}
+
+func (s *service) RestoreCategory(domainCategory *domain.Category) (*domain.Category, error) {
+	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
+
+	result, err := s.executor.Exec("UPDATE categories SET deleted_at=NULL WHERE id=$1 AND deleted_at IS NOT NULL", domainCategory.ID)
+	if err != nil {
+		if isCategoryTagUniqueConstraintError(err) {
+			ctxLogger.Warn("postgres service - unable to restore category with a duplicate tag")
+			return nil, storage.NewStorageCategoryTagUniqueConstraintError()
+		}
+
+		ctxLogger.Errorf("postgres service - unable to restore category with id %v due to %v", domainCategory.ID, err)
+		return nil, storage.NewStorageOperationError()
+	}
+
+	if rowsAffected, _ := result.RowsAffected(); rowsAffected == 0 {
+		ctxLogger.Warnf("postgres service - unable to restore category with id %v as it is not deleted", domainCategory.ID)
+		return nil, storage.NewStorageRecordNotFoundError()
+	}
+
+	return s.FindCategoryByID(domainCategory.ID)
+}
+
+func (s *service) PurgeCategories(deletedBefore time.Time) (int, error) {
+	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
+
+	query := `
+		DELETE FROM categories
+		WHERE deleted_at<$1
+		AND NOT EXISTS (SELECT 1 FROM invitations WHERE invitations.category_id=categories.id)
+	`
+
+	result, err := s.executor.Exec(query, deletedBefore)
+	if err != nil {
+		ctxLogger.Errorf("postgres service - unable to purge categories deleted before %v due to %v", deletedBefore, err)
+		return 0, storage.NewStorageOperationError()
+	}
+
+	purged, _ := result.RowsAffected()
+
+	return int(purged), nil
+}
+
+func prependColumnsForJoin() string {
+	return prefixColumns("categories", categoryColumns)
 }
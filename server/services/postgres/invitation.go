@@ -7,19 +7,26 @@ import (
 
 	"github.com/rawfish-dev/rsvp-starter/server/domain"
 	"github.com/rawfish-dev/rsvp-starter/server/interfaces"
+	"github.com/rawfish-dev/rsvp-starter/server/services/storage"
 
 	"github.com/satori/go.uuid"
 )
 
 type invitation struct {
 	baseModel
-	CategoryID        int64  `db:"category_id"`
-	PrivateID         string `db:"private_id"`
-	Greeting          string `db:"greeting"`
-	MaximumGuestCount int    `db:"maximum_guest_count"`
-	Status            string `db:"status"`
-	Notes             string `db:"notes"`
-	MobilePhoneNumber string `db:"mobile_phone_number"`
+	CategoryID               int64      `db:"category_id"`
+	PrivateID                string     `db:"private_id"`
+	Greeting                 string     `db:"greeting"`
+	MaximumGuestCount        int        `db:"maximum_guest_count"`
+	Status                   string     `db:"status"`
+	Notes                    string     `db:"notes"`
+	MobilePhoneNumber        string     `db:"mobile_phone_number"`
+	Email                    string     `db:"email"`
+	Language                 string     `db:"language"`
+	LinkExpiresAt            *time.Time `db:"link_expires_at"`
+	LinkRevokedAt            *time.Time `db:"link_revoked_at"`
+	RequirePhoneConfirmation bool       `db:"require_phone_confirmation"`
+	Version                  int64      `db:"version"` // gorp sets this to 1 on insert as it treats Version as a lock column
 }
 
 var (
@@ -32,11 +39,39 @@ var (
 		"status",
 		"notes",
 		"mobile_phone_number",
+		"email",
+		"language",
+		"link_expires_at",
+		"link_revoked_at",
+		"require_phone_confirmation",
 		"created_at",
 		"updated_at",
+		"version",
+		"deleted_at",
 	}, ",")
+
+	invitationSortColumns = map[string]string{
+		domain.SortByInvitationGreeting:          "LOWER(invitations.greeting)",
+		domain.SortByInvitationMaximumGuestCount: "invitations.maximum_guest_count",
+		domain.SortByInvitationStatus:            invitationStatusExpression,
+		domain.SortByInvitationUpdatedAt:         "invitations.updated_at",
+	}
 )
 
+// invitationStatusExpression reports whether the guests replied in place of the stored status
+// once an rsvp exists, and needs rsvps to be joined on the invitation private id.
+const invitationStatusExpression = `CASE
+	WHEN rsvps.id IS NULL THEN invitations.status
+	WHEN rsvps.attending THEN 'RA'
+	ELSE 'RN'
+END`
+
+// invitationListing is an invitation along with the status derived from its rsvp
+type invitationListing struct {
+	invitation
+	RSVPStatus string `db:"rsvp_status"`
+}
+
 func (s *service) InsertInvitation(req *domain.InvitationCreateRequest) (*domain.Invitation, error) {
 	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
 
@@ -48,21 +83,23 @@ func (s *service) InsertInvitation(req *domain.InvitationCreateRequest) (*domain
 		Status:            string(domain.NotSent),
 		Notes:             req.Notes,
 		MobilePhoneNumber: req.MobilePhoneNumber,
+		Email:             req.Email,
+		Language:          req.Language,
 	}
 
-	err := s.gorpDB.Insert(invitation)
+	err := s.executor.Insert(invitation)
 	if err != nil {
 		if isInvitationGreetingUniqueConstraintError(err) {
 			ctxLogger.Warnf("postgres service - unable to insert invitation with a duplicate greeting %v", invitation.Greeting)
-			return nil, NewPostgresInvitationGreetingUniqueConstraintError()
+			return nil, storage.NewStorageInvitationGreetingUniqueConstraintError()
 		}
 		if isInvitationMobilePhoneNumberUniqueConstraintError(err) {
 			ctxLogger.Warnf("postgres service - unable to insert invitation with a duplicate mobile phone number %v", invitation.MobilePhoneNumber)
-			return nil, NewPostgresInvitationMobilePhoneNumberUniqueConstraintError()
+			return nil, storage.NewStorageInvitationMobilePhoneNumberUniqueConstraintError()
 		}
 
 		ctxLogger.Errorf("postgres service - unable to insert invitation due to %v", err)
-		return nil, NewPostgresOperationError()
+		return nil, storage.NewStorageOperationError()
 	}
 
 	newInvitation := &domain.Invitation{
@@ -72,11 +109,17 @@ func (s *service) InsertInvitation(req *domain.InvitationCreateRequest) (*domain
 			MaximumGuestCount: invitation.MaximumGuestCount,
 			Notes:             invitation.Notes,
 			MobilePhoneNumber: invitation.MobilePhoneNumber,
+			Email:             invitation.Email,
+			Language:          invitation.Language,
 		},
-		ID:        invitation.ID,
-		PrivateID: invitation.PrivateID,
-		Status:    domain.RSVPStatus(invitation.Status),
-		UpdatedAt: invitation.UpdatedAt.Format(time.RFC3339),
+		ID:                       invitation.ID,
+		PrivateID:                invitation.PrivateID,
+		Status:                   domain.RSVPStatus(invitation.Status),
+		LinkExpiresAt:            formatOptionalTime(invitation.LinkExpiresAt),
+		LinkRevokedAt:            formatOptionalTime(invitation.LinkRevokedAt),
+		RequirePhoneConfirmation: invitation.RequirePhoneConfirmation,
+		UpdatedAt:                invitation.UpdatedAt.Format(time.RFC3339),
+		Version:                  invitation.Version,
 	}
 
 	return newInvitation, nil
@@ -88,20 +131,20 @@ func (s *service) FindInvitationByID(invitationID int64) (*domain.Invitation, er
 	query := fmt.Sprintf(`
 		SELECT %v
 		FROM invitations
-		WHERE id=$1
+		WHERE id=$1 AND deleted_at IS NULL
 	`, invitationColumns)
 
 	var invitation invitation
 
-	err := s.gorpDB.SelectOne(&invitation, query, invitationID)
+	err := s.executor.SelectOne(&invitation, query, invitationID)
 	if err != nil {
 		if isNotFoundError(err) {
 			ctxLogger.Warnf("postgres service - unable to find invitation with id %v", invitationID)
-			return nil, NewPostgresRecordNotFoundError()
+			return nil, storage.NewStorageRecordNotFoundError()
 		}
 
 		ctxLogger.Errorf("postgres service - unable to find invitation with id %v due to %v", invitationID, err)
-		return nil, NewPostgresOperationError()
+		return nil, storage.NewStorageOperationError()
 	}
 
 	domainInvitation := &domain.Invitation{
@@ -111,11 +154,17 @@ func (s *service) FindInvitationByID(invitationID int64) (*domain.Invitation, er
 			MaximumGuestCount: invitation.MaximumGuestCount,
 			Notes:             invitation.Notes,
 			MobilePhoneNumber: invitation.MobilePhoneNumber,
+			Email:             invitation.Email,
+			Language:          invitation.Language,
 		},
-		ID:        invitation.ID,
-		PrivateID: invitation.PrivateID,
-		Status:    domain.RSVPStatus(invitation.Status),
-		UpdatedAt: invitation.UpdatedAt.Format(time.RFC3339),
+		ID:                       invitation.ID,
+		PrivateID:                invitation.PrivateID,
+		Status:                   domain.RSVPStatus(invitation.Status),
+		LinkExpiresAt:            formatOptionalTime(invitation.LinkExpiresAt),
+		LinkRevokedAt:            formatOptionalTime(invitation.LinkRevokedAt),
+		RequirePhoneConfirmation: invitation.RequirePhoneConfirmation,
+		UpdatedAt:                invitation.UpdatedAt.Format(time.RFC3339),
+		Version:                  invitation.Version,
 	}
 
 	return domainInvitation, nil
@@ -127,20 +176,20 @@ func (s *service) FindInvitationByPrivateID(privateID string) (*domain.Invitatio
 	query := fmt.Sprintf(`
 		SELECT %v
 		FROM invitations
-		WHERE private_id=$1
+		WHERE private_id=$1 AND deleted_at IS NULL
 	`, invitationColumns)
 
 	var invitation invitation
 
-	err := s.gorpDB.SelectOne(&invitation, query, privateID)
+	err := s.executor.SelectOne(&invitation, query, privateID)
 	if err != nil {
 		if isNotFoundError(err) {
 			ctxLogger.Warnf("postgres service - unable to find invitation with private id %v", privateID)
-			return nil, NewPostgresRecordNotFoundError()
+			return nil, storage.NewStorageRecordNotFoundError()
 		}
 
 		ctxLogger.Errorf("postgres service - unable to find invitation with private id %v due to %v", privateID, err)
-		return nil, NewPostgresOperationError()
+		return nil, storage.NewStorageOperationError()
 	}
 
 	domainInvitation := &domain.Invitation{
@@ -150,31 +199,65 @@ func (s *service) FindInvitationByPrivateID(privateID string) (*domain.Invitatio
 			MaximumGuestCount: invitation.MaximumGuestCount,
 			Notes:             invitation.Notes,
 			MobilePhoneNumber: invitation.MobilePhoneNumber,
+			Email:             invitation.Email,
+			Language:          invitation.Language,
 		},
-		ID:        invitation.ID,
-		PrivateID: invitation.PrivateID,
-		Status:    domain.RSVPStatus(invitation.Status),
-		UpdatedAt: invitation.UpdatedAt.Format(time.RFC3339),
+		ID:                       invitation.ID,
+		PrivateID:                invitation.PrivateID,
+		Status:                   domain.RSVPStatus(invitation.Status),
+		LinkExpiresAt:            formatOptionalTime(invitation.LinkExpiresAt),
+		LinkRevokedAt:            formatOptionalTime(invitation.LinkRevokedAt),
+		RequirePhoneConfirmation: invitation.RequirePhoneConfirmation,
+		UpdatedAt:                invitation.UpdatedAt.Format(time.RFC3339),
+		Version:                  invitation.Version,
 	}
 
 	return domainInvitation, nil
 }
 
-func (s *service) ListInvitations() ([]domain.Invitation, error) {
+func (s *service) ListInvitations(req *domain.InvitationListRequest) ([]domain.Invitation, int, error) {
 	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
 
+	filters := listQuery{conditions: []string{"invitations.deleted_at IS NULL"}}
+	if req.CategoryID != 0 {
+		filters.where("invitations.category_id=%v", req.CategoryID)
+	}
+	if req.Status != "" {
+		filters.where(invitationStatusExpression+"=%v", string(req.Status))
+	}
+	if req.UpdatedSince != nil {
+		filters.where("invitations.updated_at>=%v", req.UpdatedSince.UTC())
+	}
+
+	total, err := s.executor.SelectInt(fmt.Sprintf(`
+		SELECT COUNT(*)
+		FROM invitations
+		LEFT JOIN rsvps
+		ON invitations.private_id=rsvps.invitation_private_id AND rsvps.deleted_at IS NULL
+		%v
+	`, filters.whereClause()), filters.args...)
+	if err != nil {
+		ctxLogger.Errorf("postgres service - unable to count invitations due to %v", err)
+		return nil, 0, storage.NewStorageOperationError()
+	}
+
+	pageClause, args := filters.pageClause(req.ListRequest, invitationSortColumns, "invitations.id")
+
 	query := fmt.Sprintf(`
-		SELECT %v
+		SELECT %v, %v AS rsvp_status
 		FROM invitations
-		ORDER BY updated_at DESC
-	`, invitationColumns)
+		LEFT JOIN rsvps
+		ON invitations.private_id=rsvps.invitation_private_id AND rsvps.deleted_at IS NULL
+		%v
+		%v
+	`, prefixColumns("invitations", invitationColumns), invitationStatusExpression, filters.whereClause(), pageClause)
 
-	var invitations []invitation
+	var invitations []invitationListing
 
-	_, err := s.gorpDB.Select(&invitations, query)
+	_, err = s.executor.Select(&invitations, query, args...)
 	if err != nil {
-		ctxLogger.Errorf("postgres service - unable to retrieve all invitations due to %v", err)
-		return nil, NewPostgresOperationError()
+		ctxLogger.Errorf("postgres service - unable to retrieve invitations due to %v", err)
+		return nil, 0, storage.NewStorageOperationError()
 	}
 
 	domainInvitations := make([]domain.Invitation, len(invitations))
@@ -186,40 +269,86 @@ func (s *service) ListInvitations() ([]domain.Invitation, error) {
 				MaximumGuestCount: invitations[idx].MaximumGuestCount,
 				Notes:             invitations[idx].Notes,
 				MobilePhoneNumber: invitations[idx].MobilePhoneNumber,
+				Email:             invitations[idx].Email,
+				Language:          invitations[idx].Language,
 			},
-			ID:        invitations[idx].ID,
-			PrivateID: invitations[idx].PrivateID,
-			Status:    domain.RSVPStatus(invitations[idx].Status),
-			UpdatedAt: invitations[idx].UpdatedAt.Format(time.RFC3339),
+			ID:                       invitations[idx].ID,
+			PrivateID:                invitations[idx].PrivateID,
+			Status:                   domain.RSVPStatus(invitations[idx].RSVPStatus),
+			LinkExpiresAt:            formatOptionalTime(invitations[idx].LinkExpiresAt),
+			LinkRevokedAt:            formatOptionalTime(invitations[idx].LinkRevokedAt),
+			RequirePhoneConfirmation: invitations[idx].RequirePhoneConfirmation,
+			UpdatedAt:                invitations[idx].UpdatedAt.Format(time.RFC3339),
+			Version:                  invitations[idx].Version,
 		}
 	}
 
-	return domainInvitations, nil
+	return domainInvitations, int(total), nil
 }
 
 func (s *service) UpdateInvitation(domainInvitation *domain.Invitation) (*domain.Invitation, error) {
 	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
 
-	invitation := &invitation{
-		baseModel: baseModel{
-			ID: domainInvitation.ID,
-		},
-		CategoryID:        domainInvitation.CategoryID,
-		PrivateID:         domainInvitation.PrivateID,
-		Greeting:          domainInvitation.Greeting,
-		MaximumGuestCount: domainInvitation.MaximumGuestCount,
-		Status:            string(domainInvitation.Status),
-		Notes:             domainInvitation.Notes,
-		MobilePhoneNumber: domainInvitation.MobilePhoneNumber,
+	query := `
+		UPDATE invitations
+		SET category_id=$1, private_id=$2, greeting=$3, maximum_guest_count=$4, status=$5, notes=$6, mobile_phone_number=$7, email=$8, language=$9,
+			link_expires_at=$10, link_revoked_at=$11, require_phone_confirmation=$12, updated_at=$13, version=version+1
+		WHERE id=$14 AND version=$15 AND deleted_at IS NULL
+	`
+
+	linkExpiresAt, err := storage.ParseOptionalTimestamp(domainInvitation.LinkExpiresAt)
+	if err != nil {
+		ctxLogger.Errorf("postgres service - unable to update invitation %v due to %v", domainInvitation.ID, err)
+		return nil, storage.NewStorageOperationError()
 	}
 
-	_, err := s.gorpDB.Update(invitation)
+	linkRevokedAt, err := storage.ParseOptionalTimestamp(domainInvitation.LinkRevokedAt)
 	if err != nil {
-		ctxLogger.Errorf("postgres service - unable to update invitation %+v due to %v", invitation, err)
-		return nil, NewPostgresOperationError()
+		ctxLogger.Errorf("postgres service - unable to update invitation %v due to %v", domainInvitation.ID, err)
+		return nil, storage.NewStorageOperationError()
+	}
+
+	updatedAt := time.Now()
+
+	result, err := s.executor.Exec(query,
+		domainInvitation.CategoryID,
+		domainInvitation.PrivateID,
+		domainInvitation.Greeting,
+		domainInvitation.MaximumGuestCount,
+		string(domainInvitation.Status),
+		domainInvitation.Notes,
+		domainInvitation.MobilePhoneNumber,
+		domainInvitation.Email,
+		domainInvitation.Language,
+		linkExpiresAt,
+		linkRevokedAt,
+		domainInvitation.RequirePhoneConfirmation,
+		updatedAt,
+		domainInvitation.ID,
+		domainInvitation.Version,
+	)
+	if err != nil {
+		if isInvitationGreetingUniqueConstraintError(err) {
+			ctxLogger.Warnf("postgres service - unable to update invitation with a duplicate greeting %v", domainInvitation.Greeting)
+			return nil, storage.NewStorageInvitationGreetingUniqueConstraintError()
+		}
+		if isInvitationMobilePhoneNumberUniqueConstraintError(err) {
+			ctxLogger.Warnf("postgres service - unable to update invitation with a duplicate mobile phone number %v", domainInvitation.MobilePhoneNumber)
+			return nil, storage.NewStorageInvitationMobilePhoneNumberUniqueConstraintError()
+		}
+
+		ctxLogger.Errorf("postgres service - unable to update invitation %+v due to %v", domainInvitation, err)
+		return nil, storage.NewStorageOperationError()
 	}
 
-	domainInvitation.UpdatedAt = invitation.UpdatedAt.Format(time.RFC3339)
+	if rowsAffected, _ := result.RowsAffected(); rowsAffected == 0 {
+		err = s.versionConflictOrNotFound("invitations", domainInvitation.ID)
+		ctxLogger.Warnf("postgres service - unable to update invitation with id %v and version %v due to %v", domainInvitation.ID, domainInvitation.Version, err)
+		return nil, err
+	}
+
+	domainInvitation.UpdatedAt = updatedAt.Format(time.RFC3339)
+	domainInvitation.Version++
 
 	return domainInvitation, nil
 }
@@ -227,11 +356,181 @@ func (s *service) UpdateInvitation(domainInvitation *domain.Invitation) (*domain
 func (s *service) DeleteInvitation(invitation *domain.Invitation) error {
 	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
 
-	_, err := s.gorpDB.Delete(invitation)
+	result, err := s.executor.Exec("UPDATE invitations SET deleted_at=$1 WHERE id=$2 AND deleted_at IS NULL", time.Now(), invitation.ID)
 	if err != nil {
 		ctxLogger.Errorf("postgres service - unable to delete invitation with id %v due to %v", invitation.ID, err)
-		return NewPostgresOperationError()
+		return storage.NewStorageOperationError()
+	}
+
+	if rowsAffected, _ := result.RowsAffected(); rowsAffected == 0 {
+		ctxLogger.Warnf("postgres service - unable to delete invitation with id %v as it does not exist", invitation.ID)
+		return storage.NewStorageRecordNotFoundError()
 	}
 
 	return nil
 }
+
+func (s *service) FindDeletedInvitationByID(invitationID int64) (*domain.Invitation, error) {
+	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
+
+	query := fmt.Sprintf(`
+		SELECT %v
+		FROM invitations
+		WHERE id=$1 AND deleted_at IS NOT NULL
+	`, invitationColumns)
+
+	var invitation invitation
+
+	err := s.executor.SelectOne(&invitation, query, invitationID)
+	if err != nil {
+		if isNotFoundError(err) {
+			ctxLogger.Warnf("postgres service - unable to find deleted invitation with id %v", invitationID)
+			return nil, storage.NewStorageRecordNotFoundError()
+		}
+
+		ctxLogger.Errorf("postgres service - unable to find deleted invitation with id %v due to %v", invitationID, err)
+		return nil, storage.NewStorageOperationError()
+	}
+
+	domainInvitation := &domain.Invitation{
+		BaseInvitation: domain.BaseInvitation{
+			CategoryID:        invitation.CategoryID,
+			Greeting:          invitation.Greeting,
+			MaximumGuestCount: invitation.MaximumGuestCount,
+			Notes:             invitation.Notes,
+			MobilePhoneNumber: invitation.MobilePhoneNumber,
+			Email:             invitation.Email,
+			Language:          invitation.Language,
+		},
+		ID:                       invitation.ID,
+		PrivateID:                invitation.PrivateID,
+		Status:                   domain.RSVPStatus(invitation.Status),
+		LinkExpiresAt:            formatOptionalTime(invitation.LinkExpiresAt),
+		LinkRevokedAt:            formatOptionalTime(invitation.LinkRevokedAt),
+		RequirePhoneConfirmation: invitation.RequirePhoneConfirmation,
+		UpdatedAt:                invitation.UpdatedAt.Format(time.RFC3339),
+		Version:                  invitation.Version,
+		DeletedAt:                invitation.deletedAt(),
+	}
+
+	return domainInvitation, nil
+}
+
+func (s *service) ListDeletedInvitations() ([]domain.Invitation, error) {
+	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
+
+	query := fmt.Sprintf(`
+		SELECT %v
+		FROM invitations
+		WHERE deleted_at IS NOT NULL
+		ORDER BY deleted_at DESC, id DESC
+	`, invitationColumns)
+
+	var invitations []invitation
+
+	_, err := s.executor.Select(&invitations, query)
+	if err != nil {
+		ctxLogger.Errorf("postgres service - unable to retrieve deleted invitations due to %v", err)
+		return nil, storage.NewStorageOperationError()
+	}
+
+	domainInvitations := make([]domain.Invitation, len(invitations))
+	for idx := range invitations {
+		domainInvitations[idx] = domain.Invitation{
+			BaseInvitation: domain.BaseInvitation{
+				CategoryID:        invitations[idx].CategoryID,
+				Greeting:          invitations[idx].Greeting,
+				MaximumGuestCount: invitations[idx].MaximumGuestCount,
+				Notes:             invitations[idx].Notes,
+				MobilePhoneNumber: invitations[idx].MobilePhoneNumber,
+				Email:             invitations[idx].Email,
+				Language:          invitations[idx].Language,
+			},
+			ID:                       invitations[idx].ID,
+			PrivateID:                invitations[idx].PrivateID,
+			Status:                   domain.RSVPStatus(invitations[idx].Status),
+			LinkExpiresAt:            formatOptionalTime(invitations[idx].LinkExpiresAt),
+			LinkRevokedAt:            formatOptionalTime(invitations[idx].LinkRevokedAt),
+			RequirePhoneConfirmation: invitations[idx].RequirePhoneConfirmation,
+			UpdatedAt:                invitations[idx].UpdatedAt.Format(time.RFC3339),
+			Version:                  invitations[idx].Version,
+			DeletedAt:                invitations[idx].deletedAt(),
+		}
+	}
+
+	return domainInvitations, nil
+}
+
+func (s *service) RestoreInvitation(domainInvitation *domain.Invitation) (*domain.Invitation, error) {
+	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
+
+	result, err := s.executor.Exec("UPDATE invitations SET deleted_at=NULL WHERE id=$1 AND deleted_at IS NOT NULL", domainInvitation.ID)
+	if err != nil {
+		if isInvitationGreetingUniqueConstraintError(err) {
+			ctxLogger.Warnf("postgres service - unable to restore invitation with a duplicate greeting %v", domainInvitation.Greeting)
+			return nil, storage.NewStorageInvitationGreetingUniqueConstraintError()
+		}
+		if isInvitationMobilePhoneNumberUniqueConstraintError(err) {
+			ctxLogger.Warnf("postgres service - unable to restore invitation with a duplicate mobile phone number %v", domainInvitation.MobilePhoneNumber)
+			return nil, storage.NewStorageInvitationMobilePhoneNumberUniqueConstraintError()
+		}
+
+		ctxLogger.Errorf("postgres service - unable to restore invitation with id %v due to %v", domainInvitation.ID, err)
+		return nil, storage.NewStorageOperationError()
+	}
+
+	if rowsAffected, _ := result.RowsAffected(); rowsAffected == 0 {
+		ctxLogger.Warnf("postgres service - unable to restore invitation with id %v as it is not deleted", domainInvitation.ID)
+		return nil, storage.NewStorageRecordNotFoundError()
+	}
+
+	return s.FindInvitationByID(domainInvitation.ID)
+}
+
+func (s *service) PurgeInvitations(deletedBefore time.Time) (int, error) {
+	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
+
+	// Messages and retired links have no foreign key to the invitation so they are removed first, in the
+	// same transaction when purging from the trash
+	for _, table := range []string{"messages", "retired_invitation_links"} {
+		_, err := s.executor.Exec(fmt.Sprintf("DELETE FROM %v WHERE invitation_id IN (SELECT id FROM invitations WHERE deleted_at<$1)", table), deletedBefore)
+		if err != nil {
+			ctxLogger.Errorf("postgres service - unable to purge %v of invitations deleted before %v due to %v", table, deletedBefore, err)
+			return 0, storage.NewStorageOperationError()
+		}
+	}
+
+	result, err := s.executor.Exec("DELETE FROM invitations WHERE deleted_at<$1", deletedBefore)
+	if err != nil {
+		ctxLogger.Errorf("postgres service - unable to purge invitations deleted before %v due to %v", deletedBefore, err)
+		return 0, storage.NewStorageOperationError()
+	}
+
+	purged, _ := result.RowsAffected()
+
+	return int(purged), nil
+}
+
+func (s *service) InsertRetiredInvitationLink(invitationID int64, privateID string) error {
+	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
+
+	_, err := s.executor.Exec("INSERT INTO retired_invitation_links (private_id, invitation_id) VALUES ($1, $2)", privateID, invitationID)
+	if err != nil {
+		ctxLogger.Errorf("postgres service - unable to retire private id of invitation %v due to %v", invitationID, err)
+		return storage.NewStorageOperationError()
+	}
+
+	return nil
+}
+
+func (s *service) IsInvitationLinkRetired(privateID string) (bool, error) {
+	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
+
+	count, err := s.executor.SelectInt("SELECT COUNT(*) FROM retired_invitation_links WHERE private_id=$1", privateID)
+	if err != nil {
+		ctxLogger.Errorf("postgres service - unable to check if private id %v is retired due to %v", privateID, err)
+		return false, storage.NewStorageOperationError()
+	}
+
+	return count > 0, nil
+}
@@ -0,0 +1,253 @@
+package postgres
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rawfish-dev/rsvp-starter/server/domain"
+	"github.com/rawfish-dev/rsvp-starter/server/interfaces"
+	"github.com/rawfish-dev/rsvp-starter/server/services/storage"
+
+	"gopkg.in/gorp.v1"
+)
+
+type message struct {
+	ID                int64     `db:"id"`
+	InvitationID      int64     `db:"invitation_id"`
+	Kind              string    `db:"kind"`
+	Channel           string    `db:"channel"`
+	Recipient         string    `db:"recipient"`
+	Subject           string    `db:"subject"`
+	Body              string    `db:"body"`
+	Provider          string    `db:"provider"`
+	ProviderMessageID string    `db:"provider_message_id"`
+	Status            string    `db:"status"`
+	Error             string    `db:"error"`
+	CreatedAt         time.Time `db:"created_at"`
+	UpdatedAt         time.Time `db:"updated_at"`
+}
+
+var messageColumns = strings.Join([]string{
+	"id",
+	"invitation_id",
+	"kind",
+	"channel",
+	"recipient",
+	"subject",
+	"body",
+	"provider",
+	"provider_message_id",
+	"status",
+	"error",
+	"created_at",
+	"updated_at",
+}, ",")
+
+func (m *message) PreInsert(s gorp.SqlExecutor) error {
+	m.CreatedAt = time.Now()
+	m.UpdatedAt = m.CreatedAt
+	return nil
+}
+
+func (m *message) toDomain() domain.Message {
+	return domain.Message{
+		ID:                m.ID,
+		InvitationID:      m.InvitationID,
+		Kind:              domain.MessageKind(m.Kind),
+		Channel:           domain.MessageChannel(m.Channel),
+		Recipient:         m.Recipient,
+		Subject:           m.Subject,
+		Body:              m.Body,
+		Provider:          m.Provider,
+		ProviderMessageID: m.ProviderMessageID,
+		Status:            domain.MessageStatus(m.Status),
+		Error:             m.Error,
+		CreatedAt:         m.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:         m.UpdatedAt.Format(time.RFC3339),
+	}
+}
+
+func (s *service) InsertMessage(domainMessage *domain.Message) (*domain.Message, error) {
+	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
+
+	message := &message{
+		InvitationID:      domainMessage.InvitationID,
+		Kind:              string(domainMessage.Kind),
+		Channel:           string(domainMessage.Channel),
+		Recipient:         domainMessage.Recipient,
+		Subject:           domainMessage.Subject,
+		Body:              domainMessage.Body,
+		Provider:          domainMessage.Provider,
+		ProviderMessageID: domainMessage.ProviderMessageID,
+		Status:            string(domainMessage.Status),
+		Error:             domainMessage.Error,
+	}
+
+	err := s.executor.Insert(message)
+	if err != nil {
+		ctxLogger.Errorf("postgres service - unable to insert message for invitation %v due to %v", domainMessage.InvitationID, err)
+		return nil, storage.NewStorageOperationError()
+	}
+
+	newMessage := message.toDomain()
+
+	return &newMessage, nil
+}
+
+func (s *service) FindMessageByProviderMessageID(provider, providerMessageID string) (*domain.Message, error) {
+	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
+
+	query := fmt.Sprintf(`
+		SELECT %v
+		FROM messages
+		WHERE provider=$1 AND provider_message_id=$2
+		ORDER BY id DESC
+		LIMIT 1
+	`, messageColumns)
+
+	var message message
+
+	err := s.executor.SelectOne(&message, query, provider, providerMessageID)
+	if err != nil {
+		if isNotFoundError(err) {
+			ctxLogger.Warnf("postgres service - unable to find message %v from %v", providerMessageID, provider)
+			return nil, storage.NewStorageRecordNotFoundError()
+		}
+
+		ctxLogger.Errorf("postgres service - unable to find message %v from %v due to %v", providerMessageID, provider, err)
+		return nil, storage.NewStorageOperationError()
+	}
+
+	domainMessage := message.toDomain()
+
+	return &domainMessage, nil
+}
+
+func (s *service) ListMessagesByInvitationID(invitationID int64) ([]domain.Message, error) {
+	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
+
+	query := fmt.Sprintf(`
+		SELECT %v
+		FROM messages
+		WHERE invitation_id=$1
+		ORDER BY id
+	`, messageColumns)
+
+	var messages []message
+
+	_, err := s.executor.Select(&messages, query, invitationID)
+	if err != nil {
+		ctxLogger.Errorf("postgres service - unable to retrieve messages for invitation %v due to %v", invitationID, err)
+		return nil, storage.NewStorageOperationError()
+	}
+
+	domainMessages := make([]domain.Message, len(messages))
+	for idx := range messages {
+		domainMessages[idx] = messages[idx].toDomain()
+	}
+
+	return domainMessages, nil
+}
+
+func (s *service) ListMessagesByKind(kind domain.MessageKind) ([]domain.Message, error) {
+	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
+
+	query := fmt.Sprintf(`
+		SELECT %v
+		FROM messages
+		WHERE kind=$1
+		ORDER BY id
+	`, messageColumns)
+
+	var messages []message
+
+	_, err := s.executor.Select(&messages, query, string(kind))
+	if err != nil {
+		ctxLogger.Errorf("postgres service - unable to retrieve %v messages due to %v", kind, err)
+		return nil, storage.NewStorageOperationError()
+	}
+
+	domainMessages := make([]domain.Message, len(messages))
+	for idx := range messages {
+		domainMessages[idx] = messages[idx].toDomain()
+	}
+
+	return domainMessages, nil
+}
+
+func (s *service) ListMessagesByInvitationIDs(invitationIDs []int64) ([]domain.Message, error) {
+	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
+
+	if len(invitationIDs) == 0 {
+		return []domain.Message{}, nil
+	}
+
+	placeholders := make([]string, len(invitationIDs))
+	args := make([]interface{}, len(invitationIDs))
+	for idx, invitationID := range invitationIDs {
+		placeholders[idx] = fmt.Sprintf("$%v", idx+1)
+		args[idx] = invitationID
+	}
+
+	query := fmt.Sprintf(`
+		SELECT %v
+		FROM messages
+		WHERE invitation_id IN (%v)
+		ORDER BY id
+	`, messageColumns, strings.Join(placeholders, ","))
+
+	var messages []message
+
+	_, err := s.executor.Select(&messages, query, args...)
+	if err != nil {
+		ctxLogger.Errorf("postgres service - unable to retrieve messages for invitations %v due to %v", invitationIDs, err)
+		return nil, storage.NewStorageOperationError()
+	}
+
+	domainMessages := make([]domain.Message, len(messages))
+	for idx := range messages {
+		domainMessages[idx] = messages[idx].toDomain()
+	}
+
+	return domainMessages, nil
+}
+
+func (s *service) UpdateMessage(domainMessage *domain.Message) (*domain.Message, error) {
+	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
+
+	query := `
+		UPDATE messages
+		SET status=$1, error=$2, updated_at=$3
+		WHERE id=$4
+	`
+
+	result, err := s.executor.Exec(query, string(domainMessage.Status), domainMessage.Error, time.Now(), domainMessage.ID)
+	if err != nil {
+		ctxLogger.Errorf("postgres service - unable to update message with id %v due to %v", domainMessage.ID, err)
+		return nil, storage.NewStorageOperationError()
+	}
+
+	if rowsAffected, _ := result.RowsAffected(); rowsAffected == 0 {
+		ctxLogger.Warnf("postgres service - unable to update message with id %v as it does not exist", domainMessage.ID)
+		return nil, storage.NewStorageRecordNotFoundError()
+	}
+
+	query = fmt.Sprintf(`
+		SELECT %v
+		FROM messages
+		WHERE id=$1
+	`, messageColumns)
+
+	var message message
+
+	err = s.executor.SelectOne(&message, query, domainMessage.ID)
+	if err != nil {
+		ctxLogger.Errorf("postgres service - unable to retrieve message with id %v after updating it due to %v", domainMessage.ID, err)
+		return nil, storage.NewStorageOperationError()
+	}
+
+	updatedMessage := message.toDomain()
+
+	return &updatedMessage, nil
+}
@@ -16,13 +16,14 @@ import (
 	"gopkg.in/gorp.v1"
 )
 
-var _ interfaces.CategoryStorage = new(service)
-var _ interfaces.InvitationStorage = new(service)
-var _ interfaces.RSVPStorage = new(service)
+var _ interfaces.Storage = new(service)
 
 type service struct {
 	ctx    context.Context
 	gorpDB *gorp.DbMap
+
+	// executor is either gorpDB or the transaction opened by WithTx
+	executor gorp.SqlExecutor
 }
 
 var singletonService *service
@@ -44,10 +45,15 @@ func NewService(ctx context.Context, postgresConfig config.PostgresConfig) *serv
 		gorpDB.AddTableWithName(category{}, "categories").SetKeys(true, "ID")
 		gorpDB.AddTableWithName(invitation{}, "invitations").SetKeys(true, "ID")
 		gorpDB.AddTableWithName(rsvp{}, "rsvps").SetKeys(true, "ID")
+		gorpDB.AddTableWithName(auditEntry{}, "audit_entries").SetKeys(true, "ID")
+		gorpDB.AddTableWithName(user{}, "users").SetKeys(true, "ID")
+		gorpDB.AddTableWithName(apiKey{}, "api_keys").SetKeys(true, "ID")
+		gorpDB.AddTableWithName(message{}, "messages").SetKeys(true, "ID")
+		gorpDB.AddTableWithName(messageTemplate{}, "message_templates").SetKeys(true, "ID")
 
 		gorpDB.TypeConverter = dbTypeConverter{}
 
-		singletonService = &service{ctx, gorpDB}
+		singletonService = &service{ctx, gorpDB, gorpDB}
 	})
 
 	return singletonService
@@ -57,6 +63,11 @@ func (s *service) Close() error {
 	return s.gorpDB.Db.Close()
 }
 
+// DB exposes the underlying connection pool for tasks such as running migrations.
+func (s *service) DB() *sql.DB {
+	return s.gorpDB.Db
+}
+
 type dbTypeConverter struct{}
 
 func (c dbTypeConverter) ToDb(val interface{}) (interface{}, error) {
@@ -87,7 +98,7 @@ func (c dbTypeConverter) FromDb(target interface{}) (gorp.CustomScanner, bool) {
 			b := []byte(*s)
 			return json.Unmarshal(b, target)
 		}
-		return gorp.CustomScanner{new(string), target, binder}, true
+		return gorp.CustomScanner{Holder: new(string), Target: target, Binder: binder}, true
 	}
 
 	return gorp.CustomScanner{}, false
@@ -0,0 +1,41 @@
+package postgres_test
+
+import (
+	"os"
+
+	"github.com/rawfish-dev/rsvp-starter/server/config"
+	"github.com/rawfish-dev/rsvp-starter/server/interfaces"
+	"github.com/rawfish-dev/rsvp-starter/server/services/migration"
+	. "github.com/rawfish-dev/rsvp-starter/server/services/postgres"
+	"github.com/rawfish-dev/rsvp-starter/server/services/storage/storagetest"
+
+	"github.com/Sirupsen/logrus"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"golang.org/x/net/context"
+)
+
+// These specs need a disposable database, e.g.
+// POSTGRES_TEST_URL="postgres://localhost/rsvp_starter_test?sslmode=disable", as every table is emptied.
+var _ = Describe("Postgres", func() {
+
+	storagetest.StorageContract(func() interfaces.Storage {
+		postgresURL := os.Getenv("POSTGRES_TEST_URL")
+		if postgresURL == "" {
+			Skip("POSTGRES_TEST_URL not set")
+		}
+
+		ctxlogger := logrus.New()
+		ctx := context.Background()
+		ctx = context.WithValue(ctx, "logger", ctxlogger)
+
+		postgresService := NewService(ctx, config.PostgresConfig{URL: postgresURL, MaxIdle: 1, MaxConnections: 5})
+		_, err := migration.NewService(ctx, postgresService.DB(), Migrations).Up()
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = postgresService.DB().Exec("DELETE FROM message_templates; DELETE FROM messages; DELETE FROM retired_invitation_links; DELETE FROM api_keys; DELETE FROM users; DELETE FROM audit_entries; DELETE FROM rsvps; DELETE FROM invitations; DELETE FROM categories;")
+		Expect(err).ToNot(HaveOccurred())
+
+		return postgresService
+	})
+})
@@ -7,17 +7,21 @@ import (
 
 	"github.com/rawfish-dev/rsvp-starter/server/domain"
 	"github.com/rawfish-dev/rsvp-starter/server/interfaces"
+	"github.com/rawfish-dev/rsvp-starter/server/services/storage"
 )
 
 type rsvp struct {
 	baseModel
-	InvitationPrivateID string `db:"invitation_private_id"`
-	FullName            string `db:"full_name"`
-	Attending           bool   `db:"attending"`
-	GuestCount          int    `db:"guest_count"`
-	SpecialDiet         bool   `db:"special_diet"`
-	Remarks             string `db:"remarks"`
-	MobilePhoneNumber   string `db:"mobile_phone_number"`
+	InvitationPrivateID string     `db:"invitation_private_id"`
+	FullName            string     `db:"full_name"`
+	Attending           bool       `db:"attending"`
+	GuestCount          int        `db:"guest_count"`
+	SpecialDiet         bool       `db:"special_diet"`
+	Remarks             string     `db:"remarks"`
+	MobilePhoneNumber   string     `db:"mobile_phone_number"`
+	Email               string     `db:"email"`
+	Version             int64      `db:"version"` // gorp sets this to 1 on insert as it treats Version as a lock column
+	CheckedInAt         *time.Time `db:"checked_in_at"`
 }
 
 var (
@@ -30,7 +34,19 @@ var (
 		"special_diet",
 		"remarks",
 		"mobile_phone_number",
+		"email",
+		"created_at",
+		"updated_at",
+		"version",
+		"checked_in_at",
+		"deleted_at",
 	}, ",")
+
+	rsvpSortColumns = map[string]string{
+		domain.SortByRSVPFullName:   "LOWER(full_name)",
+		domain.SortByRSVPGuestCount: "guest_count",
+		domain.SortByRSVPUpdatedAt:  "updated_at",
+	}
 )
 
 func (s *service) InsertRSVP(req *domain.RSVPCreateRequest) (*domain.RSVP, error) {
@@ -44,17 +60,18 @@ func (s *service) InsertRSVP(req *domain.RSVPCreateRequest) (*domain.RSVP, error
 		SpecialDiet:         req.SpecialDiet,
 		Remarks:             req.Remarks,
 		MobilePhoneNumber:   req.MobilePhoneNumber,
+		Email:               req.Email,
 	}
 
-	err := s.gorpDB.Insert(rsvp)
+	err := s.executor.Insert(rsvp)
 	if err != nil {
 		if isRSVPPrivateIDUniqueConstraintError(err) {
 			ctxLogger.Warnf("postgres service - unable to insert rsvp with a duplicate private id %v", rsvp.InvitationPrivateID)
-			return nil, NewPostgresRSVPPrivateIDUniqueConstraintError()
+			return nil, storage.NewStorageRSVPPrivateIDUniqueConstraintError()
 		}
 
 		ctxLogger.Errorf("postgres service - unable to insert rsvp due to %v", err)
-		return nil, NewPostgresOperationError()
+		return nil, storage.NewStorageOperationError()
 	}
 
 	newRSVP := &domain.RSVP{
@@ -65,10 +82,13 @@ func (s *service) InsertRSVP(req *domain.RSVPCreateRequest) (*domain.RSVP, error
 			SpecialDiet:       rsvp.SpecialDiet,
 			Remarks:           rsvp.Remarks,
 			MobilePhoneNumber: rsvp.MobilePhoneNumber,
+			Email:             rsvp.Email,
 		},
 		ID:                  rsvp.ID,
 		InvitationPrivateID: rsvp.InvitationPrivateID,
 		UpdatedAt:           rsvp.UpdatedAt.Format(time.RFC3339),
+		Version:             rsvp.Version,
+		CheckedInAt:         formatOptionalTime(rsvp.CheckedInAt),
 		Completed:           true,
 	}
 
@@ -81,20 +101,20 @@ func (s *service) FindRSVPByID(rsvpID int64) (*domain.RSVP, error) {
 	query := fmt.Sprintf(`
 		SELECT %v
 		FROM rsvps
-		WHERE id=$1
+		WHERE id=$1 AND deleted_at IS NULL
 	`, rsvpColumns)
 
 	var rsvp rsvp
 
-	err := s.gorpDB.SelectOne(&rsvp, query, rsvpID)
+	err := s.executor.SelectOne(&rsvp, query, rsvpID)
 	if err != nil {
 		if isNotFoundError(err) {
 			ctxLogger.Warnf("postgres service - unable to find rsvp with id %v", rsvpID)
-			return nil, NewPostgresRecordNotFoundError()
+			return nil, storage.NewStorageRecordNotFoundError()
 		}
 
 		ctxLogger.Errorf("postgres service - unable to find rsvp with id %v due to %v", rsvpID, err)
-		return nil, NewPostgresOperationError()
+		return nil, storage.NewStorageOperationError()
 	}
 
 	domainRSVP := &domain.RSVP{
@@ -105,10 +125,13 @@ func (s *service) FindRSVPByID(rsvpID int64) (*domain.RSVP, error) {
 			SpecialDiet:       rsvp.SpecialDiet,
 			Remarks:           rsvp.Remarks,
 			MobilePhoneNumber: rsvp.MobilePhoneNumber,
+			Email:             rsvp.Email,
 		},
 		ID:                  rsvp.ID,
 		InvitationPrivateID: rsvp.InvitationPrivateID,
 		UpdatedAt:           rsvp.UpdatedAt.Format(time.RFC3339),
+		Version:             rsvp.Version,
+		CheckedInAt:         formatOptionalTime(rsvp.CheckedInAt),
 		Completed:           true,
 	}
 
@@ -121,20 +144,20 @@ func (s *service) FindRSVPByInvitationPrivateID(invitationPrivateID string) (*do
 	query := fmt.Sprintf(`
 		SELECT %v
 		FROM rsvps
-		WHERE invitation_private_id=$1
+		WHERE invitation_private_id=$1 AND deleted_at IS NULL
 	`, rsvpColumns)
 
 	var rsvp rsvp
 
-	err := s.gorpDB.SelectOne(&rsvp, query, invitationPrivateID)
+	err := s.executor.SelectOne(&rsvp, query, invitationPrivateID)
 	if err != nil {
 		if isNotFoundError(err) {
 			ctxLogger.Warnf("postgres service - unable to find rsvp with invitation private id %v", invitationPrivateID)
-			return nil, NewPostgresRecordNotFoundError()
+			return nil, storage.NewStorageRecordNotFoundError()
 		}
 
 		ctxLogger.Errorf("postgres service - unable to find rsvp with invitation private id %v due to %v", invitationPrivateID, err)
-		return nil, NewPostgresOperationError()
+		return nil, storage.NewStorageOperationError()
 	}
 
 	domainRSVP := &domain.RSVP{
@@ -145,31 +168,57 @@ func (s *service) FindRSVPByInvitationPrivateID(invitationPrivateID string) (*do
 			SpecialDiet:       rsvp.SpecialDiet,
 			Remarks:           rsvp.Remarks,
 			MobilePhoneNumber: rsvp.MobilePhoneNumber,
+			Email:             rsvp.Email,
 		},
-		// ID:                  rsvp.ID, omit since no operations can be performed against it
+		// ID and Version are omitted since no operations can be performed against it
 		InvitationPrivateID: rsvp.InvitationPrivateID,
 		UpdatedAt:           rsvp.UpdatedAt.Format(time.RFC3339),
 		Completed:           true,
+		CheckedInAt:         formatOptionalTime(rsvp.CheckedInAt),
 	}
 
 	return domainRSVP, nil
 }
 
-func (s *service) ListRSVPs() ([]domain.RSVP, error) {
+func (s *service) ListRSVPs(req *domain.RSVPListRequest) ([]domain.RSVP, int, error) {
 	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
 
+	filters := listQuery{conditions: []string{"deleted_at IS NULL"}}
+	if req.Attending != nil {
+		filters.where("attending=%v", *req.Attending)
+	}
+	if req.SpecialDiet != nil {
+		filters.where("special_diet=%v", *req.SpecialDiet)
+	}
+	if req.UpdatedSince != nil {
+		filters.where("updated_at>=%v", req.UpdatedSince.UTC())
+	}
+
+	total, err := s.executor.SelectInt(fmt.Sprintf(`
+		SELECT COUNT(*)
+		FROM rsvps
+		%v
+	`, filters.whereClause()), filters.args...)
+	if err != nil {
+		ctxLogger.Errorf("postgres service - unable to count rsvps due to %v", err)
+		return nil, 0, storage.NewStorageOperationError()
+	}
+
+	pageClause, args := filters.pageClause(req.ListRequest, rsvpSortColumns, "id")
+
 	query := fmt.Sprintf(`
 		SELECT %v
 		FROM rsvps
-		ORDER BY updated_at DESC
-	`, rsvpColumns)
+		%v
+		%v
+	`, rsvpColumns, filters.whereClause(), pageClause)
 
 	var rsvps []rsvp
 
-	_, err := s.gorpDB.Select(&rsvps, query)
+	_, err = s.executor.Select(&rsvps, query, args...)
 	if err != nil {
-		ctxLogger.Errorf("postgres service - unable to retrieve all rsvps due to %v", err)
-		return nil, NewPostgresOperationError()
+		ctxLogger.Errorf("postgres service - unable to retrieve rsvps due to %v", err)
+		return nil, 0, storage.NewStorageOperationError()
 	}
 
 	domainRSVPs := make([]domain.RSVP, len(rsvps))
@@ -182,40 +231,62 @@ func (s *service) ListRSVPs() ([]domain.RSVP, error) {
 				SpecialDiet:       rsvps[idx].SpecialDiet,
 				Remarks:           rsvps[idx].Remarks,
 				MobilePhoneNumber: rsvps[idx].MobilePhoneNumber,
+				Email:             rsvps[idx].Email,
 			},
 			ID:                  rsvps[idx].ID,
 			InvitationPrivateID: rsvps[idx].InvitationPrivateID,
 			UpdatedAt:           rsvps[idx].UpdatedAt.Format(time.RFC3339),
+			Version:             rsvps[idx].Version,
+			CheckedInAt:         formatOptionalTime(rsvps[idx].CheckedInAt),
 			Completed:           true,
 		}
 	}
 
-	return domainRSVPs, nil
+	return domainRSVPs, int(total), nil
 }
 
 func (s *service) UpdateRSVP(domainRSVP *domain.RSVP) (*domain.RSVP, error) {
 	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
 
-	rsvp := &rsvp{
-		baseModel: baseModel{
-			ID: domainRSVP.ID,
-		},
-		InvitationPrivateID: domainRSVP.InvitationPrivateID,
-		FullName:            domainRSVP.FullName,
-		Attending:           domainRSVP.Attending,
-		GuestCount:          domainRSVP.GuestCount,
-		SpecialDiet:         domainRSVP.SpecialDiet,
-		Remarks:             domainRSVP.Remarks,
-		MobilePhoneNumber:   domainRSVP.MobilePhoneNumber,
+	query := `
+		UPDATE rsvps
+		SET invitation_private_id=$1, full_name=$2, attending=$3, guest_count=$4, special_diet=$5, remarks=$6, mobile_phone_number=$7, email=$8, updated_at=$9, version=version+1
+		WHERE id=$10 AND version=$11 AND deleted_at IS NULL
+	`
+
+	updatedAt := time.Now()
+
+	result, err := s.executor.Exec(query,
+		domainRSVP.InvitationPrivateID,
+		domainRSVP.FullName,
+		domainRSVP.Attending,
+		domainRSVP.GuestCount,
+		domainRSVP.SpecialDiet,
+		domainRSVP.Remarks,
+		domainRSVP.MobilePhoneNumber,
+		domainRSVP.Email,
+		updatedAt,
+		domainRSVP.ID,
+		domainRSVP.Version,
+	)
+	if err != nil {
+		if isRSVPPrivateIDUniqueConstraintError(err) {
+			ctxLogger.Warnf("postgres service - unable to update rsvp with a duplicate private id %v", domainRSVP.InvitationPrivateID)
+			return nil, storage.NewStorageRSVPPrivateIDUniqueConstraintError()
+		}
+
+		ctxLogger.Errorf("postgres service - unable to update rsvp %+v due to %v", domainRSVP, err)
+		return nil, storage.NewStorageOperationError()
 	}
 
-	_, err := s.gorpDB.Update(rsvp)
-	if err != nil {
-		ctxLogger.Errorf("postgres service - unable to update rsvp %+v due to %v", rsvp, err)
-		return nil, NewPostgresOperationError()
+	if rowsAffected, _ := result.RowsAffected(); rowsAffected == 0 {
+		err = s.versionConflictOrNotFound("rsvps", domainRSVP.ID)
+		ctxLogger.Warnf("postgres service - unable to update rsvp with id %v and version %v due to %v", domainRSVP.ID, domainRSVP.Version, err)
+		return nil, err
 	}
 
-	domainRSVP.UpdatedAt = rsvp.UpdatedAt.Format(time.RFC3339)
+	domainRSVP.UpdatedAt = updatedAt.Format(time.RFC3339)
+	domainRSVP.Version++
 	domainRSVP.Completed = true
 
 	return domainRSVP, nil
@@ -224,11 +295,156 @@ func (s *service) UpdateRSVP(domainRSVP *domain.RSVP) (*domain.RSVP, error) {
 func (s *service) DeleteRSVP(rsvp *domain.RSVP) error {
 	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
 
-	_, err := s.gorpDB.Delete(rsvp)
+	result, err := s.executor.Exec("UPDATE rsvps SET deleted_at=$1 WHERE id=$2 AND deleted_at IS NULL", time.Now(), rsvp.ID)
 	if err != nil {
 		ctxLogger.Errorf("postgres service - unable to delete rsvp with id %v due to %v", rsvp.ID, err)
-		return NewPostgresOperationError()
+		return storage.NewStorageOperationError()
+	}
+
+	if rowsAffected, _ := result.RowsAffected(); rowsAffected == 0 {
+		ctxLogger.Warnf("postgres service - unable to delete rsvp with id %v as it does not exist", rsvp.ID)
+		return storage.NewStorageRecordNotFoundError()
 	}
 
 	return nil
 }
+
+func (s *service) FindDeletedRSVPByID(rsvpID int64) (*domain.RSVP, error) {
+	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
+
+	query := fmt.Sprintf(`
+		SELECT %v
+		FROM rsvps
+		WHERE id=$1 AND deleted_at IS NOT NULL
+	`, rsvpColumns)
+
+	var rsvp rsvp
+
+	err := s.executor.SelectOne(&rsvp, query, rsvpID)
+	if err != nil {
+		if isNotFoundError(err) {
+			ctxLogger.Warnf("postgres service - unable to find deleted rsvp with id %v", rsvpID)
+			return nil, storage.NewStorageRecordNotFoundError()
+		}
+
+		ctxLogger.Errorf("postgres service - unable to find deleted rsvp with id %v due to %v", rsvpID, err)
+		return nil, storage.NewStorageOperationError()
+	}
+
+	domainRSVP := &domain.RSVP{
+		BaseRSVP: domain.BaseRSVP{
+			FullName:          rsvp.FullName,
+			Attending:         rsvp.Attending,
+			GuestCount:        rsvp.GuestCount,
+			SpecialDiet:       rsvp.SpecialDiet,
+			Remarks:           rsvp.Remarks,
+			MobilePhoneNumber: rsvp.MobilePhoneNumber,
+			Email:             rsvp.Email,
+		},
+		ID:                  rsvp.ID,
+		InvitationPrivateID: rsvp.InvitationPrivateID,
+		UpdatedAt:           rsvp.UpdatedAt.Format(time.RFC3339),
+		Version:             rsvp.Version,
+		CheckedInAt:         formatOptionalTime(rsvp.CheckedInAt),
+		Completed:           true,
+		DeletedAt:           rsvp.deletedAt(),
+	}
+
+	return domainRSVP, nil
+}
+
+func (s *service) ListDeletedRSVPs() ([]domain.RSVP, error) {
+	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
+
+	query := fmt.Sprintf(`
+		SELECT %v
+		FROM rsvps
+		WHERE deleted_at IS NOT NULL
+		ORDER BY deleted_at DESC, id DESC
+	`, rsvpColumns)
+
+	var rsvps []rsvp
+
+	_, err := s.executor.Select(&rsvps, query)
+	if err != nil {
+		ctxLogger.Errorf("postgres service - unable to retrieve deleted rsvps due to %v", err)
+		return nil, storage.NewStorageOperationError()
+	}
+
+	domainRSVPs := make([]domain.RSVP, len(rsvps))
+	for idx := range rsvps {
+		domainRSVPs[idx] = domain.RSVP{
+			BaseRSVP: domain.BaseRSVP{
+				FullName:          rsvps[idx].FullName,
+				Attending:         rsvps[idx].Attending,
+				GuestCount:        rsvps[idx].GuestCount,
+				SpecialDiet:       rsvps[idx].SpecialDiet,
+				Remarks:           rsvps[idx].Remarks,
+				MobilePhoneNumber: rsvps[idx].MobilePhoneNumber,
+				Email:             rsvps[idx].Email,
+			},
+			ID:                  rsvps[idx].ID,
+			InvitationPrivateID: rsvps[idx].InvitationPrivateID,
+			UpdatedAt:           rsvps[idx].UpdatedAt.Format(time.RFC3339),
+			Version:             rsvps[idx].Version,
+			CheckedInAt:         formatOptionalTime(rsvps[idx].CheckedInAt),
+			Completed:           true,
+			DeletedAt:           rsvps[idx].deletedAt(),
+		}
+	}
+
+	return domainRSVPs, nil
+}
+
+func (s *service) RestoreRSVP(domainRSVP *domain.RSVP) (*domain.RSVP, error) {
+	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
+
+	result, err := s.executor.Exec("UPDATE rsvps SET deleted_at=NULL WHERE id=$1 AND deleted_at IS NOT NULL", domainRSVP.ID)
+	if err != nil {
+		if isRSVPPrivateIDUniqueConstraintError(err) {
+			ctxLogger.Warnf("postgres service - unable to restore rsvp with a duplicate private id %v", domainRSVP.InvitationPrivateID)
+			return nil, storage.NewStorageRSVPPrivateIDUniqueConstraintError()
+		}
+
+		ctxLogger.Errorf("postgres service - unable to restore rsvp with id %v due to %v", domainRSVP.ID, err)
+		return nil, storage.NewStorageOperationError()
+	}
+
+	if rowsAffected, _ := result.RowsAffected(); rowsAffected == 0 {
+		ctxLogger.Warnf("postgres service - unable to restore rsvp with id %v as it is not deleted", domainRSVP.ID)
+		return nil, storage.NewStorageRecordNotFoundError()
+	}
+
+	return s.FindRSVPByID(domainRSVP.ID)
+}
+
+func (s *service) CheckInRSVP(domainRSVP *domain.RSVP) (*domain.RSVP, error) {
+	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
+
+	result, err := s.executor.Exec("UPDATE rsvps SET checked_in_at=$1 WHERE id=$2 AND deleted_at IS NULL", time.Now(), domainRSVP.ID)
+	if err != nil {
+		ctxLogger.Errorf("postgres service - unable to check in rsvp with id %v due to %v", domainRSVP.ID, err)
+		return nil, storage.NewStorageOperationError()
+	}
+
+	if rowsAffected, _ := result.RowsAffected(); rowsAffected == 0 {
+		ctxLogger.Warnf("postgres service - unable to check in rsvp with id %v as it does not exist", domainRSVP.ID)
+		return nil, storage.NewStorageRecordNotFoundError()
+	}
+
+	return s.FindRSVPByID(domainRSVP.ID)
+}
+
+func (s *service) PurgeRSVPs(deletedBefore time.Time) (int, error) {
+	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
+
+	result, err := s.executor.Exec("DELETE FROM rsvps WHERE deleted_at<$1", deletedBefore)
+	if err != nil {
+		ctxLogger.Errorf("postgres service - unable to purge rsvps deleted before %v due to %v", deletedBefore, err)
+		return 0, storage.NewStorageOperationError()
+	}
+
+	purged, _ := result.RowsAffected()
+
+	return int(purged), nil
+}
@@ -0,0 +1,178 @@
+package postgres
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rawfish-dev/rsvp-starter/server/domain"
+	"github.com/rawfish-dev/rsvp-starter/server/interfaces"
+	"github.com/rawfish-dev/rsvp-starter/server/services/storage"
+)
+
+// Search relies on the indexes added in 20261017100000. Full-text matching handles whole words,
+// trigrams handle partial names and phone numbers are compared on their digits alone. Both queries
+// take $1 as the query, $2 as the phone fragment, $3 as a LIKE pattern and $4 as the limit.
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+type invitationSearchHit struct {
+	invitationListing
+	Rank float64 `db:"search_rank"`
+}
+
+type rsvpSearchHit struct {
+	rsvp
+	Rank float64 `db:"search_rank"`
+}
+
+// searchColumns names the columns a table is searched on. vector must match the expression indexed
+// by the migration for the full-text index to be used.
+type searchColumns struct {
+	vector string
+	name   string
+	notes  string
+	phone  string
+}
+
+var (
+	invitationSearchColumns = searchColumns{
+		vector: `to_tsvector('simple', invitations.greeting || ' ' || COALESCE(invitations.notes, ''))`,
+		name:   "invitations.greeting",
+		notes:  "invitations.notes",
+		phone:  `regexp_replace(invitations.mobile_phone_number, '\D', '', 'g')`,
+	}
+
+	rsvpSearchColumns = searchColumns{
+		vector: `to_tsvector('simple', COALESCE(full_name, '') || ' ' || COALESCE(remarks, ''))`,
+		name:   "full_name",
+		notes:  "remarks",
+		phone:  `regexp_replace(mobile_phone_number, '\D', '', 'g')`,
+	}
+)
+
+// rank weighs names above phone numbers and notes, then adds the full-text rank so whole word
+// matches win over partial ones.
+func (s searchColumns) rank() string {
+	return fmt.Sprintf(`(GREATEST(
+		word_similarity($1, COALESCE(%[1]v, '')),
+		0.5 * word_similarity($1, COALESCE(%[2]v, '')),
+		CASE
+			WHEN $2 = '' THEN 0
+			WHEN %[3]v = $2 THEN 0.8
+			WHEN %[3]v LIKE '%%' || $2 THEN 0.6
+			WHEN %[3]v LIKE '%%' || $2 || '%%' THEN 0.4
+			ELSE 0
+		END
+	) + ts_rank(%[4]v, plainto_tsquery('simple', $1)))::float8`, s.name, s.notes, s.phone, s.vector)
+}
+
+func (s searchColumns) condition() string {
+	return fmt.Sprintf(`(%[1]v @@ plainto_tsquery('simple', $1)
+		OR %[2]v ILIKE $3
+		OR %[3]v ILIKE $3
+		OR ($2 <> '' AND %[4]v LIKE '%%' || $2 || '%%'))`, s.vector, s.name, s.notes, s.phone)
+}
+
+func (s *service) Search(req *domain.SearchRequest) ([]domain.SearchResult, error) {
+	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
+
+	query := strings.TrimSpace(req.Query)
+	args := []interface{}{query, req.PhoneFragment(), "%" + likeEscaper.Replace(query) + "%", req.Limit}
+
+	invitationQuery := fmt.Sprintf(`
+		SELECT %v, %v AS rsvp_status, %v AS search_rank
+		FROM invitations
+		LEFT JOIN rsvps
+		ON invitations.private_id=rsvps.invitation_private_id AND rsvps.deleted_at IS NULL
+		WHERE invitations.deleted_at IS NULL AND %v
+		ORDER BY search_rank DESC, invitations.id
+		LIMIT $4
+	`,
+		prefixColumns("invitations", invitationColumns),
+		invitationStatusExpression,
+		invitationSearchColumns.rank(),
+		invitationSearchColumns.condition(),
+	)
+
+	var invitations []invitationSearchHit
+
+	_, err := s.executor.Select(&invitations, invitationQuery, args...)
+	if err != nil {
+		ctxLogger.Errorf("postgres service - unable to search invitations due to %v", err)
+		return nil, storage.NewStorageOperationError()
+	}
+
+	rsvpQuery := fmt.Sprintf(`
+		SELECT %v, %v AS search_rank
+		FROM rsvps
+		WHERE deleted_at IS NULL AND %v
+		ORDER BY search_rank DESC, id
+		LIMIT $4
+	`,
+		rsvpColumns,
+		rsvpSearchColumns.rank(),
+		rsvpSearchColumns.condition(),
+	)
+
+	var rsvps []rsvpSearchHit
+
+	_, err = s.executor.Select(&rsvps, rsvpQuery, args...)
+	if err != nil {
+		ctxLogger.Errorf("postgres service - unable to search rsvps due to %v", err)
+		return nil, storage.NewStorageOperationError()
+	}
+
+	results := make([]domain.SearchResult, 0, len(invitations)+len(rsvps))
+
+	for idx := range invitations {
+		results = append(results, domain.SearchResult{
+			Type: domain.InvitationSearchResult,
+			Rank: invitations[idx].Rank,
+			Invitation: &domain.Invitation{
+				BaseInvitation: domain.BaseInvitation{
+					CategoryID:        invitations[idx].CategoryID,
+					Greeting:          invitations[idx].Greeting,
+					MaximumGuestCount: invitations[idx].MaximumGuestCount,
+					Notes:             invitations[idx].Notes,
+					MobilePhoneNumber: invitations[idx].MobilePhoneNumber,
+					Email:             invitations[idx].Email,
+					Language:          invitations[idx].Language,
+				},
+				ID:                       invitations[idx].ID,
+				PrivateID:                invitations[idx].PrivateID,
+				Status:                   domain.RSVPStatus(invitations[idx].RSVPStatus),
+				LinkExpiresAt:            formatOptionalTime(invitations[idx].LinkExpiresAt),
+				LinkRevokedAt:            formatOptionalTime(invitations[idx].LinkRevokedAt),
+				RequirePhoneConfirmation: invitations[idx].RequirePhoneConfirmation,
+				UpdatedAt:                invitations[idx].UpdatedAt.Format(time.RFC3339),
+				Version:                  invitations[idx].Version,
+			},
+		})
+	}
+
+	for idx := range rsvps {
+		results = append(results, domain.SearchResult{
+			Type: domain.RSVPSearchResult,
+			Rank: rsvps[idx].Rank,
+			RSVP: &domain.RSVP{
+				BaseRSVP: domain.BaseRSVP{
+					FullName:          rsvps[idx].FullName,
+					Attending:         rsvps[idx].Attending,
+					GuestCount:        rsvps[idx].GuestCount,
+					SpecialDiet:       rsvps[idx].SpecialDiet,
+					Remarks:           rsvps[idx].Remarks,
+					MobilePhoneNumber: rsvps[idx].MobilePhoneNumber,
+					Email:             rsvps[idx].Email,
+				},
+				ID:                  rsvps[idx].ID,
+				InvitationPrivateID: rsvps[idx].InvitationPrivateID,
+				UpdatedAt:           rsvps[idx].UpdatedAt.Format(time.RFC3339),
+				Version:             rsvps[idx].Version,
+				Completed:           true,
+			},
+		})
+	}
+
+	return storage.SortSearchResults(results, req.Limit), nil
+}
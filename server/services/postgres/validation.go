@@ -2,7 +2,10 @@ package postgres
 
 import (
 	"database/sql"
+	"fmt"
 	"strings"
+
+	"github.com/rawfish-dev/rsvp-starter/server/services/storage"
 )
 
 func isNotFoundError(err error) bool {
@@ -24,3 +27,27 @@ func isInvitationMobilePhoneNumberUniqueConstraintError(err error) bool {
 func isRSVPPrivateIDUniqueConstraintError(err error) bool {
 	return strings.Contains(err.Error(), `duplicate key value violates unique constraint "unique_invitation_private_id"`)
 }
+
+func isUserUsernameUniqueConstraintError(err error) bool {
+	return strings.Contains(err.Error(), `duplicate key value violates unique constraint "unique_username"`)
+}
+
+func isMessageTemplateUniqueConstraintError(err error) bool {
+	return strings.Contains(err.Error(), `duplicate key value violates unique constraint "unique_message_template"`)
+}
+
+// versionConflictOrNotFound tells apart an update that matched no rows because the record is gone
+// or in the trash from one that matched no rows because the record was changed since the given
+// version was read.
+func (s *service) versionConflictOrNotFound(table string, id int64) error {
+	count, err := s.executor.SelectInt(fmt.Sprintf("SELECT COUNT(*) FROM %v WHERE id=$1 AND deleted_at IS NULL", table), id)
+	if err != nil {
+		return storage.NewStorageOperationError()
+	}
+
+	if count == 0 {
+		return storage.NewStorageRecordNotFoundError()
+	}
+
+	return storage.NewStorageVersionConflictError()
+}
@@ -0,0 +1,97 @@
+package rsvp
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+
+	"github.com/rawfish-dev/rsvp-starter/server/domain"
+	"github.com/rawfish-dev/rsvp-starter/server/interfaces"
+)
+
+const confirmationEmailSubject = "We have received your RSVP"
+
+var confirmationEmailHTML = template.Must(template.New("confirmation").Parse(`<p>Hi {{.FullName}},</p>
+<p>Thank you for letting us know. This is the reply we have received:</p>
+<ul>
+{{- if .Attending}}
+<li>Attending: yes</li>
+<li>Guests: {{.GuestCount}}</li>
+<li>Special diet: {{if .SpecialDiet}}yes{{else}}no{{end}}</li>
+{{- else}}
+<li>Attending: no</li>
+{{- end}}
+</ul>
+`))
+
+// sendConfirmation emails the guest a summary of their reply. The rsvp has already been saved, so
+// failures are only logged and the message is recorded against the invitation for the control panel.
+func (s *service) sendConfirmation(rsvp *domain.RSVP) {
+	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
+
+	if rsvp.Email == "" {
+		return
+	}
+
+	invitation, err := s.rsvpStorage.FindInvitationByPrivateID(rsvp.InvitationPrivateID)
+	if err != nil {
+		ctxLogger.Errorf("rsvp service - unable to find invitation of rsvp %v for its confirmation due to %v", rsvp.ID, err)
+		return
+	}
+
+	email := &domain.Email{
+		To:       rsvp.Email,
+		Subject:  confirmationEmailSubject,
+		TextBody: confirmationEmailText(rsvp),
+	}
+
+	message := &domain.Message{
+		InvitationID: invitation.ID,
+		Kind:         domain.ConfirmationMessage,
+		Channel:      domain.EmailChannel,
+		Recipient:    email.To,
+		Subject:      email.Subject,
+		Body:         email.TextBody,
+		Provider:     s.emailSender.Name(),
+		Status:       domain.MessageQueued,
+	}
+
+	var html bytes.Buffer
+	err = confirmationEmailHTML.Execute(&html, rsvp)
+	if err != nil {
+		ctxLogger.Errorf("rsvp service - unable to build confirmation for rsvp %v due to %v", rsvp.ID, err)
+		return
+	}
+	email.HTMLBody = html.String()
+
+	providerMessageID, err := s.emailSender.SendEmail(email)
+	if err != nil {
+		ctxLogger.Warnf("rsvp service - unable to email confirmation for rsvp %v due to %v", rsvp.ID, err)
+		message.Status = domain.MessageFailed
+		message.Error = err.Error()
+	}
+	message.ProviderMessageID = providerMessageID
+
+	_, err = s.rsvpStorage.InsertMessage(message)
+	if err != nil {
+		ctxLogger.Errorf("rsvp service - unable to record confirmation for rsvp %v due to %v", rsvp.ID, err)
+	}
+}
+
+func confirmationEmailText(rsvp *domain.RSVP) string {
+	var text bytes.Buffer
+	fmt.Fprintf(&text, "Hi %v,\n\nThank you for letting us know. This is the reply we have received:\n\n", rsvp.FullName)
+
+	if !rsvp.Attending {
+		fmt.Fprintf(&text, "Attending: no\n")
+		return text.String()
+	}
+
+	specialDiet := "no"
+	if rsvp.SpecialDiet {
+		specialDiet = "yes"
+	}
+	fmt.Fprintf(&text, "Attending: yes\nGuests: %v\nSpecial diet: %v\n", rsvp.GuestCount, specialDiet)
+
+	return text.String()
+}
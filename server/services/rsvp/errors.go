@@ -1,6 +1,11 @@
 package rsvp
 
+import (
+	"github.com/rawfish-dev/rsvp-starter/server/domain"
+)
+
 var _ error = new(RSVPNotFoundError)
+var _ error = new(RSVPVersionConflictError)
 
 type RSVPNotFoundError struct {
 }
@@ -12,3 +17,16 @@ func NewRSVPNotFoundError() error {
 func (r RSVPNotFoundError) Error() string {
 	return "rsvp not found"
 }
+
+// RSVPVersionConflictError carries the latest rsvp so the caller can show what changed.
+type RSVPVersionConflictError struct {
+	Current *domain.RSVP
+}
+
+func NewRSVPVersionConflictError(current *domain.RSVP) error {
+	return RSVPVersionConflictError{current}
+}
+
+func (r RSVPVersionConflictError) Error() string {
+	return "rsvp has been modified since it was retrieved"
+}
@@ -5,8 +5,9 @@ import (
 
 	"github.com/rawfish-dev/rsvp-starter/server/domain"
 	"github.com/rawfish-dev/rsvp-starter/server/interfaces"
+	"github.com/rawfish-dev/rsvp-starter/server/services/audit"
 	serviceErrors "github.com/rawfish-dev/rsvp-starter/server/services/errors"
-	"github.com/rawfish-dev/rsvp-starter/server/services/postgres"
+	"github.com/rawfish-dev/rsvp-starter/server/services/storage"
 	"github.com/rawfish-dev/rsvp-starter/server/utils"
 
 	"golang.org/x/net/context"
@@ -20,17 +21,19 @@ const (
 	NoteMaxLength              = 500
 	MobilePhoneNumberMinLength = 8
 	MobilePhoneNumberMaxLength = 20
+	EmailMaxLength             = 254
 )
 
 var _ interfaces.RSVPServiceProvider = new(service)
 
 type service struct {
 	ctx         context.Context
-	rsvpStorage interfaces.RSVPStorage
+	emailSender interfaces.EmailSender
+	rsvpStorage interfaces.Storage
 }
 
-func NewService(ctx context.Context, rsvpStorage interfaces.RSVPStorage) *service {
-	return &service{ctx, rsvpStorage}
+func NewService(ctx context.Context, emailSender interfaces.EmailSender, rsvpStorage interfaces.Storage) *service {
+	return &service{ctx, emailSender, rsvpStorage}
 }
 
 func (s *service) CreateRSVP(req *domain.RSVPCreateRequest) (*domain.RSVP, error) {
@@ -39,31 +42,69 @@ func (s *service) CreateRSVP(req *domain.RSVPCreateRequest) (*domain.RSVP, error
 		return nil, serviceErrors.NewValidationError(errorMessages)
 	}
 
-	newRSVP, err := s.rsvpStorage.InsertRSVP(req)
-	if err != nil {
-		errorMessage := []string{err.Error()}
+	var newRSVP *domain.RSVP
 
-		switch err.(type) {
-		case postgres.PostgresRSVPPrivateIDUniqueConstraintError:
-			return nil, serviceErrors.NewValidationError(errorMessage)
+	err := s.rsvpStorage.WithTx(func(tx interfaces.Storage) error {
+		// The invitation must still exist when the rsvp is written
+		_, err := tx.FindInvitationByPrivateID(req.InvitationPrivateID)
+		if err != nil {
+			switch err.(type) {
+			case storage.StorageRecordNotFoundError:
+				return serviceErrors.NewValidationError([]string{"invitation does not exist"})
+			}
+
+			return serviceErrors.NewGeneralServiceError()
 		}
 
-		return nil, serviceErrors.NewGeneralServiceError()
+		newRSVP, err = tx.InsertRSVP(req)
+		if err != nil {
+			errorMessage := []string{err.Error()}
+
+			switch err.(type) {
+			case storage.StorageRSVPPrivateIDUniqueConstraintError:
+				return serviceErrors.NewValidationError(errorMessage)
+			}
+
+			return serviceErrors.NewGeneralServiceError()
+		}
+
+		return audit.Record(s.ctx, tx, domain.AuditCreated, domain.RSVPAuditEntity, newRSVP.ID, nil, newRSVP)
+	})
+	if err != nil {
+		return nil, serviceErrors.FromTransaction(err)
 	}
 
+	s.sendConfirmation(newRSVP)
+
 	return newRSVP, nil
 }
 
-func (s *service) ListRSVPs() ([]domain.RSVP, error) {
+func (s *service) ListRSVPs(req *domain.RSVPListRequest) (*domain.RSVPList, error) {
 	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
 
-	rsvps, err := s.rsvpStorage.ListRSVPs()
+	req.ApplyDefaults("-" + domain.SortByRSVPUpdatedAt)
+
+	errorMessages := req.Validate(domain.SortByRSVPFullName, domain.SortByRSVPGuestCount, domain.SortByRSVPUpdatedAt)
+	if len(errorMessages) > 0 {
+		return nil, serviceErrors.NewValidationError(errorMessages)
+	}
+
+	rsvps, total, err := s.rsvpStorage.ListRSVPs(req)
 	if err != nil {
-		ctxLogger.Error("rsvp service - unable to list all rsvps")
+		ctxLogger.Error("rsvp service - unable to list rsvps")
 		return nil, serviceErrors.NewGeneralServiceError()
 	}
 
-	return rsvps, nil
+	rsvpList := &domain.RSVPList{
+		ListResponse: domain.ListResponse{
+			Page:     req.Page,
+			PageSize: req.PageSize,
+			Total:    total,
+		},
+		RSVPs: rsvps,
+	}
+
+	return rsvpList, nil
 }
 
 func (s *service) UpdateRSVP(req *domain.RSVPUpdateRequest) (*domain.RSVP, error) {
@@ -72,62 +113,192 @@ func (s *service) UpdateRSVP(req *domain.RSVPUpdateRequest) (*domain.RSVP, error
 		return nil, serviceErrors.NewValidationError(errorMessages)
 	}
 
-	rsvp, err := s.rsvpStorage.FindRSVPByID(req.ID)
-	if err != nil {
-		switch err.(type) {
-		case postgres.PostgresRecordNotFoundError:
-			return nil, NewRSVPNotFoundError()
+	var updatedRSVP *domain.RSVP
+
+	err := s.rsvpStorage.WithTx(func(tx interfaces.Storage) error {
+		rsvp, err := tx.FindRSVPByID(req.ID)
+		if err != nil {
+			switch err.(type) {
+			case storage.StorageRecordNotFoundError:
+				return NewRSVPNotFoundError()
+			}
+
+			return serviceErrors.NewGeneralServiceError()
 		}
 
-		return nil, serviceErrors.NewGeneralServiceError()
-	}
+		if rsvp.Version != req.Version {
+			return NewRSVPVersionConflictError(rsvp)
+		}
 
-	rsvp.FullName = req.FullName
-	rsvp.Attending = req.Attending
-	rsvp.GuestCount = req.GuestCount
-	rsvp.SpecialDiet = req.SpecialDiet
-	rsvp.Remarks = req.Remarks
-	rsvp.MobilePhoneNumber = req.MobilePhoneNumber
+		before := *rsvp
+		rsvp.FullName = req.FullName
+		rsvp.Attending = req.Attending
+		rsvp.GuestCount = req.GuestCount
+		rsvp.SpecialDiet = req.SpecialDiet
+		rsvp.Remarks = req.Remarks
+		rsvp.MobilePhoneNumber = req.MobilePhoneNumber
+		rsvp.Email = req.Email
+
+		updatedRSVP, err = tx.UpdateRSVP(rsvp)
+		if err != nil {
+			switch err.(type) {
+			case storage.StorageVersionConflictError:
+				return s.rsvpVersionConflict(tx, req.ID)
+			}
+
+			return serviceErrors.NewGeneralServiceError()
+		}
 
-	updatedInvitation, err := s.rsvpStorage.UpdateRSVP(rsvp)
+		return audit.Record(s.ctx, tx, domain.AuditUpdated, domain.RSVPAuditEntity, rsvp.ID, before, updatedRSVP)
+	})
 	if err != nil {
-		// TODO:: add specific service error handling
-
-		return nil, serviceErrors.NewGeneralServiceError()
+		return nil, serviceErrors.FromTransaction(err)
 	}
 
-	return updatedInvitation, nil
+	s.sendConfirmation(updatedRSVP)
+
+	return updatedRSVP, nil
 }
 
 func (s *service) DeleteRSVPByID(rsvpID int64) error {
-	rsvp, err := s.rsvpStorage.FindRSVPByID(rsvpID)
+	err := s.rsvpStorage.WithTx(func(tx interfaces.Storage) error {
+		rsvp, err := tx.FindRSVPByID(rsvpID)
+		if err != nil {
+			switch err.(type) {
+			case storage.StorageRecordNotFoundError:
+				return NewRSVPNotFoundError()
+			}
+
+			return serviceErrors.NewGeneralServiceError()
+		}
+
+		err = tx.DeleteRSVP(rsvp)
+		if err != nil {
+			switch err.(type) {
+			case storage.StorageRecordNotFoundError:
+				return NewRSVPNotFoundError()
+			}
+
+			return serviceErrors.NewGeneralServiceError()
+		}
+
+		return audit.Record(s.ctx, tx, domain.AuditDeleted, domain.RSVPAuditEntity, rsvp.ID, rsvp, nil)
+	})
 	if err != nil {
-		switch err.(type) {
-		case postgres.PostgresRecordNotFoundError:
-			return NewRSVPNotFoundError()
+		return serviceErrors.FromTransaction(err)
+	}
+
+	return nil
+}
+
+func (s *service) RestoreRSVPByID(rsvpID int64) (*domain.RSVP, error) {
+	var restoredRSVP *domain.RSVP
+
+	err := s.rsvpStorage.WithTx(func(tx interfaces.Storage) error {
+		rsvp, err := tx.FindDeletedRSVPByID(rsvpID)
+		if err != nil {
+			switch err.(type) {
+			case storage.StorageRecordNotFoundError:
+				return NewRSVPNotFoundError()
+			}
+
+			return serviceErrors.NewGeneralServiceError()
 		}
 
-		return serviceErrors.NewGeneralServiceError()
+		_, err = tx.FindInvitationByPrivateID(rsvp.InvitationPrivateID)
+		if err != nil {
+			switch err.(type) {
+			case storage.StorageRecordNotFoundError:
+				errorMessage := []string{"invitation of the rsvp must be restored first"}
+				return serviceErrors.NewValidationError(errorMessage)
+			}
+
+			return serviceErrors.NewGeneralServiceError()
+		}
+
+		restoredRSVP, err = tx.RestoreRSVP(rsvp)
+		if err != nil {
+			errorMessage := []string{err.Error()}
+
+			switch err.(type) {
+			case storage.StorageRSVPPrivateIDUniqueConstraintError:
+				return serviceErrors.NewValidationError(errorMessage)
+			case storage.StorageRecordNotFoundError:
+				return NewRSVPNotFoundError()
+			}
+
+			return serviceErrors.NewGeneralServiceError()
+		}
+
+		return audit.Record(s.ctx, tx, domain.AuditRestored, domain.RSVPAuditEntity, rsvp.ID, rsvp, restoredRSVP)
+	})
+	if err != nil {
+		return nil, serviceErrors.FromTransaction(err)
 	}
 
-	err = s.rsvpStorage.DeleteRSVP(rsvp)
+	return restoredRSVP, nil
+}
+
+// CheckInRSVPByID marks the guest of the rsvp as arrived. Checking in a guest who already arrived
+// keeps the time they were first checked in.
+func (s *service) CheckInRSVPByID(rsvpID int64) (*domain.RSVP, error) {
+	var checkedInRSVP *domain.RSVP
+
+	err := s.rsvpStorage.WithTx(func(tx interfaces.Storage) error {
+		rsvp, err := tx.FindRSVPByID(rsvpID)
+		if err != nil {
+			switch err.(type) {
+			case storage.StorageRecordNotFoundError:
+				return NewRSVPNotFoundError()
+			}
+
+			return serviceErrors.NewGeneralServiceError()
+		}
+
+		if rsvp.CheckedInAt != "" {
+			checkedInRSVP = rsvp
+			return nil
+		}
+
+		checkedInRSVP, err = tx.CheckInRSVP(rsvp)
+		if err != nil {
+			switch err.(type) {
+			case storage.StorageRecordNotFoundError:
+				return NewRSVPNotFoundError()
+			}
+
+			return serviceErrors.NewGeneralServiceError()
+		}
+
+		return audit.Record(s.ctx, tx, domain.AuditCheckedIn, domain.RSVPAuditEntity, rsvp.ID, rsvp, checkedInRSVP)
+	})
+	if err != nil {
+		return nil, serviceErrors.FromTransaction(err)
+	}
+
+	return checkedInRSVP, nil
+}
+
+// rsvpVersionConflict reloads the rsvp that was changed by a concurrent update.
+func (s *service) rsvpVersionConflict(tx interfaces.Storage, rsvpID int64) error {
+	current, err := tx.FindRSVPByID(rsvpID)
 	if err != nil {
 		switch err.(type) {
-		case postgres.PostgresRecordNotFoundError:
+		case storage.StorageRecordNotFoundError:
 			return NewRSVPNotFoundError()
 		}
 
 		return serviceErrors.NewGeneralServiceError()
 	}
 
-	return nil
+	return NewRSVPVersionConflictError(current)
 }
 
 func (s *service) RetrievePrivateRSVP(invitationPrivateID string) (*domain.RSVP, error) {
 	rsvp, err := s.rsvpStorage.FindRSVPByInvitationPrivateID(invitationPrivateID)
 	if err != nil {
 		switch err.(type) {
-		case postgres.PostgresRecordNotFoundError:
+		case storage.StorageRecordNotFoundError:
 			return nil, NewRSVPNotFoundError()
 		}
 
@@ -153,6 +324,9 @@ func validateBaseRSVP(baseRSVP domain.BaseRSVP) (errorMessages []string) {
 	if !utils.IsWithin(len(baseRSVP.MobilePhoneNumber), MobilePhoneNumberMinLength, MobilePhoneNumberMaxLength) {
 		errorMessages = append(errorMessages, fmt.Sprintf("rsvp mobile phone number must be between %v to %v in length and contain only numbers", MobilePhoneNumberMinLength, MobilePhoneNumberMaxLength))
 	}
+	if baseRSVP.Email != "" && (len(baseRSVP.Email) > EmailMaxLength || !utils.IsValidEmail(baseRSVP.Email)) {
+		errorMessages = append(errorMessages, "rsvp email must be a valid email address")
+	}
 
 	return errorMessages
 }
@@ -165,6 +339,9 @@ func validateRSVPUpdateRequest(req *domain.RSVPUpdateRequest) (errorMessages []s
 	if req.ID <= 0 {
 		errorMessages = append(errorMessages, "rsvp id is invalid")
 	}
+	if req.Version <= 0 {
+		errorMessages = append(errorMessages, "rsvp version is invalid")
+	}
 
 	return append(errorMessages, validateBaseRSVP(req.BaseRSVP)...)
 }
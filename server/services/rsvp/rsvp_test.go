@@ -8,8 +8,8 @@ import (
 	"github.com/rawfish-dev/rsvp-starter/server/interfaces"
 	"github.com/rawfish-dev/rsvp-starter/server/mock"
 	serviceErrors "github.com/rawfish-dev/rsvp-starter/server/services/errors"
-	"github.com/rawfish-dev/rsvp-starter/server/services/postgres"
 	. "github.com/rawfish-dev/rsvp-starter/server/services/rsvp"
+	"github.com/rawfish-dev/rsvp-starter/server/services/storage"
 
 	"github.com/Sirupsen/logrus"
 	"github.com/golang/mock/gomock"
@@ -21,7 +21,8 @@ import (
 var _ = Describe("RSVP", func() {
 
 	var ctrl *gomock.Controller
-	var mockRSVPStorage *mock_interfaces.MockRSVPStorage
+	var mockRSVPStorage *mock_interfaces.MockTransactionalStorage
+	var mockEmailSender *mock_interfaces.MockEmailSender
 	var testRSVPService interfaces.RSVPServiceProvider
 
 	BeforeEach(func() {
@@ -31,8 +32,10 @@ var _ = Describe("RSVP", func() {
 		ctx := context.Background()
 		ctx = context.WithValue(ctx, "logger", ctxlogger)
 
-		mockRSVPStorage = mock_interfaces.NewMockRSVPStorage(ctrl)
-		testRSVPService = NewService(ctx, mockRSVPStorage)
+		mockRSVPStorage = mock_interfaces.NewMockTransactionalStorage(ctrl)
+		mockEmailSender = mock_interfaces.NewMockEmailSender(ctrl)
+		mockEmailSender.EXPECT().Name().Return("smtp").AnyTimes()
+		testRSVPService = NewService(ctx, mockEmailSender, mockRSVPStorage)
 	})
 
 	Context("creation", func() {
@@ -54,12 +57,16 @@ var _ = Describe("RSVP", func() {
 		})
 
 		It("should create an rsvp given valid values", func() {
+			mockRSVPStorage.EXPECT().FindInvitationByPrivateID("some-private-id").Return(&domain.Invitation{
+				PrivateID: "some-private-id",
+			}, nil)
 			mockRSVPStorage.EXPECT().InsertRSVP(req).Return(&domain.RSVP{
 				BaseRSVP:            req.BaseRSVP,
 				ID:                  1,
 				InvitationPrivateID: req.InvitationPrivateID,
 				Completed:           true,
 			}, nil)
+			mockRSVPStorage.EXPECT().InsertAuditEntry(gomock.Any()).Return(&domain.AuditEntry{}, nil)
 
 			newRSVP, err := testRSVPService.CreateRSVP(req)
 			Expect(err).ToNot(HaveOccurred())
@@ -76,8 +83,11 @@ var _ = Describe("RSVP", func() {
 		})
 
 		It("should not allow rsvps with duplicate private ids", func() {
+			mockRSVPStorage.EXPECT().FindInvitationByPrivateID("some-private-id").Return(&domain.Invitation{
+				PrivateID: "some-private-id",
+			}, nil)
 			mockRSVPStorage.EXPECT().InsertRSVP(req).Return(
-				nil, postgres.NewPostgresRSVPPrivateIDUniqueConstraintError())
+				nil, storage.NewStorageRSVPPrivateIDUniqueConstraintError())
 
 			duplicateRSVP, err := testRSVPService.CreateRSVP(req)
 			Expect(err).To(HaveOccurred())
@@ -86,6 +96,18 @@ var _ = Describe("RSVP", func() {
 			Expect(duplicateRSVP).To(BeNil())
 		})
 
+		It("should not allow rsvps for invitations that do not exist", func() {
+			mockRSVPStorage.EXPECT().FindInvitationByPrivateID("some-private-id").
+				Return(nil, storage.NewStorageRecordNotFoundError())
+			mockRSVPStorage.EXPECT().InsertRSVP(gomock.Any()).Times(0)
+
+			newRSVP, err := testRSVPService.CreateRSVP(req)
+			Expect(err).To(HaveOccurred())
+			Expect(err).To(BeAssignableToTypeOf(serviceErrors.ValidationError{}))
+			Expect(err.Error()).To(Equal("invitation does not exist"))
+			Expect(newRSVP).To(BeNil())
+		})
+
 		It("should return an error if full name is too short", func() {
 			req.FullName = "a"
 
@@ -147,12 +169,16 @@ var _ = Describe("RSVP", func() {
 		It("should not return an error if remarks are empty", func() {
 			req.Remarks = ""
 
+			mockRSVPStorage.EXPECT().FindInvitationByPrivateID("some-private-id").Return(&domain.Invitation{
+				PrivateID: "some-private-id",
+			}, nil)
 			mockRSVPStorage.EXPECT().InsertRSVP(req).Return(&domain.RSVP{
 				BaseRSVP:            req.BaseRSVP,
 				ID:                  1,
 				InvitationPrivateID: req.InvitationPrivateID,
 				Completed:           true,
 			}, nil)
+			mockRSVPStorage.EXPECT().InsertAuditEntry(gomock.Any()).Return(&domain.AuditEntry{}, nil)
 
 			newRSVP, err := testRSVPService.CreateRSVP(req)
 			Expect(err).ToNot(HaveOccurred())
@@ -216,6 +242,139 @@ var _ = Describe("RSVP", func() {
 		// })
 	})
 
+	Context("confirmation", func() {
+
+		var req *domain.RSVPCreateRequest
+
+		BeforeEach(func() {
+			req = &domain.RSVPCreateRequest{
+				BaseRSVP: domain.BaseRSVP{
+					FullName:          "mitten lin",
+					Attending:         true,
+					GuestCount:        2,
+					SpecialDiet:       true,
+					MobilePhoneNumber: "91234123",
+					Email:             "mitten@example.com",
+				},
+				InvitationPrivateID: "some-private-id",
+			}
+		})
+
+		It("should email a summary of the reply and record it against the invitation", func() {
+			invitation := &domain.Invitation{ID: 3, PrivateID: "some-private-id"}
+			newRSVP := &domain.RSVP{BaseRSVP: req.BaseRSVP, ID: 1, InvitationPrivateID: req.InvitationPrivateID}
+
+			gomock.InOrder(
+				mockRSVPStorage.EXPECT().FindInvitationByPrivateID("some-private-id").Return(invitation, nil),
+				mockRSVPStorage.EXPECT().InsertRSVP(req).Return(newRSVP, nil),
+				mockRSVPStorage.EXPECT().InsertAuditEntry(gomock.Any()).Return(&domain.AuditEntry{}, nil),
+				mockRSVPStorage.EXPECT().FindInvitationByPrivateID("some-private-id").Return(invitation, nil),
+				mockEmailSender.EXPECT().SendEmail(gomock.Any()).Do(func(email *domain.Email) {
+					Expect(email.To).To(Equal("mitten@example.com"))
+					Expect(email.TextBody).To(ContainSubstring("Hi mitten lin"))
+					Expect(email.TextBody).To(ContainSubstring("Attending: yes"))
+					Expect(email.TextBody).To(ContainSubstring("Guests: 2"))
+					Expect(email.TextBody).To(ContainSubstring("Special diet: yes"))
+					Expect(email.HTMLBody).To(ContainSubstring("<li>Guests: 2</li>"))
+				}).Return("<abc@example.com>", nil),
+				mockRSVPStorage.EXPECT().InsertMessage(gomock.Any()).Do(func(message *domain.Message) {
+					Expect(message.InvitationID).To(Equal(int64(3)))
+					Expect(message.Channel).To(Equal(domain.EmailChannel))
+					Expect(message.Recipient).To(Equal("mitten@example.com"))
+					Expect(message.Status).To(Equal(domain.MessageQueued))
+				}).Return(&domain.Message{}, nil),
+			)
+
+			_, err := testRSVPService.CreateRSVP(req)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should only mention attending when the guest is not coming", func() {
+			updateReq := &domain.RSVPUpdateRequest{BaseRSVP: req.BaseRSVP, ID: 1, InvitationPrivateID: "some-private-id", Version: 1}
+			updateReq.Attending = false
+
+			rsvp := &domain.RSVP{BaseRSVP: req.BaseRSVP, ID: 1, InvitationPrivateID: "some-private-id", Version: 1}
+
+			gomock.InOrder(
+				mockRSVPStorage.EXPECT().FindRSVPByID(int64(1)).Return(rsvp, nil),
+				mockRSVPStorage.EXPECT().UpdateRSVP(gomock.Any()).Return(&domain.RSVP{BaseRSVP: updateReq.BaseRSVP, ID: 1, InvitationPrivateID: "some-private-id", Version: 2}, nil),
+				mockRSVPStorage.EXPECT().InsertAuditEntry(gomock.Any()).Return(&domain.AuditEntry{}, nil),
+				mockRSVPStorage.EXPECT().FindInvitationByPrivateID("some-private-id").Return(&domain.Invitation{ID: 3}, nil),
+				mockEmailSender.EXPECT().SendEmail(gomock.Any()).Do(func(email *domain.Email) {
+					Expect(email.TextBody).To(ContainSubstring("Attending: no"))
+					Expect(email.TextBody).ToNot(ContainSubstring("Guests"))
+				}).Return("<abc@example.com>", nil),
+				mockRSVPStorage.EXPECT().InsertMessage(gomock.Any()).Return(&domain.Message{}, nil),
+			)
+
+			_, err := testRSVPService.UpdateRSVP(updateReq)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should record the failed email without failing the rsvp", func() {
+			gomock.InOrder(
+				mockRSVPStorage.EXPECT().FindInvitationByPrivateID("some-private-id").Return(&domain.Invitation{ID: 3}, nil),
+				mockRSVPStorage.EXPECT().InsertRSVP(req).Return(&domain.RSVP{BaseRSVP: req.BaseRSVP, ID: 1, InvitationPrivateID: req.InvitationPrivateID}, nil),
+				mockRSVPStorage.EXPECT().InsertAuditEntry(gomock.Any()).Return(&domain.AuditEntry{}, nil),
+				mockRSVPStorage.EXPECT().FindInvitationByPrivateID("some-private-id").Return(&domain.Invitation{ID: 3}, nil),
+				mockEmailSender.EXPECT().SendEmail(gomock.Any()).Return("", fmt.Errorf("550 mailbox unavailable")),
+				mockRSVPStorage.EXPECT().InsertMessage(gomock.Any()).Do(func(message *domain.Message) {
+					Expect(message.Status).To(Equal(domain.MessageFailed))
+					Expect(message.Error).To(Equal("550 mailbox unavailable"))
+				}).Return(&domain.Message{}, nil),
+			)
+
+			newRSVP, err := testRSVPService.CreateRSVP(req)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(newRSVP.ID).To(Equal(int64(1)))
+		})
+
+		It("should return an error if the email is invalid", func() {
+			req.Email = "not an email"
+
+			newRSVP, err := testRSVPService.CreateRSVP(req)
+			Expect(err).To(BeAssignableToTypeOf(serviceErrors.ValidationError{}))
+			Expect(err.Error()).To(ContainSubstring("rsvp email must be a valid email address"))
+			Expect(newRSVP).To(BeNil())
+		})
+	})
+
+	Context("listing", func() {
+
+		It("should pass the filters through with the default page and sort", func() {
+			attending := true
+			rsvps := []domain.RSVP{
+				{ID: 1, BaseRSVP: domain.BaseRSVP{Attending: true}},
+			}
+
+			mockRSVPStorage.EXPECT().ListRSVPs(&domain.RSVPListRequest{
+				ListRequest: domain.ListRequest{Page: 2, PageSize: domain.DefaultPageSize, Sort: "-updatedAt"},
+				Attending:   &attending,
+			}).Return(rsvps, 51, nil)
+
+			rsvpList, err := testRSVPService.ListRSVPs(&domain.RSVPListRequest{
+				ListRequest: domain.ListRequest{Page: 2},
+				Attending:   &attending,
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(rsvpList.RSVPs).To(Equal(rsvps))
+			Expect(rsvpList.Page).To(Equal(2))
+			Expect(rsvpList.Total).To(Equal(51))
+		})
+
+		It("should return an error if the sort field is invalid", func() {
+			// Validation should catch it before any attempt to storage is made
+			mockRSVPStorage.EXPECT().ListRSVPs(gomock.Any()).Times(0)
+
+			rsvpList, err := testRSVPService.ListRSVPs(&domain.RSVPListRequest{
+				ListRequest: domain.ListRequest{Sort: "remarks"},
+			})
+			Expect(err).To(BeAssignableToTypeOf(serviceErrors.ValidationError{}))
+			Expect(err.Error()).To(Equal("sort field remarks is invalid"))
+			Expect(rsvpList).To(BeNil())
+		})
+	})
+
 	// Context("retrieval", func() {
 
 	// 	It("should return all rsvps sorted by updated at asc", func() {
@@ -279,6 +438,7 @@ var _ = Describe("RSVP", func() {
 				BaseRSVP:            baseRSVP,
 				ID:                  1,
 				InvitationPrivateID: "some-private-id",
+				Version:             1,
 			}
 		})
 
@@ -288,6 +448,7 @@ var _ = Describe("RSVP", func() {
 				ID:                  1,
 				InvitationPrivateID: "some-private-id",
 				Completed:           true,
+				Version:             1,
 			}
 
 			modifiedRSVP := *rsvp
@@ -304,6 +465,7 @@ var _ = Describe("RSVP", func() {
 					rsvp, nil),
 				mockRSVPStorage.EXPECT().UpdateRSVP(&modifiedRSVP).Return(
 					&modifiedRSVP, nil),
+				mockRSVPStorage.EXPECT().InsertAuditEntry(gomock.Any()).Return(&domain.AuditEntry{}, nil),
 			)
 
 			updatedRSVP, err := testRSVPService.UpdateRSVP(updateReq)
@@ -322,7 +484,7 @@ var _ = Describe("RSVP", func() {
 
 		It("should return an error if the rsvp cannot be found", func() {
 			mockRSVPStorage.EXPECT().FindRSVPByID(int64(123123123)).Return(
-				nil, postgres.NewPostgresRecordNotFoundError())
+				nil, storage.NewStorageRecordNotFoundError())
 
 			updateReq.ID = 123123123
 
@@ -332,6 +494,69 @@ var _ = Describe("RSVP", func() {
 			Expect(updatedRSVP).To(BeNil())
 		})
 
+		It("should return a version conflict error with the current rsvp if the version is stale", func() {
+			currentRSVP := &domain.RSVP{
+				BaseRSVP:            baseRSVP,
+				ID:                  1,
+				InvitationPrivateID: "some-private-id",
+				Completed:           true,
+				Version:             2,
+			}
+
+			gomock.InOrder(
+				mockRSVPStorage.EXPECT().FindRSVPByID(int64(1)).Return(currentRSVP, nil),
+				mockRSVPStorage.EXPECT().UpdateRSVP(gomock.Any()).Times(0),
+			)
+
+			updatedRSVP, err := testRSVPService.UpdateRSVP(updateReq)
+			Expect(err).To(HaveOccurred())
+			Expect(err).To(BeAssignableToTypeOf(RSVPVersionConflictError{}))
+			Expect(err.Error()).To(Equal("rsvp has been modified since it was retrieved"))
+			Expect(err.(RSVPVersionConflictError).Current).To(Equal(currentRSVP))
+			Expect(updatedRSVP).To(BeNil())
+		})
+
+		It("should return a version conflict error with the current rsvp if it changes before it is written", func() {
+			rsvp := &domain.RSVP{
+				BaseRSVP:            baseRSVP,
+				ID:                  1,
+				InvitationPrivateID: "some-private-id",
+				Completed:           true,
+				Version:             1,
+			}
+			currentRSVP := &domain.RSVP{
+				BaseRSVP:            baseRSVP,
+				ID:                  1,
+				InvitationPrivateID: "some-private-id",
+				Completed:           true,
+				Version:             2,
+			}
+
+			gomock.InOrder(
+				mockRSVPStorage.EXPECT().FindRSVPByID(int64(1)).Return(rsvp, nil),
+				mockRSVPStorage.EXPECT().UpdateRSVP(rsvp).Return(nil, storage.NewStorageVersionConflictError()),
+				mockRSVPStorage.EXPECT().FindRSVPByID(int64(1)).Return(currentRSVP, nil),
+			)
+
+			updatedRSVP, err := testRSVPService.UpdateRSVP(updateReq)
+			Expect(err).To(BeAssignableToTypeOf(RSVPVersionConflictError{}))
+			Expect(err.(RSVPVersionConflictError).Current).To(Equal(currentRSVP))
+			Expect(updatedRSVP).To(BeNil())
+		})
+
+		It("should return an error if version is missing", func() {
+			// Validation should catch it before any attempt to storage is made
+			mockRSVPStorage.EXPECT().FindRSVPByID(gomock.Any()).Times(0)
+
+			updateReq.Version = 0
+
+			updatedRSVP, err := testRSVPService.UpdateRSVP(updateReq)
+			Expect(err).To(HaveOccurred())
+			Expect(err).To(BeAssignableToTypeOf(serviceErrors.ValidationError{}))
+			Expect(err.Error()).To(Equal("rsvp version is invalid"))
+			Expect(updatedRSVP).To(BeNil())
+		})
+
 		It("should return an error if full name is too short", func() {
 			// Validation should catch it before any attempt to storage is made
 			mockRSVPStorage.EXPECT().InsertRSVP(gomock.Any()).Times(0)
@@ -396,6 +621,7 @@ var _ = Describe("RSVP", func() {
 				ID:                  1,
 				InvitationPrivateID: "some-private-id",
 				Completed:           true,
+				Version:             1,
 			}
 			rsvp.Remarks = ""
 
@@ -406,6 +632,7 @@ var _ = Describe("RSVP", func() {
 					rsvp, nil),
 				mockRSVPStorage.EXPECT().UpdateRSVP(rsvp).Return(
 					rsvp, nil),
+				mockRSVPStorage.EXPECT().InsertAuditEntry(gomock.Any()).Return(&domain.AuditEntry{}, nil),
 			)
 
 			updatedRSVP, err := testRSVPService.UpdateRSVP(updateReq)
@@ -490,6 +717,7 @@ var _ = Describe("RSVP", func() {
 			gomock.InOrder(
 				mockRSVPStorage.EXPECT().FindRSVPByID(int64(1)).Return(rsvp, nil),
 				mockRSVPStorage.EXPECT().DeleteRSVP(rsvp).Return(nil),
+				mockRSVPStorage.EXPECT().InsertAuditEntry(gomock.Any()).Return(&domain.AuditEntry{}, nil),
 			)
 
 			err := testRSVPService.DeleteRSVPByID(1)
@@ -498,11 +726,133 @@ var _ = Describe("RSVP", func() {
 
 		It("should return an error if the rsvp cannot be found", func() {
 			mockRSVPStorage.EXPECT().FindRSVPByID(int64(123123123)).Return(
-				nil, postgres.NewPostgresRecordNotFoundError())
+				nil, storage.NewStorageRecordNotFoundError())
 
 			err := testRSVPService.DeleteRSVPByID(123123123)
 			Expect(err).To(HaveOccurred())
 			Expect(err).To(BeAssignableToTypeOf(RSVPNotFoundError{}))
 		})
 	})
+
+	Context("restoring", func() {
+
+		var rsvp *domain.RSVP
+
+		BeforeEach(func() {
+			rsvp = &domain.RSVP{
+				BaseRSVP: domain.BaseRSVP{
+					FullName:   "some full name",
+					Attending:  true,
+					GuestCount: 2,
+				},
+				ID:                  1,
+				InvitationPrivateID: "some-private-id",
+				Completed:           true,
+				DeletedAt:           "2026-10-17T10:00:00Z",
+			}
+		})
+
+		It("should restore a deleted rsvp", func() {
+			restoredRSVP := *rsvp
+			restoredRSVP.DeletedAt = ""
+
+			gomock.InOrder(
+				mockRSVPStorage.EXPECT().FindDeletedRSVPByID(int64(1)).Return(rsvp, nil),
+				mockRSVPStorage.EXPECT().FindInvitationByPrivateID("some-private-id").Return(&domain.Invitation{ID: 1}, nil),
+				mockRSVPStorage.EXPECT().RestoreRSVP(rsvp).Return(&restoredRSVP, nil),
+				mockRSVPStorage.EXPECT().InsertAuditEntry(gomock.Any()).Return(&domain.AuditEntry{}, nil),
+			)
+
+			result, err := testRSVPService.RestoreRSVPByID(1)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result).To(Equal(&restoredRSVP))
+		})
+
+		It("should not restore an rsvp whose invitation is not restored", func() {
+			gomock.InOrder(
+				mockRSVPStorage.EXPECT().FindDeletedRSVPByID(int64(1)).Return(rsvp, nil),
+				mockRSVPStorage.EXPECT().FindInvitationByPrivateID("some-private-id").Return(
+					nil, storage.NewStorageRecordNotFoundError()),
+			)
+
+			_, err := testRSVPService.RestoreRSVPByID(1)
+			Expect(err).To(BeAssignableToTypeOf(serviceErrors.ValidationError{}))
+			Expect(err.Error()).To(Equal("invitation of the rsvp must be restored first"))
+		})
+
+		It("should not restore an rsvp if the invitation has been replied to since it was deleted", func() {
+			gomock.InOrder(
+				mockRSVPStorage.EXPECT().FindDeletedRSVPByID(int64(1)).Return(rsvp, nil),
+				mockRSVPStorage.EXPECT().FindInvitationByPrivateID("some-private-id").Return(&domain.Invitation{ID: 1}, nil),
+				mockRSVPStorage.EXPECT().RestoreRSVP(rsvp).Return(
+					nil, storage.NewStorageRSVPPrivateIDUniqueConstraintError()),
+			)
+
+			_, err := testRSVPService.RestoreRSVPByID(1)
+			Expect(err).To(BeAssignableToTypeOf(serviceErrors.ValidationError{}))
+		})
+
+		It("should return an error if the rsvp is not in the trash", func() {
+			mockRSVPStorage.EXPECT().FindDeletedRSVPByID(int64(123123123)).Return(
+				nil, storage.NewStorageRecordNotFoundError())
+
+			_, err := testRSVPService.RestoreRSVPByID(123123123)
+			Expect(err).To(BeAssignableToTypeOf(RSVPNotFoundError{}))
+		})
+	})
+
+	Context("checking in", func() {
+
+		var rsvp *domain.RSVP
+
+		BeforeEach(func() {
+			rsvp = &domain.RSVP{
+				BaseRSVP: domain.BaseRSVP{
+					FullName:   "some full name",
+					Attending:  true,
+					GuestCount: 2,
+				},
+				ID:                  1,
+				InvitationPrivateID: "some-private-id",
+				Completed:           true,
+				Version:             1,
+			}
+		})
+
+		It("should mark the guest of the rsvp as arrived", func() {
+			checkedInRSVP := *rsvp
+			checkedInRSVP.CheckedInAt = "2026-10-17T19:00:00Z"
+
+			gomock.InOrder(
+				mockRSVPStorage.EXPECT().FindRSVPByID(int64(1)).Return(rsvp, nil),
+				mockRSVPStorage.EXPECT().CheckInRSVP(rsvp).Return(&checkedInRSVP, nil),
+				mockRSVPStorage.EXPECT().InsertAuditEntry(gomock.Any()).Do(func(req *domain.AuditEntryCreateRequest) {
+					Expect(req.Action).To(Equal(domain.AuditCheckedIn))
+				}).Return(&domain.AuditEntry{}, nil),
+			)
+
+			result, err := testRSVPService.CheckInRSVPByID(1)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result).To(Equal(&checkedInRSVP))
+		})
+
+		It("should keep the time a guest who already arrived was first checked in", func() {
+			rsvp.CheckedInAt = "2026-10-17T19:00:00Z"
+
+			mockRSVPStorage.EXPECT().FindRSVPByID(int64(1)).Return(rsvp, nil)
+			mockRSVPStorage.EXPECT().CheckInRSVP(gomock.Any()).Times(0)
+
+			result, err := testRSVPService.CheckInRSVPByID(1)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.CheckedInAt).To(Equal("2026-10-17T19:00:00Z"))
+		})
+
+		It("should return an error if the rsvp cannot be found", func() {
+			mockRSVPStorage.EXPECT().FindRSVPByID(int64(123123123)).Return(
+				nil, storage.NewStorageRecordNotFoundError())
+
+			_, err := testRSVPService.CheckInRSVPByID(123123123)
+			Expect(err).To(BeAssignableToTypeOf(RSVPNotFoundError{}))
+		})
+	})
 })
@@ -0,0 +1,73 @@
+package search
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/rawfish-dev/rsvp-starter/server/domain"
+	"github.com/rawfish-dev/rsvp-starter/server/interfaces"
+	serviceErrors "github.com/rawfish-dev/rsvp-starter/server/services/errors"
+	"github.com/rawfish-dev/rsvp-starter/server/utils"
+
+	"golang.org/x/net/context"
+)
+
+const (
+	QueryMinLength = 2
+	QueryMaxLength = 100
+)
+
+var _ interfaces.SearchServiceProvider = new(service)
+
+type service struct {
+	ctx           context.Context
+	searchStorage interfaces.Storage
+}
+
+func NewService(ctx context.Context, searchStorage interfaces.Storage) *service {
+	return &service{ctx, searchStorage}
+}
+
+func (s *service) Search(req *domain.SearchRequest) (*domain.SearchResults, error) {
+	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
+
+	req.Query = strings.TrimSpace(req.Query)
+	if req.Limit == 0 {
+		req.Limit = domain.DefaultSearchLimit
+	}
+
+	errorMessages := validateSearchRequest(req)
+	if len(errorMessages) > 0 {
+		return nil, serviceErrors.NewValidationError(errorMessages)
+	}
+
+	results, err := s.searchStorage.Search(req)
+	if err != nil {
+		ctxLogger.Error("search service - unable to search invitations and rsvps")
+		return nil, serviceErrors.NewGeneralServiceError()
+	}
+
+	// Always return an array so clients do not need to check for null
+	if results == nil {
+		results = []domain.SearchResult{}
+	}
+
+	searchResults := &domain.SearchResults{
+		Query:   req.Query,
+		Results: results,
+	}
+
+	return searchResults, nil
+}
+
+func validateSearchRequest(req *domain.SearchRequest) (errorMessages []string) {
+	if !utils.IsWithin(len(req.Query), QueryMinLength, QueryMaxLength) {
+		errorMessages = append(errorMessages, fmt.Sprintf("search query must be between %v to %v characters", QueryMinLength, QueryMaxLength))
+	}
+
+	if !utils.IsWithin(req.Limit, 1, domain.MaximumSearchLimit) {
+		errorMessages = append(errorMessages, fmt.Sprintf("limit must be between %v to %v", 1, domain.MaximumSearchLimit))
+	}
+
+	return errorMessages
+}
@@ -0,0 +1,113 @@
+package search_test
+
+import (
+	"strings"
+
+	"github.com/rawfish-dev/rsvp-starter/server/domain"
+	"github.com/rawfish-dev/rsvp-starter/server/interfaces"
+	"github.com/rawfish-dev/rsvp-starter/server/mock"
+	serviceErrors "github.com/rawfish-dev/rsvp-starter/server/services/errors"
+	. "github.com/rawfish-dev/rsvp-starter/server/services/search"
+	"github.com/rawfish-dev/rsvp-starter/server/services/storage"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"golang.org/x/net/context"
+)
+
+var _ = Describe("Search", func() {
+
+	var ctrl *gomock.Controller
+	var mockSearchStorage *mock_interfaces.MockStorage
+	var testSearchService interfaces.SearchServiceProvider
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+
+		ctxlogger := logrus.New()
+		ctx := context.Background()
+		ctx = context.WithValue(ctx, "logger", ctxlogger)
+
+		mockSearchStorage = mock_interfaces.NewMockStorage(ctrl)
+		testSearchService = NewService(ctx, mockSearchStorage)
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	Context("searching", func() {
+
+		It("should trim the query, apply the default limit and return the ranked results", func() {
+			results := []domain.SearchResult{
+				{
+					Type:       domain.InvitationSearchResult,
+					Rank:       1,
+					Invitation: &domain.Invitation{ID: 1, BaseInvitation: domain.BaseInvitation{Greeting: "The Tan Family"}},
+				},
+				{
+					Type: domain.RSVPSearchResult,
+					Rank: 0.75,
+					RSVP: &domain.RSVP{ID: 2, BaseRSVP: domain.BaseRSVP{FullName: "Tan Ah Kow"}},
+				},
+			}
+
+			mockSearchStorage.EXPECT().Search(&domain.SearchRequest{Query: "tan", Limit: domain.DefaultSearchLimit}).
+				Return(results, nil)
+
+			searchResults, err := testSearchService.Search(&domain.SearchRequest{Query: "  tan "})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(searchResults.Query).To(Equal("tan"))
+			Expect(searchResults.Results).To(Equal(results))
+		})
+
+		It("should return an empty list when nothing matches", func() {
+			mockSearchStorage.EXPECT().Search(&domain.SearchRequest{Query: "nobody", Limit: 5}).
+				Return(nil, nil)
+
+			searchResults, err := testSearchService.Search(&domain.SearchRequest{Query: "nobody", Limit: 5})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(searchResults.Results).ToNot(BeNil())
+			Expect(searchResults.Results).To(BeEmpty())
+		})
+
+		It("should return an error if the query is too short", func() {
+			// Validation should catch it before any attempt to storage is made
+			mockSearchStorage.EXPECT().Search(gomock.Any()).Times(0)
+
+			searchResults, err := testSearchService.Search(&domain.SearchRequest{Query: " a "})
+			Expect(err).To(BeAssignableToTypeOf(serviceErrors.ValidationError{}))
+			Expect(err.Error()).To(Equal("search query must be between 2 to 100 characters"))
+			Expect(searchResults).To(BeNil())
+		})
+
+		It("should return an error if the query is too long", func() {
+			mockSearchStorage.EXPECT().Search(gomock.Any()).Times(0)
+
+			searchResults, err := testSearchService.Search(&domain.SearchRequest{Query: strings.Repeat("a", 101)})
+			Expect(err).To(BeAssignableToTypeOf(serviceErrors.ValidationError{}))
+			Expect(err.Error()).To(Equal("search query must be between 2 to 100 characters"))
+			Expect(searchResults).To(BeNil())
+		})
+
+		It("should return an error if the limit is out of range", func() {
+			mockSearchStorage.EXPECT().Search(gomock.Any()).Times(0)
+
+			searchResults, err := testSearchService.Search(&domain.SearchRequest{Query: "tan", Limit: 51})
+			Expect(err).To(BeAssignableToTypeOf(serviceErrors.ValidationError{}))
+			Expect(err.Error()).To(Equal("limit must be between 1 to 50"))
+			Expect(searchResults).To(BeNil())
+		})
+
+		It("should return a general service error when storage fails", func() {
+			mockSearchStorage.EXPECT().Search(gomock.Any()).
+				Return(nil, storage.NewStorageOperationError())
+
+			searchResults, err := testSearchService.Search(&domain.SearchRequest{Query: "tan"})
+			Expect(err).To(BeAssignableToTypeOf(serviceErrors.GeneralServiceError{}))
+			Expect(searchResults).To(BeNil())
+		})
+	})
+})
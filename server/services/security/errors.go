@@ -0,0 +1,14 @@
+package security
+
+var _ error = new(PreAuthTokenInvalidError)
+
+type PreAuthTokenInvalidError struct {
+}
+
+func NewPreAuthTokenInvalidError() error {
+	return PreAuthTokenInvalidError{}
+}
+
+func (p PreAuthTokenInvalidError) Error() string {
+	return "pre-auth token is invalid"
+}
@@ -2,29 +2,36 @@ package security
 
 import (
 	"strings"
+	"time"
 
+	"github.com/rawfish-dev/rsvp-starter/server/config"
+	"github.com/rawfish-dev/rsvp-starter/server/domain"
 	"github.com/rawfish-dev/rsvp-starter/server/interfaces"
+	serviceErrors "github.com/rawfish-dev/rsvp-starter/server/services/errors"
+	"github.com/rawfish-dev/rsvp-starter/server/services/jwt"
+	"github.com/rawfish-dev/rsvp-starter/server/services/storage"
 
 	"golang.org/x/crypto/bcrypt"
 	"golang.org/x/net/context"
 )
 
-var _ interfaces.SecurityServiceProvider
+// preAuthPurpose marks pre-auth tokens apart from auth tokens, which are signed with the same secret
+const preAuthPurpose = "pre_auth"
+
+var _ interfaces.SecurityServiceProvider = new(service)
 
 type service struct {
-	ctx context.Context
+	ctx            context.Context
+	securityConfig config.SecurityConfig
+	jwtService     interfaces.JWTServiceProvider
+	userStorage    interfaces.Storage
 }
 
-var (
-	// TODO:: Store in DB/ENV so they can be changed easily
-	credentials = map[string]string{
-		"kevin": "$2a$10$Aq2PBEw7JeYFEvesyZBDV.Q5tyFoyLObTaMa03VHgxIfwzRRrZH0W",
-		"jenny": "$2a$10$vOZqrM2Kv5TI0hIdfpWA4.dqIGYV5G7nOl7sybAWPr8gupdiwRsja",
-	}
-)
-
-func NewService(ctx context.Context) *service {
-	return &service{ctx}
+func NewService(ctx context.Context,
+	securityConfig config.SecurityConfig,
+	jwtService interfaces.JWTServiceProvider,
+	userStorage interfaces.Storage) *service {
+	return &service{ctx, securityConfig, jwtService, userStorage}
 }
 
 func (s *service) ValidateCredentials(username, password string) (valid bool) {
@@ -32,13 +39,18 @@ func (s *service) ValidateCredentials(username, password string) (valid bool) {
 
 	downcasedUsername := strings.ToLower(username)
 
-	encryptedPassword, ok := credentials[downcasedUsername]
-	if !ok {
+	user, err := s.userStorage.FindUserByUsername(downcasedUsername)
+	if err != nil {
 		ctxLogger.Warnf("security service - unable to find username %v", downcasedUsername)
 		return false
 	}
 
-	err := bcrypt.CompareHashAndPassword([]byte(encryptedPassword), []byte(password))
+	if user.Disabled {
+		ctxLogger.Warnf("security service - username %v is disabled", downcasedUsername)
+		return false
+	}
+
+	err = bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password))
 	if err != nil {
 		ctxLogger.Warnf("security service - password did not match encrypted password %v", err)
 		return false
@@ -46,3 +58,117 @@ func (s *service) ValidateCredentials(username, password string) (valid bool) {
 
 	return true
 }
+
+// GeneratePreAuthToken is called once the password of a user with two-factor authentication checks out.
+// The token names the user but has no session behind it, so it is only good for exchanging with a code.
+func (s *service) GeneratePreAuthToken(username string) (*domain.PreAuthTokenResponse, error) {
+	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
+
+	additionalClaims := map[string]string{
+		"sub":     strings.ToLower(username),
+		"purpose": preAuthPurpose,
+	}
+
+	expiresAt := time.Now().UTC().Add(s.securityConfig.PreAuthDuration)
+
+	preAuthToken, err := s.jwtService.GenerateAuthToken(additionalClaims, s.securityConfig.PreAuthDuration)
+	if err != nil {
+		ctxLogger.Errorf("security service - unable to generate pre-auth token due to %v", err)
+		return nil, serviceErrors.NewGeneralServiceError()
+	}
+
+	return &domain.PreAuthTokenResponse{
+		TwoFactorRequired:     true,
+		PreAuthToken:          preAuthToken,
+		PreAuthTokenExpiresAt: expiresAt.Format(time.RFC3339),
+	}, nil
+}
+
+// ParsePreAuthToken returns the username the pre-auth token was issued to
+func (s *service) ParsePreAuthToken(preAuthToken string) (username string, err error) {
+	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
+
+	claims, err := s.jwtService.ParseToken(preAuthToken)
+	if err != nil {
+		switch err.(type) {
+		case jwt.JWTInvalidError:
+			return "", NewPreAuthTokenInvalidError()
+		}
+
+		return "", serviceErrors.NewGeneralServiceError()
+	}
+
+	purpose, _ := claims["purpose"].(string)
+	username, _ = claims["sub"].(string)
+	if purpose != preAuthPurpose || username == "" {
+		ctxLogger.Warn("security service - token given in place of a pre-auth token was not one")
+		return "", NewPreAuthTokenInvalidError()
+	}
+
+	return username, nil
+}
+
+// ValidateTwoFactorCode accepts a code from the authenticator app of the user, or failing that one of
+// their recovery codes. Either can only be used once.
+func (s *service) ValidateTwoFactorCode(username, code string) (valid bool, err error) {
+	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
+
+	err = s.userStorage.WithTx(func(tx interfaces.Storage) error {
+		user, err := tx.FindUserByUsername(strings.ToLower(username))
+		if err != nil {
+			switch err.(type) {
+			case storage.StorageRecordNotFoundError:
+				return nil
+			}
+
+			return serviceErrors.NewGeneralServiceError()
+		}
+
+		if user.Disabled || !user.TOTPEnabled {
+			ctxLogger.Warnf("security service - username %v is disabled or has no two-factor authentication", user.Username)
+			return nil
+		}
+
+		step, totpValid := ValidateTOTPCode(user.TOTPSecret, code, time.Now(), user.TOTPLastStep)
+		switch {
+		case totpValid:
+			user.TOTPLastStep = step
+		case removeRecoveryCode(user, code):
+			ctxLogger.Warnf("security service - username %v used a recovery code, %v left", user.Username, len(user.RecoveryCodeHashes))
+		default:
+			ctxLogger.Warnf("security service - two-factor code of username %v did not match", user.Username)
+			return nil
+		}
+
+		_, err = tx.UpdateUser(user)
+		if err != nil {
+			return serviceErrors.NewGeneralServiceError()
+		}
+
+		valid = true
+		return nil
+	})
+	if err != nil {
+		return false, serviceErrors.FromTransaction(err)
+	}
+
+	return valid, nil
+}
+
+func removeRecoveryCode(user *domain.User, code string) bool {
+	codeHash := HashRecoveryCode(code)
+
+	remainingHashes := make([]string, 0, len(user.RecoveryCodeHashes))
+	for idx := range user.RecoveryCodeHashes {
+		if user.RecoveryCodeHashes[idx] != codeHash {
+			remainingHashes = append(remainingHashes, user.RecoveryCodeHashes[idx])
+		}
+	}
+
+	if len(remainingHashes) == len(user.RecoveryCodeHashes) {
+		return false
+	}
+
+	user.RecoveryCodeHashes = remainingHashes
+	return true
+}
@@ -0,0 +1,132 @@
+package security_test
+
+import (
+	"time"
+
+	"github.com/rawfish-dev/rsvp-starter/server/config"
+	"github.com/rawfish-dev/rsvp-starter/server/domain"
+	"github.com/rawfish-dev/rsvp-starter/server/interfaces"
+	"github.com/rawfish-dev/rsvp-starter/server/mock"
+	"github.com/rawfish-dev/rsvp-starter/server/services/jwt"
+	. "github.com/rawfish-dev/rsvp-starter/server/services/security"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"golang.org/x/net/context"
+)
+
+var _ = Describe("Security", func() {
+
+	var ctrl *gomock.Controller
+	var mockUserStorage *mock_interfaces.MockTransactionalStorage
+	var jwtService interfaces.JWTServiceProvider
+	var testSecurityService interfaces.SecurityServiceProvider
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+
+		ctxlogger := logrus.New()
+		ctx := context.Background()
+		ctx = context.WithValue(ctx, "logger", ctxlogger)
+
+		securityConfig := config.SecurityConfig{
+			TOTPIssuer:      "RSVP Starter",
+			PreAuthDuration: time.Minute * 5,
+		}
+
+		jwtService = jwt.NewService(ctx, config.JWTConfig{SigningKey: config.NewHMACJWTKey("some-secret"), TokenIssuer: "rsvp-starter-test"})
+		mockUserStorage = mock_interfaces.NewMockTransactionalStorage(ctrl)
+		testSecurityService = NewService(ctx, securityConfig, jwtService, mockUserStorage)
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	Context("pre-auth tokens", func() {
+
+		It("should return the username a pre-auth token was issued to", func() {
+			preAuthTokenResponse, err := testSecurityService.GeneratePreAuthToken("Kevin")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(preAuthTokenResponse.TwoFactorRequired).To(BeTrue())
+			Expect(preAuthTokenResponse.PreAuthTokenExpiresAt).ToNot(BeEmpty())
+
+			username, err := testSecurityService.ParsePreAuthToken(preAuthTokenResponse.PreAuthToken)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(username).To(Equal("kevin"))
+		})
+
+		It("should not take an auth token as a pre-auth token", func() {
+			authToken, err := jwtService.GenerateAuthToken(map[string]string{"username": "kevin", "jti": "some-session"}, time.Minute)
+			Expect(err).ToNot(HaveOccurred())
+
+			_, err = testSecurityService.ParsePreAuthToken(authToken)
+			Expect(err).To(BeAssignableToTypeOf(PreAuthTokenInvalidError{}))
+
+			_, err = testSecurityService.ParsePreAuthToken("not-a-token")
+			Expect(err).To(BeAssignableToTypeOf(PreAuthTokenInvalidError{}))
+		})
+	})
+
+	Context("two-factor codes", func() {
+
+		var secret string
+
+		BeforeEach(func() {
+			var err error
+
+			secret, err = GenerateTOTPSecret()
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should accept a code from the authenticator app and remember its step", func() {
+			code, err := GenerateTOTPCode(secret, time.Now())
+			Expect(err).ToNot(HaveOccurred())
+
+			gomock.InOrder(
+				mockUserStorage.EXPECT().FindUserByUsername("kevin").Return(&domain.User{ID: 1, Username: "kevin", TOTPSecret: secret, TOTPEnabled: true}, nil),
+				mockUserStorage.EXPECT().UpdateUser(gomock.Any()).
+					Do(func(user *domain.User) {
+						Expect(user.TOTPLastStep).ToNot(BeZero())
+					}).
+					Return(&domain.User{}, nil),
+			)
+
+			valid, err := testSecurityService.ValidateTwoFactorCode("kevin", code)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(valid).To(BeTrue())
+		})
+
+		It("should accept a recovery code only once", func() {
+			codes, hashes, err := GenerateRecoveryCodes()
+			Expect(err).ToNot(HaveOccurred())
+
+			gomock.InOrder(
+				mockUserStorage.EXPECT().FindUserByUsername("kevin").Return(&domain.User{ID: 1, Username: "kevin", TOTPSecret: secret, TOTPEnabled: true, RecoveryCodeHashes: hashes}, nil),
+				mockUserStorage.EXPECT().UpdateUser(gomock.Any()).
+					Do(func(user *domain.User) {
+						Expect(user.RecoveryCodeHashes).To(HaveLen(9))
+						Expect(user.RecoveryCodeHashes).ToNot(ContainElement(hashes[0]))
+					}).
+					Return(&domain.User{}, nil),
+			)
+
+			valid, err := testSecurityService.ValidateTwoFactorCode("kevin", codes[0])
+			Expect(err).ToNot(HaveOccurred())
+			Expect(valid).To(BeTrue())
+		})
+
+		It("should reject codes that match neither", func() {
+			gomock.InOrder(
+				mockUserStorage.EXPECT().FindUserByUsername("kevin").Return(&domain.User{ID: 1, Username: "kevin", TOTPSecret: secret, TOTPEnabled: true}, nil),
+				mockUserStorage.EXPECT().UpdateUser(gomock.Any()).Times(0),
+			)
+
+			valid, err := testSecurityService.ValidateTwoFactorCode("kevin", "not-a-code")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(valid).To(BeFalse())
+		})
+	})
+})
@@ -0,0 +1,156 @@
+package security
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// TOTP codes follow RFC 6238 with the defaults every authenticator app supports: HMAC-SHA1, six
+// digits and a thirty second time step
+const (
+	totpSecretLength = 20
+	totpDigits       = 6
+	totpPeriod       = 30
+
+	// Codes from one step either side are accepted to allow for clock drift
+	totpSkew = 1
+
+	recoveryCodeCount  = 10
+	recoveryCodeLength = 5
+)
+
+var base32NoPadding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// GenerateTOTPSecret returns a new random secret in base32, the form authenticator apps expect
+func GenerateTOTPSecret() (string, error) {
+	secretBytes := make([]byte, totpSecretLength)
+
+	_, err := rand.Read(secretBytes)
+	if err != nil {
+		return "", err
+	}
+
+	return base32NoPadding.EncodeToString(secretBytes), nil
+}
+
+// TOTPProvisioningURI returns the otpauth:// URI that authenticator apps read from a QR code
+func TOTPProvisioningURI(issuer, username, secret string) string {
+	query := url.Values{}
+	query.Set("secret", secret)
+	query.Set("issuer", issuer)
+	query.Set("algorithm", "SHA1")
+	query.Set("digits", fmt.Sprintf("%v", totpDigits))
+	query.Set("period", fmt.Sprintf("%v", totpPeriod))
+
+	provisioningURI := url.URL{
+		Scheme:   "otpauth",
+		Host:     "totp",
+		Path:     "/" + issuer + ":" + username,
+		RawQuery: query.Encode(),
+	}
+
+	return provisioningURI.String()
+}
+
+// GenerateTOTPCode returns the code for the secret at the given time
+func GenerateTOTPCode(secret string, at time.Time) (string, error) {
+	return totpCode(secret, totpStep(at))
+}
+
+// ValidateTOTPCode returns the time step the code was generated for. Steps up to and including the
+// last step that was used are rejected so that a code cannot be replayed.
+func ValidateTOTPCode(secret, code string, at time.Time, lastStep int64) (step int64, valid bool) {
+	code = strings.TrimSpace(code)
+	if len(code) != totpDigits {
+		return 0, false
+	}
+
+	currentStep := totpStep(at)
+
+	for offset := int64(-totpSkew); offset <= totpSkew; offset++ {
+		step = currentStep + offset
+		if step <= lastStep {
+			continue
+		}
+
+		expectedCode, err := totpCode(secret, step)
+		if err != nil {
+			return 0, false
+		}
+
+		if subtle.ConstantTimeCompare([]byte(code), []byte(expectedCode)) == 1 {
+			return step, true
+		}
+	}
+
+	return 0, false
+}
+
+// GenerateRecoveryCodes returns new recovery codes to show to the user along with the hashes to store
+func GenerateRecoveryCodes() (codes, hashes []string, err error) {
+	for idx := 0; idx < recoveryCodeCount; idx++ {
+		codeBytes := make([]byte, recoveryCodeLength)
+
+		_, err = rand.Read(codeBytes)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		code := hex.EncodeToString(codeBytes)
+		code = code[:recoveryCodeLength] + "-" + code[recoveryCodeLength:]
+
+		codes = append(codes, code)
+		hashes = append(hashes, HashRecoveryCode(code))
+	}
+
+	return codes, hashes, nil
+}
+
+// HashRecoveryCode ignores case, spaces and dashes so codes can be typed in however they were noted
+// down. Recovery codes are random enough that a plain hash is as good as a password hash.
+func HashRecoveryCode(code string) string {
+	normalisedCode := strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+
+	hash := sha256.Sum256([]byte(normalisedCode))
+
+	return hex.EncodeToString(hash[:])
+}
+
+func totpStep(at time.Time) int64 {
+	return at.Unix() / totpPeriod
+}
+
+func totpCode(secret string, step int64) (string, error) {
+	key, err := base32NoPadding.DecodeString(strings.ToUpper(strings.TrimRight(secret, "=")))
+	if err != nil {
+		return "", err
+	}
+
+	message := make([]byte, 8)
+	binary.BigEndian.PutUint64(message, uint64(step))
+
+	mac := hmac.New(sha1.New, key)
+	mac.Write(message)
+	sum := mac.Sum(nil)
+
+	// Dynamic truncation as described in RFC 4226
+	offset := sum[len(sum)-1] & 0x0f
+	truncated := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	modulo := uint32(1)
+	for idx := 0; idx < totpDigits; idx++ {
+		modulo *= 10
+	}
+
+	return fmt.Sprintf("%0*d", totpDigits, truncated%modulo), nil
+}
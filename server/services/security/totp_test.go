@@ -0,0 +1,85 @@
+package security_test
+
+import (
+	"strings"
+	"time"
+
+	. "github.com/rawfish-dev/rsvp-starter/server/services/security"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("TOTP", func() {
+
+	// The SHA1 secret from the test vectors in RFC 6238, in base32
+	const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+	It("should generate the codes of the RFC 6238 test vectors", func() {
+		// The RFC lists eight digit codes, of which six digit codes are the last six
+		testVectors := map[int64]string{
+			59:         "287082",
+			1111111109: "081804",
+			1111111111: "050471",
+			1234567890: "005924",
+			2000000000: "279037",
+		}
+
+		for unixTime, expectedCode := range testVectors {
+			code, err := GenerateTOTPCode(rfcSecret, time.Unix(unixTime, 0))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(code).To(Equal(expectedCode))
+		}
+	})
+
+	It("should accept codes from the step either side of the current one", func() {
+		now := time.Unix(1234567890, 0)
+
+		for _, offset := range []time.Duration{-30 * time.Second, 0, 30 * time.Second} {
+			code, err := GenerateTOTPCode(rfcSecret, now.Add(offset))
+			Expect(err).ToNot(HaveOccurred())
+
+			_, valid := ValidateTOTPCode(rfcSecret, code, now, 0)
+			Expect(valid).To(BeTrue())
+		}
+
+		code, err := GenerateTOTPCode(rfcSecret, now.Add(-90*time.Second))
+		Expect(err).ToNot(HaveOccurred())
+
+		_, valid := ValidateTOTPCode(rfcSecret, code, now, 0)
+		Expect(valid).To(BeFalse())
+	})
+
+	It("should not accept a code for a step that has already been used", func() {
+		now := time.Unix(1234567890, 0)
+
+		code, err := GenerateTOTPCode(rfcSecret, now)
+		Expect(err).ToNot(HaveOccurred())
+
+		step, valid := ValidateTOTPCode(rfcSecret, code, now, 0)
+		Expect(valid).To(BeTrue())
+
+		_, valid = ValidateTOTPCode(rfcSecret, code, now, step)
+		Expect(valid).To(BeFalse())
+	})
+
+	It("should build a provisioning uri for authenticator apps", func() {
+		provisioningURI := TOTPProvisioningURI("RSVP Starter", "kevin", "ABCDEF")
+
+		Expect(provisioningURI).To(HavePrefix("otpauth://totp/RSVP%20Starter:kevin?"))
+		Expect(provisioningURI).To(ContainSubstring("secret=ABCDEF"))
+		Expect(provisioningURI).To(ContainSubstring("issuer=RSVP+Starter"))
+	})
+
+	It("should generate recovery codes whose hashes ignore case and dashes", func() {
+		codes, hashes, err := GenerateRecoveryCodes()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(codes).To(HaveLen(10))
+		Expect(hashes).To(HaveLen(10))
+
+		for idx := range codes {
+			Expect(hashes[idx]).ToNot(Equal(codes[idx]))
+			Expect(HashRecoveryCode(strings.ToUpper(strings.Replace(codes[idx], "-", "", -1)))).To(Equal(hashes[idx]))
+		}
+	})
+})
@@ -0,0 +1,27 @@
+package session
+
+var _ error = new(SessionNotFoundError)
+
+type SessionNotFoundError struct {
+}
+
+func NewSessionNotFoundError() error {
+	return SessionNotFoundError{}
+}
+
+func (s SessionNotFoundError) Error() string {
+	return "session not found"
+}
+
+var _ error = new(RefreshTokenInvalidError)
+
+type RefreshTokenInvalidError struct {
+}
+
+func NewRefreshTokenInvalidError() error {
+	return RefreshTokenInvalidError{}
+}
+
+func (r RefreshTokenInvalidError) Error() string {
+	return "refresh token is invalid"
+}
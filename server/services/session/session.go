@@ -1,10 +1,22 @@
 package session
 
 import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
 	"github.com/rawfish-dev/rsvp-starter/server/config"
+	"github.com/rawfish-dev/rsvp-starter/server/domain"
 	"github.com/rawfish-dev/rsvp-starter/server/interfaces"
 	serviceErrors "github.com/rawfish-dev/rsvp-starter/server/services/errors"
 	"github.com/rawfish-dev/rsvp-starter/server/services/jwt"
+	"github.com/rawfish-dev/rsvp-starter/server/services/storage"
 
 	"golang.org/x/net/context"
 )
@@ -16,35 +28,139 @@ type service struct {
 	sessionConfig config.SessionConfig
 	jwtService    interfaces.JWTServiceProvider
 	cacheService  interfaces.CacheServiceProvider
+	userStorage   interfaces.Storage
 }
 
 func NewService(ctx context.Context,
 	sessionConfig config.SessionConfig,
 	jwtService interfaces.JWTServiceProvider,
-	cacheService interfaces.CacheServiceProvider) *service {
-	return &service{ctx, sessionConfig, jwtService, cacheService}
+	cacheService interfaces.CacheServiceProvider,
+	userStorage interfaces.Storage) *service {
+	return &service{ctx, sessionConfig, jwtService, cacheService, userStorage}
+}
+
+// sessionRecord is what the cache keeps for each session. Only a hash of the refresh token is kept so
+// it cannot be read back from the cache.
+type sessionRecord struct {
+	domain.Session
+	Role             domain.Role `json:"role"`
+	RefreshTokenHash string      `json:"refreshTokenHash"`
 }
 
-func (s *service) CreateWithExpiry(username string) (authToken string, err error) {
+// CreateWithExpiry starts a new session alongside any others of the same user. Each session is kept in
+// the cache under its own id, which is also the jti claim of its auth tokens.
+func (s *service) CreateWithExpiry(username string, role domain.Role) (tokens *domain.SessionTokens, err error) {
 	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
 
-	// Store username as an additional claim
-	additionalClaims := make(map[string]string)
-	additionalClaims["username"] = username
+	sessionID, err := newRandomString()
+	if err != nil {
+		ctxLogger.Errorf("session service - unable to generate session id due to %v", err)
+		return nil, serviceErrors.NewGeneralServiceError()
+	}
 
-	authToken, err = s.jwtService.GenerateAuthToken(additionalClaims, s.sessionConfig.Duration)
+	record := &sessionRecord{
+		Session: domain.Session{
+			ID:        sessionID,
+			Username:  username,
+			CreatedAt: time.Now().UTC().Format(time.RFC3339),
+		},
+		Role: role,
+	}
+
+	return s.issueTokens(record)
+}
+
+// Refresh exchanges a refresh token for new tokens of the same session. A refresh token that was
+// already used ends the session since either the user or someone who copied it is holding a newer one.
+func (s *service) Refresh(refreshToken string) (tokens *domain.SessionTokens, err error) {
+	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
+
+	parts := strings.Split(refreshToken, ":")
+	if len(parts) != 3 {
+		ctxLogger.Warn("session service - refresh token was malformed")
+		return nil, NewRefreshTokenInvalidError()
+	}
+	username, sessionID, secret := parts[0], parts[1], parts[2]
+
+	record, err := s.findRecord(username, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if record == nil {
+		ctxLogger.Warnf("session service - unable to refresh session %v of %v as it has ended", sessionID, username)
+		return nil, NewRefreshTokenInvalidError()
+	}
+
+	if subtle.ConstantTimeCompare([]byte(hashSecret(secret)), []byte(record.RefreshTokenHash)) != 1 {
+		ctxLogger.Warnf("session service - refresh token of session %v of %v was reused so the session is ended", sessionID, username)
+		s.cacheService.Delete(sessionKey(username, sessionID))
+		return nil, NewRefreshTokenInvalidError()
+	}
+
+	err = s.reloadRole(record)
+	if err != nil {
+		switch err.(type) {
+		case SessionNotFoundError:
+			return nil, NewRefreshTokenInvalidError()
+		}
+
+		return nil, err
+	}
+
+	return s.issueTokens(record)
+}
+
+// Renew returns a new auth token for the same session once the given one is past half of its lifetime,
+// or nothing while it is still fresh. It also keeps the session from expiring while it is in use. Sessions
+// of users who have since been disabled are ended instead.
+func (s *service) Renew(authToken string) (renewedAuthToken string, err error) {
+	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
+
+	claims, err := s.jwtService.ParseToken(authToken)
+	if err != nil {
+		ctxLogger.Errorf("session service - unable to parse auth token due to %v", err)
+		return "", serviceErrors.NewGeneralServiceError()
+	}
+
+	expiry, ok := claims["exp"].(float64)
+	if !ok {
+		ctxLogger.Error("session service - could not find expiry claim in auth token")
+		return "", serviceErrors.NewGeneralServiceError()
+	}
+	if time.Until(time.Unix(int64(expiry), 0)) > s.sessionConfig.Duration/2 {
+		return "", nil
+	}
+
+	username, sessionID, ok := sessionClaims(claims)
+	if !ok {
+		ctxLogger.Error("session service - could not find username or session id claims in auth token")
+		return "", serviceErrors.NewGeneralServiceError()
+	}
+
+	record, err := s.findRecord(username, sessionID)
+	if err != nil {
+		return "", err
+	}
+	if record == nil {
+		return "", nil
+	}
+
+	err = s.reloadRole(record)
 	if err != nil {
 		return "", err
 	}
 
-	expiryInSeconds := int(s.sessionConfig.Duration.Seconds())
-	err = s.cacheService.SetWithExpiry(username, authToken, expiryInSeconds)
+	renewedAuthToken, _, err = s.generateAuthToken(record)
 	if err != nil {
-		ctxLogger.Errorf("session service - unable to set auth token with expiry in cache due to %v", err)
 		return "", err
 	}
 
-	return authToken, nil
+	err = s.saveRecord(record)
+	if err != nil {
+		return "", err
+	}
+
+	return renewedAuthToken, nil
 }
 
 func (s *service) IsSessionValid(authToken string) (valid bool, err error) {
@@ -62,42 +178,331 @@ func (s *service) IsSessionValid(authToken string) (valid bool, err error) {
 		return false, serviceErrors.NewGeneralServiceError()
 	}
 
-	username, ok := claims["username"]
+	username, sessionID, ok := sessionClaims(claims)
 	if !ok {
-		ctxLogger.Error("session service - could not find username claim in auth token")
+		ctxLogger.Warn("session service - could not find username or session id claims in auth token")
 		return false, nil
 	}
 
-	exists, err := s.cacheService.Exists(username.(string))
+	exists, err := s.cacheService.Exists(sessionKey(username, sessionID))
 	if err != nil {
-		ctxLogger.Errorf("session service - unable to check if session is active for %v due to %v", username, err)
+		ctxLogger.Errorf("session service - unable to check if session %v is active for %v due to %v", sessionID, username, err)
 		return false, serviceErrors.NewGeneralServiceError()
 	}
+
+	return exists, nil
+}
+
+func (s *service) Username(authToken string) (username string, err error) {
+	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
+
+	claims, err := s.jwtService.ParseToken(authToken)
+	if err != nil {
+		ctxLogger.Errorf("session service - unable to parse auth token due to %v", err)
+		return "", serviceErrors.NewGeneralServiceError()
+	}
+
+	claim, ok := claims["username"].(string)
+	if !ok {
+		ctxLogger.Error("session service - could not find username claim in auth token")
+		return "", serviceErrors.NewGeneralServiceError()
+	}
+
+	return claim, nil
+}
+
+// Role returns an empty role for tokens issued before roles existed, which are then refused by every
+// route that checks for one
+func (s *service) Role(authToken string) (role domain.Role, err error) {
+	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
+
+	claims, err := s.jwtService.ParseToken(authToken)
+	if err != nil {
+		ctxLogger.Errorf("session service - unable to parse auth token due to %v", err)
+		return "", serviceErrors.NewGeneralServiceError()
+	}
+
+	claim, ok := claims["role"].(string)
+	if !ok {
+		ctxLogger.Warn("session service - could not find role claim in auth token")
+		return "", nil
+	}
+
+	return domain.Role(claim), nil
+}
+
+// ListSessions returns the active sessions of the user the auth token belongs to, oldest first
+func (s *service) ListSessions(authToken string) (sessions []domain.Session, err error) {
+	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
+
+	username, currentSessionID, err := s.parseSessionClaims(authToken)
+	if err != nil {
+		return nil, err
+	}
+
+	keys, err := s.cacheService.Keys(sessionKey(username, ""))
+	if err != nil {
+		ctxLogger.Errorf("session service - unable to list session keys for %v due to %v", username, err)
+		return nil, serviceErrors.NewGeneralServiceError()
+	}
+
+	sessions = make([]domain.Session, 0, len(keys))
+	for _, key := range keys {
+		value, err := s.cacheService.Get(key)
+		if err != nil {
+			ctxLogger.Errorf("session service - unable to get session %v due to %v", key, err)
+			return nil, serviceErrors.NewGeneralServiceError()
+		}
+
+		// The session expired after its key was listed
+		if value == "" {
+			continue
+		}
+
+		var record sessionRecord
+		err = json.Unmarshal([]byte(value), &record)
+		if err != nil {
+			ctxLogger.Errorf("session service - unable to unmarshal session %v due to %v", key, err)
+			return nil, serviceErrors.NewGeneralServiceError()
+		}
+
+		session := record.Session
+		session.Current = session.ID == currentSessionID
+		sessions = append(sessions, session)
+	}
+
+	sort.Sort(sessionsByCreatedAt(sessions))
+
+	return sessions, nil
+}
+
+// Revoke ends one of the sessions of the user the auth token belongs to, which can be the current one
+func (s *service) Revoke(authToken, sessionID string) (err error) {
+	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
+
+	username, _, err := s.parseSessionClaims(authToken)
+	if err != nil {
+		return err
+	}
+
+	key := sessionKey(username, sessionID)
+
+	exists, err := s.cacheService.Exists(key)
+	if err != nil {
+		ctxLogger.Errorf("session service - unable to check if session %v is active for %v due to %v", sessionID, username, err)
+		return serviceErrors.NewGeneralServiceError()
+	}
 	if !exists {
-		return false, nil
+		ctxLogger.Warnf("session service - unable to revoke session %v of %v as it does not exist", sessionID, username)
+		return NewSessionNotFoundError()
 	}
 
-	// TODO:: Consider logins from multiple browsers causing multiple auth tokens
+	return s.cacheService.Delete(key)
+}
+
+// RevokeAll ends every session of the user, for when their account is disabled or its password or role
+// changes
+func (s *service) RevokeAll(username string) (err error) {
+	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
 
-	return true, nil
+	keys, err := s.cacheService.Keys(sessionKey(username, ""))
+	if err != nil {
+		ctxLogger.Errorf("session service - unable to list session keys for %v due to %v", username, err)
+		return serviceErrors.NewGeneralServiceError()
+	}
+
+	for _, key := range keys {
+		err = s.cacheService.Delete(key)
+		if err != nil {
+			ctxLogger.Errorf("session service - unable to delete session %v due to %v", key, err)
+			return serviceErrors.NewGeneralServiceError()
+		}
+	}
+
+	return nil
 }
 
+// Destroy ends the session of the auth token and leaves other sessions of the same user alone
 func (s *service) Destroy(authToken string) (err error) {
+	username, sessionID, err := s.parseSessionClaims(authToken)
+	if err != nil {
+		return err
+	}
+
+	return s.cacheService.Delete(sessionKey(username, sessionID))
+}
+
+// reloadRole takes the role of the session from the user as they are now so tokens handed out for an
+// existing session pick up role changes. The session is ended when the user has been disabled or removed.
+func (s *service) reloadRole(record *sessionRecord) error {
+	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
+
+	user, err := s.userStorage.FindUserByUsername(record.Username)
+	if err != nil {
+		switch err.(type) {
+		case storage.StorageRecordNotFoundError:
+			user = nil
+		default:
+			ctxLogger.Errorf("session service - unable to find user %v due to %v", record.Username, err)
+			return serviceErrors.NewGeneralServiceError()
+		}
+	}
+
+	if user == nil || user.Disabled {
+		ctxLogger.Warnf("session service - ending session %v of %v as the user is disabled or no longer exists", record.ID, record.Username)
+		s.cacheService.Delete(sessionKey(record.Username, record.ID))
+		return NewSessionNotFoundError()
+	}
+
+	record.Role = user.Role
+	return nil
+}
+
+// issueTokens hands out a new auth token and a new refresh token for the session and saves it, which
+// pushes back when it expires
+func (s *service) issueTokens(record *sessionRecord) (*domain.SessionTokens, error) {
+	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
+
+	authToken, authTokenExpiresAt, err := s.generateAuthToken(record)
+	if err != nil {
+		return nil, err
+	}
+
+	secret, err := newRandomString()
+	if err != nil {
+		ctxLogger.Errorf("session service - unable to generate refresh token due to %v", err)
+		return nil, serviceErrors.NewGeneralServiceError()
+	}
+	record.RefreshTokenHash = hashSecret(secret)
+
+	err = s.saveRecord(record)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.SessionTokens{
+		AuthToken:          authToken,
+		AuthTokenExpiresAt: authTokenExpiresAt.Format(time.RFC3339),
+		RefreshToken:       fmt.Sprintf("%v:%v:%v", record.Username, record.ID, secret),
+	}, nil
+}
+
+func (s *service) generateAuthToken(record *sessionRecord) (authToken string, expiresAt time.Time, err error) {
+	// Store username, role and session id as additional claims
+	additionalClaims := make(map[string]string)
+	additionalClaims["username"] = record.Username
+	additionalClaims["role"] = string(record.Role)
+	additionalClaims["jti"] = record.ID
+
+	expiresAt = time.Now().UTC().Add(s.sessionConfig.Duration)
+
+	authToken, err = s.jwtService.GenerateAuthToken(additionalClaims, s.sessionConfig.Duration)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	return authToken, expiresAt, nil
+}
+
+func (s *service) saveRecord(record *sessionRecord) error {
+	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
+
+	record.Current = false
+	record.ExpiresAt = time.Now().UTC().Add(s.sessionConfig.RefreshDuration).Format(time.RFC3339)
+
+	recordBytes, err := json.Marshal(record)
+	if err != nil {
+		ctxLogger.Errorf("session service - unable to marshal session due to %v", err)
+		return serviceErrors.NewGeneralServiceError()
+	}
+
+	expiryInSeconds := int(s.sessionConfig.RefreshDuration.Seconds())
+	err = s.cacheService.SetWithExpiry(sessionKey(record.Username, record.ID), string(recordBytes), expiryInSeconds)
+	if err != nil {
+		ctxLogger.Errorf("session service - unable to set session with expiry in cache due to %v", err)
+		return serviceErrors.NewGeneralServiceError()
+	}
+
+	return nil
+}
+
+// findRecord returns nothing without an error for sessions that have ended
+func (s *service) findRecord(username, sessionID string) (*sessionRecord, error) {
+	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
+
+	value, err := s.cacheService.Get(sessionKey(username, sessionID))
+	if err != nil {
+		ctxLogger.Errorf("session service - unable to get session %v of %v due to %v", sessionID, username, err)
+		return nil, serviceErrors.NewGeneralServiceError()
+	}
+	if value == "" {
+		return nil, nil
+	}
+
+	var record sessionRecord
+	err = json.Unmarshal([]byte(value), &record)
+	if err != nil {
+		ctxLogger.Errorf("session service - unable to unmarshal session %v of %v due to %v", sessionID, username, err)
+		return nil, serviceErrors.NewGeneralServiceError()
+	}
+
+	return &record, nil
+}
+
+func (s *service) parseSessionClaims(authToken string) (username, sessionID string, err error) {
 	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
 
 	claims, err := s.jwtService.ParseToken(authToken)
 	if err != nil {
 		ctxLogger.Errorf("session service - unable to parse auth token due to %v", err)
-		return
+		return "", "", serviceErrors.NewGeneralServiceError()
 	}
 
-	username, ok := claims["username"]
+	username, sessionID, ok := sessionClaims(claims)
 	if !ok {
-		ctxLogger.Error("session service - could not find username claim in auth token")
-		return
+		ctxLogger.Error("session service - could not find username or session id claims in auth token")
+		return "", "", serviceErrors.NewGeneralServiceError()
 	}
 
-	s.cacheService.Delete(username.(string))
+	return username, sessionID, nil
+}
 
-	return nil
+func sessionClaims(claims map[string]interface{}) (username, sessionID string, ok bool) {
+	username, usernameOK := claims["username"].(string)
+	sessionID, sessionIDOK := claims["jti"].(string)
+
+	return username, sessionID, usernameOK && sessionIDOK && username != "" && sessionID != ""
+}
+
+// sessionKey namespaces sessions by username so those of one user can be listed by prefix
+func sessionKey(username, sessionID string) string {
+	return fmt.Sprintf("session:%v:%v", username, sessionID)
+}
+
+func hashSecret(secret string) string {
+	hash := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(hash[:])
+}
+
+func newRandomString() (string, error) {
+	randomBytes := make([]byte, 16)
+
+	_, err := rand.Read(randomBytes)
+	if err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(randomBytes), nil
+}
+
+type sessionsByCreatedAt []domain.Session
+
+func (b sessionsByCreatedAt) Len() int      { return len(b) }
+func (b sessionsByCreatedAt) Swap(i, j int) { b[i], b[j] = b[j], b[i] }
+func (b sessionsByCreatedAt) Less(i, j int) bool {
+	if b[i].CreatedAt == b[j].CreatedAt {
+		return b[i].ID < b[j].ID
+	}
+
+	return b[i].CreatedAt < b[j].CreatedAt
 }
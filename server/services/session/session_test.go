@@ -0,0 +1,292 @@
+package session_test
+
+import (
+	"time"
+
+	"github.com/rawfish-dev/rsvp-starter/server/config"
+	"github.com/rawfish-dev/rsvp-starter/server/domain"
+	"github.com/rawfish-dev/rsvp-starter/server/interfaces"
+	"github.com/rawfish-dev/rsvp-starter/server/services/cache"
+	"github.com/rawfish-dev/rsvp-starter/server/services/jwt"
+	"github.com/rawfish-dev/rsvp-starter/server/services/memory"
+	. "github.com/rawfish-dev/rsvp-starter/server/services/session"
+
+	"github.com/Sirupsen/logrus"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"golang.org/x/net/context"
+)
+
+var _ = Describe("Session", func() {
+
+	var testSessionService interfaces.SessionServiceProvider
+	var newSessionService func(sessionConfig config.SessionConfig) interfaces.SessionServiceProvider
+	var userStorage interfaces.Storage
+	var removeUsers func()
+	var kevin *domain.User
+
+	BeforeEach(func() {
+		ctxlogger := logrus.New()
+		ctx := context.Background()
+		ctx = context.WithValue(ctx, "logger", ctxlogger)
+
+		jwtService := jwt.NewService(ctx, config.JWTConfig{SigningKey: config.NewHMACJWTKey("some-secret-hmac"), TokenIssuer: "rsvp-starter-test"})
+		cacheService := cache.NewService(ctx)
+		Expect(cacheService.Flush()).To(Succeed())
+
+		memoryService := memory.NewService(ctx)
+		memoryService.Flush()
+		userStorage = memoryService
+		removeUsers = memoryService.Flush
+
+		var err error
+		kevin, err = userStorage.InsertUser(&domain.User{Username: "kevin", PasswordHash: "some-hash", Role: domain.RoleOwner})
+		Expect(err).ToNot(HaveOccurred())
+
+		newSessionService = func(sessionConfig config.SessionConfig) interfaces.SessionServiceProvider {
+			return NewService(ctx, sessionConfig, jwtService, cacheService, userStorage)
+		}
+		testSessionService = newSessionService(config.SessionConfig{Duration: time.Minute, RefreshDuration: time.Hour})
+	})
+
+	updateKevin := func(update func(user *domain.User)) {
+		update(kevin)
+
+		var err error
+		kevin, err = userStorage.UpdateUser(kevin)
+		Expect(err).ToNot(HaveOccurred())
+	}
+
+	login := func(username string, role domain.Role) string {
+		tokens, err := testSessionService.CreateWithExpiry(username, role)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(tokens.RefreshToken).ToNot(BeEmpty())
+
+		return tokens.AuthToken
+	}
+
+	It("should keep sessions from several browsers valid at the same time", func() {
+		firstToken := login("kevin", domain.RoleOwner)
+
+		secondToken := login("kevin", domain.RoleOwner)
+		Expect(secondToken).ToNot(Equal(firstToken))
+
+		for _, authToken := range []string{firstToken, secondToken} {
+			valid, err := testSessionService.IsSessionValid(authToken)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(valid).To(BeTrue())
+		}
+	})
+
+	It("should only end the session that logs out", func() {
+		firstToken := login("kevin", domain.RoleOwner)
+
+		secondToken := login("kevin", domain.RoleOwner)
+
+		Expect(testSessionService.Destroy(firstToken)).To(Succeed())
+
+		valid, err := testSessionService.IsSessionValid(firstToken)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(valid).To(BeFalse())
+
+		valid, err = testSessionService.IsSessionValid(secondToken)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(valid).To(BeTrue())
+	})
+
+	It("should list the sessions of the same user and mark the current one", func() {
+		firstToken := login("kevin", domain.RoleOwner)
+
+		login("kevin", domain.RoleOwner)
+
+		login("jenny", domain.RoleEditor)
+
+		sessions, err := testSessionService.ListSessions(firstToken)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(sessions).To(HaveLen(2))
+
+		currentSessions := 0
+		for _, session := range sessions {
+			Expect(session.Username).To(Equal("kevin"))
+			Expect(session.ID).ToNot(BeEmpty())
+			Expect(session.ExpiresAt).ToNot(BeEmpty())
+			if session.Current {
+				currentSessions++
+			}
+		}
+		Expect(currentSessions).To(Equal(1))
+	})
+
+	It("should revoke another session of the same user", func() {
+		firstToken := login("kevin", domain.RoleOwner)
+
+		secondToken := login("kevin", domain.RoleOwner)
+
+		sessions, err := testSessionService.ListSessions(secondToken)
+		Expect(err).ToNot(HaveOccurred())
+
+		for _, session := range sessions {
+			if !session.Current {
+				Expect(testSessionService.Revoke(secondToken, session.ID)).To(Succeed())
+			}
+		}
+
+		valid, err := testSessionService.IsSessionValid(firstToken)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(valid).To(BeFalse())
+
+		valid, err = testSessionService.IsSessionValid(secondToken)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(valid).To(BeTrue())
+	})
+
+	It("should not revoke sessions of other users", func() {
+		kevinToken := login("kevin", domain.RoleOwner)
+
+		jennyToken := login("jenny", domain.RoleEditor)
+
+		jennySessions, err := testSessionService.ListSessions(jennyToken)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(jennySessions).To(HaveLen(1))
+
+		err = testSessionService.Revoke(kevinToken, jennySessions[0].ID)
+		Expect(err).To(BeAssignableToTypeOf(SessionNotFoundError{}))
+
+		valid, err := testSessionService.IsSessionValid(jennyToken)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(valid).To(BeTrue())
+	})
+
+	Context("refreshing", func() {
+
+		It("should hand out new tokens for the same session and rotate the refresh token", func() {
+			tokens, err := testSessionService.CreateWithExpiry("kevin", domain.RoleOwner)
+			Expect(err).ToNot(HaveOccurred())
+
+			refreshedTokens, err := testSessionService.Refresh(tokens.RefreshToken)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(refreshedTokens.RefreshToken).ToNot(Equal(tokens.RefreshToken))
+
+			valid, err := testSessionService.IsSessionValid(refreshedTokens.AuthToken)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(valid).To(BeTrue())
+
+			role, err := testSessionService.Role(refreshedTokens.AuthToken)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(role).To(Equal(domain.RoleOwner))
+
+			sessions, err := testSessionService.ListSessions(refreshedTokens.AuthToken)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(sessions).To(HaveLen(1))
+			Expect(sessions[0].Current).To(BeTrue())
+		})
+
+		It("should end the session when a used refresh token is used again", func() {
+			tokens, err := testSessionService.CreateWithExpiry("kevin", domain.RoleOwner)
+			Expect(err).ToNot(HaveOccurred())
+
+			refreshedTokens, err := testSessionService.Refresh(tokens.RefreshToken)
+			Expect(err).ToNot(HaveOccurred())
+
+			_, err = testSessionService.Refresh(tokens.RefreshToken)
+			Expect(err).To(BeAssignableToTypeOf(RefreshTokenInvalidError{}))
+
+			_, err = testSessionService.Refresh(refreshedTokens.RefreshToken)
+			Expect(err).To(BeAssignableToTypeOf(RefreshTokenInvalidError{}))
+
+			valid, err := testSessionService.IsSessionValid(refreshedTokens.AuthToken)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(valid).To(BeFalse())
+		})
+
+		It("should not refresh sessions that were logged out or malformed refresh tokens", func() {
+			tokens, err := testSessionService.CreateWithExpiry("kevin", domain.RoleOwner)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(testSessionService.Destroy(tokens.AuthToken)).To(Succeed())
+
+			_, err = testSessionService.Refresh(tokens.RefreshToken)
+			Expect(err).To(BeAssignableToTypeOf(RefreshTokenInvalidError{}))
+
+			_, err = testSessionService.Refresh("not-a-refresh-token")
+			Expect(err).To(BeAssignableToTypeOf(RefreshTokenInvalidError{}))
+		})
+
+		It("should end the session instead of refreshing it once the user is disabled", func() {
+			tokens, err := testSessionService.CreateWithExpiry("kevin", domain.RoleOwner)
+			Expect(err).ToNot(HaveOccurred())
+
+			updateKevin(func(user *domain.User) { user.Disabled = true })
+
+			_, err = testSessionService.Refresh(tokens.RefreshToken)
+			Expect(err).To(BeAssignableToTypeOf(RefreshTokenInvalidError{}))
+
+			valid, err := testSessionService.IsSessionValid(tokens.AuthToken)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(valid).To(BeFalse())
+		})
+
+		It("should end the session instead of refreshing it once the user no longer exists", func() {
+			tokens, err := testSessionService.CreateWithExpiry("kevin", domain.RoleOwner)
+			Expect(err).ToNot(HaveOccurred())
+
+			removeUsers()
+
+			_, err = testSessionService.Refresh(tokens.RefreshToken)
+			Expect(err).To(BeAssignableToTypeOf(RefreshTokenInvalidError{}))
+		})
+
+		It("should hand out tokens with the current role of the user", func() {
+			tokens, err := testSessionService.CreateWithExpiry("kevin", domain.RoleOwner)
+			Expect(err).ToNot(HaveOccurred())
+
+			updateKevin(func(user *domain.User) { user.Role = domain.RoleViewer })
+
+			refreshedTokens, err := testSessionService.Refresh(tokens.RefreshToken)
+			Expect(err).ToNot(HaveOccurred())
+
+			role, err := testSessionService.Role(refreshedTokens.AuthToken)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(role).To(Equal(domain.RoleViewer))
+		})
+	})
+
+	Context("renewing", func() {
+
+		It("should not renew auth tokens that are still fresh", func() {
+			authToken := login("kevin", domain.RoleOwner)
+
+			renewedAuthToken, err := testSessionService.Renew(authToken)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(renewedAuthToken).To(BeEmpty())
+		})
+
+		It("should renew auth tokens past half of their lifetime", func() {
+			authToken := login("kevin", domain.RoleOwner)
+
+			// A minute long token is past half the lifetime of tokens lasting three minutes
+			longerSessionService := newSessionService(config.SessionConfig{Duration: time.Minute * 3, RefreshDuration: time.Hour})
+
+			renewedAuthToken, err := longerSessionService.Renew(authToken)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(renewedAuthToken).ToNot(BeEmpty())
+
+			valid, err := longerSessionService.IsSessionValid(renewedAuthToken)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(valid).To(BeTrue())
+		})
+
+		It("should end the session instead of renewing it once the user is disabled", func() {
+			authToken := login("kevin", domain.RoleOwner)
+			longerSessionService := newSessionService(config.SessionConfig{Duration: time.Minute * 3, RefreshDuration: time.Hour})
+
+			updateKevin(func(user *domain.User) { user.Disabled = true })
+
+			_, err := longerSessionService.Renew(authToken)
+			Expect(err).To(BeAssignableToTypeOf(SessionNotFoundError{}))
+
+			valid, err := longerSessionService.IsSessionValid(authToken)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(valid).To(BeFalse())
+		})
+	})
+})
@@ -0,0 +1,208 @@
+package sqlite
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rawfish-dev/rsvp-starter/server/domain"
+	"github.com/rawfish-dev/rsvp-starter/server/interfaces"
+	"github.com/rawfish-dev/rsvp-starter/server/services/storage"
+
+	"gopkg.in/gorp.v1"
+)
+
+// apiKey is revoked rather than moved to the trash. Scopes are joined by commas as none contain one.
+type apiKey struct {
+	ID         int64      `db:"id"`
+	Name       string     `db:"name"`
+	Prefix     string     `db:"prefix"`
+	KeyHash    string     `db:"key_hash"`
+	Scopes     string     `db:"scopes"`
+	CreatedBy  string     `db:"created_by"`
+	ExpiresAt  *time.Time `db:"expires_at"`
+	LastUsedAt *time.Time `db:"last_used_at"`
+	RevokedAt  *time.Time `db:"revoked_at"`
+	CreatedAt  time.Time  `db:"created_at"`
+}
+
+var apiKeyColumns = strings.Join([]string{
+	"id",
+	"name",
+	"prefix",
+	"key_hash",
+	"scopes",
+	"created_by",
+	"expires_at",
+	"last_used_at",
+	"revoked_at",
+	"created_at",
+}, ",")
+
+func (a *apiKey) PreInsert(s gorp.SqlExecutor) error {
+	a.CreatedAt = time.Now().UTC()
+	return nil
+}
+
+func (a *apiKey) toDomain() domain.APIKey {
+	return domain.APIKey{
+		ID:         a.ID,
+		Name:       a.Name,
+		Prefix:     a.Prefix,
+		KeyHash:    a.KeyHash,
+		Scopes:     splitScopes(a.Scopes),
+		CreatedBy:  a.CreatedBy,
+		ExpiresAt:  formatOptionalTime(a.ExpiresAt),
+		LastUsedAt: formatOptionalTime(a.LastUsedAt),
+		RevokedAt:  formatOptionalTime(a.RevokedAt),
+		CreatedAt:  a.CreatedAt.UTC().Format(time.RFC3339),
+	}
+}
+
+func formatOptionalTime(timestamp *time.Time) string {
+	if timestamp == nil {
+		return ""
+	}
+
+	return timestamp.UTC().Format(time.RFC3339)
+}
+
+func joinScopes(scopes []domain.Scope) string {
+	joinedScopes := make([]string, len(scopes))
+	for idx, scope := range scopes {
+		joinedScopes[idx] = string(scope)
+	}
+
+	return strings.Join(joinedScopes, ",")
+}
+
+func splitScopes(joinedScopes string) []domain.Scope {
+	if joinedScopes == "" {
+		return nil
+	}
+
+	var scopes []domain.Scope
+	for _, scope := range strings.Split(joinedScopes, ",") {
+		scopes = append(scopes, domain.Scope(scope))
+	}
+
+	return scopes
+}
+
+func (s *service) InsertAPIKey(domainAPIKey *domain.APIKey) (*domain.APIKey, error) {
+	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
+
+	expiresAt, err := storage.ParseOptionalTimestamp(domainAPIKey.ExpiresAt)
+	if err != nil {
+		ctxLogger.Errorf("sqlite service - unable to insert api key due to %v", err)
+		return nil, storage.NewStorageOperationError()
+	}
+
+	apiKey := &apiKey{
+		Name:      domainAPIKey.Name,
+		Prefix:    domainAPIKey.Prefix,
+		KeyHash:   domainAPIKey.KeyHash,
+		Scopes:    joinScopes(domainAPIKey.Scopes),
+		CreatedBy: domainAPIKey.CreatedBy,
+		ExpiresAt: expiresAt,
+	}
+
+	err = s.executor.Insert(apiKey)
+	if err != nil {
+		ctxLogger.Errorf("sqlite service - unable to insert api key due to %v", err)
+		return nil, storage.NewStorageOperationError()
+	}
+
+	newAPIKey := apiKey.toDomain()
+
+	return &newAPIKey, nil
+}
+
+func (s *service) FindAPIKeyByID(apiKeyID int64) (*domain.APIKey, error) {
+	return s.findAPIKey("id", apiKeyID)
+}
+
+func (s *service) FindAPIKeyByHash(keyHash string) (*domain.APIKey, error) {
+	return s.findAPIKey("key_hash", keyHash)
+}
+
+func (s *service) findAPIKey(column string, value interface{}) (*domain.APIKey, error) {
+	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
+
+	query := fmt.Sprintf(`
+		SELECT %v
+		FROM api_keys
+		WHERE %v=?
+	`, apiKeyColumns, column)
+
+	var apiKey apiKey
+
+	err := s.executor.SelectOne(&apiKey, query, value)
+	if err != nil {
+		if isNotFoundError(err) {
+			ctxLogger.Warnf("sqlite service - unable to find api key by %v", column)
+			return nil, storage.NewStorageRecordNotFoundError()
+		}
+
+		ctxLogger.Errorf("sqlite service - unable to find api key by %v due to %v", column, err)
+		return nil, storage.NewStorageOperationError()
+	}
+
+	domainAPIKey := apiKey.toDomain()
+
+	return &domainAPIKey, nil
+}
+
+func (s *service) ListAPIKeys() ([]domain.APIKey, error) {
+	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
+
+	query := fmt.Sprintf(`
+		SELECT %v
+		FROM api_keys
+		ORDER BY id
+	`, apiKeyColumns)
+
+	var apiKeys []apiKey
+
+	_, err := s.executor.Select(&apiKeys, query)
+	if err != nil {
+		ctxLogger.Errorf("sqlite service - unable to retrieve api keys due to %v", err)
+		return nil, storage.NewStorageOperationError()
+	}
+
+	domainAPIKeys := make([]domain.APIKey, len(apiKeys))
+	for idx := range apiKeys {
+		domainAPIKeys[idx] = apiKeys[idx].toDomain()
+	}
+
+	return domainAPIKeys, nil
+}
+
+func (s *service) RevokeAPIKey(apiKeyID int64) (*domain.APIKey, error) {
+	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
+
+	_, err := s.executor.Exec("UPDATE api_keys SET revoked_at=? WHERE id=? AND revoked_at IS NULL", time.Now().UTC(), apiKeyID)
+	if err != nil {
+		ctxLogger.Errorf("sqlite service - unable to revoke api key with id %v due to %v", apiKeyID, err)
+		return nil, storage.NewStorageOperationError()
+	}
+
+	return s.FindAPIKeyByID(apiKeyID)
+}
+
+func (s *service) UpdateAPIKeyLastUsed(apiKeyID int64, lastUsedAt time.Time) error {
+	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
+
+	result, err := s.executor.Exec("UPDATE api_keys SET last_used_at=? WHERE id=?", lastUsedAt.UTC(), apiKeyID)
+	if err != nil {
+		ctxLogger.Errorf("sqlite service - unable to update api key with id %v due to %v", apiKeyID, err)
+		return storage.NewStorageOperationError()
+	}
+
+	if rowsAffected, _ := result.RowsAffected(); rowsAffected == 0 {
+		ctxLogger.Warnf("sqlite service - unable to update api key with id %v as it does not exist", apiKeyID)
+		return storage.NewStorageRecordNotFoundError()
+	}
+
+	return nil
+}
@@ -0,0 +1,134 @@
+package sqlite
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rawfish-dev/rsvp-starter/server/domain"
+	"github.com/rawfish-dev/rsvp-starter/server/interfaces"
+	"github.com/rawfish-dev/rsvp-starter/server/services/storage"
+
+	"gopkg.in/gorp.v1"
+)
+
+// auditEntry keeps its snapshots as text as they are only ever handed back as they were written
+type auditEntry struct {
+	ID                       int64     `db:"id"`
+	Actor                    string    `db:"actor"`
+	ActorInvitationPrivateID string    `db:"actor_invitation_private_id"`
+	Action                   string    `db:"action"`
+	EntityType               string    `db:"entity_type"`
+	EntityID                 int64     `db:"entity_id"`
+	Before                   string    `db:"before_state"`
+	After                    string    `db:"after_state"`
+	CreatedAt                time.Time `db:"created_at"`
+}
+
+var (
+	auditEntryColumns = strings.Join([]string{
+		"id",
+		"actor",
+		"actor_invitation_private_id",
+		"action",
+		"entity_type",
+		"entity_id",
+		"before_state",
+		"after_state",
+		"created_at",
+	}, ",")
+
+	auditEntrySortColumns = map[string]string{
+		domain.SortByAuditCreatedAt: "created_at",
+	}
+)
+
+func (a *auditEntry) PreInsert(s gorp.SqlExecutor) error {
+	a.CreatedAt = time.Now().UTC()
+	return nil
+}
+
+func (a *auditEntry) toDomain() domain.AuditEntry {
+	return domain.AuditEntry{
+		ID:                       a.ID,
+		Actor:                    a.Actor,
+		ActorInvitationPrivateID: a.ActorInvitationPrivateID,
+		Action:                   domain.AuditAction(a.Action),
+		EntityType:               domain.AuditEntityType(a.EntityType),
+		EntityID:                 a.EntityID,
+		Before:                   json.RawMessage(a.Before),
+		After:                    json.RawMessage(a.After),
+		CreatedAt:                a.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+func (s *service) InsertAuditEntry(req *domain.AuditEntryCreateRequest) (*domain.AuditEntry, error) {
+	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
+
+	entry := &auditEntry{
+		Actor:                    req.Actor.Username,
+		ActorInvitationPrivateID: req.Actor.InvitationPrivateID,
+		Action:                   string(req.Action),
+		EntityType:               string(req.EntityType),
+		EntityID:                 req.EntityID,
+		Before:                   string(req.Before),
+		After:                    string(req.After),
+	}
+
+	err := s.executor.Insert(entry)
+	if err != nil {
+		ctxLogger.Errorf("sqlite service - unable to insert audit entry for %v %v due to %v", req.EntityType, req.EntityID, err)
+		return nil, storage.NewStorageOperationError()
+	}
+
+	newEntry := entry.toDomain()
+
+	return &newEntry, nil
+}
+
+func (s *service) ListAuditEntries(req *domain.AuditListRequest) ([]domain.AuditEntry, int, error) {
+	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
+
+	var filters listQuery
+	if req.EntityType != "" {
+		filters.where("entity_type=?", string(req.EntityType))
+	}
+	if req.EntityID != 0 {
+		filters.where("entity_id=?", req.EntityID)
+	}
+
+	total, err := s.executor.SelectInt(fmt.Sprintf(`
+		SELECT COUNT(*)
+		FROM audit_entries
+		%v
+	`, filters.whereClause()), filters.args...)
+	if err != nil {
+		ctxLogger.Errorf("sqlite service - unable to count audit entries due to %v", err)
+		return nil, 0, storage.NewStorageOperationError()
+	}
+
+	pageClause, args := filters.pageClause(req.ListRequest, auditEntrySortColumns, "id")
+
+	query := fmt.Sprintf(`
+		SELECT %v
+		FROM audit_entries
+		%v
+		%v
+	`, auditEntryColumns, filters.whereClause(), pageClause)
+
+	var entries []auditEntry
+
+	_, err = s.executor.Select(&entries, query, args...)
+	if err != nil {
+		ctxLogger.Errorf("sqlite service - unable to retrieve audit entries due to %v", err)
+		return nil, 0, storage.NewStorageOperationError()
+	}
+
+	domainEntries := make([]domain.AuditEntry, len(entries))
+	for idx := range entries {
+		domainEntries[idx] = entries[idx].toDomain()
+	}
+
+	return domainEntries, int(total), nil
+}
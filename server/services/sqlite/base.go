@@ -0,0 +1,35 @@
+package sqlite
+
+import (
+	"time"
+
+	"gopkg.in/gorp.v1"
+)
+
+// Timestamps are stored as text in SQLite so they are kept in UTC to sort correctly. DeletedAt is
+// only set once the record is moved to the trash.
+type baseModel struct {
+	ID        int64      `db:"id"`
+	CreatedAt time.Time  `db:"created_at"`
+	UpdatedAt time.Time  `db:"updated_at"`
+	DeletedAt *time.Time `db:"deleted_at"`
+}
+
+func (b *baseModel) deletedAt() string {
+	if b.DeletedAt == nil {
+		return ""
+	}
+
+	return b.DeletedAt.UTC().Format(time.RFC3339)
+}
+
+func (b *baseModel) PreInsert(s gorp.SqlExecutor) error {
+	b.CreatedAt = time.Now().UTC()
+	b.UpdatedAt = b.CreatedAt
+	return nil
+}
+
+func (b *baseModel) PreUpdate(s gorp.SqlExecutor) error {
+	b.UpdatedAt = time.Now().UTC()
+	return nil
+}
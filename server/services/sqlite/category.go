@@ -0,0 +1,294 @@
+package sqlite
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rawfish-dev/rsvp-starter/server/domain"
+	"github.com/rawfish-dev/rsvp-starter/server/interfaces"
+	"github.com/rawfish-dev/rsvp-starter/server/services/storage"
+)
+
+type category struct {
+	baseModel
+	Tag string `db:"tag"`
+}
+
+type categoryAggregate struct {
+	category
+	Total int `db:"total"`
+}
+
+var (
+	categoryColumns = strings.Join([]string{
+		"id",
+		"tag",
+		"created_at",
+		"updated_at",
+		"deleted_at",
+	}, ",")
+
+	categorySortColumns = map[string]string{
+		domain.SortByCategoryTag:   "LOWER(categories.tag)",
+		domain.SortByCategoryTotal: "total",
+	}
+)
+
+func (s *service) InsertCategory(req *domain.CategoryCreateRequest) (*domain.Category, error) {
+	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
+
+	category := &category{
+		Tag: req.Tag,
+	}
+
+	err := s.executor.Insert(category)
+	if err != nil {
+		if isCategoryTagUniqueConstraintError(err) {
+			ctxLogger.Warn("sqlite service - unable to insert category with a duplicate tag")
+			return nil, storage.NewStorageCategoryTagUniqueConstraintError()
+		}
+
+		ctxLogger.Errorf("sqlite service - unable to insert category due to %v", err)
+		return nil, storage.NewStorageOperationError()
+	}
+
+	newCategory := &domain.Category{
+		ID:  category.ID,
+		Tag: category.Tag,
+	}
+
+	return newCategory, nil
+}
+
+func (s *service) FindCategoryByID(categoryID int64) (*domain.Category, error) {
+	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
+
+	query := fmt.Sprintf(`
+		SELECT %v, COUNT(invitations.id) as total
+		FROM categories
+		LEFT JOIN invitations
+		ON categories.id=invitations.category_id AND invitations.deleted_at IS NULL
+		WHERE categories.id=? AND categories.deleted_at IS NULL
+		GROUP BY categories.id
+	`, prependColumnsForJoin())
+
+	var category categoryAggregate
+
+	err := s.executor.SelectOne(&category, query, categoryID)
+	if err != nil {
+		if isNotFoundError(err) {
+			ctxLogger.Warnf("sqlite service - unable to find category with id %v", categoryID)
+			return nil, storage.NewStorageRecordNotFoundError()
+		}
+
+		ctxLogger.Errorf("sqlite service - unable to find category with id %v due to %v", categoryID, err)
+		return nil, storage.NewStorageOperationError()
+	}
+
+	domainCategory := category.toDomain()
+
+	return &domainCategory, nil
+}
+
+func (s *service) ListCategories(req *domain.CategoryListRequest) ([]domain.Category, int, error) {
+	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
+
+	total, err := s.executor.SelectInt("SELECT COUNT(*) FROM categories WHERE deleted_at IS NULL")
+	if err != nil {
+		ctxLogger.Errorf("sqlite service - unable to count categories due to %v", err)
+		return nil, 0, storage.NewStorageOperationError()
+	}
+
+	var filters listQuery
+	pageClause, args := filters.pageClause(req.ListRequest, categorySortColumns, "categories.id")
+
+	query := fmt.Sprintf(`
+		SELECT %v, COUNT(invitations.id) as total
+		FROM categories
+		LEFT JOIN invitations
+		ON categories.id=invitations.category_id AND invitations.deleted_at IS NULL
+		WHERE categories.deleted_at IS NULL
+		GROUP BY categories.id
+		%v
+	`, prependColumnsForJoin(), pageClause)
+
+	var categories []categoryAggregate
+
+	_, err = s.executor.Select(&categories, query, args...)
+	if err != nil {
+		ctxLogger.Errorf("sqlite service - unable to retrieve categories due to %v", err)
+		return nil, 0, storage.NewStorageOperationError()
+	}
+
+	domainCategories := make([]domain.Category, len(categories))
+	for idx := range categories {
+		domainCategories[idx] = categories[idx].toDomain()
+	}
+
+	return domainCategories, int(total), nil
+}
+
+func (s *service) UpdateCategory(domainCategory *domain.Category) (*domain.Category, error) {
+	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
+
+	query := `
+		UPDATE categories
+		SET tag=?, updated_at=?
+		WHERE id=? AND deleted_at IS NULL
+	`
+
+	result, err := s.executor.Exec(query, domainCategory.Tag, time.Now().UTC(), domainCategory.ID)
+	if err != nil {
+		if isCategoryTagUniqueConstraintError(err) {
+			ctxLogger.Warn("sqlite service - unable to update category with a duplicate tag")
+			return nil, storage.NewStorageCategoryTagUniqueConstraintError()
+		}
+
+		ctxLogger.Errorf("sqlite service - unable to update category %+v due to %v", domainCategory, err)
+		return nil, storage.NewStorageOperationError()
+	}
+
+	if rowsAffected, _ := result.RowsAffected(); rowsAffected == 0 {
+		ctxLogger.Warnf("sqlite service - unable to update category with id %v as it does not exist", domainCategory.ID)
+		return nil, storage.NewStorageRecordNotFoundError()
+	}
+
+	return domainCategory, nil
+}
+
+func (s *service) DeleteCategory(domainCategory *domain.Category) error {
+	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
+
+	// Invitations outside the trash keep their category just as the foreign key did for hard deletes
+	invitationCount, err := s.executor.SelectInt("SELECT COUNT(*) FROM invitations WHERE category_id=? AND deleted_at IS NULL", domainCategory.ID)
+	if err != nil {
+		ctxLogger.Errorf("sqlite service - unable to count invitations of category with id %v due to %v", domainCategory.ID, err)
+		return storage.NewStorageOperationError()
+	}
+	if invitationCount > 0 {
+		ctxLogger.Errorf("sqlite service - unable to delete category with id %v as invitations still reference it", domainCategory.ID)
+		return storage.NewStorageOperationError()
+	}
+
+	result, err := s.executor.Exec("UPDATE categories SET deleted_at=? WHERE id=? AND deleted_at IS NULL", time.Now().UTC(), domainCategory.ID)
+	if err != nil {
+		ctxLogger.Errorf("sqlite service - unable to delete category with id %v due to %v", domainCategory.ID, err)
+		return storage.NewStorageOperationError()
+	}
+
+	if rowsAffected, _ := result.RowsAffected(); rowsAffected == 0 {
+		ctxLogger.Warnf("sqlite service - unable to delete category with id %v as it does not exist", domainCategory.ID)
+		return storage.NewStorageRecordNotFoundError()
+	}
+
+	return nil
+}
+
+func (s *service) FindDeletedCategoryByID(categoryID int64) (*domain.Category, error) {
+	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
+
+	query := fmt.Sprintf(`
+		SELECT %v, 0 as total
+		FROM categories
+		WHERE id=? AND deleted_at IS NOT NULL
+	`, categoryColumns)
+
+	var category categoryAggregate
+
+	err := s.executor.SelectOne(&category, query, categoryID)
+	if err != nil {
+		if isNotFoundError(err) {
+			ctxLogger.Warnf("sqlite service - unable to find deleted category with id %v", categoryID)
+			return nil, storage.NewStorageRecordNotFoundError()
+		}
+
+		ctxLogger.Errorf("sqlite service - unable to find deleted category with id %v due to %v", categoryID, err)
+		return nil, storage.NewStorageOperationError()
+	}
+
+	domainCategory := category.toDomain()
+
+	return &domainCategory, nil
+}
+
+func (s *service) ListDeletedCategories() ([]domain.Category, error) {
+	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
+
+	query := fmt.Sprintf(`
+		SELECT %v, 0 as total
+		FROM categories
+		WHERE deleted_at IS NOT NULL
+		ORDER BY deleted_at DESC, id DESC
+	`, categoryColumns)
+
+	var categories []categoryAggregate
+
+	_, err := s.executor.Select(&categories, query)
+	if err != nil {
+		ctxLogger.Errorf("sqlite service - unable to retrieve deleted categories due to %v", err)
+		return nil, storage.NewStorageOperationError()
+	}
+
+	domainCategories := make([]domain.Category, len(categories))
+	for idx := range categories {
+		domainCategories[idx] = categories[idx].toDomain()
+	}
+
+	return domainCategories, nil
+}
+
+func (s *service) RestoreCategory(domainCategory *domain.Category) (*domain.Category, error) {
+	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
+
+	result, err := s.executor.Exec("UPDATE categories SET deleted_at=NULL WHERE id=? AND deleted_at IS NOT NULL", domainCategory.ID)
+	if err != nil {
+		if isCategoryTagUniqueConstraintError(err) {
+			ctxLogger.Warn("sqlite service - unable to restore category with a duplicate tag")
+			return nil, storage.NewStorageCategoryTagUniqueConstraintError()
+		}
+
+		ctxLogger.Errorf("sqlite service - unable to restore category with id %v due to %v", domainCategory.ID, err)
+		return nil, storage.NewStorageOperationError()
+	}
+
+	if rowsAffected, _ := result.RowsAffected(); rowsAffected == 0 {
+		ctxLogger.Warnf("sqlite service - unable to restore category with id %v as it is not deleted", domainCategory.ID)
+		return nil, storage.NewStorageRecordNotFoundError()
+	}
+
+	return s.FindCategoryByID(domainCategory.ID)
+}
+
+func (s *service) PurgeCategories(deletedBefore time.Time) (int, error) {
+	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
+
+	query := `
+		DELETE FROM categories
+		WHERE deleted_at<?
+		AND NOT EXISTS (SELECT 1 FROM invitations WHERE invitations.category_id=categories.id)
+	`
+
+	result, err := s.executor.Exec(query, deletedBefore.UTC())
+	if err != nil {
+		ctxLogger.Errorf("sqlite service - unable to purge categories deleted before %v due to %v", deletedBefore, err)
+		return 0, storage.NewStorageOperationError()
+	}
+
+	purged, _ := result.RowsAffected()
+
+	return int(purged), nil
+}
+
+func (c *categoryAggregate) toDomain() domain.Category {
+	return domain.Category{
+		ID:        c.ID,
+		Tag:       c.Tag,
+		Total:     c.Total,
+		DeletedAt: c.deletedAt(),
+	}
+}
+
+func prependColumnsForJoin() string {
+	return prefixColumns("categories", categoryColumns)
+}
@@ -0,0 +1,430 @@
+package sqlite
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rawfish-dev/rsvp-starter/server/domain"
+	"github.com/rawfish-dev/rsvp-starter/server/interfaces"
+	"github.com/rawfish-dev/rsvp-starter/server/services/storage"
+
+	"github.com/satori/go.uuid"
+)
+
+type invitation struct {
+	baseModel
+	CategoryID               int64      `db:"category_id"`
+	PrivateID                string     `db:"private_id"`
+	Greeting                 string     `db:"greeting"`
+	MaximumGuestCount        int        `db:"maximum_guest_count"`
+	Status                   string     `db:"status"`
+	Notes                    string     `db:"notes"`
+	MobilePhoneNumber        string     `db:"mobile_phone_number"`
+	Email                    string     `db:"email"`
+	Language                 string     `db:"language"`
+	LinkExpiresAt            *time.Time `db:"link_expires_at"`
+	LinkRevokedAt            *time.Time `db:"link_revoked_at"`
+	RequirePhoneConfirmation bool       `db:"require_phone_confirmation"`
+	Version                  int64      `db:"version"` // gorp sets this to 1 on insert as it treats Version as a lock column
+}
+
+var (
+	invitationColumns = strings.Join([]string{
+		"id",
+		"category_id",
+		"private_id",
+		"greeting",
+		"maximum_guest_count",
+		"status",
+		"notes",
+		"mobile_phone_number",
+		"email",
+		"language",
+		"link_expires_at",
+		"link_revoked_at",
+		"require_phone_confirmation",
+		"created_at",
+		"updated_at",
+		"version",
+		"deleted_at",
+	}, ",")
+
+	invitationSortColumns = map[string]string{
+		domain.SortByInvitationGreeting:          "LOWER(invitations.greeting)",
+		domain.SortByInvitationMaximumGuestCount: "invitations.maximum_guest_count",
+		domain.SortByInvitationStatus:            invitationStatusExpression,
+		domain.SortByInvitationUpdatedAt:         "invitations.updated_at",
+	}
+)
+
+// invitationStatusExpression reports whether the guests replied in place of the stored status
+// once an rsvp exists, and needs rsvps to be joined on the invitation private id.
+const invitationStatusExpression = `CASE
+	WHEN rsvps.id IS NULL THEN invitations.status
+	WHEN rsvps.attending THEN 'RA'
+	ELSE 'RN'
+END`
+
+// invitationListing is an invitation along with the status derived from its rsvp
+type invitationListing struct {
+	invitation
+	RSVPStatus string `db:"rsvp_status"`
+}
+
+func (i *invitation) toDomain() domain.Invitation {
+	return domain.Invitation{
+		BaseInvitation: domain.BaseInvitation{
+			CategoryID:        i.CategoryID,
+			Greeting:          i.Greeting,
+			MaximumGuestCount: i.MaximumGuestCount,
+			Notes:             i.Notes,
+			MobilePhoneNumber: i.MobilePhoneNumber,
+			Email:             i.Email,
+			Language:          i.Language,
+		},
+		ID:                       i.ID,
+		PrivateID:                i.PrivateID,
+		Status:                   domain.RSVPStatus(i.Status),
+		LinkExpiresAt:            formatOptionalTime(i.LinkExpiresAt),
+		LinkRevokedAt:            formatOptionalTime(i.LinkRevokedAt),
+		RequirePhoneConfirmation: i.RequirePhoneConfirmation,
+		UpdatedAt:                i.UpdatedAt.Format(time.RFC3339),
+		Version:                  i.Version,
+		DeletedAt:                i.deletedAt(),
+	}
+}
+
+func (s *service) InsertInvitation(req *domain.InvitationCreateRequest) (*domain.Invitation, error) {
+	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
+
+	invitation := &invitation{
+		CategoryID:        req.CategoryID,
+		PrivateID:         uuid.NewV4().String(),
+		Greeting:          req.Greeting,
+		MaximumGuestCount: req.MaximumGuestCount,
+		Status:            string(domain.NotSent),
+		Notes:             req.Notes,
+		MobilePhoneNumber: req.MobilePhoneNumber,
+		Email:             req.Email,
+		Language:          req.Language,
+	}
+
+	err := s.executor.Insert(invitation)
+	if err != nil {
+		if mappedErr := mapInvitationUniqueConstraintError(err); mappedErr != nil {
+			ctxLogger.Warnf("sqlite service - unable to insert invitation %v due to %v", invitation.Greeting, mappedErr)
+			return nil, mappedErr
+		}
+
+		ctxLogger.Errorf("sqlite service - unable to insert invitation due to %v", err)
+		return nil, storage.NewStorageOperationError()
+	}
+
+	newInvitation := invitation.toDomain()
+
+	return &newInvitation, nil
+}
+
+func (s *service) FindInvitationByID(invitationID int64) (*domain.Invitation, error) {
+	return s.findInvitation("id", invitationID)
+}
+
+func (s *service) FindInvitationByPrivateID(privateID string) (*domain.Invitation, error) {
+	return s.findInvitation("private_id", privateID)
+}
+
+func (s *service) findInvitation(column string, value interface{}) (*domain.Invitation, error) {
+	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
+
+	query := fmt.Sprintf(`
+		SELECT %v
+		FROM invitations
+		WHERE %v=? AND deleted_at IS NULL
+	`, invitationColumns, column)
+
+	var invitation invitation
+
+	err := s.executor.SelectOne(&invitation, query, value)
+	if err != nil {
+		if isNotFoundError(err) {
+			ctxLogger.Warnf("sqlite service - unable to find invitation with %v %v", column, value)
+			return nil, storage.NewStorageRecordNotFoundError()
+		}
+
+		ctxLogger.Errorf("sqlite service - unable to find invitation with %v %v due to %v", column, value, err)
+		return nil, storage.NewStorageOperationError()
+	}
+
+	domainInvitation := invitation.toDomain()
+
+	return &domainInvitation, nil
+}
+
+func (s *service) ListInvitations(req *domain.InvitationListRequest) ([]domain.Invitation, int, error) {
+	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
+
+	filters := listQuery{conditions: []string{"invitations.deleted_at IS NULL"}}
+	if req.CategoryID != 0 {
+		filters.where("invitations.category_id=?", req.CategoryID)
+	}
+	if req.Status != "" {
+		filters.where(invitationStatusExpression+"=?", string(req.Status))
+	}
+	if req.UpdatedSince != nil {
+		filters.where("invitations.updated_at>=?", req.UpdatedSince.UTC())
+	}
+
+	total, err := s.executor.SelectInt(fmt.Sprintf(`
+		SELECT COUNT(*)
+		FROM invitations
+		LEFT JOIN rsvps
+		ON invitations.private_id=rsvps.invitation_private_id AND rsvps.deleted_at IS NULL
+		%v
+	`, filters.whereClause()), filters.args...)
+	if err != nil {
+		ctxLogger.Errorf("sqlite service - unable to count invitations due to %v", err)
+		return nil, 0, storage.NewStorageOperationError()
+	}
+
+	pageClause, args := filters.pageClause(req.ListRequest, invitationSortColumns, "invitations.id")
+
+	query := fmt.Sprintf(`
+		SELECT %v, %v AS rsvp_status
+		FROM invitations
+		LEFT JOIN rsvps
+		ON invitations.private_id=rsvps.invitation_private_id AND rsvps.deleted_at IS NULL
+		%v
+		%v
+	`, prefixColumns("invitations", invitationColumns), invitationStatusExpression, filters.whereClause(), pageClause)
+
+	var invitations []invitationListing
+
+	_, err = s.executor.Select(&invitations, query, args...)
+	if err != nil {
+		ctxLogger.Errorf("sqlite service - unable to retrieve invitations due to %v", err)
+		return nil, 0, storage.NewStorageOperationError()
+	}
+
+	domainInvitations := make([]domain.Invitation, len(invitations))
+	for idx := range invitations {
+		domainInvitations[idx] = invitations[idx].toDomain()
+		domainInvitations[idx].Status = domain.RSVPStatus(invitations[idx].RSVPStatus)
+	}
+
+	return domainInvitations, int(total), nil
+}
+
+func (s *service) UpdateInvitation(domainInvitation *domain.Invitation) (*domain.Invitation, error) {
+	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
+
+	query := `
+		UPDATE invitations
+		SET category_id=?, private_id=?, greeting=?, maximum_guest_count=?, status=?, notes=?, mobile_phone_number=?, email=?, language=?,
+			link_expires_at=?, link_revoked_at=?, require_phone_confirmation=?, updated_at=?, version=version+1
+		WHERE id=? AND version=? AND deleted_at IS NULL
+	`
+
+	linkExpiresAt, err := storage.ParseOptionalTimestamp(domainInvitation.LinkExpiresAt)
+	if err != nil {
+		ctxLogger.Errorf("sqlite service - unable to update invitation %v due to %v", domainInvitation.ID, err)
+		return nil, storage.NewStorageOperationError()
+	}
+
+	linkRevokedAt, err := storage.ParseOptionalTimestamp(domainInvitation.LinkRevokedAt)
+	if err != nil {
+		ctxLogger.Errorf("sqlite service - unable to update invitation %v due to %v", domainInvitation.ID, err)
+		return nil, storage.NewStorageOperationError()
+	}
+
+	updatedAt := time.Now().UTC()
+
+	result, err := s.executor.Exec(query,
+		domainInvitation.CategoryID,
+		domainInvitation.PrivateID,
+		domainInvitation.Greeting,
+		domainInvitation.MaximumGuestCount,
+		string(domainInvitation.Status),
+		domainInvitation.Notes,
+		domainInvitation.MobilePhoneNumber,
+		domainInvitation.Email,
+		domainInvitation.Language,
+		linkExpiresAt,
+		linkRevokedAt,
+		domainInvitation.RequirePhoneConfirmation,
+		updatedAt,
+		domainInvitation.ID,
+		domainInvitation.Version,
+	)
+	if err != nil {
+		if mappedErr := mapInvitationUniqueConstraintError(err); mappedErr != nil {
+			ctxLogger.Warnf("sqlite service - unable to update invitation %v due to %v", domainInvitation.ID, mappedErr)
+			return nil, mappedErr
+		}
+
+		ctxLogger.Errorf("sqlite service - unable to update invitation %+v due to %v", domainInvitation, err)
+		return nil, storage.NewStorageOperationError()
+	}
+
+	if rowsAffected, _ := result.RowsAffected(); rowsAffected == 0 {
+		err = s.versionConflictOrNotFound("invitations", domainInvitation.ID)
+		ctxLogger.Warnf("sqlite service - unable to update invitation with id %v and version %v due to %v", domainInvitation.ID, domainInvitation.Version, err)
+		return nil, err
+	}
+
+	domainInvitation.UpdatedAt = updatedAt.Format(time.RFC3339)
+	domainInvitation.Version++
+
+	return domainInvitation, nil
+}
+
+func (s *service) DeleteInvitation(domainInvitation *domain.Invitation) error {
+	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
+
+	result, err := s.executor.Exec("UPDATE invitations SET deleted_at=? WHERE id=? AND deleted_at IS NULL", time.Now().UTC(), domainInvitation.ID)
+	if err != nil {
+		ctxLogger.Errorf("sqlite service - unable to delete invitation with id %v due to %v", domainInvitation.ID, err)
+		return storage.NewStorageOperationError()
+	}
+
+	if rowsAffected, _ := result.RowsAffected(); rowsAffected == 0 {
+		ctxLogger.Warnf("sqlite service - unable to delete invitation with id %v as it does not exist", domainInvitation.ID)
+		return storage.NewStorageRecordNotFoundError()
+	}
+
+	return nil
+}
+
+func (s *service) FindDeletedInvitationByID(invitationID int64) (*domain.Invitation, error) {
+	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
+
+	query := fmt.Sprintf(`
+		SELECT %v
+		FROM invitations
+		WHERE id=? AND deleted_at IS NOT NULL
+	`, invitationColumns)
+
+	var invitation invitation
+
+	err := s.executor.SelectOne(&invitation, query, invitationID)
+	if err != nil {
+		if isNotFoundError(err) {
+			ctxLogger.Warnf("sqlite service - unable to find deleted invitation with id %v", invitationID)
+			return nil, storage.NewStorageRecordNotFoundError()
+		}
+
+		ctxLogger.Errorf("sqlite service - unable to find deleted invitation with id %v due to %v", invitationID, err)
+		return nil, storage.NewStorageOperationError()
+	}
+
+	domainInvitation := invitation.toDomain()
+
+	return &domainInvitation, nil
+}
+
+func (s *service) ListDeletedInvitations() ([]domain.Invitation, error) {
+	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
+
+	query := fmt.Sprintf(`
+		SELECT %v
+		FROM invitations
+		WHERE deleted_at IS NOT NULL
+		ORDER BY deleted_at DESC, id DESC
+	`, invitationColumns)
+
+	var invitations []invitation
+
+	_, err := s.executor.Select(&invitations, query)
+	if err != nil {
+		ctxLogger.Errorf("sqlite service - unable to retrieve deleted invitations due to %v", err)
+		return nil, storage.NewStorageOperationError()
+	}
+
+	domainInvitations := make([]domain.Invitation, len(invitations))
+	for idx := range invitations {
+		domainInvitations[idx] = invitations[idx].toDomain()
+	}
+
+	return domainInvitations, nil
+}
+
+func (s *service) RestoreInvitation(domainInvitation *domain.Invitation) (*domain.Invitation, error) {
+	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
+
+	result, err := s.executor.Exec("UPDATE invitations SET deleted_at=NULL WHERE id=? AND deleted_at IS NOT NULL", domainInvitation.ID)
+	if err != nil {
+		if mappedErr := mapInvitationUniqueConstraintError(err); mappedErr != nil {
+			ctxLogger.Warnf("sqlite service - unable to restore invitation %v due to %v", domainInvitation.ID, mappedErr)
+			return nil, mappedErr
+		}
+
+		ctxLogger.Errorf("sqlite service - unable to restore invitation with id %v due to %v", domainInvitation.ID, err)
+		return nil, storage.NewStorageOperationError()
+	}
+
+	if rowsAffected, _ := result.RowsAffected(); rowsAffected == 0 {
+		ctxLogger.Warnf("sqlite service - unable to restore invitation with id %v as it is not deleted", domainInvitation.ID)
+		return nil, storage.NewStorageRecordNotFoundError()
+	}
+
+	return s.FindInvitationByID(domainInvitation.ID)
+}
+
+func (s *service) PurgeInvitations(deletedBefore time.Time) (int, error) {
+	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
+
+	// Messages and retired links have no foreign key to the invitation so they are removed first, in the
+	// same transaction when purging from the trash
+	for _, table := range []string{"messages", "retired_invitation_links"} {
+		_, err := s.executor.Exec(fmt.Sprintf("DELETE FROM %v WHERE invitation_id IN (SELECT id FROM invitations WHERE deleted_at<?)", table), deletedBefore.UTC())
+		if err != nil {
+			ctxLogger.Errorf("sqlite service - unable to purge %v of invitations deleted before %v due to %v", table, deletedBefore, err)
+			return 0, storage.NewStorageOperationError()
+		}
+	}
+
+	result, err := s.executor.Exec("DELETE FROM invitations WHERE deleted_at<?", deletedBefore.UTC())
+	if err != nil {
+		ctxLogger.Errorf("sqlite service - unable to purge invitations deleted before %v due to %v", deletedBefore, err)
+		return 0, storage.NewStorageOperationError()
+	}
+
+	purged, _ := result.RowsAffected()
+
+	return int(purged), nil
+}
+
+func (s *service) InsertRetiredInvitationLink(invitationID int64, privateID string) error {
+	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
+
+	_, err := s.executor.Exec("INSERT INTO retired_invitation_links (private_id, invitation_id) VALUES (?, ?)", privateID, invitationID)
+	if err != nil {
+		ctxLogger.Errorf("sqlite service - unable to retire private id of invitation %v due to %v", invitationID, err)
+		return storage.NewStorageOperationError()
+	}
+
+	return nil
+}
+
+func (s *service) IsInvitationLinkRetired(privateID string) (bool, error) {
+	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
+
+	count, err := s.executor.SelectInt("SELECT COUNT(*) FROM retired_invitation_links WHERE private_id=?", privateID)
+	if err != nil {
+		ctxLogger.Errorf("sqlite service - unable to check if private id %v is retired due to %v", privateID, err)
+		return false, storage.NewStorageOperationError()
+	}
+
+	return count > 0, nil
+}
+
+func mapInvitationUniqueConstraintError(err error) error {
+	if isInvitationGreetingUniqueConstraintError(err) {
+		return storage.NewStorageInvitationGreetingUniqueConstraintError()
+	}
+	if isInvitationMobilePhoneNumberUniqueConstraintError(err) {
+		return storage.NewStorageInvitationMobilePhoneNumberUniqueConstraintError()
+	}
+
+	return nil
+}
@@ -0,0 +1,59 @@
+package sqlite
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/rawfish-dev/rsvp-starter/server/domain"
+)
+
+// listQuery collects the filters of a list query along with their arguments.
+type listQuery struct {
+	conditions []string
+	args       []interface{}
+}
+
+func (l *listQuery) where(condition string, arg interface{}) {
+	l.conditions = append(l.conditions, condition)
+	l.args = append(l.args, arg)
+}
+
+func (l *listQuery) whereClause() string {
+	if len(l.conditions) == 0 {
+		return ""
+	}
+
+	return "WHERE " + strings.Join(l.conditions, " AND ")
+}
+
+// pageClause orders by the column mapped to the requested sort field, breaking ties on the id so
+// rows never move between pages, and returns the arguments for the whole query.
+func (l *listQuery) pageClause(req domain.ListRequest, sortColumns map[string]string, idColumn string) (string, []interface{}) {
+	field, descending := req.SortField()
+
+	direction := "ASC"
+	if descending {
+		direction = "DESC"
+	}
+
+	order := fmt.Sprintf("%v %v", idColumn, direction)
+	if column, ok := sortColumns[field]; ok {
+		order = fmt.Sprintf("%v %v, %v", column, direction, order)
+	}
+
+	args := make([]interface{}, len(l.args), len(l.args)+2)
+	copy(args, l.args)
+	args = append(args, req.PageSize, req.Offset())
+
+	return fmt.Sprintf("ORDER BY %v LIMIT ? OFFSET ?", order), args
+}
+
+func prefixColumns(table, columns string) string {
+	splitColumns := strings.Split(columns, ",")
+	prefixedColumns := make([]string, len(splitColumns))
+	for idx := range splitColumns {
+		prefixedColumns[idx] = table + "." + splitColumns[idx]
+	}
+
+	return strings.Join(prefixedColumns, ",")
+}
@@ -0,0 +1,191 @@
+package sqlite
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rawfish-dev/rsvp-starter/server/domain"
+	"github.com/rawfish-dev/rsvp-starter/server/interfaces"
+	"github.com/rawfish-dev/rsvp-starter/server/services/storage"
+
+	"gopkg.in/gorp.v1"
+)
+
+// messageTemplate is removed for good when deleted rather than moved to the trash
+type messageTemplate struct {
+	ID         int64     `db:"id"`
+	Name       string    `db:"name"`
+	Kind       string    `db:"kind"`
+	Channel    string    `db:"channel"`
+	CategoryID int64     `db:"category_id"`
+	Language   string    `db:"language"`
+	Subject    string    `db:"subject"`
+	Body       string    `db:"body"`
+	CreatedAt  time.Time `db:"created_at"`
+	UpdatedAt  time.Time `db:"updated_at"`
+}
+
+var messageTemplateColumns = strings.Join([]string{
+	"id",
+	"name",
+	"kind",
+	"channel",
+	"category_id",
+	"language",
+	"subject",
+	"body",
+	"created_at",
+	"updated_at",
+}, ",")
+
+func (m *messageTemplate) PreInsert(s gorp.SqlExecutor) error {
+	m.CreatedAt = time.Now().UTC()
+	m.UpdatedAt = m.CreatedAt
+	return nil
+}
+
+func (m *messageTemplate) toDomain() domain.MessageTemplate {
+	return domain.MessageTemplate{
+		BaseMessageTemplate: domain.BaseMessageTemplate{
+			Name:       m.Name,
+			Kind:       domain.MessageTemplateKind(m.Kind),
+			Channel:    domain.MessageChannel(m.Channel),
+			CategoryID: m.CategoryID,
+			Language:   m.Language,
+			Subject:    m.Subject,
+			Body:       m.Body,
+		},
+		ID:        m.ID,
+		CreatedAt: m.CreatedAt.UTC().Format(time.RFC3339),
+		UpdatedAt: m.UpdatedAt.UTC().Format(time.RFC3339),
+	}
+}
+
+func (s *service) InsertMessageTemplate(req *domain.MessageTemplateCreateRequest) (*domain.MessageTemplate, error) {
+	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
+
+	messageTemplate := &messageTemplate{
+		Name:       req.Name,
+		Kind:       string(req.Kind),
+		Channel:    string(req.Channel),
+		CategoryID: req.CategoryID,
+		Language:   req.Language,
+		Subject:    req.Subject,
+		Body:       req.Body,
+	}
+
+	err := s.executor.Insert(messageTemplate)
+	if err != nil {
+		if isMessageTemplateUniqueConstraintError(err) {
+			ctxLogger.Warn("sqlite service - unable to insert message template for a kind, channel, category and language that already has one")
+			return nil, storage.NewStorageMessageTemplateUniqueConstraintError()
+		}
+
+		ctxLogger.Errorf("sqlite service - unable to insert message template due to %v", err)
+		return nil, storage.NewStorageOperationError()
+	}
+
+	newMessageTemplate := messageTemplate.toDomain()
+
+	return &newMessageTemplate, nil
+}
+
+func (s *service) FindMessageTemplateByID(templateID int64) (*domain.MessageTemplate, error) {
+	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
+
+	query := fmt.Sprintf(`
+		SELECT %v
+		FROM message_templates
+		WHERE id=?
+	`, messageTemplateColumns)
+
+	var messageTemplate messageTemplate
+
+	err := s.executor.SelectOne(&messageTemplate, query, templateID)
+	if err != nil {
+		if isNotFoundError(err) {
+			ctxLogger.Warnf("sqlite service - unable to find message template with id %v", templateID)
+			return nil, storage.NewStorageRecordNotFoundError()
+		}
+
+		ctxLogger.Errorf("sqlite service - unable to find message template with id %v due to %v", templateID, err)
+		return nil, storage.NewStorageOperationError()
+	}
+
+	domainMessageTemplate := messageTemplate.toDomain()
+
+	return &domainMessageTemplate, nil
+}
+
+func (s *service) ListMessageTemplates() ([]domain.MessageTemplate, error) {
+	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
+
+	query := fmt.Sprintf(`
+		SELECT %v
+		FROM message_templates
+		ORDER BY id
+	`, messageTemplateColumns)
+
+	var messageTemplates []messageTemplate
+
+	_, err := s.executor.Select(&messageTemplates, query)
+	if err != nil {
+		ctxLogger.Errorf("sqlite service - unable to retrieve message templates due to %v", err)
+		return nil, storage.NewStorageOperationError()
+	}
+
+	domainMessageTemplates := make([]domain.MessageTemplate, len(messageTemplates))
+	for idx := range messageTemplates {
+		domainMessageTemplates[idx] = messageTemplates[idx].toDomain()
+	}
+
+	return domainMessageTemplates, nil
+}
+
+func (s *service) UpdateMessageTemplate(domainMessageTemplate *domain.MessageTemplate) (*domain.MessageTemplate, error) {
+	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
+
+	query := `
+		UPDATE message_templates
+		SET name=?, kind=?, channel=?, category_id=?, language=?, subject=?, body=?, updated_at=?
+		WHERE id=?
+	`
+
+	result, err := s.executor.Exec(query, domainMessageTemplate.Name, string(domainMessageTemplate.Kind), string(domainMessageTemplate.Channel),
+		domainMessageTemplate.CategoryID, domainMessageTemplate.Language, domainMessageTemplate.Subject, domainMessageTemplate.Body,
+		time.Now().UTC(), domainMessageTemplate.ID)
+	if err != nil {
+		if isMessageTemplateUniqueConstraintError(err) {
+			ctxLogger.Warn("sqlite service - unable to update message template to a kind, channel, category and language that already has one")
+			return nil, storage.NewStorageMessageTemplateUniqueConstraintError()
+		}
+
+		ctxLogger.Errorf("sqlite service - unable to update message template with id %v due to %v", domainMessageTemplate.ID, err)
+		return nil, storage.NewStorageOperationError()
+	}
+
+	if rowsAffected, _ := result.RowsAffected(); rowsAffected == 0 {
+		ctxLogger.Warnf("sqlite service - unable to update message template with id %v as it does not exist", domainMessageTemplate.ID)
+		return nil, storage.NewStorageRecordNotFoundError()
+	}
+
+	return s.FindMessageTemplateByID(domainMessageTemplate.ID)
+}
+
+func (s *service) DeleteMessageTemplate(domainMessageTemplate *domain.MessageTemplate) error {
+	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
+
+	result, err := s.executor.Exec("DELETE FROM message_templates WHERE id=?", domainMessageTemplate.ID)
+	if err != nil {
+		ctxLogger.Errorf("sqlite service - unable to delete message template with id %v due to %v", domainMessageTemplate.ID, err)
+		return storage.NewStorageOperationError()
+	}
+
+	if rowsAffected, _ := result.RowsAffected(); rowsAffected == 0 {
+		ctxLogger.Warnf("sqlite service - unable to delete message template with id %v as it does not exist", domainMessageTemplate.ID)
+		return storage.NewStorageRecordNotFoundError()
+	}
+
+	return nil
+}
@@ -0,0 +1,321 @@
+package sqlite
+
+import (
+	"github.com/rawfish-dev/rsvp-starter/server/services/migration"
+)
+
+// Migrations mirrors the postgres migrations version for version so both schemas evolve together.
+// The only exception is 20261017100000, which adds full-text and trigram indexes SQLite cannot build.
+var Migrations = []migration.Migration{
+	{
+		Version: 20160917000243,
+		Name:    "CreateCategories",
+		Up: `
+			CREATE TABLE categories (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				tag text NOT NULL,
+				created_at timestamp DEFAULT CURRENT_TIMESTAMP NOT NULL,
+				updated_at timestamp DEFAULT CURRENT_TIMESTAMP NOT NULL
+			);
+			CREATE UNIQUE INDEX unique_tag ON categories (LOWER(tag));
+		`,
+		Down: `
+			DROP TABLE categories;
+		`,
+	},
+	{
+		Version: 20160917000317,
+		Name:    "CreateInvitations",
+		Up: `
+			CREATE TABLE invitations (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				category_id integer NOT NULL REFERENCES categories(id),
+				private_id text NOT NULL,
+				greeting text NOT NULL,
+				maximum_guest_count integer NOT NULL DEFAULT 1,
+				status text,
+				notes text,
+				mobile_phone_number text,
+				created_at timestamp DEFAULT CURRENT_TIMESTAMP NOT NULL,
+				updated_at timestamp DEFAULT CURRENT_TIMESTAMP NOT NULL
+			);
+			CREATE UNIQUE INDEX unique_private_id ON invitations (private_id);
+			CREATE UNIQUE INDEX unique_greeting ON invitations (LOWER(greeting));
+			CREATE UNIQUE INDEX unique_mobile_phone_number ON invitations (LOWER(mobile_phone_number));
+		`,
+		Down: `
+			DROP TABLE invitations;
+		`,
+	},
+	{
+		Version: 20160917001302,
+		Name:    "CreateRSVP",
+		Up: `
+			CREATE TABLE rsvps (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				invitation_private_id text,
+				full_name text,
+				attending boolean NOT NULL DEFAULT false,
+				guest_count integer NOT NULL DEFAULT 1,
+				special_diet boolean NOT NULL DEFAULT false,
+				remarks text,
+				mobile_phone_number text,
+				created_at timestamp DEFAULT CURRENT_TIMESTAMP NOT NULL,
+				updated_at timestamp DEFAULT CURRENT_TIMESTAMP NOT NULL
+			);
+			CREATE UNIQUE INDEX unique_invitation_private_id ON rsvps (invitation_private_id);
+		`,
+		Down: `
+			DROP TABLE rsvps;
+		`,
+	},
+	{
+		Version: 20161018134623,
+		Name:    "DropUniqueIndexOnMobilePhoneNumberFromInvitations",
+		Up: `
+			DROP INDEX unique_mobile_phone_number;
+		`,
+		Down: `
+			CREATE UNIQUE INDEX unique_mobile_phone_number ON invitations (LOWER(mobile_phone_number));
+		`,
+	},
+	{
+		Version: 20261017090000,
+		Name:    "AddVersionToInvitationsAndRSVPs",
+		Up: `
+			ALTER TABLE invitations ADD COLUMN version integer NOT NULL DEFAULT 1;
+			ALTER TABLE rsvps ADD COLUMN version integer NOT NULL DEFAULT 1;
+		`,
+		Down: `
+			ALTER TABLE rsvps DROP COLUMN version;
+			ALTER TABLE invitations DROP COLUMN version;
+		`,
+	},
+	{
+		Version: 20261017110000,
+		Name:    "AddDeletedAtToCategoriesInvitationsAndRSVPs",
+		Up: `
+			ALTER TABLE categories ADD COLUMN deleted_at timestamp;
+			ALTER TABLE invitations ADD COLUMN deleted_at timestamp;
+			ALTER TABLE rsvps ADD COLUMN deleted_at timestamp;
+			DROP INDEX unique_tag;
+			CREATE UNIQUE INDEX unique_tag ON categories (LOWER(tag)) WHERE deleted_at IS NULL;
+			DROP INDEX unique_greeting;
+			CREATE UNIQUE INDEX unique_greeting ON invitations (LOWER(greeting)) WHERE deleted_at IS NULL;
+			DROP INDEX unique_invitation_private_id;
+			CREATE UNIQUE INDEX unique_invitation_private_id ON rsvps (invitation_private_id) WHERE deleted_at IS NULL;
+		`,
+		Down: `
+			DROP INDEX unique_invitation_private_id;
+			CREATE UNIQUE INDEX unique_invitation_private_id ON rsvps (invitation_private_id);
+			DROP INDEX unique_greeting;
+			CREATE UNIQUE INDEX unique_greeting ON invitations (LOWER(greeting));
+			DROP INDEX unique_tag;
+			CREATE UNIQUE INDEX unique_tag ON categories (LOWER(tag));
+			ALTER TABLE rsvps DROP COLUMN deleted_at;
+			ALTER TABLE invitations DROP COLUMN deleted_at;
+			ALTER TABLE categories DROP COLUMN deleted_at;
+		`,
+	},
+	{
+		Version: 20261017120000,
+		Name:    "CreateAuditEntries",
+		Up: `
+			CREATE TABLE audit_entries (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				actor text NOT NULL,
+				actor_invitation_private_id text,
+				action text NOT NULL,
+				entity_type text NOT NULL,
+				entity_id integer NOT NULL,
+				before_state text,
+				after_state text,
+				created_at timestamp DEFAULT CURRENT_TIMESTAMP NOT NULL
+			);
+			CREATE INDEX audit_entries_entity ON audit_entries (entity_type, entity_id);
+		`,
+		Down: `
+			DROP TABLE audit_entries;
+		`,
+	},
+	{
+		Version: 20261017130000,
+		Name:    "CreateUsers",
+		Up: `
+			CREATE TABLE users (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				username text NOT NULL UNIQUE,
+				password_hash text NOT NULL,
+				disabled boolean DEFAULT false NOT NULL,
+				created_at timestamp DEFAULT CURRENT_TIMESTAMP NOT NULL,
+				updated_at timestamp DEFAULT CURRENT_TIMESTAMP NOT NULL
+			);
+		`,
+		Down: `
+			DROP TABLE users;
+		`,
+	},
+	{
+		Version: 20261017140000,
+		Name:    "AddRoleToUsers",
+		Up: `
+			ALTER TABLE users ADD COLUMN role text NOT NULL DEFAULT 'owner';
+		`,
+		Down: `
+			ALTER TABLE users DROP COLUMN role;
+		`,
+	},
+	{
+		Version: 20261017150000,
+		Name:    "AddTOTPToUsers",
+		Up: `
+			ALTER TABLE users ADD COLUMN totp_secret text NOT NULL DEFAULT '';
+			ALTER TABLE users ADD COLUMN totp_enabled boolean NOT NULL DEFAULT false;
+			ALTER TABLE users ADD COLUMN totp_last_step bigint NOT NULL DEFAULT 0;
+			ALTER TABLE users ADD COLUMN recovery_code_hashes text NOT NULL DEFAULT '';
+		`,
+		Down: `
+			ALTER TABLE users DROP COLUMN recovery_code_hashes;
+			ALTER TABLE users DROP COLUMN totp_last_step;
+			ALTER TABLE users DROP COLUMN totp_enabled;
+			ALTER TABLE users DROP COLUMN totp_secret;
+		`,
+	},
+	{
+		Version: 20261017160000,
+		Name:    "CreateAPIKeys",
+		Up: `
+			CREATE TABLE api_keys (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				name text NOT NULL,
+				prefix text NOT NULL,
+				key_hash text NOT NULL,
+				scopes text NOT NULL,
+				created_by text NOT NULL,
+				expires_at timestamp,
+				last_used_at timestamp,
+				revoked_at timestamp,
+				created_at timestamp DEFAULT CURRENT_TIMESTAMP NOT NULL
+			);
+			CREATE UNIQUE INDEX unique_key_hash ON api_keys (key_hash);
+		`,
+		Down: `
+			DROP TABLE api_keys;
+		`,
+	},
+	{
+		Version: 20261017170000,
+		Name:    "AddInvitationLinkSettings",
+		Up: `
+			ALTER TABLE invitations ADD COLUMN link_expires_at timestamp;
+			ALTER TABLE invitations ADD COLUMN link_revoked_at timestamp;
+			ALTER TABLE invitations ADD COLUMN require_phone_confirmation boolean NOT NULL DEFAULT false;
+			CREATE TABLE retired_invitation_links (
+				private_id text PRIMARY KEY,
+				invitation_id bigint NOT NULL,
+				retired_at timestamp DEFAULT CURRENT_TIMESTAMP NOT NULL
+			);
+		`,
+		Down: `
+			DROP TABLE retired_invitation_links;
+			ALTER TABLE invitations DROP COLUMN require_phone_confirmation;
+			ALTER TABLE invitations DROP COLUMN link_revoked_at;
+			ALTER TABLE invitations DROP COLUMN link_expires_at;
+		`,
+	},
+	{
+		Version: 20261017180000,
+		Name:    "CreateMessages",
+		Up: `
+			CREATE TABLE messages (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				invitation_id bigint NOT NULL,
+				channel text NOT NULL,
+				recipient text NOT NULL,
+				body text NOT NULL,
+				provider text NOT NULL,
+				provider_message_id text NOT NULL,
+				status text NOT NULL,
+				error text NOT NULL,
+				created_at timestamp DEFAULT CURRENT_TIMESTAMP NOT NULL
+			);
+			CREATE INDEX messages_invitation_id ON messages (invitation_id);
+		`,
+		Down: `
+			DROP TABLE messages;
+		`,
+	},
+	{
+		Version: 20261017190000,
+		Name:    "AddEmails",
+		Up: `
+			ALTER TABLE invitations ADD COLUMN email text NOT NULL DEFAULT '';
+			ALTER TABLE rsvps ADD COLUMN email text NOT NULL DEFAULT '';
+			ALTER TABLE messages ADD COLUMN subject text NOT NULL DEFAULT '';
+		`,
+		Down: `
+			ALTER TABLE messages DROP COLUMN subject;
+			ALTER TABLE rsvps DROP COLUMN email;
+			ALTER TABLE invitations DROP COLUMN email;
+		`,
+	},
+	{
+		Version: 20261017200000,
+		Name:    "AddMessageTemplates",
+		Up: `
+			ALTER TABLE invitations ADD COLUMN language text NOT NULL DEFAULT '';
+			CREATE TABLE message_templates (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				name text NOT NULL,
+				kind text NOT NULL,
+				channel text NOT NULL,
+				category_id integer NOT NULL DEFAULT 0,
+				language text NOT NULL DEFAULT '',
+				subject text NOT NULL DEFAULT '',
+				body text NOT NULL,
+				created_at timestamp DEFAULT CURRENT_TIMESTAMP NOT NULL,
+				updated_at timestamp DEFAULT CURRENT_TIMESTAMP NOT NULL
+			);
+			CREATE UNIQUE INDEX unique_message_template ON message_templates (kind, channel, category_id, LOWER(language));
+		`,
+		Down: `
+			DROP TABLE message_templates;
+			ALTER TABLE invitations DROP COLUMN language;
+		`,
+	},
+	{
+		Version: 20261017210000,
+		Name:    "AddMessageKinds",
+		Up: `
+			ALTER TABLE messages ADD COLUMN kind text NOT NULL DEFAULT '';
+			CREATE INDEX messages_kind ON messages (kind);
+		`,
+		Down: `
+			DROP INDEX messages_kind;
+			ALTER TABLE messages DROP COLUMN kind;
+		`,
+	},
+	{
+		Version: 20261017220000,
+		Name:    "AddMessageDeliveryStatus",
+		Up: `
+			ALTER TABLE messages ADD COLUMN updated_at timestamp NOT NULL DEFAULT '1970-01-01 00:00:00';
+			UPDATE messages SET updated_at = created_at;
+			CREATE INDEX messages_provider_message_id ON messages (provider, provider_message_id);
+		`,
+		Down: `
+			DROP INDEX messages_provider_message_id;
+			ALTER TABLE messages DROP COLUMN updated_at;
+		`,
+	},
+	{
+		Version: 20261017230000,
+		Name:    "AddCheckedInAtToRSVPs",
+		Up: `
+			ALTER TABLE rsvps ADD COLUMN checked_in_at timestamp;
+		`,
+		Down: `
+			ALTER TABLE rsvps DROP COLUMN checked_in_at;
+		`,
+	},
+}
@@ -0,0 +1,364 @@
+package sqlite
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rawfish-dev/rsvp-starter/server/domain"
+	"github.com/rawfish-dev/rsvp-starter/server/interfaces"
+	"github.com/rawfish-dev/rsvp-starter/server/services/storage"
+)
+
+type rsvp struct {
+	baseModel
+	InvitationPrivateID string     `db:"invitation_private_id"`
+	FullName            string     `db:"full_name"`
+	Attending           bool       `db:"attending"`
+	GuestCount          int        `db:"guest_count"`
+	SpecialDiet         bool       `db:"special_diet"`
+	Remarks             string     `db:"remarks"`
+	MobilePhoneNumber   string     `db:"mobile_phone_number"`
+	Email               string     `db:"email"`
+	Version             int64      `db:"version"` // gorp sets this to 1 on insert as it treats Version as a lock column
+	CheckedInAt         *time.Time `db:"checked_in_at"`
+}
+
+var (
+	rsvpColumns = strings.Join([]string{
+		"id",
+		"invitation_private_id",
+		"full_name",
+		"attending",
+		"guest_count",
+		"special_diet",
+		"remarks",
+		"mobile_phone_number",
+		"email",
+		"created_at",
+		"updated_at",
+		"version",
+		"checked_in_at",
+		"deleted_at",
+	}, ",")
+
+	rsvpSortColumns = map[string]string{
+		domain.SortByRSVPFullName:   "LOWER(full_name)",
+		domain.SortByRSVPGuestCount: "guest_count",
+		domain.SortByRSVPUpdatedAt:  "updated_at",
+	}
+)
+
+func (r *rsvp) toDomain() domain.RSVP {
+	return domain.RSVP{
+		BaseRSVP: domain.BaseRSVP{
+			FullName:          r.FullName,
+			Attending:         r.Attending,
+			GuestCount:        r.GuestCount,
+			SpecialDiet:       r.SpecialDiet,
+			Remarks:           r.Remarks,
+			MobilePhoneNumber: r.MobilePhoneNumber,
+			Email:             r.Email,
+		},
+		ID:                  r.ID,
+		InvitationPrivateID: r.InvitationPrivateID,
+		UpdatedAt:           r.UpdatedAt.Format(time.RFC3339),
+		Version:             r.Version,
+		CheckedInAt:         formatOptionalTime(r.CheckedInAt),
+		Completed:           true,
+		DeletedAt:           r.deletedAt(),
+	}
+}
+
+func (s *service) InsertRSVP(req *domain.RSVPCreateRequest) (*domain.RSVP, error) {
+	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
+
+	rsvp := &rsvp{
+		InvitationPrivateID: req.InvitationPrivateID,
+		FullName:            req.FullName,
+		Attending:           req.Attending,
+		GuestCount:          req.GuestCount,
+		SpecialDiet:         req.SpecialDiet,
+		Remarks:             req.Remarks,
+		MobilePhoneNumber:   req.MobilePhoneNumber,
+		Email:               req.Email,
+	}
+
+	err := s.executor.Insert(rsvp)
+	if err != nil {
+		if isRSVPPrivateIDUniqueConstraintError(err) {
+			ctxLogger.Warnf("sqlite service - unable to insert rsvp with a duplicate private id %v", rsvp.InvitationPrivateID)
+			return nil, storage.NewStorageRSVPPrivateIDUniqueConstraintError()
+		}
+
+		ctxLogger.Errorf("sqlite service - unable to insert rsvp due to %v", err)
+		return nil, storage.NewStorageOperationError()
+	}
+
+	newRSVP := rsvp.toDomain()
+
+	return &newRSVP, nil
+}
+
+func (s *service) FindRSVPByID(rsvpID int64) (*domain.RSVP, error) {
+	return s.findRSVP("id", rsvpID)
+}
+
+func (s *service) FindRSVPByInvitationPrivateID(invitationPrivateID string) (*domain.RSVP, error) {
+	domainRSVP, err := s.findRSVP("invitation_private_id", invitationPrivateID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Omit the id and version since no operations can be performed against it by guests
+	domainRSVP.ID = 0
+	domainRSVP.Version = 0
+
+	return domainRSVP, nil
+}
+
+func (s *service) findRSVP(column string, value interface{}) (*domain.RSVP, error) {
+	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
+
+	query := fmt.Sprintf(`
+		SELECT %v
+		FROM rsvps
+		WHERE %v=? AND deleted_at IS NULL
+	`, rsvpColumns, column)
+
+	var rsvp rsvp
+
+	err := s.executor.SelectOne(&rsvp, query, value)
+	if err != nil {
+		if isNotFoundError(err) {
+			ctxLogger.Warnf("sqlite service - unable to find rsvp with %v %v", column, value)
+			return nil, storage.NewStorageRecordNotFoundError()
+		}
+
+		ctxLogger.Errorf("sqlite service - unable to find rsvp with %v %v due to %v", column, value, err)
+		return nil, storage.NewStorageOperationError()
+	}
+
+	domainRSVP := rsvp.toDomain()
+
+	return &domainRSVP, nil
+}
+
+func (s *service) ListRSVPs(req *domain.RSVPListRequest) ([]domain.RSVP, int, error) {
+	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
+
+	filters := listQuery{conditions: []string{"deleted_at IS NULL"}}
+	if req.Attending != nil {
+		filters.where("attending=?", *req.Attending)
+	}
+	if req.SpecialDiet != nil {
+		filters.where("special_diet=?", *req.SpecialDiet)
+	}
+	if req.UpdatedSince != nil {
+		filters.where("updated_at>=?", req.UpdatedSince.UTC())
+	}
+
+	total, err := s.executor.SelectInt(fmt.Sprintf(`
+		SELECT COUNT(*)
+		FROM rsvps
+		%v
+	`, filters.whereClause()), filters.args...)
+	if err != nil {
+		ctxLogger.Errorf("sqlite service - unable to count rsvps due to %v", err)
+		return nil, 0, storage.NewStorageOperationError()
+	}
+
+	pageClause, args := filters.pageClause(req.ListRequest, rsvpSortColumns, "id")
+
+	query := fmt.Sprintf(`
+		SELECT %v
+		FROM rsvps
+		%v
+		%v
+	`, rsvpColumns, filters.whereClause(), pageClause)
+
+	var rsvps []rsvp
+
+	_, err = s.executor.Select(&rsvps, query, args...)
+	if err != nil {
+		ctxLogger.Errorf("sqlite service - unable to retrieve rsvps due to %v", err)
+		return nil, 0, storage.NewStorageOperationError()
+	}
+
+	domainRSVPs := make([]domain.RSVP, len(rsvps))
+	for idx := range rsvps {
+		domainRSVPs[idx] = rsvps[idx].toDomain()
+	}
+
+	return domainRSVPs, int(total), nil
+}
+
+func (s *service) UpdateRSVP(domainRSVP *domain.RSVP) (*domain.RSVP, error) {
+	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
+
+	query := `
+		UPDATE rsvps
+		SET invitation_private_id=?, full_name=?, attending=?, guest_count=?, special_diet=?, remarks=?, mobile_phone_number=?, email=?, updated_at=?, version=version+1
+		WHERE id=? AND version=? AND deleted_at IS NULL
+	`
+
+	updatedAt := time.Now().UTC()
+
+	result, err := s.executor.Exec(query,
+		domainRSVP.InvitationPrivateID,
+		domainRSVP.FullName,
+		domainRSVP.Attending,
+		domainRSVP.GuestCount,
+		domainRSVP.SpecialDiet,
+		domainRSVP.Remarks,
+		domainRSVP.MobilePhoneNumber,
+		domainRSVP.Email,
+		updatedAt,
+		domainRSVP.ID,
+		domainRSVP.Version,
+	)
+	if err != nil {
+		if isRSVPPrivateIDUniqueConstraintError(err) {
+			ctxLogger.Warnf("sqlite service - unable to update rsvp with a duplicate private id %v", domainRSVP.InvitationPrivateID)
+			return nil, storage.NewStorageRSVPPrivateIDUniqueConstraintError()
+		}
+
+		ctxLogger.Errorf("sqlite service - unable to update rsvp %+v due to %v", domainRSVP, err)
+		return nil, storage.NewStorageOperationError()
+	}
+
+	if rowsAffected, _ := result.RowsAffected(); rowsAffected == 0 {
+		err = s.versionConflictOrNotFound("rsvps", domainRSVP.ID)
+		ctxLogger.Warnf("sqlite service - unable to update rsvp with id %v and version %v due to %v", domainRSVP.ID, domainRSVP.Version, err)
+		return nil, err
+	}
+
+	domainRSVP.UpdatedAt = updatedAt.Format(time.RFC3339)
+	domainRSVP.Version++
+	domainRSVP.Completed = true
+
+	return domainRSVP, nil
+}
+
+func (s *service) DeleteRSVP(domainRSVP *domain.RSVP) error {
+	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
+
+	result, err := s.executor.Exec("UPDATE rsvps SET deleted_at=? WHERE id=? AND deleted_at IS NULL", time.Now().UTC(), domainRSVP.ID)
+	if err != nil {
+		ctxLogger.Errorf("sqlite service - unable to delete rsvp with id %v due to %v", domainRSVP.ID, err)
+		return storage.NewStorageOperationError()
+	}
+
+	if rowsAffected, _ := result.RowsAffected(); rowsAffected == 0 {
+		ctxLogger.Warnf("sqlite service - unable to delete rsvp with id %v as it does not exist", domainRSVP.ID)
+		return storage.NewStorageRecordNotFoundError()
+	}
+
+	return nil
+}
+
+func (s *service) FindDeletedRSVPByID(rsvpID int64) (*domain.RSVP, error) {
+	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
+
+	query := fmt.Sprintf(`
+		SELECT %v
+		FROM rsvps
+		WHERE id=? AND deleted_at IS NOT NULL
+	`, rsvpColumns)
+
+	var rsvp rsvp
+
+	err := s.executor.SelectOne(&rsvp, query, rsvpID)
+	if err != nil {
+		if isNotFoundError(err) {
+			ctxLogger.Warnf("sqlite service - unable to find deleted rsvp with id %v", rsvpID)
+			return nil, storage.NewStorageRecordNotFoundError()
+		}
+
+		ctxLogger.Errorf("sqlite service - unable to find deleted rsvp with id %v due to %v", rsvpID, err)
+		return nil, storage.NewStorageOperationError()
+	}
+
+	domainRSVP := rsvp.toDomain()
+
+	return &domainRSVP, nil
+}
+
+func (s *service) ListDeletedRSVPs() ([]domain.RSVP, error) {
+	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
+
+	query := fmt.Sprintf(`
+		SELECT %v
+		FROM rsvps
+		WHERE deleted_at IS NOT NULL
+		ORDER BY deleted_at DESC, id DESC
+	`, rsvpColumns)
+
+	var rsvps []rsvp
+
+	_, err := s.executor.Select(&rsvps, query)
+	if err != nil {
+		ctxLogger.Errorf("sqlite service - unable to retrieve deleted rsvps due to %v", err)
+		return nil, storage.NewStorageOperationError()
+	}
+
+	domainRSVPs := make([]domain.RSVP, len(rsvps))
+	for idx := range rsvps {
+		domainRSVPs[idx] = rsvps[idx].toDomain()
+	}
+
+	return domainRSVPs, nil
+}
+
+func (s *service) RestoreRSVP(domainRSVP *domain.RSVP) (*domain.RSVP, error) {
+	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
+
+	result, err := s.executor.Exec("UPDATE rsvps SET deleted_at=NULL WHERE id=? AND deleted_at IS NOT NULL", domainRSVP.ID)
+	if err != nil {
+		if isRSVPPrivateIDUniqueConstraintError(err) {
+			ctxLogger.Warnf("sqlite service - unable to restore rsvp with a duplicate private id %v", domainRSVP.InvitationPrivateID)
+			return nil, storage.NewStorageRSVPPrivateIDUniqueConstraintError()
+		}
+
+		ctxLogger.Errorf("sqlite service - unable to restore rsvp with id %v due to %v", domainRSVP.ID, err)
+		return nil, storage.NewStorageOperationError()
+	}
+
+	if rowsAffected, _ := result.RowsAffected(); rowsAffected == 0 {
+		ctxLogger.Warnf("sqlite service - unable to restore rsvp with id %v as it is not deleted", domainRSVP.ID)
+		return nil, storage.NewStorageRecordNotFoundError()
+	}
+
+	return s.FindRSVPByID(domainRSVP.ID)
+}
+
+func (s *service) CheckInRSVP(domainRSVP *domain.RSVP) (*domain.RSVP, error) {
+	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
+
+	result, err := s.executor.Exec("UPDATE rsvps SET checked_in_at=? WHERE id=? AND deleted_at IS NULL", time.Now().UTC(), domainRSVP.ID)
+	if err != nil {
+		ctxLogger.Errorf("sqlite service - unable to check in rsvp with id %v due to %v", domainRSVP.ID, err)
+		return nil, storage.NewStorageOperationError()
+	}
+
+	if rowsAffected, _ := result.RowsAffected(); rowsAffected == 0 {
+		ctxLogger.Warnf("sqlite service - unable to check in rsvp with id %v as it does not exist", domainRSVP.ID)
+		return nil, storage.NewStorageRecordNotFoundError()
+	}
+
+	return s.FindRSVPByID(domainRSVP.ID)
+}
+
+func (s *service) PurgeRSVPs(deletedBefore time.Time) (int, error) {
+	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
+
+	result, err := s.executor.Exec("DELETE FROM rsvps WHERE deleted_at<?", deletedBefore.UTC())
+	if err != nil {
+		ctxLogger.Errorf("sqlite service - unable to purge rsvps deleted before %v due to %v", deletedBefore, err)
+		return 0, storage.NewStorageOperationError()
+	}
+
+	purged, _ := result.RowsAffected()
+
+	return int(purged), nil
+}
@@ -0,0 +1,92 @@
+package sqlite
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/rawfish-dev/rsvp-starter/server/domain"
+	"github.com/rawfish-dev/rsvp-starter/server/interfaces"
+	"github.com/rawfish-dev/rsvp-starter/server/services/storage"
+)
+
+// SQLite has neither full-text nor trigram indexes available in this build, so search narrows down
+// the candidates with LIKE and ranks them with the same rules as the memory backend.
+
+// phoneDigitsExpression strips the punctuation guests commonly type in phone numbers since SQLite
+// lacks regexp_replace.
+const phoneDigitsExpression = `REPLACE(REPLACE(REPLACE(REPLACE(REPLACE(REPLACE(COALESCE(%v, ''), ' ', ''), '-', ''), '+', ''), '(', ''), ')', ''), '.', '')`
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+func (s *service) Search(req *domain.SearchRequest) ([]domain.SearchResult, error) {
+	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
+
+	textPattern := "%" + likeEscaper.Replace(strings.ToLower(strings.TrimSpace(req.Query))) + "%"
+	phoneFragment := req.PhoneFragment()
+	phonePattern := "%" + phoneFragment + "%"
+
+	invitationQuery := fmt.Sprintf(`
+		SELECT %v, %v AS rsvp_status
+		FROM invitations
+		LEFT JOIN rsvps
+		ON invitations.private_id=rsvps.invitation_private_id AND rsvps.deleted_at IS NULL
+		WHERE invitations.deleted_at IS NULL
+		AND (LOWER(invitations.greeting) LIKE ? ESCAPE '\'
+		OR LOWER(COALESCE(invitations.notes, '')) LIKE ? ESCAPE '\'
+		OR (? <> '' AND %v LIKE ?))
+	`, prefixColumns("invitations", invitationColumns), invitationStatusExpression, fmt.Sprintf(phoneDigitsExpression, "invitations.mobile_phone_number"))
+
+	var invitations []invitationListing
+
+	_, err := s.executor.Select(&invitations, invitationQuery, textPattern, textPattern, phoneFragment, phonePattern)
+	if err != nil {
+		ctxLogger.Errorf("sqlite service - unable to search invitations due to %v", err)
+		return nil, storage.NewStorageOperationError()
+	}
+
+	rsvpQuery := fmt.Sprintf(`
+		SELECT %v
+		FROM rsvps
+		WHERE deleted_at IS NULL
+		AND (LOWER(COALESCE(full_name, '')) LIKE ? ESCAPE '\'
+		OR LOWER(COALESCE(remarks, '')) LIKE ? ESCAPE '\'
+		OR (? <> '' AND %v LIKE ?))
+	`, rsvpColumns, fmt.Sprintf(phoneDigitsExpression, "mobile_phone_number"))
+
+	var rsvps []rsvp
+
+	_, err = s.executor.Select(&rsvps, rsvpQuery, textPattern, textPattern, phoneFragment, phonePattern)
+	if err != nil {
+		ctxLogger.Errorf("sqlite service - unable to search rsvps due to %v", err)
+		return nil, storage.NewStorageOperationError()
+	}
+
+	results := make([]domain.SearchResult, 0, len(invitations)+len(rsvps))
+
+	for idx := range invitations {
+		domainInvitation := invitations[idx].toDomain()
+		domainInvitation.Status = domain.RSVPStatus(invitations[idx].RSVPStatus)
+
+		if rank := storage.RankInvitation(req, &domainInvitation); rank > 0 {
+			results = append(results, domain.SearchResult{
+				Type:       domain.InvitationSearchResult,
+				Rank:       rank,
+				Invitation: &domainInvitation,
+			})
+		}
+	}
+
+	for idx := range rsvps {
+		domainRSVP := rsvps[idx].toDomain()
+
+		if rank := storage.RankRSVP(req, &domainRSVP); rank > 0 {
+			results = append(results, domain.SearchResult{
+				Type: domain.RSVPSearchResult,
+				Rank: rank,
+				RSVP: &domainRSVP,
+			})
+		}
+	}
+
+	return storage.SortSearchResults(results, req.Limit), nil
+}
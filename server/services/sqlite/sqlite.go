@@ -0,0 +1,73 @@
+package sqlite
+
+import (
+	_ "github.com/mattn/go-sqlite3"
+
+	"database/sql"
+	"fmt"
+	"sync"
+
+	"github.com/rawfish-dev/rsvp-starter/server/config"
+	"github.com/rawfish-dev/rsvp-starter/server/interfaces"
+
+	"golang.org/x/net/context"
+	"gopkg.in/gorp.v1"
+)
+
+var _ interfaces.Storage = new(service)
+
+type service struct {
+	ctx    context.Context
+	gorpDB *gorp.DbMap
+
+	// executor is either gorpDB or the transaction opened by WithTx
+	executor gorp.SqlExecutor
+}
+
+var singletonService *service
+var once sync.Once
+
+func NewService(ctx context.Context, sqliteConfig config.SQLiteConfig) *service {
+	once.Do(func() {
+		singletonService = newService(ctx, sqliteConfig)
+	})
+
+	return singletonService
+}
+
+func newService(ctx context.Context, sqliteConfig config.SQLiteConfig) *service {
+	ctxLogger := ctx.Value("logger").(interfaces.Logger)
+
+	// Foreign keys are off by default in SQLite but postgres always enforces them
+	dataSourceName := fmt.Sprintf("%v?_foreign_keys=1&_busy_timeout=5000", sqliteConfig.Path)
+
+	dbConnection, err := sql.Open("sqlite3", dataSourceName)
+	if err != nil {
+		ctxLogger.Fatalf("sqlite service - unable to open %v due to %v", sqliteConfig.Path, err.Error())
+	}
+
+	// SQLite only allows a single writer, and in-memory databases only live as long as their connection
+	dbConnection.SetMaxIdleConns(1)
+	dbConnection.SetMaxOpenConns(1)
+
+	gorpDB := &gorp.DbMap{Db: dbConnection, Dialect: gorp.SqliteDialect{}}
+	gorpDB.AddTableWithName(category{}, "categories").SetKeys(true, "ID")
+	gorpDB.AddTableWithName(invitation{}, "invitations").SetKeys(true, "ID")
+	gorpDB.AddTableWithName(rsvp{}, "rsvps").SetKeys(true, "ID")
+	gorpDB.AddTableWithName(auditEntry{}, "audit_entries").SetKeys(true, "ID")
+	gorpDB.AddTableWithName(user{}, "users").SetKeys(true, "ID")
+	gorpDB.AddTableWithName(apiKey{}, "api_keys").SetKeys(true, "ID")
+	gorpDB.AddTableWithName(message{}, "messages").SetKeys(true, "ID")
+	gorpDB.AddTableWithName(messageTemplate{}, "message_templates").SetKeys(true, "ID")
+
+	return &service{ctx, gorpDB, gorpDB}
+}
+
+func (s *service) Close() error {
+	return s.gorpDB.Db.Close()
+}
+
+// DB exposes the underlying connection pool for tasks such as running migrations.
+func (s *service) DB() *sql.DB {
+	return s.gorpDB.Db
+}
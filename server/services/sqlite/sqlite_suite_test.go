@@ -0,0 +1,13 @@
+package sqlite_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestSqlite(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Sqlite Suite")
+}
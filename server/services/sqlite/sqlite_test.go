@@ -0,0 +1,78 @@
+package sqlite_test
+
+import (
+	"github.com/rawfish-dev/rsvp-starter/server/config"
+	"github.com/rawfish-dev/rsvp-starter/server/domain"
+	"github.com/rawfish-dev/rsvp-starter/server/interfaces"
+	"github.com/rawfish-dev/rsvp-starter/server/services/migration"
+	. "github.com/rawfish-dev/rsvp-starter/server/services/sqlite"
+	"github.com/rawfish-dev/rsvp-starter/server/services/storage/storagetest"
+
+	"github.com/Sirupsen/logrus"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"golang.org/x/net/context"
+)
+
+var _ = Describe("Sqlite", func() {
+
+	storagetest.StorageContract(func() interfaces.Storage {
+		ctxlogger := logrus.New()
+		ctx := context.Background()
+		ctx = context.WithValue(ctx, "logger", ctxlogger)
+
+		sqliteService := NewService(ctx, config.SQLiteConfig{Path: ":memory:"})
+		_, err := migration.NewService(ctx, sqliteService.DB(), Migrations).Up()
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = sqliteService.DB().Exec("DELETE FROM message_templates; DELETE FROM messages; DELETE FROM retired_invitation_links; DELETE FROM api_keys; DELETE FROM users; DELETE FROM audit_entries; DELETE FROM rsvps; DELETE FROM invitations; DELETE FROM categories;")
+		Expect(err).ToNot(HaveOccurred())
+
+		return sqliteService
+	})
+
+	It("should leave messages sent before kinds were recorded unclassified", func() {
+		ctxlogger := logrus.New()
+		ctx := context.Background()
+		ctx = context.WithValue(ctx, "logger", ctxlogger)
+
+		sqliteService := NewService(ctx, config.SQLiteConfig{Path: ":memory:"})
+		migrationService := migration.NewService(ctx, sqliteService.DB(), Migrations)
+		_, err := migrationService.Up()
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = sqliteService.DB().Exec("DELETE FROM messages;")
+		Expect(err).ToNot(HaveOccurred())
+
+		// Roll back to just before message kinds were recorded
+		for {
+			rolledBack, err := migrationService.Down()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(rolledBack).ToNot(BeNil())
+			if rolledBack.Version == 20261017210000 {
+				break
+			}
+		}
+
+		// Confirmation subjects could be edited, so they do not tell confirmations apart either
+		_, err = sqliteService.DB().Exec(`
+			INSERT INTO messages (invitation_id, channel, recipient, subject, body, provider, provider_message_id, status, error)
+			VALUES (1, 'email', 'guest@example.com', 'We have received your RSVP', 'some body', 'smtp', 'some-id', 'sent', '')
+		`)
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = migrationService.Up()
+		Expect(err).ToNot(HaveOccurred())
+
+		messages, err := sqliteService.ListMessagesByInvitationID(1)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(messages).To(HaveLen(1))
+		Expect(messages[0].Kind).To(Equal(domain.UnclassifiedMessage))
+
+		for _, kind := range []domain.MessageKind{domain.InvitationMessage, domain.ReminderMessage, domain.ConfirmationMessage} {
+			messages, err := sqliteService.ListMessagesByKind(kind)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(messages).To(BeEmpty())
+		}
+	})
+})
@@ -0,0 +1,50 @@
+package sqlite
+
+import (
+	"github.com/rawfish-dev/rsvp-starter/server/interfaces"
+	"github.com/rawfish-dev/rsvp-starter/server/services/storage"
+
+	"gopkg.in/gorp.v1"
+)
+
+func (s *service) WithTx(fn func(tx interfaces.Storage) error) error {
+	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
+
+	// Nested calls join the transaction that is already open
+	if _, ok := s.executor.(*gorp.Transaction); ok {
+		return fn(s)
+	}
+
+	transaction, err := s.gorpDB.Begin()
+	if err != nil {
+		ctxLogger.Errorf("sqlite service - unable to begin transaction due to %v", err)
+		return storage.NewStorageOperationError()
+	}
+
+	// Roll back when fn fails or panics
+	committed := false
+	defer func() {
+		if committed {
+			return
+		}
+		if rollbackErr := transaction.Rollback(); rollbackErr != nil {
+			ctxLogger.Errorf("sqlite service - unable to roll back transaction due to %v", rollbackErr)
+		}
+	}()
+
+	err = fn(&service{s.ctx, s.gorpDB, transaction})
+	if err != nil {
+		return err
+	}
+
+	// The transaction is finished whether or not the commit succeeds
+	committed = true
+
+	err = transaction.Commit()
+	if err != nil {
+		ctxLogger.Errorf("sqlite service - unable to commit transaction due to %v", err)
+		return storage.NewStorageOperationError()
+	}
+
+	return nil
+}
@@ -0,0 +1,188 @@
+package sqlite
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rawfish-dev/rsvp-starter/server/domain"
+	"github.com/rawfish-dev/rsvp-starter/server/interfaces"
+	"github.com/rawfish-dev/rsvp-starter/server/services/storage"
+
+	"gopkg.in/gorp.v1"
+)
+
+// user has no deleted_at since users are disabled rather than moved to the trash. Recovery code hashes
+// are joined by commas as hex hashes never contain one.
+type user struct {
+	ID                 int64     `db:"id"`
+	Username           string    `db:"username"`
+	PasswordHash       string    `db:"password_hash"`
+	Role               string    `db:"role"`
+	Disabled           bool      `db:"disabled"`
+	TOTPSecret         string    `db:"totp_secret"`
+	TOTPEnabled        bool      `db:"totp_enabled"`
+	TOTPLastStep       int64     `db:"totp_last_step"`
+	RecoveryCodeHashes string    `db:"recovery_code_hashes"`
+	CreatedAt          time.Time `db:"created_at"`
+	UpdatedAt          time.Time `db:"updated_at"`
+}
+
+var userColumns = strings.Join([]string{
+	"id",
+	"username",
+	"password_hash",
+	"role",
+	"disabled",
+	"totp_secret",
+	"totp_enabled",
+	"totp_last_step",
+	"recovery_code_hashes",
+	"created_at",
+	"updated_at",
+}, ",")
+
+func (u *user) PreInsert(s gorp.SqlExecutor) error {
+	u.CreatedAt = time.Now().UTC()
+	u.UpdatedAt = u.CreatedAt
+	return nil
+}
+
+func (u *user) toDomain() domain.User {
+	return domain.User{
+		ID:                 u.ID,
+		Username:           u.Username,
+		PasswordHash:       u.PasswordHash,
+		Role:               domain.Role(u.Role),
+		Disabled:           u.Disabled,
+		TOTPSecret:         u.TOTPSecret,
+		TOTPEnabled:        u.TOTPEnabled,
+		TOTPLastStep:       u.TOTPLastStep,
+		RecoveryCodeHashes: splitRecoveryCodeHashes(u.RecoveryCodeHashes),
+		CreatedAt:          u.CreatedAt.UTC().Format(time.RFC3339),
+		UpdatedAt:          u.UpdatedAt.UTC().Format(time.RFC3339),
+	}
+}
+
+func splitRecoveryCodeHashes(joinedHashes string) []string {
+	if joinedHashes == "" {
+		return nil
+	}
+
+	return strings.Split(joinedHashes, ",")
+}
+
+func (s *service) InsertUser(domainUser *domain.User) (*domain.User, error) {
+	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
+
+	user := &user{
+		Username:           domainUser.Username,
+		PasswordHash:       domainUser.PasswordHash,
+		Role:               string(domainUser.Role),
+		Disabled:           domainUser.Disabled,
+		TOTPSecret:         domainUser.TOTPSecret,
+		TOTPEnabled:        domainUser.TOTPEnabled,
+		TOTPLastStep:       domainUser.TOTPLastStep,
+		RecoveryCodeHashes: strings.Join(domainUser.RecoveryCodeHashes, ","),
+	}
+
+	err := s.executor.Insert(user)
+	if err != nil {
+		if isUserUsernameUniqueConstraintError(err) {
+			ctxLogger.Warn("sqlite service - unable to insert user with a duplicate username")
+			return nil, storage.NewStorageUserUsernameUniqueConstraintError()
+		}
+
+		ctxLogger.Errorf("sqlite service - unable to insert user due to %v", err)
+		return nil, storage.NewStorageOperationError()
+	}
+
+	newUser := user.toDomain()
+
+	return &newUser, nil
+}
+
+func (s *service) FindUserByID(userID int64) (*domain.User, error) {
+	return s.findUser("id", userID)
+}
+
+func (s *service) FindUserByUsername(username string) (*domain.User, error) {
+	return s.findUser("username", username)
+}
+
+func (s *service) findUser(column string, value interface{}) (*domain.User, error) {
+	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
+
+	query := fmt.Sprintf(`
+		SELECT %v
+		FROM users
+		WHERE %v=?
+	`, userColumns, column)
+
+	var user user
+
+	err := s.executor.SelectOne(&user, query, value)
+	if err != nil {
+		if isNotFoundError(err) {
+			ctxLogger.Warnf("sqlite service - unable to find user with %v %v", column, value)
+			return nil, storage.NewStorageRecordNotFoundError()
+		}
+
+		ctxLogger.Errorf("sqlite service - unable to find user with %v %v due to %v", column, value, err)
+		return nil, storage.NewStorageOperationError()
+	}
+
+	domainUser := user.toDomain()
+
+	return &domainUser, nil
+}
+
+func (s *service) ListUsers() ([]domain.User, error) {
+	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
+
+	query := fmt.Sprintf(`
+		SELECT %v
+		FROM users
+		ORDER BY username
+	`, userColumns)
+
+	var users []user
+
+	_, err := s.executor.Select(&users, query)
+	if err != nil {
+		ctxLogger.Errorf("sqlite service - unable to retrieve users due to %v", err)
+		return nil, storage.NewStorageOperationError()
+	}
+
+	domainUsers := make([]domain.User, len(users))
+	for idx := range users {
+		domainUsers[idx] = users[idx].toDomain()
+	}
+
+	return domainUsers, nil
+}
+
+func (s *service) UpdateUser(domainUser *domain.User) (*domain.User, error) {
+	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
+
+	query := `
+		UPDATE users
+		SET password_hash=?, role=?, disabled=?, totp_secret=?, totp_enabled=?, totp_last_step=?, recovery_code_hashes=?, updated_at=?
+		WHERE id=?
+	`
+
+	result, err := s.executor.Exec(query, domainUser.PasswordHash, string(domainUser.Role), domainUser.Disabled,
+		domainUser.TOTPSecret, domainUser.TOTPEnabled, domainUser.TOTPLastStep, strings.Join(domainUser.RecoveryCodeHashes, ","),
+		time.Now().UTC(), domainUser.ID)
+	if err != nil {
+		ctxLogger.Errorf("sqlite service - unable to update user with id %v due to %v", domainUser.ID, err)
+		return nil, storage.NewStorageOperationError()
+	}
+
+	if rowsAffected, _ := result.RowsAffected(); rowsAffected == 0 {
+		ctxLogger.Warnf("sqlite service - unable to update user with id %v as it does not exist", domainUser.ID)
+		return nil, storage.NewStorageRecordNotFoundError()
+	}
+
+	return s.FindUserByID(domainUser.ID)
+}
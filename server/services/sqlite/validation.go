@@ -0,0 +1,65 @@
+package sqlite
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/rawfish-dev/rsvp-starter/server/services/storage"
+
+	"github.com/mattn/go-sqlite3"
+)
+
+func isNotFoundError(err error) bool {
+	return err != nil && err == sql.ErrNoRows
+}
+
+func isUniqueConstraintError(err error, constraint string) bool {
+	sqliteErr, ok := err.(sqlite3.Error)
+	if !ok {
+		return false
+	}
+
+	return sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique && strings.Contains(sqliteErr.Error(), constraint)
+}
+
+func isCategoryTagUniqueConstraintError(err error) bool {
+	return isUniqueConstraintError(err, "index 'unique_tag'")
+}
+
+func isInvitationGreetingUniqueConstraintError(err error) bool {
+	return isUniqueConstraintError(err, "index 'unique_greeting'")
+}
+
+func isInvitationMobilePhoneNumberUniqueConstraintError(err error) bool {
+	return isUniqueConstraintError(err, "index 'unique_mobile_phone_number'")
+}
+
+// Indexes on plain columns are reported by column rather than by index name
+func isRSVPPrivateIDUniqueConstraintError(err error) bool {
+	return isUniqueConstraintError(err, "rsvps.invitation_private_id")
+}
+
+func isUserUsernameUniqueConstraintError(err error) bool {
+	return isUniqueConstraintError(err, "users.username")
+}
+
+func isMessageTemplateUniqueConstraintError(err error) bool {
+	return isUniqueConstraintError(err, "index 'unique_message_template'")
+}
+
+// versionConflictOrNotFound tells apart an update that matched no rows because the record is gone
+// or in the trash from one that matched no rows because the record was changed since the given
+// version was read.
+func (s *service) versionConflictOrNotFound(table string, id int64) error {
+	count, err := s.executor.SelectInt(fmt.Sprintf("SELECT COUNT(*) FROM %v WHERE id=? AND deleted_at IS NULL", table), id)
+	if err != nil {
+		return storage.NewStorageOperationError()
+	}
+
+	if count == 0 {
+		return storage.NewStorageRecordNotFoundError()
+	}
+
+	return storage.NewStorageVersionConflictError()
+}
@@ -0,0 +1,104 @@
+package storage
+
+// Errors returned by every storage backend so services never depend on a specific database.
+
+type StorageOperationError struct {
+}
+
+func NewStorageOperationError() error {
+	return StorageOperationError{}
+}
+
+func (s StorageOperationError) Error() string {
+	return ""
+}
+
+type StorageRecordNotFoundError struct {
+}
+
+func NewStorageRecordNotFoundError() error {
+	return StorageRecordNotFoundError{}
+}
+
+func (s StorageRecordNotFoundError) Error() string {
+	return ""
+}
+
+type StorageCategoryTagUniqueConstraintError struct {
+}
+
+func NewStorageCategoryTagUniqueConstraintError() error {
+	return StorageCategoryTagUniqueConstraintError{}
+}
+
+func (s StorageCategoryTagUniqueConstraintError) Error() string {
+	return ""
+}
+
+type StorageInvitationGreetingUniqueConstraintError struct {
+}
+
+func NewStorageInvitationGreetingUniqueConstraintError() error {
+	return StorageInvitationGreetingUniqueConstraintError{}
+}
+
+func (s StorageInvitationGreetingUniqueConstraintError) Error() string {
+	return "greeting already exists"
+}
+
+type StorageInvitationMobilePhoneNumberUniqueConstraintError struct {
+}
+
+func NewStorageInvitationMobilePhoneNumberUniqueConstraintError() error {
+	return StorageInvitationMobilePhoneNumberUniqueConstraintError{}
+}
+
+func (s StorageInvitationMobilePhoneNumberUniqueConstraintError) Error() string {
+	return "mobile phone number already exists"
+}
+
+type StorageRSVPPrivateIDUniqueConstraintError struct {
+}
+
+func NewStorageRSVPPrivateIDUniqueConstraintError() error {
+	return StorageRSVPPrivateIDUniqueConstraintError{}
+}
+
+func (s StorageRSVPPrivateIDUniqueConstraintError) Error() string {
+	return "rsvp already exists for invitation"
+}
+
+type StorageUserUsernameUniqueConstraintError struct {
+}
+
+func NewStorageUserUsernameUniqueConstraintError() error {
+	return StorageUserUsernameUniqueConstraintError{}
+}
+
+func (s StorageUserUsernameUniqueConstraintError) Error() string {
+	return "username already exists"
+}
+
+type StorageMessageTemplateUniqueConstraintError struct {
+}
+
+func NewStorageMessageTemplateUniqueConstraintError() error {
+	return StorageMessageTemplateUniqueConstraintError{}
+}
+
+func (s StorageMessageTemplateUniqueConstraintError) Error() string {
+	return "a template already exists for the kind, channel, category and language"
+}
+
+// StorageVersionConflictError is returned when a record was changed by someone else after the
+// version being updated was read.
+type StorageVersionConflictError struct {
+}
+
+func NewStorageVersionConflictError() error {
+	return StorageVersionConflictError{}
+}
+
+func (s StorageVersionConflictError) Error() string {
+	return "record has been modified since it was retrieved"
+}
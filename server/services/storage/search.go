@@ -0,0 +1,115 @@
+package storage
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/rawfish-dev/rsvp-starter/server/domain"
+)
+
+// Backends without full-text search rank matches with the functions below. Names outrank notes and
+// remarks, and within a field an exact match outranks one at the start of a word, which in turn
+// outranks a match anywhere else.
+
+const (
+	nameWeight  = 1.0
+	phoneWeight = 0.8
+	notesWeight = 0.5
+)
+
+func RankInvitation(req *domain.SearchRequest, invitation *domain.Invitation) float64 {
+	return maxRank(
+		nameWeight*textRank(req.Query, invitation.Greeting),
+		notesWeight*textRank(req.Query, invitation.Notes),
+		phoneWeight*phoneRank(req.PhoneFragment(), invitation.MobilePhoneNumber),
+	)
+}
+
+func RankRSVP(req *domain.SearchRequest, rsvp *domain.RSVP) float64 {
+	return maxRank(
+		nameWeight*textRank(req.Query, rsvp.FullName),
+		notesWeight*textRank(req.Query, rsvp.Remarks),
+		phoneWeight*phoneRank(req.PhoneFragment(), rsvp.MobilePhoneNumber),
+	)
+}
+
+// SortSearchResults orders results from the best match, breaking ties on the type and id so the
+// order is stable, and keeps at most limit of them.
+func SortSearchResults(results []domain.SearchResult, limit int) []domain.SearchResult {
+	sort.Sort(searchResultsByRank(results))
+
+	if len(results) > limit {
+		results = results[:limit]
+	}
+
+	return results
+}
+
+type searchResultsByRank []domain.SearchResult
+
+func (s searchResultsByRank) Len() int {
+	return len(s)
+}
+
+func (s searchResultsByRank) Swap(i, j int) {
+	s[i], s[j] = s[j], s[i]
+}
+
+func (s searchResultsByRank) Less(i, j int) bool {
+	if s[i].Rank != s[j].Rank {
+		return s[i].Rank > s[j].Rank
+	}
+	if s[i].Type != s[j].Type {
+		return s[i].Type < s[j].Type
+	}
+
+	return s[i].ID() < s[j].ID()
+}
+
+func textRank(query, text string) float64 {
+	query = strings.ToLower(strings.TrimSpace(query))
+	text = strings.ToLower(text)
+
+	switch {
+	case query == "":
+		return 0
+	case text == query:
+		return 1
+	case strings.HasPrefix(text, query), strings.Contains(text, " "+query):
+		return 0.75
+	case strings.Contains(text, query):
+		return 0.5
+	}
+
+	return 0
+}
+
+// phoneRank favours fragments at the end of the number since guests are usually remembered by
+// their last few digits.
+func phoneRank(fragment, phoneNumber string) float64 {
+	digits := domain.SearchRequest{Query: phoneNumber}.PhoneFragment()
+
+	switch {
+	case fragment == "" || digits == "":
+		return 0
+	case digits == fragment:
+		return 1
+	case strings.HasSuffix(digits, fragment):
+		return 0.75
+	case strings.Contains(digits, fragment):
+		return 0.5
+	}
+
+	return 0
+}
+
+func maxRank(ranks ...float64) float64 {
+	var max float64
+	for _, rank := range ranks {
+		if rank > max {
+			max = rank
+		}
+	}
+
+	return max
+}
@@ -0,0 +1,1600 @@
+// Package storagetest holds the behaviour every storage backend must share so the services
+// can rely on it regardless of which driver is configured.
+package storagetest
+
+import (
+	"time"
+
+	"github.com/rawfish-dev/rsvp-starter/server/domain"
+	"github.com/rawfish-dev/rsvp-starter/server/interfaces"
+	"github.com/rawfish-dev/rsvp-starter/server/services/storage"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// StorageContract registers the shared specs in the enclosing container. newStorage is called
+// before every spec and must return a backend without any records.
+func StorageContract(newStorage func() interfaces.Storage) {
+
+	var testStorage interfaces.Storage
+
+	BeforeEach(func() {
+		testStorage = newStorage()
+	})
+
+	insertCategory := func(tag string) *domain.Category {
+		newCategory, err := testStorage.InsertCategory(&domain.CategoryCreateRequest{Tag: tag})
+		Expect(err).ToNot(HaveOccurred())
+
+		return newCategory
+	}
+
+	insertInvitation := func(categoryID int64, greeting string) *domain.Invitation {
+		newInvitation, err := testStorage.InsertInvitation(&domain.InvitationCreateRequest{
+			BaseInvitation: domain.BaseInvitation{
+				CategoryID:        categoryID,
+				Greeting:          greeting,
+				MaximumGuestCount: 2,
+				Notes:             "some notes",
+				MobilePhoneNumber: "91231234",
+			},
+		})
+		Expect(err).ToNot(HaveOccurred())
+
+		return newInvitation
+	}
+
+	firstPage := func(sort string) domain.ListRequest {
+		return domain.ListRequest{Page: 1, PageSize: domain.DefaultPageSize, Sort: sort}
+	}
+
+	insertRSVP := func(invitationPrivateID, fullName string) *domain.RSVP {
+		newRSVP, err := testStorage.InsertRSVP(&domain.RSVPCreateRequest{
+			BaseRSVP: domain.BaseRSVP{
+				FullName:          fullName,
+				Attending:         true,
+				GuestCount:        2,
+				SpecialDiet:       true,
+				Remarks:           "no peanuts",
+				MobilePhoneNumber: "91231234",
+				Email:             "guest@example.com",
+			},
+			InvitationPrivateID: invitationPrivateID,
+		})
+		Expect(err).ToNot(HaveOccurred())
+
+		return newRSVP
+	}
+
+	Context("category storage", func() {
+
+		It("should insert and find a category with no invitations", func() {
+			newCategory := insertCategory("family")
+			Expect(newCategory.ID).ToNot(BeZero())
+			Expect(newCategory.Tag).To(Equal("family"))
+
+			category, err := testStorage.FindCategoryByID(newCategory.ID)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(category.Tag).To(Equal("family"))
+			Expect(category.Total).To(BeZero())
+		})
+
+		It("should return a tag unique constraint error for duplicate tags regardless of case", func() {
+			insertCategory("Family")
+
+			_, err := testStorage.InsertCategory(&domain.CategoryCreateRequest{Tag: "fAMILY"})
+			Expect(err).To(BeAssignableToTypeOf(storage.StorageCategoryTagUniqueConstraintError{}))
+
+			newCategory := insertCategory("friends")
+			newCategory.Tag = "family"
+			_, err = testStorage.UpdateCategory(newCategory)
+			Expect(err).To(BeAssignableToTypeOf(storage.StorageCategoryTagUniqueConstraintError{}))
+		})
+
+		It("should count only the invitations in each category", func() {
+			family := insertCategory("family")
+			friends := insertCategory("friends")
+			insertCategory("colleagues")
+
+			insertInvitation(family.ID, "ah ma")
+			insertInvitation(family.ID, "ah gong")
+			insertInvitation(friends.ID, "uncle tan")
+
+			category, err := testStorage.FindCategoryByID(family.ID)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(category.Total).To(Equal(2))
+
+			category, err = testStorage.FindCategoryByID(friends.ID)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(category.Total).To(Equal(1))
+		})
+
+		It("should list categories by tag in descending order with totals", func() {
+			family := insertCategory("family")
+			insertCategory("friends")
+			insertCategory("colleagues")
+
+			insertInvitation(family.ID, "ah ma")
+
+			categories, total, err := testStorage.ListCategories(&domain.CategoryListRequest{ListRequest: firstPage("-tag")})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(total).To(Equal(3))
+			Expect(categories).To(HaveLen(3))
+			Expect(categories[0].Tag).To(Equal("friends"))
+			Expect(categories[0].Total).To(BeZero())
+			Expect(categories[1].Tag).To(Equal("family"))
+			Expect(categories[1].Total).To(Equal(1))
+			Expect(categories[2].Tag).To(Equal("colleagues"))
+		})
+
+		It("should page through categories sorted by total", func() {
+			family := insertCategory("family")
+			friends := insertCategory("friends")
+			insertCategory("colleagues")
+
+			insertInvitation(family.ID, "ah ma")
+			insertInvitation(family.ID, "ah gong")
+			insertInvitation(friends.ID, "ah beng")
+
+			categories, total, err := testStorage.ListCategories(&domain.CategoryListRequest{
+				ListRequest: domain.ListRequest{Page: 1, PageSize: 2, Sort: "-total"},
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(total).To(Equal(3))
+			Expect(categories).To(HaveLen(2))
+			Expect(categories[0].Tag).To(Equal("family"))
+			Expect(categories[0].Total).To(Equal(2))
+			Expect(categories[1].Tag).To(Equal("friends"))
+		})
+
+		It("should update a category", func() {
+			newCategory := insertCategory("family")
+			newCategory.Tag = "relatives"
+
+			_, err := testStorage.UpdateCategory(newCategory)
+			Expect(err).ToNot(HaveOccurred())
+
+			category, err := testStorage.FindCategoryByID(newCategory.ID)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(category.Tag).To(Equal("relatives"))
+		})
+
+		It("should delete a category", func() {
+			newCategory := insertCategory("family")
+
+			err := testStorage.DeleteCategory(newCategory)
+			Expect(err).ToNot(HaveOccurred())
+
+			_, err = testStorage.FindCategoryByID(newCategory.ID)
+			Expect(err).To(BeAssignableToTypeOf(storage.StorageRecordNotFoundError{}))
+		})
+
+		It("should not delete a category that still has invitations", func() {
+			newCategory := insertCategory("family")
+			insertInvitation(newCategory.ID, "ah ma")
+
+			err := testStorage.DeleteCategory(newCategory)
+			Expect(err).To(BeAssignableToTypeOf(storage.StorageOperationError{}))
+		})
+
+		It("should return not found errors for unknown categories", func() {
+			_, err := testStorage.FindCategoryByID(1000)
+			Expect(err).To(BeAssignableToTypeOf(storage.StorageRecordNotFoundError{}))
+
+			_, err = testStorage.UpdateCategory(&domain.Category{ID: 1000, Tag: "unknown"})
+			Expect(err).To(BeAssignableToTypeOf(storage.StorageRecordNotFoundError{}))
+
+			err = testStorage.DeleteCategory(&domain.Category{ID: 1000})
+			Expect(err).To(BeAssignableToTypeOf(storage.StorageRecordNotFoundError{}))
+		})
+	})
+
+	Context("invitation storage", func() {
+
+		var categoryID int64
+
+		BeforeEach(func() {
+			categoryID = insertCategory("family").ID
+		})
+
+		It("should insert a not sent invitation with a private id", func() {
+			newInvitation := insertInvitation(categoryID, "ah ma")
+			Expect(newInvitation.ID).ToNot(BeZero())
+			Expect(newInvitation.PrivateID).ToNot(BeEmpty())
+			Expect(newInvitation.Status).To(Equal(domain.NotSent))
+
+			invitation, err := testStorage.FindInvitationByID(newInvitation.ID)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(invitation.BaseInvitation).To(Equal(newInvitation.BaseInvitation))
+			Expect(invitation.PrivateID).To(Equal(newInvitation.PrivateID))
+			Expect(invitation.UpdatedAt).ToNot(BeEmpty())
+
+			invitation, err = testStorage.FindInvitationByPrivateID(newInvitation.PrivateID)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(invitation.ID).To(Equal(newInvitation.ID))
+		})
+
+		It("should allow the same mobile phone number on multiple invitations", func() {
+			insertInvitation(categoryID, "ah ma")
+			insertInvitation(categoryID, "ah gong")
+		})
+
+		It("should return a greeting unique constraint error for duplicate greetings regardless of case", func() {
+			insertInvitation(categoryID, "Uncle Tan")
+
+			_, err := testStorage.InsertInvitation(&domain.InvitationCreateRequest{
+				BaseInvitation: domain.BaseInvitation{
+					CategoryID:        categoryID,
+					Greeting:          "uncle TAN",
+					MaximumGuestCount: 2,
+				},
+			})
+			Expect(err).To(BeAssignableToTypeOf(storage.StorageInvitationGreetingUniqueConstraintError{}))
+			Expect(err.Error()).To(Equal("greeting already exists"))
+
+			newInvitation := insertInvitation(categoryID, "Auntie Tan")
+			newInvitation.Greeting = "uncle tan"
+			_, err = testStorage.UpdateInvitation(newInvitation)
+			Expect(err).To(BeAssignableToTypeOf(storage.StorageInvitationGreetingUniqueConstraintError{}))
+		})
+
+		It("should not insert an invitation for an unknown category", func() {
+			_, err := testStorage.InsertInvitation(&domain.InvitationCreateRequest{
+				BaseInvitation: domain.BaseInvitation{
+					CategoryID:        categoryID + 1000,
+					Greeting:          "ah ma",
+					MaximumGuestCount: 2,
+				},
+			})
+			Expect(err).To(BeAssignableToTypeOf(storage.StorageOperationError{}))
+		})
+
+		It("should list invitations by most recently updated first", func() {
+			first := insertInvitation(categoryID, "first")
+			insertInvitation(categoryID, "second")
+			insertInvitation(categoryID, "third")
+
+			first.Status = domain.Sent
+			_, err := testStorage.UpdateInvitation(first)
+			Expect(err).ToNot(HaveOccurred())
+
+			invitations, total, err := testStorage.ListInvitations(&domain.InvitationListRequest{ListRequest: firstPage("-updatedAt")})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(total).To(Equal(3))
+			Expect(invitations).To(HaveLen(3))
+			Expect(invitations[0].Greeting).To(Equal("first"))
+			Expect(invitations[0].Status).To(Equal(domain.Sent))
+			Expect(invitations[1].Greeting).To(Equal("third"))
+			Expect(invitations[2].Greeting).To(Equal("second"))
+		})
+
+		It("should page through invitations sorted by greeting ignoring case with the total", func() {
+			for _, greeting := range []string{"Echo", "alpha", "delta", "Bravo", "charlie"} {
+				insertInvitation(categoryID, greeting)
+			}
+
+			listRequest := &domain.InvitationListRequest{
+				ListRequest: domain.ListRequest{Page: 2, PageSize: 2, Sort: "greeting"},
+			}
+
+			invitations, total, err := testStorage.ListInvitations(listRequest)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(total).To(Equal(5))
+			Expect(invitations).To(HaveLen(2))
+			Expect(invitations[0].Greeting).To(Equal("charlie"))
+			Expect(invitations[1].Greeting).To(Equal("delta"))
+
+			listRequest.Page = 3
+			listRequest.Sort = "-greeting"
+
+			invitations, total, err = testStorage.ListInvitations(listRequest)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(total).To(Equal(5))
+			Expect(invitations).To(HaveLen(1))
+			Expect(invitations[0].Greeting).To(Equal("alpha"))
+
+			listRequest.Page = 4
+
+			invitations, total, err = testStorage.ListInvitations(listRequest)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(total).To(Equal(5))
+			Expect(invitations).To(BeEmpty())
+		})
+
+		It("should derive the status of listed invitations from their rsvps and filter on it", func() {
+			attending := insertInvitation(categoryID, "ah ma")
+			notAttending := insertInvitation(categoryID, "ah gong")
+			notReplied := insertInvitation(categoryID, "ah pek")
+
+			insertRSVP(attending.PrivateID, "ah ma")
+			notAttendingRSVP := insertRSVP(notAttending.PrivateID, "ah gong")
+			notAttendingRSVP.Attending = false
+			_, err := testStorage.UpdateRSVP(notAttendingRSVP)
+			Expect(err).ToNot(HaveOccurred())
+
+			invitations, total, err := testStorage.ListInvitations(&domain.InvitationListRequest{ListRequest: firstPage("status")})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(total).To(Equal(3))
+			Expect(invitations[0].ID).To(Equal(notReplied.ID))
+			Expect(invitations[0].Status).To(Equal(domain.NotSent))
+			Expect(invitations[1].ID).To(Equal(attending.ID))
+			Expect(invitations[1].Status).To(Equal(domain.RepliedAttending))
+			Expect(invitations[2].ID).To(Equal(notAttending.ID))
+			Expect(invitations[2].Status).To(Equal(domain.RepliedNotAttending))
+
+			invitations, total, err = testStorage.ListInvitations(&domain.InvitationListRequest{
+				ListRequest: firstPage("-updatedAt"),
+				Status:      domain.RepliedNotAttending,
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(total).To(Equal(1))
+			Expect(invitations).To(HaveLen(1))
+			Expect(invitations[0].ID).To(Equal(notAttending.ID))
+		})
+
+		It("should filter invitations by category and updated since", func() {
+			otherCategoryID := insertCategory("friends").ID
+			insertInvitation(categoryID, "ah ma")
+			insertInvitation(otherCategoryID, "ah beng")
+
+			invitations, total, err := testStorage.ListInvitations(&domain.InvitationListRequest{
+				ListRequest: firstPage("-updatedAt"),
+				CategoryID:  otherCategoryID,
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(total).To(Equal(1))
+			Expect(invitations[0].Greeting).To(Equal("ah beng"))
+
+			past := time.Now().Add(-time.Hour)
+			future := time.Now().Add(time.Hour)
+
+			_, total, err = testStorage.ListInvitations(&domain.InvitationListRequest{
+				ListRequest:  firstPage("-updatedAt"),
+				UpdatedSince: &past,
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(total).To(Equal(2))
+
+			invitations, total, err = testStorage.ListInvitations(&domain.InvitationListRequest{
+				ListRequest:  firstPage("-updatedAt"),
+				UpdatedSince: &future,
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(total).To(BeZero())
+			Expect(invitations).To(BeEmpty())
+		})
+
+		It("should update every field of an invitation", func() {
+			otherCategoryID := insertCategory("friends").ID
+			newInvitation := insertInvitation(categoryID, "ah ma")
+
+			newInvitation.CategoryID = otherCategoryID
+			newInvitation.Greeting = "ah ma and ah gong"
+			newInvitation.MaximumGuestCount = 3
+			newInvitation.Notes = "other notes"
+			newInvitation.MobilePhoneNumber = "98769876"
+			newInvitation.Email = "ahma@example.com"
+			newInvitation.Language = "zh-Hans"
+			newInvitation.Status = domain.Sent
+
+			updatedInvitation, err := testStorage.UpdateInvitation(newInvitation)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(updatedInvitation.UpdatedAt).ToNot(BeEmpty())
+
+			invitation, err := testStorage.FindInvitationByID(newInvitation.ID)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(invitation.BaseInvitation).To(Equal(newInvitation.BaseInvitation))
+			Expect(invitation.Status).To(Equal(domain.Sent))
+			Expect(invitation.PrivateID).To(Equal(newInvitation.PrivateID))
+		})
+
+		It("should update the link settings and private id of an invitation", func() {
+			newInvitation := insertInvitation(categoryID, "ah ma")
+			Expect(newInvitation.LinkExpiresAt).To(BeEmpty())
+			Expect(newInvitation.LinkRevokedAt).To(BeEmpty())
+			Expect(newInvitation.RequirePhoneConfirmation).To(BeFalse())
+
+			oldPrivateID := newInvitation.PrivateID
+
+			newInvitation.PrivateID = "some-rotated-private-id"
+			newInvitation.LinkExpiresAt = "2030-01-02T03:04:05Z"
+			newInvitation.LinkRevokedAt = "2020-01-02T03:04:05Z"
+			newInvitation.RequirePhoneConfirmation = true
+
+			_, err := testStorage.UpdateInvitation(newInvitation)
+			Expect(err).ToNot(HaveOccurred())
+
+			invitation, err := testStorage.FindInvitationByPrivateID("some-rotated-private-id")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(invitation.ID).To(Equal(newInvitation.ID))
+			Expect(invitation.LinkExpiresAt).To(Equal("2030-01-02T03:04:05Z"))
+			Expect(invitation.LinkRevokedAt).To(Equal("2020-01-02T03:04:05Z"))
+			Expect(invitation.RequirePhoneConfirmation).To(BeTrue())
+
+			_, err = testStorage.FindInvitationByPrivateID(oldPrivateID)
+			Expect(err).To(BeAssignableToTypeOf(storage.StorageRecordNotFoundError{}))
+
+			invitation.LinkExpiresAt = ""
+			invitation.LinkRevokedAt = ""
+
+			_, err = testStorage.UpdateInvitation(invitation)
+			Expect(err).ToNot(HaveOccurred())
+
+			invitation, err = testStorage.FindInvitationByID(newInvitation.ID)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(invitation.LinkExpiresAt).To(BeEmpty())
+			Expect(invitation.LinkRevokedAt).To(BeEmpty())
+		})
+
+		It("should remember retired private ids", func() {
+			newInvitation := insertInvitation(categoryID, "ah ma")
+
+			retired, err := testStorage.IsInvitationLinkRetired(newInvitation.PrivateID)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(retired).To(BeFalse())
+
+			err = testStorage.InsertRetiredInvitationLink(newInvitation.ID, newInvitation.PrivateID)
+			Expect(err).ToNot(HaveOccurred())
+
+			retired, err = testStorage.IsInvitationLinkRetired(newInvitation.PrivateID)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(retired).To(BeTrue())
+
+			retired, err = testStorage.IsInvitationLinkRetired("unknown")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(retired).To(BeFalse())
+		})
+
+		It("should start invitations at version 1 and increment the version on every update", func() {
+			newInvitation := insertInvitation(categoryID, "ah ma")
+			Expect(newInvitation.Version).To(Equal(int64(1)))
+
+			newInvitation.Notes = "other notes"
+			updatedInvitation, err := testStorage.UpdateInvitation(newInvitation)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(updatedInvitation.Version).To(Equal(int64(2)))
+
+			invitation, err := testStorage.FindInvitationByID(newInvitation.ID)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(invitation.Version).To(Equal(int64(2)))
+		})
+
+		It("should return a version conflict error when updating an invitation with a stale version", func() {
+			newInvitation := insertInvitation(categoryID, "ah ma")
+
+			staleInvitation := *newInvitation
+			newInvitation.Notes = "first notes"
+			_, err := testStorage.UpdateInvitation(newInvitation)
+			Expect(err).ToNot(HaveOccurred())
+
+			staleInvitation.Notes = "second notes"
+			_, err = testStorage.UpdateInvitation(&staleInvitation)
+			Expect(err).To(BeAssignableToTypeOf(storage.StorageVersionConflictError{}))
+			Expect(err.Error()).To(Equal("record has been modified since it was retrieved"))
+
+			invitation, err := testStorage.FindInvitationByID(newInvitation.ID)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(invitation.Notes).To(Equal("first notes"))
+			Expect(invitation.Version).To(Equal(int64(2)))
+		})
+
+		It("should delete an invitation", func() {
+			newInvitation := insertInvitation(categoryID, "ah ma")
+
+			err := testStorage.DeleteInvitation(newInvitation)
+			Expect(err).ToNot(HaveOccurred())
+
+			_, err = testStorage.FindInvitationByID(newInvitation.ID)
+			Expect(err).To(BeAssignableToTypeOf(storage.StorageRecordNotFoundError{}))
+		})
+
+		It("should return not found errors for unknown invitations", func() {
+			_, err := testStorage.FindInvitationByID(1000)
+			Expect(err).To(BeAssignableToTypeOf(storage.StorageRecordNotFoundError{}))
+
+			_, err = testStorage.FindInvitationByPrivateID("unknown")
+			Expect(err).To(BeAssignableToTypeOf(storage.StorageRecordNotFoundError{}))
+
+			_, err = testStorage.UpdateInvitation(&domain.Invitation{
+				BaseInvitation: domain.BaseInvitation{CategoryID: categoryID, Greeting: "unknown"},
+				ID:             1000,
+				PrivateID:      "unknown",
+				Status:         domain.NotSent,
+			})
+			Expect(err).To(BeAssignableToTypeOf(storage.StorageRecordNotFoundError{}))
+
+			err = testStorage.DeleteInvitation(&domain.Invitation{ID: 1000})
+			Expect(err).To(BeAssignableToTypeOf(storage.StorageRecordNotFoundError{}))
+		})
+	})
+
+	Context("rsvp storage", func() {
+
+		It("should insert and find a completed rsvp", func() {
+			newRSVP := insertRSVP("some-private-id", "ah ma")
+			Expect(newRSVP.ID).ToNot(BeZero())
+			Expect(newRSVP.Completed).To(BeTrue())
+
+			rsvp, err := testStorage.FindRSVPByID(newRSVP.ID)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(rsvp.BaseRSVP).To(Equal(newRSVP.BaseRSVP))
+			Expect(rsvp.InvitationPrivateID).To(Equal("some-private-id"))
+			Expect(rsvp.Completed).To(BeTrue())
+			Expect(rsvp.UpdatedAt).ToNot(BeEmpty())
+		})
+
+		It("should omit the id when found by invitation private id", func() {
+			newRSVP := insertRSVP("some-private-id", "ah ma")
+
+			rsvp, err := testStorage.FindRSVPByInvitationPrivateID("some-private-id")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(rsvp.ID).To(BeZero())
+			Expect(rsvp.BaseRSVP).To(Equal(newRSVP.BaseRSVP))
+			Expect(rsvp.Completed).To(BeTrue())
+		})
+
+		It("should return a private id unique constraint error for a second rsvp to the same invitation", func() {
+			insertRSVP("some-private-id", "ah ma")
+
+			_, err := testStorage.InsertRSVP(&domain.RSVPCreateRequest{
+				BaseRSVP:            domain.BaseRSVP{FullName: "ah gong"},
+				InvitationPrivateID: "some-private-id",
+			})
+			Expect(err).To(BeAssignableToTypeOf(storage.StorageRSVPPrivateIDUniqueConstraintError{}))
+
+			otherRSVP := insertRSVP("other-private-id", "ah gong")
+			otherRSVP.InvitationPrivateID = "some-private-id"
+			_, err = testStorage.UpdateRSVP(otherRSVP)
+			Expect(err).To(BeAssignableToTypeOf(storage.StorageRSVPPrivateIDUniqueConstraintError{}))
+		})
+
+		It("should list rsvps by most recently updated first", func() {
+			first := insertRSVP("first", "first")
+			insertRSVP("second", "second")
+			insertRSVP("third", "third")
+
+			first.Attending = false
+			first.Email = "first@example.com"
+			_, err := testStorage.UpdateRSVP(first)
+			Expect(err).ToNot(HaveOccurred())
+
+			rsvps, total, err := testStorage.ListRSVPs(&domain.RSVPListRequest{ListRequest: firstPage("-updatedAt")})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(total).To(Equal(3))
+			Expect(rsvps).To(HaveLen(3))
+			Expect(rsvps[0].FullName).To(Equal("first"))
+			Expect(rsvps[0].Attending).To(BeFalse())
+			Expect(rsvps[0].Email).To(Equal("first@example.com"))
+			Expect(rsvps[1].FullName).To(Equal("third"))
+			Expect(rsvps[2].FullName).To(Equal("second"))
+		})
+
+		It("should filter rsvps by attending, special diet and updated since", func() {
+			insertRSVP("first", "first")
+			second := insertRSVP("second", "second")
+			third := insertRSVP("third", "third")
+
+			second.Attending = false
+			_, err := testStorage.UpdateRSVP(second)
+			Expect(err).ToNot(HaveOccurred())
+
+			third.SpecialDiet = false
+			third.GuestCount = 5
+			_, err = testStorage.UpdateRSVP(third)
+			Expect(err).ToNot(HaveOccurred())
+
+			attending := true
+			noSpecialDiet := false
+
+			rsvps, total, err := testStorage.ListRSVPs(&domain.RSVPListRequest{
+				ListRequest: firstPage("-guestCount"),
+				Attending:   &attending,
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(total).To(Equal(2))
+			Expect(rsvps[0].FullName).To(Equal("third"))
+			Expect(rsvps[1].FullName).To(Equal("first"))
+
+			rsvps, total, err = testStorage.ListRSVPs(&domain.RSVPListRequest{
+				ListRequest: firstPage("fullName"),
+				Attending:   &attending,
+				SpecialDiet: &noSpecialDiet,
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(total).To(Equal(1))
+			Expect(rsvps[0].FullName).To(Equal("third"))
+
+			future := time.Now().Add(time.Hour)
+
+			rsvps, total, err = testStorage.ListRSVPs(&domain.RSVPListRequest{
+				ListRequest:  firstPage("fullName"),
+				UpdatedSince: &future,
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(total).To(BeZero())
+			Expect(rsvps).To(BeEmpty())
+		})
+
+		It("should return a version conflict error when updating an rsvp with a stale version", func() {
+			newRSVP := insertRSVP("some-private-id", "ah ma")
+			Expect(newRSVP.Version).To(Equal(int64(1)))
+
+			staleRSVP := *newRSVP
+			newRSVP.GuestCount = 2
+			updatedRSVP, err := testStorage.UpdateRSVP(newRSVP)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(updatedRSVP.Version).To(Equal(int64(2)))
+
+			staleRSVP.GuestCount = 3
+			_, err = testStorage.UpdateRSVP(&staleRSVP)
+			Expect(err).To(BeAssignableToTypeOf(storage.StorageVersionConflictError{}))
+
+			rsvp, err := testStorage.FindRSVPByID(newRSVP.ID)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(rsvp.GuestCount).To(Equal(2))
+			Expect(rsvp.Version).To(Equal(int64(2)))
+		})
+
+		It("should delete an rsvp", func() {
+			newRSVP := insertRSVP("some-private-id", "ah ma")
+
+			err := testStorage.DeleteRSVP(newRSVP)
+			Expect(err).ToNot(HaveOccurred())
+
+			_, err = testStorage.FindRSVPByID(newRSVP.ID)
+			Expect(err).To(BeAssignableToTypeOf(storage.StorageRecordNotFoundError{}))
+		})
+
+		It("should return not found errors for unknown rsvps", func() {
+			_, err := testStorage.FindRSVPByID(1000)
+			Expect(err).To(BeAssignableToTypeOf(storage.StorageRecordNotFoundError{}))
+
+			_, err = testStorage.FindRSVPByInvitationPrivateID("unknown")
+			Expect(err).To(BeAssignableToTypeOf(storage.StorageRecordNotFoundError{}))
+
+			_, err = testStorage.UpdateRSVP(&domain.RSVP{ID: 1000, InvitationPrivateID: "unknown"})
+			Expect(err).To(BeAssignableToTypeOf(storage.StorageRecordNotFoundError{}))
+
+			err = testStorage.DeleteRSVP(&domain.RSVP{ID: 1000})
+			Expect(err).To(BeAssignableToTypeOf(storage.StorageRecordNotFoundError{}))
+
+			_, err = testStorage.CheckInRSVP(&domain.RSVP{ID: 1000})
+			Expect(err).To(BeAssignableToTypeOf(storage.StorageRecordNotFoundError{}))
+		})
+
+		It("should check in an rsvp without changing its version", func() {
+			newRSVP := insertRSVP("some-private-id", "ah ma")
+			Expect(newRSVP.CheckedInAt).To(BeEmpty())
+
+			checkedInRSVP, err := testStorage.CheckInRSVP(newRSVP)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(checkedInRSVP.CheckedInAt).ToNot(BeEmpty())
+			Expect(checkedInRSVP.Version).To(Equal(newRSVP.Version))
+
+			rsvp, err := testStorage.FindRSVPByID(newRSVP.ID)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(rsvp.CheckedInAt).To(Equal(checkedInRSVP.CheckedInAt))
+		})
+	})
+
+	Context("search", func() {
+
+		insertInvitationWith := func(categoryID int64, greeting, notes, mobilePhoneNumber string) *domain.Invitation {
+			newInvitation, err := testStorage.InsertInvitation(&domain.InvitationCreateRequest{
+				BaseInvitation: domain.BaseInvitation{
+					CategoryID:        categoryID,
+					Greeting:          greeting,
+					MaximumGuestCount: 2,
+					Notes:             notes,
+					MobilePhoneNumber: mobilePhoneNumber,
+				},
+			})
+			Expect(err).ToNot(HaveOccurred())
+
+			return newInvitation
+		}
+
+		search := func(query string) []domain.SearchResult {
+			results, err := testStorage.Search(&domain.SearchRequest{Query: query, Limit: domain.DefaultSearchLimit})
+			Expect(err).ToNot(HaveOccurred())
+
+			return results
+		}
+
+		It("should rank name matches above notes and remarks and type every result", func() {
+			category := insertCategory("family")
+			tanFamily := insertInvitationWith(category.ID, "The Tan Family", "some notes", "91231234")
+			limFamily := insertInvitationWith(category.ID, "Lim Family", "seat near tan", "91231235")
+			insertInvitationWith(category.ID, "Ong Family", "some notes", "91231236")
+			tanAhKow := insertRSVP("some-private-id", "Tan Ah Kow")
+
+			results := search("tan")
+			Expect(results).To(HaveLen(3))
+
+			nameMatches := []int64{results[0].ID(), results[1].ID()}
+			Expect(nameMatches).To(ConsistOf(tanFamily.ID, tanAhKow.ID))
+			for _, result := range results[:2] {
+				switch result.Type {
+				case domain.InvitationSearchResult:
+					Expect(result.Invitation.Greeting).To(Equal("The Tan Family"))
+					Expect(result.RSVP).To(BeNil())
+				case domain.RSVPSearchResult:
+					Expect(result.RSVP.FullName).To(Equal("Tan Ah Kow"))
+					Expect(result.Invitation).To(BeNil())
+				default:
+					Fail("unexpected search result type " + string(result.Type))
+				}
+			}
+
+			Expect(results[2].Type).To(Equal(domain.InvitationSearchResult))
+			Expect(results[2].Invitation.ID).To(Equal(limFamily.ID))
+			Expect(results[2].Rank).To(BeNumerically("<", results[1].Rank))
+		})
+
+		It("should match remarks and phone number fragments regardless of formatting", func() {
+			category := insertCategory("family")
+			cousin := insertInvitationWith(category.ID, "Cousin Ah Boy", "some notes", "+65 9123-4421")
+			insertInvitationWith(category.ID, "Uncle Ah Seng", "some notes", "98765432")
+			insertRSVP(cousin.PrivateID, "Ah Boy")
+
+			results := search("4421")
+			Expect(results).To(HaveLen(1))
+			Expect(results[0].Type).To(Equal(domain.InvitationSearchResult))
+			Expect(results[0].Invitation.ID).To(Equal(cousin.ID))
+			Expect(results[0].Invitation.Status).To(Equal(domain.RepliedAttending))
+
+			results = search("peanuts")
+			Expect(results).To(HaveLen(1))
+			Expect(results[0].Type).To(Equal(domain.RSVPSearchResult))
+			Expect(results[0].RSVP.FullName).To(Equal("Ah Boy"))
+			Expect(results[0].RSVP.InvitationPrivateID).To(Equal(cousin.PrivateID))
+		})
+
+		It("should return at most the requested number of results", func() {
+			category := insertCategory("family")
+			insertInvitationWith(category.ID, "Ah Ma", "some notes", "91231234")
+			insertInvitationWith(category.ID, "Ah Gong", "some notes", "91231235")
+			insertRSVP("some-private-id", "Ah Di")
+
+			results, err := testStorage.Search(&domain.SearchRequest{Query: "ah", Limit: 2})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(results).To(HaveLen(2))
+		})
+
+		It("should treat wildcard characters in the query literally", func() {
+			category := insertCategory("family")
+			insertInvitationWith(category.ID, "Ah Ma", "some notes", "91231234")
+			insertRSVP("some-private-id", "Ah Gong")
+
+			Expect(search("%_")).To(BeEmpty())
+			Expect(search("nobody")).To(BeEmpty())
+		})
+	})
+
+	Context("trash", func() {
+
+		var categoryID int64
+
+		BeforeEach(func() {
+			categoryID = insertCategory("family").ID
+		})
+
+		It("should hide deleted records from finds, lists, totals and search", func() {
+			newInvitation := insertInvitation(categoryID, "ah ma")
+			newRSVP := insertRSVP(newInvitation.PrivateID, "ah ma")
+
+			Expect(testStorage.DeleteRSVP(newRSVP)).To(Succeed())
+			Expect(testStorage.DeleteInvitation(newInvitation)).To(Succeed())
+
+			_, err := testStorage.FindInvitationByPrivateID(newInvitation.PrivateID)
+			Expect(err).To(BeAssignableToTypeOf(storage.StorageRecordNotFoundError{}))
+
+			_, err = testStorage.FindRSVPByInvitationPrivateID(newInvitation.PrivateID)
+			Expect(err).To(BeAssignableToTypeOf(storage.StorageRecordNotFoundError{}))
+
+			invitations, total, err := testStorage.ListInvitations(&domain.InvitationListRequest{ListRequest: firstPage("")})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(invitations).To(BeEmpty())
+			Expect(total).To(BeZero())
+
+			rsvps, total, err := testStorage.ListRSVPs(&domain.RSVPListRequest{ListRequest: firstPage("")})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(rsvps).To(BeEmpty())
+			Expect(total).To(BeZero())
+
+			category, err := testStorage.FindCategoryByID(categoryID)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(category.Total).To(BeZero())
+
+			results, err := testStorage.Search(&domain.SearchRequest{Query: "ah ma", Limit: domain.DefaultSearchLimit})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(results).To(BeEmpty())
+
+			_, err = testStorage.UpdateInvitation(newInvitation)
+			Expect(err).To(BeAssignableToTypeOf(storage.StorageRecordNotFoundError{}))
+
+			err = testStorage.DeleteInvitation(newInvitation)
+			Expect(err).To(BeAssignableToTypeOf(storage.StorageRecordNotFoundError{}))
+		})
+
+		It("should derive the status of invitations from rsvps outside the trash only", func() {
+			newInvitation := insertInvitation(categoryID, "ah ma")
+			newRSVP := insertRSVP(newInvitation.PrivateID, "ah ma")
+
+			Expect(testStorage.DeleteRSVP(newRSVP)).To(Succeed())
+
+			invitations, _, err := testStorage.ListInvitations(&domain.InvitationListRequest{ListRequest: firstPage("")})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(invitations).To(HaveLen(1))
+			Expect(invitations[0].Status).To(Equal(domain.NotSent))
+		})
+
+		It("should find and list deleted records with the time they were deleted, most recently deleted first", func() {
+			ahMa := insertInvitation(categoryID, "ah ma")
+			ahGong := insertInvitation(categoryID, "ah gong")
+
+			Expect(testStorage.DeleteInvitation(ahMa)).To(Succeed())
+			Expect(testStorage.DeleteInvitation(ahGong)).To(Succeed())
+
+			deletedInvitation, err := testStorage.FindDeletedInvitationByID(ahMa.ID)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(deletedInvitation.Greeting).To(Equal("ah ma"))
+			Expect(deletedInvitation.DeletedAt).ToNot(BeEmpty())
+
+			deletedInvitations, err := testStorage.ListDeletedInvitations()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(deletedInvitations).To(HaveLen(2))
+			Expect(deletedInvitations[0].ID).To(Equal(ahGong.ID))
+			Expect(deletedInvitations[1].ID).To(Equal(ahMa.ID))
+
+			_, err = testStorage.FindDeletedInvitationByID(insertInvitation(categoryID, "ah di").ID)
+			Expect(err).To(BeAssignableToTypeOf(storage.StorageRecordNotFoundError{}))
+		})
+
+		It("should restore deleted records", func() {
+			newInvitation := insertInvitation(categoryID, "ah ma")
+			newRSVP := insertRSVP(newInvitation.PrivateID, "ah ma")
+			emptyCategory := insertCategory("friends")
+
+			Expect(testStorage.DeleteRSVP(newRSVP)).To(Succeed())
+			Expect(testStorage.DeleteInvitation(newInvitation)).To(Succeed())
+			Expect(testStorage.DeleteCategory(emptyCategory)).To(Succeed())
+
+			restoredCategory, err := testStorage.RestoreCategory(emptyCategory)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(restoredCategory.Tag).To(Equal("friends"))
+			Expect(restoredCategory.DeletedAt).To(BeEmpty())
+
+			restoredInvitation, err := testStorage.RestoreInvitation(newInvitation)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(restoredInvitation.Greeting).To(Equal("ah ma"))
+			Expect(restoredInvitation.DeletedAt).To(BeEmpty())
+
+			restoredRSVP, err := testStorage.RestoreRSVP(newRSVP)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(restoredRSVP.FullName).To(Equal("ah ma"))
+
+			_, err = testStorage.FindInvitationByID(newInvitation.ID)
+			Expect(err).ToNot(HaveOccurred())
+
+			_, err = testStorage.FindRSVPByID(newRSVP.ID)
+			Expect(err).ToNot(HaveOccurred())
+
+			deletedCategories, err := testStorage.ListDeletedCategories()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(deletedCategories).To(BeEmpty())
+
+			_, err = testStorage.RestoreCategory(emptyCategory)
+			Expect(err).To(BeAssignableToTypeOf(storage.StorageRecordNotFoundError{}))
+		})
+
+		It("should free unique values on delete and report them when restoring a duplicate", func() {
+			deletedCategory := insertCategory("friends")
+			Expect(testStorage.DeleteCategory(deletedCategory)).To(Succeed())
+			insertCategory("Friends")
+
+			_, err := testStorage.RestoreCategory(deletedCategory)
+			Expect(err).To(BeAssignableToTypeOf(storage.StorageCategoryTagUniqueConstraintError{}))
+
+			deletedInvitation := insertInvitation(categoryID, "ah ma")
+			Expect(testStorage.DeleteInvitation(deletedInvitation)).To(Succeed())
+			insertInvitation(categoryID, "Ah Ma")
+
+			_, err = testStorage.RestoreInvitation(deletedInvitation)
+			Expect(err).To(BeAssignableToTypeOf(storage.StorageInvitationGreetingUniqueConstraintError{}))
+
+			deletedRSVP := insertRSVP("some-private-id", "ah ma")
+			Expect(testStorage.DeleteRSVP(deletedRSVP)).To(Succeed())
+			insertRSVP("some-private-id", "ah ma")
+
+			_, err = testStorage.RestoreRSVP(deletedRSVP)
+			Expect(err).To(BeAssignableToTypeOf(storage.StorageRSVPPrivateIDUniqueConstraintError{}))
+		})
+
+		It("should purge only records deleted before the cutoff", func() {
+			newRSVP := insertRSVP("some-private-id", "ah ma")
+			Expect(testStorage.DeleteRSVP(newRSVP)).To(Succeed())
+
+			purged, err := testStorage.PurgeRSVPs(time.Now().Add(-time.Hour))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(purged).To(BeZero())
+
+			purged, err = testStorage.PurgeRSVPs(time.Now().Add(time.Hour))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(purged).To(Equal(1))
+
+			_, err = testStorage.FindDeletedRSVPByID(newRSVP.ID)
+			Expect(err).To(BeAssignableToTypeOf(storage.StorageRecordNotFoundError{}))
+		})
+
+		It("should purge the messages and retired private ids of purged invitations", func() {
+			newInvitation := insertInvitation(categoryID, "ah ma")
+			Expect(testStorage.InsertRetiredInvitationLink(newInvitation.ID, "retired-private-id")).To(Succeed())
+			_, err := testStorage.InsertMessage(&domain.Message{
+				InvitationID: newInvitation.ID,
+				Kind:         domain.InvitationMessage,
+				Channel:      domain.SMSChannel,
+				Recipient:    "+6591234567",
+				Body:         "You are invited",
+				Provider:     "twilio",
+				Status:       domain.MessageSent,
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(testStorage.DeleteInvitation(newInvitation)).To(Succeed())
+
+			purged, err := testStorage.PurgeInvitations(time.Now().Add(time.Hour))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(purged).To(Equal(1))
+
+			retired, err := testStorage.IsInvitationLinkRetired("retired-private-id")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(retired).To(BeFalse())
+
+			messages, err := testStorage.ListMessagesByInvitationID(newInvitation.ID)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(messages).To(BeEmpty())
+		})
+
+		It("should keep deleted categories until the invitations in the trash referencing them are purged", func() {
+			newInvitation := insertInvitation(categoryID, "ah ma")
+			Expect(testStorage.DeleteInvitation(newInvitation)).To(Succeed())
+			Expect(testStorage.DeleteCategory(&domain.Category{ID: categoryID})).To(Succeed())
+
+			cutoff := time.Now().Add(time.Hour)
+
+			purged, err := testStorage.PurgeCategories(cutoff)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(purged).To(BeZero())
+
+			purged, err = testStorage.PurgeInvitations(cutoff)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(purged).To(Equal(1))
+
+			purged, err = testStorage.PurgeCategories(cutoff)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(purged).To(Equal(1))
+
+			_, err = testStorage.FindDeletedCategoryByID(categoryID)
+			Expect(err).To(BeAssignableToTypeOf(storage.StorageRecordNotFoundError{}))
+		})
+	})
+
+	Context("user storage", func() {
+
+		insertUser := func(username string) *domain.User {
+			newUser, err := testStorage.InsertUser(&domain.User{Username: username, PasswordHash: "some-hash", Role: domain.RoleEditor})
+			Expect(err).ToNot(HaveOccurred())
+
+			return newUser
+		}
+
+		It("should insert and find a user by id and username", func() {
+			newUser := insertUser("kevin")
+			Expect(newUser.ID).ToNot(BeZero())
+			Expect(newUser.Username).To(Equal("kevin"))
+			Expect(newUser.PasswordHash).To(Equal("some-hash"))
+			Expect(newUser.Role).To(Equal(domain.RoleEditor))
+			Expect(newUser.Disabled).To(BeFalse())
+			Expect(newUser.CreatedAt).ToNot(BeEmpty())
+
+			foundUser, err := testStorage.FindUserByID(newUser.ID)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(foundUser).To(Equal(newUser))
+
+			foundUser, err = testStorage.FindUserByUsername("kevin")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(foundUser).To(Equal(newUser))
+		})
+
+		It("should return a username unique constraint error for a duplicate username", func() {
+			insertUser("kevin")
+
+			_, err := testStorage.InsertUser(&domain.User{Username: "kevin", PasswordHash: "another-hash"})
+			Expect(err).To(BeAssignableToTypeOf(storage.StorageUserUsernameUniqueConstraintError{}))
+		})
+
+		It("should list users by username", func() {
+			insertUser("kevin")
+			insertUser("jenny")
+
+			users, err := testStorage.ListUsers()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(users).To(HaveLen(2))
+			Expect(users[0].Username).To(Equal("jenny"))
+			Expect(users[1].Username).To(Equal("kevin"))
+		})
+
+		It("should update the password hash, role and disabled flag", func() {
+			newUser := insertUser("kevin")
+			newUser.PasswordHash = "another-hash"
+			newUser.Role = domain.RoleDoorStaff
+			newUser.Disabled = true
+
+			updatedUser, err := testStorage.UpdateUser(newUser)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(updatedUser.PasswordHash).To(Equal("another-hash"))
+			Expect(updatedUser.Role).To(Equal(domain.RoleDoorStaff))
+			Expect(updatedUser.Disabled).To(BeTrue())
+
+			foundUser, err := testStorage.FindUserByUsername("kevin")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(foundUser.PasswordHash).To(Equal("another-hash"))
+			Expect(foundUser.Role).To(Equal(domain.RoleDoorStaff))
+			Expect(foundUser.Disabled).To(BeTrue())
+		})
+
+		It("should update the two-factor secret, last step and recovery code hashes", func() {
+			newUser := insertUser("kevin")
+			Expect(newUser.TOTPEnabled).To(BeFalse())
+			Expect(newUser.RecoveryCodeHashes).To(BeEmpty())
+
+			newUser.TOTPSecret = "some-secret"
+			newUser.TOTPEnabled = true
+			newUser.TOTPLastStep = 12345
+			newUser.RecoveryCodeHashes = []string{"first-hash", "second-hash"}
+
+			_, err := testStorage.UpdateUser(newUser)
+			Expect(err).ToNot(HaveOccurred())
+
+			foundUser, err := testStorage.FindUserByUsername("kevin")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(foundUser.TOTPSecret).To(Equal("some-secret"))
+			Expect(foundUser.TOTPEnabled).To(BeTrue())
+			Expect(foundUser.TOTPLastStep).To(Equal(int64(12345)))
+			Expect(foundUser.RecoveryCodeHashes).To(Equal([]string{"first-hash", "second-hash"}))
+		})
+
+		It("should return not found errors for unknown users", func() {
+			_, err := testStorage.FindUserByID(123)
+			Expect(err).To(BeAssignableToTypeOf(storage.StorageRecordNotFoundError{}))
+
+			_, err = testStorage.FindUserByUsername("nobody")
+			Expect(err).To(BeAssignableToTypeOf(storage.StorageRecordNotFoundError{}))
+
+			_, err = testStorage.UpdateUser(&domain.User{ID: 123, PasswordHash: "some-hash"})
+			Expect(err).To(BeAssignableToTypeOf(storage.StorageRecordNotFoundError{}))
+		})
+	})
+
+	Context("api key storage", func() {
+
+		insertAPIKey := func(name, keyHash, expiresAt string) *domain.APIKey {
+			newAPIKey, err := testStorage.InsertAPIKey(&domain.APIKey{
+				Name:      name,
+				Prefix:    "abcd1234",
+				KeyHash:   keyHash,
+				Scopes:    []domain.Scope{domain.ScopeInvitationsRead, domain.ScopeRSVPsWrite},
+				CreatedBy: "admin",
+				ExpiresAt: expiresAt,
+			})
+			Expect(err).ToNot(HaveOccurred())
+
+			return newAPIKey
+		}
+
+		It("should insert and find an api key by id and hash", func() {
+			newAPIKey := insertAPIKey("spreadsheet", "some-hash", "2030-01-02T03:04:05Z")
+			Expect(newAPIKey.ID).ToNot(BeZero())
+			Expect(newAPIKey.Name).To(Equal("spreadsheet"))
+			Expect(newAPIKey.Prefix).To(Equal("abcd1234"))
+			Expect(newAPIKey.KeyHash).To(Equal("some-hash"))
+			Expect(newAPIKey.Scopes).To(Equal([]domain.Scope{domain.ScopeInvitationsRead, domain.ScopeRSVPsWrite}))
+			Expect(newAPIKey.CreatedBy).To(Equal("admin"))
+			Expect(newAPIKey.ExpiresAt).To(Equal("2030-01-02T03:04:05Z"))
+			Expect(newAPIKey.LastUsedAt).To(BeEmpty())
+			Expect(newAPIKey.RevokedAt).To(BeEmpty())
+			Expect(newAPIKey.CreatedAt).ToNot(BeEmpty())
+
+			foundAPIKey, err := testStorage.FindAPIKeyByID(newAPIKey.ID)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(foundAPIKey).To(Equal(newAPIKey))
+
+			foundAPIKey, err = testStorage.FindAPIKeyByHash("some-hash")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(foundAPIKey).To(Equal(newAPIKey))
+		})
+
+		It("should list api keys in the order they were created", func() {
+			insertAPIKey("spreadsheet", "some-hash", "")
+			insertAPIKey("backup", "another-hash", "")
+
+			apiKeys, err := testStorage.ListAPIKeys()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(apiKeys).To(HaveLen(2))
+			Expect(apiKeys[0].Name).To(Equal("spreadsheet"))
+			Expect(apiKeys[0].ExpiresAt).To(BeEmpty())
+			Expect(apiKeys[1].Name).To(Equal("backup"))
+		})
+
+		It("should revoke an api key once and keep listing it", func() {
+			newAPIKey := insertAPIKey("spreadsheet", "some-hash", "")
+
+			revokedAPIKey, err := testStorage.RevokeAPIKey(newAPIKey.ID)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(revokedAPIKey.RevokedAt).ToNot(BeEmpty())
+
+			revokedAgain, err := testStorage.RevokeAPIKey(newAPIKey.ID)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(revokedAgain.RevokedAt).To(Equal(revokedAPIKey.RevokedAt))
+
+			apiKeys, err := testStorage.ListAPIKeys()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(apiKeys).To(HaveLen(1))
+			Expect(apiKeys[0].RevokedAt).To(Equal(revokedAPIKey.RevokedAt))
+		})
+
+		It("should record when an api key was last used", func() {
+			newAPIKey := insertAPIKey("spreadsheet", "some-hash", "")
+
+			lastUsedAt := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+			Expect(testStorage.UpdateAPIKeyLastUsed(newAPIKey.ID, lastUsedAt)).To(Succeed())
+
+			foundAPIKey, err := testStorage.FindAPIKeyByID(newAPIKey.ID)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(foundAPIKey.LastUsedAt).To(Equal("2026-10-17T12:00:00Z"))
+		})
+
+		It("should return not found errors for unknown api keys", func() {
+			_, err := testStorage.FindAPIKeyByID(123)
+			Expect(err).To(BeAssignableToTypeOf(storage.StorageRecordNotFoundError{}))
+
+			_, err = testStorage.FindAPIKeyByHash("unknown-hash")
+			Expect(err).To(BeAssignableToTypeOf(storage.StorageRecordNotFoundError{}))
+
+			_, err = testStorage.RevokeAPIKey(123)
+			Expect(err).To(BeAssignableToTypeOf(storage.StorageRecordNotFoundError{}))
+
+			err = testStorage.UpdateAPIKeyLastUsed(123, time.Now())
+			Expect(err).To(BeAssignableToTypeOf(storage.StorageRecordNotFoundError{}))
+		})
+	})
+
+	Context("message storage", func() {
+
+		var invitationID int64
+
+		BeforeEach(func() {
+			invitationID = insertInvitation(insertCategory("family").ID, "ah ma").ID
+		})
+
+		insertMessage := func(invitationID int64, status domain.MessageStatus) *domain.Message {
+			newMessage, err := testStorage.InsertMessage(&domain.Message{
+				InvitationID:      invitationID,
+				Kind:              domain.InvitationMessage,
+				Channel:           domain.SMSChannel,
+				Recipient:         "+6591234567",
+				Body:              "You are invited",
+				Provider:          "twilio",
+				ProviderMessageID: "SM123",
+				Status:            status,
+			})
+			Expect(err).ToNot(HaveOccurred())
+
+			return newMessage
+		}
+
+		It("should insert a message along with the provider's result", func() {
+			newMessage := insertMessage(invitationID, domain.MessageSent)
+			Expect(newMessage.ID).ToNot(BeZero())
+			Expect(newMessage.InvitationID).To(Equal(invitationID))
+			Expect(newMessage.Kind).To(Equal(domain.InvitationMessage))
+			Expect(newMessage.Channel).To(Equal(domain.SMSChannel))
+			Expect(newMessage.Recipient).To(Equal("+6591234567"))
+			Expect(newMessage.Body).To(Equal("You are invited"))
+			Expect(newMessage.Provider).To(Equal("twilio"))
+			Expect(newMessage.ProviderMessageID).To(Equal("SM123"))
+			Expect(newMessage.Status).To(Equal(domain.MessageSent))
+			Expect(newMessage.Error).To(BeEmpty())
+			Expect(newMessage.CreatedAt).ToNot(BeEmpty())
+			Expect(newMessage.UpdatedAt).To(Equal(newMessage.CreatedAt))
+		})
+
+		It("should insert an email along with its subject", func() {
+			newMessage, err := testStorage.InsertMessage(&domain.Message{
+				InvitationID:      invitationID,
+				Channel:           domain.EmailChannel,
+				Recipient:         "ahma@example.com",
+				Subject:           "You're invited!",
+				Body:              "You are invited",
+				Provider:          "smtp",
+				ProviderMessageID: "<abc@example.com>",
+				Status:            domain.MessageSent,
+			})
+			Expect(err).ToNot(HaveOccurred())
+
+			messages, err := testStorage.ListMessagesByInvitationID(invitationID)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(messages).To(Equal([]domain.Message{*newMessage}))
+			Expect(messages[0].Channel).To(Equal(domain.EmailChannel))
+			Expect(messages[0].Subject).To(Equal("You're invited!"))
+		})
+
+		It("should list the messages of an invitation in the order they were sent", func() {
+			otherInvitationID := insertInvitation(insertCategory("friends").ID, "ah gong").ID
+
+			firstMessage := insertMessage(invitationID, domain.MessageFailed)
+			insertMessage(otherInvitationID, domain.MessageSent)
+			secondMessage := insertMessage(invitationID, domain.MessageSent)
+
+			messages, err := testStorage.ListMessagesByInvitationID(invitationID)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(messages).To(Equal([]domain.Message{*firstMessage, *secondMessage}))
+
+			messages, err = testStorage.ListMessagesByInvitationID(123)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(messages).To(BeEmpty())
+		})
+
+		It("should list the messages of a kind across invitations in the order they were sent", func() {
+			otherInvitationID := insertInvitation(insertCategory("friends").ID, "ah gong").ID
+
+			firstInvitation := insertMessage(invitationID, domain.MessageSent)
+			reminder, err := testStorage.InsertMessage(&domain.Message{
+				InvitationID: invitationID,
+				Kind:         domain.ReminderMessage,
+				Channel:      domain.SMSChannel,
+				Recipient:    "+6591234567",
+				Body:         "We have not heard from you",
+				Provider:     "twilio",
+				Status:       domain.MessageSent,
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(reminder.Kind).To(Equal(domain.ReminderMessage))
+			secondInvitation := insertMessage(otherInvitationID, domain.MessageFailed)
+
+			messages, err := testStorage.ListMessagesByKind(domain.InvitationMessage)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(messages).To(Equal([]domain.Message{*firstInvitation, *secondInvitation}))
+
+			messages, err = testStorage.ListMessagesByKind(domain.ReminderMessage)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(messages).To(Equal([]domain.Message{*reminder}))
+
+			messages, err = testStorage.ListMessagesByKind(domain.ConfirmationMessage)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(messages).To(BeEmpty())
+		})
+
+		It("should list the messages of several invitations in the order they were sent", func() {
+			otherInvitationID := insertInvitation(insertCategory("friends").ID, "ah gong").ID
+			unlistedInvitationID := insertInvitation(insertCategory("colleagues").ID, "boss").ID
+
+			firstMessage := insertMessage(otherInvitationID, domain.MessageSent)
+			insertMessage(unlistedInvitationID, domain.MessageSent)
+			secondMessage := insertMessage(invitationID, domain.MessageQueued)
+
+			messages, err := testStorage.ListMessagesByInvitationIDs([]int64{invitationID, otherInvitationID})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(messages).To(Equal([]domain.Message{*firstMessage, *secondMessage}))
+
+			messages, err = testStorage.ListMessagesByInvitationIDs(nil)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(messages).To(BeEmpty())
+		})
+
+		It("should find the latest message the provider gave an id", func() {
+			insertMessage(invitationID, domain.MessageFailed)
+			latestMessage := insertMessage(invitationID, domain.MessageQueued)
+
+			foundMessage, err := testStorage.FindMessageByProviderMessageID("twilio", "SM123")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(foundMessage).To(Equal(latestMessage))
+
+			_, err = testStorage.FindMessageByProviderMessageID("smtp", "SM123")
+			Expect(err).To(BeAssignableToTypeOf(storage.StorageRecordNotFoundError{}))
+		})
+
+		It("should update the status of a message along with the reason it failed", func() {
+			newMessage := insertMessage(invitationID, domain.MessageQueued)
+			newMessage.Status = domain.MessageFailed
+			newMessage.Error = "carrier rejected"
+
+			updatedMessage, err := testStorage.UpdateMessage(newMessage)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(updatedMessage.Status).To(Equal(domain.MessageFailed))
+			Expect(updatedMessage.Error).To(Equal("carrier rejected"))
+			Expect(updatedMessage.UpdatedAt).ToNot(BeEmpty())
+
+			messages, err := testStorage.ListMessagesByInvitationID(invitationID)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(messages).To(Equal([]domain.Message{*updatedMessage}))
+
+			_, err = testStorage.UpdateMessage(&domain.Message{ID: 123, Status: domain.MessageDelivered})
+			Expect(err).To(BeAssignableToTypeOf(storage.StorageRecordNotFoundError{}))
+		})
+	})
+
+	Context("message template storage", func() {
+
+		insertMessageTemplate := func(channel domain.MessageChannel, categoryID int64, language string) *domain.MessageTemplate {
+			newTemplate, err := testStorage.InsertMessageTemplate(&domain.MessageTemplateCreateRequest{
+				BaseMessageTemplate: domain.BaseMessageTemplate{
+					Name:       "invitation",
+					Kind:       domain.InvitationTemplate,
+					Channel:    channel,
+					CategoryID: categoryID,
+					Language:   language,
+					Body:       "{{.Greeting}}, reply at {{.RSVPLink}}",
+				},
+			})
+			Expect(err).ToNot(HaveOccurred())
+
+			return newTemplate
+		}
+
+		It("should insert and find a template", func() {
+			newTemplate := insertMessageTemplate(domain.SMSChannel, 0, "zh-Hans")
+			Expect(newTemplate.ID).ToNot(BeZero())
+			Expect(newTemplate.Kind).To(Equal(domain.InvitationTemplate))
+			Expect(newTemplate.Channel).To(Equal(domain.SMSChannel))
+			Expect(newTemplate.Language).To(Equal("zh-Hans"))
+			Expect(newTemplate.Body).To(Equal("{{.Greeting}}, reply at {{.RSVPLink}}"))
+			Expect(newTemplate.CreatedAt).ToNot(BeEmpty())
+
+			messageTemplate, err := testStorage.FindMessageTemplateByID(newTemplate.ID)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(messageTemplate).To(Equal(newTemplate))
+
+			_, err = testStorage.FindMessageTemplateByID(newTemplate.ID + 1)
+			Expect(err).To(BeAssignableToTypeOf(storage.StorageRecordNotFoundError{}))
+		})
+
+		It("should not allow two templates for the same kind, channel, category and language", func() {
+			categoryID := insertCategory("family").ID
+			insertMessageTemplate(domain.SMSChannel, categoryID, "en")
+			insertMessageTemplate(domain.EmailChannel, categoryID, "en")
+			insertMessageTemplate(domain.SMSChannel, 0, "en")
+			insertMessageTemplate(domain.SMSChannel, categoryID, "")
+
+			_, err := testStorage.InsertMessageTemplate(&domain.MessageTemplateCreateRequest{
+				BaseMessageTemplate: domain.BaseMessageTemplate{
+					Name:       "another invitation",
+					Kind:       domain.InvitationTemplate,
+					Channel:    domain.SMSChannel,
+					CategoryID: categoryID,
+					Language:   "EN",
+					Body:       "{{.Greeting}}",
+				},
+			})
+			Expect(err).To(BeAssignableToTypeOf(storage.StorageMessageTemplateUniqueConstraintError{}))
+		})
+
+		It("should list, update and delete templates", func() {
+			firstTemplate := insertMessageTemplate(domain.SMSChannel, 0, "")
+			secondTemplate := insertMessageTemplate(domain.EmailChannel, 0, "")
+
+			templates, err := testStorage.ListMessageTemplates()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(templates).To(Equal([]domain.MessageTemplate{*firstTemplate, *secondTemplate}))
+
+			secondTemplate.Kind = domain.ReminderTemplate
+			secondTemplate.Subject = "We have not heard from you"
+			secondTemplate.Body = "{{.Greeting}}, please reply"
+
+			updatedTemplate, err := testStorage.UpdateMessageTemplate(secondTemplate)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(updatedTemplate.BaseMessageTemplate).To(Equal(secondTemplate.BaseMessageTemplate))
+			Expect(updatedTemplate.UpdatedAt).ToNot(BeEmpty())
+
+			err = testStorage.DeleteMessageTemplate(firstTemplate)
+			Expect(err).ToNot(HaveOccurred())
+
+			templates, err = testStorage.ListMessageTemplates()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(templates).To(Equal([]domain.MessageTemplate{*updatedTemplate}))
+
+			err = testStorage.DeleteMessageTemplate(firstTemplate)
+			Expect(err).To(BeAssignableToTypeOf(storage.StorageRecordNotFoundError{}))
+		})
+	})
+
+	Context("audit", func() {
+
+		insertAuditEntry := func(actor domain.Actor, entityType domain.AuditEntityType, entityID int64) *domain.AuditEntry {
+			newEntry, err := testStorage.InsertAuditEntry(&domain.AuditEntryCreateRequest{
+				Actor:      actor,
+				Action:     domain.AuditUpdated,
+				EntityType: entityType,
+				EntityID:   entityID,
+				Before:     []byte(`{"tag":"family"}`),
+				After:      []byte(`{"tag":"friends"}`),
+			})
+			Expect(err).ToNot(HaveOccurred())
+
+			return newEntry
+		}
+
+		It("should insert an entry with the actor and snapshots of the change", func() {
+			newEntry := insertAuditEntry(domain.NewGuestActor("some-private-id"), domain.RSVPAuditEntity, 1)
+			Expect(newEntry.ID).ToNot(BeZero())
+			Expect(newEntry.Actor).To(Equal(domain.GuestActor))
+			Expect(newEntry.ActorInvitationPrivateID).To(Equal("some-private-id"))
+			Expect(newEntry.Action).To(Equal(domain.AuditUpdated))
+			Expect(newEntry.EntityType).To(Equal(domain.RSVPAuditEntity))
+			Expect(newEntry.EntityID).To(Equal(int64(1)))
+			Expect(newEntry.Before).To(MatchJSON(`{"tag":"family"}`))
+			Expect(newEntry.After).To(MatchJSON(`{"tag":"friends"}`))
+			Expect(newEntry.CreatedAt).ToNot(BeEmpty())
+
+			entries, total, err := testStorage.ListAuditEntries(&domain.AuditListRequest{ListRequest: firstPage("-createdAt")})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(total).To(Equal(1))
+			Expect(entries).To(HaveLen(1))
+			Expect(entries[0].ID).To(Equal(newEntry.ID))
+			Expect(entries[0].Before).To(MatchJSON(`{"tag":"family"}`))
+			Expect(entries[0].After).To(MatchJSON(`{"tag":"friends"}`))
+		})
+
+		It("should store absent snapshots as null", func() {
+			newEntry, err := testStorage.InsertAuditEntry(&domain.AuditEntryCreateRequest{
+				Actor:      domain.NewAdminActor("admin"),
+				Action:     domain.AuditCreated,
+				EntityType: domain.CategoryAuditEntity,
+				EntityID:   1,
+				Before:     []byte("null"),
+				After:      []byte(`{"tag":"family"}`),
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(newEntry.Before).To(MatchJSON("null"))
+		})
+
+		It("should list entries newest first and filter them by entity", func() {
+			first := insertAuditEntry(domain.NewAdminActor("admin"), domain.CategoryAuditEntity, 1)
+			second := insertAuditEntry(domain.NewAdminActor("admin"), domain.InvitationAuditEntity, 1)
+			third := insertAuditEntry(domain.NewAdminActor("admin"), domain.InvitationAuditEntity, 2)
+
+			entries, total, err := testStorage.ListAuditEntries(&domain.AuditListRequest{ListRequest: firstPage("-createdAt")})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(total).To(Equal(3))
+			Expect(entries).To(HaveLen(3))
+			Expect(entries[0].ID).To(Equal(third.ID))
+			Expect(entries[1].ID).To(Equal(second.ID))
+			Expect(entries[2].ID).To(Equal(first.ID))
+
+			entries, total, err = testStorage.ListAuditEntries(&domain.AuditListRequest{
+				ListRequest: firstPage("-createdAt"),
+				EntityType:  domain.InvitationAuditEntity,
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(total).To(Equal(2))
+			Expect(entries).To(HaveLen(2))
+
+			entries, total, err = testStorage.ListAuditEntries(&domain.AuditListRequest{
+				ListRequest: firstPage("-createdAt"),
+				EntityType:  domain.InvitationAuditEntity,
+				EntityID:    2,
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(total).To(Equal(1))
+			Expect(entries).To(HaveLen(1))
+			Expect(entries[0].ID).To(Equal(third.ID))
+		})
+
+		It("should page entries", func() {
+			for idx := int64(1); idx <= 3; idx++ {
+				insertAuditEntry(domain.NewAdminActor("admin"), domain.CategoryAuditEntity, idx)
+			}
+
+			entries, total, err := testStorage.ListAuditEntries(&domain.AuditListRequest{
+				ListRequest: domain.ListRequest{Page: 2, PageSize: 2, Sort: "createdAt"},
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(total).To(Equal(3))
+			Expect(entries).To(HaveLen(1))
+			Expect(entries[0].EntityID).To(Equal(int64(3)))
+		})
+
+		It("should roll back entries with the transaction they were written in", func() {
+			err := testStorage.WithTx(func(tx interfaces.Storage) error {
+				_, err := tx.InsertAuditEntry(&domain.AuditEntryCreateRequest{
+					Actor:      domain.NewAdminActor("admin"),
+					Action:     domain.AuditDeleted,
+					EntityType: domain.CategoryAuditEntity,
+					EntityID:   1,
+					Before:     []byte(`{"tag":"family"}`),
+					After:      []byte("null"),
+				})
+				Expect(err).ToNot(HaveOccurred())
+
+				return storage.NewStorageOperationError()
+			})
+			Expect(err).To(HaveOccurred())
+
+			_, total, err := testStorage.ListAuditEntries(&domain.AuditListRequest{ListRequest: firstPage("-createdAt")})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(total).To(BeZero())
+		})
+	})
+
+	Context("transactions", func() {
+
+		It("should commit every write when the callback succeeds", func() {
+			var categoryID int64
+
+			err := testStorage.WithTx(func(tx interfaces.Storage) error {
+				newCategory, err := tx.InsertCategory(&domain.CategoryCreateRequest{Tag: "family"})
+				if err != nil {
+					return err
+				}
+				categoryID = newCategory.ID
+
+				_, err = tx.InsertInvitation(&domain.InvitationCreateRequest{
+					BaseInvitation: domain.BaseInvitation{
+						CategoryID:        categoryID,
+						Greeting:          "ah ma",
+						MaximumGuestCount: 2,
+					},
+				})
+				return err
+			})
+			Expect(err).ToNot(HaveOccurred())
+
+			category, err := testStorage.FindCategoryByID(categoryID)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(category.Total).To(Equal(1))
+		})
+
+		It("should roll back every write and return the callback error as is when the callback fails", func() {
+			existingCategory := insertCategory("friends")
+			callbackErr := storage.NewStorageRecordNotFoundError()
+
+			err := testStorage.WithTx(func(tx interfaces.Storage) error {
+				_, err := tx.InsertCategory(&domain.CategoryCreateRequest{Tag: "family"})
+				Expect(err).ToNot(HaveOccurred())
+
+				existingCategory.Tag = "colleagues"
+				_, err = tx.UpdateCategory(existingCategory)
+				Expect(err).ToNot(HaveOccurred())
+
+				return callbackErr
+			})
+			Expect(err).To(Equal(callbackErr))
+
+			categories, _, err := testStorage.ListCategories(&domain.CategoryListRequest{ListRequest: firstPage("-tag")})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(categories).To(HaveLen(1))
+			Expect(categories[0].Tag).To(Equal("friends"))
+		})
+
+		It("should join the open transaction when nested", func() {
+			err := testStorage.WithTx(func(tx interfaces.Storage) error {
+				_, err := tx.InsertCategory(&domain.CategoryCreateRequest{Tag: "family"})
+				Expect(err).ToNot(HaveOccurred())
+
+				return tx.WithTx(func(nestedTx interfaces.Storage) error {
+					_, err := nestedTx.InsertCategory(&domain.CategoryCreateRequest{Tag: "friends"})
+					Expect(err).ToNot(HaveOccurred())
+
+					return storage.NewStorageOperationError()
+				})
+			})
+			Expect(err).To(BeAssignableToTypeOf(storage.StorageOperationError{}))
+
+			categories, total, err := testStorage.ListCategories(&domain.CategoryListRequest{ListRequest: firstPage("-tag")})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(total).To(BeZero())
+			Expect(categories).To(BeEmpty())
+		})
+	})
+}
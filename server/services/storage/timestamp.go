@@ -0,0 +1,22 @@
+package storage
+
+import (
+	"time"
+)
+
+// ParseOptionalTimestamp reads the RFC 3339 timestamps of the domain models, which are left empty
+// when they have not happened, into the nil or time the backends store
+func ParseOptionalTimestamp(timestamp string) (*time.Time, error) {
+	if timestamp == "" {
+		return nil, nil
+	}
+
+	parsed, err := time.Parse(time.RFC3339, timestamp)
+	if err != nil {
+		return nil, err
+	}
+
+	parsed = parsed.UTC()
+
+	return &parsed, nil
+}
@@ -0,0 +1,316 @@
+package throttle
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/rawfish-dev/rsvp-starter/server/config"
+	"github.com/rawfish-dev/rsvp-starter/server/domain"
+	"github.com/rawfish-dev/rsvp-starter/server/interfaces"
+	"github.com/rawfish-dev/rsvp-starter/server/services/audit"
+	serviceErrors "github.com/rawfish-dev/rsvp-starter/server/services/errors"
+	"github.com/rawfish-dev/rsvp-starter/server/services/storage"
+
+	"golang.org/x/net/context"
+)
+
+var _ interfaces.LoginThrottleServiceProvider = new(service)
+
+// mutex keeps concurrent attempts from overwriting each other's counts or getting between checking
+// for a lockout and reserving an attempt, since the cache has no atomic increment
+var mutex sync.Mutex
+
+// Namespaces keep the attempts counted by each throttle apart, so guests confirming their mobile phone
+// number cannot lock admins out of logging in from the same client IP
+const (
+	LoginNamespace = "login"
+	GuestNamespace = "guest"
+)
+
+type service struct {
+	ctx          context.Context
+	namespace    string
+	loginConfig  config.LoginConfig
+	cacheService interfaces.CacheServiceProvider
+	auditStorage interfaces.Storage
+}
+
+func NewService(ctx context.Context,
+	namespace string,
+	loginConfig config.LoginConfig,
+	cacheService interfaces.CacheServiceProvider,
+	auditStorage interfaces.Storage) *service {
+	return &service{ctx, namespace, loginConfig, cacheService, auditStorage}
+}
+
+// reservationTimeout is how long a reserved attempt counts against its keys if it is never settled,
+// such as when the request handling it fails part way
+const reservationTimeout = time.Minute
+
+// attempts are the failed logins of a username or client IP within the attempt window. Pending holds
+// when each attempt reserved but not yet settled stops counting.
+type attempts struct {
+	Failures     int         `json:"failures"`
+	BlockedUntil time.Time   `json:"blockedUntil"`
+	Pending      []time.Time `json:"pending,omitempty"`
+}
+
+// ReserveAttempt returns how long to wait before the username may be tried again from the client IP.
+// When that is zero the attempt is reserved against both in the same step, so concurrent attempts
+// cannot all get through before any of them fails. Every reserved attempt has to be settled with
+// RecordFailure, RecordSuccess or ReleaseAttempt.
+func (s *service) ReserveAttempt(username, clientIP string) (wait time.Duration, err error) {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	usernameAttempts, err := s.findAttempts(s.usernameKey(username))
+	if err != nil {
+		return 0, err
+	}
+
+	clientIPAttempts, err := s.findAttempts(s.clientIPKey(clientIP))
+	if err != nil {
+		return 0, err
+	}
+
+	for _, current := range []*attempts{usernameAttempts, clientIPAttempts} {
+		if keyWait := time.Until(current.BlockedUntil); keyWait > wait {
+			wait = keyWait
+		}
+	}
+	if wait > 0 {
+		return wait, nil
+	}
+
+	// Attempts underway could use up what is left before a lockout, and any failure of the username
+	// holds it back for at least the backoff
+	if len(usernameAttempts.Pending) > 0 || clientIPAttempts.Failures+len(clientIPAttempts.Pending) >= s.loginConfig.MaxAttemptsPerIP {
+		return s.loginConfig.Backoff, nil
+	}
+
+	pendingUntil := time.Now().UTC().Add(reservationTimeout)
+	usernameAttempts.Pending = append(usernameAttempts.Pending, pendingUntil)
+	clientIPAttempts.Pending = append(clientIPAttempts.Pending, pendingUntil)
+
+	err = s.saveAttempts(s.usernameKey(username), usernameAttempts)
+	if err != nil {
+		return 0, err
+	}
+
+	return 0, s.saveAttempts(s.clientIPKey(clientIP), clientIPAttempts)
+}
+
+// RecordFailure settles a reserved attempt as a failed login against both the username and the client
+// IP and records an audit entry for either being locked out
+func (s *service) RecordFailure(username, clientIP string) (err error) {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	usernameLockedUntil, err := s.recordFailure(s.usernameKey(username), s.loginConfig.MaxAttempts, true)
+	if err != nil {
+		return err
+	}
+	if !usernameLockedUntil.IsZero() {
+		err = s.recordLockout(username, clientIP, usernameLockedUntil)
+		if err != nil {
+			return err
+		}
+	}
+
+	clientIPLockedUntil, err := s.recordFailure(s.clientIPKey(clientIP), s.loginConfig.MaxAttemptsPerIP, false)
+	if err != nil {
+		return err
+	}
+	if !clientIPLockedUntil.IsZero() {
+		return s.recordLockout("", clientIP, clientIPLockedUntil)
+	}
+
+	return nil
+}
+
+// RecordSuccess settles a reserved attempt as a successful login and forgets the failures of the
+// username. Those of the client IP are kept so logging in to one account cannot be used to keep
+// guessing at others.
+func (s *service) RecordSuccess(username, clientIP string) (err error) {
+	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
+
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	err = s.cacheService.Delete(s.usernameKey(username))
+	if err != nil {
+		ctxLogger.Errorf("throttle service - unable to clear failed logins of %v due to %v", username, err)
+		return serviceErrors.NewGeneralServiceError()
+	}
+
+	return s.release(s.clientIPKey(clientIP))
+}
+
+// ReleaseAttempt settles a reserved attempt that neither failed nor completed a login, such as a
+// correct password still waiting on a two-factor code
+func (s *service) ReleaseAttempt(username, clientIP string) (err error) {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	err = s.release(s.usernameKey(username))
+	if err != nil {
+		return err
+	}
+
+	return s.release(s.clientIPKey(clientIP))
+}
+
+// release gives back the oldest attempt reserved against the key
+func (s *service) release(key string) error {
+	current, err := s.findAttempts(key)
+	if err != nil {
+		return err
+	}
+
+	if len(current.Pending) == 0 {
+		return nil
+	}
+	current.Pending = current.Pending[1:]
+
+	return s.saveAttempts(key, current)
+}
+
+// recordFailure settles the oldest attempt reserved against the key as a failure and returns when
+// the key is locked out until if this failure locked it out. The count starts over after a lockout.
+// With backoff, every failure before that doubles the wait.
+func (s *service) recordFailure(key string, maxAttempts int, backoff bool) (lockedUntil time.Time, err error) {
+	current, err := s.findAttempts(key)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	now := time.Now().UTC()
+	current.Failures++
+	if len(current.Pending) > 0 {
+		current.Pending = current.Pending[1:]
+	}
+
+	switch {
+	case current.Failures >= maxAttempts:
+		current.Failures = 0
+		current.BlockedUntil = now.Add(s.loginConfig.LockoutDuration)
+		lockedUntil = current.BlockedUntil
+	case backoff:
+		current.BlockedUntil = now.Add(s.backoff(current.Failures))
+	}
+
+	err = s.saveAttempts(key, current)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return lockedUntil, nil
+}
+
+// saveAttempts keeps the count for the attempt window after the key can be tried again
+func (s *service) saveAttempts(key string, current *attempts) error {
+	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
+
+	attemptsBytes, err := json.Marshal(current)
+	if err != nil {
+		ctxLogger.Errorf("throttle service - unable to marshal failed logins of %v due to %v", key, err)
+		return serviceErrors.NewGeneralServiceError()
+	}
+
+	expiryInSeconds := int((s.loginConfig.AttemptWindow + time.Until(current.BlockedUntil)).Seconds())
+	err = s.cacheService.SetWithExpiry(key, string(attemptsBytes), expiryInSeconds)
+	if err != nil {
+		ctxLogger.Errorf("throttle service - unable to save failed logins of %v due to %v", key, err)
+		return serviceErrors.NewGeneralServiceError()
+	}
+
+	return nil
+}
+
+// backoff doubles with every failure and never goes past the lockout duration
+func (s *service) backoff(failures int) time.Duration {
+	wait := s.loginConfig.Backoff
+	for idx := 1; idx < failures && wait < s.loginConfig.LockoutDuration; idx++ {
+		wait *= 2
+	}
+
+	if wait > s.loginConfig.LockoutDuration {
+		return s.loginConfig.LockoutDuration
+	}
+
+	return wait
+}
+
+// recordLockout audits the lockout against the user with the username, or against no user for
+// usernames that do not exist, client IP lockouts and guests
+func (s *service) recordLockout(username, clientIP string, lockedUntil time.Time) error {
+	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
+
+	ctxLogger.Warnf("throttle service - locked out username %q from client ip %v until %v", username, clientIP, lockedUntil)
+
+	lockout := domain.LoginLockout{
+		Username:    username,
+		ClientIP:    clientIP,
+		LockedUntil: lockedUntil.Format(time.RFC3339),
+	}
+
+	err := s.auditStorage.WithTx(func(tx interfaces.Storage) error {
+		var userID int64
+
+		if username != "" && s.namespace == LoginNamespace {
+			user, err := tx.FindUserByUsername(username)
+			if err != nil {
+				switch err.(type) {
+				case storage.StorageRecordNotFoundError:
+				default:
+					return serviceErrors.NewGeneralServiceError()
+				}
+			} else {
+				userID = user.ID
+			}
+		}
+
+		return audit.Record(s.ctx, tx, domain.AuditLockedOut, domain.LoginAuditEntity, userID, nil, lockout)
+	})
+
+	return serviceErrors.FromTransaction(err)
+}
+
+func (s *service) findAttempts(key string) (*attempts, error) {
+	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
+
+	value, err := s.cacheService.Get(key)
+	if err != nil {
+		ctxLogger.Errorf("throttle service - unable to get failed logins of %v due to %v", key, err)
+		return nil, serviceErrors.NewGeneralServiceError()
+	}
+
+	var current attempts
+	if value == "" {
+		return &current, nil
+	}
+
+	err = json.Unmarshal([]byte(value), &current)
+	if err != nil {
+		ctxLogger.Errorf("throttle service - unable to unmarshal failed logins of %v due to %v", key, err)
+		return nil, serviceErrors.NewGeneralServiceError()
+	}
+
+	// Reservations are appended as they are made, so those that timed out come first
+	now := time.Now().UTC()
+	for len(current.Pending) > 0 && !current.Pending[0].After(now) {
+		current.Pending = current.Pending[1:]
+	}
+
+	return &current, nil
+}
+
+func (s *service) usernameKey(username string) string {
+	return fmt.Sprintf("%v:username:%v", s.namespace, username)
+}
+
+func (s *service) clientIPKey(clientIP string) string {
+	return fmt.Sprintf("%v:ip:%v", s.namespace, clientIP)
+}
@@ -0,0 +1,230 @@
+package throttle_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/rawfish-dev/rsvp-starter/server/config"
+	"github.com/rawfish-dev/rsvp-starter/server/domain"
+	"github.com/rawfish-dev/rsvp-starter/server/interfaces"
+	"github.com/rawfish-dev/rsvp-starter/server/mock"
+	"github.com/rawfish-dev/rsvp-starter/server/services/cache"
+	"github.com/rawfish-dev/rsvp-starter/server/services/storage"
+	. "github.com/rawfish-dev/rsvp-starter/server/services/throttle"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"golang.org/x/net/context"
+)
+
+var _ = Describe("Throttle", func() {
+
+	var ctrl *gomock.Controller
+	var mockAuditStorage *mock_interfaces.MockTransactionalStorage
+	var testLoginThrottle interfaces.LoginThrottleServiceProvider
+	var testGuestThrottle interfaces.LoginThrottleServiceProvider
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+
+		ctxlogger := logrus.New()
+		ctx := context.Background()
+		ctx = context.WithValue(ctx, "logger", ctxlogger)
+
+		cacheService := cache.NewService(ctx)
+		Expect(cacheService.Flush()).To(Succeed())
+
+		loginConfig := config.LoginConfig{
+			MaxAttempts:      3,
+			MaxAttemptsPerIP: 5,
+			Backoff:          time.Minute,
+			LockoutDuration:  time.Hour,
+			AttemptWindow:    time.Hour,
+		}
+
+		mockAuditStorage = mock_interfaces.NewMockTransactionalStorage(ctrl)
+		testLoginThrottle = NewService(ctx, LoginNamespace, loginConfig, cacheService, mockAuditStorage)
+		testGuestThrottle = NewService(ctx, GuestNamespace, loginConfig, cacheService, mockAuditStorage)
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	// expectWait gives back the attempt when it is let through so only the wait is checked
+	expectWait := func(username, clientIP string, expectedWait time.Duration) {
+		wait, err := testLoginThrottle.ReserveAttempt(username, clientIP)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(wait).To(BeNumerically("~", expectedWait, time.Second))
+
+		if wait == 0 {
+			Expect(testLoginThrottle.ReleaseAttempt(username, clientIP)).To(Succeed())
+		}
+	}
+
+	fail := func(username, clientIP string) {
+		wait, err := testLoginThrottle.ReserveAttempt(username, clientIP)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(wait).To(BeZero())
+
+		Expect(testLoginThrottle.RecordFailure(username, clientIP)).To(Succeed())
+	}
+
+	It("should let logins through without failures", func() {
+		expectWait("kevin", "10.0.0.1", 0)
+	})
+
+	It("should double the wait with every failure of the same username", func() {
+		fail("kevin", "10.0.0.1")
+		expectWait("kevin", "10.0.0.2", time.Minute)
+
+		Expect(testLoginThrottle.RecordFailure("kevin", "10.0.0.2")).To(Succeed())
+		expectWait("kevin", "10.0.0.3", time.Minute*2)
+
+		// Other usernames from other client IPs are not held up
+		expectWait("jenny", "10.0.0.3", 0)
+	})
+
+	It("should lock out and audit a username after too many failures", func() {
+		mockAuditStorage.EXPECT().FindUserByUsername("kevin").Return(&domain.User{ID: 7, Username: "kevin"}, nil)
+		mockAuditStorage.EXPECT().InsertAuditEntry(gomock.Any()).
+			Do(func(req *domain.AuditEntryCreateRequest) {
+				Expect(req.Action).To(Equal(domain.AuditLockedOut))
+				Expect(req.EntityType).To(Equal(domain.LoginAuditEntity))
+				Expect(req.EntityID).To(Equal(int64(7)))
+
+				var lockout domain.LoginLockout
+				Expect(json.Unmarshal(req.After, &lockout)).To(Succeed())
+				Expect(lockout.Username).To(Equal("kevin"))
+				Expect(lockout.ClientIP).To(Equal("10.0.0.3"))
+			}).
+			Return(&domain.AuditEntry{}, nil)
+
+		Expect(testLoginThrottle.RecordFailure("kevin", "10.0.0.1")).To(Succeed())
+		Expect(testLoginThrottle.RecordFailure("kevin", "10.0.0.2")).To(Succeed())
+		Expect(testLoginThrottle.RecordFailure("kevin", "10.0.0.3")).To(Succeed())
+
+		expectWait("kevin", "10.0.0.4", time.Hour)
+	})
+
+	It("should audit lockouts of usernames that do not exist against no user", func() {
+		mockAuditStorage.EXPECT().FindUserByUsername("nobody").Return(nil, storage.NewStorageRecordNotFoundError())
+		mockAuditStorage.EXPECT().InsertAuditEntry(gomock.Any()).
+			Do(func(req *domain.AuditEntryCreateRequest) {
+				Expect(req.EntityID).To(BeZero())
+			}).
+			Return(&domain.AuditEntry{}, nil)
+
+		for idx := 0; idx < 3; idx++ {
+			Expect(testLoginThrottle.RecordFailure("nobody", "10.0.0.1")).To(Succeed())
+		}
+	})
+
+	It("should lock out a client ip after too many failures across usernames", func() {
+		mockAuditStorage.EXPECT().InsertAuditEntry(gomock.Any()).
+			Do(func(req *domain.AuditEntryCreateRequest) {
+				var lockout domain.LoginLockout
+				Expect(json.Unmarshal(req.After, &lockout)).To(Succeed())
+				Expect(lockout.Username).To(BeEmpty())
+				Expect(lockout.ClientIP).To(Equal("10.0.0.1"))
+			}).
+			Return(&domain.AuditEntry{}, nil)
+
+		for _, username := range []string{"a", "b", "c", "d", "e"} {
+			fail(username, "10.0.0.1")
+		}
+
+		expectWait("someone", "10.0.0.1", time.Hour)
+		expectWait("someone", "10.0.0.2", 0)
+	})
+
+	It("should forget failures of the username but not of the client ip on success", func() {
+		fail("kevin", "10.0.0.1")
+		Expect(testLoginThrottle.RecordSuccess("kevin", "10.0.0.1")).To(Succeed())
+
+		expectWait("kevin", "10.0.0.2", 0)
+
+		// Four more failures from the same client ip make five
+		mockAuditStorage.EXPECT().InsertAuditEntry(gomock.Any()).Return(&domain.AuditEntry{}, nil)
+		for _, username := range []string{"a", "b", "c", "d"} {
+			fail(username, "10.0.0.1")
+		}
+
+		expectWait("kevin", "10.0.0.1", time.Hour)
+	})
+
+	It("should hold back attempts of a username while another one is underway", func() {
+		wait, err := testLoginThrottle.ReserveAttempt("kevin", "10.0.0.1")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(wait).To(BeZero())
+
+		expectWait("kevin", "10.0.0.2", time.Minute)
+
+		Expect(testLoginThrottle.ReleaseAttempt("kevin", "10.0.0.1")).To(Succeed())
+		expectWait("kevin", "10.0.0.2", 0)
+	})
+
+	It("should not let more attempts from a client ip through at once than it has left", func() {
+		for _, username := range []string{"a", "b", "c", "d", "e"} {
+			wait, err := testLoginThrottle.ReserveAttempt(username, "10.0.0.1")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(wait).To(BeZero())
+		}
+
+		expectWait("f", "10.0.0.1", time.Minute)
+
+		// Successful logins give their attempt back
+		Expect(testLoginThrottle.RecordSuccess("a", "10.0.0.1")).To(Succeed())
+		expectWait("f", "10.0.0.1", 0)
+	})
+
+	It("should let a single one of many concurrent attempts of a username through", func() {
+		waits := make(chan time.Duration, 10)
+
+		var wg sync.WaitGroup
+		for idx := 0; idx < 10; idx++ {
+			wg.Add(1)
+			go func(idx int) {
+				defer GinkgoRecover()
+				defer wg.Done()
+
+				wait, err := testLoginThrottle.ReserveAttempt("kevin", fmt.Sprintf("10.0.0.%v", idx))
+				Expect(err).ToNot(HaveOccurred())
+				waits <- wait
+			}(idx)
+		}
+		wg.Wait()
+		close(waits)
+
+		letThrough := 0
+		for wait := range waits {
+			if wait == 0 {
+				letThrough++
+			}
+		}
+		Expect(letThrough).To(Equal(1))
+	})
+
+	It("should not count guests confirming their mobile phone number against admin logins", func() {
+		mockAuditStorage.EXPECT().FindUserByUsername(gomock.Any()).Times(0)
+		mockAuditStorage.EXPECT().InsertAuditEntry(gomock.Any()).Return(&domain.AuditEntry{}, nil)
+
+		for _, privateID := range []string{"a", "b", "c", "d", "e"} {
+			wait, err := testGuestThrottle.ReserveAttempt("guest:"+privateID, "10.0.0.1")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(wait).To(BeZero())
+
+			Expect(testGuestThrottle.RecordFailure("guest:"+privateID, "10.0.0.1")).To(Succeed())
+		}
+
+		wait, err := testGuestThrottle.ReserveAttempt("guest:f", "10.0.0.1")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(wait).To(BeNumerically("~", time.Hour, time.Second))
+
+		expectWait("kevin", "10.0.0.1", 0)
+	})
+})
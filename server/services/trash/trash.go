@@ -0,0 +1,87 @@
+package trash
+
+import (
+	"time"
+
+	"github.com/rawfish-dev/rsvp-starter/server/domain"
+	"github.com/rawfish-dev/rsvp-starter/server/interfaces"
+	serviceErrors "github.com/rawfish-dev/rsvp-starter/server/services/errors"
+
+	"golang.org/x/net/context"
+)
+
+var _ interfaces.TrashServiceProvider = new(service)
+
+type service struct {
+	ctx          context.Context
+	trashStorage interfaces.Storage
+}
+
+func NewService(ctx context.Context, trashStorage interfaces.Storage) *service {
+	return &service{ctx, trashStorage}
+}
+
+func (s *service) ListTrash() (*domain.Trash, error) {
+	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
+
+	categories, err := s.trashStorage.ListDeletedCategories()
+	if err != nil {
+		ctxLogger.Error("trash service - unable to list deleted categories")
+		return nil, serviceErrors.NewGeneralServiceError()
+	}
+
+	invitations, err := s.trashStorage.ListDeletedInvitations()
+	if err != nil {
+		ctxLogger.Error("trash service - unable to list deleted invitations")
+		return nil, serviceErrors.NewGeneralServiceError()
+	}
+
+	rsvps, err := s.trashStorage.ListDeletedRSVPs()
+	if err != nil {
+		ctxLogger.Error("trash service - unable to list deleted rsvps")
+		return nil, serviceErrors.NewGeneralServiceError()
+	}
+
+	trash := &domain.Trash{
+		Categories:  categories,
+		Invitations: invitations,
+		RSVPs:       rsvps,
+	}
+
+	return trash, nil
+}
+
+// Purge permanently removes the records deleted before the given time. RSVPs go first and
+// invitations before categories so nothing purged is still referenced.
+func (s *service) Purge(deletedBefore time.Time) (*domain.PurgeResult, error) {
+	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
+
+	result := &domain.PurgeResult{}
+
+	err := s.trashStorage.WithTx(func(tx interfaces.Storage) (err error) {
+		result.RSVPs, err = tx.PurgeRSVPs(deletedBefore)
+		if err != nil {
+			ctxLogger.Errorf("trash service - unable to purge rsvps deleted before %v", deletedBefore)
+			return serviceErrors.NewGeneralServiceError()
+		}
+
+		result.Invitations, err = tx.PurgeInvitations(deletedBefore)
+		if err != nil {
+			ctxLogger.Errorf("trash service - unable to purge invitations deleted before %v", deletedBefore)
+			return serviceErrors.NewGeneralServiceError()
+		}
+
+		result.Categories, err = tx.PurgeCategories(deletedBefore)
+		if err != nil {
+			ctxLogger.Errorf("trash service - unable to purge categories deleted before %v", deletedBefore)
+			return serviceErrors.NewGeneralServiceError()
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, serviceErrors.FromTransaction(err)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,13 @@
+package trash_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestTrash(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Trash Suite")
+}
@@ -0,0 +1,97 @@
+package trash_test
+
+import (
+	"time"
+
+	"github.com/rawfish-dev/rsvp-starter/server/domain"
+	"github.com/rawfish-dev/rsvp-starter/server/interfaces"
+	"github.com/rawfish-dev/rsvp-starter/server/mock"
+	serviceErrors "github.com/rawfish-dev/rsvp-starter/server/services/errors"
+	"github.com/rawfish-dev/rsvp-starter/server/services/storage"
+	. "github.com/rawfish-dev/rsvp-starter/server/services/trash"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"golang.org/x/net/context"
+)
+
+var _ = Describe("Trash", func() {
+
+	var ctrl *gomock.Controller
+	var mockTrashStorage *mock_interfaces.MockTransactionalStorage
+	var testTrashService interfaces.TrashServiceProvider
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+
+		ctxlogger := logrus.New()
+		ctx := context.Background()
+		ctx = context.WithValue(ctx, "logger", ctxlogger)
+
+		mockTrashStorage = mock_interfaces.NewMockTransactionalStorage(ctrl)
+		testTrashService = NewService(ctx, mockTrashStorage)
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	Context("listing", func() {
+
+		It("should return every deleted record", func() {
+			categories := []domain.Category{{ID: 1, Tag: "family", DeletedAt: "2026-10-17T10:00:00Z"}}
+			invitations := []domain.Invitation{{ID: 2, DeletedAt: "2026-10-17T10:00:00Z"}}
+			rsvps := []domain.RSVP{{ID: 3, DeletedAt: "2026-10-17T10:00:00Z"}}
+
+			mockTrashStorage.EXPECT().ListDeletedCategories().Return(categories, nil)
+			mockTrashStorage.EXPECT().ListDeletedInvitations().Return(invitations, nil)
+			mockTrashStorage.EXPECT().ListDeletedRSVPs().Return(rsvps, nil)
+
+			trash, err := testTrashService.ListTrash()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(trash.Categories).To(Equal(categories))
+			Expect(trash.Invitations).To(Equal(invitations))
+			Expect(trash.RSVPs).To(Equal(rsvps))
+		})
+
+		It("should return a general error if the trash cannot be listed", func() {
+			mockTrashStorage.EXPECT().ListDeletedCategories().Return(nil, storage.NewStorageOperationError())
+
+			_, err := testTrashService.ListTrash()
+			Expect(err).To(BeAssignableToTypeOf(serviceErrors.GeneralServiceError{}))
+		})
+	})
+
+	Context("purging", func() {
+
+		var deletedBefore time.Time
+
+		BeforeEach(func() {
+			deletedBefore = time.Date(2026, time.September, 17, 0, 0, 0, 0, time.UTC)
+		})
+
+		It("should purge rsvps, then invitations, then categories and count them", func() {
+			gomock.InOrder(
+				mockTrashStorage.EXPECT().PurgeRSVPs(deletedBefore).Return(3, nil),
+				mockTrashStorage.EXPECT().PurgeInvitations(deletedBefore).Return(2, nil),
+				mockTrashStorage.EXPECT().PurgeCategories(deletedBefore).Return(1, nil),
+			)
+
+			result, err := testTrashService.Purge(deletedBefore)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result).To(Equal(&domain.PurgeResult{Categories: 1, Invitations: 2, RSVPs: 3}))
+		})
+
+		It("should stop and return a general error if any purge fails", func() {
+			gomock.InOrder(
+				mockTrashStorage.EXPECT().PurgeRSVPs(deletedBefore).Return(3, nil),
+				mockTrashStorage.EXPECT().PurgeInvitations(deletedBefore).Return(0, storage.NewStorageOperationError()),
+			)
+
+			_, err := testTrashService.Purge(deletedBefore)
+			Expect(err).To(BeAssignableToTypeOf(serviceErrors.GeneralServiceError{}))
+		})
+	})
+})
@@ -0,0 +1,14 @@
+package user
+
+var _ error = new(UserNotFoundError)
+
+type UserNotFoundError struct {
+}
+
+func NewUserNotFoundError() error {
+	return UserNotFoundError{}
+}
+
+func (u UserNotFoundError) Error() string {
+	return "user not found"
+}
@@ -0,0 +1,533 @@
+package user
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/rawfish-dev/rsvp-starter/server/config"
+	"github.com/rawfish-dev/rsvp-starter/server/domain"
+	"github.com/rawfish-dev/rsvp-starter/server/interfaces"
+	"github.com/rawfish-dev/rsvp-starter/server/services/audit"
+	serviceErrors "github.com/rawfish-dev/rsvp-starter/server/services/errors"
+	"github.com/rawfish-dev/rsvp-starter/server/services/security"
+	"github.com/rawfish-dev/rsvp-starter/server/services/storage"
+	"github.com/rawfish-dev/rsvp-starter/server/utils"
+
+	"golang.org/x/crypto/bcrypt"
+	"golang.org/x/net/context"
+)
+
+const (
+	UsernameMinLength = 1
+	UsernameMaxLength = 50
+	PasswordMinLength = 8
+
+	// bcrypt ignores everything past the first 72 bytes
+	PasswordMaxLength = 72
+)
+
+var usernameFormat = regexp.MustCompile(`^[a-z0-9._-]+$`)
+
+var roleErrorMessage = fmt.Sprintf("role must be one of %v, %v, %v or %v", domain.RoleOwner, domain.RoleEditor, domain.RoleViewer, domain.RoleDoorStaff)
+
+var _ interfaces.UserServiceProvider = new(service)
+
+type service struct {
+	ctx            context.Context
+	securityConfig config.SecurityConfig
+	userStorage    interfaces.Storage
+	sessionService interfaces.SessionServiceProvider
+}
+
+func NewService(ctx context.Context, securityConfig config.SecurityConfig, userStorage interfaces.Storage, sessionService interfaces.SessionServiceProvider) *service {
+	return &service{
+		ctx:            ctx,
+		securityConfig: securityConfig,
+		userStorage:    userStorage,
+		sessionService: sessionService,
+	}
+}
+
+func (s *service) CreateUser(req *domain.UserCreateRequest) (*domain.User, error) {
+	req.Username = strings.ToLower(strings.TrimSpace(req.Username))
+
+	errorMessages := validateUserCreateRequest(req)
+	if len(errorMessages) > 0 {
+		return nil, serviceErrors.NewValidationError(errorMessages)
+	}
+
+	passwordHash, err := s.hashPassword(req.Password)
+	if err != nil {
+		return nil, err
+	}
+
+	var newUser *domain.User
+
+	err = s.userStorage.WithTx(func(tx interfaces.Storage) error {
+		var err error
+
+		newUser, err = tx.InsertUser(&domain.User{
+			Username:     req.Username,
+			PasswordHash: passwordHash,
+			Role:         req.Role,
+		})
+		if err != nil {
+			switch err.(type) {
+			case storage.StorageUserUsernameUniqueConstraintError:
+				return serviceErrors.NewValidationError([]string{err.Error()})
+			}
+
+			return serviceErrors.NewGeneralServiceError()
+		}
+
+		return audit.Record(s.ctx, tx, domain.AuditCreated, domain.UserAuditEntity, newUser.ID, nil, newUser)
+	})
+	if err != nil {
+		return nil, serviceErrors.FromTransaction(err)
+	}
+
+	return newUser, nil
+}
+
+func (s *service) ListUsers() ([]domain.User, error) {
+	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
+
+	users, err := s.userStorage.ListUsers()
+	if err != nil {
+		ctxLogger.Error("user service - unable to list users")
+		return nil, serviceErrors.NewGeneralServiceError()
+	}
+
+	return users, nil
+}
+
+func (s *service) RetrieveUserByUsername(username string) (*domain.User, error) {
+	user, err := s.userStorage.FindUserByUsername(strings.ToLower(username))
+	if err != nil {
+		switch err.(type) {
+		case storage.StorageRecordNotFoundError:
+			return nil, NewUserNotFoundError()
+		}
+
+		return nil, serviceErrors.NewGeneralServiceError()
+	}
+
+	return user, nil
+}
+
+// DisableUserByID keeps at least one enabled owner so the control panel cannot be locked out. Every
+// session of the user is ended.
+func (s *service) DisableUserByID(userID int64) (*domain.User, error) {
+	var disabledUser *domain.User
+
+	err := s.userStorage.WithTx(func(tx interfaces.Storage) error {
+		user, err := findUser(tx, userID)
+		if err != nil {
+			return err
+		}
+
+		if user.Disabled {
+			disabledUser = user
+			return nil
+		}
+
+		err = ensureAnotherOwner(tx, user, "the last enabled owner cannot be disabled")
+		if err != nil {
+			return err
+		}
+
+		before := *user
+		user.Disabled = true
+
+		disabledUser, err = tx.UpdateUser(user)
+		if err != nil {
+			return serviceErrors.NewGeneralServiceError()
+		}
+
+		err = audit.Record(s.ctx, tx, domain.AuditUpdated, domain.UserAuditEntity, user.ID, before, disabledUser)
+		if err != nil {
+			return err
+		}
+
+		return s.sessionService.RevokeAll(user.Username)
+	})
+	if err != nil {
+		return nil, serviceErrors.FromTransaction(err)
+	}
+
+	return disabledUser, nil
+}
+
+func (s *service) UpdateUserRole(req *domain.UserRoleUpdateRequest) (*domain.User, error) {
+	if !domain.IsValidRole(req.Role) {
+		return nil, serviceErrors.NewValidationError([]string{roleErrorMessage})
+	}
+
+	var updatedUser *domain.User
+
+	err := s.userStorage.WithTx(func(tx interfaces.Storage) error {
+		user, err := findUser(tx, req.ID)
+		if err != nil {
+			return err
+		}
+
+		if user.Role == req.Role {
+			updatedUser = user
+			return nil
+		}
+
+		err = ensureAnotherOwner(tx, user, "the last enabled owner must keep the owner role")
+		if err != nil {
+			return err
+		}
+
+		before := *user
+		user.Role = req.Role
+
+		updatedUser, err = tx.UpdateUser(user)
+		if err != nil {
+			return serviceErrors.NewGeneralServiceError()
+		}
+
+		err = audit.Record(s.ctx, tx, domain.AuditUpdated, domain.UserAuditEntity, user.ID, before, updatedUser)
+		if err != nil {
+			return err
+		}
+
+		// Sessions carry the role they were started with, so they are ended to stop the old role being used
+		return s.sessionService.RevokeAll(user.Username)
+	})
+	if err != nil {
+		return nil, serviceErrors.FromTransaction(err)
+	}
+
+	return updatedUser, nil
+}
+
+// ResetPassword sets a new password without knowing the current one, for admins locked out of their
+// account. It does not enable disabled users. Every session of the user is ended in case the old
+// password was compromised.
+func (s *service) ResetPassword(req *domain.UserPasswordResetRequest) (*domain.User, error) {
+	errorMessages := validatePassword(req.Password)
+	if len(errorMessages) > 0 {
+		return nil, serviceErrors.NewValidationError(errorMessages)
+	}
+
+	passwordHash, err := s.hashPassword(req.Password)
+	if err != nil {
+		return nil, err
+	}
+
+	var updatedUser *domain.User
+
+	err = s.userStorage.WithTx(func(tx interfaces.Storage) error {
+		user, err := findUser(tx, req.ID)
+		if err != nil {
+			return err
+		}
+
+		updatedUser, err = s.updatePassword(tx, user, passwordHash)
+		return err
+	})
+	if err != nil {
+		return nil, serviceErrors.FromTransaction(err)
+	}
+
+	return updatedUser, nil
+}
+
+// ChangePassword ends every session of the user, including the one changing the password
+func (s *service) ChangePassword(username string, req *domain.PasswordChangeRequest) error {
+	errorMessages := validatePassword(req.NewPassword)
+	if len(errorMessages) > 0 {
+		return serviceErrors.NewValidationError(errorMessages)
+	}
+
+	passwordHash, err := s.hashPassword(req.NewPassword)
+	if err != nil {
+		return err
+	}
+
+	err = s.userStorage.WithTx(func(tx interfaces.Storage) error {
+		user, err := findUserByUsername(tx, username)
+		if err != nil {
+			return err
+		}
+
+		err = bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(req.CurrentPassword))
+		if err != nil {
+			return serviceErrors.NewValidationError([]string{"current password is incorrect"})
+		}
+
+		_, err = s.updatePassword(tx, user, passwordHash)
+		return err
+	})
+
+	return serviceErrors.FromTransaction(err)
+}
+
+// BeginTOTPEnrollment gives the user a new secret for their authenticator app. Two-factor
+// authentication stays off until a code from the app is confirmed, and starting over replaces the
+// secret of an unconfirmed enrollment.
+func (s *service) BeginTOTPEnrollment(username string) (*domain.TOTPEnrollment, error) {
+	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
+
+	secret, err := security.GenerateTOTPSecret()
+	if err != nil {
+		ctxLogger.Errorf("user service - unable to generate totp secret due to %v", err)
+		return nil, serviceErrors.NewGeneralServiceError()
+	}
+
+	var user *domain.User
+
+	err = s.userStorage.WithTx(func(tx interfaces.Storage) error {
+		var err error
+
+		user, err = findUserByUsername(tx, username)
+		if err != nil {
+			return err
+		}
+
+		if user.TOTPEnabled {
+			return serviceErrors.NewValidationError([]string{"two-factor authentication is already enabled"})
+		}
+
+		user.TOTPSecret = secret
+
+		_, err = tx.UpdateUser(user)
+		if err != nil {
+			return serviceErrors.NewGeneralServiceError()
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, serviceErrors.FromTransaction(err)
+	}
+
+	return &domain.TOTPEnrollment{
+		Secret:          secret,
+		ProvisioningURI: security.TOTPProvisioningURI(s.securityConfig.TOTPIssuer, user.Username, secret),
+	}, nil
+}
+
+// ConfirmTOTPEnrollment turns on two-factor authentication once a code matches the new secret. The
+// recovery codes are only ever returned here.
+func (s *service) ConfirmTOTPEnrollment(username string, req *domain.TOTPConfirmRequest) (*domain.RecoveryCodes, error) {
+	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
+
+	codes, hashes, err := security.GenerateRecoveryCodes()
+	if err != nil {
+		ctxLogger.Errorf("user service - unable to generate recovery codes due to %v", err)
+		return nil, serviceErrors.NewGeneralServiceError()
+	}
+
+	err = s.userStorage.WithTx(func(tx interfaces.Storage) error {
+		user, err := findUserByUsername(tx, username)
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case user.TOTPEnabled:
+			return serviceErrors.NewValidationError([]string{"two-factor authentication is already enabled"})
+		case user.TOTPSecret == "":
+			return serviceErrors.NewValidationError([]string{"two-factor authentication setup has not been started"})
+		}
+
+		step, valid := security.ValidateTOTPCode(user.TOTPSecret, req.Code, time.Now(), user.TOTPLastStep)
+		if !valid {
+			return serviceErrors.NewValidationError([]string{"code is incorrect"})
+		}
+
+		before := *user
+		user.TOTPEnabled = true
+		user.TOTPLastStep = step
+		user.RecoveryCodeHashes = hashes
+
+		updatedUser, err := tx.UpdateUser(user)
+		if err != nil {
+			return serviceErrors.NewGeneralServiceError()
+		}
+
+		return audit.Record(s.ctx, tx, domain.AuditUpdated, domain.UserAuditEntity, user.ID, before, updatedUser)
+	})
+	if err != nil {
+		return nil, serviceErrors.FromTransaction(err)
+	}
+
+	return &domain.RecoveryCodes{Codes: codes}, nil
+}
+
+// DisableTOTP needs the password of the user so a session left open cannot be used to remove the
+// second factor
+func (s *service) DisableTOTP(username string, req *domain.TOTPDisableRequest) error {
+	err := s.userStorage.WithTx(func(tx interfaces.Storage) error {
+		user, err := findUserByUsername(tx, username)
+		if err != nil {
+			return err
+		}
+
+		err = bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(req.Password))
+		if err != nil {
+			return serviceErrors.NewValidationError([]string{"password is incorrect"})
+		}
+
+		_, err = s.clearTOTP(tx, user)
+		return err
+	})
+
+	return serviceErrors.FromTransaction(err)
+}
+
+// ResetTOTPByID turns off two-factor authentication without a password, for admins who have lost both
+// their authenticator app and their recovery codes
+func (s *service) ResetTOTPByID(userID int64) (*domain.User, error) {
+	var updatedUser *domain.User
+
+	err := s.userStorage.WithTx(func(tx interfaces.Storage) error {
+		user, err := findUser(tx, userID)
+		if err != nil {
+			return err
+		}
+
+		updatedUser, err = s.clearTOTP(tx, user)
+		return err
+	})
+	if err != nil {
+		return nil, serviceErrors.FromTransaction(err)
+	}
+
+	return updatedUser, nil
+}
+
+func (s *service) clearTOTP(tx interfaces.Storage, user *domain.User) (*domain.User, error) {
+	if !user.TOTPEnabled && user.TOTPSecret == "" {
+		return user, nil
+	}
+
+	before := *user
+	user.TOTPSecret = ""
+	user.TOTPEnabled = false
+	user.TOTPLastStep = 0
+	user.RecoveryCodeHashes = nil
+
+	updatedUser, err := tx.UpdateUser(user)
+	if err != nil {
+		return nil, serviceErrors.NewGeneralServiceError()
+	}
+
+	err = audit.Record(s.ctx, tx, domain.AuditUpdated, domain.UserAuditEntity, user.ID, before, updatedUser)
+	if err != nil {
+		return nil, err
+	}
+
+	return updatedUser, nil
+}
+
+func (s *service) updatePassword(tx interfaces.Storage, user *domain.User, passwordHash string) (*domain.User, error) {
+	before := *user
+	user.PasswordHash = passwordHash
+
+	updatedUser, err := tx.UpdateUser(user)
+	if err != nil {
+		return nil, serviceErrors.NewGeneralServiceError()
+	}
+
+	err = audit.Record(s.ctx, tx, domain.AuditUpdated, domain.UserAuditEntity, user.ID, before, updatedUser)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.sessionService.RevokeAll(user.Username)
+	if err != nil {
+		return nil, err
+	}
+
+	return updatedUser, nil
+}
+
+func (s *service) hashPassword(password string) (string, error) {
+	ctxLogger := s.ctx.Value("logger").(interfaces.Logger)
+
+	passwordHash, err := bcrypt.GenerateFromPassword([]byte(password), s.securityConfig.BcryptCost)
+	if err != nil {
+		ctxLogger.Errorf("user service - unable to hash password due to %v", err)
+		return "", serviceErrors.NewGeneralServiceError()
+	}
+
+	return string(passwordHash), nil
+}
+
+func findUser(tx interfaces.Storage, userID int64) (*domain.User, error) {
+	user, err := tx.FindUserByID(userID)
+	if err != nil {
+		switch err.(type) {
+		case storage.StorageRecordNotFoundError:
+			return nil, NewUserNotFoundError()
+		}
+
+		return nil, serviceErrors.NewGeneralServiceError()
+	}
+
+	return user, nil
+}
+
+func findUserByUsername(tx interfaces.Storage, username string) (*domain.User, error) {
+	user, err := tx.FindUserByUsername(strings.ToLower(username))
+	if err != nil {
+		switch err.(type) {
+		case storage.StorageRecordNotFoundError:
+			return nil, NewUserNotFoundError()
+		}
+
+		return nil, serviceErrors.NewGeneralServiceError()
+	}
+
+	return user, nil
+}
+
+// ensureAnotherOwner returns a validation error with the given message when the user is the only
+// enabled owner left
+func ensureAnotherOwner(tx interfaces.Storage, user *domain.User, errorMessage string) error {
+	if user.Disabled || user.Role != domain.RoleOwner {
+		return nil
+	}
+
+	users, err := tx.ListUsers()
+	if err != nil {
+		return serviceErrors.NewGeneralServiceError()
+	}
+
+	for idx := range users {
+		if users[idx].ID != user.ID && !users[idx].Disabled && users[idx].Role == domain.RoleOwner {
+			return nil
+		}
+	}
+
+	return serviceErrors.NewValidationError([]string{errorMessage})
+}
+
+func validateUserCreateRequest(req *domain.UserCreateRequest) (errorMessages []string) {
+	if !utils.IsWithin(len(req.Username), UsernameMinLength, UsernameMaxLength) {
+		errorMessages = append(errorMessages, fmt.Sprintf("username must be between %v to %v characters", UsernameMinLength, UsernameMaxLength))
+	} else if !usernameFormat.MatchString(req.Username) {
+		errorMessages = append(errorMessages, "username can only contain letters, numbers, dots, dashes and underscores")
+	}
+
+	if !domain.IsValidRole(req.Role) {
+		errorMessages = append(errorMessages, roleErrorMessage)
+	}
+
+	return append(errorMessages, validatePassword(req.Password)...)
+}
+
+func validatePassword(password string) (errorMessages []string) {
+	if !utils.IsWithin(len(password), PasswordMinLength, PasswordMaxLength) {
+		errorMessages = append(errorMessages, fmt.Sprintf("password must be between %v to %v characters", PasswordMinLength, PasswordMaxLength))
+	}
+
+	return errorMessages
+}
@@ -0,0 +1,434 @@
+package user_test
+
+import (
+	"time"
+
+	"github.com/rawfish-dev/rsvp-starter/server/config"
+	"github.com/rawfish-dev/rsvp-starter/server/domain"
+	"github.com/rawfish-dev/rsvp-starter/server/interfaces"
+	"github.com/rawfish-dev/rsvp-starter/server/mock"
+	"github.com/rawfish-dev/rsvp-starter/server/services/cache"
+	serviceErrors "github.com/rawfish-dev/rsvp-starter/server/services/errors"
+	"github.com/rawfish-dev/rsvp-starter/server/services/jwt"
+	"github.com/rawfish-dev/rsvp-starter/server/services/security"
+	"github.com/rawfish-dev/rsvp-starter/server/services/session"
+	"github.com/rawfish-dev/rsvp-starter/server/services/storage"
+	. "github.com/rawfish-dev/rsvp-starter/server/services/user"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"golang.org/x/crypto/bcrypt"
+	"golang.org/x/net/context"
+)
+
+var _ = Describe("User", func() {
+
+	var ctrl *gomock.Controller
+	var mockUserStorage *mock_interfaces.MockTransactionalStorage
+	var sessionService interfaces.SessionServiceProvider
+	var testUserService interfaces.UserServiceProvider
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+
+		ctxlogger := logrus.New()
+		ctx := context.Background()
+		ctx = context.WithValue(ctx, "logger", ctxlogger)
+
+		mockUserStorage = mock_interfaces.NewMockTransactionalStorage(ctrl)
+
+		cacheService := cache.NewService(ctx)
+		Expect(cacheService.Flush()).To(Succeed())
+		jwtService := jwt.NewService(ctx, config.JWTConfig{SigningKey: config.NewHMACJWTKey("some-secret-hmac"), TokenIssuer: "rsvp-starter-test"})
+		sessionService = session.NewService(ctx, config.SessionConfig{Duration: time.Minute, RefreshDuration: time.Hour}, jwtService, cacheService, mockUserStorage)
+
+		testUserService = NewService(ctx, config.SecurityConfig{BcryptCost: bcrypt.MinCost}, mockUserStorage, sessionService)
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	hashPassword := func(password string) string {
+		passwordHash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.MinCost)
+		Expect(err).ToNot(HaveOccurred())
+
+		return string(passwordHash)
+	}
+
+	login := func(username string, role domain.Role) *domain.SessionTokens {
+		tokens, err := sessionService.CreateWithExpiry(username, role)
+		Expect(err).ToNot(HaveOccurred())
+
+		return tokens
+	}
+
+	expectSessionEnded := func(tokens *domain.SessionTokens) {
+		valid, err := sessionService.IsSessionValid(tokens.AuthToken)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(valid).To(BeFalse())
+
+		_, err = sessionService.Refresh(tokens.RefreshToken)
+		Expect(err).To(BeAssignableToTypeOf(session.RefreshTokenInvalidError{}))
+	}
+
+	Context("creation", func() {
+
+		It("should create a user with a lower case username and a hash of the password at the configured cost", func() {
+			gomock.InOrder(
+				mockUserStorage.EXPECT().InsertUser(gomock.Any()).
+					Do(func(user *domain.User) {
+						Expect(user.Username).To(Equal("kevin"))
+						Expect(user.Role).To(Equal(domain.RoleEditor))
+						Expect(bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte("some password"))).To(Succeed())
+
+						cost, err := bcrypt.Cost([]byte(user.PasswordHash))
+						Expect(err).ToNot(HaveOccurred())
+						Expect(cost).To(Equal(bcrypt.MinCost))
+					}).
+					Return(&domain.User{ID: 1, Username: "kevin"}, nil),
+				mockUserStorage.EXPECT().InsertAuditEntry(gomock.Any()).Return(&domain.AuditEntry{}, nil),
+			)
+
+			newUser, err := testUserService.CreateUser(&domain.UserCreateRequest{Username: " Kevin ", Password: "some password", Role: domain.RoleEditor})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(newUser.ID).To(Equal(int64(1)))
+		})
+
+		It("should not allow duplicate usernames", func() {
+			mockUserStorage.EXPECT().InsertUser(gomock.Any()).Return(nil, storage.NewStorageUserUsernameUniqueConstraintError())
+
+			_, err := testUserService.CreateUser(&domain.UserCreateRequest{Username: "kevin", Password: "some password", Role: domain.RoleOwner})
+			Expect(err).To(BeAssignableToTypeOf(serviceErrors.ValidationError{}))
+			Expect(err.Error()).To(Equal("username already exists"))
+		})
+
+		It("should not allow invalid usernames, unknown roles or short passwords", func() {
+			mockUserStorage.EXPECT().InsertUser(gomock.Any()).Times(0)
+
+			_, err := testUserService.CreateUser(&domain.UserCreateRequest{Username: "kevin lin", Password: "short", Role: "admin"})
+			Expect(err).To(BeAssignableToTypeOf(serviceErrors.ValidationError{}))
+			Expect(err.Error()).To(Equal("username can only contain letters, numbers, dots, dashes and underscores; role must be one of owner, editor, viewer or door_staff; password must be between 8 to 72 characters"))
+		})
+	})
+
+	Context("updating roles", func() {
+
+		It("should give a user another role", func() {
+			user := &domain.User{ID: 3, Username: "tom", Role: domain.RoleEditor}
+
+			gomock.InOrder(
+				mockUserStorage.EXPECT().FindUserByID(int64(3)).Return(user, nil),
+				mockUserStorage.EXPECT().UpdateUser(&domain.User{ID: 3, Username: "tom", Role: domain.RoleDoorStaff}).
+					Return(&domain.User{ID: 3, Username: "tom", Role: domain.RoleDoorStaff}, nil),
+				mockUserStorage.EXPECT().InsertAuditEntry(gomock.Any()).Return(&domain.AuditEntry{}, nil),
+			)
+
+			updatedUser, err := testUserService.UpdateUserRole(&domain.UserRoleUpdateRequest{ID: 3, Role: domain.RoleDoorStaff})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(updatedUser.Role).To(Equal(domain.RoleDoorStaff))
+		})
+
+		It("should end every session started with the old role", func() {
+			tokens := login("tom", domain.RoleEditor)
+
+			gomock.InOrder(
+				mockUserStorage.EXPECT().FindUserByID(int64(3)).Return(&domain.User{ID: 3, Username: "tom", Role: domain.RoleEditor}, nil),
+				mockUserStorage.EXPECT().UpdateUser(gomock.Any()).
+					Return(&domain.User{ID: 3, Username: "tom", Role: domain.RoleDoorStaff}, nil),
+				mockUserStorage.EXPECT().InsertAuditEntry(gomock.Any()).Return(&domain.AuditEntry{}, nil),
+			)
+
+			_, err := testUserService.UpdateUserRole(&domain.UserRoleUpdateRequest{ID: 3, Role: domain.RoleDoorStaff})
+			Expect(err).ToNot(HaveOccurred())
+
+			expectSessionEnded(tokens)
+		})
+
+		It("should not take the owner role away from the last enabled owner", func() {
+			user := &domain.User{ID: 1, Username: "kevin", Role: domain.RoleOwner}
+
+			gomock.InOrder(
+				mockUserStorage.EXPECT().FindUserByID(int64(1)).Return(user, nil),
+				mockUserStorage.EXPECT().ListUsers().Return([]domain.User{*user, {ID: 3, Username: "tom", Role: domain.RoleEditor}}, nil),
+				mockUserStorage.EXPECT().UpdateUser(gomock.Any()).Times(0),
+			)
+
+			_, err := testUserService.UpdateUserRole(&domain.UserRoleUpdateRequest{ID: 1, Role: domain.RoleViewer})
+			Expect(err).To(BeAssignableToTypeOf(serviceErrors.ValidationError{}))
+			Expect(err.Error()).To(Equal("the last enabled owner must keep the owner role"))
+		})
+
+		It("should not allow unknown roles", func() {
+			mockUserStorage.EXPECT().FindUserByID(gomock.Any()).Times(0)
+
+			_, err := testUserService.UpdateUserRole(&domain.UserRoleUpdateRequest{ID: 1, Role: "admin"})
+			Expect(err).To(BeAssignableToTypeOf(serviceErrors.ValidationError{}))
+		})
+	})
+
+	Context("disabling", func() {
+
+		It("should disable an owner while another owner is enabled", func() {
+			user := &domain.User{ID: 1, Username: "kevin", Role: domain.RoleOwner}
+
+			gomock.InOrder(
+				mockUserStorage.EXPECT().FindUserByID(int64(1)).Return(user, nil),
+				mockUserStorage.EXPECT().ListUsers().Return([]domain.User{*user, {ID: 2, Username: "jenny", Role: domain.RoleOwner}}, nil),
+				mockUserStorage.EXPECT().UpdateUser(&domain.User{ID: 1, Username: "kevin", Role: domain.RoleOwner, Disabled: true}).
+					Return(&domain.User{ID: 1, Username: "kevin", Role: domain.RoleOwner, Disabled: true}, nil),
+				mockUserStorage.EXPECT().InsertAuditEntry(gomock.Any()).Return(&domain.AuditEntry{}, nil),
+			)
+
+			disabledUser, err := testUserService.DisableUserByID(1)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(disabledUser.Disabled).To(BeTrue())
+		})
+
+		It("should not disable the last enabled owner", func() {
+			user := &domain.User{ID: 1, Username: "kevin", Role: domain.RoleOwner}
+
+			gomock.InOrder(
+				mockUserStorage.EXPECT().FindUserByID(int64(1)).Return(user, nil),
+				mockUserStorage.EXPECT().ListUsers().Return([]domain.User{
+					*user,
+					{ID: 2, Username: "jenny", Role: domain.RoleOwner, Disabled: true},
+					{ID: 3, Username: "tom", Role: domain.RoleEditor},
+				}, nil),
+				mockUserStorage.EXPECT().UpdateUser(gomock.Any()).Times(0),
+			)
+
+			_, err := testUserService.DisableUserByID(1)
+			Expect(err).To(BeAssignableToTypeOf(serviceErrors.ValidationError{}))
+			Expect(err.Error()).To(Equal("the last enabled owner cannot be disabled"))
+		})
+
+		It("should disable other roles without checking for owners", func() {
+			user := &domain.User{ID: 3, Username: "tom", Role: domain.RoleEditor}
+
+			gomock.InOrder(
+				mockUserStorage.EXPECT().FindUserByID(int64(3)).Return(user, nil),
+				mockUserStorage.EXPECT().UpdateUser(gomock.Any()).
+					Return(&domain.User{ID: 3, Username: "tom", Role: domain.RoleEditor, Disabled: true}, nil),
+				mockUserStorage.EXPECT().InsertAuditEntry(gomock.Any()).Return(&domain.AuditEntry{}, nil),
+			)
+
+			disabledUser, err := testUserService.DisableUserByID(3)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(disabledUser.Disabled).To(BeTrue())
+		})
+
+		It("should return an error if the user cannot be found", func() {
+			mockUserStorage.EXPECT().FindUserByID(int64(1)).Return(nil, storage.NewStorageRecordNotFoundError())
+
+			_, err := testUserService.DisableUserByID(1)
+			Expect(err).To(BeAssignableToTypeOf(UserNotFoundError{}))
+		})
+
+		It("should end every session of the user and leave other users signed in", func() {
+			tomTokens := []*domain.SessionTokens{login("tom", domain.RoleEditor), login("tom", domain.RoleEditor)}
+			kevinTokens := login("kevin", domain.RoleOwner)
+
+			gomock.InOrder(
+				mockUserStorage.EXPECT().FindUserByID(int64(3)).Return(&domain.User{ID: 3, Username: "tom", Role: domain.RoleEditor}, nil),
+				mockUserStorage.EXPECT().UpdateUser(gomock.Any()).
+					Return(&domain.User{ID: 3, Username: "tom", Role: domain.RoleEditor, Disabled: true}, nil),
+				mockUserStorage.EXPECT().InsertAuditEntry(gomock.Any()).Return(&domain.AuditEntry{}, nil),
+			)
+
+			_, err := testUserService.DisableUserByID(3)
+			Expect(err).ToNot(HaveOccurred())
+
+			for _, tokens := range tomTokens {
+				expectSessionEnded(tokens)
+			}
+
+			valid, err := sessionService.IsSessionValid(kevinTokens.AuthToken)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(valid).To(BeTrue())
+		})
+	})
+
+	Context("passwords", func() {
+
+		It("should reset the password of a user", func() {
+			gomock.InOrder(
+				mockUserStorage.EXPECT().FindUserByID(int64(1)).Return(&domain.User{ID: 1, Username: "kevin", PasswordHash: "old-hash"}, nil),
+				mockUserStorage.EXPECT().UpdateUser(gomock.Any()).
+					Do(func(user *domain.User) {
+						Expect(bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte("new password"))).To(Succeed())
+					}).
+					Return(&domain.User{ID: 1, Username: "kevin"}, nil),
+				mockUserStorage.EXPECT().InsertAuditEntry(gomock.Any()).Return(&domain.AuditEntry{}, nil),
+			)
+
+			_, err := testUserService.ResetPassword(&domain.UserPasswordResetRequest{ID: 1, Password: "new password"})
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should change the password of a user given their current password", func() {
+			gomock.InOrder(
+				mockUserStorage.EXPECT().FindUserByUsername("kevin").Return(&domain.User{ID: 1, Username: "kevin", PasswordHash: hashPassword("old password")}, nil),
+				mockUserStorage.EXPECT().UpdateUser(gomock.Any()).
+					Do(func(user *domain.User) {
+						Expect(bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte("new password"))).To(Succeed())
+					}).
+					Return(&domain.User{ID: 1, Username: "kevin"}, nil),
+				mockUserStorage.EXPECT().InsertAuditEntry(gomock.Any()).Return(&domain.AuditEntry{}, nil),
+			)
+
+			err := testUserService.ChangePassword("kevin", &domain.PasswordChangeRequest{CurrentPassword: "old password", NewPassword: "new password"})
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should not change the password given the wrong current password", func() {
+			gomock.InOrder(
+				mockUserStorage.EXPECT().FindUserByUsername("kevin").Return(&domain.User{ID: 1, Username: "kevin", PasswordHash: hashPassword("old password")}, nil),
+				mockUserStorage.EXPECT().UpdateUser(gomock.Any()).Times(0),
+			)
+
+			err := testUserService.ChangePassword("kevin", &domain.PasswordChangeRequest{CurrentPassword: "wrong password", NewPassword: "new password"})
+			Expect(err).To(BeAssignableToTypeOf(serviceErrors.ValidationError{}))
+			Expect(err.Error()).To(Equal("current password is incorrect"))
+		})
+
+		It("should end every session of the user when the password is reset", func() {
+			tokens := login("kevin", domain.RoleOwner)
+
+			gomock.InOrder(
+				mockUserStorage.EXPECT().FindUserByID(int64(1)).Return(&domain.User{ID: 1, Username: "kevin", PasswordHash: "old-hash"}, nil),
+				mockUserStorage.EXPECT().UpdateUser(gomock.Any()).Return(&domain.User{ID: 1, Username: "kevin"}, nil),
+				mockUserStorage.EXPECT().InsertAuditEntry(gomock.Any()).Return(&domain.AuditEntry{}, nil),
+			)
+
+			_, err := testUserService.ResetPassword(&domain.UserPasswordResetRequest{ID: 1, Password: "new password"})
+			Expect(err).ToNot(HaveOccurred())
+
+			expectSessionEnded(tokens)
+		})
+
+		It("should end every session of the user when they change their password", func() {
+			tokens := login("kevin", domain.RoleOwner)
+
+			gomock.InOrder(
+				mockUserStorage.EXPECT().FindUserByUsername("kevin").Return(&domain.User{ID: 1, Username: "kevin", PasswordHash: hashPassword("old password")}, nil),
+				mockUserStorage.EXPECT().UpdateUser(gomock.Any()).Return(&domain.User{ID: 1, Username: "kevin"}, nil),
+				mockUserStorage.EXPECT().InsertAuditEntry(gomock.Any()).Return(&domain.AuditEntry{}, nil),
+			)
+
+			err := testUserService.ChangePassword("kevin", &domain.PasswordChangeRequest{CurrentPassword: "old password", NewPassword: "new password"})
+			Expect(err).ToNot(HaveOccurred())
+
+			expectSessionEnded(tokens)
+		})
+
+		It("should not allow short passwords", func() {
+			mockUserStorage.EXPECT().FindUserByID(gomock.Any()).Times(0)
+
+			_, err := testUserService.ResetPassword(&domain.UserPasswordResetRequest{ID: 1, Password: "short"})
+			Expect(err).To(BeAssignableToTypeOf(serviceErrors.ValidationError{}))
+		})
+	})
+
+	Context("two-factor authentication", func() {
+
+		It("should begin enrollment with a new secret without enabling it", func() {
+			var storedSecret string
+
+			gomock.InOrder(
+				mockUserStorage.EXPECT().FindUserByUsername("kevin").Return(&domain.User{ID: 1, Username: "kevin"}, nil),
+				mockUserStorage.EXPECT().UpdateUser(gomock.Any()).
+					Do(func(user *domain.User) {
+						Expect(user.TOTPEnabled).To(BeFalse())
+						storedSecret = user.TOTPSecret
+					}).
+					Return(&domain.User{ID: 1, Username: "kevin"}, nil),
+			)
+
+			enrollment, err := testUserService.BeginTOTPEnrollment("kevin")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(enrollment.Secret).To(Equal(storedSecret))
+			Expect(enrollment.ProvisioningURI).To(HavePrefix("otpauth://totp/"))
+			Expect(enrollment.ProvisioningURI).To(ContainSubstring(storedSecret))
+		})
+
+		It("should enable it and return recovery codes given a code for the new secret", func() {
+			secret, err := security.GenerateTOTPSecret()
+			Expect(err).ToNot(HaveOccurred())
+			code, err := security.GenerateTOTPCode(secret, time.Now())
+			Expect(err).ToNot(HaveOccurred())
+
+			var storedHashes []string
+
+			gomock.InOrder(
+				mockUserStorage.EXPECT().FindUserByUsername("kevin").Return(&domain.User{ID: 1, Username: "kevin", TOTPSecret: secret}, nil),
+				mockUserStorage.EXPECT().UpdateUser(gomock.Any()).
+					Do(func(user *domain.User) {
+						Expect(user.TOTPEnabled).To(BeTrue())
+						Expect(user.TOTPLastStep).ToNot(BeZero())
+						storedHashes = user.RecoveryCodeHashes
+					}).
+					Return(&domain.User{ID: 1, Username: "kevin", TOTPEnabled: true}, nil),
+				mockUserStorage.EXPECT().InsertAuditEntry(gomock.Any()).Return(&domain.AuditEntry{}, nil),
+			)
+
+			recoveryCodes, err := testUserService.ConfirmTOTPEnrollment("kevin", &domain.TOTPConfirmRequest{Code: code})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(recoveryCodes.Codes).To(HaveLen(len(storedHashes)))
+			Expect(storedHashes).To(ContainElement(security.HashRecoveryCode(recoveryCodes.Codes[0])))
+		})
+
+		It("should not enable it given an incorrect code", func() {
+			secret, err := security.GenerateTOTPSecret()
+			Expect(err).ToNot(HaveOccurred())
+
+			gomock.InOrder(
+				mockUserStorage.EXPECT().FindUserByUsername("kevin").Return(&domain.User{ID: 1, Username: "kevin", TOTPSecret: secret}, nil),
+				mockUserStorage.EXPECT().UpdateUser(gomock.Any()).Times(0),
+			)
+
+			_, err = testUserService.ConfirmTOTPEnrollment("kevin", &domain.TOTPConfirmRequest{Code: "abcdef"})
+			Expect(err).To(BeAssignableToTypeOf(serviceErrors.ValidationError{}))
+			Expect(err.Error()).To(Equal("code is incorrect"))
+		})
+
+		It("should disable it given the password of the user", func() {
+			gomock.InOrder(
+				mockUserStorage.EXPECT().FindUserByUsername("kevin").Return(&domain.User{
+					ID:                 1,
+					Username:           "kevin",
+					PasswordHash:       hashPassword("some password"),
+					TOTPSecret:         "some-secret",
+					TOTPEnabled:        true,
+					TOTPLastStep:       123,
+					RecoveryCodeHashes: []string{"some-hash"},
+				}, nil),
+				mockUserStorage.EXPECT().UpdateUser(gomock.Any()).
+					Do(func(user *domain.User) {
+						Expect(user.TOTPSecret).To(BeEmpty())
+						Expect(user.TOTPEnabled).To(BeFalse())
+						Expect(user.TOTPLastStep).To(BeZero())
+						Expect(user.RecoveryCodeHashes).To(BeEmpty())
+					}).
+					Return(&domain.User{ID: 1, Username: "kevin"}, nil),
+				mockUserStorage.EXPECT().InsertAuditEntry(gomock.Any()).Return(&domain.AuditEntry{}, nil),
+			)
+
+			err := testUserService.DisableTOTP("kevin", &domain.TOTPDisableRequest{Password: "some password"})
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should not disable it given the wrong password", func() {
+			gomock.InOrder(
+				mockUserStorage.EXPECT().FindUserByUsername("kevin").Return(&domain.User{ID: 1, Username: "kevin", PasswordHash: hashPassword("some password"), TOTPEnabled: true}, nil),
+				mockUserStorage.EXPECT().UpdateUser(gomock.Any()).Times(0),
+			)
+
+			err := testUserService.DisableTOTP("kevin", &domain.TOTPDisableRequest{Password: "wrong password"})
+			Expect(err).To(BeAssignableToTypeOf(serviceErrors.ValidationError{}))
+			Expect(err.Error()).To(Equal("password is incorrect"))
+		})
+	})
+})
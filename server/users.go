@@ -0,0 +1,155 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/rawfish-dev/rsvp-starter/server/api"
+	"github.com/rawfish-dev/rsvp-starter/server/config"
+	"github.com/rawfish-dev/rsvp-starter/server/domain"
+	"github.com/rawfish-dev/rsvp-starter/server/interfaces"
+	"github.com/rawfish-dev/rsvp-starter/server/services/cache"
+	"github.com/rawfish-dev/rsvp-starter/server/services/jwt"
+	"github.com/rawfish-dev/rsvp-starter/server/services/session"
+	"github.com/rawfish-dev/rsvp-starter/server/services/user"
+
+	"github.com/Sirupsen/logrus"
+	"golang.org/x/net/context"
+)
+
+const usersUsage = "usage: server users list|create <username> [role]|set-role <username> <role>|disable <username>|reset-password <username>|reset-totp <username>"
+
+// runUsers manages admin users from the command line, which is the only way to add the first one.
+// Passwords are read from the first line of stdin so they can be piped in. New users are owners unless
+// another role is given.
+func runUsers(loadedConfig config.Config, args []string) {
+	ctxlogger := logrus.New()
+	ctx := context.Background()
+	ctx = context.WithValue(ctx, "logger", ctxlogger)
+
+	if len(args) == 0 {
+		ctxlogger.Fatal(usersUsage)
+	}
+
+	if loadedConfig.Storage.Driver == config.MemoryDriver {
+		ctxlogger.Fatal("users - the memory storage driver keeps no users between runs")
+	}
+
+	userStorage := api.NewStorage(ctx, loadedConfig)
+	if closer, ok := userStorage.(io.Closer); ok {
+		defer closer.Close()
+	}
+
+	// Sessions live in the memory of the API process, so the sessions this command ends are only its own.
+	// Sessions on the server of users disabled or changed here end when they next refresh or renew.
+	sessionService := session.NewService(ctx, loadedConfig.Session, jwt.NewService(ctx, loadedConfig.JWT), cache.NewService(ctx), userStorage)
+	userService := user.NewService(ctx, loadedConfig.Security, userStorage, sessionService)
+
+	switch {
+	case args[0] == "list" && len(args) == 1:
+		users, err := userService.ListUsers()
+		if err != nil {
+			ctxlogger.Fatalf("users - unable to list users due to %v", err)
+		}
+
+		writer := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(writer, "ID\tUSERNAME\tROLE\tSTATUS\tTWO-FACTOR\tUPDATED AT")
+		for idx := range users {
+			status := "enabled"
+			if users[idx].Disabled {
+				status = "disabled"
+			}
+			twoFactor := "off"
+			if users[idx].TOTPEnabled {
+				twoFactor = "on"
+			}
+			fmt.Fprintf(writer, "%v\t%v\t%v\t%v\t%v\t%v\n", users[idx].ID, users[idx].Username, users[idx].Role, status, twoFactor, users[idx].UpdatedAt)
+		}
+		writer.Flush()
+
+	case args[0] == "create" && (len(args) == 2 || len(args) == 3):
+		role := domain.RoleOwner
+		if len(args) == 3 {
+			role = domain.Role(args[2])
+		}
+
+		newUser, err := userService.CreateUser(&domain.UserCreateRequest{
+			Username: args[1],
+			Password: readPassword(ctxlogger),
+			Role:     role,
+		})
+		if err != nil {
+			ctxlogger.Fatalf("users - unable to create user due to %v", err)
+		}
+		fmt.Printf("created %v %v\n", newUser.Role, newUser.Username)
+
+	case args[0] == "set-role" && len(args) == 3:
+		existingUser := retrieveUser(ctxlogger, userService, args[1])
+
+		updatedUser, err := userService.UpdateUserRole(&domain.UserRoleUpdateRequest{
+			ID:   existingUser.ID,
+			Role: domain.Role(args[2]),
+		})
+		if err != nil {
+			ctxlogger.Fatalf("users - unable to set role due to %v", err)
+		}
+		fmt.Printf("user %v is now %v\n", updatedUser.Username, updatedUser.Role)
+
+	case args[0] == "disable" && len(args) == 2:
+		existingUser := retrieveUser(ctxlogger, userService, args[1])
+
+		_, err := userService.DisableUserByID(existingUser.ID)
+		if err != nil {
+			ctxlogger.Fatalf("users - unable to disable user due to %v", err)
+		}
+		fmt.Printf("disabled user %v\n", existingUser.Username)
+
+	case args[0] == "reset-password" && len(args) == 2:
+		existingUser := retrieveUser(ctxlogger, userService, args[1])
+
+		_, err := userService.ResetPassword(&domain.UserPasswordResetRequest{
+			ID:       existingUser.ID,
+			Password: readPassword(ctxlogger),
+		})
+		if err != nil {
+			ctxlogger.Fatalf("users - unable to reset password due to %v", err)
+		}
+		fmt.Printf("reset password of user %v\n", existingUser.Username)
+
+	case args[0] == "reset-totp" && len(args) == 2:
+		existingUser := retrieveUser(ctxlogger, userService, args[1])
+
+		_, err := userService.ResetTOTPByID(existingUser.ID)
+		if err != nil {
+			ctxlogger.Fatalf("users - unable to reset two-factor authentication due to %v", err)
+		}
+		fmt.Printf("turned off two-factor authentication of user %v\n", existingUser.Username)
+
+	default:
+		ctxlogger.Fatal(usersUsage)
+	}
+}
+
+func retrieveUser(ctxlogger interfaces.Logger, userService interfaces.UserServiceProvider, username string) *domain.User {
+	existingUser, err := userService.RetrieveUserByUsername(username)
+	if err != nil {
+		ctxlogger.Fatalf("users - unable to find user %v due to %v", username, err)
+	}
+
+	return existingUser
+}
+
+func readPassword(ctxlogger interfaces.Logger) string {
+	fmt.Fprint(os.Stderr, "password: ")
+
+	password, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && password == "" {
+		ctxlogger.Fatalf("users - unable to read password due to %v", err)
+	}
+
+	return strings.TrimRight(password, "\r\n")
+}
@@ -1,5 +1,24 @@
 package utils
 
+import (
+	"net/mail"
+	"regexp"
+)
+
+var languageTagPattern = regexp.MustCompile(`^[a-zA-Z]{2,3}(-[a-zA-Z0-9]{2,8})*$`)
+
 func IsWithin(val, min, max int) bool {
 	return val >= min && val <= max
 }
+
+// IsValidEmail only accepts a bare address such as guest@example.com, without a display name
+func IsValidEmail(email string) bool {
+	address, err := mail.ParseAddress(email)
+	return err == nil && address.Address == email
+}
+
+// IsValidLanguageTag accepts language tags such as en, zh-Hans or en-SG without checking them
+// against the registry
+func IsValidLanguageTag(tag string) bool {
+	return len(tag) <= 35 && languageTagPattern.MatchString(tag)
+}